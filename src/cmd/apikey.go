@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	apiKeyName    string
+	apiKeyScopes  []string
+	apiKeyAllow   []string
+	apiKeyExpires time.Duration
+)
+
+var apiKeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "Manage API keys for the REST and MCP servers",
+	Long:  `Create, list and revoke the named API keys that authenticate REST and MCP requests. Keys are stored hashed in the chat storage database.`,
+}
+
+var apiKeyCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an API key and print it once",
+	Example: `  whatsapp apikey create --name=crm --scopes=send,read:chats --allow="62812*" --expires=720h
+  whatsapp apikey create --name=ops --scopes=admin`,
+	Args: cobra.NoArgs,
+	Run:  apiKeyCreate,
+}
+
+var apiKeyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List API keys",
+	Args:  cobra.NoArgs,
+	Run:   apiKeyList,
+}
+
+var apiKeyRevokeCmd = &cobra.Command{
+	Use:   "revoke <key-id>",
+	Short: "Revoke an API key",
+	Args:  cobra.ExactArgs(1),
+	Run:   apiKeyRevoke,
+}
+
+func init() {
+	rootCmd.AddCommand(apiKeyCmd)
+	apiKeyCmd.AddCommand(apiKeyCreateCmd, apiKeyListCmd, apiKeyRevokeCmd)
+
+	apiKeyCreateCmd.Flags().StringVar(&apiKeyName, "name", "", "name of the key, recorded in the audit log")
+	apiKeyCreateCmd.Flags().StringSliceVar(&apiKeyScopes, "scopes", nil, "scopes granted to the key: "+strings.Join(domainAPIKey.Scopes, ", "))
+	apiKeyCreateCmd.Flags().StringSliceVar(&apiKeyAllow, "allow", nil, `recipient patterns the key may send to, e.g. "62812*" or "*@g.us" (default: any recipient)`)
+	apiKeyCreateCmd.Flags().DurationVar(&apiKeyExpires, "expires", 0, "lifetime of the key, e.g. 720h (default: never expires)")
+}
+
+func apiKeyCreate(_ *cobra.Command, _ []string) {
+	ctx := context.Background()
+	request := domainAPIKey.CreateKeyRequest{
+		Name:              apiKeyName,
+		Scopes:            apiKeyScopes,
+		AllowedRecipients: apiKeyAllow,
+	}
+	if apiKeyExpires > 0 {
+		expiresAt := time.Now().Add(apiKeyExpires)
+		request.ExpiresAt = &expiresAt
+	}
+
+	response, err := apiKeyUsecase.CreateKey(ctx, request)
+	if err != nil {
+		logrus.Fatalf("Failed to create API key: %v", err)
+	}
+	apiKeyUsecase.RecordAudit(ctx, domainAPIKey.AuditEntry{
+		Actor:  "cli",
+		Source: domainAPIKey.SourceCLI,
+		Action: "apikey create",
+		Target: response.ID,
+		Status: 200,
+	})
+
+	fmt.Printf("Created API key %s (%s)\n", response.Name, response.ID)
+	fmt.Printf("Scopes: %s\n", strings.Join(response.Scopes, ", "))
+	fmt.Printf("API key: %s\n", response.APIKey)
+	fmt.Println("Store the key now, it cannot be shown again.")
+}
+
+func apiKeyList(_ *cobra.Command, _ []string) {
+	keys, err := apiKeyUsecase.ListKeys(context.Background())
+	if err != nil {
+		logrus.Fatalf("Failed to list API keys: %v", err)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tNAME\tPREFIX\tSCOPES\tALLOWED RECIPIENTS\tEXPIRES\tLAST USED")
+	for _, key := range keys {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			key.ID, key.Name, key.Prefix,
+			strings.Join(key.Scopes, ","),
+			strings.Join(key.AllowedRecipients, ","),
+			formatOptionalTime(key.ExpiresAt, "never"),
+			formatOptionalTime(key.LastUsedAt, "-"),
+		)
+	}
+	_ = writer.Flush()
+}
+
+func apiKeyRevoke(_ *cobra.Command, args []string) {
+	ctx := context.Background()
+	if err := apiKeyUsecase.DeleteKey(ctx, args[0]); err != nil {
+		logrus.Fatalf("Failed to revoke API key: %v", err)
+	}
+	apiKeyUsecase.RecordAudit(ctx, domainAPIKey.AuditEntry{
+		Actor:  "cli",
+		Source: domainAPIKey.SourceCLI,
+		Action: "apikey revoke",
+		Target: args[0],
+		Status: 200,
+	})
+
+	fmt.Printf("Revoked API key %s\n", args[0])
+}
+
+func formatOptionalTime(value *time.Time, fallback string) string {
+	if value == nil {
+		return fallback
+	}
+	return value.Format(time.RFC3339)
+}
@@ -1,29 +1,47 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"os"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/mcp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/spf13/cobra"
 )
 
+// MCP transports
+const (
+	mcpTransportStdio = "stdio"
+	mcpTransportSSE   = "sse"
+	mcpTransportHTTP  = "http"
+)
+
+// mcpStdout is the real stdout, kept for the protocol in stdio mode while everything else printing to
+// stdout is sent to stderr
+var mcpStdout = os.Stdout
+
 // rootCmd represents the base command when called without any subcommands
 var mcpCmd = &cobra.Command{
 	Use:   "mcp",
-	Short: "Start WhatsApp MCP server using SSE",
-	Long:  `Start a WhatsApp MCP (Model Context Protocol) server using Server-Sent Events (SSE) transport. This allows AI agents to interact with WhatsApp through a standardized protocol.`,
-	Run:   mcpServer,
+	Short: "Start WhatsApp MCP server using stdio, SSE or streamable HTTP",
+	Long: `Start a WhatsApp MCP (Model Context Protocol) server. This allows AI agents to interact with WhatsApp through a standardized protocol.
+The server talks over stdio to clients that launch it, or over Server-Sent Events (SSE) or streamable HTTP on --host and --port.`,
+	Run: mcpServer,
 }
 
 func init() {
 	rootCmd.AddCommand(mcpCmd)
-	mcpCmd.Flags().StringVar(&config.McpPort, "port", "8080", "Port for the SSE MCP server")
-	mcpCmd.Flags().StringVar(&config.McpHost, "host", "localhost", "Host for the SSE MCP server")
+	mcpCmd.Flags().StringVar(&config.McpPort, "port", "8080", "Port for the SSE or HTTP MCP server")
+	mcpCmd.Flags().StringVar(&config.McpHost, "host", "localhost", "Host for the SSE or HTTP MCP server")
+	mcpCmd.Flags().StringVar(&config.McpTransport, "transport", config.McpTransport, "MCP transport: stdio, sse or http")
 }
 
 func mcpServer(_ *cobra.Command, _ []string) {
@@ -32,19 +50,43 @@ func mcpServer(_ *cobra.Command, _ []string) {
 	// Set auto reconnect checking
 	go helpers.SetAutoReconnectChecking(whatsappCli)
 
+	if err := serveMcp(newMcpServer()); err != nil {
+		logrus.Fatalf("Failed to start MCP server: %v", err)
+	}
+}
+
+// initMcpOutput keeps stdout for the protocol in stdio mode, the WhatsApp client logs and anything else
+// printed to stdout would corrupt it
+func initMcpOutput() {
+	if config.McpTransport != mcpTransportStdio {
+		return
+	}
+	os.Stdout = os.Stderr
+	logrus.SetOutput(os.Stderr)
+}
+
+// newMcpServer creates the MCP server with every WhatsApp tool
+func newMcpServer() *server.MCPServer {
 	// Create MCP server with capabilities
+	serverOptions := []server.ServerOption{
+		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(true, true),
+	}
+	serverOptions = append(serverOptions, mcp.DeviceServerOptions()...)
+	serverOptions = append(serverOptions, mcp.AuthServerOptions(apiKeyUsecase)...)
+	serverOptions = append(serverOptions, mcp.ResourceServerOptions()...)
+
 	mcpServer := server.NewMCPServer(
 		"WhatsApp Web Multidevice MCP Server",
 		config.AppVersion,
-		server.WithToolCapabilities(true),
-		server.WithResourceCapabilities(true, true),
+		serverOptions...,
 	)
 
 	// Add all WhatsApp tools
 	sendHandler := mcp.InitMcpSend(sendUsecase)
 	sendHandler.AddSendTools(mcpServer)
 
-	queryHandler := mcp.InitMcpQuery(chatUsecase, userUsecase, messageUsecase)
+	queryHandler := mcp.InitMcpQuery(chatUsecase, userUsecase, messageUsecase, pollUsecase, callUsecase)
 	queryHandler.AddQueryTools(mcpServer)
 
 	appHandler := mcp.InitMcpApp(appUsecase)
@@ -53,20 +95,104 @@ func mcpServer(_ *cobra.Command, _ []string) {
 	groupHandler := mcp.InitMcpGroup(groupUsecase)
 	groupHandler.AddGroupTools(mcpServer)
 
-	// Create SSE server
+	communityHandler := mcp.InitMcpCommunity(communityUsecase)
+	communityHandler.AddCommunityTools(mcpServer)
+
+	newsletterHandler := mcp.InitMcpNewsletter(newsletterUsecase)
+	newsletterHandler.AddNewsletterTools(mcpServer)
+
+	deviceHandler := mcp.InitMcpDevice(deviceUsecase)
+	deviceHandler.AddDeviceTools(mcpServer)
+
+	messageHandler := mcp.InitMcpMessage(messageUsecase, chatUsecase)
+	messageHandler.AddMessageTools(mcpServer)
+
+	userHandler := mcp.InitMcpUser(userUsecase)
+	userHandler.AddUserTools(mcpServer)
+
+	campaignHandler := mcp.InitMcpCampaign(campaignUsecase)
+	campaignHandler.AddCampaignTools(mcpServer)
+
+	webhookHandler := mcp.InitMcpWebhook(webhookUsecase)
+	webhookHandler.AddWebhookTools(mcpServer)
+
+	autoReplyHandler := mcp.InitMcpAutoReply(autoReplyUsecase)
+	autoReplyHandler.AddAutoReplyTools(mcpServer)
+
+	apiKeyHandler := mcp.InitMcpAPIKey(apiKeyUsecase)
+	apiKeyHandler.AddAPIKeyTools(mcpServer)
+
+	// Add the chats, messages, contacts, groups and media as resources
+	resourceHandler := mcp.InitMcpResource(chatUsecase, userUsecase, groupUsecase, messageUsecase)
+	resourceHandler.AddResources(mcpServer)
+
+	return mcpServer
+}
+
+// serveMcp serves mcpServer over the configured transport until it stops
+func serveMcp(mcpServer *server.MCPServer) error {
+	switch config.McpTransport {
+	case mcpTransportStdio:
+		return serveMcpStdio(mcpServer)
+	case mcpTransportSSE:
+		return serveMcpSSE(mcpServer)
+	case mcpTransportHTTP:
+		return serveMcpHTTP(mcpServer)
+	default:
+		return fmt.Errorf("unknown transport %q, use %s, %s or %s", config.McpTransport, mcpTransportStdio, mcpTransportSSE, mcpTransportHTTP)
+	}
+}
+
+// serveMcpStdio serves the client that launched the process. Stdio has no credentials to check,
+// the client runs with the access of the local user that started it.
+func serveMcpStdio(mcpServer *server.MCPServer) error {
+	logrus.Println("Starting WhatsApp MCP server on stdio")
+	return server.NewStdioServer(mcpServer).Listen(context.Background(), mcp.SubscriptionReader(os.Stdin), mcpStdout)
+}
+
+func serveMcpSSE(mcpServer *server.MCPServer) error {
+	// Create SSE server, sharing its HTTP server with the metrics endpoint
+	addr := fmt.Sprintf("%s:%s", config.McpHost, config.McpPort)
+	httpServer := &http.Server{Addr: addr}
 	sseServer := server.NewSSEServer(
 		mcpServer,
 		server.WithBaseURL(fmt.Sprintf("http://%s:%s", config.McpHost, config.McpPort)),
 		server.WithKeepAlive(true),
+		server.WithSSEContextFunc(mcp.DeviceHTTPContext),
+		server.WithHTTPServer(httpServer),
 	)
+	httpServer.Handler = mcpHTTPHandler("/", sseServer)
 
 	// Start the SSE server
-	addr := fmt.Sprintf("%s:%s", config.McpHost, config.McpPort)
 	logrus.Printf("Starting WhatsApp MCP SSE server on %s", addr)
 	logrus.Printf("SSE endpoint: http://%s:%s/sse", config.McpHost, config.McpPort)
 	logrus.Printf("Message endpoint: http://%s:%s/message", config.McpHost, config.McpPort)
+	logrus.Printf("Metrics endpoint: http://%s:%s/metrics", config.McpHost, config.McpPort)
 
-	if err := sseServer.Start(addr); err != nil {
-		logrus.Fatalf("Failed to start SSE server: %v", err)
-	}
+	return sseServer.Start(addr)
+}
+
+func serveMcpHTTP(mcpServer *server.MCPServer) error {
+	addr := fmt.Sprintf("%s:%s", config.McpHost, config.McpPort)
+	httpServer := &http.Server{Addr: addr}
+	streamableServer := server.NewStreamableHTTPServer(
+		mcpServer,
+		server.WithHTTPContextFunc(mcp.DeviceHTTPContext),
+		server.WithStreamableHTTPServer(httpServer),
+	)
+	httpServer.Handler = mcpHTTPHandler("/mcp", streamableServer)
+
+	logrus.Printf("Starting WhatsApp MCP streamable HTTP server on %s", addr)
+	logrus.Printf("MCP endpoint: http://%s:%s/mcp", config.McpHost, config.McpPort)
+	logrus.Printf("Metrics endpoint: http://%s:%s/metrics", config.McpHost, config.McpPort)
+
+	return streamableServer.Start(addr)
+}
+
+// mcpHTTPHandler authenticates the requests to the MCP transport served on pattern and serves the metrics next to it
+func mcpHTTPHandler(pattern string, transport http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", mcp.AuthHandler(apiKeyUsecase, domainAPIKey.ScopeAdmin, metrics.Handler()))
+	mux.Handle(pattern, mcp.AuthHandler(apiKeyUsecase, "", mcp.SubscriptionHandler(transport)))
+	return mux
 }
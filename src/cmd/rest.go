@@ -6,12 +6,14 @@ import (
 	"strings"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
 	"github.com/dustin/go-humanize"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/basicauth"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/filesystem"
@@ -31,6 +33,10 @@ var restCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(restCmd)
+	restCmd.Flags().BoolVar(&config.McpWithRest, "mcp", config.McpWithRest, "Serve the MCP server too, sharing the WhatsApp session of the REST API")
+	restCmd.Flags().StringVar(&config.McpTransport, "mcp-transport", config.McpTransport, "MCP transport with --mcp: stdio, sse or http")
+	restCmd.Flags().StringVar(&config.McpHost, "mcp-host", config.McpHost, "Host for the SSE or HTTP MCP server with --mcp")
+	restCmd.Flags().StringVar(&config.McpPort, "mcp-port", config.McpPort, "Port for the SSE or HTTP MCP server with --mcp")
 }
 func restServer(_ *cobra.Command, _ []string) {
 	engine := html.NewFileSystem(http.FS(EmbedIndex), ".html")
@@ -71,7 +77,7 @@ func restServer(_ *cobra.Command, _ []string) {
 	}
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept",
+		AllowHeaders: "Origin, Content-Type, Accept, Authorization, " + middleware.DeviceIDHeader + ", " + middleware.APIKeyHeader,
 	}))
 
 	if len(config.AppBasicAuthCredential) > 0 {
@@ -86,9 +92,16 @@ func restServer(_ *cobra.Command, _ []string) {
 
 		app.Use(basicauth.New(basicauth.Config{
 			Users: account,
+			// Requests carrying an API key are authenticated by APIKeyAuth instead
+			Next: func(c *fiber.Ctx) bool {
+				return middleware.APIKeyFromRequest(c) != ""
+			},
 		}))
 	}
 
+	app.Use(middleware.DeviceSelector(config.AppBasePath))
+	app.Use(middleware.APIKeyAuth(config.AppBasePath, apiKeyUsecase))
+
 	// Create base path group or use app directly
 	var apiGroup fiber.Router = app
 	if config.AppBasePath != "" {
@@ -103,6 +116,18 @@ func restServer(_ *cobra.Command, _ []string) {
 	rest.InitRestMessage(apiGroup, messageUsecase)
 	rest.InitRestGroup(apiGroup, groupUsecase)
 	rest.InitRestNewsletter(apiGroup, newsletterUsecase)
+	rest.InitRestCommunity(apiGroup, communityUsecase)
+	rest.InitRestDevice(apiGroup, deviceUsecase)
+	rest.InitRestWebhook(apiGroup, webhookUsecase)
+	rest.InitRestCampaign(apiGroup, campaignUsecase)
+	rest.InitRestAutoReply(apiGroup, autoReplyUsecase)
+	rest.InitRestAPIKey(apiGroup, apiKeyUsecase)
+	rest.InitRestCall(apiGroup, callUsecase)
+	rest.InitRestPoll(apiGroup, pollUsecase)
+	rest.InitRestEvents(apiGroup)
+
+	// Prometheus metrics
+	apiGroup.Get("/metrics", adaptor.HTTPHandler(metrics.Handler()))
 
 	apiGroup.Get("/", func(c *fiber.Ctx) error {
 		return c.Render("views/index", fiber.Map{
@@ -116,6 +141,10 @@ func restServer(_ *cobra.Command, _ []string) {
 	})
 
 	websocket.RegisterRoutes(apiGroup, appUsecase)
+	// Without a replay buffer nothing is logged, so the event log is left alone
+	if config.AppEventBuffer > 0 {
+		websocket.SetEventStore(chatStorageRepo)
+	}
 	go websocket.RunHub()
 
 	// Set auto reconnect to whatsapp server after booting
@@ -123,6 +152,14 @@ func restServer(_ *cobra.Command, _ []string) {
 	// Set auto reconnect checking
 	go helpers.SetAutoReconnectChecking(whatsappCli)
 
+	if config.McpWithRest {
+		go func() {
+			if err := serveMcp(newMcpServer()); err != nil {
+				logrus.Fatalf("Failed to start MCP server: %v", err)
+			}
+		}()
+	}
+
 	if err := app.Listen(":" + config.AppPort); err != nil {
 		logrus.Fatalln("Failed to start: ", err.Error())
 	}
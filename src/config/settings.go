@@ -13,14 +13,19 @@ var (
 	AppBasicAuthCredential []string
 	AppBasePath            = ""
 	AppTrustedProxies      []string // Trusted proxy IP ranges (e.g., "0.0.0.0/0" for all, or specific CIDRs)
+	AppEventBuffer         = 1000   // Recent events kept to replay to websocket clients after a reconnect
 
-	McpPort = "8080"
-	McpHost = "localhost"
+	McpPort      = "8080"
+	McpHost      = "localhost"
+	McpTransport = "sse" // stdio, sse or http
+	McpWithRest  = false // Serve MCP from the rest command too, sharing its WhatsApp session
 
 	PathQrCode    = "statics/qrcode"
 	PathSendItems = "statics/senditems"
 	PathMedia     = "statics/media"
 	PathStorages  = "storages"
+	PathScheduled = "storages/scheduled"
+	PathCampaigns = "storages/campaigns"
 
 	DBURI     = "file:storages/whatsapp.db?_foreign_keys=on"
 	DBKeysURI = ""
@@ -30,6 +35,8 @@ var (
 	WhatsappAutoDownloadMedia      = true  // Auto-download media from incoming messages
 	WhatsappWebhook                []string
 	WhatsappWebhookSecret                = "secret"
+	WhatsappWebhookWorkers               = 4  // Concurrent webhook delivery workers
+	WhatsappWebhookMaxAttempts           = 10 // Delivery attempts before a webhook event is dead-lettered
 	WhatsappLogLevel                     = "ERROR"
 	WhatsappSettingMaxImageSize    int64 = 20000000  // 20MB
 	WhatsappSettingMaxFileSize     int64 = 50000000  // 50MB
@@ -39,6 +46,12 @@ var (
 	WhatsappTypeGroup                    = "@g.us"
 	WhatsappAccountValidation            = true
 
+	WhatsappCallReject        string                                        // Auto-reject incoming calls: all, unknown or outside_hours
+	WhatsappCallRejectMessage string                                        // Sent to the caller after an automatic rejection
+	WhatsappCallBusinessHours = "09:00-17:00"                               // Business hours of the outside_hours policy
+	WhatsappCallBusinessDays  = []string{"mon", "tue", "wed", "thu", "fri"} // Business days of the outside_hours policy
+	WhatsappCallTimezone      string                                        // Time zone of the business hours, the local one when empty
+
 	ChatStorageURI               = "file:storages/chatstorage.db"
 	ChatStorageEnableForeignKeys = true
 	ChatStorageEnableWAL         = true
@@ -0,0 +1,151 @@
+package apikey
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+)
+
+// Scopes grant access to groups of REST routes and MCP tools
+const (
+	ScopeSend       = "send"        // send messages, act on messages and chats, scheduled messages and campaigns
+	ScopeReadChats  = "read:chats"  // read chats, messages, contacts, groups and user information
+	ScopeGroupAdmin = "group:admin" // create and manage groups and newsletters
+	ScopeAppLogin   = "app:login"   // log devices in and out, reconnect and manage devices and the profile
+	ScopeAdmin      = "admin"       // everything, including API keys, the audit log, webhooks and auto-reply rules
+)
+
+// Scopes lists every scope a key can be granted
+var Scopes = []string{ScopeSend, ScopeReadChats, ScopeGroupAdmin, ScopeAppLogin, ScopeAdmin}
+
+// Sources of audited actions
+const (
+	SourceREST = "rest"
+	SourceMCP  = "mcp"
+	SourceCLI  = "cli"
+)
+
+type IAPIKeyUsecase interface {
+	ListKeys(ctx context.Context) (response []Key, err error)
+	CreateKey(ctx context.Context, request CreateKeyRequest) (response CreateKeyResponse, err error)
+	DeleteKey(ctx context.Context, keyID string) (err error)
+
+	// Authenticate resolves an API key to the principal it belongs to
+	Authenticate(ctx context.Context, token string) (principal *Principal, err error)
+	// AuthRequired reports whether requests without credentials must be rejected
+	AuthRequired(ctx context.Context) (required bool, err error)
+
+	RecordAudit(ctx context.Context, entry AuditEntry)
+	ListAudit(ctx context.Context, request ListAuditRequest) (response ListAuditResponse, err error)
+}
+
+type Key struct {
+	ID                string     `json:"id"`
+	Name              string     `json:"name"`
+	Prefix            string     `json:"prefix"`
+	Scopes            []string   `json:"scopes"`
+	AllowedRecipients []string   `json:"allowed_recipients"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt        *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// CreateKeyRequest creates a key. Allowed recipients are patterns such as "62812*" or "*@g.us";
+// when given, the key can only send to recipients matching one of them.
+type CreateKeyRequest struct {
+	Name              string     `json:"name" form:"name"`
+	Scopes            []string   `json:"scopes" form:"scopes"`
+	AllowedRecipients []string   `json:"allowed_recipients" form:"allowed_recipients"`
+	ExpiresAt         *time.Time `json:"expires_at" form:"expires_at"`
+}
+
+// CreateKeyResponse carries the plain key, which is only shown once
+type CreateKeyResponse struct {
+	Key
+	APIKey string `json:"api_key"`
+}
+
+type AuditEntry struct {
+	ID        int64     `json:"id"`
+	KeyID     string    `json:"key_id,omitempty"`
+	Actor     string    `json:"actor"`
+	Source    string    `json:"source"`
+	DeviceID  string    `json:"device_id,omitempty"`
+	Action    string    `json:"action"`
+	Target    string    `json:"target,omitempty"`
+	Status    int       `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ListAuditRequest struct {
+	KeyID  string `json:"key_id" query:"key_id"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+type ListAuditResponse struct {
+	Data   []AuditEntry `json:"data"`
+	Limit  int          `json:"limit"`
+	Offset int          `json:"offset"`
+}
+
+// Principal is the caller a request was authenticated as
+type Principal struct {
+	KeyID             string
+	Name              string
+	Scopes            []string
+	AllowedRecipients []string
+}
+
+// HasScope reports whether the principal was granted scope. The admin scope grants every scope.
+func (p *Principal) HasScope(scope string) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsRecipient reports whether the principal may send to recipient, given as a phone number or JID
+func (p *Principal) AllowsRecipient(recipient string) bool {
+	if len(p.AllowedRecipients) == 0 {
+		return true
+	}
+
+	recipient = strings.TrimPrefix(strings.TrimSpace(recipient), "+")
+	candidates := []string{recipient}
+	if user, _, found := strings.Cut(recipient, "@"); found {
+		candidates = append(candidates, user)
+	} else {
+		candidates = append(candidates, recipient+"@s.whatsapp.net")
+	}
+
+	for _, pattern := range p.AllowedRecipients {
+		pattern = strings.TrimPrefix(pattern, "+")
+		for _, candidate := range candidates {
+			if matched, _ := path.Match(pattern, candidate); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type principalContextKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx carrying the authenticated principal
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal the request was authenticated as
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	principal, ok := ctx.Value(principalContextKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
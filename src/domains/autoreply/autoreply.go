@@ -0,0 +1,129 @@
+package autoreply
+
+import (
+	"context"
+	"time"
+)
+
+// Text match types
+const (
+	MatchKeyword = "keyword"
+	MatchRegex   = "regex"
+	MatchExact   = "exact"
+)
+
+// Chat types a rule can be limited to
+const (
+	ChatTypeAll   = "all"
+	ChatTypeDM    = "dm"
+	ChatTypeGroup = "group"
+)
+
+// Action types
+const (
+	ActionReply    = "reply"
+	ActionReact    = "react"
+	ActionMarkRead = "mark_read"
+	ActionLabel    = "label"
+	ActionWebhook  = "webhook"
+)
+
+// Message types a rule can be limited to
+const (
+	MessageText     = "text"
+	MessageImage    = "image"
+	MessageVideo    = "video"
+	MessageAudio    = "audio"
+	MessageDocument = "document"
+	MessageSticker  = "sticker"
+	MessageLocation = "location"
+	MessageContact  = "contact"
+	MessagePoll     = "poll"
+)
+
+// MessageTypes lists every message type rules can match
+var MessageTypes = []string{
+	MessageText, MessageImage, MessageVideo, MessageAudio, MessageDocument,
+	MessageSticker, MessageLocation, MessageContact, MessagePoll,
+}
+
+// Media types a reply can attach from a URL
+const (
+	MediaImage = "image"
+	MediaVideo = "video"
+	MediaAudio = "audio"
+)
+
+type IAutoReplyUsecase interface {
+	ListRules(ctx context.Context) (response []Rule, err error)
+	GetRule(ctx context.Context, ruleID string) (response Rule, err error)
+	CreateRule(ctx context.Context, request RuleRequest) (response Rule, err error)
+	UpdateRule(ctx context.Context, request RuleRequest) (response Rule, err error)
+	DeleteRule(ctx context.Context, ruleID string) (err error)
+	ReplaceRules(ctx context.Context, request ReplaceRulesRequest) (response []Rule, err error)
+}
+
+// Conditions must all hold for a rule to match. Empty conditions match every incoming message.
+type Conditions struct {
+	MatchType     string         `json:"match_type,omitempty"`
+	Patterns      []string       `json:"patterns,omitempty"`
+	CaseSensitive bool           `json:"case_sensitive,omitempty"`
+	Senders       []string       `json:"senders,omitempty"`
+	ChatType      string         `json:"chat_type,omitempty"`
+	MessageTypes  []string       `json:"message_types,omitempty"`
+	BusinessHours *BusinessHours `json:"business_hours,omitempty"`
+}
+
+// BusinessHours limits a rule to a daily time window, or to the time outside of it
+type BusinessHours struct {
+	Timezone string   `json:"timezone,omitempty"`
+	Days     []string `json:"days,omitempty"`
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+	Outside  bool     `json:"outside,omitempty"`
+}
+
+// Action is run when a rule matches. Replies go to the chat the message came from.
+type Action struct {
+	Type      string `json:"type"`
+	Text      string `json:"text,omitempty"`
+	MediaURL  string `json:"media_url,omitempty"`
+	MediaType string `json:"media_type,omitempty"`
+	Quote     bool   `json:"quote,omitempty"`
+	Emoji     string `json:"emoji,omitempty"`
+	LabelID   string `json:"label_id,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+type Rule struct {
+	ID               string     `json:"id"`
+	Name             string     `json:"name"`
+	Position         int        `json:"position"`
+	Enabled          bool       `json:"enabled"`
+	DeviceID         string     `json:"device_id,omitempty"`
+	Conditions       Conditions `json:"conditions"`
+	Actions          []Action   `json:"actions"`
+	CooldownSeconds  int        `json:"cooldown_seconds"`
+	ContinueMatching bool       `json:"continue_matching"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// RuleRequest creates or replaces a rule. Rules are evaluated by ascending position and
+// evaluation stops at the first matching rule unless it sets continue_matching.
+type RuleRequest struct {
+	ID               string     `json:"id" form:"-"`
+	Name             string     `json:"name"`
+	Position         int        `json:"position"`
+	Enabled          *bool      `json:"enabled"`
+	DeviceID         string     `json:"device_id"`
+	Conditions       Conditions `json:"conditions"`
+	Actions          []Action   `json:"actions"`
+	CooldownSeconds  int        `json:"cooldown_seconds"`
+	ContinueMatching bool       `json:"continue_matching"`
+}
+
+// ReplaceRulesRequest swaps the whole rule set, e.g. to import an exported configuration
+type ReplaceRulesRequest struct {
+	Rules []RuleRequest `json:"rules"`
+}
@@ -0,0 +1,49 @@
+package call
+
+import (
+	"context"
+	"time"
+)
+
+// Auto-reject policies for incoming calls
+const (
+	RejectAll          = "all"
+	RejectUnknown      = "unknown"
+	RejectOutsideHours = "outside_hours"
+)
+
+// RejectPolicies lists every auto-reject policy
+var RejectPolicies = []string{RejectAll, RejectUnknown, RejectOutsideHours}
+
+type ICallUsecase interface {
+	ListCalls(ctx context.Context, request ListCallsRequest) (response ListCallsResponse, err error)
+}
+
+type ListCallsRequest struct {
+	Phone  string `json:"phone" query:"phone"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+type ListCallsResponse struct {
+	Data   []Call `json:"data"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+// Call is an entry of the call log. ChatJID is the other party, or the group of a group call.
+type Call struct {
+	ID              string     `json:"id"`
+	ChatJID         string     `json:"chat_jid"`
+	CallerJID       string     `json:"caller_jid"`
+	IsFromMe        bool       `json:"is_from_me"`
+	IsVideo         bool       `json:"is_video"`
+	IsGroup         bool       `json:"is_group"`
+	Status          string     `json:"status"`
+	AutoRejected    bool       `json:"auto_rejected"`
+	EndReason       string     `json:"end_reason,omitempty"`
+	DurationSeconds int        `json:"duration_seconds"`
+	StartedAt       time.Time  `json:"started_at"`
+	AcceptedAt      *time.Time `json:"accepted_at,omitempty"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+}
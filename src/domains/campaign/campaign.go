@@ -0,0 +1,104 @@
+package campaign
+
+import (
+	"context"
+	"mime/multipart"
+	"time"
+)
+
+type ICampaignUsecase interface {
+	CreateCampaign(ctx context.Context, request CreateCampaignRequest) (response Campaign, err error)
+	ListCampaigns(ctx context.Context, request ListCampaignsRequest) (response ListCampaignsResponse, err error)
+	GetCampaign(ctx context.Context, campaignID string) (response Campaign, err error)
+	ListRecipients(ctx context.Context, request ListRecipientsRequest) (response ListRecipientsResponse, err error)
+	PauseCampaign(ctx context.Context, campaignID string) (response Campaign, err error)
+	ResumeCampaign(ctx context.Context, campaignID string) (response Campaign, err error)
+	CancelCampaign(ctx context.Context, campaignID string) (response Campaign, err error)
+}
+
+// Recipient is one entry of a campaign recipient list, its variables fill the {{name}} placeholders of the message
+type Recipient struct {
+	Phone     string            `json:"phone"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// CreateCampaignRequest submits a campaign. Recipients are given as JSON or uploaded as a CSV or JSON
+// file, the message is sent as the caption when an image is attached.
+type CreateCampaignRequest struct {
+	Name            string                `json:"name" form:"name"`
+	Message         string                `json:"message" form:"message"`
+	Image           *multipart.FileHeader `json:"-" form:"-"`
+	Recipients      []Recipient           `json:"recipients" form:"-"`
+	RecipientsFile  *multipart.FileHeader `json:"-" form:"-"`
+	RatePerMinute   int                   `json:"rate_per_minute" form:"rate_per_minute"`
+	MinDelaySeconds int                   `json:"min_delay_seconds" form:"min_delay_seconds"`
+	MaxDelaySeconds int                   `json:"max_delay_seconds" form:"max_delay_seconds"`
+	ValidateNumbers bool                  `json:"validate_numbers" form:"validate_numbers"`
+}
+
+type Campaign struct {
+	ID              string     `json:"id"`
+	DeviceID        string     `json:"device_id,omitempty"`
+	Name            string     `json:"name"`
+	Message         string     `json:"message"`
+	HasImage        bool       `json:"has_image"`
+	RatePerMinute   int        `json:"rate_per_minute"`
+	MinDelaySeconds int        `json:"min_delay_seconds"`
+	MaxDelaySeconds int        `json:"max_delay_seconds"`
+	ValidateNumbers bool       `json:"validate_numbers"`
+	Status          string     `json:"status"`
+	Stats           Stats      `json:"stats"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+}
+
+// Stats counts the recipients of a campaign per status
+type Stats struct {
+	Total       int `json:"total"`
+	Queued      int `json:"queued"`
+	Sending     int `json:"sending"`
+	Sent        int `json:"sent"`
+	Delivered   int `json:"delivered"`
+	Read        int `json:"read"`
+	Failed      int `json:"failed"`
+	NeedsReview int `json:"needs_review"`
+}
+
+type RecipientStatus struct {
+	ID          int64             `json:"id"`
+	Phone       string            `json:"phone"`
+	Variables   map[string]string `json:"variables,omitempty"`
+	Status      string            `json:"status"`
+	MessageID   string            `json:"message_id,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	SentAt      *time.Time        `json:"sent_at,omitempty"`
+	DeliveredAt *time.Time        `json:"delivered_at,omitempty"`
+	ReadAt      *time.Time        `json:"read_at,omitempty"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+type ListCampaignsRequest struct {
+	Status string `json:"status" query:"status"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+type ListCampaignsResponse struct {
+	Data   []Campaign `json:"data"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+}
+
+type ListRecipientsRequest struct {
+	CampaignID string `json:"campaign_id" query:"-"`
+	Status     string `json:"status" query:"status"`
+	Limit      int    `json:"limit" query:"limit"`
+	Offset     int    `json:"offset" query:"offset"`
+}
+
+type ListRecipientsResponse struct {
+	Data   []RecipientStatus `json:"data"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
@@ -31,6 +31,34 @@ type GetChatMessagesResponse struct {
 	ChatInfo   ChatInfo           `json:"chat_info"`
 }
 
+// SearchMessagesRequest searches stored messages across all chats. Query accepts the
+// full-text syntax: "exact phrase", prefix*, AND, OR and NOT.
+type SearchMessagesRequest struct {
+	Query     string  `json:"q" query:"q"`
+	ChatJID   string  `json:"chat_jid" query:"chat_jid"`
+	Sender    string  `json:"sender" query:"sender"`
+	StartTime *string `json:"start_time" query:"start_time"`
+	EndTime   *string `json:"end_time" query:"end_time"`
+	MediaType string  `json:"media_type" query:"media_type"`
+	MediaOnly bool    `json:"media_only" query:"media_only"`
+	IsFromMe  *bool   `json:"is_from_me" query:"is_from_me"`
+	Sort      string  `json:"sort" query:"sort"`
+	Limit     int     `json:"limit" query:"limit"`
+	Offset    int     `json:"offset" query:"offset"`
+}
+
+type SearchMessagesResponse struct {
+	Data       []SearchMessageInfo `json:"data"`
+	Pagination PaginationResponse  `json:"pagination"`
+}
+
+type SearchMessageInfo struct {
+	MessageInfo
+	ChatName string  `json:"chat_name"`
+	Snippet  string  `json:"snippet"`
+	Rank     float64 `json:"rank"`
+}
+
 // Pin Chat operations
 type PinChatRequest struct {
 	ChatJID string `json:"chat_jid" uri:"chat_jid"`
@@ -64,8 +92,29 @@ type MessageInfo struct {
 	Filename   string `json:"filename"`
 	URL        string `json:"url"`
 	FileLength uint64 `json:"file_length"`
-	CreatedAt  string `json:"created_at"`
-	UpdatedAt  string `json:"updated_at"`
+	// Status is the delivery state of messages sent by us: sent, server_ack, delivered, read or played
+	Status string `json:"status,omitempty"`
+	// Content is the latest text of edited messages, Edits holds every edit oldest first
+	EditedAt  string                `json:"edited_at,omitempty"`
+	Edits     []MessageEditInfo     `json:"edits,omitempty"`
+	Reactions []MessageReactionInfo `json:"reactions,omitempty"`
+	IsRevoked bool                  `json:"is_revoked"`
+	RevokedAt string                `json:"revoked_at,omitempty"`
+	CreatedAt string                `json:"created_at"`
+	UpdatedAt string                `json:"updated_at"`
+}
+
+type MessageEditInfo struct {
+	OldContent string `json:"old_content"`
+	NewContent string `json:"new_content"`
+	EditedAt   string `json:"edited_at"`
+}
+
+// MessageReactionInfo is the current reaction of one sender, removed reactions are not listed
+type MessageReactionInfo struct {
+	SenderJID string `json:"sender_jid"`
+	Reaction  string `json:"reaction"`
+	ReactedAt string `json:"reacted_at"`
 }
 
 type PaginationResponse struct {
@@ -1,6 +1,9 @@
 package chatstorage
 
-import "time"
+import (
+	"errors"
+	"time"
+)
 
 // Chat represents a WhatsApp chat/conversation
 type Chat struct {
@@ -14,21 +17,413 @@ type Chat struct {
 
 // Message represents a WhatsApp message
 type Message struct {
-	ID            string    `db:"id"`
-	ChatJID       string    `db:"chat_jid"`
-	Sender        string    `db:"sender"`
-	Content       string    `db:"content"`
-	Timestamp     time.Time `db:"timestamp"`
-	IsFromMe      bool      `db:"is_from_me"`
-	MediaType     string    `db:"media_type"`
-	Filename      string    `db:"filename"`
-	URL           string    `db:"url"`
-	MediaKey      []byte    `db:"media_key"`
-	FileSHA256    []byte    `db:"file_sha256"`
-	FileEncSHA256 []byte    `db:"file_enc_sha256"`
-	FileLength    uint64    `db:"file_length"`
-	CreatedAt     time.Time `db:"created_at"`
-	UpdatedAt     time.Time `db:"updated_at"`
+	ID            string     `db:"id"`
+	ChatJID       string     `db:"chat_jid"`
+	Sender        string     `db:"sender"`
+	Content       string     `db:"content"`
+	Timestamp     time.Time  `db:"timestamp"`
+	IsFromMe      bool       `db:"is_from_me"`
+	MediaType     string     `db:"media_type"`
+	Filename      string     `db:"filename"`
+	URL           string     `db:"url"`
+	MediaKey      []byte     `db:"media_key"`
+	FileSHA256    []byte     `db:"file_sha256"`
+	FileEncSHA256 []byte     `db:"file_enc_sha256"`
+	FileLength    uint64     `db:"file_length"`
+	EditedAt      *time.Time `db:"edited_at"`
+	IsRevoked     bool       `db:"is_revoked"`
+	RevokedAt     *time.Time `db:"revoked_at"`
+	CreatedAt     time.Time  `db:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+}
+
+// MessageEdit is one edit of a stored message, ID is the ID of the edit message itself
+type MessageEdit struct {
+	ID         string    `db:"id"`
+	MessageID  string    `db:"message_id"`
+	ChatJID    string    `db:"chat_jid"`
+	OldContent string    `db:"old_content"`
+	NewContent string    `db:"new_content"`
+	EditedAt   time.Time `db:"edited_at"`
+}
+
+// MessageReaction is the current reaction of one sender to a message
+type MessageReaction struct {
+	MessageID string    `db:"message_id"`
+	ChatJID   string    `db:"chat_jid"`
+	SenderJID string    `db:"sender_jid"`
+	Reaction  string    `db:"reaction"`
+	ReactedAt time.Time `db:"reacted_at"`
+}
+
+// Outgoing message states, in the order a message reaches them
+const (
+	MessageStatusSent      = "sent"
+	MessageStatusServerAck = "server_ack"
+	MessageStatusDelivered = "delivered"
+	MessageStatusRead      = "read"
+	MessageStatusPlayed    = "played"
+)
+
+// MessageStatusRank orders the message states, unknown states rank lowest
+func MessageStatusRank(status string) int {
+	switch status {
+	case MessageStatusSent:
+		return 1
+	case MessageStatusServerAck:
+		return 2
+	case MessageStatusDelivered:
+		return 3
+	case MessageStatusRead:
+		return 4
+	case MessageStatusPlayed:
+		return 5
+	}
+	return 0
+}
+
+// MessageReceipt records when an outgoing message reached a state for one participant.
+// The server acknowledgement is stored without a participant.
+type MessageReceipt struct {
+	MessageID      string    `db:"message_id"`
+	ChatJID        string    `db:"chat_jid"`
+	ParticipantJID string    `db:"participant_jid"`
+	Status         string    `db:"status"`
+	Timestamp      time.Time `db:"timestamp"`
+}
+
+// Call states. A call rings until it is accepted, rejected or missed; accepted calls end.
+const (
+	CallStatusRinging  = "ringing"
+	CallStatusAccepted = "accepted"
+	CallStatusRejected = "rejected"
+	CallStatusMissed   = "missed"
+	CallStatusEnded    = "ended"
+)
+
+// Call is an entry of the call log. ChatJID is the other party, or the group of a group call.
+type Call struct {
+	ID           string     `db:"id"`
+	ChatJID      string     `db:"chat_jid"`
+	CallerJID    string     `db:"caller_jid"`
+	IsFromMe     bool       `db:"is_from_me"`
+	IsVideo      bool       `db:"is_video"`
+	IsGroup      bool       `db:"is_group"`
+	Status       string     `db:"status"`
+	AutoRejected bool       `db:"auto_rejected"`
+	EndReason    string     `db:"end_reason"`
+	StartedAt    time.Time  `db:"started_at"`
+	AcceptedAt   *time.Time `db:"accepted_at"`
+	EndedAt      *time.Time `db:"ended_at"`
+}
+
+// CallFilter represents query filters for the call log
+type CallFilter struct {
+	ChatJID string
+	Limit   int
+	Offset  int
+}
+
+// Poll is a poll sent or received by the device. Secret is the message secret the votes are encrypted with.
+type Poll struct {
+	MessageID       string    `db:"message_id"`
+	ChatJID         string    `db:"chat_jid"`
+	CreatorJID      string    `db:"creator_jid"`
+	Question        string    `db:"question"`
+	Options         []string  `db:"options"`
+	SelectableCount int       `db:"selectable_count"`
+	Secret          []byte    `db:"secret"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+// PollVote is the current vote of one voter, a later vote replaces it. SelectedOptions is empty when the
+// voter took back their vote; options that are not part of the poll are kept as their hex SHA-256 hash.
+type PollVote struct {
+	PollID          string    `db:"poll_id"`
+	VoterJID        string    `db:"voter_jid"`
+	SelectedOptions []string  `db:"selected_options"`
+	VotedAt         time.Time `db:"voted_at"`
+}
+
+// Group event types. Membership changes are recorded once per participant, settings changes with their new value.
+const (
+	GroupEventJoin               = "join"
+	GroupEventLeave              = "leave"
+	GroupEventPromote            = "promote"
+	GroupEventDemote             = "demote"
+	GroupEventName               = "name"
+	GroupEventTopic              = "topic"
+	GroupEventLocked             = "locked"
+	GroupEventAnnounce           = "announce"
+	GroupEventEphemeral          = "ephemeral"
+	GroupEventMembershipApproval = "membership_approval"
+	GroupEventPhoto              = "photo"
+	GroupEventInviteLink         = "invite_link"
+	GroupEventLink               = "link"
+	GroupEventUnlink             = "unlink"
+	GroupEventDelete             = "delete"
+)
+
+// GroupEventTypes lists every group event type
+var GroupEventTypes = []string{
+	GroupEventJoin, GroupEventLeave, GroupEventPromote, GroupEventDemote,
+	GroupEventName, GroupEventTopic, GroupEventLocked, GroupEventAnnounce, GroupEventEphemeral,
+	GroupEventMembershipApproval, GroupEventPhoto, GroupEventInviteLink, GroupEventLink, GroupEventUnlink, GroupEventDelete,
+}
+
+// GroupEvent is a membership or settings change of a group. TargetJID is the participant of membership changes,
+// Value the new value of settings changes.
+type GroupEvent struct {
+	ID        int64     `db:"id"`
+	GroupJID  string    `db:"group_jid"`
+	Type      string    `db:"type"`
+	ActorJID  string    `db:"actor_jid"`
+	TargetJID string    `db:"target_jid"`
+	Value     string    `db:"value"`
+	Timestamp time.Time `db:"timestamp"`
+}
+
+// GroupEventFilter selects group events, empty fields match everything
+type GroupEventFilter struct {
+	GroupJID  string
+	Types     []string
+	ActorJID  string
+	TargetJID string
+	StartTime *time.Time
+	EndTime   *time.Time
+	Limit     int
+	Offset    int
+}
+
+// StreamEvent is an entry of the on-disk event log the event stream replays from. Data holds the
+// webhook body of the event as JSON.
+type StreamEvent struct {
+	ID        int64     `db:"id"`
+	Event     string    `db:"event"`
+	DeviceID  string    `db:"device_id"`
+	ChatJID   string    `db:"chat_jid"`
+	Data      string    `db:"data"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// DeviceRecord represents a registered WhatsApp device managed by this instance
+type DeviceRecord struct {
+	ID        string    `db:"id"`
+	JID       string    `db:"jid"`
+	Webhooks  []string  `db:"webhooks"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// Webhook delivery states
+const (
+	WebhookDeliveryPending    = "pending"
+	WebhookDeliveryDelivering = "delivering"
+	WebhookDeliveryDelivered  = "delivered"
+	WebhookDeliveryDead       = "dead"
+)
+
+// WebhookDelivery represents an outgoing webhook event persisted in the delivery queue
+type WebhookDelivery struct {
+	ID            int64      `db:"id"`
+	WebhookID     string     `db:"webhook_id"`
+	DeviceID      string     `db:"device_id"`
+	Event         string     `db:"event"`
+	URL           string     `db:"url"`
+	Payload       string     `db:"payload"`
+	Status        string     `db:"status"`
+	Attempts      int        `db:"attempts"`
+	LastError     string     `db:"last_error"`
+	NextAttemptAt time.Time  `db:"next_attempt_at"`
+	DeliveredAt   *time.Time `db:"delivered_at"`
+	CreatedAt     time.Time  `db:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+}
+
+// Webhook subscription chat types
+const (
+	WebhookChatTypeAll   = "all"
+	WebhookChatTypeGroup = "group"
+	WebhookChatTypeDM    = "dm"
+)
+
+// WebhookSubscription represents a configured webhook endpoint with its own secret and event filters
+type WebhookSubscription struct {
+	ID           string    `db:"id"`
+	URL          string    `db:"url"`
+	Secret       string    `db:"secret"`
+	DeviceID     string    `db:"device_id"`
+	Events       []string  `db:"events"`
+	AllowJIDs    []string  `db:"allow_jids"`
+	DenyJIDs     []string  `db:"deny_jids"`
+	SkipFromMe   bool      `db:"skip_from_me"`
+	ChatType     string    `db:"chat_type"`
+	IncludeMedia bool      `db:"include_media"`
+	Enabled      bool      `db:"enabled"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+// Scheduled message states
+const (
+	ScheduledMessagePending   = "pending"
+	ScheduledMessageSending   = "sending"
+	ScheduledMessageSent      = "sent"
+	ScheduledMessageFailed    = "failed"
+	ScheduledMessageCancelled = "cancelled"
+)
+
+// ScheduledMessage represents a send request persisted until it is due
+type ScheduledMessage struct {
+	ID            string     `db:"id"`
+	DeviceID      string     `db:"device_id"`
+	Type          string     `db:"type"`
+	Phone         string     `db:"phone"`
+	Payload       string     `db:"payload"`
+	MediaPath     string     `db:"media_path"`
+	SendAt        time.Time  `db:"send_at"`
+	Status        string     `db:"status"`
+	Attempts      int        `db:"attempts"`
+	LastError     string     `db:"last_error"`
+	MessageID     string     `db:"message_id"`
+	NextAttemptAt time.Time  `db:"next_attempt_at"`
+	SentAt        *time.Time `db:"sent_at"`
+	CreatedAt     time.Time  `db:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+}
+
+// ScheduledMessageFilter represents query filters for scheduled messages
+type ScheduledMessageFilter struct {
+	DeviceID string
+	Status   string
+	Limit    int
+	Offset   int
+}
+
+// Campaign states
+const (
+	CampaignRunning   = "running"
+	CampaignPaused    = "paused"
+	CampaignCompleted = "completed"
+	CampaignCancelled = "cancelled"
+)
+
+// Campaign recipient states, a sent message moves on to delivered and read as receipts arrive. A recipient
+// is sending while its message is handed to WhatsApp; one left sending by a crash needs review, since the
+// message may have gone out before its outcome was recorded.
+const (
+	CampaignRecipientQueued      = "queued"
+	CampaignRecipientSending     = "sending"
+	CampaignRecipientSent        = "sent"
+	CampaignRecipientDelivered   = "delivered"
+	CampaignRecipientRead        = "read"
+	CampaignRecipientFailed      = "failed"
+	CampaignRecipientNeedsReview = "needs_review"
+)
+
+// Campaign represents one message template sent to many recipients at a throttled pace
+type Campaign struct {
+	ID              string     `db:"id"`
+	DeviceID        string     `db:"device_id"`
+	Name            string     `db:"name"`
+	Message         string     `db:"message"`
+	MediaPath       string     `db:"media_path"`
+	MediaType       string     `db:"media_type"`
+	RatePerMinute   int        `db:"rate_per_minute"`
+	MinDelaySeconds int        `db:"min_delay_seconds"`
+	MaxDelaySeconds int        `db:"max_delay_seconds"`
+	ValidateNumbers bool       `db:"validate_numbers"`
+	Status          string     `db:"status"`
+	CreatedAt       time.Time  `db:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"`
+	CompletedAt     *time.Time `db:"completed_at"`
+}
+
+// CampaignRecipient represents the delivery state of a campaign message for one recipient
+type CampaignRecipient struct {
+	ID          int64      `db:"id"`
+	CampaignID  string     `db:"campaign_id"`
+	Phone       string     `db:"phone"`
+	Variables   string     `db:"variables"`
+	Status      string     `db:"status"`
+	MessageID   string     `db:"message_id"`
+	Error       string     `db:"error"`
+	SentAt      *time.Time `db:"sent_at"`
+	DeliveredAt *time.Time `db:"delivered_at"`
+	ReadAt      *time.Time `db:"read_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
+
+// CampaignFilter represents query filters for campaigns
+type CampaignFilter struct {
+	DeviceID string
+	Status   string
+	Limit    int
+	Offset   int
+}
+
+// CampaignRecipientFilter represents query filters for the recipients of a campaign
+type CampaignRecipientFilter struct {
+	CampaignID string
+	Status     string
+	Limit      int
+	Offset     int
+}
+
+// AutoReplyRule is a persisted auto-reply rule. Conditions and actions are stored as JSON.
+type AutoReplyRule struct {
+	ID               string    `db:"id"`
+	Name             string    `db:"name"`
+	Position         int       `db:"position"`
+	Enabled          bool      `db:"enabled"`
+	DeviceID         string    `db:"device_id"`
+	Conditions       string    `db:"conditions"`
+	Actions          string    `db:"actions"`
+	CooldownSeconds  int       `db:"cooldown_seconds"`
+	ContinueMatching bool      `db:"continue_matching"`
+	CreatedAt        time.Time `db:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at"`
+}
+
+// APIKey is a named credential for the REST API and the MCP server. Only the SHA-256 hash of the key is stored.
+type APIKey struct {
+	ID                string     `db:"id"`
+	Name              string     `db:"name"`
+	KeyHash           string     `db:"key_hash"`
+	KeyPrefix         string     `db:"key_prefix"`
+	Scopes            []string   `db:"scopes"`
+	AllowedRecipients []string   `db:"allowed_recipients"`
+	ExpiresAt         *time.Time `db:"expires_at"`
+	LastUsedAt        *time.Time `db:"last_used_at"`
+	CreatedAt         time.Time  `db:"created_at"`
+}
+
+// AuditLog records an action performed through the REST API, the MCP server or the CLI
+type AuditLog struct {
+	ID        int64     `db:"id"`
+	KeyID     string    `db:"key_id"`
+	Actor     string    `db:"actor"`
+	Source    string    `db:"source"`
+	DeviceID  string    `db:"device_id"`
+	Action    string    `db:"action"`
+	Target    string    `db:"target"`
+	Status    int       `db:"status"`
+	Error     string    `db:"error"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// AuditLogFilter represents query filters for the audit log
+type AuditLogFilter struct {
+	KeyID  string
+	Limit  int
+	Offset int
+}
+
+// WebhookDeliveryFilter represents query filters for webhook deliveries
+type WebhookDeliveryFilter struct {
+	Status string
+	Limit  int
+	Offset int
 }
 
 // MediaInfo represents downloadable media information
@@ -55,6 +450,38 @@ type MessageFilter struct {
 	IsFromMe  *bool
 }
 
+// Orderings for full-text message search
+const (
+	SearchSortRelevance = "relevance"
+	SearchSortNewest    = "newest"
+)
+
+// ErrInvalidSearchQuery is returned when a full-text query cannot be parsed
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+// MessageSearchFilter represents full-text search options across all chats
+type MessageSearchFilter struct {
+	Query     string
+	ChatJID   string
+	Sender    string
+	StartTime *time.Time
+	EndTime   *time.Time
+	MediaType string
+	MediaOnly bool
+	IsFromMe  *bool
+	Sort      string
+	Limit     int
+	Offset    int
+}
+
+// MessageSearchResult is a message matched by a full-text search
+type MessageSearchResult struct {
+	Message  *Message
+	ChatName string
+	Snippet  string
+	Rank     float64
+}
+
 // ChatFilter represents query filters for chats
 type ChatFilter struct {
 	Limit      int
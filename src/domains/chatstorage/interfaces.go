@@ -21,10 +21,45 @@ type IChatStorageRepository interface {
 	StoreMessagesBatch(messages []*Message) error
 	GetMessageByID(id string) (*Message, error) // New method for efficient ID-only search
 	GetMessages(filter *MessageFilter) ([]*Message, error)
-	SearchMessages(chatJID, searchText string, limit int) ([]*Message, error) // Database-level search
+	SearchMessages(chatJID, searchText string, limit int) ([]*Message, error)      // Database-level search
+	SearchAllMessages(filter *MessageSearchFilter) ([]*MessageSearchResult, error) // Full-text search across chats
+	CountSearchMessages(filter *MessageSearchFilter) (int64, error)
 	DeleteMessage(id, chatJID string) error
 	StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error
 
+	// Message receipt operations
+	StoreMessageReceipts(receipts []*MessageReceipt) error
+	GetMessageReceipts(messageIDs []string) ([]*MessageReceipt, error)
+
+	// Message edit, revoke and reaction operations
+	StoreMessageEdit(edit *MessageEdit) error
+	GetMessageEdits(messageIDs []string) ([]*MessageEdit, error)
+	RevokeMessage(id, chatJID string, revokedAt time.Time) error
+	StoreMessageReaction(reaction *MessageReaction) error
+	GetMessageReactions(messageIDs []string) ([]*MessageReaction, error)
+
+	// Call log operations
+	StoreCall(call *Call) error
+	GetCall(id string) (*Call, error)
+	GetCalls(filter *CallFilter) ([]*Call, error)
+
+	// Poll operations
+	StorePoll(poll *Poll) error
+	GetPoll(messageID string) (*Poll, error)
+	StorePollVote(vote *PollVote) error
+	GetPollVotes(pollID string) ([]*PollVote, error)
+
+	// Group event operations
+	StoreGroupEvents(events []*GroupEvent) error
+	GetGroupEvents(filter *GroupEventFilter) ([]*GroupEvent, error)
+	CountGroupEvents(filter *GroupEventFilter) (int64, error)
+
+	// Event stream log operations
+	StoreStreamEvent(event *StreamEvent) error
+	GetStreamEventsAfter(afterID int64, limit int) ([]*StreamEvent, error)
+	GetLastStreamEventID() (int64, error)
+	TrimStreamEvents(upToID int64) error
+
 	// Statistics
 	GetChatMessageCount(chatJID string) (int64, error)
 	GetTotalMessageCount() (int64, error)
@@ -32,6 +67,76 @@ type IChatStorageRepository interface {
 	GetChatNameWithPushName(jid types.JID, chatJID string, senderUser string, pushName string) string
 	GetStorageStatistics() (chatCount int64, messageCount int64, err error)
 
+	// Device registry operations
+	StoreDeviceRecord(device *DeviceRecord) error
+	GetDeviceRecords() ([]*DeviceRecord, error)
+	DeleteDeviceRecord(id string) error
+
+	// Webhook subscription operations
+	StoreWebhookSubscription(subscription *WebhookSubscription) error
+	GetWebhookSubscription(id string) (*WebhookSubscription, error)
+	GetWebhookSubscriptions() ([]*WebhookSubscription, error)
+	DeleteWebhookSubscription(id string) error
+
+	// Webhook delivery queue operations
+	EnqueueWebhookDelivery(delivery *WebhookDelivery) error
+	ClaimDueWebhookDeliveries(now time.Time, limit int) ([]*WebhookDelivery, error)
+	MarkWebhookDelivered(id int64, attempts int) error
+	ScheduleWebhookRetry(id int64, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkWebhookDead(id int64, attempts int, lastError string) error
+	ReleaseInFlightWebhookDeliveries() (int64, error)
+	GetWebhookDelivery(id int64) (*WebhookDelivery, error)
+	GetWebhookDeliveries(filter *WebhookDeliveryFilter) ([]*WebhookDelivery, error)
+	ReplayWebhookDelivery(id int64) error
+	PurgeWebhookDeliveries(status string) (int64, error)
+
+	// Scheduled message operations
+	StoreScheduledMessage(message *ScheduledMessage) error
+	GetScheduledMessage(id string) (*ScheduledMessage, error)
+	GetScheduledMessages(filter *ScheduledMessageFilter) ([]*ScheduledMessage, error)
+	ClaimDueScheduledMessages(now time.Time, limit int) ([]*ScheduledMessage, error)
+	RescheduleScheduledMessage(id string, sendAt time.Time) error
+	CancelScheduledMessage(id string) error
+	MarkScheduledMessageSent(id string, attempts int, messageID string) error
+	RetryScheduledMessage(id string, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkScheduledMessageFailed(id string, attempts int, lastError string) error
+	ReleaseInFlightScheduledMessages() (int64, error)
+
+	// Campaign operations
+	StoreCampaign(campaign *Campaign, recipients []*CampaignRecipient) error
+	GetCampaign(id string) (*Campaign, error)
+	GetCampaigns(filter *CampaignFilter) ([]*Campaign, error)
+	UpdateCampaignStatus(id string, status string, from ...string) error
+	GetCampaignRecipients(filter *CampaignRecipientFilter) ([]*CampaignRecipient, error)
+	CountCampaignRecipients(campaignID string) (map[string]int, error)
+	MarkCampaignRecipientSending(id int64) error
+	MarkCampaignRecipientSent(id int64, messageID string) error
+	MarkCampaignRecipientFailed(id int64, reason string) error
+	RequeueCampaignRecipient(id int64, reason string) error
+	FlagCampaignRecipientForReview(id int64, reason string) error
+	FlagInterruptedCampaignRecipients(campaignID string, reason string) (int64, error)
+	UpdateCampaignRecipientReceipt(messageIDs []string, status string, at time.Time) (int64, error)
+
+	// Auto-reply rule operations
+	StoreAutoReplyRule(rule *AutoReplyRule) error
+	GetAutoReplyRule(id string) (*AutoReplyRule, error)
+	GetAutoReplyRules() ([]*AutoReplyRule, error)
+	DeleteAutoReplyRule(id string) error
+	ReplaceAutoReplyRules(rules []*AutoReplyRule) error
+
+	// API key operations
+	StoreAPIKey(key *APIKey) error
+	GetAPIKey(id string) (*APIKey, error)
+	GetAPIKeyByHash(keyHash string) (*APIKey, error)
+	GetAPIKeys() ([]*APIKey, error)
+	CountAPIKeys() (int64, error)
+	TouchAPIKey(id string, usedAt time.Time) error
+	DeleteAPIKey(id string) error
+
+	// Audit log operations
+	StoreAuditLog(entry *AuditLog) error
+	GetAuditLogs(filter *AuditLogFilter) ([]*AuditLog, error)
+
 	// Cleanup operations
 	TruncateAllChats() error
 	TruncateAllDataWithLogging(logPrefix string) error
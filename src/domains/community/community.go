@@ -0,0 +1,66 @@
+package community
+
+import "context"
+
+type ICommunityUsecase interface {
+	CreateCommunity(ctx context.Context, request CreateCommunityRequest) (response CreateCommunityResponse, err error)
+	CommunityInfo(ctx context.Context, request CommunityRequest) (response CommunityInfo, err error)
+	ListSubGroups(ctx context.Context, request CommunityRequest) (response []SubGroup, err error)
+	LinkGroup(ctx context.Context, request LinkGroupRequest) (err error)
+	UnlinkGroup(ctx context.Context, request LinkGroupRequest) (err error)
+	ListParticipants(ctx context.Context, request CommunityRequest) (response CommunityParticipantsResponse, err error)
+	SendAnnouncement(ctx context.Context, request AnnouncementRequest) (response AnnouncementResponse, err error)
+}
+
+// CreateCommunityRequest creates a community, WhatsApp creates its announcement group along with it
+type CreateCommunityRequest struct {
+	Name         string   `json:"name" form:"name"`
+	Description  string   `json:"description" form:"description"`
+	Participants []string `json:"participants" form:"participants"`
+}
+
+type CreateCommunityResponse struct {
+	CommunityID string `json:"community_id"`
+	Name        string `json:"name"`
+}
+
+type CommunityRequest struct {
+	CommunityID string `json:"community_id" query:"community_id"`
+}
+
+type LinkGroupRequest struct {
+	CommunityID string `json:"community_id" form:"community_id"`
+	GroupID     string `json:"group_id" form:"group_id"`
+}
+
+// AnnouncementRequest sends a text message to the announcement group of a community
+type AnnouncementRequest struct {
+	CommunityID string `json:"community_id" form:"community_id"`
+	Message     string `json:"message" form:"message"`
+}
+
+type AnnouncementResponse struct {
+	GroupID   string `json:"group_id"`
+	MessageID string `json:"message_id"`
+	Status    string `json:"status"`
+}
+
+// SubGroup is a group linked to a community, IsAnnouncement marks the announcement group every member is in
+type SubGroup struct {
+	GroupID        string `json:"group_id"`
+	Name           string `json:"name"`
+	IsAnnouncement bool   `json:"is_announcement"`
+}
+
+type CommunityInfo struct {
+	CommunityID         string     `json:"community_id"`
+	Name                string     `json:"name"`
+	Description         string     `json:"description"`
+	AnnouncementGroupID string     `json:"announcement_group_id,omitempty"`
+	SubGroups           []SubGroup `json:"sub_groups"`
+}
+
+type CommunityParticipantsResponse struct {
+	CommunityID  string   `json:"community_id"`
+	Participants []string `json:"participants"`
+}
@@ -0,0 +1,29 @@
+package device
+
+import "context"
+
+type IDeviceUsecase interface {
+	ListDevices(ctx context.Context) (response []DeviceInfo, err error)
+	GetDevice(ctx context.Context, deviceID string) (response DeviceInfo, err error)
+	AddDevice(ctx context.Context, request AddDeviceRequest) (response DeviceInfo, err error)
+	UpdateWebhooks(ctx context.Context, request UpdateWebhooksRequest) (response DeviceInfo, err error)
+	RemoveDevice(ctx context.Context, deviceID string) (err error)
+}
+
+type DeviceInfo struct {
+	DeviceID    string   `json:"device_id"`
+	JID         string   `json:"jid"`
+	IsConnected bool     `json:"is_connected"`
+	IsLoggedIn  bool     `json:"is_logged_in"`
+	Webhooks    []string `json:"webhooks"`
+}
+
+type AddDeviceRequest struct {
+	DeviceID string   `json:"device_id" form:"device_id"`
+	Webhooks []string `json:"webhooks" form:"webhooks"`
+}
+
+type UpdateWebhooksRequest struct {
+	DeviceID string   `json:"device_id" form:"device_id"`
+	Webhooks []string `json:"webhooks" form:"webhooks"`
+}
@@ -2,9 +2,11 @@ package group
 
 import (
 	"mime/multipart"
+	"strings"
 	"time"
 
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 )
 
 // NOTE: IGroupUsecase is now defined in interfaces.go with proper segregation
@@ -53,6 +55,59 @@ type GetGroupParticipantsResponse struct {
 	Participants []GroupParticipant `json:"participants"`
 }
 
+// Actions and results of a participant reconcile
+const (
+	ReconcileAdd     = "add"
+	ReconcileRemove  = "remove"
+	ReconcilePromote = "promote"
+	ReconcileDemote  = "demote"
+	ReconcileInvite  = "invite"
+
+	ReconcileStatusPlanned = "planned"
+	ReconcileStatusSuccess = "success"
+	ReconcileStatusInvited = "invited"
+	ReconcileStatusSkipped = "skipped"
+	ReconcileStatusError   = "error"
+)
+
+// ReconcileParticipantsRequest is the desired roster of a group. Participants lists every member that should be
+// in the group, Admins the members that should be admins; admins do not have to be repeated in Participants.
+// Changes are applied in batches of BatchSize with a pause of BatchDelaySeconds between batches.
+type ReconcileParticipantsRequest struct {
+	GroupID           string   `json:"group_id" form:"group_id"`
+	Participants      []string `json:"participants" form:"participants"`
+	Admins            []string `json:"admins" form:"admins"`
+	DryRun            bool     `json:"dry_run" form:"dry_run"`
+	BatchSize         int      `json:"batch_size" form:"batch_size"`
+	BatchDelaySeconds *int     `json:"batch_delay_seconds" form:"batch_delay_seconds"`
+	InviteMessage     string   `json:"invite_message" form:"invite_message"`
+}
+
+// ReconcilePlan lists the participants each action applies to
+type ReconcilePlan struct {
+	Add       []string `json:"add"`
+	Remove    []string `json:"remove"`
+	Promote   []string `json:"promote"`
+	Demote    []string `json:"demote"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// ReconcileResult is the outcome of an action for a participant, participants whose privacy settings block
+// being added get an invite link instead
+type ReconcileResult struct {
+	Participant string `json:"participant"`
+	Action      string `json:"action"`
+	Status      string `json:"status"`
+	Message     string `json:"message,omitempty"`
+}
+
+type ReconcileParticipantsResponse struct {
+	GroupID string            `json:"group_id"`
+	DryRun  bool              `json:"dry_run"`
+	Plan    ReconcilePlan     `json:"plan"`
+	Results []ReconcileResult `json:"results"`
+}
+
 type GetGroupRequestParticipantsRequest struct {
 	GroupID string `json:"group_id" query:"group_id"`
 }
@@ -131,3 +186,68 @@ type GetGroupInviteLinkResponse struct {
 type GroupInfoResponse struct {
 	Data any `json:"data"`
 }
+
+// GroupInfoData is the group info WhatsApp returns with the community metadata of the group
+type GroupInfoData struct {
+	types.GroupInfo
+	Community *GroupCommunity `json:"Community,omitempty"`
+}
+
+// GroupCommunity is set for communities and for groups linked to one. CommunityID is the parent community of
+// linked groups, SubGroups the linked groups of a community.
+type GroupCommunity struct {
+	IsCommunity         bool                 `json:"is_community"`
+	CommunityID         string               `json:"community_id,omitempty"`
+	IsAnnouncementGroup bool                 `json:"is_announcement_group"`
+	SubGroups           []GroupCommunityLink `json:"sub_groups,omitempty"`
+}
+
+type GroupCommunityLink struct {
+	GroupID        string `json:"group_id"`
+	Name           string `json:"name"`
+	IsAnnouncement bool   `json:"is_announcement"`
+}
+
+// GroupHistoryRequest filters the recorded changes of a group. Type is a comma-separated list of event types,
+// Actor and Participant match the account that made the change and the participant it was made to.
+type GroupHistoryRequest struct {
+	GroupID     string  `json:"group_id" query:"group_id"`
+	Type        string  `json:"type" query:"type"`
+	Actor       string  `json:"actor" query:"actor"`
+	Participant string  `json:"participant" query:"participant"`
+	StartTime   *string `json:"start_time" query:"start_time"`
+	EndTime     *string `json:"end_time" query:"end_time"`
+	Limit       int     `json:"limit" query:"limit"`
+	Offset      int     `json:"offset" query:"offset"`
+}
+
+// SplitGroupEventTypes returns the event types of the comma-separated type filter
+func SplitGroupEventTypes(value string) []string {
+	var eventTypes []string
+	for _, eventType := range strings.Split(value, ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	return eventTypes
+}
+
+type GroupHistoryResponse struct {
+	GroupID string       `json:"group_id"`
+	Data    []GroupEvent `json:"data"`
+	Total   int64        `json:"total"`
+	Limit   int          `json:"limit"`
+	Offset  int          `json:"offset"`
+}
+
+// GroupEvent is a recorded change of a group. TargetJID is the participant of membership changes and
+// the linked group of link changes; Value holds the new setting.
+type GroupEvent struct {
+	ID        int64     `json:"id"`
+	GroupJID  string    `json:"group_jid"`
+	Type      string    `json:"type"`
+	ActorJID  string    `json:"actor_jid,omitempty"`
+	TargetJID string    `json:"target_jid,omitempty"`
+	Value     string    `json:"value,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
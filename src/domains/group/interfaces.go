@@ -20,6 +20,7 @@ type IGroupParticipants interface {
 	GetGroupParticipants(ctx context.Context, request GetGroupParticipantsRequest) (response GetGroupParticipantsResponse, err error)
 	GetGroupRequestParticipants(ctx context.Context, request GetGroupRequestParticipantsRequest) (result []GetGroupRequestParticipantsResponse, err error)
 	ManageGroupRequestParticipants(ctx context.Context, request GroupRequestParticipantsRequest) (result []ParticipantStatus, err error)
+	ReconcileParticipants(ctx context.Context, request ReconcileParticipantsRequest) (response ReconcileParticipantsResponse, err error)
 }
 
 // IGroupSettings handles group settings operations
@@ -31,9 +32,15 @@ type IGroupSettings interface {
 	SetGroupTopic(ctx context.Context, request SetGroupTopicRequest) (err error)
 }
 
+// IGroupHistory handles the recorded membership and settings changes of groups
+type IGroupHistory interface {
+	GroupHistory(ctx context.Context, request GroupHistoryRequest) (response GroupHistoryResponse, err error)
+}
+
 // IGroupUsecase combines all group interfaces for backward compatibility
 type IGroupUsecase interface {
 	IGroupManagement
 	IGroupParticipants
 	IGroupSettings
+	IGroupHistory
 }
@@ -17,6 +17,7 @@ type IMessageManagement interface {
 	DeleteMessage(ctx context.Context, request DeleteRequest) (err error)
 	StarMessage(ctx context.Context, request StarRequest) (err error)
 	DownloadMedia(ctx context.Context, request DownloadMediaRequest) (response DownloadMediaResponse, err error)
+	GetMessageStatus(ctx context.Context, request MessageStatusRequest) (response MessageStatusResponse, err error)
 }
 
 // IMessageUsecase combines all message interfaces
@@ -1,5 +1,7 @@
 package message
 
+import "time"
+
 type GenericResponse struct {
 	MessageID string `json:"message_id"`
 	Status    string `json:"status"`
@@ -51,3 +53,27 @@ type DownloadMediaResponse struct {
 	FilePath  string `json:"file_path"`
 	FileSize  int64  `json:"file_size"`
 }
+
+type MessageStatusRequest struct {
+	MessageID string `json:"message_id" uri:"message_id"`
+}
+
+// MessageStatusResponse is the delivery state of an outgoing message. Status is the furthest state any
+// recipient reached: sent, server_ack, delivered, read or played.
+type MessageStatusResponse struct {
+	MessageID    string              `json:"message_id"`
+	ChatJID      string              `json:"chat_jid"`
+	IsFromMe     bool                `json:"is_from_me"`
+	Status       string              `json:"status"`
+	ServerAckAt  *time.Time          `json:"server_ack_at,omitempty"`
+	Participants []ParticipantStatus `json:"participants"`
+}
+
+// ParticipantStatus is the state a message reached on the devices of one recipient
+type ParticipantStatus struct {
+	JID         string     `json:"jid"`
+	Status      string     `json:"status"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+	PlayedAt    *time.Time `json:"played_at,omitempty"`
+}
@@ -1,11 +1,89 @@
 package newsletter
 
-import "context"
+import (
+	"context"
+	"mime/multipart"
+
+	"go.mau.fi/whatsmeow/types"
+)
 
 type INewsletterUsecase interface {
+	Create(ctx context.Context, request CreateRequest) (response *types.NewsletterMetadata, err error)
+	Update(ctx context.Context, request UpdateRequest) (response *types.NewsletterMetadata, err error)
+	Follow(ctx context.Context, request FollowRequest) (response *types.NewsletterMetadata, err error)
 	Unfollow(ctx context.Context, request UnfollowRequest) (err error)
+	Info(ctx context.Context, request InfoRequest) (response *types.NewsletterMetadata, err error)
+	Messages(ctx context.Context, request MessagesRequest) (response MessagesResponse, err error)
+	Publish(ctx context.Context, request PublishRequest) (response PublishResponse, err error)
+	MarkViewed(ctx context.Context, request MarkViewedRequest) (err error)
+}
+
+type CreateRequest struct {
+	Name        string                `json:"name" form:"name"`
+	Description string                `json:"description" form:"description"`
+	Picture     *multipart.FileHeader `json:"picture" form:"picture"`
+}
+
+// UpdateRequest changes the fields that are set, empty fields keep their current value
+type UpdateRequest struct {
+	NewsletterID string                `json:"newsletter_id" form:"newsletter_id"`
+	Name         string                `json:"name" form:"name"`
+	Description  string                `json:"description" form:"description"`
+	Picture      *multipart.FileHeader `json:"picture" form:"picture"`
+}
+
+// FollowRequest takes a channel invite link, e.g. https://whatsapp.com/channel/0029Va..., or only its code
+type FollowRequest struct {
+	Invite string `json:"invite" form:"invite"`
 }
 
 type UnfollowRequest struct {
 	NewsletterID string `json:"newsletter_id" form:"newsletter_id"`
 }
+
+type InfoRequest struct {
+	NewsletterID string `json:"newsletter_id" query:"newsletter_id"`
+}
+
+type MessagesRequest struct {
+	NewsletterID string `json:"newsletter_id" query:"newsletter_id"`
+	Limit        int    `json:"limit" query:"limit"`
+	Before       int    `json:"before" query:"before"`
+}
+
+type MessagesResponse struct {
+	NewsletterID string    `json:"newsletter_id"`
+	Data         []Message `json:"data"`
+}
+
+// Message is a channel post, ServerID is the ID used to page, react and mark posts viewed
+type Message struct {
+	ServerID       int            `json:"server_id"`
+	MessageID      string         `json:"message_id"`
+	Type           string         `json:"type"`
+	Text           string         `json:"text"`
+	Timestamp      string         `json:"timestamp"`
+	ViewsCount     int            `json:"views_count"`
+	ReactionCounts map[string]int `json:"reaction_counts"`
+}
+
+// PublishRequest posts Message as text, or as the caption of an image or video when one is attached
+type PublishRequest struct {
+	NewsletterID string                `json:"newsletter_id" form:"newsletter_id"`
+	Message      string                `json:"message" form:"message"`
+	Image        *multipart.FileHeader `json:"image" form:"image"`
+	ImageURL     *string               `json:"image_url" form:"image_url"`
+	Video        *multipart.FileHeader `json:"video" form:"video"`
+	VideoURL     *string               `json:"video_url" form:"video_url"`
+}
+
+type PublishResponse struct {
+	NewsletterID string `json:"newsletter_id"`
+	MessageID    string `json:"message_id"`
+	Status       string `json:"status"`
+}
+
+type MarkViewedRequest struct {
+	NewsletterID string `json:"newsletter_id" form:"newsletter_id"`
+	ServerIDs    []int  `json:"server_ids" form:"server_ids"`
+}
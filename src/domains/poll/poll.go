@@ -0,0 +1,40 @@
+package poll
+
+import (
+	"context"
+	"time"
+)
+
+type IPollUsecase interface {
+	GetPollResults(ctx context.Context, request PollResultsRequest) (response PollResultsResponse, err error)
+}
+
+type PollResultsRequest struct {
+	MessageID string `json:"message_id" uri:"message_id"`
+}
+
+// PollResultsResponse is the tally of a poll, counting the current vote of every voter
+type PollResultsResponse struct {
+	MessageID       string         `json:"message_id"`
+	ChatJID         string         `json:"chat_jid"`
+	CreatorJID      string         `json:"creator_jid"`
+	Question        string         `json:"question"`
+	SelectableCount int            `json:"selectable_count"`
+	CreatedAt       time.Time      `json:"created_at"`
+	TotalVoters     int            `json:"total_voters"`
+	Options         []OptionResult `json:"options"`
+	Voters          []Voter        `json:"voters"`
+}
+
+type OptionResult struct {
+	Name   string   `json:"name"`
+	Votes  int      `json:"votes"`
+	Voters []string `json:"voters"`
+}
+
+// Voter is the current vote of one voter, voters that took back their vote have no options
+type Voter struct {
+	JID     string    `json:"jid"`
+	Options []string  `json:"options"`
+	VotedAt time.Time `json:"voted_at"`
+}
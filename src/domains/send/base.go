@@ -4,4 +4,12 @@ type BaseRequest struct {
 	Phone       string `json:"phone" form:"phone"`
 	Duration    *int   `json:"duration,omitempty" form:"duration"`
 	IsForwarded bool   `json:"is_forwarded,omitempty" form:"is_forwarded"`
+	// SendAt (RFC3339) or DelaySeconds schedule the message instead of sending it right away
+	SendAt       *string `json:"send_at,omitempty" form:"send_at"`
+	DelaySeconds int     `json:"delay_seconds,omitempty" form:"delay_seconds"`
+}
+
+// IsScheduled reports whether the request asks for a later delivery
+func (r BaseRequest) IsScheduled() bool {
+	return (r.SendAt != nil && *r.SendAt != "") || r.DelaySeconds > 0
 }
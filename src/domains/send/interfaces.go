@@ -32,10 +32,19 @@ type IPresenceSender interface {
 	SendChatPresence(ctx context.Context, request ChatPresenceRequest) (response GenericResponse, err error)
 }
 
+// IScheduledSender manages messages waiting to be sent later
+type IScheduledSender interface {
+	ListScheduled(ctx context.Context, request ListScheduledRequest) (response ListScheduledResponse, err error)
+	GetScheduled(ctx context.Context, scheduleID string) (response ScheduledMessage, err error)
+	Reschedule(ctx context.Context, request RescheduleRequest) (response ScheduledMessage, err error)
+	CancelScheduled(ctx context.Context, scheduleID string) (err error)
+}
+
 // ISendUsecase combines all sender interfaces for backward compatibility
 type ISendUsecase interface {
 	ITextSender
 	IMediaSender
 	IInteractionSender
 	IPresenceSender
+	IScheduledSender
 }
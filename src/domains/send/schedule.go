@@ -0,0 +1,52 @@
+package send
+
+import "time"
+
+// Message types a scheduled message can be sent as
+const (
+	ScheduledTypeMessage      = "message"
+	ScheduledTypeImage        = "image"
+	ScheduledTypeFile         = "file"
+	ScheduledTypeVideo        = "video"
+	ScheduledTypeAudio        = "audio"
+	ScheduledTypeSticker      = "sticker"
+	ScheduledTypeContact      = "contact"
+	ScheduledTypeLink         = "link"
+	ScheduledTypeLocation     = "location"
+	ScheduledTypePoll         = "poll"
+	ScheduledTypeChatPresence = "chat_presence"
+)
+
+type ScheduledMessage struct {
+	ID        string     `json:"id"`
+	DeviceID  string     `json:"device_id,omitempty"`
+	Type      string     `json:"type"`
+	Phone     string     `json:"phone"`
+	SendAt    time.Time  `json:"send_at"`
+	Status    string     `json:"status"`
+	Attempts  int        `json:"attempts"`
+	LastError string     `json:"last_error,omitempty"`
+	MessageID string     `json:"message_id,omitempty"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type ListScheduledRequest struct {
+	Status string `json:"status" query:"status"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+type ListScheduledResponse struct {
+	Data   []ScheduledMessage `json:"data"`
+	Limit  int                `json:"limit"`
+	Offset int                `json:"offset"`
+}
+
+// RescheduleRequest moves a pending scheduled message, either to SendAt (RFC3339) or DelaySeconds from now
+type RescheduleRequest struct {
+	ScheduleID   string  `json:"schedule_id" form:"schedule_id"`
+	SendAt       *string `json:"send_at" form:"send_at"`
+	DelaySeconds int     `json:"delay_seconds" form:"delay_seconds"`
+}
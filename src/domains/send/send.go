@@ -1,6 +1,10 @@
 package send
 
+import "time"
+
 type GenericResponse struct {
-	MessageID string `json:"message_id"`
-	Status    string `json:"status"`
+	MessageID  string     `json:"message_id"`
+	Status     string     `json:"status"`
+	ScheduleID string     `json:"schedule_id,omitempty"`
+	SendAt     *time.Time `json:"send_at,omitempty"`
 }
@@ -30,10 +30,18 @@ type IUserPrivacy interface {
 	MyPrivacySetting(ctx context.Context) (response MyPrivacySettingResponse, err error)
 }
 
+// IUserPresence handles presence subscriptions and the last known presence of contacts
+type IUserPresence interface {
+	SubscribePresence(ctx context.Context, request PresenceRequest) (err error)
+	UnsubscribePresence(ctx context.Context, request PresenceRequest) (err error)
+	Presence(ctx context.Context, request PresenceRequest) (response PresenceResponse, err error)
+}
+
 // IUserUsecase combines all user interfaces for backward compatibility
 type IUserUsecase interface {
 	IUserInfo
 	IUserProfile
 	IUserListing
 	IUserPrivacy
+	IUserPresence
 }
@@ -0,0 +1,24 @@
+package user
+
+import "time"
+
+// PresenceRequest selects a contact. Phone is optional when querying presence, all known contacts are returned without it.
+type PresenceRequest struct {
+	Phone string `json:"phone" form:"phone" query:"phone"`
+}
+
+type PresenceResponse struct {
+	Data []Presence `json:"data"`
+}
+
+// Presence is the last known presence of a contact, as reported since the server started
+type Presence struct {
+	JID         string     `json:"jid"`
+	Subscribed  bool       `json:"subscribed"`
+	Online      bool       `json:"online"`
+	LastSeen    *time.Time `json:"last_seen,omitempty"`
+	ChatState   string     `json:"chat_state,omitempty"`
+	ChatJID     string     `json:"chat_jid,omitempty"`
+	ChatStateAt *time.Time `json:"chat_state_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
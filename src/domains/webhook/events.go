@@ -0,0 +1,79 @@
+package webhook
+
+// The payloads below are sent as the payload field of the typed message events, the JSON Schema of each
+// event is in docs/webhook-schemas.
+
+// Reaction actions reported by message.reaction events
+const (
+	ReactionAdded   = "added"
+	ReactionRemoved = "removed"
+)
+
+// MessageEditedPayload is the payload of message.edited, OldText is empty when the original message was not stored
+type MessageEditedPayload struct {
+	MessageID string `json:"message_id"`
+	ChatID    string `json:"chat_id"`
+	SenderID  string `json:"sender_id"`
+	IsFromMe  bool   `json:"is_from_me"`
+	OldText   string `json:"old_text"`
+	NewText   string `json:"new_text"`
+	EditedAt  string `json:"edited_at"`
+}
+
+// MessageRevokedPayload is the payload of message.revoked, the original fields are only set when the message was stored
+type MessageRevokedPayload struct {
+	MessageID         string `json:"message_id"`
+	ChatID            string `json:"chat_id"`
+	SenderID          string `json:"sender_id"`
+	RevokedBy         string `json:"revoked_by"`
+	IsFromMe          bool   `json:"is_from_me"`
+	OriginalText      string `json:"original_text,omitempty"`
+	OriginalMediaType string `json:"original_media_type,omitempty"`
+	RevokedAt         string `json:"revoked_at"`
+}
+
+// MessageReactionPayload is the payload of message.reaction, Reaction is empty when it was removed
+type MessageReactionPayload struct {
+	MessageID string `json:"message_id"`
+	ChatID    string `json:"chat_id"`
+	SenderID  string `json:"sender_id"`
+	IsFromMe  bool   `json:"is_from_me"`
+	Reaction  string `json:"reaction"`
+	Action    string `json:"action"`
+	ReactedAt string `json:"reacted_at"`
+}
+
+// PollVotePayload is the payload of poll.vote. SelectedOptions holds the option names, or the hex SHA-256 hash
+// of options of polls that were not stored; an empty list means the voter took back their vote.
+type PollVotePayload struct {
+	PollID               string   `json:"poll_id"`
+	ChatID               string   `json:"chat_id"`
+	VoterID              string   `json:"voter_id"`
+	IsFromMe             bool     `json:"is_from_me"`
+	SelectedOptions      []string `json:"selected_options"`
+	SelectedOptionHashes []string `json:"selected_option_hashes"`
+	VotedAt              string   `json:"voted_at"`
+}
+
+// MediaDownloadedPayload is the payload of message.media_downloaded
+type MediaDownloadedPayload struct {
+	MessageID string `json:"message_id"`
+	ChatID    string `json:"chat_id"`
+	SenderID  string `json:"sender_id"`
+	IsFromMe  bool   `json:"is_from_me"`
+	MediaType string `json:"media_type"`
+	Path      string `json:"path"`
+	MimeType  string `json:"mime_type"`
+	Caption   string `json:"caption,omitempty"`
+}
+
+// GroupSettingsPayload is the payload of group.settings. Value is the new setting, e.g. the name, "true"/"false"
+// for locked, announce and membership_approval or the disappearing timer in seconds for ephemeral.
+type GroupSettingsPayload struct {
+	ChatID    string `json:"chat_id"`
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+	ActorID   string `json:"actor_id"`
+	TargetID  string `json:"target_id,omitempty"`
+	ChangedAt string `json:"changed_at"`
+}
@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Event types that webhook subscriptions can filter on
+const (
+	EventMessage           = "message"
+	EventMessageAck        = "message.ack"
+	EventMessageDeleted    = "message.deleted"
+	EventGroupParticipants = "group.participants"
+	EventGroupSettings     = "group.settings"
+	EventScheduledSent     = "scheduled.sent"
+	EventScheduledFailed   = "scheduled.failed"
+	EventAutoReplyMatched  = "auto_reply.matched"
+	EventCallOffer         = "call.offer"
+	EventCallEnded         = "call.ended"
+	EventPresenceUpdate    = "presence.update"
+	EventChatTyping        = "chat.typing"
+
+	EventMessageEdited          = "message.edited"
+	EventMessageRevoked         = "message.revoked"
+	EventMessageReaction        = "message.reaction"
+	EventPollVote               = "poll.vote"
+	EventMessageMediaDownloaded = "message.media_downloaded"
+)
+
+// EventTypes lists every event type delivered to webhooks
+var EventTypes = []string{
+	EventMessage,
+	EventMessageAck,
+	EventMessageDeleted,
+	EventGroupParticipants,
+	EventGroupSettings,
+	EventScheduledSent,
+	EventScheduledFailed,
+	EventAutoReplyMatched,
+	EventCallOffer,
+	EventCallEnded,
+	EventPresenceUpdate,
+	EventChatTyping,
+	EventMessageEdited,
+	EventMessageRevoked,
+	EventMessageReaction,
+	EventPollVote,
+	EventMessageMediaDownloaded,
+}
+
+type IWebhookUsecase interface {
+	IWebhookSubscriptionUsecase
+	IWebhookDeliveryUsecase
+}
+
+// IWebhookSubscriptionUsecase manages webhook endpoints and their event filters
+type IWebhookSubscriptionUsecase interface {
+	ListWebhooks(ctx context.Context) (response []Webhook, err error)
+	GetWebhook(ctx context.Context, webhookID string) (response Webhook, err error)
+	CreateWebhook(ctx context.Context, request WebhookRequest) (response Webhook, err error)
+	UpdateWebhook(ctx context.Context, request WebhookRequest) (response Webhook, err error)
+	DeleteWebhook(ctx context.Context, webhookID string) (err error)
+}
+
+// IWebhookDeliveryUsecase manages the durable webhook delivery queue
+type IWebhookDeliveryUsecase interface {
+	ListDeliveries(ctx context.Context, request ListDeliveriesRequest) (response ListDeliveriesResponse, err error)
+	GetDelivery(ctx context.Context, deliveryID int64) (response Delivery, err error)
+	ReplayDelivery(ctx context.Context, deliveryID int64) (err error)
+	PurgeDeliveries(ctx context.Context, request PurgeDeliveriesRequest) (response PurgeDeliveriesResponse, err error)
+}
+
+type Webhook struct {
+	ID           string    `json:"id"`
+	URL          string    `json:"url"`
+	HasSecret    bool      `json:"has_secret"`
+	DeviceID     string    `json:"device_id,omitempty"`
+	Events       []string  `json:"events"`
+	AllowJIDs    []string  `json:"allow_jids"`
+	DenyJIDs     []string  `json:"deny_jids"`
+	SkipFromMe   bool      `json:"skip_from_me"`
+	ChatType     string    `json:"chat_type"`
+	IncludeMedia bool      `json:"include_media"`
+	Enabled      bool      `json:"enabled"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// WebhookRequest creates or replaces a webhook. Optional booleans keep their defaults
+// (include_media and enabled true, skip_from_me false) when omitted.
+type WebhookRequest struct {
+	ID           string   `json:"-" form:"-"`
+	URL          string   `json:"url" form:"url"`
+	Secret       string   `json:"secret" form:"secret"`
+	DeviceID     string   `json:"device_id" form:"device_id"`
+	Events       []string `json:"events" form:"events"`
+	AllowJIDs    []string `json:"allow_jids" form:"allow_jids"`
+	DenyJIDs     []string `json:"deny_jids" form:"deny_jids"`
+	SkipFromMe   bool     `json:"skip_from_me" form:"skip_from_me"`
+	ChatType     string   `json:"chat_type" form:"chat_type"`
+	IncludeMedia *bool    `json:"include_media" form:"include_media"`
+	Enabled      *bool    `json:"enabled" form:"enabled"`
+}
+
+type Delivery struct {
+	ID            int64           `json:"id"`
+	WebhookID     string          `json:"webhook_id,omitempty"`
+	DeviceID      string          `json:"device_id,omitempty"`
+	Event         string          `json:"event"`
+	URL           string          `json:"url"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	Payload       json.RawMessage `json:"payload,omitempty"`
+}
+
+type ListDeliveriesRequest struct {
+	Status string `json:"status" query:"status"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+type ListDeliveriesResponse struct {
+	Data   []Delivery `json:"data"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+}
+
+type PurgeDeliveriesRequest struct {
+	Status string `json:"status" query:"status"`
+}
+
+type PurgeDeliveriesResponse struct {
+	Status  string `json:"status"`
+	Deleted int64  `json:"deleted"`
+}
@@ -0,0 +1,532 @@
+package chatstorage
+
+import (
+	"context"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// instrumentedRepository records the duration of every chat storage operation in the metrics
+type instrumentedRepository struct {
+	repo domainChatStorage.IChatStorageRepository
+}
+
+// NewInstrumentedRepository wraps repo so its operations show up in the query latency metrics
+func NewInstrumentedRepository(repo domainChatStorage.IChatStorageRepository) domainChatStorage.IChatStorageRepository {
+	return &instrumentedRepository{repo: repo}
+}
+
+// Chat operations
+
+func (r *instrumentedRepository) CreateMessage(ctx context.Context, evt *events.Message) error {
+	defer metrics.ObserveStorageQuery("CreateMessage", time.Now())
+	return r.repo.CreateMessage(ctx, evt)
+}
+
+func (r *instrumentedRepository) StoreChat(chat *domainChatStorage.Chat) error {
+	defer metrics.ObserveStorageQuery("StoreChat", time.Now())
+	return r.repo.StoreChat(chat)
+}
+
+func (r *instrumentedRepository) GetChat(jid string) (*domainChatStorage.Chat, error) {
+	defer metrics.ObserveStorageQuery("GetChat", time.Now())
+	return r.repo.GetChat(jid)
+}
+
+func (r *instrumentedRepository) GetChats(filter *domainChatStorage.ChatFilter) ([]*domainChatStorage.Chat, error) {
+	defer metrics.ObserveStorageQuery("GetChats", time.Now())
+	return r.repo.GetChats(filter)
+}
+
+func (r *instrumentedRepository) DeleteChat(jid string) error {
+	defer metrics.ObserveStorageQuery("DeleteChat", time.Now())
+	return r.repo.DeleteChat(jid)
+}
+
+// Message operations
+
+func (r *instrumentedRepository) StoreMessage(message *domainChatStorage.Message) error {
+	defer metrics.ObserveStorageQuery("StoreMessage", time.Now())
+	return r.repo.StoreMessage(message)
+}
+
+func (r *instrumentedRepository) StoreMessagesBatch(messages []*domainChatStorage.Message) error {
+	defer metrics.ObserveStorageQuery("StoreMessagesBatch", time.Now())
+	return r.repo.StoreMessagesBatch(messages)
+}
+
+func (r *instrumentedRepository) GetMessageByID(id string) (*domainChatStorage.Message, error) {
+	defer metrics.ObserveStorageQuery("GetMessageByID", time.Now())
+	return r.repo.GetMessageByID(id)
+}
+
+func (r *instrumentedRepository) GetMessages(filter *domainChatStorage.MessageFilter) ([]*domainChatStorage.Message, error) {
+	defer metrics.ObserveStorageQuery("GetMessages", time.Now())
+	return r.repo.GetMessages(filter)
+}
+
+func (r *instrumentedRepository) SearchMessages(chatJID string, searchText string, limit int) ([]*domainChatStorage.Message, error) {
+	defer metrics.ObserveStorageQuery("SearchMessages", time.Now())
+	return r.repo.SearchMessages(chatJID, searchText, limit)
+}
+
+func (r *instrumentedRepository) SearchAllMessages(filter *domainChatStorage.MessageSearchFilter) ([]*domainChatStorage.MessageSearchResult, error) {
+	defer metrics.ObserveStorageQuery("SearchAllMessages", time.Now())
+	return r.repo.SearchAllMessages(filter)
+}
+
+func (r *instrumentedRepository) CountSearchMessages(filter *domainChatStorage.MessageSearchFilter) (int64, error) {
+	defer metrics.ObserveStorageQuery("CountSearchMessages", time.Now())
+	return r.repo.CountSearchMessages(filter)
+}
+
+func (r *instrumentedRepository) DeleteMessage(id string, chatJID string) error {
+	defer metrics.ObserveStorageQuery("DeleteMessage", time.Now())
+	return r.repo.DeleteMessage(id, chatJID)
+}
+
+func (r *instrumentedRepository) StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error {
+	defer metrics.ObserveStorageQuery("StoreSentMessageWithContext", time.Now())
+	return r.repo.StoreSentMessageWithContext(ctx, messageID, senderJID, recipientJID, content, timestamp)
+}
+
+func (r *instrumentedRepository) StoreMessageReceipts(receipts []*domainChatStorage.MessageReceipt) error {
+	defer metrics.ObserveStorageQuery("StoreMessageReceipts", time.Now())
+	return r.repo.StoreMessageReceipts(receipts)
+}
+
+func (r *instrumentedRepository) GetMessageReceipts(messageIDs []string) ([]*domainChatStorage.MessageReceipt, error) {
+	defer metrics.ObserveStorageQuery("GetMessageReceipts", time.Now())
+	return r.repo.GetMessageReceipts(messageIDs)
+}
+
+func (r *instrumentedRepository) StoreMessageEdit(edit *domainChatStorage.MessageEdit) error {
+	defer metrics.ObserveStorageQuery("StoreMessageEdit", time.Now())
+	return r.repo.StoreMessageEdit(edit)
+}
+
+func (r *instrumentedRepository) GetMessageEdits(messageIDs []string) ([]*domainChatStorage.MessageEdit, error) {
+	defer metrics.ObserveStorageQuery("GetMessageEdits", time.Now())
+	return r.repo.GetMessageEdits(messageIDs)
+}
+
+func (r *instrumentedRepository) RevokeMessage(id, chatJID string, revokedAt time.Time) error {
+	defer metrics.ObserveStorageQuery("RevokeMessage", time.Now())
+	return r.repo.RevokeMessage(id, chatJID, revokedAt)
+}
+
+func (r *instrumentedRepository) StoreMessageReaction(reaction *domainChatStorage.MessageReaction) error {
+	defer metrics.ObserveStorageQuery("StoreMessageReaction", time.Now())
+	return r.repo.StoreMessageReaction(reaction)
+}
+
+func (r *instrumentedRepository) GetMessageReactions(messageIDs []string) ([]*domainChatStorage.MessageReaction, error) {
+	defer metrics.ObserveStorageQuery("GetMessageReactions", time.Now())
+	return r.repo.GetMessageReactions(messageIDs)
+}
+
+func (r *instrumentedRepository) StoreGroupEvents(events []*domainChatStorage.GroupEvent) error {
+	defer metrics.ObserveStorageQuery("StoreGroupEvents", time.Now())
+	return r.repo.StoreGroupEvents(events)
+}
+
+func (r *instrumentedRepository) GetGroupEvents(filter *domainChatStorage.GroupEventFilter) ([]*domainChatStorage.GroupEvent, error) {
+	defer metrics.ObserveStorageQuery("GetGroupEvents", time.Now())
+	return r.repo.GetGroupEvents(filter)
+}
+
+func (r *instrumentedRepository) CountGroupEvents(filter *domainChatStorage.GroupEventFilter) (int64, error) {
+	defer metrics.ObserveStorageQuery("CountGroupEvents", time.Now())
+	return r.repo.CountGroupEvents(filter)
+}
+
+func (r *instrumentedRepository) StoreStreamEvent(event *domainChatStorage.StreamEvent) error {
+	defer metrics.ObserveStorageQuery("StoreStreamEvent", time.Now())
+	return r.repo.StoreStreamEvent(event)
+}
+
+func (r *instrumentedRepository) GetStreamEventsAfter(afterID int64, limit int) ([]*domainChatStorage.StreamEvent, error) {
+	defer metrics.ObserveStorageQuery("GetStreamEventsAfter", time.Now())
+	return r.repo.GetStreamEventsAfter(afterID, limit)
+}
+
+func (r *instrumentedRepository) GetLastStreamEventID() (int64, error) {
+	defer metrics.ObserveStorageQuery("GetLastStreamEventID", time.Now())
+	return r.repo.GetLastStreamEventID()
+}
+
+func (r *instrumentedRepository) TrimStreamEvents(upToID int64) error {
+	defer metrics.ObserveStorageQuery("TrimStreamEvents", time.Now())
+	return r.repo.TrimStreamEvents(upToID)
+}
+
+func (r *instrumentedRepository) StoreCall(call *domainChatStorage.Call) error {
+	defer metrics.ObserveStorageQuery("StoreCall", time.Now())
+	return r.repo.StoreCall(call)
+}
+
+func (r *instrumentedRepository) GetCall(id string) (*domainChatStorage.Call, error) {
+	defer metrics.ObserveStorageQuery("GetCall", time.Now())
+	return r.repo.GetCall(id)
+}
+
+func (r *instrumentedRepository) GetCalls(filter *domainChatStorage.CallFilter) ([]*domainChatStorage.Call, error) {
+	defer metrics.ObserveStorageQuery("GetCalls", time.Now())
+	return r.repo.GetCalls(filter)
+}
+
+func (r *instrumentedRepository) StorePoll(poll *domainChatStorage.Poll) error {
+	defer metrics.ObserveStorageQuery("StorePoll", time.Now())
+	return r.repo.StorePoll(poll)
+}
+
+func (r *instrumentedRepository) GetPoll(messageID string) (*domainChatStorage.Poll, error) {
+	defer metrics.ObserveStorageQuery("GetPoll", time.Now())
+	return r.repo.GetPoll(messageID)
+}
+
+func (r *instrumentedRepository) StorePollVote(vote *domainChatStorage.PollVote) error {
+	defer metrics.ObserveStorageQuery("StorePollVote", time.Now())
+	return r.repo.StorePollVote(vote)
+}
+
+func (r *instrumentedRepository) GetPollVotes(pollID string) ([]*domainChatStorage.PollVote, error) {
+	defer metrics.ObserveStorageQuery("GetPollVotes", time.Now())
+	return r.repo.GetPollVotes(pollID)
+}
+
+// Statistics
+
+func (r *instrumentedRepository) GetChatMessageCount(chatJID string) (int64, error) {
+	defer metrics.ObserveStorageQuery("GetChatMessageCount", time.Now())
+	return r.repo.GetChatMessageCount(chatJID)
+}
+
+func (r *instrumentedRepository) GetTotalMessageCount() (int64, error) {
+	defer metrics.ObserveStorageQuery("GetTotalMessageCount", time.Now())
+	return r.repo.GetTotalMessageCount()
+}
+
+func (r *instrumentedRepository) GetTotalChatCount() (int64, error) {
+	defer metrics.ObserveStorageQuery("GetTotalChatCount", time.Now())
+	return r.repo.GetTotalChatCount()
+}
+
+func (r *instrumentedRepository) GetChatNameWithPushName(jid types.JID, chatJID string, senderUser string, pushName string) string {
+	defer metrics.ObserveStorageQuery("GetChatNameWithPushName", time.Now())
+	return r.repo.GetChatNameWithPushName(jid, chatJID, senderUser, pushName)
+}
+
+func (r *instrumentedRepository) GetStorageStatistics() (int64, int64, error) {
+	defer metrics.ObserveStorageQuery("GetStorageStatistics", time.Now())
+	return r.repo.GetStorageStatistics()
+}
+
+// Device registry operations
+
+func (r *instrumentedRepository) StoreDeviceRecord(device *domainChatStorage.DeviceRecord) error {
+	defer metrics.ObserveStorageQuery("StoreDeviceRecord", time.Now())
+	return r.repo.StoreDeviceRecord(device)
+}
+
+func (r *instrumentedRepository) GetDeviceRecords() ([]*domainChatStorage.DeviceRecord, error) {
+	defer metrics.ObserveStorageQuery("GetDeviceRecords", time.Now())
+	return r.repo.GetDeviceRecords()
+}
+
+func (r *instrumentedRepository) DeleteDeviceRecord(id string) error {
+	defer metrics.ObserveStorageQuery("DeleteDeviceRecord", time.Now())
+	return r.repo.DeleteDeviceRecord(id)
+}
+
+// Webhook subscription operations
+
+func (r *instrumentedRepository) StoreWebhookSubscription(subscription *domainChatStorage.WebhookSubscription) error {
+	defer metrics.ObserveStorageQuery("StoreWebhookSubscription", time.Now())
+	return r.repo.StoreWebhookSubscription(subscription)
+}
+
+func (r *instrumentedRepository) GetWebhookSubscription(id string) (*domainChatStorage.WebhookSubscription, error) {
+	defer metrics.ObserveStorageQuery("GetWebhookSubscription", time.Now())
+	return r.repo.GetWebhookSubscription(id)
+}
+
+func (r *instrumentedRepository) GetWebhookSubscriptions() ([]*domainChatStorage.WebhookSubscription, error) {
+	defer metrics.ObserveStorageQuery("GetWebhookSubscriptions", time.Now())
+	return r.repo.GetWebhookSubscriptions()
+}
+
+func (r *instrumentedRepository) DeleteWebhookSubscription(id string) error {
+	defer metrics.ObserveStorageQuery("DeleteWebhookSubscription", time.Now())
+	return r.repo.DeleteWebhookSubscription(id)
+}
+
+// Webhook delivery queue operations
+
+func (r *instrumentedRepository) EnqueueWebhookDelivery(delivery *domainChatStorage.WebhookDelivery) error {
+	defer metrics.ObserveStorageQuery("EnqueueWebhookDelivery", time.Now())
+	return r.repo.EnqueueWebhookDelivery(delivery)
+}
+
+func (r *instrumentedRepository) ClaimDueWebhookDeliveries(now time.Time, limit int) ([]*domainChatStorage.WebhookDelivery, error) {
+	defer metrics.ObserveStorageQuery("ClaimDueWebhookDeliveries", time.Now())
+	return r.repo.ClaimDueWebhookDeliveries(now, limit)
+}
+
+func (r *instrumentedRepository) MarkWebhookDelivered(id int64, attempts int) error {
+	defer metrics.ObserveStorageQuery("MarkWebhookDelivered", time.Now())
+	return r.repo.MarkWebhookDelivered(id, attempts)
+}
+
+func (r *instrumentedRepository) ScheduleWebhookRetry(id int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	defer metrics.ObserveStorageQuery("ScheduleWebhookRetry", time.Now())
+	return r.repo.ScheduleWebhookRetry(id, attempts, nextAttemptAt, lastError)
+}
+
+func (r *instrumentedRepository) MarkWebhookDead(id int64, attempts int, lastError string) error {
+	defer metrics.ObserveStorageQuery("MarkWebhookDead", time.Now())
+	return r.repo.MarkWebhookDead(id, attempts, lastError)
+}
+
+func (r *instrumentedRepository) ReleaseInFlightWebhookDeliveries() (int64, error) {
+	defer metrics.ObserveStorageQuery("ReleaseInFlightWebhookDeliveries", time.Now())
+	return r.repo.ReleaseInFlightWebhookDeliveries()
+}
+
+func (r *instrumentedRepository) GetWebhookDelivery(id int64) (*domainChatStorage.WebhookDelivery, error) {
+	defer metrics.ObserveStorageQuery("GetWebhookDelivery", time.Now())
+	return r.repo.GetWebhookDelivery(id)
+}
+
+func (r *instrumentedRepository) GetWebhookDeliveries(filter *domainChatStorage.WebhookDeliveryFilter) ([]*domainChatStorage.WebhookDelivery, error) {
+	defer metrics.ObserveStorageQuery("GetWebhookDeliveries", time.Now())
+	return r.repo.GetWebhookDeliveries(filter)
+}
+
+func (r *instrumentedRepository) ReplayWebhookDelivery(id int64) error {
+	defer metrics.ObserveStorageQuery("ReplayWebhookDelivery", time.Now())
+	return r.repo.ReplayWebhookDelivery(id)
+}
+
+func (r *instrumentedRepository) PurgeWebhookDeliveries(status string) (int64, error) {
+	defer metrics.ObserveStorageQuery("PurgeWebhookDeliveries", time.Now())
+	return r.repo.PurgeWebhookDeliveries(status)
+}
+
+// Scheduled message operations
+
+func (r *instrumentedRepository) StoreScheduledMessage(message *domainChatStorage.ScheduledMessage) error {
+	defer metrics.ObserveStorageQuery("StoreScheduledMessage", time.Now())
+	return r.repo.StoreScheduledMessage(message)
+}
+
+func (r *instrumentedRepository) GetScheduledMessage(id string) (*domainChatStorage.ScheduledMessage, error) {
+	defer metrics.ObserveStorageQuery("GetScheduledMessage", time.Now())
+	return r.repo.GetScheduledMessage(id)
+}
+
+func (r *instrumentedRepository) GetScheduledMessages(filter *domainChatStorage.ScheduledMessageFilter) ([]*domainChatStorage.ScheduledMessage, error) {
+	defer metrics.ObserveStorageQuery("GetScheduledMessages", time.Now())
+	return r.repo.GetScheduledMessages(filter)
+}
+
+func (r *instrumentedRepository) ClaimDueScheduledMessages(now time.Time, limit int) ([]*domainChatStorage.ScheduledMessage, error) {
+	defer metrics.ObserveStorageQuery("ClaimDueScheduledMessages", time.Now())
+	return r.repo.ClaimDueScheduledMessages(now, limit)
+}
+
+func (r *instrumentedRepository) RescheduleScheduledMessage(id string, sendAt time.Time) error {
+	defer metrics.ObserveStorageQuery("RescheduleScheduledMessage", time.Now())
+	return r.repo.RescheduleScheduledMessage(id, sendAt)
+}
+
+func (r *instrumentedRepository) CancelScheduledMessage(id string) error {
+	defer metrics.ObserveStorageQuery("CancelScheduledMessage", time.Now())
+	return r.repo.CancelScheduledMessage(id)
+}
+
+func (r *instrumentedRepository) MarkScheduledMessageSent(id string, attempts int, messageID string) error {
+	defer metrics.ObserveStorageQuery("MarkScheduledMessageSent", time.Now())
+	return r.repo.MarkScheduledMessageSent(id, attempts, messageID)
+}
+
+func (r *instrumentedRepository) RetryScheduledMessage(id string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	defer metrics.ObserveStorageQuery("RetryScheduledMessage", time.Now())
+	return r.repo.RetryScheduledMessage(id, attempts, nextAttemptAt, lastError)
+}
+
+func (r *instrumentedRepository) MarkScheduledMessageFailed(id string, attempts int, lastError string) error {
+	defer metrics.ObserveStorageQuery("MarkScheduledMessageFailed", time.Now())
+	return r.repo.MarkScheduledMessageFailed(id, attempts, lastError)
+}
+
+func (r *instrumentedRepository) ReleaseInFlightScheduledMessages() (int64, error) {
+	defer metrics.ObserveStorageQuery("ReleaseInFlightScheduledMessages", time.Now())
+	return r.repo.ReleaseInFlightScheduledMessages()
+}
+
+// Campaign operations
+
+func (r *instrumentedRepository) StoreCampaign(campaign *domainChatStorage.Campaign, recipients []*domainChatStorage.CampaignRecipient) error {
+	defer metrics.ObserveStorageQuery("StoreCampaign", time.Now())
+	return r.repo.StoreCampaign(campaign, recipients)
+}
+
+func (r *instrumentedRepository) GetCampaign(id string) (*domainChatStorage.Campaign, error) {
+	defer metrics.ObserveStorageQuery("GetCampaign", time.Now())
+	return r.repo.GetCampaign(id)
+}
+
+func (r *instrumentedRepository) GetCampaigns(filter *domainChatStorage.CampaignFilter) ([]*domainChatStorage.Campaign, error) {
+	defer metrics.ObserveStorageQuery("GetCampaigns", time.Now())
+	return r.repo.GetCampaigns(filter)
+}
+
+func (r *instrumentedRepository) UpdateCampaignStatus(id string, status string, from ...string) error {
+	defer metrics.ObserveStorageQuery("UpdateCampaignStatus", time.Now())
+	return r.repo.UpdateCampaignStatus(id, status, from...)
+}
+
+func (r *instrumentedRepository) GetCampaignRecipients(filter *domainChatStorage.CampaignRecipientFilter) ([]*domainChatStorage.CampaignRecipient, error) {
+	defer metrics.ObserveStorageQuery("GetCampaignRecipients", time.Now())
+	return r.repo.GetCampaignRecipients(filter)
+}
+
+func (r *instrumentedRepository) CountCampaignRecipients(campaignID string) (map[string]int, error) {
+	defer metrics.ObserveStorageQuery("CountCampaignRecipients", time.Now())
+	return r.repo.CountCampaignRecipients(campaignID)
+}
+
+func (r *instrumentedRepository) MarkCampaignRecipientSending(id int64) error {
+	defer metrics.ObserveStorageQuery("MarkCampaignRecipientSending", time.Now())
+	return r.repo.MarkCampaignRecipientSending(id)
+}
+
+func (r *instrumentedRepository) MarkCampaignRecipientSent(id int64, messageID string) error {
+	defer metrics.ObserveStorageQuery("MarkCampaignRecipientSent", time.Now())
+	return r.repo.MarkCampaignRecipientSent(id, messageID)
+}
+
+func (r *instrumentedRepository) MarkCampaignRecipientFailed(id int64, reason string) error {
+	defer metrics.ObserveStorageQuery("MarkCampaignRecipientFailed", time.Now())
+	return r.repo.MarkCampaignRecipientFailed(id, reason)
+}
+
+func (r *instrumentedRepository) RequeueCampaignRecipient(id int64, reason string) error {
+	defer metrics.ObserveStorageQuery("RequeueCampaignRecipient", time.Now())
+	return r.repo.RequeueCampaignRecipient(id, reason)
+}
+
+func (r *instrumentedRepository) FlagCampaignRecipientForReview(id int64, reason string) error {
+	defer metrics.ObserveStorageQuery("FlagCampaignRecipientForReview", time.Now())
+	return r.repo.FlagCampaignRecipientForReview(id, reason)
+}
+
+func (r *instrumentedRepository) FlagInterruptedCampaignRecipients(campaignID string, reason string) (int64, error) {
+	defer metrics.ObserveStorageQuery("FlagInterruptedCampaignRecipients", time.Now())
+	return r.repo.FlagInterruptedCampaignRecipients(campaignID, reason)
+}
+
+func (r *instrumentedRepository) UpdateCampaignRecipientReceipt(messageIDs []string, status string, at time.Time) (int64, error) {
+	defer metrics.ObserveStorageQuery("UpdateCampaignRecipientReceipt", time.Now())
+	return r.repo.UpdateCampaignRecipientReceipt(messageIDs, status, at)
+}
+
+// Auto-reply rule operations
+
+func (r *instrumentedRepository) StoreAutoReplyRule(rule *domainChatStorage.AutoReplyRule) error {
+	defer metrics.ObserveStorageQuery("StoreAutoReplyRule", time.Now())
+	return r.repo.StoreAutoReplyRule(rule)
+}
+
+func (r *instrumentedRepository) GetAutoReplyRule(id string) (*domainChatStorage.AutoReplyRule, error) {
+	defer metrics.ObserveStorageQuery("GetAutoReplyRule", time.Now())
+	return r.repo.GetAutoReplyRule(id)
+}
+
+func (r *instrumentedRepository) GetAutoReplyRules() ([]*domainChatStorage.AutoReplyRule, error) {
+	defer metrics.ObserveStorageQuery("GetAutoReplyRules", time.Now())
+	return r.repo.GetAutoReplyRules()
+}
+
+func (r *instrumentedRepository) DeleteAutoReplyRule(id string) error {
+	defer metrics.ObserveStorageQuery("DeleteAutoReplyRule", time.Now())
+	return r.repo.DeleteAutoReplyRule(id)
+}
+
+func (r *instrumentedRepository) ReplaceAutoReplyRules(rules []*domainChatStorage.AutoReplyRule) error {
+	defer metrics.ObserveStorageQuery("ReplaceAutoReplyRules", time.Now())
+	return r.repo.ReplaceAutoReplyRules(rules)
+}
+
+// API key operations
+
+func (r *instrumentedRepository) StoreAPIKey(key *domainChatStorage.APIKey) error {
+	defer metrics.ObserveStorageQuery("StoreAPIKey", time.Now())
+	return r.repo.StoreAPIKey(key)
+}
+
+func (r *instrumentedRepository) GetAPIKey(id string) (*domainChatStorage.APIKey, error) {
+	defer metrics.ObserveStorageQuery("GetAPIKey", time.Now())
+	return r.repo.GetAPIKey(id)
+}
+
+func (r *instrumentedRepository) GetAPIKeyByHash(keyHash string) (*domainChatStorage.APIKey, error) {
+	defer metrics.ObserveStorageQuery("GetAPIKeyByHash", time.Now())
+	return r.repo.GetAPIKeyByHash(keyHash)
+}
+
+func (r *instrumentedRepository) GetAPIKeys() ([]*domainChatStorage.APIKey, error) {
+	defer metrics.ObserveStorageQuery("GetAPIKeys", time.Now())
+	return r.repo.GetAPIKeys()
+}
+
+func (r *instrumentedRepository) CountAPIKeys() (int64, error) {
+	defer metrics.ObserveStorageQuery("CountAPIKeys", time.Now())
+	return r.repo.CountAPIKeys()
+}
+
+func (r *instrumentedRepository) TouchAPIKey(id string, usedAt time.Time) error {
+	defer metrics.ObserveStorageQuery("TouchAPIKey", time.Now())
+	return r.repo.TouchAPIKey(id, usedAt)
+}
+
+func (r *instrumentedRepository) DeleteAPIKey(id string) error {
+	defer metrics.ObserveStorageQuery("DeleteAPIKey", time.Now())
+	return r.repo.DeleteAPIKey(id)
+}
+
+// Audit log operations
+
+func (r *instrumentedRepository) StoreAuditLog(entry *domainChatStorage.AuditLog) error {
+	defer metrics.ObserveStorageQuery("StoreAuditLog", time.Now())
+	return r.repo.StoreAuditLog(entry)
+}
+
+func (r *instrumentedRepository) GetAuditLogs(filter *domainChatStorage.AuditLogFilter) ([]*domainChatStorage.AuditLog, error) {
+	defer metrics.ObserveStorageQuery("GetAuditLogs", time.Now())
+	return r.repo.GetAuditLogs(filter)
+}
+
+// Cleanup operations
+
+func (r *instrumentedRepository) TruncateAllChats() error {
+	defer metrics.ObserveStorageQuery("TruncateAllChats", time.Now())
+	return r.repo.TruncateAllChats()
+}
+
+func (r *instrumentedRepository) TruncateAllDataWithLogging(logPrefix string) error {
+	defer metrics.ObserveStorageQuery("TruncateAllDataWithLogging", time.Now())
+	return r.repo.TruncateAllDataWithLogging(logPrefix)
+}
+
+// Schema operations
+
+func (r *instrumentedRepository) InitializeSchema() error {
+	defer metrics.ObserveStorageQuery("InitializeSchema", time.Now())
+	return r.repo.InitializeSchema()
+}
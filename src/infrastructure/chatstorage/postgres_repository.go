@@ -0,0 +1,2324 @@
+package chatstorage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// PostgresRepository implements Repository using PostgreSQL
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL repository
+func NewPostgresRepository(db *sql.DB) domainChatStorage.IChatStorageRepository {
+	return &PostgresRepository{db: db}
+}
+
+// postgresArgs collects query arguments and hands out their numbered placeholders
+type postgresArgs []any
+
+func (a *postgresArgs) add(value any) string {
+	*a = append(*a, value)
+	return "$" + strconv.Itoa(len(*a))
+}
+
+// StoreChat creates or updates a chat
+func (r *PostgresRepository) StoreChat(chat *domainChatStorage.Chat) error {
+	now := time.Now()
+	chat.UpdatedAt = now
+
+	query := `
+		INSERT INTO chats (jid, name, last_message_time, ephemeral_expiration, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (jid) DO UPDATE SET
+			name = excluded.name,
+			last_message_time = excluded.last_message_time,
+			ephemeral_expiration = excluded.ephemeral_expiration,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(query, chat.JID, chat.Name, chat.LastMessageTime, chat.EphemeralExpiration, now, chat.UpdatedAt)
+	return err
+}
+
+// GetChat retrieves a chat by JID
+func (r *PostgresRepository) GetChat(jid string) (*domainChatStorage.Chat, error) {
+	query := `
+		SELECT jid, name, last_message_time, ephemeral_expiration, created_at, updated_at
+		FROM chats
+		WHERE jid = $1
+	`
+
+	chat, err := scanChat(r.db.QueryRow(query, jid))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return chat, err
+}
+
+// GetMessageByID retrieves a message by its ID from any chat
+func (r *PostgresRepository) GetMessageByID(id string) (*domainChatStorage.Message, error) {
+	query := `
+		SELECT ` + postgresMessageColumns + `
+		FROM messages
+		WHERE id = $1
+		LIMIT 1
+	`
+
+	message, err := scanMessage(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return message, err
+}
+
+// GetChats retrieves chats with filtering
+func (r *PostgresRepository) GetChats(filter *domainChatStorage.ChatFilter) ([]*domainChatStorage.Chat, error) {
+	var conditions []string
+	var args postgresArgs
+
+	query := `
+		SELECT c.jid, c.name, c.last_message_time, c.ephemeral_expiration, c.created_at, c.updated_at
+		FROM chats c
+	`
+
+	if filter.SearchName != "" {
+		// ILIKE keeps the case-insensitive matching of SQLite's LIKE
+		conditions = append(conditions, "c.name ILIKE "+args.add("%"+filter.SearchName+"%"))
+	}
+
+	if filter.HasMedia {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM messages m WHERE m.chat_jid = c.jid AND m.media_type != '')")
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY c.last_message_time DESC"
+	query += postgresPagination(&args, &filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chats []*domainChatStorage.Chat
+	for rows.Next() {
+		chat, err := scanChat(rows)
+		if err != nil {
+			return nil, err
+		}
+		chats = append(chats, chat)
+	}
+
+	return chats, rows.Err()
+}
+
+// DeleteChat deletes a chat and all its messages
+func (r *PostgresRepository) DeleteChat(jid string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec("DELETE FROM messages WHERE chat_jid = $1", jid); err != nil {
+		return err
+	}
+	if _, err = tx.Exec("DELETE FROM message_receipts WHERE chat_jid = $1", jid); err != nil {
+		return err
+	}
+	if _, err = tx.Exec("DELETE FROM message_edits WHERE chat_jid = $1", jid); err != nil {
+		return err
+	}
+	if _, err = tx.Exec("DELETE FROM message_reactions WHERE chat_jid = $1", jid); err != nil {
+		return err
+	}
+	if _, err = tx.Exec("DELETE FROM chats WHERE jid = $1", jid); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const postgresUpsertMessage = `
+	INSERT INTO messages (
+		id, chat_jid, sender, content, timestamp, is_from_me,
+		media_type, filename, url, media_key, file_sha256,
+		file_enc_sha256, file_length, created_at, updated_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	ON CONFLICT (id, chat_jid) DO UPDATE SET
+		sender = excluded.sender,
+		content = excluded.content,
+		timestamp = excluded.timestamp,
+		is_from_me = excluded.is_from_me,
+		media_type = excluded.media_type,
+		filename = excluded.filename,
+		url = excluded.url,
+		media_key = excluded.media_key,
+		file_sha256 = excluded.file_sha256,
+		file_enc_sha256 = excluded.file_enc_sha256,
+		file_length = excluded.file_length,
+		updated_at = excluded.updated_at
+`
+
+// StoreMessage creates or updates a message
+func (r *PostgresRepository) StoreMessage(message *domainChatStorage.Message) error {
+	now := time.Now()
+	message.CreatedAt = now
+	message.UpdatedAt = now
+
+	// Skip empty messages
+	if message.Content == "" && message.MediaType == "" {
+		return nil
+	}
+
+	_, err := r.db.Exec(postgresUpsertMessage,
+		message.ID, message.ChatJID, message.Sender, message.Content,
+		message.Timestamp, message.IsFromMe, message.MediaType, message.Filename,
+		message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
+		message.FileLength, message.CreatedAt, message.UpdatedAt,
+	)
+
+	return err
+}
+
+// StoreMessagesBatch creates or updates multiple messages in a single transaction
+func (r *PostgresRepository) StoreMessagesBatch(messages []*domainChatStorage.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(postgresUpsertMessage)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, message := range messages {
+		// Skip empty messages
+		if message.Content == "" && message.MediaType == "" {
+			continue
+		}
+
+		message.CreatedAt = now
+		message.UpdatedAt = now
+
+		_, err = stmt.Exec(
+			message.ID, message.ChatJID, message.Sender, message.Content,
+			message.Timestamp, message.IsFromMe, message.MediaType, message.Filename,
+			message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
+			message.FileLength, message.CreatedAt, message.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to store message %s: %w", message.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetMessages retrieves messages with filtering
+func (r *PostgresRepository) GetMessages(filter *domainChatStorage.MessageFilter) ([]*domainChatStorage.Message, error) {
+	var args postgresArgs
+	conditions := []string{"chat_jid = " + args.add(filter.ChatJID)}
+
+	if filter.StartTime != nil {
+		conditions = append(conditions, "timestamp >= "+args.add(*filter.StartTime))
+	}
+
+	if filter.EndTime != nil {
+		conditions = append(conditions, "timestamp <= "+args.add(*filter.EndTime))
+	}
+
+	if filter.MediaOnly {
+		conditions = append(conditions, "media_type != ''")
+	}
+
+	if filter.IsFromMe != nil {
+		conditions = append(conditions, "is_from_me = "+args.add(*filter.IsFromMe))
+	}
+
+	query := `
+		SELECT ` + postgresMessageColumns + `
+		FROM messages
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY timestamp DESC
+	`
+	query += postgresPagination(&args, &filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*domainChatStorage.Message
+	for rows.Next() {
+		message, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+// SearchMessages performs database-level search for messages containing specific text
+func (r *PostgresRepository) SearchMessages(chatJID, searchText string, limit int) ([]*domainChatStorage.Message, error) {
+	// Return empty results for empty search text
+	if strings.TrimSpace(searchText) == "" {
+		return []*domainChatStorage.Message{}, nil
+	}
+
+	results, err := r.SearchAllMessages(&domainChatStorage.MessageSearchFilter{
+		Query:   literalSearchQuery(searchText),
+		ChatJID: chatJID,
+		Sort:    domainChatStorage.SearchSortNewest,
+		Limit:   limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*domainChatStorage.Message, 0, len(results))
+	for _, result := range results {
+		messages = append(messages, result.Message)
+	}
+	return messages, nil
+}
+
+// SearchAllMessages runs a full-text search across all chats using the tsvector index.
+// The query syntax matches the SQLite backend and is translated to a tsquery.
+func (r *PostgresRepository) SearchAllMessages(filter *domainChatStorage.MessageSearchFilter) ([]*domainChatStorage.MessageSearchResult, error) {
+	tsQuery, err := postgresTSQuery(filter.Query)
+	if err != nil {
+		return nil, err
+	}
+	if tsQuery == "" {
+		return []*domainChatStorage.MessageSearchResult{}, nil
+	}
+
+	var args postgresArgs
+	source := postgresSearchSource(&args, tsQuery, filter)
+	headline := fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=16, MinWords=6, MaxFragments=1", searchHighlightStart, searchHighlightEnd)
+
+	orderBy := "ts_rank(m.search_vector, q) DESC, m.timestamp DESC"
+	if filter.Sort == domainChatStorage.SearchSortNewest {
+		orderBy = "m.timestamp DESC"
+	}
+
+	query := `
+		SELECT ` + postgresMessageColumnsWithAlias + `,
+			COALESCE(c.name, ''), ts_headline('simple', COALESCE(m.content, ''), q, ` + args.add(headline) + `),
+			ts_rank(m.search_vector, q)
+		` + source + `
+		ORDER BY ` + orderBy
+	query += postgresPagination(&args, &filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, postgresSearchError(err)
+	}
+	defer rows.Close()
+
+	results := []*domainChatStorage.MessageSearchResult{}
+	for rows.Next() {
+		result := &domainChatStorage.MessageSearchResult{}
+		message, err := scanMessage(rows, &result.ChatName, &result.Snippet, &result.Rank)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+		result.Message = message
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, postgresSearchError(err)
+	}
+
+	return results, nil
+}
+
+// CountSearchMessages returns the number of messages matching a full-text search
+func (r *PostgresRepository) CountSearchMessages(filter *domainChatStorage.MessageSearchFilter) (int64, error) {
+	tsQuery, err := postgresTSQuery(filter.Query)
+	if err != nil {
+		return 0, err
+	}
+	if tsQuery == "" {
+		return 0, nil
+	}
+
+	var args postgresArgs
+	source := postgresSearchSource(&args, tsQuery, filter)
+
+	count, err := r.getCount("SELECT COUNT(*) "+source, args...)
+	if err != nil {
+		return 0, postgresSearchError(err)
+	}
+	return count, nil
+}
+
+// postgresSearchSource builds the FROM and WHERE clauses shared by the search and count queries
+func postgresSearchSource(args *postgresArgs, tsQuery string, filter *domainChatStorage.MessageSearchFilter) string {
+	source := "FROM messages m CROSS JOIN to_tsquery('simple', " + args.add(tsQuery) + ") q LEFT JOIN chats c ON c.jid = m.chat_jid"
+	conditions := []string{"m.search_vector @@ q"}
+
+	if filter.ChatJID != "" {
+		conditions = append(conditions, "m.chat_jid = "+args.add(filter.ChatJID))
+	}
+
+	if filter.Sender != "" {
+		if strings.Contains(filter.Sender, "@") {
+			conditions = append(conditions, "m.sender = "+args.add(filter.Sender))
+		} else {
+			// A bare phone number matches the sender on any server and device
+			conditions = append(conditions, "(m.sender LIKE "+args.add(filter.Sender+"@%")+" OR m.sender LIKE "+args.add(filter.Sender+":%")+")")
+		}
+	}
+
+	if filter.StartTime != nil {
+		conditions = append(conditions, "m.timestamp >= "+args.add(*filter.StartTime))
+	}
+
+	if filter.EndTime != nil {
+		conditions = append(conditions, "m.timestamp <= "+args.add(*filter.EndTime))
+	}
+
+	if filter.MediaType != "" {
+		conditions = append(conditions, "m.media_type = "+args.add(filter.MediaType))
+	} else if filter.MediaOnly {
+		conditions = append(conditions, "m.media_type != ''")
+	}
+
+	if filter.IsFromMe != nil {
+		conditions = append(conditions, "m.is_from_me = "+args.add(*filter.IsFromMe))
+	}
+
+	return source + " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// postgresSearchError marks tsquery parse errors so callers can report them as bad input
+func postgresSearchError(err error) error {
+	if strings.Contains(err.Error(), "tsquery") {
+		return fmt.Errorf("%w: %v", domainChatStorage.ErrInvalidSearchQuery, err)
+	}
+	return fmt.Errorf("failed to search messages: %w", err)
+}
+
+// DeleteMessage deletes a specific message
+func (r *PostgresRepository) DeleteMessage(id, chatJID string) error {
+	for _, table := range []string{"message_receipts", "message_edits", "message_reactions"} {
+		if _, err := r.db.Exec("DELETE FROM "+table+" WHERE message_id = $1 AND chat_jid = $2", id, chatJID); err != nil {
+			return err
+		}
+	}
+	_, err := r.db.Exec("DELETE FROM messages WHERE id = $1 AND chat_jid = $2", id, chatJID)
+	return err
+}
+
+// getCount is a private helper for count queries
+func (r *PostgresRepository) getCount(query string, args ...any) (int64, error) {
+	var count int64
+	err := r.db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+// GetChatMessageCount returns the number of messages in a chat
+func (r *PostgresRepository) GetChatMessageCount(chatJID string) (int64, error) {
+	return r.getCount("SELECT COUNT(*) FROM messages WHERE chat_jid = $1", chatJID)
+}
+
+// GetTotalMessageCount returns the total number of messages
+func (r *PostgresRepository) GetTotalMessageCount() (int64, error) {
+	return r.getCount("SELECT COUNT(*) FROM messages")
+}
+
+// GetTotalChatCount returns the total number of chats
+func (r *PostgresRepository) GetTotalChatCount() (int64, error) {
+	return r.getCount("SELECT COUNT(*) FROM chats")
+}
+
+// TruncateAllChats deletes all chats and messages from the database
+func (r *PostgresRepository) TruncateAllChats() error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec("DELETE FROM messages"); err != nil {
+		return fmt.Errorf("failed to delete messages: %w", err)
+	}
+	if _, err = tx.Exec("DELETE FROM message_receipts"); err != nil {
+		return fmt.Errorf("failed to delete message receipts: %w", err)
+	}
+	if _, err = tx.Exec("DELETE FROM message_edits"); err != nil {
+		return fmt.Errorf("failed to delete message edits: %w", err)
+	}
+	if _, err = tx.Exec("DELETE FROM message_reactions"); err != nil {
+		return fmt.Errorf("failed to delete message reactions: %w", err)
+	}
+	if _, err = tx.Exec("DELETE FROM calls"); err != nil {
+		return fmt.Errorf("failed to delete calls: %w", err)
+	}
+	if _, err = tx.Exec("DELETE FROM poll_votes"); err != nil {
+		return fmt.Errorf("failed to delete poll votes: %w", err)
+	}
+	if _, err = tx.Exec("DELETE FROM polls"); err != nil {
+		return fmt.Errorf("failed to delete polls: %w", err)
+	}
+	if _, err = tx.Exec("DELETE FROM group_events"); err != nil {
+		return fmt.Errorf("failed to delete group events: %w", err)
+	}
+	if _, err = tx.Exec("DELETE FROM stream_events"); err != nil {
+		return fmt.Errorf("failed to delete stream events: %w", err)
+	}
+	if _, err = tx.Exec("DELETE FROM chats"); err != nil {
+		return fmt.Errorf("failed to delete chats: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetChatNameWithPushName determines the appropriate name for a chat with pushname support
+func (r *PostgresRepository) GetChatNameWithPushName(jid types.JID, chatJID string, senderUser string, pushName string) string {
+	return chatNameWithPushName(r, jid, chatJID, senderUser, pushName)
+}
+
+// CreateMessage stores the chat and message of an incoming message event
+func (r *PostgresRepository) CreateMessage(ctx context.Context, evt *events.Message) error {
+	return createMessage(r, evt)
+}
+
+// GetStorageStatistics returns current storage statistics for logging purposes
+func (r *PostgresRepository) GetStorageStatistics() (chatCount int64, messageCount int64, err error) {
+	return storageStatistics(r)
+}
+
+// TruncateAllDataWithLogging performs truncation with detailed logging
+func (r *PostgresRepository) TruncateAllDataWithLogging(logPrefix string) error {
+	return truncateAllDataWithLogging(r, logPrefix)
+}
+
+// StoreSentMessageWithContext stores a message that was sent by the user with context cancellation support
+func (r *PostgresRepository) StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error {
+	return storeSentMessage(ctx, r, messageID, senderJID, recipientJID, content, timestamp)
+}
+
+// StoreMessageReceipts records message states, keeping the first time each participant reached a state
+func (r *PostgresRepository) StoreMessageReceipts(receipts []*domainChatStorage.MessageReceipt) error {
+	if len(receipts) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO message_receipts (message_id, chat_jid, participant_jid, status, timestamp)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (message_id, chat_jid, participant_jid, status) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, receipt := range receipts {
+		if _, err := stmt.Exec(receipt.MessageID, receipt.ChatJID, receipt.ParticipantJID, receipt.Status, receipt.Timestamp); err != nil {
+			return fmt.Errorf("failed to store receipt of message %s: %w", receipt.MessageID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetMessageReceipts returns the receipts of the given messages in the order they arrived
+func (r *PostgresRepository) GetMessageReceipts(messageIDs []string) ([]*domainChatStorage.MessageReceipt, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	var args postgresArgs
+	query := `SELECT ` + messageReceiptColumns + ` FROM message_receipts WHERE message_id IN (` +
+		postgresList(&args, messageIDs) + `) ORDER BY timestamp, participant_jid`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var receipts []*domainChatStorage.MessageReceipt
+	for rows.Next() {
+		receipt, err := scanMessageReceipt(rows)
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, receipt)
+	}
+	return receipts, rows.Err()
+}
+
+// StoreMessageEdit records an edit of a stored message and replaces its content when the edit is the latest.
+// Edits of messages that were not stored and edits that were already recorded are ignored.
+func (r *PostgresRepository) StoreMessageEdit(edit *domainChatStorage.MessageEdit) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var content sql.NullString
+	err = tx.QueryRow("SELECT content FROM messages WHERE id = $1 AND chat_jid = $2 FOR UPDATE", edit.MessageID, edit.ChatJID).Scan(&content)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	edit.OldContent = content.String
+
+	result, err := tx.Exec(`
+		INSERT INTO message_edits (`+messageEditColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO NOTHING
+	`, edit.ID, edit.MessageID, edit.ChatJID, edit.OldContent, edit.NewContent, edit.EditedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store edit of message %s: %w", edit.MessageID, err)
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE messages SET content = $1, edited_at = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND chat_jid = $4 AND (edited_at IS NULL OR edited_at <= $2)
+	`, edit.NewContent, edit.EditedAt, edit.MessageID, edit.ChatJID)
+	if err != nil {
+		return fmt.Errorf("failed to update edited message %s: %w", edit.MessageID, err)
+	}
+
+	return tx.Commit()
+}
+
+// GetMessageEdits returns the edits of the given messages, oldest first
+func (r *PostgresRepository) GetMessageEdits(messageIDs []string) ([]*domainChatStorage.MessageEdit, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	var args postgresArgs
+	query := `SELECT ` + messageEditColumns + ` FROM message_edits WHERE message_id IN (` +
+		postgresList(&args, messageIDs) + `) ORDER BY edited_at, id`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var edits []*domainChatStorage.MessageEdit
+	for rows.Next() {
+		edit, err := scanMessageEdit(rows)
+		if err != nil {
+			return nil, err
+		}
+		edits = append(edits, edit)
+	}
+	return edits, rows.Err()
+}
+
+// RevokeMessage marks a stored message as deleted for everyone, keeping the time it was first revoked
+func (r *PostgresRepository) RevokeMessage(id, chatJID string, revokedAt time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE messages SET is_revoked = TRUE, revoked_at = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND chat_jid = $3 AND NOT is_revoked
+	`, revokedAt, id, chatJID)
+	return err
+}
+
+// StoreMessageReaction replaces the reaction of a sender to a message, an empty reaction removes it.
+// Reactions older than the stored one are ignored.
+func (r *PostgresRepository) StoreMessageReaction(reaction *domainChatStorage.MessageReaction) error {
+	if reaction.Reaction == "" {
+		_, err := r.db.Exec(`
+			DELETE FROM message_reactions
+			WHERE message_id = $1 AND chat_jid = $2 AND sender_jid = $3 AND reacted_at <= $4
+		`, reaction.MessageID, reaction.ChatJID, reaction.SenderJID, reaction.ReactedAt)
+		return err
+	}
+
+	_, err := r.db.Exec(`
+		INSERT INTO message_reactions (`+messageReactionColumns+`)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (message_id, chat_jid, sender_jid) DO UPDATE SET
+			reaction = excluded.reaction,
+			reacted_at = excluded.reacted_at
+		WHERE excluded.reacted_at >= message_reactions.reacted_at
+	`, reaction.MessageID, reaction.ChatJID, reaction.SenderJID, reaction.Reaction, reaction.ReactedAt)
+	return err
+}
+
+// GetMessageReactions returns the current reactions to the given messages, oldest first
+func (r *PostgresRepository) GetMessageReactions(messageIDs []string) ([]*domainChatStorage.MessageReaction, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	var args postgresArgs
+	query := `SELECT ` + messageReactionColumns + ` FROM message_reactions WHERE message_id IN (` +
+		postgresList(&args, messageIDs) + `) ORDER BY reacted_at, sender_jid`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reactions []*domainChatStorage.MessageReaction
+	for rows.Next() {
+		reaction, err := scanMessageReaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		reactions = append(reactions, reaction)
+	}
+	return reactions, rows.Err()
+}
+
+// StoreCall creates or updates an entry of the call log
+func (r *PostgresRepository) StoreCall(call *domainChatStorage.Call) error {
+	_, err := r.db.Exec(`
+		INSERT INTO calls (`+callColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT(id) DO UPDATE SET
+			status = excluded.status,
+			auto_rejected = excluded.auto_rejected,
+			end_reason = excluded.end_reason,
+			accepted_at = excluded.accepted_at,
+			ended_at = excluded.ended_at
+	`, callArgs(call)...)
+	return err
+}
+
+// GetCall retrieves a call by ID
+func (r *PostgresRepository) GetCall(id string) (*domainChatStorage.Call, error) {
+	row := r.db.QueryRow("SELECT "+callColumns+" FROM calls WHERE id = $1", id)
+	call, err := scanCall(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return call, err
+}
+
+// GetCalls returns the call log, most recent calls first
+func (r *PostgresRepository) GetCalls(filter *domainChatStorage.CallFilter) ([]*domainChatStorage.Call, error) {
+	var args postgresArgs
+	query := "SELECT " + callColumns + " FROM calls"
+
+	if filter.ChatJID != "" {
+		query += " WHERE chat_jid = " + args.add(filter.ChatJID)
+	}
+
+	query += " ORDER BY started_at DESC"
+	query += postgresPagination(&args, &filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var calls []*domainChatStorage.Call
+	for rows.Next() {
+		call, err := scanCall(rows)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+	}
+	return calls, rows.Err()
+}
+
+// StoreGroupEvents records group events, events that were already recorded are ignored
+func (r *PostgresRepository) StoreGroupEvents(events []*domainChatStorage.GroupEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO group_events (` + groupEventColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (group_jid, type, target_jid, timestamp) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, event := range events {
+		if _, err := stmt.Exec(event.GroupJID, event.Type, event.ActorJID, event.TargetJID, event.Value, event.Timestamp); err != nil {
+			return fmt.Errorf("failed to store %s event of group %s: %w", event.Type, event.GroupJID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetGroupEvents returns the group events matching the filter, most recent first
+func (r *PostgresRepository) GetGroupEvents(filter *domainChatStorage.GroupEventFilter) ([]*domainChatStorage.GroupEvent, error) {
+	var args postgresArgs
+	query := "SELECT id, " + groupEventColumns + " FROM group_events" + postgresGroupEventConditions(&args, filter) +
+		" ORDER BY timestamp DESC, id DESC"
+	query += postgresPagination(&args, &filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domainChatStorage.GroupEvent
+	for rows.Next() {
+		event, err := scanGroupEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// CountGroupEvents returns the number of group events matching the filter
+func (r *PostgresRepository) CountGroupEvents(filter *domainChatStorage.GroupEventFilter) (int64, error) {
+	var args postgresArgs
+	return r.getCount("SELECT COUNT(*) FROM group_events"+postgresGroupEventConditions(&args, filter), args...)
+}
+
+func postgresGroupEventConditions(args *postgresArgs, filter *domainChatStorage.GroupEventFilter) string {
+	var conditions []string
+
+	if filter.GroupJID != "" {
+		conditions = append(conditions, "group_jid = "+args.add(filter.GroupJID))
+	}
+	if len(filter.Types) > 0 {
+		conditions = append(conditions, "type IN ("+postgresList(args, filter.Types)+")")
+	}
+	if filter.ActorJID != "" {
+		conditions = append(conditions, "actor_jid = "+args.add(filter.ActorJID))
+	}
+	if filter.TargetJID != "" {
+		conditions = append(conditions, "target_jid = "+args.add(filter.TargetJID))
+	}
+	if filter.StartTime != nil {
+		conditions = append(conditions, "timestamp >= "+args.add(*filter.StartTime))
+	}
+	if filter.EndTime != nil {
+		conditions = append(conditions, "timestamp <= "+args.add(*filter.EndTime))
+	}
+
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// StoreStreamEvent appends an event to the event stream log, the caller assigns its ID
+func (r *PostgresRepository) StoreStreamEvent(event *domainChatStorage.StreamEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	_, err := r.db.Exec(`
+		INSERT INTO stream_events (`+streamEventColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, event.ID, event.Event, event.DeviceID, event.ChatJID, event.Data, event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store %s stream event %d: %w", event.Event, event.ID, err)
+	}
+	return nil
+}
+
+// GetStreamEventsAfter returns up to limit logged events with an ID above afterID, oldest first
+func (r *PostgresRepository) GetStreamEventsAfter(afterID int64, limit int) ([]*domainChatStorage.StreamEvent, error) {
+	rows, err := r.db.Query("SELECT "+streamEventColumns+" FROM stream_events WHERE id > $1 ORDER BY id LIMIT $2", afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domainChatStorage.StreamEvent
+	for rows.Next() {
+		event, err := scanStreamEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// GetLastStreamEventID returns the ID of the most recent logged event, 0 when the log is empty
+func (r *PostgresRepository) GetLastStreamEventID() (int64, error) {
+	return r.getCount("SELECT COALESCE(MAX(id), 0) FROM stream_events")
+}
+
+// TrimStreamEvents removes the logged events with an ID up to upToID
+func (r *PostgresRepository) TrimStreamEvents(upToID int64) error {
+	_, err := r.db.Exec("DELETE FROM stream_events WHERE id <= $1", upToID)
+	return err
+}
+
+// StorePoll creates or updates a poll, keeping a known secret when the poll is stored again without one
+func (r *PostgresRepository) StorePoll(poll *domainChatStorage.Poll) error {
+	args, err := pollArgs(poll)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO polls (`+pollColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT(message_id) DO UPDATE SET
+			question = excluded.question,
+			options = excluded.options,
+			selectable_count = excluded.selectable_count,
+			secret = COALESCE(excluded.secret, polls.secret)
+	`, args...)
+	return err
+}
+
+// GetPoll retrieves a poll by the ID of its message
+func (r *PostgresRepository) GetPoll(messageID string) (*domainChatStorage.Poll, error) {
+	row := r.db.QueryRow("SELECT "+pollColumns+" FROM polls WHERE message_id = $1", messageID)
+	poll, err := scanPoll(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return poll, err
+}
+
+// StorePollVote records the vote of a voter, replacing an older vote of the same voter
+func (r *PostgresRepository) StorePollVote(vote *domainChatStorage.PollVote) error {
+	args, err := pollVoteArgs(vote)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO poll_votes (`+pollVoteColumns+`)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT(poll_id, voter_jid) DO UPDATE SET
+			selected_options = excluded.selected_options,
+			voted_at = excluded.voted_at
+		WHERE excluded.voted_at >= poll_votes.voted_at
+	`, args...)
+	return err
+}
+
+// GetPollVotes returns the current vote of every voter of a poll
+func (r *PostgresRepository) GetPollVotes(pollID string) ([]*domainChatStorage.PollVote, error) {
+	rows, err := r.db.Query("SELECT "+pollVoteColumns+" FROM poll_votes WHERE poll_id = $1 ORDER BY voted_at, voter_jid", pollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var votes []*domainChatStorage.PollVote
+	for rows.Next() {
+		vote, err := scanPollVote(rows)
+		if err != nil {
+			return nil, err
+		}
+		votes = append(votes, vote)
+	}
+	return votes, rows.Err()
+}
+
+// StoreDeviceRecord creates or updates a device registry entry
+func (r *PostgresRepository) StoreDeviceRecord(device *domainChatStorage.DeviceRecord) error {
+	now := time.Now()
+	device.UpdatedAt = now
+
+	query := `
+		INSERT INTO devices (id, jid, webhooks, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET
+			jid = excluded.jid,
+			webhooks = excluded.webhooks,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(query, device.ID, device.JID, strings.Join(device.Webhooks, ","), now, device.UpdatedAt)
+	return err
+}
+
+// GetDeviceRecords returns all registered devices ordered by creation time
+func (r *PostgresRepository) GetDeviceRecords() ([]*domainChatStorage.DeviceRecord, error) {
+	rows, err := r.db.Query(`
+		SELECT id, jid, webhooks, created_at, updated_at
+		FROM devices
+		ORDER BY created_at ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var devices []*domainChatStorage.DeviceRecord
+	for rows.Next() {
+		device := &domainChatStorage.DeviceRecord{}
+		var webhooks string
+		if err := rows.Scan(&device.ID, &device.JID, &webhooks, &device.CreatedAt, &device.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if webhooks != "" {
+			device.Webhooks = strings.Split(webhooks, ",")
+		}
+		devices = append(devices, device)
+	}
+
+	return devices, rows.Err()
+}
+
+// DeleteDeviceRecord removes a device from the registry
+func (r *PostgresRepository) DeleteDeviceRecord(id string) error {
+	_, err := r.db.Exec("DELETE FROM devices WHERE id = $1", id)
+	return err
+}
+
+// StoreWebhookSubscription creates or updates a webhook subscription
+func (r *PostgresRepository) StoreWebhookSubscription(subscription *domainChatStorage.WebhookSubscription) error {
+	now := time.Now()
+	if subscription.CreatedAt.IsZero() {
+		subscription.CreatedAt = now
+	}
+	subscription.UpdatedAt = now
+
+	events, allowJIDs, denyJIDs, err := encodeWebhookSubscriptionLists(subscription)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO webhooks (id, url, secret, device_id, events, allow_jids, deny_jids, skip_from_me, chat_type, include_media, enabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (id) DO UPDATE SET
+			url = excluded.url,
+			secret = excluded.secret,
+			device_id = excluded.device_id,
+			events = excluded.events,
+			allow_jids = excluded.allow_jids,
+			deny_jids = excluded.deny_jids,
+			skip_from_me = excluded.skip_from_me,
+			chat_type = excluded.chat_type,
+			include_media = excluded.include_media,
+			enabled = excluded.enabled,
+			updated_at = excluded.updated_at
+	`, subscription.ID, subscription.URL, subscription.Secret, subscription.DeviceID, events, allowJIDs,
+		denyJIDs, subscription.SkipFromMe, subscription.ChatType, subscription.IncludeMedia, subscription.Enabled,
+		subscription.CreatedAt, subscription.UpdatedAt)
+	return err
+}
+
+// GetWebhookSubscription retrieves a webhook subscription by ID
+func (r *PostgresRepository) GetWebhookSubscription(id string) (*domainChatStorage.WebhookSubscription, error) {
+	row := r.db.QueryRow("SELECT "+webhookSubscriptionColumns+" FROM webhooks WHERE id = $1", id)
+	subscription, err := scanWebhookSubscription(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return subscription, err
+}
+
+// GetWebhookSubscriptions returns all webhook subscriptions ordered by creation time
+func (r *PostgresRepository) GetWebhookSubscriptions() ([]*domainChatStorage.WebhookSubscription, error) {
+	rows, err := r.db.Query("SELECT " + webhookSubscriptionColumns + " FROM webhooks ORDER BY created_at ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*domainChatStorage.WebhookSubscription
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// DeleteWebhookSubscription removes a webhook subscription
+func (r *PostgresRepository) DeleteWebhookSubscription(id string) error {
+	_, err := r.db.Exec("DELETE FROM webhooks WHERE id = $1", id)
+	return err
+}
+
+// EnqueueWebhookDelivery persists a webhook event before it is delivered
+func (r *PostgresRepository) EnqueueWebhookDelivery(delivery *domainChatStorage.WebhookDelivery) error {
+	now := time.Now()
+	if delivery.Status == "" {
+		delivery.Status = domainChatStorage.WebhookDeliveryPending
+	}
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = now
+	}
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+
+	// lib/pq does not support LastInsertId, the generated key is read back with RETURNING
+	return r.db.QueryRow(`
+		INSERT INTO webhook_deliveries (webhook_id, device_id, event, url, payload, status, attempts, last_error, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`, delivery.WebhookID, delivery.DeviceID, delivery.Event, delivery.URL, delivery.Payload, delivery.Status, delivery.Attempts,
+		delivery.LastError, delivery.NextAttemptAt.UnixMilli(), now, now).Scan(&delivery.ID)
+}
+
+// ClaimDueWebhookDeliveries marks up to limit pending deliveries that are due as in flight and returns them.
+// SKIP LOCKED lets several instances share one queue without claiming the same delivery twice.
+func (r *PostgresRepository) ClaimDueWebhookDeliveries(now time.Time, limit int) ([]*domainChatStorage.WebhookDelivery, error) {
+	rows, err := r.db.Query(`
+		UPDATE webhook_deliveries
+		SET status = $1, updated_at = $2
+		WHERE id IN (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = $3 AND next_attempt_at <= $4
+			ORDER BY next_attempt_at ASC, id ASC
+			LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+webhookDeliveryColumns,
+		domainChatStorage.WebhookDeliveryDelivering, now, domainChatStorage.WebhookDeliveryPending, now.UnixMilli(), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*domainChatStorage.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the order of the subquery
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].NextAttemptAt.Equal(deliveries[j].NextAttemptAt) {
+			return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt)
+		}
+		return deliveries[i].ID < deliveries[j].ID
+	})
+	return deliveries, nil
+}
+
+// MarkWebhookDelivered records a successful delivery
+func (r *PostgresRepository) MarkWebhookDelivered(id int64, attempts int) error {
+	now := time.Now()
+	_, err := r.db.Exec(`
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, last_error = '', delivered_at = $3, updated_at = $4
+		WHERE id = $5
+	`, domainChatStorage.WebhookDeliveryDelivered, attempts, now, now, id)
+	return err
+}
+
+// ScheduleWebhookRetry puts a failed delivery back in the queue for a later attempt
+func (r *PostgresRepository) ScheduleWebhookRetry(id int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	_, err := r.db.Exec(`
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, last_error = $3, next_attempt_at = $4, updated_at = $5
+		WHERE id = $6
+	`, domainChatStorage.WebhookDeliveryPending, attempts, lastError, nextAttemptAt.UnixMilli(), time.Now(), id)
+	return err
+}
+
+// MarkWebhookDead moves a delivery to the dead-letter state after its last failed attempt
+func (r *PostgresRepository) MarkWebhookDead(id int64, attempts int, lastError string) error {
+	_, err := r.db.Exec(`
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, last_error = $3, updated_at = $4
+		WHERE id = $5
+	`, domainChatStorage.WebhookDeliveryDead, attempts, lastError, time.Now(), id)
+	return err
+}
+
+// ReleaseInFlightWebhookDeliveries returns deliveries interrupted by a shutdown to the pending state
+func (r *PostgresRepository) ReleaseInFlightWebhookDeliveries() (int64, error) {
+	result, err := r.db.Exec(
+		"UPDATE webhook_deliveries SET status = $1, updated_at = $2 WHERE status = $3",
+		domainChatStorage.WebhookDeliveryPending, time.Now(), domainChatStorage.WebhookDeliveryDelivering,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// GetWebhookDelivery retrieves a single webhook delivery
+func (r *PostgresRepository) GetWebhookDelivery(id int64) (*domainChatStorage.WebhookDelivery, error) {
+	row := r.db.QueryRow("SELECT "+webhookDeliveryColumns+" FROM webhook_deliveries WHERE id = $1", id)
+	delivery, err := scanWebhookDelivery(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return delivery, err
+}
+
+// GetWebhookDeliveries retrieves webhook deliveries, newest first
+func (r *PostgresRepository) GetWebhookDeliveries(filter *domainChatStorage.WebhookDeliveryFilter) ([]*domainChatStorage.WebhookDelivery, error) {
+	var args postgresArgs
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries"
+
+	if filter.Status != "" {
+		query += " WHERE status = " + args.add(filter.Status)
+	}
+
+	query += " ORDER BY id DESC"
+	query += postgresPagination(&args, &filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*domainChatStorage.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// ReplayWebhookDelivery queues a delivery again with a fresh attempt budget
+func (r *PostgresRepository) ReplayWebhookDelivery(id int64) error {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = 0, last_error = '', next_attempt_at = $2, delivered_at = NULL, updated_at = $3
+		WHERE id = $4 AND status != $5
+	`, domainChatStorage.WebhookDeliveryPending, now.UnixMilli(), now, id, domainChatStorage.WebhookDeliveryDelivering)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// PurgeWebhookDeliveries deletes deliveries in the given state and returns how many were removed
+func (r *PostgresRepository) PurgeWebhookDeliveries(status string) (int64, error) {
+	result, err := r.db.Exec("DELETE FROM webhook_deliveries WHERE status = $1", status)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// StoreScheduledMessage persists a message to be sent once it is due
+func (r *PostgresRepository) StoreScheduledMessage(message *domainChatStorage.ScheduledMessage) error {
+	now := time.Now()
+	if message.Status == "" {
+		message.Status = domainChatStorage.ScheduledMessagePending
+	}
+	message.NextAttemptAt = message.SendAt
+	message.CreatedAt = now
+	message.UpdatedAt = now
+
+	_, err := r.db.Exec(`
+		INSERT INTO scheduled_messages (id, device_id, type, phone, payload, media_path, send_at, status, attempts, last_error, message_id, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`, message.ID, message.DeviceID, message.Type, message.Phone, message.Payload, message.MediaPath, message.SendAt.UnixMilli(),
+		message.Status, message.Attempts, message.LastError, message.MessageID, message.NextAttemptAt.UnixMilli(), now, now)
+	return err
+}
+
+// GetScheduledMessage retrieves a scheduled message by ID
+func (r *PostgresRepository) GetScheduledMessage(id string) (*domainChatStorage.ScheduledMessage, error) {
+	row := r.db.QueryRow("SELECT "+scheduledMessageColumns+" FROM scheduled_messages WHERE id = $1", id)
+	message, err := scanScheduledMessage(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return message, err
+}
+
+// GetScheduledMessages retrieves scheduled messages, the ones due first
+func (r *PostgresRepository) GetScheduledMessages(filter *domainChatStorage.ScheduledMessageFilter) ([]*domainChatStorage.ScheduledMessage, error) {
+	var conditions []string
+	var args postgresArgs
+	query := "SELECT " + scheduledMessageColumns + " FROM scheduled_messages"
+
+	if filter.DeviceID != "" {
+		conditions = append(conditions, "device_id = "+args.add(filter.DeviceID))
+	}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = "+args.add(filter.Status))
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY send_at ASC, created_at ASC"
+	query += postgresPagination(&args, &filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*domainChatStorage.ScheduledMessage
+	for rows.Next() {
+		message, err := scanScheduledMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+// ClaimDueScheduledMessages marks up to limit pending messages that are due as sending and returns them
+func (r *PostgresRepository) ClaimDueScheduledMessages(now time.Time, limit int) ([]*domainChatStorage.ScheduledMessage, error) {
+	rows, err := r.db.Query(`
+		UPDATE scheduled_messages
+		SET status = $1, updated_at = $2
+		WHERE id IN (
+			SELECT id
+			FROM scheduled_messages
+			WHERE status = $3 AND next_attempt_at <= $4
+			ORDER BY next_attempt_at ASC, created_at ASC
+			LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+scheduledMessageColumns,
+		domainChatStorage.ScheduledMessageSending, now, domainChatStorage.ScheduledMessagePending, now.UnixMilli(), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*domainChatStorage.ScheduledMessage
+	for rows.Next() {
+		message, err := scanScheduledMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the order of the subquery
+	sort.Slice(messages, func(i, j int) bool {
+		if !messages[i].NextAttemptAt.Equal(messages[j].NextAttemptAt) {
+			return messages[i].NextAttemptAt.Before(messages[j].NextAttemptAt)
+		}
+		return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+	})
+	return messages, nil
+}
+
+// RescheduleScheduledMessage moves a pending message to a new send time
+func (r *PostgresRepository) RescheduleScheduledMessage(id string, sendAt time.Time) error {
+	result, err := r.db.Exec(`
+		UPDATE scheduled_messages
+		SET send_at = $1, next_attempt_at = $1, attempts = 0, last_error = '', updated_at = $2
+		WHERE id = $3 AND status = $4
+	`, sendAt.UnixMilli(), time.Now(), id, domainChatStorage.ScheduledMessagePending)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// CancelScheduledMessage stops a pending message from being sent
+func (r *PostgresRepository) CancelScheduledMessage(id string) error {
+	result, err := r.db.Exec(
+		"UPDATE scheduled_messages SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4",
+		domainChatStorage.ScheduledMessageCancelled, time.Now(), id, domainChatStorage.ScheduledMessagePending,
+	)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// MarkScheduledMessageSent records the message ID of a dispatched message
+func (r *PostgresRepository) MarkScheduledMessageSent(id string, attempts int, messageID string) error {
+	now := time.Now()
+	_, err := r.db.Exec(`
+		UPDATE scheduled_messages
+		SET status = $1, attempts = $2, last_error = '', message_id = $3, sent_at = $4, updated_at = $5
+		WHERE id = $6
+	`, domainChatStorage.ScheduledMessageSent, attempts, messageID, now, now, id)
+	return err
+}
+
+// RetryScheduledMessage puts a message that could not be sent back in the schedule for a later attempt
+func (r *PostgresRepository) RetryScheduledMessage(id string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	_, err := r.db.Exec(`
+		UPDATE scheduled_messages
+		SET status = $1, attempts = $2, last_error = $3, next_attempt_at = $4, updated_at = $5
+		WHERE id = $6
+	`, domainChatStorage.ScheduledMessagePending, attempts, lastError, nextAttemptAt.UnixMilli(), time.Now(), id)
+	return err
+}
+
+// MarkScheduledMessageFailed records that a message could not be sent
+func (r *PostgresRepository) MarkScheduledMessageFailed(id string, attempts int, lastError string) error {
+	_, err := r.db.Exec(`
+		UPDATE scheduled_messages
+		SET status = $1, attempts = $2, last_error = $3, updated_at = $4
+		WHERE id = $5
+	`, domainChatStorage.ScheduledMessageFailed, attempts, lastError, time.Now(), id)
+	return err
+}
+
+// ReleaseInFlightScheduledMessages returns messages interrupted by a shutdown to the pending state
+func (r *PostgresRepository) ReleaseInFlightScheduledMessages() (int64, error) {
+	result, err := r.db.Exec(
+		"UPDATE scheduled_messages SET status = $1, updated_at = $2 WHERE status = $3",
+		domainChatStorage.ScheduledMessagePending, time.Now(), domainChatStorage.ScheduledMessageSending,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// StoreCampaign persists a campaign together with its recipients
+func (r *PostgresRepository) StoreCampaign(campaign *domainChatStorage.Campaign, recipients []*domainChatStorage.CampaignRecipient) error {
+	now := time.Now()
+	if campaign.Status == "" {
+		campaign.Status = domainChatStorage.CampaignRunning
+	}
+	campaign.CreatedAt = now
+	campaign.UpdatedAt = now
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO campaigns (id, device_id, name, message, media_path, media_type, rate_per_minute, min_delay_seconds, max_delay_seconds, validate_numbers, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`, campaign.ID, campaign.DeviceID, campaign.Name, campaign.Message, campaign.MediaPath, campaign.MediaType, campaign.RatePerMinute,
+		campaign.MinDelaySeconds, campaign.MaxDelaySeconds, campaign.ValidateNumbers, campaign.Status, now, now); err != nil {
+		return err
+	}
+
+	// lib/pq does not support LastInsertId, the generated key is read back with RETURNING
+	stmt, err := tx.Prepare(`
+		INSERT INTO campaign_recipients (campaign_id, phone, variables, status, message_id, error, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, recipient := range recipients {
+		recipient.CampaignID = campaign.ID
+		if recipient.Status == "" {
+			recipient.Status = domainChatStorage.CampaignRecipientQueued
+		}
+		if recipient.Variables == "" {
+			recipient.Variables = "{}"
+		}
+		recipient.UpdatedAt = now
+
+		if err := stmt.QueryRow(
+			recipient.CampaignID, recipient.Phone, recipient.Variables, recipient.Status, recipient.MessageID, recipient.Error, now,
+		).Scan(&recipient.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetCampaign retrieves a campaign by ID
+func (r *PostgresRepository) GetCampaign(id string) (*domainChatStorage.Campaign, error) {
+	row := r.db.QueryRow("SELECT "+campaignColumns+" FROM campaigns WHERE id = $1", id)
+	campaign, err := scanCampaign(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return campaign, err
+}
+
+// GetCampaigns retrieves campaigns, newest first
+func (r *PostgresRepository) GetCampaigns(filter *domainChatStorage.CampaignFilter) ([]*domainChatStorage.Campaign, error) {
+	var conditions []string
+	var args postgresArgs
+	query := "SELECT " + campaignColumns + " FROM campaigns"
+
+	if filter.DeviceID != "" {
+		conditions = append(conditions, "device_id = "+args.add(filter.DeviceID))
+	}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = "+args.add(filter.Status))
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY created_at DESC, id DESC"
+	query += postgresPagination(&args, &filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var campaigns []*domainChatStorage.Campaign
+	for rows.Next() {
+		campaign, err := scanCampaign(rows)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, campaign)
+	}
+
+	return campaigns, rows.Err()
+}
+
+// UpdateCampaignStatus moves a campaign to status, only from one of the given states when any are passed
+func (r *PostgresRepository) UpdateCampaignStatus(id string, status string, from ...string) error {
+	var args postgresArgs
+	now := time.Now()
+	query := "UPDATE campaigns SET status = " + args.add(status) + ", updated_at = " + args.add(now)
+
+	if status == domainChatStorage.CampaignCompleted || status == domainChatStorage.CampaignCancelled {
+		query += ", completed_at = " + args.add(now)
+	}
+
+	query += " WHERE id = " + args.add(id)
+
+	if len(from) > 0 {
+		query += " AND status IN (" + postgresList(&args, from) + ")"
+	}
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// GetCampaignRecipients retrieves the recipients of a campaign in the order they are sent to
+func (r *PostgresRepository) GetCampaignRecipients(filter *domainChatStorage.CampaignRecipientFilter) ([]*domainChatStorage.CampaignRecipient, error) {
+	var args postgresArgs
+	query := "SELECT " + campaignRecipientColumns + " FROM campaign_recipients WHERE campaign_id = " + args.add(filter.CampaignID)
+
+	if filter.Status != "" {
+		query += " AND status = " + args.add(filter.Status)
+	}
+
+	query += " ORDER BY id ASC"
+	query += postgresPagination(&args, &filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []*domainChatStorage.CampaignRecipient
+	for rows.Next() {
+		recipient, err := scanCampaignRecipient(rows)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, rows.Err()
+}
+
+// CountCampaignRecipients returns the number of recipients of a campaign per status
+func (r *PostgresRepository) CountCampaignRecipients(campaignID string) (map[string]int, error) {
+	rows, err := r.db.Query("SELECT status, COUNT(*) FROM campaign_recipients WHERE campaign_id = $1 GROUP BY status", campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// MarkCampaignRecipientSending records that the message of a queued campaign recipient is about to be sent
+func (r *PostgresRepository) MarkCampaignRecipientSending(id int64) error {
+	result, err := r.db.Exec(
+		"UPDATE campaign_recipients SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4",
+		domainChatStorage.CampaignRecipientSending, time.Now(), id, domainChatStorage.CampaignRecipientQueued,
+	)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// MarkCampaignRecipientSent records the message ID sent to a campaign recipient
+func (r *PostgresRepository) MarkCampaignRecipientSent(id int64, messageID string) error {
+	now := time.Now()
+	_, err := r.db.Exec(`
+		UPDATE campaign_recipients
+		SET status = $1, message_id = $2, error = '', sent_at = $3, updated_at = $4
+		WHERE id = $5
+	`, domainChatStorage.CampaignRecipientSent, messageID, now, now, id)
+	return err
+}
+
+// MarkCampaignRecipientFailed records why a campaign recipient could not be sent to
+func (r *PostgresRepository) MarkCampaignRecipientFailed(id int64, reason string) error {
+	_, err := r.db.Exec(
+		"UPDATE campaign_recipients SET status = $1, error = $2, updated_at = $3 WHERE id = $4",
+		domainChatStorage.CampaignRecipientFailed, reason, time.Now(), id,
+	)
+	return err
+}
+
+// RequeueCampaignRecipient returns a recipient whose message was not sent to the queue, keeping reason as its last error
+func (r *PostgresRepository) RequeueCampaignRecipient(id int64, reason string) error {
+	_, err := r.db.Exec(
+		"UPDATE campaign_recipients SET status = $1, error = $2, updated_at = $3 WHERE id = $4 AND status = $5",
+		domainChatStorage.CampaignRecipientQueued, reason, time.Now(), id, domainChatStorage.CampaignRecipientSending,
+	)
+	return err
+}
+
+// FlagCampaignRecipientForReview moves a recipient whose send ended without a known outcome to needs review,
+// keeping reason as its last error
+func (r *PostgresRepository) FlagCampaignRecipientForReview(id int64, reason string) error {
+	_, err := r.db.Exec(
+		"UPDATE campaign_recipients SET status = $1, error = $2, updated_at = $3 WHERE id = $4 AND status = $5",
+		domainChatStorage.CampaignRecipientNeedsReview, reason, time.Now(), id, domainChatStorage.CampaignRecipientSending,
+	)
+	return err
+}
+
+// FlagInterruptedCampaignRecipients moves the recipients of a campaign left sending to needs review, since their
+// message may have been sent before the outcome was recorded
+func (r *PostgresRepository) FlagInterruptedCampaignRecipients(campaignID string, reason string) (int64, error) {
+	result, err := r.db.Exec(
+		"UPDATE campaign_recipients SET status = $1, error = $2, updated_at = $3 WHERE campaign_id = $4 AND status = $5",
+		domainChatStorage.CampaignRecipientNeedsReview, reason, time.Now(), campaignID, domainChatStorage.CampaignRecipientSending,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// UpdateCampaignRecipientReceipt advances the recipients of the given messages to delivered or read
+func (r *PostgresRepository) UpdateCampaignRecipientReceipt(messageIDs []string, status string, at time.Time) (int64, error) {
+	sources := campaignReceiptSources(status)
+	if len(messageIDs) == 0 || len(sources) == 0 {
+		return 0, nil
+	}
+
+	var args postgresArgs
+	query := "UPDATE campaign_recipients SET status = " + args.add(status) +
+		", delivered_at = COALESCE(delivered_at, " + args.add(at) + "), updated_at = " + args.add(time.Now())
+
+	if status == domainChatStorage.CampaignRecipientRead {
+		query += ", read_at = " + args.add(at)
+	}
+
+	query += " WHERE message_id IN (" + postgresList(&args, messageIDs) + ")"
+	query += " AND status IN (" + postgresList(&args, sources) + ")"
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const postgresMessageColumns = `id, chat_jid, sender, content, timestamp, is_from_me,
+			media_type, filename, url, media_key, file_sha256,
+			file_enc_sha256, file_length, edited_at, is_revoked, revoked_at,
+			created_at, updated_at`
+
+const postgresMessageColumnsWithAlias = `m.id, m.chat_jid, m.sender, m.content, m.timestamp, m.is_from_me,
+			m.media_type, m.filename, m.url, m.media_key, m.file_sha256,
+			m.file_enc_sha256, m.file_length, m.edited_at, m.is_revoked, m.revoked_at,
+			m.created_at, m.updated_at`
+
+// postgresPagination appends LIMIT and OFFSET placeholders, capping the limit like the SQLite backend
+func postgresPagination(args *postgresArgs, limit *int, offset int) string {
+	if *limit <= 0 {
+		return ""
+	}
+
+	// Validate limit to prevent abuse
+	if *limit > 1000 {
+		*limit = 1000
+	}
+
+	clause := " LIMIT " + args.add(*limit)
+	if offset > 0 {
+		clause += " OFFSET " + args.add(offset)
+	}
+	return clause
+}
+
+// postgresList adds every value as an argument and returns their comma separated placeholders
+func postgresList(args *postgresArgs, values []string) string {
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		placeholders[i] = args.add(value)
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+const postgresAutoReplyRuleUpsert = `
+	INSERT INTO auto_reply_rules (id, name, position, enabled, device_id, conditions, actions, cooldown_seconds, continue_matching, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	ON CONFLICT (id) DO UPDATE SET
+		name = excluded.name,
+		position = excluded.position,
+		enabled = excluded.enabled,
+		device_id = excluded.device_id,
+		conditions = excluded.conditions,
+		actions = excluded.actions,
+		cooldown_seconds = excluded.cooldown_seconds,
+		continue_matching = excluded.continue_matching,
+		updated_at = excluded.updated_at
+`
+
+// StoreAutoReplyRule creates or updates an auto-reply rule
+func (r *PostgresRepository) StoreAutoReplyRule(rule *domainChatStorage.AutoReplyRule) error {
+	prepareAutoReplyRule(rule, time.Now())
+	_, err := r.db.Exec(postgresAutoReplyRuleUpsert, autoReplyRuleArgs(rule)...)
+	return err
+}
+
+// GetAutoReplyRule retrieves an auto-reply rule by ID
+func (r *PostgresRepository) GetAutoReplyRule(id string) (*domainChatStorage.AutoReplyRule, error) {
+	row := r.db.QueryRow("SELECT "+autoReplyRuleColumns+" FROM auto_reply_rules WHERE id = $1", id)
+	rule, err := scanAutoReplyRule(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return rule, err
+}
+
+// GetAutoReplyRules returns all auto-reply rules in evaluation order
+func (r *PostgresRepository) GetAutoReplyRules() ([]*domainChatStorage.AutoReplyRule, error) {
+	rows, err := r.db.Query("SELECT " + autoReplyRuleColumns + " FROM auto_reply_rules ORDER BY position ASC, created_at ASC, id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*domainChatStorage.AutoReplyRule
+	for rows.Next() {
+		rule, err := scanAutoReplyRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// DeleteAutoReplyRule removes an auto-reply rule
+func (r *PostgresRepository) DeleteAutoReplyRule(id string) error {
+	_, err := r.db.Exec("DELETE FROM auto_reply_rules WHERE id = $1", id)
+	return err
+}
+
+// ReplaceAutoReplyRules atomically replaces every auto-reply rule with the given ones
+func (r *PostgresRepository) ReplaceAutoReplyRules(rules []*domainChatStorage.AutoReplyRule) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM auto_reply_rules"); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, rule := range rules {
+		prepareAutoReplyRule(rule, now)
+		if _, err := tx.Exec(postgresAutoReplyRuleUpsert, autoReplyRuleArgs(rule)...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// StoreAPIKey creates an API key
+func (r *PostgresRepository) StoreAPIKey(key *domainChatStorage.APIKey) error {
+	args, err := apiKeyArgs(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec("INSERT INTO api_keys ("+apiKeyColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)", args...)
+	return err
+}
+
+// GetAPIKey retrieves an API key by ID
+func (r *PostgresRepository) GetAPIKey(id string) (*domainChatStorage.APIKey, error) {
+	row := r.db.QueryRow("SELECT "+apiKeyColumns+" FROM api_keys WHERE id = $1", id)
+	key, err := scanAPIKey(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return key, err
+}
+
+// GetAPIKeyByHash retrieves the API key with the given key hash
+func (r *PostgresRepository) GetAPIKeyByHash(keyHash string) (*domainChatStorage.APIKey, error) {
+	row := r.db.QueryRow("SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = $1", keyHash)
+	key, err := scanAPIKey(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return key, err
+}
+
+// GetAPIKeys returns all API keys, oldest first
+func (r *PostgresRepository) GetAPIKeys() ([]*domainChatStorage.APIKey, error) {
+	rows, err := r.db.Query("SELECT " + apiKeyColumns + " FROM api_keys ORDER BY created_at ASC, id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*domainChatStorage.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// CountAPIKeys returns the number of API keys
+func (r *PostgresRepository) CountAPIKeys() (int64, error) {
+	return r.getCount("SELECT COUNT(*) FROM api_keys")
+}
+
+// TouchAPIKey records when an API key was last used
+func (r *PostgresRepository) TouchAPIKey(id string, usedAt time.Time) error {
+	_, err := r.db.Exec("UPDATE api_keys SET last_used_at = $1 WHERE id = $2", usedAt, id)
+	return err
+}
+
+// DeleteAPIKey removes an API key, revoking it immediately
+func (r *PostgresRepository) DeleteAPIKey(id string) error {
+	_, err := r.db.Exec("DELETE FROM api_keys WHERE id = $1", id)
+	return err
+}
+
+// StoreAuditLog appends an entry to the audit log
+func (r *PostgresRepository) StoreAuditLog(entry *domainChatStorage.AuditLog) error {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	return r.db.QueryRow(`
+		INSERT INTO audit_logs (key_id, actor, source, device_id, action, target, status, error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, entry.KeyID, entry.Actor, entry.Source, entry.DeviceID, entry.Action, entry.Target, entry.Status, entry.Error,
+		entry.CreatedAt).Scan(&entry.ID)
+}
+
+// GetAuditLogs returns audit log entries, newest first
+func (r *PostgresRepository) GetAuditLogs(filter *domainChatStorage.AuditLogFilter) ([]*domainChatStorage.AuditLog, error) {
+	var args postgresArgs
+	query := "SELECT " + auditLogColumns + " FROM audit_logs"
+
+	if filter.KeyID != "" {
+		query += " WHERE key_id = " + args.add(filter.KeyID)
+	}
+
+	query += " ORDER BY id DESC"
+	query += postgresPagination(&args, &filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domainChatStorage.AuditLog
+	for rows.Next() {
+		entry, err := scanAuditLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// _____________________________________________________________________________________________________________________
+
+// InitializeSchema creates or migrates the database schema
+func (r *PostgresRepository) InitializeSchema() error {
+	if _, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_info (
+			version INTEGER PRIMARY KEY DEFAULT 0,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)
+	`); err != nil {
+		return err
+	}
+
+	version, err := r.getCount("SELECT COALESCE(MAX(version), 0) FROM schema_info")
+	if err != nil {
+		return err
+	}
+
+	migrations := r.getMigrations()
+	for i := int(version); i < len(migrations); i++ {
+		if err := r.runMigration(migrations[i], i+1); err != nil {
+			return fmt.Errorf("failed to run migration %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// runMigration executes a migration
+func (r *PostgresRepository) runMigration(migration string, version int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(migration); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO schema_info (version) VALUES ($1)
+		ON CONFLICT (version) DO UPDATE SET updated_at = CURRENT_TIMESTAMP
+	`, version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// getMigrations returns all database migrations. Versions line up with the SQLite migrations.
+func (r *PostgresRepository) getMigrations() []string {
+	return []string{
+		// Migration 1: Initial schema with only chats and messages tables
+		`
+		CREATE TABLE IF NOT EXISTS chats (
+			jid TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			last_message_time TIMESTAMPTZ NOT NULL,
+			ephemeral_expiration BIGINT DEFAULT 0,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS messages (
+			id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			sender TEXT NOT NULL,
+			content TEXT,
+			timestamp TIMESTAMPTZ NOT NULL,
+			is_from_me BOOLEAN DEFAULT FALSE,
+			media_type TEXT,
+			filename TEXT,
+			url TEXT,
+			media_key BYTEA,
+			file_sha256 BYTEA,
+			file_enc_sha256 BYTEA,
+			file_length BIGINT DEFAULT 0,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (id, chat_jid),
+			FOREIGN KEY (chat_jid) REFERENCES chats(jid) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_messages_chat_jid ON messages(chat_jid);
+		CREATE INDEX IF NOT EXISTS idx_messages_timestamp ON messages(timestamp);
+		CREATE INDEX IF NOT EXISTS idx_messages_media_type ON messages(media_type);
+		CREATE INDEX IF NOT EXISTS idx_messages_sender ON messages(sender);
+		CREATE INDEX IF NOT EXISTS idx_chats_last_message ON chats(last_message_time);
+		CREATE INDEX IF NOT EXISTS idx_chats_name ON chats(name);
+		`,
+
+		// Migration 2: Add index for message ID lookups (performance optimization)
+		`
+		CREATE INDEX IF NOT EXISTS idx_messages_id ON messages(id);
+		`,
+
+		// Migration 3: Device registry for multi-device sessions
+		`
+		CREATE TABLE IF NOT EXISTS devices (
+			id TEXT PRIMARY KEY,
+			jid TEXT NOT NULL DEFAULT '',
+			webhooks TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		);
+		`,
+
+		// Migration 4: Durable webhook delivery queue
+		`
+		CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id BIGSERIAL PRIMARY KEY,
+			device_id TEXT NOT NULL DEFAULT '',
+			event TEXT NOT NULL DEFAULT '',
+			url TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			next_attempt_at BIGINT NOT NULL,
+			delivered_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+		`,
+
+		// Migration 5: Webhook subscriptions with per-webhook secrets and filters
+		`
+		CREATE TABLE IF NOT EXISTS webhooks (
+			id TEXT PRIMARY KEY,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL DEFAULT '',
+			device_id TEXT NOT NULL DEFAULT '',
+			events TEXT NOT NULL DEFAULT '[]',
+			allow_jids TEXT NOT NULL DEFAULT '[]',
+			deny_jids TEXT NOT NULL DEFAULT '[]',
+			skip_from_me BOOLEAN NOT NULL DEFAULT FALSE,
+			chat_type TEXT NOT NULL DEFAULT 'all',
+			include_media BOOLEAN NOT NULL DEFAULT TRUE,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		);
+
+		ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS webhook_id TEXT NOT NULL DEFAULT '';
+		`,
+
+		// Migration 6: Full-text search index over message content (generated columns need PostgreSQL 12+)
+		`
+		ALTER TABLE messages ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (to_tsvector('simple', COALESCE(content, ''))) STORED;
+
+		CREATE INDEX IF NOT EXISTS idx_messages_search ON messages USING GIN (search_vector);
+		`,
+
+		// Migration 7: Messages scheduled for later delivery
+		`
+		CREATE TABLE IF NOT EXISTS scheduled_messages (
+			id TEXT PRIMARY KEY,
+			device_id TEXT NOT NULL DEFAULT '',
+			type TEXT NOT NULL,
+			phone TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			media_path TEXT NOT NULL DEFAULT '',
+			send_at BIGINT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			message_id TEXT NOT NULL DEFAULT '',
+			next_attempt_at BIGINT NOT NULL,
+			sent_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_scheduled_messages_due ON scheduled_messages(status, next_attempt_at);
+		`,
+
+		// Migration 8: Bulk send campaigns and their recipients
+		`
+		CREATE TABLE IF NOT EXISTS campaigns (
+			id TEXT PRIMARY KEY,
+			device_id TEXT NOT NULL DEFAULT '',
+			name TEXT NOT NULL DEFAULT '',
+			message TEXT NOT NULL DEFAULT '',
+			media_path TEXT NOT NULL DEFAULT '',
+			media_type TEXT NOT NULL DEFAULT '',
+			rate_per_minute INTEGER NOT NULL DEFAULT 0,
+			min_delay_seconds INTEGER NOT NULL DEFAULT 0,
+			max_delay_seconds INTEGER NOT NULL DEFAULT 0,
+			validate_numbers BOOLEAN NOT NULL DEFAULT TRUE,
+			status TEXT NOT NULL DEFAULT 'running',
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			completed_at TIMESTAMPTZ
+		);
+
+		CREATE TABLE IF NOT EXISTS campaign_recipients (
+			id BIGSERIAL PRIMARY KEY,
+			campaign_id TEXT NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+			phone TEXT NOT NULL,
+			variables TEXT NOT NULL DEFAULT '{}',
+			status TEXT NOT NULL DEFAULT 'queued',
+			message_id TEXT NOT NULL DEFAULT '',
+			error TEXT NOT NULL DEFAULT '',
+			sent_at TIMESTAMPTZ,
+			delivered_at TIMESTAMPTZ,
+			read_at TIMESTAMPTZ,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_campaign_recipients_campaign ON campaign_recipients(campaign_id, status, id);
+		CREATE INDEX IF NOT EXISTS idx_campaign_recipients_message ON campaign_recipients(message_id);
+		`,
+
+		// Migration 9: Auto-reply rules
+		`
+		CREATE TABLE IF NOT EXISTS auto_reply_rules (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL DEFAULT '',
+			position INTEGER NOT NULL DEFAULT 0,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			device_id TEXT NOT NULL DEFAULT '',
+			conditions TEXT NOT NULL DEFAULT '{}',
+			actions TEXT NOT NULL DEFAULT '[]',
+			cooldown_seconds INTEGER NOT NULL DEFAULT 0,
+			continue_matching BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_auto_reply_rules_position ON auto_reply_rules(position, created_at);
+		`,
+
+		// Migration 10: API keys and the audit log
+		`
+		CREATE TABLE IF NOT EXISTS api_keys (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			key_hash TEXT NOT NULL UNIQUE,
+			key_prefix TEXT NOT NULL DEFAULT '',
+			scopes TEXT NOT NULL DEFAULT '[]',
+			allowed_recipients TEXT NOT NULL DEFAULT '[]',
+			expires_at TIMESTAMPTZ,
+			last_used_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS audit_logs (
+			id BIGSERIAL PRIMARY KEY,
+			key_id TEXT NOT NULL DEFAULT '',
+			actor TEXT NOT NULL DEFAULT '',
+			source TEXT NOT NULL DEFAULT '',
+			device_id TEXT NOT NULL DEFAULT '',
+			action TEXT NOT NULL,
+			target TEXT NOT NULL DEFAULT '',
+			status INTEGER NOT NULL DEFAULT 0,
+			error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_key ON audit_logs(key_id, id);
+		`,
+
+		// Migration 11: Delivery and read receipts of outgoing messages
+		`
+		CREATE TABLE IF NOT EXISTS message_receipts (
+			message_id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			participant_jid TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			timestamp TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (message_id, chat_jid, participant_jid, status)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_message_receipts_chat ON message_receipts(chat_jid);
+		`,
+
+		// Migration 12: Call log
+		`
+		CREATE TABLE IF NOT EXISTS calls (
+			id TEXT PRIMARY KEY,
+			chat_jid TEXT NOT NULL,
+			caller_jid TEXT NOT NULL DEFAULT '',
+			is_from_me BOOLEAN NOT NULL DEFAULT FALSE,
+			is_video BOOLEAN NOT NULL DEFAULT FALSE,
+			is_group BOOLEAN NOT NULL DEFAULT FALSE,
+			status TEXT NOT NULL,
+			auto_rejected BOOLEAN NOT NULL DEFAULT FALSE,
+			end_reason TEXT NOT NULL DEFAULT '',
+			started_at TIMESTAMPTZ NOT NULL,
+			accepted_at TIMESTAMPTZ,
+			ended_at TIMESTAMPTZ
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_calls_chat ON calls(chat_jid, started_at);
+		CREATE INDEX IF NOT EXISTS idx_calls_started ON calls(started_at);
+		`,
+
+		// Migration 13: Polls and the current vote of each voter
+		`
+		CREATE TABLE IF NOT EXISTS polls (
+			message_id TEXT PRIMARY KEY,
+			chat_jid TEXT NOT NULL,
+			creator_jid TEXT NOT NULL DEFAULT '',
+			question TEXT NOT NULL DEFAULT '',
+			options TEXT NOT NULL DEFAULT '[]',
+			selectable_count INTEGER NOT NULL DEFAULT 0,
+			secret BYTEA,
+			created_at TIMESTAMPTZ NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS poll_votes (
+			poll_id TEXT NOT NULL,
+			voter_jid TEXT NOT NULL,
+			selected_options TEXT NOT NULL DEFAULT '[]',
+			voted_at TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (poll_id, voter_jid)
+		);
+		`,
+
+		// Migration 14: Edit history, reactions and revocation of messages
+		`
+		ALTER TABLE messages ADD COLUMN IF NOT EXISTS edited_at TIMESTAMPTZ;
+		ALTER TABLE messages ADD COLUMN IF NOT EXISTS is_revoked BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE messages ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ;
+
+		CREATE TABLE IF NOT EXISTS message_edits (
+			id TEXT PRIMARY KEY,
+			message_id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			old_content TEXT NOT NULL DEFAULT '',
+			new_content TEXT NOT NULL DEFAULT '',
+			edited_at TIMESTAMPTZ NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_message_edits_message ON message_edits(message_id, chat_jid);
+
+		CREATE TABLE IF NOT EXISTS message_reactions (
+			message_id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			sender_jid TEXT NOT NULL,
+			reaction TEXT NOT NULL,
+			reacted_at TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (message_id, chat_jid, sender_jid)
+		);
+		`,
+
+		// Migration 15: History of group membership and settings changes
+		`
+		CREATE TABLE IF NOT EXISTS group_events (
+			id BIGSERIAL PRIMARY KEY,
+			group_jid TEXT NOT NULL,
+			type TEXT NOT NULL,
+			actor_jid TEXT NOT NULL DEFAULT '',
+			target_jid TEXT NOT NULL DEFAULT '',
+			value TEXT NOT NULL DEFAULT '',
+			timestamp TIMESTAMPTZ NOT NULL,
+			UNIQUE (group_jid, type, target_jid, timestamp)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_group_events_group ON group_events(group_jid, timestamp);
+		`,
+
+		// Migration 16: Bounded log of the events replayed by the event stream
+		`
+		CREATE TABLE IF NOT EXISTS stream_events (
+			id BIGINT PRIMARY KEY,
+			event TEXT NOT NULL,
+			device_id TEXT NOT NULL DEFAULT '',
+			chat_jid TEXT NOT NULL DEFAULT '',
+			data TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL
+		);
+		`,
+	}
+}
@@ -0,0 +1,259 @@
+package chatstorage
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+// postgresTSQuery translates the FTS5 style query accepted by the search API into a tsquery string.
+// Bare words and "quoted phrases" are implicitly ANDed, a trailing * makes a prefix match and
+// AND, OR, NOT and parentheses behave as they do in SQLite. An empty result means nothing to search for.
+func postgresTSQuery(query string) (string, error) {
+	tokens, err := tokenizeSearchQuery(query)
+	if err != nil {
+		return "", err
+	}
+	if len(tokens) == 0 {
+		return "", nil
+	}
+
+	parser := &tsQueryParser{tokens: tokens}
+	result, err := parser.parseOr()
+	if err != nil {
+		return "", err
+	}
+	if parser.pos < len(parser.tokens) {
+		return "", invalidSearchQuery("unexpected %q", parser.tokens[parser.pos].text)
+	}
+	return result, nil
+}
+
+func invalidSearchQuery(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", domainChatStorage.ErrInvalidSearchQuery, fmt.Sprintf(format, args...))
+}
+
+type searchTokenKind int
+
+const (
+	searchTokenTerm searchTokenKind = iota
+	searchTokenAnd
+	searchTokenOr
+	searchTokenNot
+	searchTokenOpen
+	searchTokenClose
+)
+
+type searchToken struct {
+	kind   searchTokenKind
+	text   string
+	prefix bool
+}
+
+func tokenizeSearchQuery(query string) ([]searchToken, error) {
+	var tokens []searchToken
+	runes := []rune(query)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, searchToken{kind: searchTokenOpen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, searchToken{kind: searchTokenClose, text: ")"})
+			i++
+		case r == '"':
+			var phrase strings.Builder
+			closed := false
+			for i++; i < len(runes); i++ {
+				if runes[i] == '"' {
+					// A doubled quote is an escaped quote inside the phrase
+					if i+1 < len(runes) && runes[i+1] == '"' {
+						phrase.WriteRune('"')
+						i++
+						continue
+					}
+					closed = true
+					i++
+					break
+				}
+				phrase.WriteRune(runes[i])
+			}
+			if !closed {
+				return nil, invalidSearchQuery("unterminated string")
+			}
+			token := searchToken{kind: searchTokenTerm, text: phrase.String()}
+			if i < len(runes) && runes[i] == '*' {
+				token.prefix = true
+				i++
+			}
+			tokens = append(tokens, token)
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`()"`, runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			switch word {
+			case "AND":
+				tokens = append(tokens, searchToken{kind: searchTokenAnd, text: word})
+			case "OR":
+				tokens = append(tokens, searchToken{kind: searchTokenOr, text: word})
+			case "NOT":
+				tokens = append(tokens, searchToken{kind: searchTokenNot, text: word})
+			default:
+				token := searchToken{kind: searchTokenTerm, text: word}
+				if strings.HasSuffix(word, "*") {
+					token.text = strings.TrimRight(word, "*")
+					token.prefix = true
+				}
+				tokens = append(tokens, token)
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+type tsQueryParser struct {
+	tokens []searchToken
+	pos    int
+}
+
+func (p *tsQueryParser) peek() (searchToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return searchToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *tsQueryParser) parseOr() (string, error) {
+	var parts []string
+	for {
+		part, err := p.parseAnd()
+		if err != nil {
+			return "", err
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+
+		token, ok := p.peek()
+		if !ok || token.kind != searchTokenOr {
+			break
+		}
+		p.pos++
+	}
+
+	if len(parts) > 1 {
+		return "(" + strings.Join(parts, " | ") + ")", nil
+	}
+	return strings.Join(parts, ""), nil
+}
+
+func (p *tsQueryParser) parseAnd() (string, error) {
+	var result string
+	start := p.pos
+	for {
+		token, ok := p.peek()
+		if !ok || token.kind == searchTokenOr || token.kind == searchTokenClose {
+			if p.pos == start {
+				if !ok {
+					return "", invalidSearchQuery("unexpected end of query")
+				}
+				return "", invalidSearchQuery("unexpected %q", token.text)
+			}
+			return result, nil
+		}
+
+		negate := false
+		switch token.kind {
+		case searchTokenAnd:
+			if p.pos == start {
+				return "", invalidSearchQuery("unexpected %q", token.text)
+			}
+			p.pos++
+			if next, ok := p.peek(); ok && next.kind == searchTokenNot {
+				negate = true
+				p.pos++
+			}
+		case searchTokenNot:
+			negate = true
+			p.pos++
+		}
+
+		operand, err := p.parseOperand()
+		if err != nil {
+			return "", err
+		}
+		if operand == "" {
+			continue
+		}
+		if negate {
+			operand = "!" + operand
+		}
+		if result == "" {
+			result = operand
+		} else {
+			result += " & " + operand
+		}
+	}
+}
+
+func (p *tsQueryParser) parseOperand() (string, error) {
+	token, ok := p.peek()
+	if !ok {
+		return "", invalidSearchQuery("unexpected end of query")
+	}
+
+	switch token.kind {
+	case searchTokenOpen:
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return "", err
+		}
+		closing, ok := p.peek()
+		if !ok || closing.kind != searchTokenClose {
+			return "", invalidSearchQuery("missing closing parenthesis")
+		}
+		p.pos++
+		if inner == "" {
+			return "", nil
+		}
+		return "(" + inner + ")", nil
+	case searchTokenTerm:
+		p.pos++
+		return tsQueryPhrase(token.text, token.prefix), nil
+	default:
+		return "", invalidSearchQuery("unexpected %q", token.text)
+	}
+}
+
+// tsQueryPhrase splits text into lexemes the way the 'simple' configuration does and
+// chains them with the followed-by operator so multi-word terms keep their order
+func tsQueryPhrase(text string, prefix bool) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+
+	for i, word := range words {
+		words[i] = "'" + word + "'"
+	}
+	if prefix {
+		words[len(words)-1] += ":*"
+	}
+
+	if len(words) == 1 {
+		return words[0]
+	}
+	return "(" + strings.Join(words, " <-> ") + ")"
+}
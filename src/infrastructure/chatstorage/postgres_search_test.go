@@ -0,0 +1,52 @@
+package chatstorage
+
+import (
+	"errors"
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresTSQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty", query: "   ", want: ""},
+		{name: "single word", query: "Invoice", want: "'invoice'"},
+		{name: "implicit and", query: "invoice paid", want: "'invoice' & 'paid'"},
+		{name: "prefix", query: "inv*", want: "'inv':*"},
+		{name: "phrase", query: `"monthly invoice"`, want: "('monthly' <-> 'invoice')"},
+		{name: "phrase prefix", query: `"monthly inv"*`, want: "('monthly' <-> 'inv':*)"},
+		{name: "escaped quote", query: `"it""s"`, want: "('it' <-> 's')"},
+		{name: "or", query: "invoice OR receipt", want: "('invoice' | 'receipt')"},
+		{name: "explicit and", query: "invoice AND paid", want: "'invoice' & 'paid'"},
+		{name: "not", query: "invoice NOT draft", want: "'invoice' & !'draft'"},
+		{name: "and not", query: "invoice AND NOT draft", want: "'invoice' & !'draft'"},
+		{name: "leading not", query: "NOT draft", want: "!'draft'"},
+		{name: "groups", query: "(invoice OR receipt) paid", want: "(('invoice' | 'receipt')) & 'paid'"},
+		{name: "punctuation", query: "o'brien", want: "('o' <-> 'brien')"},
+		{name: "literal search query", query: literalSearchQuery(`say "hi"`), want: "'say':* & 'hi':*"},
+		{name: "unterminated string", query: `"invoice`, wantErr: true},
+		{name: "missing closing parenthesis", query: "(invoice", wantErr: true},
+		{name: "unexpected closing parenthesis", query: "invoice)", wantErr: true},
+		{name: "dangling or", query: "invoice OR", wantErr: true},
+		{name: "leading and", query: "AND invoice", wantErr: true},
+		{name: "dangling not", query: "invoice NOT", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := postgresTSQuery(tt.query)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, domainChatStorage.ErrInvalidSearchQuery), "expected ErrInvalidSearchQuery, got %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,748 @@
+package chatstorage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// Behaviour shared by the SQLite and PostgreSQL repositories. Everything here goes through the
+// repository interface so that each backend only has to implement its own SQL dialect.
+
+// Markers wrapped around matched terms in search snippets
+const (
+	searchHighlightStart = "<mark>"
+	searchHighlightEnd   = "</mark>"
+)
+
+// NewRepository opens the chat storage matching the URI scheme: postgres:// or postgresql://
+// for PostgreSQL and file: for SQLite. Its operations are timed for the query latency metrics.
+func NewRepository(db *sql.DB, uri string) domainChatStorage.IChatStorageRepository {
+	if IsPostgresURI(uri) {
+		return NewInstrumentedRepository(NewPostgresRepository(db))
+	}
+	return NewInstrumentedRepository(NewStorageRepository(db))
+}
+
+// IsPostgresURI reports whether a chat storage URI points to PostgreSQL
+func IsPostgresURI(uri string) bool {
+	return strings.HasPrefix(uri, "postgres://") || strings.HasPrefix(uri, "postgresql://")
+}
+
+// chatNameWithPushName determines the appropriate name for a chat with pushname support
+func chatNameWithPushName(repo domainChatStorage.IChatStorageRepository, jid types.JID, chatJID string, senderUser string, pushName string) string {
+	// First, check if chat already exists with a name
+	existingChat, err := repo.GetChat(chatJID)
+	if err == nil && existingChat != nil && existingChat.Name != "" {
+		// If we have a pushname and the existing name is just a phone number/JID user, update it
+		if pushName != "" && (existingChat.Name == jid.User || existingChat.Name == senderUser) {
+			return pushName
+		}
+		return existingChat.Name
+	}
+
+	// Determine chat type and name
+	var name string
+
+	switch jid.Server {
+	case "g.us":
+		// This is a group chat
+		// For now, use a generic name - this can be enhanced later with group info
+		name = fmt.Sprintf("Group %s", jid.User)
+	case "newsletter":
+		// This is a newsletter/channel
+		name = fmt.Sprintf("Newsletter %s", jid.User)
+	default:
+		// This is an individual contact
+		// Priority: pushName > senderUser > JID user
+		if pushName != "" && pushName != senderUser && pushName != jid.User {
+			name = pushName
+		} else if senderUser != "" {
+			name = senderUser
+		} else {
+			name = jid.User
+		}
+	}
+
+	return name
+}
+
+// createMessage stores the chat and message of an incoming message event
+func createMessage(repo domainChatStorage.IChatStorageRepository, evt *events.Message) error {
+	if evt == nil || evt.Message == nil {
+		return nil
+	}
+
+	// Extract chat and sender information
+	chatJID := evt.Info.Chat.String()
+	// Store the full sender JID (user@server) to ensure consistency between received and sent messages
+	sender := evt.Info.Sender.String()
+
+	// Edits, revocations and reactions change a stored message instead of adding one
+	if protocolMessage := evt.Message.GetProtocolMessage(); protocolMessage != nil {
+		messageID := protocolMessage.GetKey().GetID()
+		switch protocolMessage.GetType() {
+		case waE2E.ProtocolMessage_MESSAGE_EDIT:
+			return repo.StoreMessageEdit(&domainChatStorage.MessageEdit{
+				ID:         evt.Info.ID,
+				MessageID:  messageID,
+				ChatJID:    chatJID,
+				NewContent: utils.ExtractMessageTextFromProto(protocolMessage.GetEditedMessage()),
+				EditedAt:   evt.Info.Timestamp,
+			})
+		case waE2E.ProtocolMessage_REVOKE:
+			return repo.RevokeMessage(messageID, chatJID, evt.Info.Timestamp)
+		}
+		return nil
+	}
+	if reaction := evt.Message.GetReactionMessage(); reaction != nil {
+		return repo.StoreMessageReaction(&domainChatStorage.MessageReaction{
+			MessageID: reaction.GetKey().GetID(),
+			ChatJID:   chatJID,
+			SenderJID: evt.Info.Sender.ToNonAD().String(),
+			Reaction:  reaction.GetText(),
+			ReactedAt: evt.Info.Timestamp,
+		})
+	}
+
+	// Get appropriate chat name using pushname if available
+	chatName := repo.GetChatNameWithPushName(evt.Info.Chat, chatJID, evt.Info.Sender.User, evt.Info.PushName)
+
+	// Get existing chat to preserve ephemeral_expiration if needed
+	existingChat, err := repo.GetChat(chatJID)
+	if err != nil {
+		return fmt.Errorf("failed to get existing chat: %w", err)
+	}
+
+	// Extract ephemeral expiration from incoming message
+	ephemeralExpiration := utils.ExtractEphemeralExpiration(evt.Message)
+
+	// Create or update chat
+	chat := &domainChatStorage.Chat{
+		JID:             chatJID,
+		Name:            chatName,
+		LastMessageTime: evt.Info.Timestamp,
+	}
+
+	// Set ephemeral expiration: use incoming message value if > 0, otherwise preserve existing
+	if ephemeralExpiration > 0 {
+		chat.EphemeralExpiration = ephemeralExpiration
+	} else if existingChat != nil {
+		// Preserve existing ephemeral_expiration if incoming message doesn't have one
+		chat.EphemeralExpiration = existingChat.EphemeralExpiration
+	}
+
+	// Store or update the chat
+	if err := repo.StoreChat(chat); err != nil {
+		return fmt.Errorf("failed to store chat: %w", err)
+	}
+
+	// Extract message content and media info
+	content := utils.ExtractMessageTextFromProto(evt.Message)
+	mediaType, filename, url, mediaKey, fileSHA256, fileEncSHA256, fileLength := utils.ExtractMediaInfo(evt.Message)
+
+	// Skip if there's no content and no media
+	if content == "" && mediaType == "" {
+		logrus.Debugf("Skipping message %s - no content or media", evt.Info.ID)
+		return nil
+	}
+
+	// Create message object
+	message := &domainChatStorage.Message{
+		ID:            evt.Info.ID,
+		ChatJID:       chatJID,
+		Sender:        sender,
+		Content:       content,
+		Timestamp:     evt.Info.Timestamp,
+		IsFromMe:      evt.Info.IsFromMe,
+		MediaType:     mediaType,
+		Filename:      filename,
+		URL:           url,
+		MediaKey:      mediaKey,
+		FileSHA256:    fileSHA256,
+		FileEncSHA256: fileEncSHA256,
+		FileLength:    fileLength,
+	}
+
+	// Store the message
+	return repo.StoreMessage(message)
+}
+
+// storageStatistics returns current storage statistics for logging purposes
+func storageStatistics(repo domainChatStorage.IChatStorageRepository) (chatCount int64, messageCount int64, err error) {
+	// Count all chats using efficient query
+	chatCount, err = repo.GetTotalChatCount()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get chat count: %w", err)
+	}
+
+	// Count all messages
+	messageCount, err = repo.GetTotalMessageCount()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get message count: %w", err)
+	}
+
+	return chatCount, messageCount, nil
+}
+
+// truncateAllDataWithLogging performs truncation with detailed logging
+func truncateAllDataWithLogging(repo domainChatStorage.IChatStorageRepository, logPrefix string) error {
+	// Get statistics before truncation
+	chatCount, messageCount, err := repo.GetStorageStatistics()
+	if err != nil {
+		logrus.Warnf("[%s] Failed to get storage statistics before truncation: %v", logPrefix, err)
+	} else {
+		logrus.Infof("[%s] Storage before truncation: %d chats, %d messages", logPrefix, chatCount, messageCount)
+	}
+
+	// Perform truncation
+	if err := repo.TruncateAllChats(); err != nil {
+		return fmt.Errorf("failed to truncate chatstorage data: %w", err)
+	}
+
+	// Verify truncation
+	chatCountAfter, messageCountAfter, err := repo.GetStorageStatistics()
+	if err != nil {
+		logrus.Warnf("[%s] Failed to get storage statistics after truncation: %v", logPrefix, err)
+	} else {
+		logrus.Infof("[%s] Storage after truncation: %d chats, %d messages", logPrefix, chatCountAfter, messageCountAfter)
+		if chatCountAfter == 0 && messageCountAfter == 0 {
+			logrus.Infof("[%s] ✅ Chatstorage truncation completed successfully", logPrefix)
+		} else {
+			logrus.Warnf("[%s] ⚠️ Truncation may not have completed fully", logPrefix)
+		}
+	}
+
+	return nil
+}
+
+// storeSentMessage stores a message that was sent by the user with context cancellation support
+func storeSentMessage(ctx context.Context, repo domainChatStorage.IChatStorageRepository, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error {
+	// Check if context is already cancelled before starting
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	// Ensure JID is properly formatted
+	jid, err := types.ParseJID(recipientJID)
+	if err != nil {
+		return fmt.Errorf("invalid JID format: %w", err)
+	}
+
+	chatJID := jid.String()
+
+	// Get chat name (no pushname available for sent messages)
+	chatName := repo.GetChatNameWithPushName(jid, chatJID, jid.User, "")
+
+	// Check context again before database operations
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	// Get existing chat to preserve ephemeral_expiration
+	existingChat, err := repo.GetChat(chatJID)
+	if err != nil {
+		return fmt.Errorf("failed to get existing chat: %w", err)
+	}
+
+	// Store or update chat, preserving existing ephemeral_expiration
+	chat := &domainChatStorage.Chat{
+		JID:             chatJID,
+		Name:            chatName,
+		LastMessageTime: timestamp,
+	}
+
+	// Preserve existing ephemeral_expiration if chat exists
+	if existingChat != nil {
+		chat.EphemeralExpiration = existingChat.EphemeralExpiration
+	}
+
+	if err := repo.StoreChat(chat); err != nil {
+		return fmt.Errorf("failed to store chat: %w", err)
+	}
+
+	// Check context one more time before storing message
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	// Store the sent message
+	message := &domainChatStorage.Message{
+		ID:        messageID,
+		ChatJID:   chatJID,
+		Sender:    senderJID,
+		Content:   content,
+		Timestamp: timestamp,
+		IsFromMe:  true,
+	}
+
+	return repo.StoreMessage(message)
+}
+
+// scanMessage is a private helper for scanning message rows
+func scanMessage(scanner interface{ Scan(...any) error }, extra ...any) (*domainChatStorage.Message, error) {
+	message := &domainChatStorage.Message{}
+	var editedAt, revokedAt sql.NullTime
+	dest := append([]any{
+		&message.ID, &message.ChatJID, &message.Sender, &message.Content,
+		&message.Timestamp, &message.IsFromMe, &message.MediaType, &message.Filename,
+		&message.URL, &message.MediaKey, &message.FileSHA256, &message.FileEncSHA256,
+		&message.FileLength, &editedAt, &message.IsRevoked, &revokedAt,
+		&message.CreatedAt, &message.UpdatedAt,
+	}, extra...)
+	if err := scanner.Scan(dest...); err != nil {
+		return message, err
+	}
+
+	if editedAt.Valid {
+		message.EditedAt = &editedAt.Time
+	}
+	if revokedAt.Valid {
+		message.RevokedAt = &revokedAt.Time
+	}
+	return message, nil
+}
+
+const messageEditColumns = `id, message_id, chat_jid, old_content, new_content, edited_at`
+
+// scanMessageEdit is a private helper for scanning message edit rows
+func scanMessageEdit(scanner interface{ Scan(...any) error }) (*domainChatStorage.MessageEdit, error) {
+	edit := &domainChatStorage.MessageEdit{}
+	err := scanner.Scan(&edit.ID, &edit.MessageID, &edit.ChatJID, &edit.OldContent, &edit.NewContent, &edit.EditedAt)
+	return edit, err
+}
+
+const messageReactionColumns = `message_id, chat_jid, sender_jid, reaction, reacted_at`
+
+// scanMessageReaction is a private helper for scanning message reaction rows
+func scanMessageReaction(scanner interface{ Scan(...any) error }) (*domainChatStorage.MessageReaction, error) {
+	reaction := &domainChatStorage.MessageReaction{}
+	err := scanner.Scan(&reaction.MessageID, &reaction.ChatJID, &reaction.SenderJID, &reaction.Reaction, &reaction.ReactedAt)
+	return reaction, err
+}
+
+const messageReceiptColumns = `message_id, chat_jid, participant_jid, status, timestamp`
+
+// scanMessageReceipt is a private helper for scanning message receipt rows
+func scanMessageReceipt(scanner interface{ Scan(...any) error }) (*domainChatStorage.MessageReceipt, error) {
+	receipt := &domainChatStorage.MessageReceipt{}
+	err := scanner.Scan(&receipt.MessageID, &receipt.ChatJID, &receipt.ParticipantJID, &receipt.Status, &receipt.Timestamp)
+	return receipt, err
+}
+
+const callColumns = `id, chat_jid, caller_jid, is_from_me, is_video, is_group, status, auto_rejected, end_reason,
+	started_at, accepted_at, ended_at`
+
+// scanCall is a private helper for scanning call log rows
+func scanCall(scanner interface{ Scan(...any) error }) (*domainChatStorage.Call, error) {
+	call := &domainChatStorage.Call{}
+	var acceptedAt, endedAt sql.NullTime
+
+	err := scanner.Scan(
+		&call.ID, &call.ChatJID, &call.CallerJID, &call.IsFromMe, &call.IsVideo, &call.IsGroup, &call.Status,
+		&call.AutoRejected, &call.EndReason, &call.StartedAt, &acceptedAt, &endedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if acceptedAt.Valid {
+		call.AcceptedAt = &acceptedAt.Time
+	}
+	if endedAt.Valid {
+		call.EndedAt = &endedAt.Time
+	}
+	return call, nil
+}
+
+// callArgs returns the values of a call in callColumns order
+func callArgs(call *domainChatStorage.Call) []any {
+	return []any{
+		call.ID, call.ChatJID, call.CallerJID, call.IsFromMe, call.IsVideo, call.IsGroup, call.Status,
+		call.AutoRejected, call.EndReason, call.StartedAt, call.AcceptedAt, call.EndedAt,
+	}
+}
+
+const pollColumns = `message_id, chat_jid, creator_jid, question, options, selectable_count, secret, created_at`
+
+func scanPoll(scanner interface{ Scan(...any) error }) (*domainChatStorage.Poll, error) {
+	poll := &domainChatStorage.Poll{}
+	var options string
+	err := scanner.Scan(
+		&poll.MessageID, &poll.ChatJID, &poll.CreatorJID, &poll.Question, &options, &poll.SelectableCount,
+		&poll.Secret, &poll.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(options), &poll.Options); err != nil {
+		return nil, err
+	}
+	return poll, nil
+}
+
+// pollArgs returns the values of a poll in pollColumns order, with its options encoded as a JSON array
+func pollArgs(poll *domainChatStorage.Poll) ([]any, error) {
+	if poll.CreatedAt.IsZero() {
+		poll.CreatedAt = time.Now()
+	}
+
+	options, err := json.Marshal(nonNilStrings(poll.Options))
+	if err != nil {
+		return nil, err
+	}
+	// An unknown secret is stored as NULL so it does not replace a known one
+	var secret any
+	if len(poll.Secret) > 0 {
+		secret = poll.Secret
+	}
+
+	return []any{
+		poll.MessageID, poll.ChatJID, poll.CreatorJID, poll.Question, string(options), poll.SelectableCount,
+		secret, poll.CreatedAt,
+	}, nil
+}
+
+const pollVoteColumns = `poll_id, voter_jid, selected_options, voted_at`
+
+func scanPollVote(scanner interface{ Scan(...any) error }) (*domainChatStorage.PollVote, error) {
+	vote := &domainChatStorage.PollVote{}
+	var selectedOptions string
+	if err := scanner.Scan(&vote.PollID, &vote.VoterJID, &selectedOptions, &vote.VotedAt); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(selectedOptions), &vote.SelectedOptions); err != nil {
+		return nil, err
+	}
+	return vote, nil
+}
+
+// pollVoteArgs returns the values of a vote in pollVoteColumns order
+func pollVoteArgs(vote *domainChatStorage.PollVote) ([]any, error) {
+	selectedOptions, err := json.Marshal(nonNilStrings(vote.SelectedOptions))
+	if err != nil {
+		return nil, err
+	}
+	return []any{vote.PollID, vote.VoterJID, string(selectedOptions), vote.VotedAt}, nil
+}
+
+const groupEventColumns = `group_jid, type, actor_jid, target_jid, value, timestamp`
+
+// scanGroupEvent is a private helper for scanning group event rows
+func scanGroupEvent(scanner interface{ Scan(...any) error }) (*domainChatStorage.GroupEvent, error) {
+	event := &domainChatStorage.GroupEvent{}
+	err := scanner.Scan(&event.ID, &event.GroupJID, &event.Type, &event.ActorJID, &event.TargetJID, &event.Value, &event.Timestamp)
+	return event, err
+}
+
+const streamEventColumns = `id, event, device_id, chat_jid, data, created_at`
+
+// scanStreamEvent is a private helper for scanning event stream log rows
+func scanStreamEvent(scanner interface{ Scan(...any) error }) (*domainChatStorage.StreamEvent, error) {
+	event := &domainChatStorage.StreamEvent{}
+	err := scanner.Scan(&event.ID, &event.Event, &event.DeviceID, &event.ChatJID, &event.Data, &event.CreatedAt)
+	return event, err
+}
+
+// scanChat is a private helper for scanning chat rows
+func scanChat(scanner interface{ Scan(...any) error }) (*domainChatStorage.Chat, error) {
+	chat := &domainChatStorage.Chat{}
+	err := scanner.Scan(
+		&chat.JID, &chat.Name, &chat.LastMessageTime, &chat.EphemeralExpiration,
+		&chat.CreatedAt, &chat.UpdatedAt,
+	)
+	return chat, err
+}
+
+const webhookSubscriptionColumns = `id, url, secret, device_id, events, allow_jids, deny_jids, skip_from_me, chat_type, include_media, enabled, created_at, updated_at`
+
+// encodeWebhookSubscriptionLists serializes the list filters of a subscription as JSON arrays
+func encodeWebhookSubscriptionLists(subscription *domainChatStorage.WebhookSubscription) (events, allowJIDs, denyJIDs string, err error) {
+	encoded := make([]string, 0, 3)
+	for _, values := range [][]string{subscription.Events, subscription.AllowJIDs, subscription.DenyJIDs} {
+		data, err := json.Marshal(nonNilStrings(values))
+		if err != nil {
+			return "", "", "", err
+		}
+		encoded = append(encoded, string(data))
+	}
+	return encoded[0], encoded[1], encoded[2], nil
+}
+
+func scanWebhookSubscription(scanner interface{ Scan(...any) error }) (*domainChatStorage.WebhookSubscription, error) {
+	subscription := &domainChatStorage.WebhookSubscription{}
+	var events, allowJIDs, denyJIDs string
+
+	err := scanner.Scan(
+		&subscription.ID, &subscription.URL, &subscription.Secret, &subscription.DeviceID, &events, &allowJIDs, &denyJIDs,
+		&subscription.SkipFromMe, &subscription.ChatType, &subscription.IncludeMedia, &subscription.Enabled,
+		&subscription.CreatedAt, &subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(events), &subscription.Events); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(allowJIDs), &subscription.AllowJIDs); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(denyJIDs), &subscription.DenyJIDs); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+const webhookDeliveryColumns = `id, webhook_id, device_id, event, url, payload, status, attempts, last_error, next_attempt_at, delivered_at, created_at, updated_at`
+
+func scanWebhookDelivery(scanner interface{ Scan(...any) error }) (*domainChatStorage.WebhookDelivery, error) {
+	delivery := &domainChatStorage.WebhookDelivery{}
+	var nextAttemptAt int64
+	var deliveredAt sql.NullTime
+
+	err := scanner.Scan(
+		&delivery.ID, &delivery.WebhookID, &delivery.DeviceID, &delivery.Event, &delivery.URL, &delivery.Payload, &delivery.Status,
+		&delivery.Attempts, &delivery.LastError, &nextAttemptAt, &deliveredAt, &delivery.CreatedAt, &delivery.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.NextAttemptAt = time.UnixMilli(nextAttemptAt)
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	return delivery, nil
+}
+
+// requireAffected turns an update that matched no row into sql.ErrNoRows
+func requireAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+const scheduledMessageColumns = `id, device_id, type, phone, payload, media_path, send_at, status, attempts, last_error, message_id, next_attempt_at, sent_at, created_at, updated_at`
+
+func scanScheduledMessage(scanner interface{ Scan(...any) error }) (*domainChatStorage.ScheduledMessage, error) {
+	message := &domainChatStorage.ScheduledMessage{}
+	var sendAt, nextAttemptAt int64
+	var sentAt sql.NullTime
+
+	err := scanner.Scan(
+		&message.ID, &message.DeviceID, &message.Type, &message.Phone, &message.Payload, &message.MediaPath, &sendAt,
+		&message.Status, &message.Attempts, &message.LastError, &message.MessageID, &nextAttemptAt, &sentAt,
+		&message.CreatedAt, &message.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	message.SendAt = time.UnixMilli(sendAt)
+	message.NextAttemptAt = time.UnixMilli(nextAttemptAt)
+	if sentAt.Valid {
+		message.SentAt = &sentAt.Time
+	}
+	return message, nil
+}
+
+const campaignColumns = `id, device_id, name, message, media_path, media_type, rate_per_minute, min_delay_seconds, max_delay_seconds, validate_numbers, status, created_at, updated_at, completed_at`
+
+func scanCampaign(scanner interface{ Scan(...any) error }) (*domainChatStorage.Campaign, error) {
+	campaign := &domainChatStorage.Campaign{}
+	var completedAt sql.NullTime
+
+	err := scanner.Scan(
+		&campaign.ID, &campaign.DeviceID, &campaign.Name, &campaign.Message, &campaign.MediaPath, &campaign.MediaType,
+		&campaign.RatePerMinute, &campaign.MinDelaySeconds, &campaign.MaxDelaySeconds, &campaign.ValidateNumbers,
+		&campaign.Status, &campaign.CreatedAt, &campaign.UpdatedAt, &completedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if completedAt.Valid {
+		campaign.CompletedAt = &completedAt.Time
+	}
+	return campaign, nil
+}
+
+const campaignRecipientColumns = `id, campaign_id, phone, variables, status, message_id, error, sent_at, delivered_at, read_at, updated_at`
+
+func scanCampaignRecipient(scanner interface{ Scan(...any) error }) (*domainChatStorage.CampaignRecipient, error) {
+	recipient := &domainChatStorage.CampaignRecipient{}
+	var sentAt, deliveredAt, readAt sql.NullTime
+
+	err := scanner.Scan(
+		&recipient.ID, &recipient.CampaignID, &recipient.Phone, &recipient.Variables, &recipient.Status, &recipient.MessageID,
+		&recipient.Error, &sentAt, &deliveredAt, &readAt, &recipient.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if sentAt.Valid {
+		recipient.SentAt = &sentAt.Time
+	}
+	if deliveredAt.Valid {
+		recipient.DeliveredAt = &deliveredAt.Time
+	}
+	if readAt.Valid {
+		recipient.ReadAt = &readAt.Time
+	}
+	return recipient, nil
+}
+
+// campaignReceiptSources lists the recipient states a receipt may advance, so a late
+// delivery receipt never moves a message that was already read back to delivered
+func campaignReceiptSources(status string) []string {
+	switch status {
+	case domainChatStorage.CampaignRecipientDelivered:
+		return []string{domainChatStorage.CampaignRecipientSent}
+	case domainChatStorage.CampaignRecipientRead:
+		return []string{domainChatStorage.CampaignRecipientSent, domainChatStorage.CampaignRecipientDelivered}
+	default:
+		return nil
+	}
+}
+
+const autoReplyRuleColumns = `id, name, position, enabled, device_id, conditions, actions, cooldown_seconds, continue_matching, created_at, updated_at`
+
+func scanAutoReplyRule(scanner interface{ Scan(...any) error }) (*domainChatStorage.AutoReplyRule, error) {
+	rule := &domainChatStorage.AutoReplyRule{}
+	err := scanner.Scan(
+		&rule.ID, &rule.Name, &rule.Position, &rule.Enabled, &rule.DeviceID, &rule.Conditions, &rule.Actions,
+		&rule.CooldownSeconds, &rule.ContinueMatching, &rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// autoReplyRuleArgs returns the values of a rule in autoReplyRuleColumns order
+func autoReplyRuleArgs(rule *domainChatStorage.AutoReplyRule) []any {
+	return []any{
+		rule.ID, rule.Name, rule.Position, rule.Enabled, rule.DeviceID, rule.Conditions, rule.Actions,
+		rule.CooldownSeconds, rule.ContinueMatching, rule.CreatedAt, rule.UpdatedAt,
+	}
+}
+
+// prepareAutoReplyRule fills the defaults of a rule before it is written
+func prepareAutoReplyRule(rule *domainChatStorage.AutoReplyRule, now time.Time) {
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = now
+	}
+	rule.UpdatedAt = now
+	if rule.Conditions == "" {
+		rule.Conditions = "{}"
+	}
+	if rule.Actions == "" {
+		rule.Actions = "[]"
+	}
+}
+
+const apiKeyColumns = `id, name, key_hash, key_prefix, scopes, allowed_recipients, expires_at, last_used_at, created_at`
+
+func scanAPIKey(scanner interface{ Scan(...any) error }) (*domainChatStorage.APIKey, error) {
+	key := &domainChatStorage.APIKey{}
+	var scopes, allowedRecipients string
+	var expiresAt, lastUsedAt sql.NullTime
+
+	err := scanner.Scan(
+		&key.ID, &key.Name, &key.KeyHash, &key.KeyPrefix, &scopes, &allowedRecipients,
+		&expiresAt, &lastUsedAt, &key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(scopes), &key.Scopes); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(allowedRecipients), &key.AllowedRecipients); err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	return key, nil
+}
+
+// apiKeyArgs returns the values of a key in apiKeyColumns order, with its lists encoded as JSON arrays
+func apiKeyArgs(key *domainChatStorage.APIKey) ([]any, error) {
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+
+	scopes, err := json.Marshal(nonNilStrings(key.Scopes))
+	if err != nil {
+		return nil, err
+	}
+	allowedRecipients, err := json.Marshal(nonNilStrings(key.AllowedRecipients))
+	if err != nil {
+		return nil, err
+	}
+
+	return []any{
+		key.ID, key.Name, key.KeyHash, key.KeyPrefix, string(scopes), string(allowedRecipients),
+		key.ExpiresAt, key.LastUsedAt, key.CreatedAt,
+	}, nil
+}
+
+const auditLogColumns = `id, key_id, actor, source, device_id, action, target, status, error, created_at`
+
+func scanAuditLog(scanner interface{ Scan(...any) error }) (*domainChatStorage.AuditLog, error) {
+	entry := &domainChatStorage.AuditLog{}
+	err := scanner.Scan(
+		&entry.ID, &entry.KeyID, &entry.Actor, &entry.Source, &entry.DeviceID, &entry.Action, &entry.Target,
+		&entry.Status, &entry.Error, &entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// literalSearchQuery turns plain text into a search query matching every word as a prefix
+func literalSearchQuery(text string) string {
+	words := strings.Fields(text)
+	for i, word := range words {
+		words[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"*`
+	}
+	return strings.Join(words, " ")
+}
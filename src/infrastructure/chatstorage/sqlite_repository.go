@@ -4,11 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
+	"unicode"
 
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
@@ -51,7 +52,7 @@ func (r *SQLiteRepository) GetChat(jid string) (*domainChatStorage.Chat, error)
 		WHERE jid = ?
 	`
 
-	chat, err := r.scanChat(r.db.QueryRow(query, jid))
+	chat, err := scanChat(r.db.QueryRow(query, jid))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -65,13 +66,14 @@ func (r *SQLiteRepository) GetMessageByID(id string) (*domainChatStorage.Message
 	query := `
 		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
 			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+			file_enc_sha256, file_length, edited_at, is_revoked, revoked_at,
+			created_at, updated_at
 		FROM messages
 		WHERE id = ?
 		LIMIT 1
 	`
 
-	message, err := r.scanMessage(r.db.QueryRow(query, id))
+	message, err := scanMessage(r.db.QueryRow(query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -128,7 +130,7 @@ func (r *SQLiteRepository) GetChats(filter *domainChatStorage.ChatFilter) ([]*do
 
 	var chats []*domainChatStorage.Chat
 	for rows.Next() {
-		chat, err := r.scanChat(rows)
+		chat, err := scanChat(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -152,6 +154,21 @@ func (r *SQLiteRepository) DeleteChat(jid string) error {
 		return err
 	}
 
+	_, err = tx.Exec("DELETE FROM message_receipts WHERE chat_jid = ?", jid)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("DELETE FROM message_edits WHERE chat_jid = ?", jid)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("DELETE FROM message_reactions WHERE chat_jid = ?", jid)
+	if err != nil {
+		return err
+	}
+
 	// Delete chat
 	_, err = tx.Exec("DELETE FROM chats WHERE jid = ?", jid)
 	if err != nil {
@@ -296,7 +313,8 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 	query := `
 		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
 			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+			file_enc_sha256, file_length, edited_at, is_revoked, revoked_at,
+			created_at, updated_at
 		FROM messages
 		WHERE ` + strings.Join(conditions, " AND ") + `
 		ORDER BY timestamp DESC
@@ -325,7 +343,7 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 
 	var messages []*domainChatStorage.Message
 	for rows.Next() {
-		message, err := r.scanMessage(rows)
+		message, err := scanMessage(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -342,60 +360,283 @@ func (r *SQLiteRepository) SearchMessages(chatJID, searchText string, limit int)
 		return []*domainChatStorage.Message{}, nil
 	}
 
-	var conditions []string
-	var args []any
+	results, err := r.SearchAllMessages(&domainChatStorage.MessageSearchFilter{
+		Query:   literalSearchQuery(searchText),
+		ChatJID: chatJID,
+		Sort:    domainChatStorage.SearchSortNewest,
+		Limit:   limit,
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	// Always filter by chat JID
-	conditions = append(conditions, "chat_jid = ?")
-	args = append(args, chatJID)
+	messages := make([]*domainChatStorage.Message, 0, len(results))
+	for _, result := range results {
+		messages = append(messages, result.Message)
+	}
+	return messages, nil
+}
+
+// SearchAllMessages runs a full-text search across all chats. The query uses the FTS5 syntax
+// ("exact phrase", prefix*, AND/OR/NOT); without the search index it falls back to LIKE scans.
+func (r *SQLiteRepository) SearchAllMessages(filter *domainChatStorage.MessageSearchFilter) ([]*domainChatStorage.MessageSearchResult, error) {
+	if strings.TrimSpace(filter.Query) == "" {
+		return []*domainChatStorage.MessageSearchResult{}, nil
+	}
+
+	indexed, err := r.hasMessageSearchIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	source, args, terms := messageSearchSource(filter, indexed)
+	if !indexed && len(terms) == 0 {
+		return []*domainChatStorage.MessageSearchResult{}, nil
+	}
 
-	// Add search condition using LIKE operator for case-insensitive search
-	conditions = append(conditions, "LOWER(content) LIKE ?")
-	args = append(args, "%"+strings.ToLower(searchText)+"%")
+	columns := "m.content, 0"
+	orderBy := "m.timestamp DESC"
+	if indexed {
+		columns = "snippet(messages_fts, 0, ?, ?, '…', 16), -bm25(messages_fts)"
+		args = append([]any{searchHighlightStart, searchHighlightEnd}, args...)
+		if filter.Sort != domainChatStorage.SearchSortNewest {
+			orderBy = "bm25(messages_fts), m.timestamp DESC"
+		}
+	}
 
 	query := `
-		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
-			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
-		FROM messages
-		WHERE ` + strings.Join(conditions, " AND ") + `
-		ORDER BY timestamp DESC
-	`
+		SELECT m.id, m.chat_jid, m.sender, m.content, m.timestamp, m.is_from_me,
+			m.media_type, m.filename, m.url, m.media_key, m.file_sha256,
+			m.file_enc_sha256, m.file_length, m.edited_at, m.is_revoked, m.revoked_at,
+			m.created_at, m.updated_at, COALESCE(c.name, ''), ` + columns + `
+		` + source + `
+		ORDER BY ` + orderBy
 
 	// Add limit with validation
-	if limit > 0 {
+	if filter.Limit > 0 {
 		// Validate limit to prevent abuse
-		if limit > 1000 {
-			limit = 1000
+		if filter.Limit > 1000 {
+			filter.Limit = 1000
 		}
 		query += " LIMIT ?"
-		args = append(args, limit)
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
 	}
 
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search messages: %w", err)
+		return nil, searchQueryError(err)
 	}
 	defer rows.Close()
 
-	var messages []*domainChatStorage.Message
+	results := []*domainChatStorage.MessageSearchResult{}
 	for rows.Next() {
-		message, err := r.scanMessage(rows)
+		result := &domainChatStorage.MessageSearchResult{}
+		message, err := scanMessage(rows, &result.ChatName, &result.Snippet, &result.Rank)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan message: %w", err)
 		}
-		messages = append(messages, message)
+		result.Message = message
+		if !indexed {
+			result.Snippet = highlightSnippet(message.Content, terms)
+		}
+		results = append(results, result)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating messages: %w", err)
+		return nil, searchQueryError(err)
 	}
 
-	return messages, nil
+	return results, nil
+}
+
+// CountSearchMessages returns the number of messages matching a full-text search
+func (r *SQLiteRepository) CountSearchMessages(filter *domainChatStorage.MessageSearchFilter) (int64, error) {
+	if strings.TrimSpace(filter.Query) == "" {
+		return 0, nil
+	}
+
+	indexed, err := r.hasMessageSearchIndex()
+	if err != nil {
+		return 0, err
+	}
+
+	source, args, terms := messageSearchSource(filter, indexed)
+	if !indexed && len(terms) == 0 {
+		return 0, nil
+	}
+
+	count, err := r.getCount("SELECT COUNT(*) "+source, args...)
+	if err != nil {
+		return 0, searchQueryError(err)
+	}
+	return count, nil
+}
+
+// hasMessageSearchIndex reports whether the FTS5 index was created by the migrations
+func (r *SQLiteRepository) hasMessageSearchIndex() (bool, error) {
+	count, err := r.getCount("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'messages_fts'")
+	return count > 0, err
+}
+
+// messageSearchSource builds the FROM and WHERE clauses shared by the search and count queries.
+// It also returns the plain terms used by the LIKE fallback.
+func messageSearchSource(filter *domainChatStorage.MessageSearchFilter, indexed bool) (string, []any, []string) {
+	var conditions []string
+	var args []any
+	var terms []string
+
+	source := "FROM messages m LEFT JOIN chats c ON c.jid = m.chat_jid"
+	if indexed {
+		source = "FROM messages_fts JOIN messages m ON m.rowid = messages_fts.rowid LEFT JOIN chats c ON c.jid = m.chat_jid"
+		conditions = append(conditions, "messages_fts MATCH ?")
+		args = append(args, filter.Query)
+	} else {
+		terms = searchTerms(filter.Query)
+		for _, term := range terms {
+			conditions = append(conditions, "LOWER(m.content) LIKE ?")
+			args = append(args, "%"+strings.ToLower(term)+"%")
+		}
+	}
+
+	if filter.ChatJID != "" {
+		conditions = append(conditions, "m.chat_jid = ?")
+		args = append(args, filter.ChatJID)
+	}
+
+	if filter.Sender != "" {
+		if strings.Contains(filter.Sender, "@") {
+			conditions = append(conditions, "m.sender = ?")
+			args = append(args, filter.Sender)
+		} else {
+			// A bare phone number matches the sender on any server and device
+			conditions = append(conditions, "(m.sender LIKE ? OR m.sender LIKE ?)")
+			args = append(args, filter.Sender+"@%", filter.Sender+":%")
+		}
+	}
+
+	if filter.StartTime != nil {
+		conditions = append(conditions, "m.timestamp >= ?")
+		args = append(args, *filter.StartTime)
+	}
+
+	if filter.EndTime != nil {
+		conditions = append(conditions, "m.timestamp <= ?")
+		args = append(args, *filter.EndTime)
+	}
+
+	if filter.MediaType != "" {
+		conditions = append(conditions, "m.media_type = ?")
+		args = append(args, filter.MediaType)
+	} else if filter.MediaOnly {
+		conditions = append(conditions, "m.media_type != ''")
+	}
+
+	if filter.IsFromMe != nil {
+		conditions = append(conditions, "m.is_from_me = ?")
+		args = append(args, *filter.IsFromMe)
+	}
+
+	if len(conditions) > 0 {
+		source += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return source, args, terms
+}
+
+// searchQueryError marks FTS5 parse errors so callers can report them as bad input
+func searchQueryError(err error) error {
+	for _, marker := range []string{"fts5:", "unterminated string", "no such column", "unknown special query"} {
+		if strings.Contains(err.Error(), marker) {
+			return fmt.Errorf("%w: %v", domainChatStorage.ErrInvalidSearchQuery, err)
+		}
+	}
+	return fmt.Errorf("failed to search messages: %w", err)
+}
+
+// searchTerms extracts the plain words of an FTS5 query for the LIKE fallback
+func searchTerms(query string) []string {
+	fields := strings.FieldsFunc(query, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`"*()^`, r)
+	})
+
+	terms := make([]string, 0, len(fields))
+	for _, field := range fields {
+		switch field {
+		case "AND", "OR", "NOT", "NEAR":
+			continue
+		}
+		terms = append(terms, field)
+	}
+	return terms
+}
+
+// highlightSnippet mimics the FTS5 snippet() output for the LIKE fallback
+func highlightSnippet(content string, terms []string) string {
+	const before, width = 32, 96
+
+	text := []rune(content)
+	lower := lowerRunes(text)
+	marked := make([]bool, len(text))
+	first := -1
+	for _, term := range terms {
+		needle := lowerRunes([]rune(term))
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if !slices.Equal(lower[i:i+len(needle)], needle) {
+				continue
+			}
+			for j := i; j < i+len(needle); j++ {
+				marked[j] = true
+			}
+			if first == -1 || i < first {
+				first = i
+			}
+		}
+	}
+
+	start := max(first-before, 0)
+	end := min(start+width, len(text))
+
+	var builder strings.Builder
+	if start > 0 {
+		builder.WriteString("…")
+	}
+	for i := start; i < end; i++ {
+		if marked[i] && (i == start || !marked[i-1]) {
+			builder.WriteString(searchHighlightStart)
+		}
+		builder.WriteRune(text[i])
+		if marked[i] && (i+1 == end || !marked[i+1]) {
+			builder.WriteString(searchHighlightEnd)
+		}
+	}
+	if end < len(text) {
+		builder.WriteString("…")
+	}
+	return builder.String()
+}
+
+func lowerRunes(text []rune) []rune {
+	lower := make([]rune, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
 }
 
 // DeleteMessage deletes a specific message
 func (r *SQLiteRepository) DeleteMessage(id, chatJID string) error {
+	for _, table := range []string{"message_receipts", "message_edits", "message_reactions"} {
+		if _, err := r.db.Exec("DELETE FROM "+table+" WHERE message_id = ? AND chat_jid = ?", id, chatJID); err != nil {
+			return err
+		}
+	}
 	_, err := r.db.Exec("DELETE FROM messages WHERE id = ? AND chat_jid = ?", id, chatJID)
 	return err
 }
@@ -407,28 +648,6 @@ func (r *SQLiteRepository) getCount(query string, args ...any) (int64, error) {
 	return count, err
 }
 
-// scanMessage is a private helper for scanning message rows
-func (r *SQLiteRepository) scanMessage(scanner interface{ Scan(...any) error }) (*domainChatStorage.Message, error) {
-	message := &domainChatStorage.Message{}
-	err := scanner.Scan(
-		&message.ID, &message.ChatJID, &message.Sender, &message.Content,
-		&message.Timestamp, &message.IsFromMe, &message.MediaType, &message.Filename,
-		&message.URL, &message.MediaKey, &message.FileSHA256, &message.FileEncSHA256,
-		&message.FileLength, &message.CreatedAt, &message.UpdatedAt,
-	)
-	return message, err
-}
-
-// scanChat is a private helper for scanning chat rows
-func (r *SQLiteRepository) scanChat(scanner interface{ Scan(...any) error }) (*domainChatStorage.Chat, error) {
-	chat := &domainChatStorage.Chat{}
-	err := scanner.Scan(
-		&chat.JID, &chat.Name, &chat.LastMessageTime, &chat.EphemeralExpiration,
-		&chat.CreatedAt, &chat.UpdatedAt,
-	)
-	return chat, err
-}
-
 // GetChatMessageCount returns the number of messages in a chat
 func (r *SQLiteRepository) GetChatMessageCount(chatJID string) (int64, error) {
 	return r.getCount("SELECT COUNT(*) FROM messages WHERE chat_jid = ?", chatJID)
@@ -459,269 +678,1653 @@ func (r *SQLiteRepository) TruncateAllChats() error {
 		return fmt.Errorf("failed to delete messages: %w", err)
 	}
 
-	// Delete chats
-	_, err = tx.Exec("DELETE FROM chats")
+	_, err = tx.Exec("DELETE FROM message_receipts")
 	if err != nil {
-		return fmt.Errorf("failed to delete chats: %w", err)
+		return fmt.Errorf("failed to delete message receipts: %w", err)
 	}
 
-	return tx.Commit()
-}
-
-// GetChatNameWithPushName determines the appropriate name for a chat with pushname support
-func (r *SQLiteRepository) GetChatNameWithPushName(jid types.JID, chatJID string, senderUser string, pushName string) string {
-	// First, check if chat already exists with a name
-	existingChat, err := r.GetChat(chatJID)
-	if err == nil && existingChat != nil && existingChat.Name != "" {
-		// If we have a pushname and the existing name is just a phone number/JID user, update it
-		if pushName != "" && (existingChat.Name == jid.User || existingChat.Name == senderUser) {
-			return pushName
-		}
-		return existingChat.Name
-	}
-
-	// Determine chat type and name
-	var name string
-
-	switch jid.Server {
-	case "g.us":
-		// This is a group chat
-		// For now, use a generic name - this can be enhanced later with group info
-		name = fmt.Sprintf("Group %s", jid.User)
-	case "newsletter":
-		// This is a newsletter/channel
-		name = fmt.Sprintf("Newsletter %s", jid.User)
-	default:
-		// This is an individual contact
-		// Priority: pushName > senderUser > JID user
-		if pushName != "" && pushName != senderUser && pushName != jid.User {
-			name = pushName
-		} else if senderUser != "" {
-			name = senderUser
-		} else {
-			name = jid.User
-		}
+	_, err = tx.Exec("DELETE FROM message_edits")
+	if err != nil {
+		return fmt.Errorf("failed to delete message edits: %w", err)
 	}
 
-	return name
-}
-
-func (r *SQLiteRepository) CreateMessage(ctx context.Context, evt *events.Message) error {
-	if evt == nil || evt.Message == nil {
-		return nil
+	_, err = tx.Exec("DELETE FROM message_reactions")
+	if err != nil {
+		return fmt.Errorf("failed to delete message reactions: %w", err)
 	}
 
-	// Extract chat and sender information
-	chatJID := evt.Info.Chat.String()
-	// Store the full sender JID (user@server) to ensure consistency between received and sent messages
-	sender := evt.Info.Sender.String()
-
-	// Get appropriate chat name using pushname if available
-	chatName := r.GetChatNameWithPushName(evt.Info.Chat, chatJID, evt.Info.Sender.User, evt.Info.PushName)
-
-	// Get existing chat to preserve ephemeral_expiration if needed
-	existingChat, err := r.GetChat(chatJID)
+	_, err = tx.Exec("DELETE FROM calls")
 	if err != nil {
-		return fmt.Errorf("failed to get existing chat: %w", err)
+		return fmt.Errorf("failed to delete calls: %w", err)
 	}
 
-	// Extract ephemeral expiration from incoming message
-	ephemeralExpiration := utils.ExtractEphemeralExpiration(evt.Message)
-
-	// Create or update chat
-	chat := &domainChatStorage.Chat{
-		JID:             chatJID,
-		Name:            chatName,
-		LastMessageTime: evt.Info.Timestamp,
+	_, err = tx.Exec("DELETE FROM poll_votes")
+	if err != nil {
+		return fmt.Errorf("failed to delete poll votes: %w", err)
 	}
 
-	// Set ephemeral expiration: use incoming message value if > 0, otherwise preserve existing
-	if ephemeralExpiration > 0 {
-		chat.EphemeralExpiration = ephemeralExpiration
-	} else if existingChat != nil {
-		// Preserve existing ephemeral_expiration if incoming message doesn't have one
-		chat.EphemeralExpiration = existingChat.EphemeralExpiration
+	_, err = tx.Exec("DELETE FROM polls")
+	if err != nil {
+		return fmt.Errorf("failed to delete polls: %w", err)
 	}
 
-	// Store or update the chat
-	if err := r.StoreChat(chat); err != nil {
-		return fmt.Errorf("failed to store chat: %w", err)
+	_, err = tx.Exec("DELETE FROM group_events")
+	if err != nil {
+		return fmt.Errorf("failed to delete group events: %w", err)
 	}
 
-	// Extract message content and media info
-	content := utils.ExtractMessageTextFromProto(evt.Message)
-	mediaType, filename, url, mediaKey, fileSHA256, fileEncSHA256, fileLength := utils.ExtractMediaInfo(evt.Message)
-
-	// Skip if there's no content and no media
-	if content == "" && mediaType == "" {
-		logrus.Debugf("Skipping message %s - no content or media", evt.Info.ID)
-		return nil
+	_, err = tx.Exec("DELETE FROM stream_events")
+	if err != nil {
+		return fmt.Errorf("failed to delete stream events: %w", err)
 	}
 
-	// Create message object
-	message := &domainChatStorage.Message{
-		ID:            evt.Info.ID,
-		ChatJID:       chatJID,
-		Sender:        sender,
-		Content:       content,
-		Timestamp:     evt.Info.Timestamp,
-		IsFromMe:      evt.Info.IsFromMe,
-		MediaType:     mediaType,
-		Filename:      filename,
-		URL:           url,
-		MediaKey:      mediaKey,
-		FileSHA256:    fileSHA256,
-		FileEncSHA256: fileEncSHA256,
-		FileLength:    fileLength,
+	// Delete chats
+	_, err = tx.Exec("DELETE FROM chats")
+	if err != nil {
+		return fmt.Errorf("failed to delete chats: %w", err)
 	}
 
-	// Store the message
-	return r.StoreMessage(message)
+	return tx.Commit()
 }
 
-// GetStorageStatistics returns current storage statistics for logging purposes
-func (r *SQLiteRepository) GetStorageStatistics() (chatCount int64, messageCount int64, err error) {
-	// Count all chats using efficient query
-	chatCount, err = r.GetTotalChatCount()
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to get chat count: %w", err)
-	}
+// GetChatNameWithPushName determines the appropriate name for a chat with pushname support
+func (r *SQLiteRepository) GetChatNameWithPushName(jid types.JID, chatJID string, senderUser string, pushName string) string {
+	return chatNameWithPushName(r, jid, chatJID, senderUser, pushName)
+}
 
-	// Count all messages
-	messageCount, err = r.GetTotalMessageCount()
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to get message count: %w", err)
-	}
+// CreateMessage stores the chat and message of an incoming message event
+func (r *SQLiteRepository) CreateMessage(ctx context.Context, evt *events.Message) error {
+	return createMessage(r, evt)
+}
 
-	return chatCount, messageCount, nil
+// GetStorageStatistics returns current storage statistics for logging purposes
+func (r *SQLiteRepository) GetStorageStatistics() (chatCount int64, messageCount int64, err error) {
+	return storageStatistics(r)
 }
 
 // TruncateAllDataWithLogging performs truncation with detailed logging
 func (r *SQLiteRepository) TruncateAllDataWithLogging(logPrefix string) error {
-	// Get statistics before truncation
-	chatCount, messageCount, err := r.GetStorageStatistics()
-	if err != nil {
-		logrus.Warnf("[%s] Failed to get storage statistics before truncation: %v", logPrefix, err)
-	} else {
-		logrus.Infof("[%s] Storage before truncation: %d chats, %d messages", logPrefix, chatCount, messageCount)
+	return truncateAllDataWithLogging(r, logPrefix)
+}
+
+// StoreSentMessageWithContext stores a message that was sent by the user with context cancellation support
+func (r *SQLiteRepository) StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error {
+	return storeSentMessage(ctx, r, messageID, senderJID, recipientJID, content, timestamp)
+}
+
+// StoreMessageReceipts records message states, keeping the first time each participant reached a state
+func (r *SQLiteRepository) StoreMessageReceipts(receipts []*domainChatStorage.MessageReceipt) error {
+	if len(receipts) == 0 {
+		return nil
 	}
 
-	// Perform truncation
-	if err := r.TruncateAllChats(); err != nil {
-		return fmt.Errorf("failed to truncate chatstorage data: %w", err)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	// Verify truncation
-	chatCountAfter, messageCountAfter, err := r.GetStorageStatistics()
+	stmt, err := tx.Prepare(`
+		INSERT INTO message_receipts (message_id, chat_jid, participant_jid, status, timestamp)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(message_id, chat_jid, participant_jid, status) DO NOTHING
+	`)
 	if err != nil {
-		logrus.Warnf("[%s] Failed to get storage statistics after truncation: %v", logPrefix, err)
-	} else {
-		logrus.Infof("[%s] Storage after truncation: %d chats, %d messages", logPrefix, chatCountAfter, messageCountAfter)
-		if chatCountAfter == 0 && messageCountAfter == 0 {
-			logrus.Infof("[%s] ✅ Chatstorage truncation completed successfully", logPrefix)
-		} else {
-			logrus.Warnf("[%s] ⚠️ Truncation may not have completed fully", logPrefix)
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, receipt := range receipts {
+		if _, err := stmt.Exec(receipt.MessageID, receipt.ChatJID, receipt.ParticipantJID, receipt.Status, receipt.Timestamp); err != nil {
+			return fmt.Errorf("failed to store receipt of message %s: %w", receipt.MessageID, err)
 		}
 	}
 
-	return nil
+	return tx.Commit()
 }
 
-// StoreSentMessageWithContext stores a message that was sent by the user with context cancellation support
-func (r *SQLiteRepository) StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error {
-	// Check if context is already cancelled before starting
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
+// GetMessageReceipts returns the receipts of the given messages in the order they arrived
+func (r *SQLiteRepository) GetMessageReceipts(messageIDs []string) ([]*domainChatStorage.MessageReceipt, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
 	}
 
-	// Ensure JID is properly formatted
-	jid, err := types.ParseJID(recipientJID)
-	if err != nil {
-		return fmt.Errorf("invalid JID format: %w", err)
+	query := `SELECT ` + messageReceiptColumns + ` FROM message_receipts WHERE message_id IN (` +
+		strings.TrimSuffix(strings.Repeat("?, ", len(messageIDs)), ", ") + `) ORDER BY timestamp, participant_jid`
+	args := make([]any, 0, len(messageIDs))
+	for _, messageID := range messageIDs {
+		args = append(args, messageID)
 	}
 
-	chatJID := jid.String()
-
-	// Get chat name (no pushname available for sent messages)
-	chatName := r.GetChatNameWithPushName(jid, chatJID, jid.User, "")
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	// Check context again before database operations
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
+	var receipts []*domainChatStorage.MessageReceipt
+	for rows.Next() {
+		receipt, err := scanMessageReceipt(rows)
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, receipt)
 	}
+	return receipts, rows.Err()
+}
 
-	// Get existing chat to preserve ephemeral_expiration
-	existingChat, err := r.GetChat(chatJID)
+// StoreMessageEdit records an edit of a stored message and replaces its content when the edit is the latest.
+// Edits of messages that were not stored and edits that were already recorded are ignored.
+func (r *SQLiteRepository) StoreMessageEdit(edit *domainChatStorage.MessageEdit) error {
+	tx, err := r.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to get existing chat: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	// Store or update chat, preserving existing ephemeral_expiration
-	chat := &domainChatStorage.Chat{
-		JID:             chatJID,
-		Name:            chatName,
-		LastMessageTime: timestamp,
+	var content sql.NullString
+	err = tx.QueryRow("SELECT content FROM messages WHERE id = ? AND chat_jid = ?", edit.MessageID, edit.ChatJID).Scan(&content)
+	if err == sql.ErrNoRows {
+		return nil
 	}
-
-	// Preserve existing ephemeral_expiration if chat exists
-	if existingChat != nil {
-		chat.EphemeralExpiration = existingChat.EphemeralExpiration
+	if err != nil {
+		return err
 	}
+	edit.OldContent = content.String
 
-	if err := r.StoreChat(chat); err != nil {
-		return fmt.Errorf("failed to store chat: %w", err)
+	result, err := tx.Exec(`
+		INSERT INTO message_edits (`+messageEditColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO NOTHING
+	`, edit.ID, edit.MessageID, edit.ChatJID, edit.OldContent, edit.NewContent, edit.EditedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store edit of message %s: %w", edit.MessageID, err)
 	}
-
-	// Check context one more time before storing message
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return err
 	}
 
-	// Store the sent message
-	message := &domainChatStorage.Message{
-		ID:        messageID,
-		ChatJID:   chatJID,
-		Sender:    senderJID,
-		Content:   content,
-		Timestamp: timestamp,
-		IsFromMe:  true,
+	_, err = tx.Exec(`
+		UPDATE messages SET content = ?, edited_at = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND chat_jid = ? AND (edited_at IS NULL OR edited_at <= ?)
+	`, edit.NewContent, edit.EditedAt, edit.MessageID, edit.ChatJID, edit.EditedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update edited message %s: %w", edit.MessageID, err)
 	}
 
-	return r.StoreMessage(message)
+	return tx.Commit()
 }
 
-// _____________________________________________________________________________________________________________________
+// GetMessageEdits returns the edits of the given messages, oldest first
+func (r *SQLiteRepository) GetMessageEdits(messageIDs []string) ([]*domainChatStorage.MessageEdit, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
 
-// initializeSchema creates or migrates the database schema
-func (r *SQLiteRepository) InitializeSchema() error {
-	// Get current schema version
-	version, err := r.getSchemaVersion()
+	query := `SELECT ` + messageEditColumns + ` FROM message_edits WHERE message_id IN (` +
+		strings.TrimSuffix(strings.Repeat("?, ", len(messageIDs)), ", ") + `) ORDER BY edited_at, id`
+	args := make([]any, 0, len(messageIDs))
+	for _, messageID := range messageIDs {
+		args = append(args, messageID)
+	}
+
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer rows.Close()
 
-	// Run migrations based on version
-	migrations := r.getMigrations()
-	for i := version; i < len(migrations); i++ {
-		if err := r.runMigration(migrations[i], i+1); err != nil {
-			return fmt.Errorf("failed to run migration %d: %w", i+1, err)
+	var edits []*domainChatStorage.MessageEdit
+	for rows.Next() {
+		edit, err := scanMessageEdit(rows)
+		if err != nil {
+			return nil, err
 		}
+		edits = append(edits, edit)
 	}
-
-	return nil
+	return edits, rows.Err()
 }
 
-// getSchemaVersion returns the current schema version
-func (r *SQLiteRepository) getSchemaVersion() (int, error) {
-	// Create schema_info table if it doesn't exist
+// RevokeMessage marks a stored message as deleted for everyone, keeping the time it was first revoked
+func (r *SQLiteRepository) RevokeMessage(id, chatJID string, revokedAt time.Time) error {
 	_, err := r.db.Exec(`
-		CREATE TABLE IF NOT EXISTS schema_info (
+		UPDATE messages SET is_revoked = TRUE, revoked_at = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND chat_jid = ? AND NOT is_revoked
+	`, revokedAt, id, chatJID)
+	return err
+}
+
+// StoreMessageReaction replaces the reaction of a sender to a message, an empty reaction removes it.
+// Reactions older than the stored one are ignored.
+func (r *SQLiteRepository) StoreMessageReaction(reaction *domainChatStorage.MessageReaction) error {
+	if reaction.Reaction == "" {
+		_, err := r.db.Exec(`
+			DELETE FROM message_reactions
+			WHERE message_id = ? AND chat_jid = ? AND sender_jid = ? AND reacted_at <= ?
+		`, reaction.MessageID, reaction.ChatJID, reaction.SenderJID, reaction.ReactedAt)
+		return err
+	}
+
+	_, err := r.db.Exec(`
+		INSERT INTO message_reactions (`+messageReactionColumns+`)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(message_id, chat_jid, sender_jid) DO UPDATE SET
+			reaction = excluded.reaction,
+			reacted_at = excluded.reacted_at
+		WHERE excluded.reacted_at >= message_reactions.reacted_at
+	`, reaction.MessageID, reaction.ChatJID, reaction.SenderJID, reaction.Reaction, reaction.ReactedAt)
+	return err
+}
+
+// GetMessageReactions returns the current reactions to the given messages, oldest first
+func (r *SQLiteRepository) GetMessageReactions(messageIDs []string) ([]*domainChatStorage.MessageReaction, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT ` + messageReactionColumns + ` FROM message_reactions WHERE message_id IN (` +
+		strings.TrimSuffix(strings.Repeat("?, ", len(messageIDs)), ", ") + `) ORDER BY reacted_at, sender_jid`
+	args := make([]any, 0, len(messageIDs))
+	for _, messageID := range messageIDs {
+		args = append(args, messageID)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reactions []*domainChatStorage.MessageReaction
+	for rows.Next() {
+		reaction, err := scanMessageReaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		reactions = append(reactions, reaction)
+	}
+	return reactions, rows.Err()
+}
+
+// StoreCall creates or updates an entry of the call log
+func (r *SQLiteRepository) StoreCall(call *domainChatStorage.Call) error {
+	_, err := r.db.Exec(`
+		INSERT INTO calls (`+callColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			status = excluded.status,
+			auto_rejected = excluded.auto_rejected,
+			end_reason = excluded.end_reason,
+			accepted_at = excluded.accepted_at,
+			ended_at = excluded.ended_at
+	`, callArgs(call)...)
+	return err
+}
+
+// GetCall retrieves a call by ID
+func (r *SQLiteRepository) GetCall(id string) (*domainChatStorage.Call, error) {
+	row := r.db.QueryRow("SELECT "+callColumns+" FROM calls WHERE id = ?", id)
+	call, err := scanCall(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return call, err
+}
+
+// GetCalls returns the call log, most recent calls first
+func (r *SQLiteRepository) GetCalls(filter *domainChatStorage.CallFilter) ([]*domainChatStorage.Call, error) {
+	var args []any
+	query := "SELECT " + callColumns + " FROM calls"
+
+	if filter.ChatJID != "" {
+		query += " WHERE chat_jid = ?"
+		args = append(args, filter.ChatJID)
+	}
+
+	query += " ORDER BY started_at DESC"
+
+	if filter.Limit > 0 {
+		if filter.Limit > 1000 {
+			filter.Limit = 1000
+		}
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var calls []*domainChatStorage.Call
+	for rows.Next() {
+		call, err := scanCall(rows)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+	}
+	return calls, rows.Err()
+}
+
+// StoreGroupEvents records group events, events that were already recorded are ignored
+func (r *SQLiteRepository) StoreGroupEvents(events []*domainChatStorage.GroupEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO group_events (` + groupEventColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(group_jid, type, target_jid, timestamp) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, event := range events {
+		if _, err := stmt.Exec(event.GroupJID, event.Type, event.ActorJID, event.TargetJID, event.Value, event.Timestamp); err != nil {
+			return fmt.Errorf("failed to store %s event of group %s: %w", event.Type, event.GroupJID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetGroupEvents returns the group events matching the filter, most recent first
+func (r *SQLiteRepository) GetGroupEvents(filter *domainChatStorage.GroupEventFilter) ([]*domainChatStorage.GroupEvent, error) {
+	where, args := sqliteGroupEventConditions(filter)
+	query := "SELECT id, " + groupEventColumns + " FROM group_events" + where + " ORDER BY timestamp DESC, id DESC"
+
+	if filter.Limit > 0 {
+		if filter.Limit > 1000 {
+			filter.Limit = 1000
+		}
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domainChatStorage.GroupEvent
+	for rows.Next() {
+		event, err := scanGroupEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// CountGroupEvents returns the number of group events matching the filter
+func (r *SQLiteRepository) CountGroupEvents(filter *domainChatStorage.GroupEventFilter) (int64, error) {
+	where, args := sqliteGroupEventConditions(filter)
+	return r.getCount("SELECT COUNT(*) FROM group_events"+where, args...)
+}
+
+func sqliteGroupEventConditions(filter *domainChatStorage.GroupEventFilter) (string, []any) {
+	var conditions []string
+	var args []any
+
+	if filter.GroupJID != "" {
+		conditions = append(conditions, "group_jid = ?")
+		args = append(args, filter.GroupJID)
+	}
+	if len(filter.Types) > 0 {
+		conditions = append(conditions, "type IN ("+strings.TrimSuffix(strings.Repeat("?, ", len(filter.Types)), ", ")+")")
+		for _, eventType := range filter.Types {
+			args = append(args, eventType)
+		}
+	}
+	if filter.ActorJID != "" {
+		conditions = append(conditions, "actor_jid = ?")
+		args = append(args, filter.ActorJID)
+	}
+	if filter.TargetJID != "" {
+		conditions = append(conditions, "target_jid = ?")
+		args = append(args, filter.TargetJID)
+	}
+	if filter.StartTime != nil {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		conditions = append(conditions, "timestamp <= ?")
+		args = append(args, *filter.EndTime)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// StoreStreamEvent appends an event to the event stream log, the caller assigns its ID
+func (r *SQLiteRepository) StoreStreamEvent(event *domainChatStorage.StreamEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	_, err := r.db.Exec(`
+		INSERT INTO stream_events (`+streamEventColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, event.ID, event.Event, event.DeviceID, event.ChatJID, event.Data, event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store %s stream event %d: %w", event.Event, event.ID, err)
+	}
+	return nil
+}
+
+// GetStreamEventsAfter returns up to limit logged events with an ID above afterID, oldest first
+func (r *SQLiteRepository) GetStreamEventsAfter(afterID int64, limit int) ([]*domainChatStorage.StreamEvent, error) {
+	rows, err := r.db.Query("SELECT "+streamEventColumns+" FROM stream_events WHERE id > ? ORDER BY id LIMIT ?", afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domainChatStorage.StreamEvent
+	for rows.Next() {
+		event, err := scanStreamEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// GetLastStreamEventID returns the ID of the most recent logged event, 0 when the log is empty
+func (r *SQLiteRepository) GetLastStreamEventID() (int64, error) {
+	return r.getCount("SELECT COALESCE(MAX(id), 0) FROM stream_events")
+}
+
+// TrimStreamEvents removes the logged events with an ID up to upToID
+func (r *SQLiteRepository) TrimStreamEvents(upToID int64) error {
+	_, err := r.db.Exec("DELETE FROM stream_events WHERE id <= ?", upToID)
+	return err
+}
+
+// StorePoll creates or updates a poll, keeping a known secret when the poll is stored again without one
+func (r *SQLiteRepository) StorePoll(poll *domainChatStorage.Poll) error {
+	args, err := pollArgs(poll)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO polls (`+pollColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(message_id) DO UPDATE SET
+			question = excluded.question,
+			options = excluded.options,
+			selectable_count = excluded.selectable_count,
+			secret = COALESCE(excluded.secret, polls.secret)
+	`, args...)
+	return err
+}
+
+// GetPoll retrieves a poll by the ID of its message
+func (r *SQLiteRepository) GetPoll(messageID string) (*domainChatStorage.Poll, error) {
+	row := r.db.QueryRow("SELECT "+pollColumns+" FROM polls WHERE message_id = ?", messageID)
+	poll, err := scanPoll(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return poll, err
+}
+
+// StorePollVote records the vote of a voter, replacing an older vote of the same voter
+func (r *SQLiteRepository) StorePollVote(vote *domainChatStorage.PollVote) error {
+	args, err := pollVoteArgs(vote)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO poll_votes (`+pollVoteColumns+`)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(poll_id, voter_jid) DO UPDATE SET
+			selected_options = excluded.selected_options,
+			voted_at = excluded.voted_at
+		WHERE excluded.voted_at >= poll_votes.voted_at
+	`, args...)
+	return err
+}
+
+// GetPollVotes returns the current vote of every voter of a poll
+func (r *SQLiteRepository) GetPollVotes(pollID string) ([]*domainChatStorage.PollVote, error) {
+	rows, err := r.db.Query("SELECT "+pollVoteColumns+" FROM poll_votes WHERE poll_id = ? ORDER BY voted_at, voter_jid", pollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var votes []*domainChatStorage.PollVote
+	for rows.Next() {
+		vote, err := scanPollVote(rows)
+		if err != nil {
+			return nil, err
+		}
+		votes = append(votes, vote)
+	}
+	return votes, rows.Err()
+}
+
+// StoreDeviceRecord creates or updates a device registry entry
+func (r *SQLiteRepository) StoreDeviceRecord(device *domainChatStorage.DeviceRecord) error {
+	now := time.Now()
+	device.UpdatedAt = now
+
+	query := `
+		INSERT INTO devices (id, jid, webhooks, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			jid = excluded.jid,
+			webhooks = excluded.webhooks,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(query, device.ID, device.JID, strings.Join(device.Webhooks, ","), now, device.UpdatedAt)
+	return err
+}
+
+// GetDeviceRecords returns all registered devices ordered by creation time
+func (r *SQLiteRepository) GetDeviceRecords() ([]*domainChatStorage.DeviceRecord, error) {
+	rows, err := r.db.Query(`
+		SELECT id, jid, webhooks, created_at, updated_at
+		FROM devices
+		ORDER BY created_at ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var devices []*domainChatStorage.DeviceRecord
+	for rows.Next() {
+		device := &domainChatStorage.DeviceRecord{}
+		var webhooks string
+		if err := rows.Scan(&device.ID, &device.JID, &webhooks, &device.CreatedAt, &device.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if webhooks != "" {
+			device.Webhooks = strings.Split(webhooks, ",")
+		}
+		devices = append(devices, device)
+	}
+
+	return devices, rows.Err()
+}
+
+// DeleteDeviceRecord removes a device from the registry
+func (r *SQLiteRepository) DeleteDeviceRecord(id string) error {
+	_, err := r.db.Exec("DELETE FROM devices WHERE id = ?", id)
+	return err
+}
+
+// StoreWebhookSubscription creates or updates a webhook subscription
+func (r *SQLiteRepository) StoreWebhookSubscription(subscription *domainChatStorage.WebhookSubscription) error {
+	now := time.Now()
+	if subscription.CreatedAt.IsZero() {
+		subscription.CreatedAt = now
+	}
+	subscription.UpdatedAt = now
+
+	events, allowJIDs, denyJIDs, err := encodeWebhookSubscriptionLists(subscription)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO webhooks (id, url, secret, device_id, events, allow_jids, deny_jids, skip_from_me, chat_type, include_media, enabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			url = excluded.url,
+			secret = excluded.secret,
+			device_id = excluded.device_id,
+			events = excluded.events,
+			allow_jids = excluded.allow_jids,
+			deny_jids = excluded.deny_jids,
+			skip_from_me = excluded.skip_from_me,
+			chat_type = excluded.chat_type,
+			include_media = excluded.include_media,
+			enabled = excluded.enabled,
+			updated_at = excluded.updated_at
+	`, subscription.ID, subscription.URL, subscription.Secret, subscription.DeviceID, events, allowJIDs,
+		denyJIDs, subscription.SkipFromMe, subscription.ChatType, subscription.IncludeMedia, subscription.Enabled,
+		subscription.CreatedAt, subscription.UpdatedAt)
+	return err
+}
+
+// GetWebhookSubscription retrieves a webhook subscription by ID
+func (r *SQLiteRepository) GetWebhookSubscription(id string) (*domainChatStorage.WebhookSubscription, error) {
+	row := r.db.QueryRow("SELECT "+webhookSubscriptionColumns+" FROM webhooks WHERE id = ?", id)
+	subscription, err := scanWebhookSubscription(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return subscription, err
+}
+
+// GetWebhookSubscriptions returns all webhook subscriptions ordered by creation time
+func (r *SQLiteRepository) GetWebhookSubscriptions() ([]*domainChatStorage.WebhookSubscription, error) {
+	rows, err := r.db.Query("SELECT " + webhookSubscriptionColumns + " FROM webhooks ORDER BY created_at ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*domainChatStorage.WebhookSubscription
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// DeleteWebhookSubscription removes a webhook subscription
+func (r *SQLiteRepository) DeleteWebhookSubscription(id string) error {
+	_, err := r.db.Exec("DELETE FROM webhooks WHERE id = ?", id)
+	return err
+}
+
+// EnqueueWebhookDelivery persists a webhook event before it is delivered
+func (r *SQLiteRepository) EnqueueWebhookDelivery(delivery *domainChatStorage.WebhookDelivery) error {
+	now := time.Now()
+	if delivery.Status == "" {
+		delivery.Status = domainChatStorage.WebhookDeliveryPending
+	}
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = now
+	}
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+
+	result, err := r.db.Exec(`
+		INSERT INTO webhook_deliveries (webhook_id, device_id, event, url, payload, status, attempts, last_error, next_attempt_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, delivery.WebhookID, delivery.DeviceID, delivery.Event, delivery.URL, delivery.Payload, delivery.Status, delivery.Attempts,
+		delivery.LastError, delivery.NextAttemptAt.UnixMilli(), now, now)
+	if err != nil {
+		return err
+	}
+
+	delivery.ID, err = result.LastInsertId()
+	return err
+}
+
+// ClaimDueWebhookDeliveries marks up to limit pending deliveries that are due as in flight and returns them
+func (r *SQLiteRepository) ClaimDueWebhookDeliveries(now time.Time, limit int) ([]*domainChatStorage.WebhookDelivery, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at ASC, id ASC
+		LIMIT ?
+	`, domainChatStorage.WebhookDeliveryPending, now.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []*domainChatStorage.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, delivery := range deliveries {
+		if _, err := tx.Exec(
+			"UPDATE webhook_deliveries SET status = ?, updated_at = ? WHERE id = ?",
+			domainChatStorage.WebhookDeliveryDelivering, now, delivery.ID,
+		); err != nil {
+			return nil, err
+		}
+		delivery.Status = domainChatStorage.WebhookDeliveryDelivering
+	}
+
+	return deliveries, tx.Commit()
+}
+
+// MarkWebhookDelivered records a successful delivery
+func (r *SQLiteRepository) MarkWebhookDelivered(id int64, attempts int) error {
+	now := time.Now()
+	_, err := r.db.Exec(`
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, last_error = '', delivered_at = ?, updated_at = ?
+		WHERE id = ?
+	`, domainChatStorage.WebhookDeliveryDelivered, attempts, now, now, id)
+	return err
+}
+
+// ScheduleWebhookRetry puts a failed delivery back in the queue for a later attempt
+func (r *SQLiteRepository) ScheduleWebhookRetry(id int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	_, err := r.db.Exec(`
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, updated_at = ?
+		WHERE id = ?
+	`, domainChatStorage.WebhookDeliveryPending, attempts, lastError, nextAttemptAt.UnixMilli(), time.Now(), id)
+	return err
+}
+
+// MarkWebhookDead moves a delivery to the dead-letter state after its last failed attempt
+func (r *SQLiteRepository) MarkWebhookDead(id int64, attempts int, lastError string) error {
+	_, err := r.db.Exec(`
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, last_error = ?, updated_at = ?
+		WHERE id = ?
+	`, domainChatStorage.WebhookDeliveryDead, attempts, lastError, time.Now(), id)
+	return err
+}
+
+// ReleaseInFlightWebhookDeliveries returns deliveries interrupted by a shutdown to the pending state
+func (r *SQLiteRepository) ReleaseInFlightWebhookDeliveries() (int64, error) {
+	result, err := r.db.Exec(
+		"UPDATE webhook_deliveries SET status = ?, updated_at = ? WHERE status = ?",
+		domainChatStorage.WebhookDeliveryPending, time.Now(), domainChatStorage.WebhookDeliveryDelivering,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// GetWebhookDelivery retrieves a single webhook delivery
+func (r *SQLiteRepository) GetWebhookDelivery(id int64) (*domainChatStorage.WebhookDelivery, error) {
+	row := r.db.QueryRow("SELECT "+webhookDeliveryColumns+" FROM webhook_deliveries WHERE id = ?", id)
+	delivery, err := scanWebhookDelivery(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return delivery, err
+}
+
+// GetWebhookDeliveries retrieves webhook deliveries, newest first
+func (r *SQLiteRepository) GetWebhookDeliveries(filter *domainChatStorage.WebhookDeliveryFilter) ([]*domainChatStorage.WebhookDelivery, error) {
+	var args []any
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries"
+
+	if filter.Status != "" {
+		query += " WHERE status = ?"
+		args = append(args, filter.Status)
+	}
+
+	query += " ORDER BY id DESC"
+
+	if filter.Limit > 0 {
+		if filter.Limit > 1000 {
+			filter.Limit = 1000
+		}
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*domainChatStorage.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// ReplayWebhookDelivery queues a delivery again with a fresh attempt budget
+func (r *SQLiteRepository) ReplayWebhookDelivery(id int64) error {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = 0, last_error = '', next_attempt_at = ?, delivered_at = NULL, updated_at = ?
+		WHERE id = ? AND status != ?
+	`, domainChatStorage.WebhookDeliveryPending, now.UnixMilli(), now, id, domainChatStorage.WebhookDeliveryDelivering)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// PurgeWebhookDeliveries deletes deliveries in the given state and returns how many were removed
+func (r *SQLiteRepository) PurgeWebhookDeliveries(status string) (int64, error) {
+	result, err := r.db.Exec("DELETE FROM webhook_deliveries WHERE status = ?", status)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// StoreScheduledMessage persists a message to be sent once it is due
+func (r *SQLiteRepository) StoreScheduledMessage(message *domainChatStorage.ScheduledMessage) error {
+	now := time.Now()
+	if message.Status == "" {
+		message.Status = domainChatStorage.ScheduledMessagePending
+	}
+	message.NextAttemptAt = message.SendAt
+	message.CreatedAt = now
+	message.UpdatedAt = now
+
+	_, err := r.db.Exec(`
+		INSERT INTO scheduled_messages (id, device_id, type, phone, payload, media_path, send_at, status, attempts, last_error, message_id, next_attempt_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, message.ID, message.DeviceID, message.Type, message.Phone, message.Payload, message.MediaPath, message.SendAt.UnixMilli(),
+		message.Status, message.Attempts, message.LastError, message.MessageID, message.NextAttemptAt.UnixMilli(), now, now)
+	return err
+}
+
+// GetScheduledMessage retrieves a scheduled message by ID
+func (r *SQLiteRepository) GetScheduledMessage(id string) (*domainChatStorage.ScheduledMessage, error) {
+	row := r.db.QueryRow("SELECT "+scheduledMessageColumns+" FROM scheduled_messages WHERE id = ?", id)
+	message, err := scanScheduledMessage(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return message, err
+}
+
+// GetScheduledMessages retrieves scheduled messages, the ones due first
+func (r *SQLiteRepository) GetScheduledMessages(filter *domainChatStorage.ScheduledMessageFilter) ([]*domainChatStorage.ScheduledMessage, error) {
+	var conditions []string
+	var args []any
+	query := "SELECT " + scheduledMessageColumns + " FROM scheduled_messages"
+
+	if filter.DeviceID != "" {
+		conditions = append(conditions, "device_id = ?")
+		args = append(args, filter.DeviceID)
+	}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY send_at ASC, created_at ASC"
+
+	if filter.Limit > 0 {
+		if filter.Limit > 1000 {
+			filter.Limit = 1000
+		}
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*domainChatStorage.ScheduledMessage
+	for rows.Next() {
+		message, err := scanScheduledMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+// ClaimDueScheduledMessages marks up to limit pending messages that are due as sending and returns them
+func (r *SQLiteRepository) ClaimDueScheduledMessages(now time.Time, limit int) ([]*domainChatStorage.ScheduledMessage, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT `+scheduledMessageColumns+`
+		FROM scheduled_messages
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at ASC, created_at ASC
+		LIMIT ?
+	`, domainChatStorage.ScheduledMessagePending, now.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []*domainChatStorage.ScheduledMessage
+	for rows.Next() {
+		message, err := scanScheduledMessage(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, message := range messages {
+		if _, err := tx.Exec(
+			"UPDATE scheduled_messages SET status = ?, updated_at = ? WHERE id = ?",
+			domainChatStorage.ScheduledMessageSending, now, message.ID,
+		); err != nil {
+			return nil, err
+		}
+		message.Status = domainChatStorage.ScheduledMessageSending
+	}
+
+	return messages, tx.Commit()
+}
+
+// RescheduleScheduledMessage moves a pending message to a new send time
+func (r *SQLiteRepository) RescheduleScheduledMessage(id string, sendAt time.Time) error {
+	result, err := r.db.Exec(`
+		UPDATE scheduled_messages
+		SET send_at = ?, next_attempt_at = ?, attempts = 0, last_error = '', updated_at = ?
+		WHERE id = ? AND status = ?
+	`, sendAt.UnixMilli(), sendAt.UnixMilli(), time.Now(), id, domainChatStorage.ScheduledMessagePending)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// CancelScheduledMessage stops a pending message from being sent
+func (r *SQLiteRepository) CancelScheduledMessage(id string) error {
+	result, err := r.db.Exec(
+		"UPDATE scheduled_messages SET status = ?, updated_at = ? WHERE id = ? AND status = ?",
+		domainChatStorage.ScheduledMessageCancelled, time.Now(), id, domainChatStorage.ScheduledMessagePending,
+	)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// MarkScheduledMessageSent records the message ID of a dispatched message
+func (r *SQLiteRepository) MarkScheduledMessageSent(id string, attempts int, messageID string) error {
+	now := time.Now()
+	_, err := r.db.Exec(`
+		UPDATE scheduled_messages
+		SET status = ?, attempts = ?, last_error = '', message_id = ?, sent_at = ?, updated_at = ?
+		WHERE id = ?
+	`, domainChatStorage.ScheduledMessageSent, attempts, messageID, now, now, id)
+	return err
+}
+
+// RetryScheduledMessage puts a message that could not be sent back in the schedule for a later attempt
+func (r *SQLiteRepository) RetryScheduledMessage(id string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	_, err := r.db.Exec(`
+		UPDATE scheduled_messages
+		SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, updated_at = ?
+		WHERE id = ?
+	`, domainChatStorage.ScheduledMessagePending, attempts, lastError, nextAttemptAt.UnixMilli(), time.Now(), id)
+	return err
+}
+
+// MarkScheduledMessageFailed records that a message could not be sent
+func (r *SQLiteRepository) MarkScheduledMessageFailed(id string, attempts int, lastError string) error {
+	_, err := r.db.Exec(`
+		UPDATE scheduled_messages
+		SET status = ?, attempts = ?, last_error = ?, updated_at = ?
+		WHERE id = ?
+	`, domainChatStorage.ScheduledMessageFailed, attempts, lastError, time.Now(), id)
+	return err
+}
+
+// ReleaseInFlightScheduledMessages returns messages interrupted by a shutdown to the pending state
+func (r *SQLiteRepository) ReleaseInFlightScheduledMessages() (int64, error) {
+	result, err := r.db.Exec(
+		"UPDATE scheduled_messages SET status = ?, updated_at = ? WHERE status = ?",
+		domainChatStorage.ScheduledMessagePending, time.Now(), domainChatStorage.ScheduledMessageSending,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// StoreCampaign persists a campaign together with its recipients
+func (r *SQLiteRepository) StoreCampaign(campaign *domainChatStorage.Campaign, recipients []*domainChatStorage.CampaignRecipient) error {
+	now := time.Now()
+	if campaign.Status == "" {
+		campaign.Status = domainChatStorage.CampaignRunning
+	}
+	campaign.CreatedAt = now
+	campaign.UpdatedAt = now
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO campaigns (id, device_id, name, message, media_path, media_type, rate_per_minute, min_delay_seconds, max_delay_seconds, validate_numbers, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, campaign.ID, campaign.DeviceID, campaign.Name, campaign.Message, campaign.MediaPath, campaign.MediaType, campaign.RatePerMinute,
+		campaign.MinDelaySeconds, campaign.MaxDelaySeconds, campaign.ValidateNumbers, campaign.Status, now, now); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO campaign_recipients (campaign_id, phone, variables, status, message_id, error, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, recipient := range recipients {
+		recipient.CampaignID = campaign.ID
+		if recipient.Status == "" {
+			recipient.Status = domainChatStorage.CampaignRecipientQueued
+		}
+		if recipient.Variables == "" {
+			recipient.Variables = "{}"
+		}
+		recipient.UpdatedAt = now
+
+		result, err := stmt.Exec(recipient.CampaignID, recipient.Phone, recipient.Variables, recipient.Status, recipient.MessageID, recipient.Error, now)
+		if err != nil {
+			return err
+		}
+		if recipient.ID, err = result.LastInsertId(); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetCampaign retrieves a campaign by ID
+func (r *SQLiteRepository) GetCampaign(id string) (*domainChatStorage.Campaign, error) {
+	row := r.db.QueryRow("SELECT "+campaignColumns+" FROM campaigns WHERE id = ?", id)
+	campaign, err := scanCampaign(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return campaign, err
+}
+
+// GetCampaigns retrieves campaigns, newest first
+func (r *SQLiteRepository) GetCampaigns(filter *domainChatStorage.CampaignFilter) ([]*domainChatStorage.Campaign, error) {
+	var conditions []string
+	var args []any
+	query := "SELECT " + campaignColumns + " FROM campaigns"
+
+	if filter.DeviceID != "" {
+		conditions = append(conditions, "device_id = ?")
+		args = append(args, filter.DeviceID)
+	}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY created_at DESC, id DESC"
+
+	if filter.Limit > 0 {
+		if filter.Limit > 1000 {
+			filter.Limit = 1000
+		}
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var campaigns []*domainChatStorage.Campaign
+	for rows.Next() {
+		campaign, err := scanCampaign(rows)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, campaign)
+	}
+
+	return campaigns, rows.Err()
+}
+
+// UpdateCampaignStatus moves a campaign to status, only from one of the given states when any are passed
+func (r *SQLiteRepository) UpdateCampaignStatus(id string, status string, from ...string) error {
+	now := time.Now()
+	query := "UPDATE campaigns SET status = ?, updated_at = ?"
+	args := []any{status, now}
+
+	if status == domainChatStorage.CampaignCompleted || status == domainChatStorage.CampaignCancelled {
+		query += ", completed_at = ?"
+		args = append(args, now)
+	}
+
+	query += " WHERE id = ?"
+	args = append(args, id)
+
+	if len(from) > 0 {
+		query += " AND status IN (" + strings.TrimSuffix(strings.Repeat("?, ", len(from)), ", ") + ")"
+		for _, state := range from {
+			args = append(args, state)
+		}
+	}
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// GetCampaignRecipients retrieves the recipients of a campaign in the order they are sent to
+func (r *SQLiteRepository) GetCampaignRecipients(filter *domainChatStorage.CampaignRecipientFilter) ([]*domainChatStorage.CampaignRecipient, error) {
+	query := "SELECT " + campaignRecipientColumns + " FROM campaign_recipients WHERE campaign_id = ?"
+	args := []any{filter.CampaignID}
+
+	if filter.Status != "" {
+		query += " AND status = ?"
+		args = append(args, filter.Status)
+	}
+
+	query += " ORDER BY id ASC"
+
+	if filter.Limit > 0 {
+		if filter.Limit > 1000 {
+			filter.Limit = 1000
+		}
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []*domainChatStorage.CampaignRecipient
+	for rows.Next() {
+		recipient, err := scanCampaignRecipient(rows)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, rows.Err()
+}
+
+// CountCampaignRecipients returns the number of recipients of a campaign per status
+func (r *SQLiteRepository) CountCampaignRecipients(campaignID string) (map[string]int, error) {
+	rows, err := r.db.Query("SELECT status, COUNT(*) FROM campaign_recipients WHERE campaign_id = ? GROUP BY status", campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// MarkCampaignRecipientSending records that the message of a queued campaign recipient is about to be sent
+func (r *SQLiteRepository) MarkCampaignRecipientSending(id int64) error {
+	result, err := r.db.Exec(
+		"UPDATE campaign_recipients SET status = ?, updated_at = ? WHERE id = ? AND status = ?",
+		domainChatStorage.CampaignRecipientSending, time.Now(), id, domainChatStorage.CampaignRecipientQueued,
+	)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// MarkCampaignRecipientSent records the message ID sent to a campaign recipient
+func (r *SQLiteRepository) MarkCampaignRecipientSent(id int64, messageID string) error {
+	now := time.Now()
+	_, err := r.db.Exec(`
+		UPDATE campaign_recipients
+		SET status = ?, message_id = ?, error = '', sent_at = ?, updated_at = ?
+		WHERE id = ?
+	`, domainChatStorage.CampaignRecipientSent, messageID, now, now, id)
+	return err
+}
+
+// MarkCampaignRecipientFailed records why a campaign recipient could not be sent to
+func (r *SQLiteRepository) MarkCampaignRecipientFailed(id int64, reason string) error {
+	_, err := r.db.Exec(
+		"UPDATE campaign_recipients SET status = ?, error = ?, updated_at = ? WHERE id = ?",
+		domainChatStorage.CampaignRecipientFailed, reason, time.Now(), id,
+	)
+	return err
+}
+
+// RequeueCampaignRecipient returns a recipient whose message was not sent to the queue, keeping reason as its last error
+func (r *SQLiteRepository) RequeueCampaignRecipient(id int64, reason string) error {
+	_, err := r.db.Exec(
+		"UPDATE campaign_recipients SET status = ?, error = ?, updated_at = ? WHERE id = ? AND status = ?",
+		domainChatStorage.CampaignRecipientQueued, reason, time.Now(), id, domainChatStorage.CampaignRecipientSending,
+	)
+	return err
+}
+
+// FlagCampaignRecipientForReview moves a recipient whose send ended without a known outcome to needs review,
+// keeping reason as its last error
+func (r *SQLiteRepository) FlagCampaignRecipientForReview(id int64, reason string) error {
+	_, err := r.db.Exec(
+		"UPDATE campaign_recipients SET status = ?, error = ?, updated_at = ? WHERE id = ? AND status = ?",
+		domainChatStorage.CampaignRecipientNeedsReview, reason, time.Now(), id, domainChatStorage.CampaignRecipientSending,
+	)
+	return err
+}
+
+// FlagInterruptedCampaignRecipients moves the recipients of a campaign left sending to needs review, since their
+// message may have been sent before the outcome was recorded
+func (r *SQLiteRepository) FlagInterruptedCampaignRecipients(campaignID string, reason string) (int64, error) {
+	result, err := r.db.Exec(
+		"UPDATE campaign_recipients SET status = ?, error = ?, updated_at = ? WHERE campaign_id = ? AND status = ?",
+		domainChatStorage.CampaignRecipientNeedsReview, reason, time.Now(), campaignID, domainChatStorage.CampaignRecipientSending,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// UpdateCampaignRecipientReceipt advances the recipients of the given messages to delivered or read
+func (r *SQLiteRepository) UpdateCampaignRecipientReceipt(messageIDs []string, status string, at time.Time) (int64, error) {
+	sources := campaignReceiptSources(status)
+	if len(messageIDs) == 0 || len(sources) == 0 {
+		return 0, nil
+	}
+
+	query := "UPDATE campaign_recipients SET status = ?, delivered_at = COALESCE(delivered_at, ?), updated_at = ?"
+	args := []any{status, at, time.Now()}
+
+	if status == domainChatStorage.CampaignRecipientRead {
+		query += ", read_at = ?"
+		args = append(args, at)
+	}
+
+	query += " WHERE message_id IN (" + strings.TrimSuffix(strings.Repeat("?, ", len(messageIDs)), ", ") + ")"
+	for _, messageID := range messageIDs {
+		args = append(args, messageID)
+	}
+
+	query += " AND status IN (" + strings.TrimSuffix(strings.Repeat("?, ", len(sources)), ", ") + ")"
+	for _, source := range sources {
+		args = append(args, source)
+	}
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const sqliteAutoReplyRuleUpsert = `
+	INSERT INTO auto_reply_rules (id, name, position, enabled, device_id, conditions, actions, cooldown_seconds, continue_matching, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(id) DO UPDATE SET
+		name = excluded.name,
+		position = excluded.position,
+		enabled = excluded.enabled,
+		device_id = excluded.device_id,
+		conditions = excluded.conditions,
+		actions = excluded.actions,
+		cooldown_seconds = excluded.cooldown_seconds,
+		continue_matching = excluded.continue_matching,
+		updated_at = excluded.updated_at
+`
+
+// StoreAutoReplyRule creates or updates an auto-reply rule
+func (r *SQLiteRepository) StoreAutoReplyRule(rule *domainChatStorage.AutoReplyRule) error {
+	prepareAutoReplyRule(rule, time.Now())
+	_, err := r.db.Exec(sqliteAutoReplyRuleUpsert, autoReplyRuleArgs(rule)...)
+	return err
+}
+
+// GetAutoReplyRule retrieves an auto-reply rule by ID
+func (r *SQLiteRepository) GetAutoReplyRule(id string) (*domainChatStorage.AutoReplyRule, error) {
+	row := r.db.QueryRow("SELECT "+autoReplyRuleColumns+" FROM auto_reply_rules WHERE id = ?", id)
+	rule, err := scanAutoReplyRule(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return rule, err
+}
+
+// GetAutoReplyRules returns all auto-reply rules in evaluation order
+func (r *SQLiteRepository) GetAutoReplyRules() ([]*domainChatStorage.AutoReplyRule, error) {
+	rows, err := r.db.Query("SELECT " + autoReplyRuleColumns + " FROM auto_reply_rules ORDER BY position ASC, created_at ASC, id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*domainChatStorage.AutoReplyRule
+	for rows.Next() {
+		rule, err := scanAutoReplyRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// DeleteAutoReplyRule removes an auto-reply rule
+func (r *SQLiteRepository) DeleteAutoReplyRule(id string) error {
+	_, err := r.db.Exec("DELETE FROM auto_reply_rules WHERE id = ?", id)
+	return err
+}
+
+// ReplaceAutoReplyRules atomically replaces every auto-reply rule with the given ones
+func (r *SQLiteRepository) ReplaceAutoReplyRules(rules []*domainChatStorage.AutoReplyRule) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM auto_reply_rules"); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, rule := range rules {
+		prepareAutoReplyRule(rule, now)
+		if _, err := tx.Exec(sqliteAutoReplyRuleUpsert, autoReplyRuleArgs(rule)...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// StoreAPIKey creates an API key
+func (r *SQLiteRepository) StoreAPIKey(key *domainChatStorage.APIKey) error {
+	args, err := apiKeyArgs(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec("INSERT INTO api_keys ("+apiKeyColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)", args...)
+	return err
+}
+
+// GetAPIKey retrieves an API key by ID
+func (r *SQLiteRepository) GetAPIKey(id string) (*domainChatStorage.APIKey, error) {
+	row := r.db.QueryRow("SELECT "+apiKeyColumns+" FROM api_keys WHERE id = ?", id)
+	key, err := scanAPIKey(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return key, err
+}
+
+// GetAPIKeyByHash retrieves the API key with the given key hash
+func (r *SQLiteRepository) GetAPIKeyByHash(keyHash string) (*domainChatStorage.APIKey, error) {
+	row := r.db.QueryRow("SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = ?", keyHash)
+	key, err := scanAPIKey(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return key, err
+}
+
+// GetAPIKeys returns all API keys, oldest first
+func (r *SQLiteRepository) GetAPIKeys() ([]*domainChatStorage.APIKey, error) {
+	rows, err := r.db.Query("SELECT " + apiKeyColumns + " FROM api_keys ORDER BY created_at ASC, id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*domainChatStorage.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// CountAPIKeys returns the number of API keys
+func (r *SQLiteRepository) CountAPIKeys() (int64, error) {
+	return r.getCount("SELECT COUNT(*) FROM api_keys")
+}
+
+// TouchAPIKey records when an API key was last used
+func (r *SQLiteRepository) TouchAPIKey(id string, usedAt time.Time) error {
+	_, err := r.db.Exec("UPDATE api_keys SET last_used_at = ? WHERE id = ?", usedAt, id)
+	return err
+}
+
+// DeleteAPIKey removes an API key, revoking it immediately
+func (r *SQLiteRepository) DeleteAPIKey(id string) error {
+	_, err := r.db.Exec("DELETE FROM api_keys WHERE id = ?", id)
+	return err
+}
+
+// StoreAuditLog appends an entry to the audit log
+func (r *SQLiteRepository) StoreAuditLog(entry *domainChatStorage.AuditLog) error {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	result, err := r.db.Exec(`
+		INSERT INTO audit_logs (key_id, actor, source, device_id, action, target, status, error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, entry.KeyID, entry.Actor, entry.Source, entry.DeviceID, entry.Action, entry.Target, entry.Status, entry.Error,
+		entry.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	entry.ID, err = result.LastInsertId()
+	return err
+}
+
+// GetAuditLogs returns audit log entries, newest first
+func (r *SQLiteRepository) GetAuditLogs(filter *domainChatStorage.AuditLogFilter) ([]*domainChatStorage.AuditLog, error) {
+	var args []any
+	query := "SELECT " + auditLogColumns + " FROM audit_logs"
+
+	if filter.KeyID != "" {
+		query += " WHERE key_id = ?"
+		args = append(args, filter.KeyID)
+	}
+
+	query += " ORDER BY id DESC"
+
+	if filter.Limit > 0 {
+		if filter.Limit > 1000 {
+			filter.Limit = 1000
+		}
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domainChatStorage.AuditLog
+	for rows.Next() {
+		entry, err := scanAuditLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// _____________________________________________________________________________________________________________________
+
+// initializeSchema creates or migrates the database schema
+func (r *SQLiteRepository) InitializeSchema() error {
+	// Get current schema version
+	version, err := r.getSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	// Run migrations based on version
+	migrations := r.getMigrations()
+	for i := version; i < len(migrations); i++ {
+		err := r.runMigration(migrations[i], i+1)
+		if err != nil && isFTS5Unavailable(err) {
+			// The search index is optional, message search falls back to LIKE scans without it
+			logrus.Warn("[CHATSTORAGE] SQLite was built without FTS5, message search will not use an index. Build with -tags sqlite_fts5 to enable it")
+			err = r.runMigration("SELECT 1", i+1)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to run migration %d: %w", i+1, err)
+		}
+	}
+
+	return r.ensureMessageSearchIndex()
+}
+
+// ensureMessageSearchIndex creates the search index when the schema was migrated by a build without FTS5
+func (r *SQLiteRepository) ensureMessageSearchIndex() error {
+	indexed, err := r.hasMessageSearchIndex()
+	if err != nil || indexed {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(messageSearchMigration); err != nil {
+		if isFTS5Unavailable(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to create message search index: %w", err)
+	}
+
+	logrus.Info("[CHATSTORAGE] Created message search index")
+	return tx.Commit()
+}
+
+// isFTS5Unavailable reports whether SQLite was compiled without the FTS5 extension
+func isFTS5Unavailable(err error) bool {
+	return strings.Contains(err.Error(), "no such module: fts5")
+}
+
+// getSchemaVersion returns the current schema version
+func (r *SQLiteRepository) getSchemaVersion() (int, error) {
+	// Create schema_info table if it doesn't exist
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_info (
 			version INTEGER PRIMARY KEY DEFAULT 0,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)
@@ -810,5 +2413,308 @@ func (r *SQLiteRepository) getMigrations() []string {
 		`
 		CREATE INDEX IF NOT EXISTS idx_messages_id ON messages(id);
 		`,
+
+		// Migration 3: Device registry for multi-device sessions
+		`
+		CREATE TABLE IF NOT EXISTS devices (
+			id TEXT PRIMARY KEY,
+			jid TEXT NOT NULL DEFAULT '',
+			webhooks TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		`,
+
+		// Migration 4: Durable webhook delivery queue
+		`
+		CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			device_id TEXT NOT NULL DEFAULT '',
+			event TEXT NOT NULL DEFAULT '',
+			url TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			next_attempt_at INTEGER NOT NULL,
+			delivered_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+		`,
+
+		// Migration 5: Webhook subscriptions with per-webhook secrets and filters
+		`
+		CREATE TABLE IF NOT EXISTS webhooks (
+			id TEXT PRIMARY KEY,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL DEFAULT '',
+			device_id TEXT NOT NULL DEFAULT '',
+			events TEXT NOT NULL DEFAULT '[]',
+			allow_jids TEXT NOT NULL DEFAULT '[]',
+			deny_jids TEXT NOT NULL DEFAULT '[]',
+			skip_from_me BOOLEAN NOT NULL DEFAULT FALSE,
+			chat_type TEXT NOT NULL DEFAULT 'all',
+			include_media BOOLEAN NOT NULL DEFAULT TRUE,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		ALTER TABLE webhook_deliveries ADD COLUMN webhook_id TEXT NOT NULL DEFAULT '';
+		`,
+
+		// Migration 6: Full-text search index over message content
+		messageSearchMigration,
+
+		// Migration 7: Messages scheduled for later delivery
+		`
+		CREATE TABLE IF NOT EXISTS scheduled_messages (
+			id TEXT PRIMARY KEY,
+			device_id TEXT NOT NULL DEFAULT '',
+			type TEXT NOT NULL,
+			phone TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			media_path TEXT NOT NULL DEFAULT '',
+			send_at INTEGER NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			message_id TEXT NOT NULL DEFAULT '',
+			next_attempt_at INTEGER NOT NULL,
+			sent_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_scheduled_messages_due ON scheduled_messages(status, next_attempt_at);
+		`,
+
+		// Migration 8: Bulk send campaigns and their recipients
+		`
+		CREATE TABLE IF NOT EXISTS campaigns (
+			id TEXT PRIMARY KEY,
+			device_id TEXT NOT NULL DEFAULT '',
+			name TEXT NOT NULL DEFAULT '',
+			message TEXT NOT NULL DEFAULT '',
+			media_path TEXT NOT NULL DEFAULT '',
+			media_type TEXT NOT NULL DEFAULT '',
+			rate_per_minute INTEGER NOT NULL DEFAULT 0,
+			min_delay_seconds INTEGER NOT NULL DEFAULT 0,
+			max_delay_seconds INTEGER NOT NULL DEFAULT 0,
+			validate_numbers BOOLEAN NOT NULL DEFAULT TRUE,
+			status TEXT NOT NULL DEFAULT 'running',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			completed_at TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS campaign_recipients (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			campaign_id TEXT NOT NULL,
+			phone TEXT NOT NULL,
+			variables TEXT NOT NULL DEFAULT '{}',
+			status TEXT NOT NULL DEFAULT 'queued',
+			message_id TEXT NOT NULL DEFAULT '',
+			error TEXT NOT NULL DEFAULT '',
+			sent_at TIMESTAMP,
+			delivered_at TIMESTAMP,
+			read_at TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_campaign_recipients_campaign ON campaign_recipients(campaign_id, status, id);
+		CREATE INDEX IF NOT EXISTS idx_campaign_recipients_message ON campaign_recipients(message_id);
+		`,
+
+		// Migration 9: Auto-reply rules
+		`
+		CREATE TABLE IF NOT EXISTS auto_reply_rules (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL DEFAULT '',
+			position INTEGER NOT NULL DEFAULT 0,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			device_id TEXT NOT NULL DEFAULT '',
+			conditions TEXT NOT NULL DEFAULT '{}',
+			actions TEXT NOT NULL DEFAULT '[]',
+			cooldown_seconds INTEGER NOT NULL DEFAULT 0,
+			continue_matching BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_auto_reply_rules_position ON auto_reply_rules(position, created_at);
+		`,
+
+		// Migration 10: API keys and the audit log
+		`
+		CREATE TABLE IF NOT EXISTS api_keys (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			key_hash TEXT NOT NULL UNIQUE,
+			key_prefix TEXT NOT NULL DEFAULT '',
+			scopes TEXT NOT NULL DEFAULT '[]',
+			allowed_recipients TEXT NOT NULL DEFAULT '[]',
+			expires_at TIMESTAMP,
+			last_used_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS audit_logs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			key_id TEXT NOT NULL DEFAULT '',
+			actor TEXT NOT NULL DEFAULT '',
+			source TEXT NOT NULL DEFAULT '',
+			device_id TEXT NOT NULL DEFAULT '',
+			action TEXT NOT NULL,
+			target TEXT NOT NULL DEFAULT '',
+			status INTEGER NOT NULL DEFAULT 0,
+			error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_audit_logs_key ON audit_logs(key_id, id);
+		`,
+
+		// Migration 11: Delivery and read receipts of outgoing messages
+		`
+		CREATE TABLE IF NOT EXISTS message_receipts (
+			message_id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			participant_jid TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			timestamp TIMESTAMP NOT NULL,
+			PRIMARY KEY (message_id, chat_jid, participant_jid, status)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_message_receipts_chat ON message_receipts(chat_jid);
+		`,
+
+		// Migration 12: Call log
+		`
+		CREATE TABLE IF NOT EXISTS calls (
+			id TEXT PRIMARY KEY,
+			chat_jid TEXT NOT NULL,
+			caller_jid TEXT NOT NULL DEFAULT '',
+			is_from_me BOOLEAN NOT NULL DEFAULT FALSE,
+			is_video BOOLEAN NOT NULL DEFAULT FALSE,
+			is_group BOOLEAN NOT NULL DEFAULT FALSE,
+			status TEXT NOT NULL,
+			auto_rejected BOOLEAN NOT NULL DEFAULT FALSE,
+			end_reason TEXT NOT NULL DEFAULT '',
+			started_at TIMESTAMP NOT NULL,
+			accepted_at TIMESTAMP,
+			ended_at TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_calls_chat ON calls(chat_jid, started_at);
+		CREATE INDEX IF NOT EXISTS idx_calls_started ON calls(started_at);
+		`,
+
+		// Migration 13: Polls and the current vote of each voter
+		`
+		CREATE TABLE IF NOT EXISTS polls (
+			message_id TEXT PRIMARY KEY,
+			chat_jid TEXT NOT NULL,
+			creator_jid TEXT NOT NULL DEFAULT '',
+			question TEXT NOT NULL DEFAULT '',
+			options TEXT NOT NULL DEFAULT '[]',
+			selectable_count INTEGER NOT NULL DEFAULT 0,
+			secret BLOB,
+			created_at TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS poll_votes (
+			poll_id TEXT NOT NULL,
+			voter_jid TEXT NOT NULL,
+			selected_options TEXT NOT NULL DEFAULT '[]',
+			voted_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (poll_id, voter_jid)
+		);
+		`,
+
+		// Migration 14: Edit history, reactions and revocation of messages
+		`
+		ALTER TABLE messages ADD COLUMN edited_at TIMESTAMP;
+		ALTER TABLE messages ADD COLUMN is_revoked BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE messages ADD COLUMN revoked_at TIMESTAMP;
+
+		CREATE TABLE IF NOT EXISTS message_edits (
+			id TEXT PRIMARY KEY,
+			message_id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			old_content TEXT NOT NULL DEFAULT '',
+			new_content TEXT NOT NULL DEFAULT '',
+			edited_at TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_message_edits_message ON message_edits(message_id, chat_jid);
+
+		CREATE TABLE IF NOT EXISTS message_reactions (
+			message_id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			sender_jid TEXT NOT NULL,
+			reaction TEXT NOT NULL,
+			reacted_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (message_id, chat_jid, sender_jid)
+		);
+		`,
+
+		// Migration 15: History of group membership and settings changes
+		`
+		CREATE TABLE IF NOT EXISTS group_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			group_jid TEXT NOT NULL,
+			type TEXT NOT NULL,
+			actor_jid TEXT NOT NULL DEFAULT '',
+			target_jid TEXT NOT NULL DEFAULT '',
+			value TEXT NOT NULL DEFAULT '',
+			timestamp TIMESTAMP NOT NULL,
+			UNIQUE (group_jid, type, target_jid, timestamp)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_group_events_group ON group_events(group_jid, timestamp);
+		`,
+
+		// Migration 16: Bounded log of the events replayed by the event stream
+		`
+		CREATE TABLE IF NOT EXISTS stream_events (
+			id INTEGER PRIMARY KEY,
+			event TEXT NOT NULL,
+			device_id TEXT NOT NULL DEFAULT '',
+			chat_jid TEXT NOT NULL DEFAULT '',
+			data TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		);
+		`,
 	}
 }
+
+// messageSearchMigration creates an external-content FTS5 index over messages.content,
+// kept in sync by triggers and populated from the existing rows
+const messageSearchMigration = `
+		CREATE VIRTUAL TABLE IF NOT EXISTS messages_fts USING fts5(
+			content,
+			content = 'messages',
+			content_rowid = 'rowid',
+			tokenize = 'unicode61 remove_diacritics 2'
+		);
+
+		CREATE TRIGGER IF NOT EXISTS messages_fts_insert AFTER INSERT ON messages BEGIN
+			INSERT INTO messages_fts(rowid, content) VALUES (new.rowid, new.content);
+		END;
+
+		CREATE TRIGGER IF NOT EXISTS messages_fts_delete AFTER DELETE ON messages BEGIN
+			INSERT INTO messages_fts(messages_fts, rowid, content) VALUES ('delete', old.rowid, old.content);
+		END;
+
+		CREATE TRIGGER IF NOT EXISTS messages_fts_update AFTER UPDATE OF content ON messages BEGIN
+			INSERT INTO messages_fts(messages_fts, rowid, content) VALUES ('delete', old.rowid, old.content);
+			INSERT INTO messages_fts(rowid, content) VALUES (new.rowid, new.content);
+		END;
+
+		INSERT INTO messages_fts(messages_fts) VALUES ('rebuild');
+		`
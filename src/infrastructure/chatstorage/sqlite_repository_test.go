@@ -0,0 +1,219 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRepository(t *testing.T) *SQLiteRepository {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", "file::memory:?_foreign_keys=on")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	repo := &SQLiteRepository{db: db}
+	require.NoError(t, repo.InitializeSchema())
+	return repo
+}
+
+func seedSearchMessages(t *testing.T, repo *SQLiteRepository) {
+	t.Helper()
+
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.StoreChat(&domainChatStorage.Chat{JID: "120363025246125486@g.us", Name: "Finance", LastMessageTime: base}))
+	require.NoError(t, repo.StoreChat(&domainChatStorage.Chat{JID: "628111@s.whatsapp.net", Name: "Alice", LastMessageTime: base}))
+
+	require.NoError(t, repo.StoreMessagesBatch([]*domainChatStorage.Message{
+		{ID: "m1", ChatJID: "120363025246125486@g.us", Sender: "628111@s.whatsapp.net", Content: "Please send the monthly report today", Timestamp: base},
+		{ID: "m2", ChatJID: "120363025246125486@g.us", Sender: "628222@s.whatsapp.net", Content: "Invoice attached", MediaType: "document", Filename: "invoice.pdf", Timestamp: base.Add(time.Hour)},
+		{ID: "m3", ChatJID: "628111@s.whatsapp.net", Sender: "628111:12@s.whatsapp.net", Content: "Report is late, invoices tomorrow", Timestamp: base.Add(2 * time.Hour)},
+		{ID: "m4", ChatJID: "628111@s.whatsapp.net", Sender: "628999@s.whatsapp.net", Content: "Lunch?", Timestamp: base.Add(3 * time.Hour), IsFromMe: true},
+	}))
+}
+
+func resultIDs(results []*domainChatStorage.MessageSearchResult) []string {
+	ids := make([]string, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.Message.ID)
+	}
+	return ids
+}
+
+func TestSearchAllMessages(t *testing.T) {
+	repo := newTestRepository(t)
+	seedSearchMessages(t, repo)
+
+	indexed, err := repo.hasMessageSearchIndex()
+	require.NoError(t, err)
+	t.Logf("search index available: %v", indexed)
+
+	after := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		filter domainChatStorage.MessageSearchFilter
+		want   []string
+	}{
+		{
+			name:   "matches across chats",
+			filter: domainChatStorage.MessageSearchFilter{Query: "report", Sort: domainChatStorage.SearchSortNewest},
+			want:   []string{"m3", "m1"},
+		},
+		{
+			name:   "restricts to chat",
+			filter: domainChatStorage.MessageSearchFilter{Query: "report", ChatJID: "120363025246125486@g.us"},
+			want:   []string{"m1"},
+		},
+		{
+			name:   "matches sender phone on any device",
+			filter: domainChatStorage.MessageSearchFilter{Query: "report", Sender: "628111", Sort: domainChatStorage.SearchSortNewest},
+			want:   []string{"m3", "m1"},
+		},
+		{
+			name:   "filters by date",
+			filter: domainChatStorage.MessageSearchFilter{Query: "invoice*", StartTime: &after, Sort: domainChatStorage.SearchSortNewest},
+			want:   []string{"m3", "m2"},
+		},
+		{
+			name:   "filters by media type",
+			filter: domainChatStorage.MessageSearchFilter{Query: "invoice*", MediaType: "document"},
+			want:   []string{"m2"},
+		},
+		{
+			name:   "empty query returns nothing",
+			filter: domainChatStorage.MessageSearchFilter{Query: "  "},
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := repo.SearchAllMessages(&tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resultIDs(results))
+
+			count, err := repo.CountSearchMessages(&tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.want)), count)
+		})
+	}
+}
+
+func TestSearchAllMessages_SnippetAndChatName(t *testing.T) {
+	repo := newTestRepository(t)
+	seedSearchMessages(t, repo)
+
+	results, err := repo.SearchAllMessages(&domainChatStorage.MessageSearchFilter{Query: "monthly"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Finance", results[0].ChatName)
+	assert.Contains(t, results[0].Snippet, searchHighlightStart+"monthly"+searchHighlightEnd)
+}
+
+func TestSearchAllMessages_IndexFollowsUpdatesAndDeletes(t *testing.T) {
+	repo := newTestRepository(t)
+	seedSearchMessages(t, repo)
+
+	message, err := repo.GetMessageByID("m4")
+	require.NoError(t, err)
+	message.Content = "Dinner instead?"
+	require.NoError(t, repo.StoreMessage(message))
+	require.NoError(t, repo.DeleteMessage("m1", "120363025246125486@g.us"))
+
+	results, err := repo.SearchAllMessages(&domainChatStorage.MessageSearchFilter{Query: "lunch"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	results, err = repo.SearchAllMessages(&domainChatStorage.MessageSearchFilter{Query: "dinner"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m4"}, resultIDs(results))
+
+	results, err = repo.SearchAllMessages(&domainChatStorage.MessageSearchFilter{Query: "monthly"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestSearchAllMessages_QuerySyntax(t *testing.T) {
+	repo := newTestRepository(t)
+	indexed, err := repo.hasMessageSearchIndex()
+	require.NoError(t, err)
+	if !indexed {
+		t.Skip("SQLite built without FTS5, run with -tags sqlite_fts5")
+	}
+	seedSearchMessages(t, repo)
+
+	results, err := repo.SearchAllMessages(&domainChatStorage.MessageSearchFilter{Query: `"monthly report"`})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m1"}, resultIDs(results))
+
+	results, err = repo.SearchAllMessages(&domainChatStorage.MessageSearchFilter{Query: "report NOT late"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m1"}, resultIDs(results))
+
+	results, err = repo.SearchAllMessages(&domainChatStorage.MessageSearchFilter{Query: "lunch OR attached", Sort: domainChatStorage.SearchSortNewest})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m4", "m2"}, resultIDs(results))
+
+	_, err = repo.SearchAllMessages(&domainChatStorage.MessageSearchFilter{Query: `"unbalanced`})
+	assert.ErrorIs(t, err, domainChatStorage.ErrInvalidSearchQuery)
+}
+
+func TestSearchMessages_ChatScoped(t *testing.T) {
+	repo := newTestRepository(t)
+	seedSearchMessages(t, repo)
+
+	messages, err := repo.SearchMessages("628111@s.whatsapp.net", "invoice", 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "m3", messages[0].ID)
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	snippet := highlightSnippet("Please send the Monthly report", []string{"monthly", "report"})
+	assert.Equal(t, "Please send the <mark>Monthly</mark> <mark>report</mark>", snippet)
+
+	long := strings.Repeat("word ", 40) + "needle" + strings.Repeat(" word", 40)
+	snippet = highlightSnippet(long, []string{"needle"})
+	assert.True(t, strings.HasPrefix(snippet, "…"))
+	assert.True(t, strings.HasSuffix(snippet, "…"))
+	assert.Contains(t, snippet, "<mark>needle</mark>")
+}
+
+func TestStreamEvents(t *testing.T) {
+	repo := newTestRepository(t)
+
+	lastID, err := repo.GetLastStreamEventID()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), lastID)
+
+	for id := int64(1); id <= 5; id++ {
+		require.NoError(t, repo.StoreStreamEvent(&domainChatStorage.StreamEvent{
+			ID: id, Event: "message", DeviceID: "device-1", ChatJID: "628111@s.whatsapp.net", Data: `{"event":"message"}`,
+		}))
+	}
+	require.NoError(t, repo.TrimStreamEvents(2))
+
+	lastID, err = repo.GetLastStreamEventID()
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), lastID)
+
+	events, err := repo.GetStreamEventsAfter(1, 2)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, int64(3), events[0].ID)
+	assert.Equal(t, int64(4), events[1].ID)
+	assert.Equal(t, `{"event":"message"}`, events[0].Data)
+
+	require.NoError(t, repo.TruncateAllChats())
+	events, err = repo.GetStreamEventsAfter(0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
@@ -0,0 +1,518 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+const (
+	// autoReplyFlagRuleID identifies the catch-all rule built from the --autoreply option
+	autoReplyFlagRuleID = "autoreply-flag"
+	// autoReplyCooldownPruneSize is the number of tracked cooldowns above which expired ones are dropped
+	autoReplyCooldownPruneSize = 1000
+)
+
+// AutoReplySender sends the reply of an auto-reply rule to a chat, optionally quoting the
+// message that triggered it, and returns the WhatsApp message ID
+type AutoReplySender func(ctx context.Context, chat string, quotedMessageID string, action domainAutoReply.Action) (messageID string, err error)
+
+// autoReplyRule is a stored rule decoded and compiled for matching
+type autoReplyRule struct {
+	ID               string
+	DeviceID         string
+	Conditions       domainAutoReply.Conditions
+	Actions          []domainAutoReply.Action
+	Cooldown         time.Duration
+	ContinueMatching bool
+
+	patterns []*regexp.Regexp
+	location *time.Location
+}
+
+// autoReplyMessage is the part of an incoming message the rules look at
+type autoReplyMessage struct {
+	Text     string
+	Type     string
+	ChatType string
+}
+
+// autoReplyEngine evaluates the configured rules against incoming messages
+type autoReplyEngine struct {
+	mu    sync.RWMutex
+	repo  domainChatStorage.IChatStorageRepository
+	send  AutoReplySender
+	rules []*autoReplyRule
+
+	cooldownMu sync.Mutex
+	cooldowns  map[string]time.Time
+	now        func() time.Time
+}
+
+var autoReplies = newAutoReplyEngine()
+
+func newAutoReplyEngine() *autoReplyEngine {
+	return &autoReplyEngine{
+		cooldowns: make(map[string]time.Time),
+		now:       time.Now,
+	}
+}
+
+// InitAutoReply loads the persisted auto-reply rules and sets how replies are sent
+func InitAutoReply(repo domainChatStorage.IChatStorageRepository, send AutoReplySender) {
+	autoReplies.mu.Lock()
+	autoReplies.repo = repo
+	autoReplies.send = send
+	autoReplies.mu.Unlock()
+
+	if err := ReloadAutoReplyRules(); err != nil {
+		logrus.Errorf("[AUTO_REPLY] Failed to load auto-reply rules: %v", err)
+	}
+}
+
+// ReloadAutoReplyRules refreshes the cached rules after they were changed
+func ReloadAutoReplyRules() error {
+	autoReplies.mu.Lock()
+	defer autoReplies.mu.Unlock()
+
+	if autoReplies.repo == nil {
+		return nil
+	}
+
+	stored, err := autoReplies.repo.GetAutoReplyRules()
+	if err != nil {
+		return err
+	}
+
+	rules := make([]*autoReplyRule, 0, len(stored))
+	for _, rule := range stored {
+		if !rule.Enabled {
+			continue
+		}
+		compiled, err := compileAutoReplyRule(rule)
+		if err != nil {
+			// Rules are validated before they are stored, a broken one only disables itself
+			logrus.Errorf("[AUTO_REPLY] Skipping invalid rule %s: %v", rule.ID, err)
+			continue
+		}
+		rules = append(rules, compiled)
+	}
+	autoReplies.rules = rules
+	return nil
+}
+
+// compileAutoReplyRule decodes a stored rule and prepares its patterns and time zone
+func compileAutoReplyRule(stored *domainChatStorage.AutoReplyRule) (*autoReplyRule, error) {
+	rule := &autoReplyRule{
+		ID:               stored.ID,
+		DeviceID:         stored.DeviceID,
+		Cooldown:         time.Duration(stored.CooldownSeconds) * time.Second,
+		ContinueMatching: stored.ContinueMatching,
+	}
+	if err := json.Unmarshal([]byte(stored.Conditions), &rule.Conditions); err != nil {
+		return nil, fmt.Errorf("invalid conditions: %w", err)
+	}
+	if err := json.Unmarshal([]byte(stored.Actions), &rule.Actions); err != nil {
+		return nil, fmt.Errorf("invalid actions: %w", err)
+	}
+
+	if rule.Conditions.MatchType == domainAutoReply.MatchRegex {
+		for _, pattern := range rule.Conditions.Patterns {
+			if !rule.Conditions.CaseSensitive {
+				pattern = "(?i)" + pattern
+			}
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+			rule.patterns = append(rule.patterns, compiled)
+		}
+	}
+
+	for i, sender := range rule.Conditions.Senders {
+		rule.Conditions.Senders[i] = strings.TrimPrefix(sender, "+")
+	}
+
+	if hours := rule.Conditions.BusinessHours; hours != nil {
+		rule.location = time.Local
+		if hours.Timezone != "" {
+			location, err := time.LoadLocation(hours.Timezone)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timezone %q: %w", hours.Timezone, err)
+			}
+			rule.location = location
+		}
+	}
+
+	return rule, nil
+}
+
+// flagAutoReplyRule is the catch-all rule of the --autoreply option: the configured text is
+// sent to every direct chat that sends a text message, after all stored rules were evaluated
+func flagAutoReplyRule() *autoReplyRule {
+	if config.WhatsappAutoReplyMessage == "" {
+		return nil
+	}
+	return &autoReplyRule{
+		ID: autoReplyFlagRuleID,
+		Conditions: domainAutoReply.Conditions{
+			ChatType:     domainAutoReply.ChatTypeDM,
+			MessageTypes: []string{domainAutoReply.MessageText},
+		},
+		Actions: []domainAutoReply.Action{{Type: domainAutoReply.ActionReply, Text: config.WhatsappAutoReplyMessage}},
+	}
+}
+
+func (e *autoReplyEngine) list() []*autoReplyRule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	rules := e.rules
+	if rule := flagAutoReplyRule(); rule != nil {
+		rules = append(slices.Clip(rules), rule)
+	}
+	return rules
+}
+
+func (e *autoReplyEngine) sender() AutoReplySender {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.send
+}
+
+func handleAutoReply(ctx context.Context, evt *events.Message) {
+	autoReplies.handle(ctx, evt)
+}
+
+// handle runs the actions of the first matching rule, and of the following ones while the
+// matching rules ask to continue
+func (e *autoReplyEngine) handle(ctx context.Context, evt *events.Message) {
+	// Never react to our own messages, broadcasts or status updates
+	if evt.Info.IsFromMe || evt.Info.IsIncomingBroadcast() ||
+		strings.Contains(evt.Info.SourceString(), "broadcast") ||
+		strings.HasPrefix(evt.Info.Chat.String(), "status@") {
+		return
+	}
+
+	message := newAutoReplyMessage(evt)
+	if message.Type == "" || message.ChatType == "" {
+		return
+	}
+
+	var deviceID string
+	if device := DeviceFromContext(ctx); device != nil {
+		deviceID = device.ID()
+	}
+
+	now := e.now()
+	for _, rule := range e.list() {
+		if !rule.matches(deviceID, evt, message, now) {
+			continue
+		}
+
+		if e.claimCooldown(rule, deviceID, evt, now) {
+			logrus.Infof("[AUTO_REPLY] Rule %s matched message %s from %s", rule.ID, evt.Info.ID, evt.Info.SourceString())
+			e.run(ctx, rule, evt, message)
+		} else {
+			logrus.Debugf("[AUTO_REPLY] Rule %s matched message %s but is cooling down", rule.ID, evt.Info.ID)
+		}
+
+		if !rule.ContinueMatching {
+			return
+		}
+	}
+}
+
+func newAutoReplyMessage(evt *events.Message) autoReplyMessage {
+	msg := unwrapAutoReplyMessage(evt.Message)
+
+	message := autoReplyMessage{
+		Text: strings.TrimSpace(utils.ExtractMessageTextFromProto(msg)),
+		Type: autoReplyMessageType(msg),
+	}
+
+	switch evt.Info.Chat.Server {
+	case types.GroupServer:
+		message.ChatType = domainAutoReply.ChatTypeGroup
+	case types.DefaultUserServer, types.HiddenUserServer:
+		message.ChatType = domainAutoReply.ChatTypeDM
+	}
+	return message
+}
+
+// unwrapAutoReplyMessage removes the view once and ephemeral wrappers around the message content
+func unwrapAutoReplyMessage(msg *waE2E.Message) *waE2E.Message {
+	for i := 0; i < 3; i++ { // safeguard against excessively nested wrappers
+		if vm := msg.GetViewOnceMessage(); vm != nil && vm.GetMessage() != nil {
+			msg = vm.GetMessage()
+			continue
+		}
+		if em := msg.GetEphemeralMessage(); em != nil && em.GetMessage() != nil {
+			msg = em.GetMessage()
+			continue
+		}
+		if vm2 := msg.GetViewOnceMessageV2(); vm2 != nil && vm2.GetMessage() != nil {
+			msg = vm2.GetMessage()
+			continue
+		}
+		if vm2e := msg.GetViewOnceMessageV2Extension(); vm2e != nil && vm2e.GetMessage() != nil {
+			msg = vm2e.GetMessage()
+			continue
+		}
+		break
+	}
+	return msg
+}
+
+// autoReplyMessageType classifies the message content. Protocol messages (edits, revokes),
+// reactions and other system messages have no type and never trigger rules.
+func autoReplyMessageType(msg *waE2E.Message) string {
+	switch {
+	case msg.GetConversation() != "" || msg.GetExtendedTextMessage().GetText() != "",
+		msg.GetButtonsResponseMessage() != nil, msg.GetListResponseMessage() != nil,
+		msg.GetTemplateButtonReplyMessage() != nil:
+		return domainAutoReply.MessageText
+	case msg.GetImageMessage() != nil:
+		return domainAutoReply.MessageImage
+	case msg.GetVideoMessage() != nil, msg.GetPtvMessage() != nil:
+		return domainAutoReply.MessageVideo
+	case msg.GetAudioMessage() != nil:
+		return domainAutoReply.MessageAudio
+	case msg.GetDocumentMessage() != nil, msg.GetDocumentWithCaptionMessage() != nil:
+		return domainAutoReply.MessageDocument
+	case msg.GetStickerMessage() != nil:
+		return domainAutoReply.MessageSticker
+	case msg.GetLocationMessage() != nil, msg.GetLiveLocationMessage() != nil:
+		return domainAutoReply.MessageLocation
+	case msg.GetContactMessage() != nil, msg.GetContactsArrayMessage() != nil:
+		return domainAutoReply.MessageContact
+	case msg.GetPollCreationMessage() != nil, msg.GetPollCreationMessageV2() != nil, msg.GetPollCreationMessageV3() != nil:
+		return domainAutoReply.MessagePoll
+	}
+	return ""
+}
+
+// matches reports whether every condition of the rule holds for the message
+func (rule *autoReplyRule) matches(deviceID string, evt *events.Message, message autoReplyMessage, now time.Time) bool {
+	conditions := rule.Conditions
+
+	if rule.DeviceID != "" && rule.DeviceID != deviceID {
+		return false
+	}
+	if conditions.ChatType != "" && conditions.ChatType != domainAutoReply.ChatTypeAll && conditions.ChatType != message.ChatType {
+		return false
+	}
+	if len(conditions.MessageTypes) > 0 && !slices.Contains(conditions.MessageTypes, message.Type) {
+		return false
+	}
+	if len(conditions.Senders) > 0 &&
+		!containsAny(conditions.Senders, jidCandidates(evt.Info.Chat, evt.Info.Sender, evt.Info.SenderAlt)) {
+		return false
+	}
+	if conditions.MatchType != "" && !rule.matchesText(message.Text) {
+		return false
+	}
+	if conditions.BusinessHours != nil && !rule.matchesBusinessHours(now) {
+		return false
+	}
+	return true
+}
+
+func (rule *autoReplyRule) matchesText(text string) bool {
+	if text == "" {
+		return false
+	}
+
+	conditions := rule.Conditions
+	if conditions.MatchType == domainAutoReply.MatchRegex {
+		for _, pattern := range rule.patterns {
+			if pattern.MatchString(text) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, pattern := range conditions.Patterns {
+		switch conditions.MatchType {
+		case domainAutoReply.MatchExact:
+			if (conditions.CaseSensitive && text == strings.TrimSpace(pattern)) ||
+				(!conditions.CaseSensitive && strings.EqualFold(text, strings.TrimSpace(pattern))) {
+				return true
+			}
+		case domainAutoReply.MatchKeyword:
+			if (conditions.CaseSensitive && strings.Contains(text, pattern)) ||
+				(!conditions.CaseSensitive && strings.Contains(strings.ToLower(text), strings.ToLower(pattern))) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesBusinessHours checks the time window on the rule's days
+func (rule *autoReplyRule) matchesBusinessHours(now time.Time) bool {
+	return matchesBusinessHours(rule.Conditions.BusinessHours, rule.location, now)
+}
+
+// matchesBusinessHours checks whether now, in location, falls in the time window on the given days.
+// Windows ending before they start span midnight, equal start and end times cover the whole day.
+func matchesBusinessHours(hours *domainAutoReply.BusinessHours, location *time.Location, now time.Time) bool {
+	local := now.In(location)
+
+	inside := len(hours.Days) == 0 || slices.Contains(hours.Days, strings.ToLower(local.Weekday().String()[:3]))
+	if inside {
+		start, startErr := time.Parse("15:04", hours.Start)
+		end, endErr := time.Parse("15:04", hours.End)
+		if startErr != nil || endErr != nil {
+			return false
+		}
+
+		minute := local.Hour()*60 + local.Minute()
+		from := start.Hour()*60 + start.Minute()
+		to := end.Hour()*60 + end.Minute()
+		switch {
+		case from < to:
+			inside = minute >= from && minute < to
+		case from > to:
+			inside = minute >= from || minute < to
+		}
+	}
+
+	return inside != hours.Outside
+}
+
+// claimCooldown reports whether the rule may act on the sender of the message and starts the
+// rule's cooldown for them
+func (e *autoReplyEngine) claimCooldown(rule *autoReplyRule, deviceID string, evt *events.Message, now time.Time) bool {
+	if rule.Cooldown <= 0 {
+		return true
+	}
+
+	key := strings.Join([]string{rule.ID, deviceID, evt.Info.Chat.ToNonAD().String(), evt.Info.Sender.ToNonAD().String()}, "|")
+
+	e.cooldownMu.Lock()
+	defer e.cooldownMu.Unlock()
+
+	if until, ok := e.cooldowns[key]; ok && now.Before(until) {
+		return false
+	}
+
+	if len(e.cooldowns) >= autoReplyCooldownPruneSize {
+		for other, until := range e.cooldowns {
+			if !now.Before(until) {
+				delete(e.cooldowns, other)
+			}
+		}
+	}
+	e.cooldowns[key] = now.Add(rule.Cooldown)
+	return true
+}
+
+// run executes the actions of a rule in order, a failing action does not stop the others
+func (e *autoReplyEngine) run(ctx context.Context, rule *autoReplyRule, evt *events.Message, message autoReplyMessage) {
+	chat := evt.Info.Chat.ToNonAD()
+
+	for _, action := range rule.Actions {
+		var err error
+		switch action.Type {
+		case domainAutoReply.ActionReply:
+			err = e.reply(ctx, evt, message, action)
+		case domainAutoReply.ActionReact:
+			client := ClientFromContext(ctx)
+			_, err = client.SendMessage(ctx, chat, client.BuildReaction(chat, evt.Info.Sender, evt.Info.ID, action.Emoji))
+		case domainAutoReply.ActionMarkRead:
+			err = ClientFromContext(ctx).MarkRead(ctx, []types.MessageID{evt.Info.ID}, time.Now(), chat, evt.Info.Sender)
+		case domainAutoReply.ActionLabel:
+			err = ClientFromContext(ctx).SendAppState(ctx, appstate.BuildLabelChat(chat, action.LabelID, true))
+		case domainAutoReply.ActionWebhook:
+			forwardAutoReplyToWebhook(ctx, rule, evt, action)
+		default:
+			err = fmt.Errorf("unknown action %q", action.Type)
+		}
+
+		if err != nil {
+			logrus.Errorf("[AUTO_REPLY] Rule %s failed to %s message %s: %v", rule.ID, action.Type, evt.Info.ID, err)
+		}
+	}
+}
+
+// reply sends the reply of an action. {{name}}, {{phone}} and {{message}} in the text are
+// replaced by the sender's push name and number and the text of the incoming message.
+func (e *autoReplyEngine) reply(ctx context.Context, evt *events.Message, message autoReplyMessage, action domainAutoReply.Action) error {
+	send := e.sender()
+	if send == nil {
+		return fmt.Errorf("auto-reply sender is not configured")
+	}
+
+	sender := evt.Info.Sender
+	if sender.Server == types.HiddenUserServer && !evt.Info.SenderAlt.IsEmpty() {
+		sender = evt.Info.SenderAlt
+	}
+	action.Text = utils.RenderTemplate(action.Text, map[string]string{
+		"name":    evt.Info.PushName,
+		"phone":   sender.User,
+		"message": message.Text,
+	})
+
+	var quoted string
+	if action.Quote {
+		quoted = evt.Info.ID
+	}
+
+	messageID, err := send(ctx, evt.Info.Chat.ToNonAD().String(), quoted, action)
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("[AUTO_REPLY] Replied to message %s with %s", evt.Info.ID, messageID)
+	return nil
+}
+
+// forwardAutoReplyToWebhook sends the matched message as an auto_reply.matched event to the
+// action's URL, or to the configured webhooks when the action has none
+func forwardAutoReplyToWebhook(ctx context.Context, rule *autoReplyRule, evt *events.Message, action domainAutoReply.Action) {
+	payload, err := createMessagePayload(ctx, evt)
+	if err != nil {
+		logrus.Errorf("[AUTO_REPLY] Failed to build webhook payload for message %s: %v", evt.Info.ID, err)
+		return
+	}
+	payload["event"] = domainWebhook.EventAutoReplyMatched
+	payload["auto_reply_rule_id"] = rule.ID
+
+	ctx = withWebhookEvent(ctx, webhookEvent{
+		Name:      domainWebhook.EventAutoReplyMatched,
+		ChatJID:   evt.Info.Chat,
+		SenderJID: evt.Info.Sender,
+		IsFromMe:  evt.Info.IsFromMe,
+	})
+
+	go func() {
+		if action.URL != "" {
+			if device := DeviceFromContext(ctx); device != nil {
+				payload["device_id"] = device.ID()
+			}
+			err = submitWebhookFn(ctx, payload, action.URL)
+		} else {
+			err = forwardPayloadToConfiguredWebhooks(ctx, payload, "auto-reply event")
+		}
+		if err != nil {
+			logrus.Errorf("[AUTO_REPLY] Failed forward rule %s match to webhook: %v", rule.ID, err)
+		}
+	}()
+}
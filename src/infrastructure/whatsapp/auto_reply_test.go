@@ -0,0 +1,196 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+type autoReplyCall struct {
+	chat   string
+	quoted string
+	text   string
+}
+
+func newTestAutoReplyRule(t *testing.T, id string, conditions domainAutoReply.Conditions, text string) *domainChatStorage.AutoReplyRule {
+	t.Helper()
+	encodedConditions, err := json.Marshal(conditions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encodedActions, err := json.Marshal([]domainAutoReply.Action{{Type: domainAutoReply.ActionReply, Text: text, Quote: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &domainChatStorage.AutoReplyRule{ID: id, Enabled: true, Conditions: string(encodedConditions), Actions: string(encodedActions)}
+}
+
+func newTestAutoReplyEvent(id string, chat types.JID, sender types.JID, msg *waE2E.Message) *events.Message {
+	return &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{Chat: chat, Sender: sender},
+			ID:            id,
+			PushName:      "Ann",
+		},
+		Message: msg,
+	}
+}
+
+func TestAutoReplyEngineHandle(t *testing.T) {
+	previousFlag := config.WhatsappAutoReplyMessage
+	t.Cleanup(func() { config.WhatsappAutoReplyMessage = previousFlag })
+	config.WhatsappAutoReplyMessage = "We are away"
+
+	var calls []autoReplyCall
+	engine := newAutoReplyEngine()
+	engine.send = func(_ context.Context, chat string, quoted string, action domainAutoReply.Action) (string, error) {
+		calls = append(calls, autoReplyCall{chat: chat, quoted: quoted, text: action.Text})
+		return "REPLY", nil
+	}
+
+	stored := []*domainChatStorage.AutoReplyRule{
+		newTestAutoReplyRule(t, "price", domainAutoReply.Conditions{MatchType: domainAutoReply.MatchKeyword, Patterns: []string{"price"}}, "Hi {{name}}, see our catalog"),
+		newTestAutoReplyRule(t, "order", domainAutoReply.Conditions{MatchType: domainAutoReply.MatchRegex, Patterns: []string{`^order #\d+$`}, ChatType: domainAutoReply.ChatTypeGroup}, "Checking order"),
+		newTestAutoReplyRule(t, "photo", domainAutoReply.Conditions{MessageTypes: []string{domainAutoReply.MessageImage}, Senders: []string{"+628111"}}, "Nice photo"),
+	}
+	stored[0].CooldownSeconds = 60
+	for _, rule := range stored {
+		compiled, err := compileAutoReplyRule(rule)
+		if err != nil {
+			t.Fatalf("compileAutoReplyRule(%s) error = %v", rule.ID, err)
+		}
+		engine.rules = append(engine.rules, compiled)
+	}
+
+	now := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	engine.now = func() time.Time { return now }
+
+	dm := types.NewJID("628111", types.DefaultUserServer)
+	other := types.NewJID("628222", types.DefaultUserServer)
+	group := types.NewJID("120363000000000000", types.GroupServer)
+	text := func(body string) *waE2E.Message { return &waE2E.Message{Conversation: proto.String(body)} }
+
+	tests := []struct {
+		name string
+		evt  *events.Message
+		want []autoReplyCall
+	}{
+		{
+			name: "Keyword with template",
+			evt:  newTestAutoReplyEvent("M1", dm, dm, text("What is the PRICE?")),
+			want: []autoReplyCall{{chat: "628111@s.whatsapp.net", quoted: "M1", text: "Hi Ann, see our catalog"}},
+		},
+		{
+			name: "Keyword cooling down stops evaluation",
+			evt:  newTestAutoReplyEvent("M2", dm, dm, text("price again")),
+			want: nil,
+		},
+		{
+			name: "Cooldown is per contact",
+			evt:  newTestAutoReplyEvent("M3", other, other, text("price")),
+			want: []autoReplyCall{{chat: "628222@s.whatsapp.net", quoted: "M3", text: "Hi Ann, see our catalog"}},
+		},
+		{
+			name: "Regex in group",
+			evt:  newTestAutoReplyEvent("M4", group, dm, text("Order #42")),
+			want: []autoReplyCall{{chat: "120363000000000000@g.us", quoted: "M4", text: "Checking order"}},
+		},
+		{
+			name: "Regex rule ignores direct chats, flag rule replies",
+			evt:  newTestAutoReplyEvent("M5", other, other, text("order #42")),
+			want: []autoReplyCall{{chat: "628222@s.whatsapp.net", text: "We are away"}},
+		},
+		{
+			name: "Message type and sender",
+			evt:  newTestAutoReplyEvent("M6", dm, dm, &waE2E.Message{ImageMessage: &waE2E.ImageMessage{}}),
+			want: []autoReplyCall{{chat: "628111@s.whatsapp.net", quoted: "M6", text: "Nice photo"}},
+		},
+		{
+			name: "Image from another sender",
+			evt:  newTestAutoReplyEvent("M7", other, other, &waE2E.Message{ImageMessage: &waE2E.ImageMessage{}}),
+			want: nil,
+		},
+		{
+			name: "Protocol messages are ignored",
+			evt:  newTestAutoReplyEvent("M8", other, other, &waE2E.Message{ProtocolMessage: &waE2E.ProtocolMessage{}}),
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			engine.handle(context.Background(), tt.evt)
+			if len(calls) != len(tt.want) {
+				t.Fatalf("replies = %+v, want %+v", calls, tt.want)
+			}
+			for i := range calls {
+				if calls[i] != tt.want[i] {
+					t.Fatalf("reply %d = %+v, want %+v", i, calls[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAutoReplyRuleMatchesBusinessHours(t *testing.T) {
+	tests := []struct {
+		name  string
+		hours domainAutoReply.BusinessHours
+		now   time.Time
+		want  bool
+	}{
+		{
+			name:  "Inside window",
+			hours: domainAutoReply.BusinessHours{Timezone: "Asia/Jakarta", Days: []string{"mon"}, Start: "09:00", End: "17:00"},
+			now:   time.Date(2025, 1, 6, 3, 0, 0, 0, time.UTC), // Monday 10:00 in Jakarta
+			want:  true,
+		},
+		{
+			name:  "Outside window on a working day",
+			hours: domainAutoReply.BusinessHours{Timezone: "Asia/Jakarta", Days: []string{"mon"}, Start: "09:00", End: "17:00", Outside: true},
+			now:   time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC), // Monday 19:00 in Jakarta
+			want:  true,
+		},
+		{
+			name:  "Outside window on a day off",
+			hours: domainAutoReply.BusinessHours{Timezone: "UTC", Days: []string{"mon"}, Start: "09:00", End: "17:00", Outside: true},
+			now:   time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC), // Sunday
+			want:  true,
+		},
+		{
+			name:  "Overnight window",
+			hours: domainAutoReply.BusinessHours{Timezone: "UTC", Start: "22:00", End: "06:00"},
+			now:   time.Date(2025, 1, 6, 2, 30, 0, 0, time.UTC),
+			want:  true,
+		},
+		{
+			name:  "After overnight window",
+			hours: domainAutoReply.BusinessHours{Timezone: "UTC", Start: "22:00", End: "06:00"},
+			now:   time.Date(2025, 1, 6, 6, 0, 0, 0, time.UTC),
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hours := tt.hours
+			rule, err := compileAutoReplyRule(newTestAutoReplyRule(t, "hours", domainAutoReply.Conditions{BusinessHours: &hours}, "Hi"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rule.matchesBusinessHours(tt.now); got != tt.want {
+				t.Fatalf("matchesBusinessHours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	campaignBatchSize             = 50
+	campaignDefaultRatePerMinute  = 20
+	campaignReconnectDelay        = 15 * time.Second
+	campaignNotOnWhatsAppErrorMsg = "phone is not on WhatsApp"
+	campaignInterruptedErrorMsg   = "sending was interrupted before the outcome was recorded, check whether the message arrived"
+)
+
+// CampaignMessageSender sends the rendered campaign message to one recipient and returns the WhatsApp message ID
+type CampaignMessageSender func(ctx context.Context, campaign *domainChatStorage.Campaign, phone string, message string) (messageID string, err error)
+
+// CampaignRunner sends running campaigns recipient by recipient at their configured pace.
+// Progress is kept in the chat storage database, so campaigns resume after a restart or
+// reconnect without messaging anyone twice: a recipient is marked sending before its message
+// goes out, and one still sending when its campaign resumes is left for review instead of
+// being sent again.
+type CampaignRunner struct {
+	ctx          context.Context
+	repo         domainChatStorage.IChatStorageRepository
+	send         CampaignMessageSender
+	ready        func(ctx context.Context) bool
+	checkNumbers func(ctx context.Context, phones []string) (map[string]bool, error)
+	sleep        func(ctx context.Context, d time.Duration) bool
+
+	mu   sync.Mutex
+	runs map[string]*campaignRun
+}
+
+type campaignRun struct {
+	cancel  context.CancelFunc
+	done    chan struct{}
+	stopped bool
+}
+
+var campaignRunner *CampaignRunner
+
+// InitCampaignRunner creates the campaign runner and resumes the campaigns that were running
+func InitCampaignRunner(ctx context.Context, repo domainChatStorage.IChatStorageRepository, send CampaignMessageSender) *CampaignRunner {
+	runner := newCampaignRunner(ctx, repo, send)
+
+	campaigns, err := repo.GetCampaigns(&domainChatStorage.CampaignFilter{Status: domainChatStorage.CampaignRunning})
+	if err != nil {
+		logrus.Errorf("[CAMPAIGN] Failed to load running campaigns: %v", err)
+	}
+	for _, campaign := range campaigns {
+		logrus.Infof("[CAMPAIGN] Resuming campaign %s", campaign.ID)
+		runner.Start(campaign.ID)
+	}
+
+	campaignRunner = runner
+	return runner
+}
+
+func newCampaignRunner(ctx context.Context, repo domainChatStorage.IChatStorageRepository, send CampaignMessageSender) *CampaignRunner {
+	return &CampaignRunner{
+		ctx:          ctx,
+		repo:         repo,
+		send:         send,
+		ready:        deviceReady,
+		checkNumbers: checkNumbersOnWhatsApp,
+		sleep:        sleepContext,
+		runs:         make(map[string]*campaignRun),
+	}
+}
+
+// GetCampaignRunner returns the running campaign runner, or nil when it has not been started
+func GetCampaignRunner() *CampaignRunner {
+	return campaignRunner
+}
+
+// Start sends a campaign in the background unless it is already being sent
+func (r *CampaignRunner) Start(campaignID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous := r.runs[campaignID]
+	if previous != nil && !previous.stopped {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.ctx)
+	run := &campaignRun{cancel: cancel, done: make(chan struct{})}
+	r.runs[campaignID] = run
+
+	go func() {
+		defer close(run.done)
+		defer r.forget(campaignID, run)
+
+		// A stopped run finishes the message it is sending before the campaign continues
+		if previous != nil {
+			<-previous.done
+		}
+		r.run(ctx, campaignID)
+	}()
+}
+
+// Stop interrupts a campaign after the message currently being sent
+func (r *CampaignRunner) Stop(campaignID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if run := r.runs[campaignID]; run != nil {
+		run.stopped = true
+		run.cancel()
+	}
+}
+
+func (r *CampaignRunner) forget(campaignID string, run *campaignRun) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.runs[campaignID] == run {
+		delete(r.runs, campaignID)
+	}
+	run.cancel()
+}
+
+func (r *CampaignRunner) run(ctx context.Context, campaignID string) {
+	for ctx.Err() == nil {
+		campaign, err := r.repo.GetCampaign(campaignID)
+		if err != nil {
+			logrus.Errorf("[CAMPAIGN] Failed to load campaign %s: %v", campaignID, err)
+			if !r.sleep(ctx, campaignReconnectDelay) {
+				return
+			}
+			continue
+		}
+		if campaign == nil || campaign.Status != domainChatStorage.CampaignRunning {
+			return
+		}
+
+		// Messages in flight are not interrupted by pausing or cancelling the campaign
+		sendCtx := context.WithoutCancel(ctx)
+		if deviceManager != nil {
+			device, ok := deviceManager.Get(campaignDeviceID(campaign))
+			if !ok {
+				logrus.Errorf("[CAMPAIGN] Device %s of campaign %s no longer exists, pausing it", campaignDeviceID(campaign), campaign.ID)
+				if err := r.repo.UpdateCampaignStatus(campaign.ID, domainChatStorage.CampaignPaused, domainChatStorage.CampaignRunning); err != nil {
+					logrus.Errorf("[CAMPAIGN] Failed to pause campaign %s: %v", campaign.ID, err)
+				}
+				return
+			}
+			sendCtx = ContextWithDevice(sendCtx, device)
+		}
+
+		if !r.ready(sendCtx) {
+			logrus.Debugf("[CAMPAIGN] Device of campaign %s is not connected, retrying in %s", campaign.ID, campaignReconnectDelay)
+			if !r.sleep(ctx, campaignReconnectDelay) {
+				return
+			}
+			continue
+		}
+
+		// No message of this campaign is in flight here, so recipients still sending were interrupted
+		if flagged, err := r.repo.FlagInterruptedCampaignRecipients(campaign.ID, campaignInterruptedErrorMsg); err != nil {
+			logrus.Errorf("[CAMPAIGN] Failed to flag interrupted recipients of campaign %s: %v", campaign.ID, err)
+		} else if flagged > 0 {
+			logrus.Warnf("[CAMPAIGN] %d recipient(s) of campaign %s were interrupted while sending and need review", flagged, campaign.ID)
+		}
+
+		recipients, err := r.repo.GetCampaignRecipients(&domainChatStorage.CampaignRecipientFilter{
+			CampaignID: campaign.ID,
+			Status:     domainChatStorage.CampaignRecipientQueued,
+			Limit:      campaignBatchSize,
+		})
+		if err != nil {
+			logrus.Errorf("[CAMPAIGN] Failed to load recipients of campaign %s: %v", campaign.ID, err)
+			if !r.sleep(ctx, campaignReconnectDelay) {
+				return
+			}
+			continue
+		}
+
+		if len(recipients) == 0 {
+			if err := r.repo.UpdateCampaignStatus(campaign.ID, domainChatStorage.CampaignCompleted, domainChatStorage.CampaignRunning); err != nil {
+				logrus.Errorf("[CAMPAIGN] Failed to complete campaign %s: %v", campaign.ID, err)
+			} else {
+				logrus.Infof("[CAMPAIGN] Campaign %s completed", campaign.ID)
+			}
+			return
+		}
+
+		if campaign.ValidateNumbers {
+			recipients = r.validate(sendCtx, recipients)
+		}
+
+		for _, recipient := range recipients {
+			// A disconnect stops the batch, the remaining recipients stay queued
+			if ctx.Err() != nil || !r.ready(sendCtx) {
+				break
+			}
+
+			if !r.deliver(sendCtx, campaign, recipient) {
+				// The recipient stays queued, the batch is retried once the device had time to reconnect
+				if !r.sleep(ctx, campaignReconnectDelay) {
+					return
+				}
+				break
+			}
+			if !r.sleep(ctx, campaignDelay(campaign)) {
+				return
+			}
+		}
+	}
+}
+
+// validate marks recipients that are not registered on WhatsApp as failed and returns the others
+func (r *CampaignRunner) validate(ctx context.Context, recipients []*domainChatStorage.CampaignRecipient) []*domainChatStorage.CampaignRecipient {
+	phones := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		phones = append(phones, recipient.Phone)
+	}
+
+	registered, err := r.checkNumbers(ctx, phones)
+	if err != nil {
+		logrus.Warnf("[CAMPAIGN] Failed to check recipients on WhatsApp, sending without validation: %v", err)
+		return recipients
+	}
+
+	valid := recipients[:0]
+	for _, recipient := range recipients {
+		if registered[recipient.Phone] {
+			valid = append(valid, recipient)
+			continue
+		}
+		if err := r.repo.MarkCampaignRecipientFailed(recipient.ID, campaignNotOnWhatsAppErrorMsg); err != nil {
+			logrus.Errorf("[CAMPAIGN] Failed to update campaign recipient %d: %v", recipient.ID, err)
+		}
+	}
+	return valid
+}
+
+// deliver sends the campaign message to recipient. It returns false when the recipient was left queued
+// because the message could not be sent for now, e.g. the device disconnected.
+func (r *CampaignRunner) deliver(ctx context.Context, campaign *domainChatStorage.Campaign, recipient *domainChatStorage.CampaignRecipient) bool {
+	variables := map[string]string{}
+	if recipient.Variables != "" {
+		if err := json.Unmarshal([]byte(recipient.Variables), &variables); err != nil {
+			logrus.Warnf("[CAMPAIGN] Ignoring invalid variables of campaign recipient %d: %v", recipient.ID, err)
+		}
+	}
+	if _, ok := variables["phone"]; !ok {
+		variables["phone"] = strings.SplitN(recipient.Phone, "@", 2)[0]
+	}
+
+	if err := r.repo.MarkCampaignRecipientSending(recipient.ID); err != nil {
+		logrus.Errorf("[CAMPAIGN] Failed to update campaign recipient %d, not sending to it: %v", recipient.ID, err)
+		return false
+	}
+
+	messageID, err := r.send(ctx, campaign, recipient.Phone, utils.RenderTemplate(campaign.Message, variables))
+	if err != nil {
+		// A timed out send may still have reached the recipient, so it is never sent again automatically
+		if isSendTimeout(err) {
+			logrus.Warnf("[CAMPAIGN] Sending campaign %s to %s timed out, flagging it for review: %v", campaign.ID, recipient.Phone, err)
+			if err := r.repo.FlagCampaignRecipientForReview(recipient.ID, err.Error()); err != nil {
+				logrus.Errorf("[CAMPAIGN] Failed to update campaign recipient %d: %v", recipient.ID, err)
+			}
+			return r.ready(ctx)
+		}
+
+		if isConnectionError(err) || !r.ready(ctx) {
+			logrus.Warnf("[CAMPAIGN] Failed to send campaign %s to %s, keeping it queued: %v", campaign.ID, recipient.Phone, err)
+			if err := r.repo.RequeueCampaignRecipient(recipient.ID, err.Error()); err != nil {
+				logrus.Errorf("[CAMPAIGN] Failed to update campaign recipient %d: %v", recipient.ID, err)
+			}
+			return false
+		}
+
+		logrus.Warnf("[CAMPAIGN] Failed to send campaign %s to %s: %v", campaign.ID, recipient.Phone, err)
+		if err := r.repo.MarkCampaignRecipientFailed(recipient.ID, err.Error()); err != nil {
+			logrus.Errorf("[CAMPAIGN] Failed to update campaign recipient %d: %v", recipient.ID, err)
+		}
+		return true
+	}
+
+	// When this update fails the recipient stays sending and is flagged for review, not sent to again
+	if err := r.repo.MarkCampaignRecipientSent(recipient.ID, messageID); err != nil {
+		logrus.Errorf("[CAMPAIGN] Failed to update campaign recipient %d: %v", recipient.ID, err)
+	}
+	return true
+}
+
+// isConnectionError reports whether a send failed before the message left the device because there was
+// no connection to WhatsApp, so it can be retried without sending it twice
+func isConnectionError(err error) bool {
+	var disconnected *whatsmeow.DisconnectedError
+	return !isSendTimeout(err) && (errors.As(err, &disconnected) ||
+		errors.Is(err, whatsmeow.ErrNotConnected) ||
+		errors.Is(err, whatsmeow.ErrNotLoggedIn) ||
+		errors.Is(err, pkgError.ErrNotConnected) ||
+		errors.Is(err, pkgError.ErrNotLoggedIn))
+}
+
+// isSendTimeout reports whether a send gave up waiting for WhatsApp, in which case the message may or may
+// not have been delivered
+func isSendTimeout(err error) bool {
+	return errors.Is(err, whatsmeow.ErrMessageTimedOut) ||
+		errors.Is(err, whatsmeow.ErrIQTimedOut) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// campaignDelay returns the pause before the next message: the interval implied by the rate
+// limit, or a random delay within the configured bounds when that is longer
+func campaignDelay(campaign *domainChatStorage.Campaign) time.Duration {
+	rate := campaign.RatePerMinute
+	if rate <= 0 {
+		rate = campaignDefaultRatePerMinute
+	}
+	delay := time.Minute / time.Duration(rate)
+
+	if campaign.MaxDelaySeconds > 0 {
+		minDelay := time.Duration(campaign.MinDelaySeconds) * time.Second
+		maxDelay := time.Duration(campaign.MaxDelaySeconds) * time.Second
+		random := minDelay
+		if maxDelay > minDelay {
+			random += time.Duration(rand.Int63n(int64(maxDelay-minDelay) + 1))
+		}
+		if random > delay {
+			delay = random
+		}
+	}
+
+	return delay
+}
+
+func campaignDeviceID(campaign *domainChatStorage.Campaign) string {
+	if campaign.DeviceID == "" {
+		return DefaultDeviceID
+	}
+	return campaign.DeviceID
+}
+
+// checkNumbersOnWhatsApp reports which phones are registered on WhatsApp, recipients that
+// are not personal chats (groups, newsletters) are always considered registered
+func checkNumbersOnWhatsApp(ctx context.Context, phones []string) (map[string]bool, error) {
+	registered := make(map[string]bool, len(phones))
+	queries := make(map[string]string)
+	var numbers []string
+
+	for _, phone := range phones {
+		user, server, hasServer := strings.Cut(phone, "@")
+		if hasServer && server != types.DefaultUserServer {
+			registered[phone] = true
+			continue
+		}
+		query := "+" + strings.TrimPrefix(user, "+")
+		queries[query] = phone
+		numbers = append(numbers, query)
+	}
+
+	if len(numbers) == 0 {
+		return registered, nil
+	}
+
+	responses, err := ClientFromContext(ctx).IsOnWhatsApp(ctx, numbers)
+	if err != nil {
+		return nil, err
+	}
+	for _, response := range responses {
+		if phone, ok := queries[response.Query]; ok && response.IsIn {
+			registered[phone] = true
+		}
+	}
+	return registered, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,154 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow"
+)
+
+type fakeCampaignRepo struct {
+	domainChatStorage.IChatStorageRepository
+	sending  map[int64]bool
+	sent     map[int64]string
+	failed   map[int64]string
+	requeued map[int64]string
+	review   map[int64]string
+}
+
+func (f *fakeCampaignRepo) MarkCampaignRecipientSending(id int64) error {
+	f.sending[id] = true
+	return nil
+}
+
+func (f *fakeCampaignRepo) MarkCampaignRecipientSent(id int64, messageID string) error {
+	f.sent[id] = messageID
+	return nil
+}
+
+func (f *fakeCampaignRepo) MarkCampaignRecipientFailed(id int64, reason string) error {
+	f.failed[id] = reason
+	return nil
+}
+
+func (f *fakeCampaignRepo) RequeueCampaignRecipient(id int64, reason string) error {
+	f.requeued[id] = reason
+	return nil
+}
+
+func (f *fakeCampaignRepo) FlagCampaignRecipientForReview(id int64, reason string) error {
+	f.review[id] = reason
+	return nil
+}
+
+func TestCampaignRunnerValidateAndDeliver(t *testing.T) {
+	repo := &fakeCampaignRepo{sending: map[int64]bool{}, sent: map[int64]string{}, failed: map[int64]string{}, requeued: map[int64]string{}, review: map[int64]string{}}
+	var messages []string
+
+	runner := newCampaignRunner(context.Background(), repo, func(_ context.Context, _ *domainChatStorage.Campaign, phone string, message string) (string, error) {
+		switch phone {
+		case "6283333333333":
+			return "", errors.New("invalid media")
+		case "6284444444444":
+			return "", whatsmeow.ErrNotConnected
+		case "6285555555555":
+			return "", fmt.Errorf("failed to send message: %w", whatsmeow.ErrMessageTimedOut)
+		}
+		messages = append(messages, message)
+		return "MSG-" + phone, nil
+	})
+	runner.checkNumbers = func(_ context.Context, phones []string) (map[string]bool, error) {
+		return map[string]bool{"6281111111111": true, "6283333333333": true, "6284444444444": true, "6285555555555": true}, nil
+	}
+	runner.ready = func(context.Context) bool { return true }
+
+	campaign := &domainChatStorage.Campaign{ID: "camp-1", Message: "Hi {{name}} ({{phone}})"}
+	recipients := []*domainChatStorage.CampaignRecipient{
+		{ID: 1, Phone: "6281111111111", Variables: `{"name":"Ann"}`},
+		{ID: 2, Phone: "6282222222222", Variables: `{"name":"Bob"}`},
+		{ID: 3, Phone: "6283333333333", Variables: `{"name":"Cid"}`},
+		{ID: 4, Phone: "6284444444444", Variables: `{"name":"Dan"}`},
+		{ID: 5, Phone: "6285555555555", Variables: `{"name":"Eve"}`},
+	}
+
+	valid := runner.validate(context.Background(), recipients)
+	if len(valid) != 4 {
+		t.Fatalf("validate() kept %d recipients, want 4", len(valid))
+	}
+	for _, recipient := range valid {
+		if delivered := runner.deliver(context.Background(), campaign, recipient); delivered != (recipient.ID != 4) {
+			t.Fatalf("deliver() of recipient %d = %v", recipient.ID, delivered)
+		}
+		if !repo.sending[recipient.ID] {
+			t.Fatalf("recipient %d was not marked sending before its message was sent", recipient.ID)
+		}
+	}
+
+	if repo.failed[2] != campaignNotOnWhatsAppErrorMsg {
+		t.Fatalf("unregistered recipient error = %q, want %q", repo.failed[2], campaignNotOnWhatsAppErrorMsg)
+	}
+	if repo.failed[3] != "invalid media" {
+		t.Fatalf("failed send error = %q, want %q", repo.failed[3], "invalid media")
+	}
+	if _, failed := repo.failed[4]; failed || repo.requeued[4] != whatsmeow.ErrNotConnected.Error() {
+		t.Fatalf("recipient of a send that failed on the connection was not requeued (failed %v, requeued %q)", failed, repo.requeued[4])
+	}
+	if _, requeued := repo.requeued[5]; requeued || repo.review[5] == "" {
+		t.Fatalf("recipient of a timed out send was not flagged for review (requeued %v, review %q)", requeued, repo.review[5])
+	}
+	if repo.sent[1] != "MSG-6281111111111" {
+		t.Fatalf("sent message ID = %q, want %q", repo.sent[1], "MSG-6281111111111")
+	}
+	if len(messages) != 1 || messages[0] != "Hi Ann (6281111111111)" {
+		t.Fatalf("rendered messages = %v", messages)
+	}
+}
+
+func TestCampaignDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		campaign domainChatStorage.Campaign
+		min      time.Duration
+		max      time.Duration
+	}{
+		{
+			name:     "Rate limit only",
+			campaign: domainChatStorage.Campaign{RatePerMinute: 30},
+			min:      2 * time.Second,
+			max:      2 * time.Second,
+		},
+		{
+			name:     "Default rate",
+			campaign: domainChatStorage.Campaign{},
+			min:      3 * time.Second,
+			max:      3 * time.Second,
+		},
+		{
+			name:     "Random delay longer than rate",
+			campaign: domainChatStorage.Campaign{RatePerMinute: 60, MinDelaySeconds: 5, MaxDelaySeconds: 10},
+			min:      5 * time.Second,
+			max:      10 * time.Second,
+		},
+		{
+			name:     "Rate longer than random delay",
+			campaign: domainChatStorage.Campaign{RatePerMinute: 1, MinDelaySeconds: 1, MaxDelaySeconds: 2},
+			min:      time.Minute,
+			max:      time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				got := campaignDelay(&tt.campaign)
+				if got < tt.min || got > tt.max {
+					t.Fatalf("campaignDelay() = %s, want between %s and %s", got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,430 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/store/sqlstore"
+	"go.mau.fi/whatsmeow/types"
+	waLog "go.mau.fi/whatsmeow/util/log"
+)
+
+// DefaultDeviceID is the identifier of the device created by InitWaCLI.
+// Requests without an explicit device selection are served by this device.
+const DefaultDeviceID = "default"
+
+// ChatStorageFactory opens the chat storage repository used by a device
+type ChatStorageFactory func(deviceID string) (domainChatStorage.IChatStorageRepository, error)
+
+// DeviceInstance owns a single whatsmeow client together with its chat storage and webhooks
+type DeviceInstance struct {
+	mu              sync.RWMutex
+	id              string
+	client          *whatsmeow.Client
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+	webhooks        []string
+}
+
+// ID returns the device identifier used for routing
+func (d *DeviceInstance) ID() string {
+	return d.id
+}
+
+// GetClient returns the whatsmeow client of the device
+func (d *DeviceInstance) GetClient() *whatsmeow.Client {
+	// The default device always follows the global client so that
+	// reinitialization after logout is picked up transparently.
+	if d.id == DefaultDeviceID {
+		return cli
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.client
+}
+
+// GetChatStorage returns the chat storage repository of the device
+func (d *DeviceInstance) GetChatStorage() domainChatStorage.IChatStorageRepository {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.chatStorageRepo
+}
+
+// Webhooks returns the device specific webhook URLs
+func (d *DeviceInstance) Webhooks() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return append([]string(nil), d.webhooks...)
+}
+
+// JID returns the WhatsApp JID of the paired device, or an empty string when not paired yet
+func (d *DeviceInstance) JID() string {
+	client := d.GetClient()
+	if client == nil || client.Store == nil || client.Store.ID == nil {
+		return ""
+	}
+	return client.Store.ID.String()
+}
+
+// Status returns the connection status of the device
+func (d *DeviceInstance) Status() (isConnected bool, isLoggedIn bool, deviceJID string) {
+	client := d.GetClient()
+	if client == nil {
+		return false, false, ""
+	}
+	return client.IsConnected(), client.IsLoggedIn(), d.JID()
+}
+
+func (d *DeviceInstance) setClient(client *whatsmeow.Client) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.client = client
+}
+
+func (d *DeviceInstance) setWebhooks(webhooks []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.webhooks = webhooks
+}
+
+// DeviceManager keeps one whatsmeow client per paired device of the shared store container
+type DeviceManager struct {
+	mu              sync.RWMutex
+	devices         map[string]*DeviceInstance
+	container       *sqlstore.Container
+	keysContainer   *sqlstore.Container
+	registry        domainChatStorage.IChatStorageRepository
+	storageFactory  ChatStorageFactory
+	eventsBaseCtx   context.Context
+	registryEnabled bool
+}
+
+var deviceManager *DeviceManager
+
+// InitDeviceManager registers the default device and loads every additional device from the registry
+func InitDeviceManager(ctx context.Context, storeContainer, keysStoreContainer *sqlstore.Container, defaultRepo domainChatStorage.IChatStorageRepository, storageFactory ChatStorageFactory) *DeviceManager {
+	manager := &DeviceManager{
+		devices:         make(map[string]*DeviceInstance),
+		container:       storeContainer,
+		keysContainer:   keysStoreContainer,
+		registry:        defaultRepo,
+		storageFactory:  storageFactory,
+		eventsBaseCtx:   ctx,
+		registryEnabled: defaultRepo != nil,
+	}
+
+	defaultDevice := &DeviceInstance{
+		id:              DefaultDeviceID,
+		chatStorageRepo: defaultRepo,
+	}
+	manager.devices[DefaultDeviceID] = defaultDevice
+	deviceManager = manager
+
+	if !manager.registryEnabled {
+		return manager
+	}
+
+	records, err := defaultRepo.GetDeviceRecords()
+	if err != nil {
+		logrus.Errorf("[DEVICE] Failed to load device registry: %v", err)
+		return manager
+	}
+
+	for _, record := range records {
+		if record.ID == DefaultDeviceID {
+			defaultDevice.setWebhooks(record.Webhooks)
+			continue
+		}
+
+		if _, err := manager.loadDevice(ctx, record); err != nil {
+			logrus.Errorf("[DEVICE] Failed to load device %s: %v", record.ID, err)
+		}
+	}
+
+	return manager
+}
+
+// GetDeviceManager returns the process wide device manager
+func GetDeviceManager() *DeviceManager {
+	return deviceManager
+}
+
+// Get returns the device registered with the given ID
+func (m *DeviceManager) Get(id string) (*DeviceInstance, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	device, ok := m.devices[id]
+	return device, ok
+}
+
+// List returns all registered devices with the default device first
+func (m *DeviceManager) List() []*DeviceInstance {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	devices := make([]*DeviceInstance, 0, len(m.devices))
+	for _, device := range m.devices {
+		devices = append(devices, device)
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].id == DefaultDeviceID {
+			return true
+		}
+		if devices[j].id == DefaultDeviceID {
+			return false
+		}
+		return devices[i].id < devices[j].id
+	})
+
+	return devices
+}
+
+// Count returns the number of registered devices
+func (m *DeviceManager) Count() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.devices)
+}
+
+// AddDevice registers a new, not yet paired device and starts its client
+func (m *DeviceManager) AddDevice(ctx context.Context, id string, webhooks []string) (*DeviceInstance, error) {
+	if _, exists := m.Get(id); exists {
+		return nil, pkgError.ErrDeviceAlreadyExists
+	}
+
+	record := &domainChatStorage.DeviceRecord{ID: id, Webhooks: webhooks}
+	device, err := m.loadDevice(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.saveRecord(device); err != nil {
+		return nil, err
+	}
+
+	return device, nil
+}
+
+// UpdateWebhooks replaces the webhook URLs of a device
+func (m *DeviceManager) UpdateWebhooks(id string, webhooks []string) (*DeviceInstance, error) {
+	device, ok := m.Get(id)
+	if !ok {
+		return nil, pkgError.ErrDeviceNotFound
+	}
+
+	device.setWebhooks(webhooks)
+	return device, m.saveRecord(device)
+}
+
+// RemoveDevice logs out and forgets a device. The default device cannot be removed.
+func (m *DeviceManager) RemoveDevice(ctx context.Context, id string) error {
+	if id == DefaultDeviceID {
+		return pkgError.ErrDefaultDeviceRemove
+	}
+
+	device, ok := m.Get(id)
+	if !ok {
+		return pkgError.ErrDeviceNotFound
+	}
+
+	if client := device.GetClient(); client != nil {
+		if client.IsLoggedIn() {
+			if err := client.Logout(ctx); err != nil {
+				logrus.Warnf("[DEVICE] Logout of device %s failed: %v", id, err)
+			}
+		}
+		client.Disconnect()
+		m.deleteStoreDevice(ctx, client.Store)
+	}
+
+	if repo := device.GetChatStorage(); repo != nil {
+		if err := repo.TruncateAllDataWithLogging("DEVICE_REMOVE"); err != nil {
+			logrus.Warnf("[DEVICE] Failed to truncate chat storage of device %s: %v", id, err)
+		}
+	}
+
+	m.mu.Lock()
+	delete(m.devices, id)
+	m.mu.Unlock()
+
+	if m.registryEnabled {
+		return m.registry.DeleteDeviceRecord(id)
+	}
+	return nil
+}
+
+// ResetDevice replaces the session of a device with a fresh, unpaired one.
+// It is used after a logout so the device can be paired again without restarting.
+func (m *DeviceManager) ResetDevice(ctx context.Context, device *DeviceInstance) error {
+	if client := device.GetClient(); client != nil {
+		client.Disconnect()
+		m.deleteStoreDevice(ctx, client.Store)
+	}
+
+	if repo := device.GetChatStorage(); repo != nil {
+		if err := repo.TruncateAllDataWithLogging("DEVICE_RESET"); err != nil {
+			logrus.Warnf("[DEVICE] Failed to truncate chat storage of device %s: %v", device.id, err)
+		}
+	}
+
+	client := m.newClient(ctx, device, m.container.NewDevice())
+	if device.id == DefaultDeviceID {
+		UpdateGlobalClient(client, m.container)
+	} else {
+		device.setClient(client)
+	}
+
+	return m.saveRecord(device)
+}
+
+// OnPaired stores the JID of a freshly paired device in the registry
+func (m *DeviceManager) OnPaired(device *DeviceInstance) {
+	if err := m.saveRecord(device); err != nil {
+		logrus.Errorf("[DEVICE] Failed to save device %s after pairing: %v", device.id, err)
+	}
+}
+
+func (m *DeviceManager) loadDevice(ctx context.Context, record *domainChatStorage.DeviceRecord) (*DeviceInstance, error) {
+	repo, err := m.storageFactory(record.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open chat storage: %w", err)
+	}
+
+	var storeDevice *store.Device
+	if record.JID != "" {
+		jid, err := types.ParseJID(record.JID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid device JID %s: %w", record.JID, err)
+		}
+		storeDevice, err = m.container.GetDevice(ctx, jid)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load device %s: %w", record.JID, err)
+		}
+	}
+	if storeDevice == nil {
+		// Either never paired or removed from the phone in the meantime
+		storeDevice = m.container.NewDevice()
+	}
+
+	device := &DeviceInstance{
+		id:              record.ID,
+		chatStorageRepo: repo,
+		webhooks:        record.Webhooks,
+	}
+	device.setClient(m.newClient(ctx, device, storeDevice))
+
+	m.mu.Lock()
+	m.devices[record.ID] = device
+	m.mu.Unlock()
+
+	if client := device.GetClient(); client.Store.ID != nil {
+		go func() {
+			if err := client.Connect(); err != nil {
+				logrus.Errorf("[DEVICE] Failed to connect device %s: %v", record.ID, err)
+			}
+		}()
+	}
+
+	return device, nil
+}
+
+func (m *DeviceManager) newClient(ctx context.Context, device *DeviceInstance, storeDevice *store.Device) *whatsmeow.Client {
+	if m.keysContainer != nil && storeDevice.ID != nil {
+		innerStore := sqlstore.NewSQLStore(m.keysContainer, *storeDevice.ID)
+		storeDevice.Identities = innerStore
+		storeDevice.Sessions = innerStore
+		storeDevice.PreKeys = innerStore
+		storeDevice.SenderKeys = innerStore
+		storeDevice.MsgSecrets = innerStore
+		storeDevice.PrivacyTokens = innerStore
+	}
+
+	client := whatsmeow.NewClient(storeDevice, waLog.Stdout(fmt.Sprintf("Client/%s", device.id), config.WhatsappLogLevel, true))
+	client.EnableAutoReconnect = true
+	client.AutoTrustIdentity = true
+
+	eventCtx := ContextWithDevice(m.eventsBaseCtx, device)
+	client.AddEventHandler(func(rawEvt interface{}) {
+		handler(eventCtx, rawEvt, device.GetChatStorage())
+	})
+
+	return client
+}
+
+func (m *DeviceManager) deleteStoreDevice(ctx context.Context, storeDevice *store.Device) {
+	if storeDevice == nil || storeDevice.ID == nil {
+		return
+	}
+
+	if err := m.container.DeleteDevice(ctx, storeDevice); err != nil {
+		logrus.Warnf("[DEVICE] Failed to delete device %s from store: %v", storeDevice.ID, err)
+	}
+	if m.keysContainer != nil && m.keysContainer != m.container {
+		if err := m.keysContainer.DeleteDevice(ctx, storeDevice); err != nil {
+			logrus.Warnf("[DEVICE] Failed to delete device %s from keys store: %v", storeDevice.ID, err)
+		}
+	}
+}
+
+func (m *DeviceManager) saveRecord(device *DeviceInstance) error {
+	if !m.registryEnabled {
+		return nil
+	}
+
+	return m.registry.StoreDeviceRecord(&domainChatStorage.DeviceRecord{
+		ID:       device.id,
+		JID:      device.JID(),
+		Webhooks: device.Webhooks(),
+	})
+}
+
+type deviceContextKey struct{}
+
+// ContextWithDevice returns a copy of ctx bound to the given device
+func ContextWithDevice(ctx context.Context, device *DeviceInstance) context.Context {
+	return context.WithValue(ctx, deviceContextKey{}, device)
+}
+
+// DeviceFromContext returns the device bound to ctx, falling back to the default device
+func DeviceFromContext(ctx context.Context) *DeviceInstance {
+	if ctx != nil {
+		if device, ok := ctx.Value(deviceContextKey{}).(*DeviceInstance); ok && device != nil {
+			return device
+		}
+	}
+
+	if deviceManager != nil {
+		if device, ok := deviceManager.Get(DefaultDeviceID); ok {
+			return device
+		}
+	}
+
+	return nil
+}
+
+// ClientFromContext returns the whatsmeow client of the device bound to ctx
+func ClientFromContext(ctx context.Context) *whatsmeow.Client {
+	if device := DeviceFromContext(ctx); device != nil {
+		return device.GetClient()
+	}
+	return cli
+}
+
+// ChatStorageFromContext returns the chat storage of the device bound to ctx, or fallback when none is bound
+func ChatStorageFromContext(ctx context.Context, fallback domainChatStorage.IChatStorageRepository) domainChatStorage.IChatStorageRepository {
+	if device := DeviceFromContext(ctx); device != nil {
+		if repo := device.GetChatStorage(); repo != nil {
+			return repo
+		}
+	}
+	return fallback
+}
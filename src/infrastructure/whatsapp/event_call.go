@@ -0,0 +1,345 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	domainCall "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/call"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/sirupsen/logrus"
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// CallRejectSender sends the follow-up message to the caller of an auto-rejected call
+type CallRejectSender func(ctx context.Context, phone string, message string) error
+
+// callRejectPolicy decides which incoming calls are rejected automatically
+type callRejectPolicy struct {
+	policy   string
+	message  string
+	hours    *domainAutoReply.BusinessHours
+	location *time.Location
+	send     CallRejectSender
+}
+
+var callRejection atomic.Pointer[callRejectPolicy]
+
+var weekdays = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+// InitCallRejection applies the configured auto-reject policy and sets how follow-up messages are sent
+func InitCallRejection(send CallRejectSender) error {
+	policy, err := newCallRejectPolicy(config.WhatsappCallReject, config.WhatsappCallRejectMessage,
+		config.WhatsappCallBusinessHours, config.WhatsappCallBusinessDays, config.WhatsappCallTimezone)
+	if err != nil {
+		return err
+	}
+
+	if policy != nil {
+		policy.send = send
+		logrus.Infof("[CALL] Incoming calls are rejected automatically (policy: %s)", policy.policy)
+	}
+	callRejection.Store(policy)
+	return nil
+}
+
+// newCallRejectPolicy validates the auto-reject settings, an empty policy disables auto-reject
+func newCallRejectPolicy(policy string, message string, hours string, days []string, timezone string) (*callRejectPolicy, error) {
+	policy = strings.ToLower(strings.TrimSpace(policy))
+	if policy == "" {
+		return nil, nil
+	}
+	if !slices.Contains(domainCall.RejectPolicies, policy) {
+		return nil, fmt.Errorf("unknown call reject policy %q, use one of %s", policy, strings.Join(domainCall.RejectPolicies, ", "))
+	}
+
+	result := &callRejectPolicy{policy: policy, message: strings.TrimSpace(message)}
+	if policy != domainCall.RejectOutsideHours {
+		return result, nil
+	}
+
+	start, end, _ := strings.Cut(hours, "-")
+	start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+	for _, value := range []string{start, end} {
+		if _, err := time.Parse("15:04", value); err != nil {
+			return nil, fmt.Errorf("invalid call business hours %q, use HH:MM-HH:MM", hours)
+		}
+	}
+
+	businessDays := make([]string, 0, len(days))
+	for _, day := range days {
+		day = strings.ToLower(strings.TrimSpace(day))
+		if day == "" {
+			continue
+		}
+		if !slices.Contains(weekdays, day) {
+			return nil, fmt.Errorf("invalid call business day %q, use one of %s", day, strings.Join(weekdays, ", "))
+		}
+		businessDays = append(businessDays, day)
+	}
+
+	result.location = time.Local
+	if timezone != "" {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid call timezone %q: %w", timezone, err)
+		}
+		result.location = location
+	}
+
+	result.hours = &domainAutoReply.BusinessHours{Days: businessDays, Start: start, End: end, Outside: true}
+	return result, nil
+}
+
+// shouldReject reports whether an incoming call at now is rejected, isKnownContact is only
+// consulted by the unknown policy
+func (p *callRejectPolicy) shouldReject(now time.Time, isKnownContact func() bool) bool {
+	switch p.policy {
+	case domainCall.RejectAll:
+		return true
+	case domainCall.RejectUnknown:
+		return !isKnownContact()
+	case domainCall.RejectOutsideHours:
+		return matchesBusinessHours(p.hours, p.location, now)
+	}
+	return false
+}
+
+// handleCallOffer logs a ringing call and rejects it when the auto-reject policy says so
+func handleCallOffer(ctx context.Context, meta types.BasicCallMeta, isVideo bool, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	// Group calls can be announced by both an offer and an offer notice
+	if existing, err := chatStorageRepo.GetCall(meta.CallID); err == nil && existing != nil {
+		return
+	}
+
+	call := newCallRecord(ctx, meta)
+	call.IsVideo = isVideo
+	logrus.Infof("[CALL] Incoming %s call %s from %s", callMedia(call), call.ID, call.CallerJID)
+
+	policy := callRejection.Load()
+	rejected := false
+	if policy != nil && !call.IsFromMe && !call.IsGroup {
+		rejected = policy.shouldReject(time.Now(), func() bool { return isKnownCaller(ctx, meta) })
+	}
+
+	if rejected {
+		if err := ClientFromContext(ctx).RejectCall(ctx, meta.From, meta.CallID); err != nil {
+			logrus.Errorf("[CALL] Failed to reject call %s from %s: %v", call.ID, call.CallerJID, err)
+			rejected = false
+		}
+	}
+
+	if rejected {
+		now := time.Now()
+		call.Status = domainChatStorage.CallStatusRejected
+		call.AutoRejected = true
+		call.EndReason = "auto_rejected"
+		call.EndedAt = &now
+	}
+
+	if err := chatStorageRepo.StoreCall(call); err != nil {
+		logrus.Errorf("[CALL] Failed to store call %s: %v", call.ID, err)
+	}
+
+	emitCallEvent(ctx, domainWebhook.EventCallOffer, call)
+	if !rejected {
+		return
+	}
+
+	emitCallEvent(ctx, domainWebhook.EventCallEnded, call)
+	if policy.message != "" && policy.send != nil {
+		go func() {
+			if err := policy.send(ctx, callerPhone(meta), policy.message); err != nil {
+				logrus.Errorf("[CALL] Failed to send follow-up message for call %s: %v", call.ID, err)
+			}
+		}()
+	}
+}
+
+// handleCallAccept marks a call as answered, by us on another device or by the other party
+func handleCallAccept(ctx context.Context, meta types.BasicCallMeta, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	call, err := chatStorageRepo.GetCall(meta.CallID)
+	if err != nil {
+		logrus.Errorf("[CALL] Failed to load call %s: %v", meta.CallID, err)
+		return
+	}
+	if call == nil {
+		// Calls started from the phone are only seen once they are answered
+		call = newCallRecord(ctx, meta)
+	}
+
+	acceptedAt := meta.Timestamp
+	call.Status = domainChatStorage.CallStatusAccepted
+	call.AcceptedAt = &acceptedAt
+
+	if err := chatStorageRepo.StoreCall(call); err != nil {
+		logrus.Errorf("[CALL] Failed to store call %s: %v", call.ID, err)
+	}
+}
+
+// handleCallEnd closes a call: accepted calls end, rejected ones stay rejected and the others were missed
+func handleCallEnd(ctx context.Context, meta types.BasicCallMeta, status string, reason string, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	call, err := chatStorageRepo.GetCall(meta.CallID)
+	if err != nil {
+		logrus.Errorf("[CALL] Failed to load call %s: %v", meta.CallID, err)
+		return
+	}
+	if call == nil {
+		call = newCallRecord(ctx, meta)
+	}
+	if call.EndedAt != nil {
+		// Auto-rejected calls already ended when they were rejected
+		return
+	}
+
+	switch {
+	case status != "":
+		call.Status = status
+	case call.Status == domainChatStorage.CallStatusAccepted:
+		call.Status = domainChatStorage.CallStatusEnded
+	default:
+		call.Status = domainChatStorage.CallStatusMissed
+	}
+	endedAt := meta.Timestamp
+	call.EndReason = reason
+	call.EndedAt = &endedAt
+	logrus.Infof("[CALL] Call %s with %s %s (%s)", call.ID, call.ChatJID, call.Status, reason)
+
+	if err := chatStorageRepo.StoreCall(call); err != nil {
+		logrus.Errorf("[CALL] Failed to store call %s: %v", call.ID, err)
+	}
+
+	emitCallEvent(ctx, domainWebhook.EventCallEnded, call)
+}
+
+// newCallRecord starts a call log entry from the metadata shared by all call events
+func newCallRecord(ctx context.Context, meta types.BasicCallMeta) *domainChatStorage.Call {
+	call := &domainChatStorage.Call{
+		ID:        meta.CallID,
+		ChatJID:   callerPhone(meta),
+		CallerJID: meta.CallCreator.ToNonAD().String(),
+		IsFromMe:  isOwnCall(ctx, meta),
+		Status:    domainChatStorage.CallStatusRinging,
+		StartedAt: meta.Timestamp,
+	}
+	if !meta.GroupJID.IsEmpty() {
+		call.IsGroup = true
+		call.ChatJID = meta.GroupJID.String()
+	} else if call.IsFromMe {
+		// Calls we started are logged under the callee
+		call.ChatJID = meta.From.ToNonAD().String()
+	}
+	return call
+}
+
+// callerPhone returns the phone number JID of the caller, falling back to the LID when it is unknown
+func callerPhone(meta types.BasicCallMeta) string {
+	if meta.CallCreator.Server == types.HiddenUserServer && meta.CallCreatorAlt.Server == types.DefaultUserServer {
+		return meta.CallCreatorAlt.ToNonAD().String()
+	}
+	if meta.CallCreator.IsEmpty() {
+		return meta.From.ToNonAD().String()
+	}
+	return meta.CallCreator.ToNonAD().String()
+}
+
+// isOwnCall reports whether the call was started by the logged in account
+func isOwnCall(ctx context.Context, meta types.BasicCallMeta) bool {
+	client := ClientFromContext(ctx)
+	if client == nil || client.Store == nil || client.Store.ID == nil || meta.CallCreator.IsEmpty() {
+		return false
+	}
+	creator := meta.CallCreator.ToNonAD()
+	return creator.User == client.Store.GetJID().User || creator.User == client.Store.GetLID().User
+}
+
+// isKnownCaller reports whether the caller is in the contact list of the account
+func isKnownCaller(ctx context.Context, meta types.BasicCallMeta) bool {
+	client := ClientFromContext(ctx)
+	if client == nil || client.Store == nil || client.Store.Contacts == nil {
+		return false
+	}
+
+	for _, jid := range []types.JID{meta.CallCreator, meta.CallCreatorAlt, meta.From} {
+		if jid.IsEmpty() {
+			continue
+		}
+		contact, err := client.Store.Contacts.GetContact(ctx, jid.ToNonAD())
+		if err == nil && contact.Found && (contact.FullName != "" || contact.FirstName != "") {
+			return true
+		}
+	}
+	return false
+}
+
+// callOfferIsVideo reports whether a call offer carries a video stream
+func callOfferIsVideo(data *waBinary.Node) bool {
+	if data == nil {
+		return false
+	}
+	_, ok := data.GetOptionalChildByTag("video")
+	return ok
+}
+
+func callMedia(call *domainChatStorage.Call) string {
+	if call.IsVideo {
+		return "video"
+	}
+	return "voice"
+}
+
+// createCallPayload creates a webhook payload for call events
+func createCallPayload(event string, call *domainChatStorage.Call) map[string]any {
+	payload := map[string]any{
+		"call_id":       call.ID,
+		"chat_id":       call.ChatJID,
+		"caller":        call.CallerJID,
+		"is_from_me":    call.IsFromMe,
+		"is_video":      call.IsVideo,
+		"is_group":      call.IsGroup,
+		"status":        call.Status,
+		"auto_rejected": call.AutoRejected,
+	}
+
+	timestamp := call.StartedAt
+	if event == domainWebhook.EventCallEnded && call.EndedAt != nil {
+		timestamp = *call.EndedAt
+		payload["reason"] = call.EndReason
+		duration := 0
+		if call.AcceptedAt != nil {
+			duration = int(call.EndedAt.Sub(*call.AcceptedAt).Seconds())
+		}
+		payload["duration_seconds"] = duration
+	}
+
+	return map[string]any{
+		"event":     event,
+		"timestamp": timestamp.Format(time.RFC3339),
+		"payload":   payload,
+	}
+}
+
+// emitCallEvent sends a call event to the event stream and the configured webhooks
+func emitCallEvent(ctx context.Context, event string, call *domainChatStorage.Call) {
+	if !hasEventTargets(ctx) {
+		return
+	}
+
+	body := createCallPayload(event, call)
+
+	chatJID, _ := types.ParseJID(call.ChatJID)
+	callerJID, _ := types.ParseJID(call.CallerJID)
+	ctx = withWebhookEvent(ctx, webhookEvent{Name: event, ChatJID: chatJID, SenderJID: callerJID, IsFromMe: call.IsFromMe})
+	go func() {
+		if err := forwardPayloadToConfiguredWebhooks(ctx, body, event+" event"); err != nil {
+			logrus.Errorf("[CALL] Failed to forward %s event to webhook: %v", event, err)
+		}
+	}()
+}
@@ -0,0 +1,158 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainCall "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/call"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow/types"
+)
+
+type fakeCallRepo struct {
+	domainChatStorage.IChatStorageRepository
+	calls map[string]*domainChatStorage.Call
+}
+
+func (f *fakeCallRepo) GetCall(id string) (*domainChatStorage.Call, error) {
+	call, ok := f.calls[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *call
+	return &copied, nil
+}
+
+func (f *fakeCallRepo) StoreCall(call *domainChatStorage.Call) error {
+	copied := *call
+	f.calls[call.ID] = &copied
+	return nil
+}
+
+func TestNewCallRejectPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		hours    string
+		days     []string
+		timezone string
+		wantErr  bool
+	}{
+		{name: "Disabled", policy: ""},
+		{name: "Reject all", policy: "all"},
+		{name: "Outside hours", policy: "outside_hours", hours: "08:00-16:30", days: []string{"Mon", " tue"}, timezone: "Asia/Jakarta"},
+		{name: "Unknown policy", policy: "everyone", wantErr: true},
+		{name: "Invalid hours", policy: "outside_hours", hours: "8-16", wantErr: true},
+		{name: "Invalid day", policy: "outside_hours", hours: "08:00-16:00", days: []string{"monday"}, wantErr: true},
+		{name: "Invalid timezone", policy: "outside_hours", hours: "08:00-16:00", timezone: "Mars/Base", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := newCallRejectPolicy(tt.policy, "", tt.hours, tt.days, tt.timezone)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newCallRejectPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.policy == "" && policy != nil {
+				t.Fatalf("newCallRejectPolicy() = %+v, want nil for an empty policy", policy)
+			}
+		})
+	}
+}
+
+func TestCallRejectPolicyShouldReject(t *testing.T) {
+	known := func() bool { return true }
+	unknown := func() bool { return false }
+	// Monday 19:00 and 10:00 in Jakarta
+	evening := time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)
+	morning := time.Date(2025, 1, 6, 3, 0, 0, 0, time.UTC)
+
+	outsideHours, err := newCallRejectPolicy(domainCall.RejectOutsideHours, "", "09:00-17:00", []string{"mon"}, "Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		policy *callRejectPolicy
+		now    time.Time
+		known  func() bool
+		want   bool
+	}{
+		{name: "All rejects contacts", policy: &callRejectPolicy{policy: domainCall.RejectAll}, now: morning, known: known, want: true},
+		{name: "Unknown rejects strangers", policy: &callRejectPolicy{policy: domainCall.RejectUnknown}, now: morning, known: unknown, want: true},
+		{name: "Unknown lets contacts ring", policy: &callRejectPolicy{policy: domainCall.RejectUnknown}, now: morning, known: known, want: false},
+		{name: "Outside hours rejects in the evening", policy: outsideHours, now: evening, known: known, want: true},
+		{name: "Outside hours lets calls ring during the day", policy: outsideHours, now: morning, known: unknown, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.shouldReject(tt.now, tt.known); got != tt.want {
+				t.Fatalf("shouldReject() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleCallEnd(t *testing.T) {
+	caller := types.NewJID("628111", types.DefaultUserServer)
+	startedAt := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	meta := func(id string, at time.Time) types.BasicCallMeta {
+		return types.BasicCallMeta{From: caller, CallCreator: caller, CallID: id, Timestamp: at}
+	}
+
+	tests := []struct {
+		name       string
+		existing   *domainChatStorage.Call
+		status     string
+		wantStatus string
+	}{
+		{
+			name:       "Unanswered call was missed",
+			existing:   &domainChatStorage.Call{ID: "call-1", Status: domainChatStorage.CallStatusRinging, StartedAt: startedAt},
+			wantStatus: domainChatStorage.CallStatusMissed,
+		},
+		{
+			name:       "Answered call ended",
+			existing:   &domainChatStorage.Call{ID: "call-1", Status: domainChatStorage.CallStatusAccepted, StartedAt: startedAt},
+			wantStatus: domainChatStorage.CallStatusEnded,
+		},
+		{
+			name:       "Callee rejected",
+			existing:   &domainChatStorage.Call{ID: "call-1", Status: domainChatStorage.CallStatusRinging, StartedAt: startedAt},
+			status:     domainChatStorage.CallStatusRejected,
+			wantStatus: domainChatStorage.CallStatusRejected,
+		},
+		{
+			name:       "Unseen call is logged",
+			wantStatus: domainChatStorage.CallStatusMissed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeCallRepo{calls: map[string]*domainChatStorage.Call{}}
+			if tt.existing != nil {
+				repo.calls[tt.existing.ID] = tt.existing
+			}
+
+			handleCallEnd(context.Background(), meta("call-1", startedAt.Add(time.Minute)), tt.status, "timeout", repo)
+
+			call := repo.calls["call-1"]
+			if call == nil {
+				t.Fatal("call was not stored")
+			}
+			if call.Status != tt.wantStatus {
+				t.Fatalf("status = %s, want %s", call.Status, tt.wantStatus)
+			}
+			if call.EndedAt == nil || !call.EndedAt.Equal(startedAt.Add(time.Minute)) {
+				t.Fatalf("ended_at = %v, want %v", call.EndedAt, startedAt.Add(time.Minute))
+			}
+			if call.EndReason != "timeout" {
+				t.Fatalf("end_reason = %q, want timeout", call.EndReason)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
@@ -15,6 +16,12 @@ func forwardDeleteToWebhook(ctx context.Context, evt *events.DeleteForMe, messag
 		return err
 	}
 
+	ctx = withWebhookEvent(ctx, webhookEvent{
+		Name:      domainWebhook.EventMessageDeleted,
+		ChatJID:   evt.ChatJID,
+		SenderJID: evt.SenderJID,
+		IsFromMe:  evt.IsFromMe,
+	})
 	return forwardPayloadToConfiguredWebhooks(ctx, payload, "delete event")
 }
 
@@ -3,10 +3,11 @@ package whatsapp
 import (
 	"context"
 	"fmt"
-	"strings"
+	"strconv"
 	"time"
 
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
@@ -51,7 +52,11 @@ func jidsToStrings(jids []types.JID) []string {
 
 // forwardGroupInfoToWebhook forwards group information events to the configured webhook URLs
 func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo) error {
-	logrus.Infof("Forwarding group info event to %d configured webhook(s)", len(config.WhatsappWebhook))
+	event := webhookEvent{Name: domainWebhook.EventGroupParticipants, ChatJID: evt.JID}
+	if evt.Sender != nil {
+		event.SenderJID = *evt.Sender
+	}
+	ctx = withWebhookEvent(ctx, event)
 
 	// Send separate webhook events for each action type
 	actions := []struct {
@@ -68,31 +73,179 @@ func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo) error
 		if len(action.jids) > 0 {
 			payload := createGroupInfoPayload(evt, action.actionType, action.jids)
 
-			// Collect errors from all webhook URLs instead of failing fast
-			var errors []error
-			for _, url := range config.WhatsappWebhook {
-				if err := submitWebhook(ctx, payload, url); err != nil {
-					errors = append(errors, fmt.Errorf("webhook %s failed: %w", url, err))
-				}
+			if err := forwardPayloadToConfiguredWebhooks(ctx, payload, fmt.Sprintf("group %s event", action.actionType)); err != nil {
+				return err
 			}
 
-			// If all webhooks failed, return combined error
-			if len(errors) == len(config.WhatsappWebhook) && len(errors) > 0 {
-				var errMessages []string
-				for _, err := range errors {
-					errMessages = append(errMessages, err.Error())
-				}
-				return fmt.Errorf("all webhook URLs failed: %s", strings.Join(errMessages, "; "))
-			}
+			logrus.Infof("Group %s event forwarded to webhook: %d users %s", action.actionType, len(action.jids), action.actionType)
+		}
+	}
+
+	return nil
+}
 
-			// Log partial failures
-			if len(errors) > 0 {
-				logrus.Warnf("Some webhook URLs failed for group %s event: %v", action.actionType, errors)
+// recordGroupEvents stores the membership and settings changes of a group event and sends the settings
+// changes as group.settings events
+func recordGroupEvents(ctx context.Context, evt *events.GroupInfo, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	groupEvents := groupEventsFromInfo(ctx, evt)
+	storeGroupEvents(ctx, groupEvents, chatStorageRepo)
+}
+
+// handleGroupPicture records the photo changes of groups, photos of contacts are ignored
+func handleGroupPicture(ctx context.Context, evt *events.Picture, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if evt.JID.Server != types.GroupServer {
+		return
+	}
+
+	event := &domainChatStorage.GroupEvent{
+		GroupJID:  evt.JID.String(),
+		Type:      domainChatStorage.GroupEventPhoto,
+		Value:     evt.PictureID,
+		Timestamp: evt.Timestamp,
+	}
+	if !evt.Author.IsEmpty() {
+		event.ActorJID = phoneNumberJID(ctx, evt.Author).String()
+	}
+	logrus.Infof("[GROUP] Group %s: photo changed by %s at %s", evt.JID, event.ActorJID, evt.Timestamp)
+	storeGroupEvents(ctx, []*domainChatStorage.GroupEvent{event}, chatStorageRepo)
+}
+
+func storeGroupEvents(ctx context.Context, groupEvents []*domainChatStorage.GroupEvent, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if len(groupEvents) == 0 {
+		return
+	}
+
+	if chatStorageRepo != nil {
+		if err := chatStorageRepo.StoreGroupEvents(groupEvents); err != nil {
+			logrus.Errorf("[GROUP] Failed to store events of group %s: %v", groupEvents[0].GroupJID, err)
+		}
+	}
+
+	if hasEventTargets(ctx) {
+		go forwardGroupSettingsToWebhook(ctx, groupEvents)
+	}
+}
+
+// groupEventsFromInfo lists the changes of a group event, one per participant for membership changes
+func groupEventsFromInfo(ctx context.Context, evt *events.GroupInfo) []*domainChatStorage.GroupEvent {
+	var actor string
+	if evt.SenderPN != nil && !evt.SenderPN.IsEmpty() {
+		actor = evt.SenderPN.ToNonAD().String()
+	} else if evt.Sender != nil && !evt.Sender.IsEmpty() {
+		actor = phoneNumberJID(ctx, *evt.Sender).String()
+	}
+
+	var groupEvents []*domainChatStorage.GroupEvent
+	add := func(eventType string, target string, value string) {
+		groupEvents = append(groupEvents, &domainChatStorage.GroupEvent{
+			GroupJID:  evt.JID.String(),
+			Type:      eventType,
+			ActorJID:  actor,
+			TargetJID: target,
+			Value:     value,
+			Timestamp: evt.Timestamp,
+		})
+	}
+
+	for _, membership := range []struct {
+		eventType string
+		jids      []types.JID
+	}{
+		{domainChatStorage.GroupEventJoin, evt.Join},
+		{domainChatStorage.GroupEventLeave, evt.Leave},
+		{domainChatStorage.GroupEventPromote, evt.Promote},
+		{domainChatStorage.GroupEventDemote, evt.Demote},
+	} {
+		for _, jid := range membership.jids {
+			value := ""
+			if membership.eventType == domainChatStorage.GroupEventJoin {
+				value = evt.JoinReason
 			}
+			add(membership.eventType, phoneNumberJID(ctx, jid).String(), value)
+		}
+	}
 
-			logrus.Infof("Group %s event forwarded to webhook: %d users %s", action.actionType, len(action.jids), action.actionType)
+	if evt.Name != nil {
+		add(domainChatStorage.GroupEventName, "", evt.Name.Name)
+	}
+	if evt.Topic != nil {
+		add(domainChatStorage.GroupEventTopic, "", evt.Topic.Topic)
+	}
+	if evt.Locked != nil {
+		add(domainChatStorage.GroupEventLocked, "", strconv.FormatBool(evt.Locked.IsLocked))
+	}
+	if evt.Announce != nil {
+		add(domainChatStorage.GroupEventAnnounce, "", strconv.FormatBool(evt.Announce.IsAnnounce))
+	}
+	if evt.Ephemeral != nil {
+		timer := uint32(0)
+		if evt.Ephemeral.IsEphemeral {
+			timer = evt.Ephemeral.DisappearingTimer
 		}
+		add(domainChatStorage.GroupEventEphemeral, "", strconv.FormatUint(uint64(timer), 10))
+	}
+	if evt.MembershipApprovalMode != nil {
+		add(domainChatStorage.GroupEventMembershipApproval, "", strconv.FormatBool(evt.MembershipApprovalMode.IsJoinApprovalRequired))
+	}
+	if evt.NewInviteLink != nil {
+		add(domainChatStorage.GroupEventInviteLink, "", *evt.NewInviteLink)
+	}
+	if evt.Link != nil {
+		add(domainChatStorage.GroupEventLink, evt.Link.Group.JID.String(), string(evt.Link.Type))
+	}
+	if evt.Unlink != nil {
+		add(domainChatStorage.GroupEventUnlink, evt.Unlink.Group.JID.String(), string(evt.Unlink.Type))
+	}
+	if evt.Delete != nil {
+		add(domainChatStorage.GroupEventDelete, "", evt.Delete.DeleteReason)
 	}
 
-	return nil
+	return groupEvents
+}
+
+// isGroupMembershipEvent reports whether a group event type is sent as group.participants instead of group.settings
+func isGroupMembershipEvent(eventType string) bool {
+	switch eventType {
+	case domainChatStorage.GroupEventJoin, domainChatStorage.GroupEventLeave, domainChatStorage.GroupEventPromote, domainChatStorage.GroupEventDemote:
+		return true
+	}
+	return false
+}
+
+// forwardGroupSettingsToWebhook sends a group.settings event for every settings change,
+// membership changes are sent as group.participants by forwardGroupInfoToWebhook
+func forwardGroupSettingsToWebhook(ctx context.Context, groupEvents []*domainChatStorage.GroupEvent) {
+	for _, groupEvent := range groupEvents {
+		if isGroupMembershipEvent(groupEvent.Type) {
+			continue
+		}
+
+		body := map[string]any{
+			"event":     domainWebhook.EventGroupSettings,
+			"timestamp": time.Now().Format(time.RFC3339),
+			"payload":   groupSettingsPayload(groupEvent),
+		}
+
+		filter := webhookEvent{Name: domainWebhook.EventGroupSettings}
+		if jid, err := types.ParseJID(groupEvent.GroupJID); err == nil {
+			filter.ChatJID = jid
+		}
+		if jid, err := types.ParseJID(groupEvent.ActorJID); err == nil {
+			filter.SenderJID = jid
+		}
+		if err := forwardPayloadToConfiguredWebhooks(withWebhookEvent(ctx, filter), body, fmt.Sprintf("group %s event", groupEvent.Type)); err != nil {
+			logrus.Errorf("[GROUP] Failed to forward group %s event to webhook: %v", groupEvent.Type, err)
+		}
+	}
+}
+
+func groupSettingsPayload(groupEvent *domainChatStorage.GroupEvent) domainWebhook.GroupSettingsPayload {
+	return domainWebhook.GroupSettingsPayload{
+		ChatID:    groupEvent.GroupJID,
+		Type:      groupEvent.Type,
+		Value:     groupEvent.Value,
+		ActorID:   groupEvent.ActorJID,
+		TargetID:  groupEvent.TargetJID,
+		ChangedAt: groupEvent.Timestamp.Format(time.RFC3339),
+	}
 }
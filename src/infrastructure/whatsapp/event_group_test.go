@@ -0,0 +1,111 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+type fakeGroupEventRepo struct {
+	domainChatStorage.IChatStorageRepository
+	events []*domainChatStorage.GroupEvent
+}
+
+func (f *fakeGroupEventRepo) StoreGroupEvents(events []*domainChatStorage.GroupEvent) error {
+	f.events = append(f.events, events...)
+	return nil
+}
+
+func TestGroupEventsFromInfo(t *testing.T) {
+	group := types.NewJID("120363000000000001", types.GroupServer)
+	linked := types.NewJID("120363000000000002", types.GroupServer)
+	admin := types.NewJID("628111", types.DefaultUserServer)
+	member := types.NewJID("628222", types.DefaultUserServer)
+	at := time.Unix(1736000000, 0)
+	inviteLink := "https://chat.whatsapp.com/abc"
+
+	evt := &events.GroupInfo{
+		JID:                    group,
+		Sender:                 &admin,
+		Timestamp:              at,
+		Name:                   &types.GroupName{Name: "Team"},
+		Topic:                  &types.GroupTopic{Topic: "Weekly sync"},
+		Locked:                 &types.GroupLocked{IsLocked: true},
+		Announce:               &types.GroupAnnounce{IsAnnounce: false},
+		Ephemeral:              &types.GroupEphemeral{IsEphemeral: true, DisappearingTimer: 86400},
+		MembershipApprovalMode: &types.GroupMembershipApprovalMode{IsJoinApprovalRequired: true},
+		Link:                   &types.GroupLinkChange{Type: types.GroupLinkChangeTypeSub, Group: types.GroupLinkTarget{JID: linked}},
+		NewInviteLink:          &inviteLink,
+		JoinReason:             "invite",
+		Join:                   []types.JID{member},
+		Promote:                []types.JID{member},
+	}
+
+	groupEvents := groupEventsFromInfo(context.Background(), evt)
+	want := []domainChatStorage.GroupEvent{
+		{Type: domainChatStorage.GroupEventJoin, TargetJID: member.String(), Value: "invite"},
+		{Type: domainChatStorage.GroupEventPromote, TargetJID: member.String()},
+		{Type: domainChatStorage.GroupEventName, Value: "Team"},
+		{Type: domainChatStorage.GroupEventTopic, Value: "Weekly sync"},
+		{Type: domainChatStorage.GroupEventLocked, Value: "true"},
+		{Type: domainChatStorage.GroupEventAnnounce, Value: "false"},
+		{Type: domainChatStorage.GroupEventEphemeral, Value: "86400"},
+		{Type: domainChatStorage.GroupEventMembershipApproval, Value: "true"},
+		{Type: domainChatStorage.GroupEventInviteLink, Value: inviteLink},
+		{Type: domainChatStorage.GroupEventLink, TargetJID: linked.String(), Value: string(types.GroupLinkChangeTypeSub)},
+	}
+	if len(groupEvents) != len(want) {
+		t.Fatalf("groupEventsFromInfo() returned %d events, want %d: %+v", len(groupEvents), len(want), groupEvents)
+	}
+	for i, got := range groupEvents {
+		if got.Type != want[i].Type || got.TargetJID != want[i].TargetJID || got.Value != want[i].Value {
+			t.Errorf("event %d = %s/%s/%q, want %s/%s/%q", i, got.Type, got.TargetJID, got.Value, want[i].Type, want[i].TargetJID, want[i].Value)
+		}
+		if got.GroupJID != group.String() || got.ActorJID != admin.String() || !got.Timestamp.Equal(at) {
+			t.Errorf("event %d = %+v, want group %s, actor %s and timestamp %s", i, got, group, admin, at)
+		}
+	}
+
+	t.Run("Disabled disappearing messages", func(t *testing.T) {
+		groupEvents := groupEventsFromInfo(context.Background(), &events.GroupInfo{
+			JID:       group,
+			Ephemeral: &types.GroupEphemeral{IsEphemeral: false, DisappearingTimer: 86400},
+		})
+		if len(groupEvents) != 1 || groupEvents[0].Value != "0" || groupEvents[0].ActorJID != "" {
+			t.Fatalf("groupEventsFromInfo() = %+v, want one ephemeral event with value 0 and no actor", groupEvents)
+		}
+	})
+
+	t.Run("Settings payloads match the schema", func(t *testing.T) {
+		for _, groupEvent := range groupEvents {
+			if isGroupMembershipEvent(groupEvent.Type) {
+				continue
+			}
+			validateLifecycleEvent(t, lifecycleEvent{name: domainWebhook.EventGroupSettings, payload: groupSettingsPayload(groupEvent)})
+		}
+	})
+}
+
+func TestHandleGroupPicture(t *testing.T) {
+	repo := &fakeGroupEventRepo{}
+	author := types.NewJID("628111", types.DefaultUserServer)
+	group := types.NewJID("120363000000000001", types.GroupServer)
+
+	handleGroupPicture(context.Background(), &events.Picture{JID: author, Author: author, PictureID: "1"}, repo)
+	if len(repo.events) != 0 {
+		t.Fatalf("photo changes of contacts were stored: %+v", repo.events)
+	}
+
+	handleGroupPicture(context.Background(), &events.Picture{JID: group, Author: author, Remove: true, Timestamp: time.Unix(1736000000, 0)}, repo)
+	if len(repo.events) != 1 {
+		t.Fatalf("stored %d events, want 1", len(repo.events))
+	}
+	if got := repo.events[0]; got.Type != domainChatStorage.GroupEventPhoto || got.GroupJID != group.String() || got.ActorJID != author.String() || got.Value != "" {
+		t.Fatalf("stored %+v, want a removed photo of %s by %s", got, group, author)
+	}
+}
@@ -0,0 +1,153 @@
+package whatsapp
+
+import (
+	"context"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// lifecycleEvent is a typed webhook event about an earlier message
+type lifecycleEvent struct {
+	name    string
+	payload any
+}
+
+// handleMessageLifecycle sends the typed message.edited, message.revoked and message.reaction events, poll.vote
+// is sent by handlePoll. The generic message event is still sent for these messages as well.
+// It runs before the message is stored so edits and revokes can report the text that was stored before.
+func handleMessageLifecycle(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if !hasEventTargets(ctx) || evt.Info.Chat.Server == types.BroadcastServer {
+		return
+	}
+
+	event := buildLifecycleEvent(ctx, evt, chatStorageRepo)
+	if event == nil {
+		return
+	}
+
+	go forwardLifecycleEvent(ctx, evt, *event)
+}
+
+func buildLifecycleEvent(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) *lifecycleEvent {
+	chatID := evt.Info.Chat.ToNonAD().String()
+	senderID := phoneNumberJID(ctx, evt.Info.Sender).String()
+	at := evt.Info.Timestamp.Format(time.RFC3339)
+
+	if protocolMessage := evt.Message.GetProtocolMessage(); protocolMessage != nil {
+		messageID := protocolMessage.GetKey().GetID()
+		original := storedMessage(chatStorageRepo, messageID)
+
+		switch protocolMessage.GetType() {
+		case waE2E.ProtocolMessage_MESSAGE_EDIT:
+			payload := domainWebhook.MessageEditedPayload{
+				MessageID: messageID,
+				ChatID:    chatID,
+				SenderID:  senderID,
+				IsFromMe:  evt.Info.IsFromMe,
+				NewText:   utils.ExtractMessageTextFromProto(protocolMessage.GetEditedMessage()),
+				EditedAt:  at,
+			}
+			if original != nil {
+				payload.OldText = original.Content
+			}
+			return &lifecycleEvent{name: domainWebhook.EventMessageEdited, payload: payload}
+		case waE2E.ProtocolMessage_REVOKE:
+			payload := domainWebhook.MessageRevokedPayload{
+				MessageID: messageID,
+				ChatID:    chatID,
+				SenderID:  senderID,
+				RevokedBy: senderID,
+				IsFromMe:  protocolMessage.GetKey().GetFromMe(),
+				RevokedAt: at,
+			}
+			// Admins can revoke the messages of other group members, SenderID is the author of the message
+			if participant := protocolMessage.GetKey().GetParticipant(); participant != "" {
+				if jid, err := types.ParseJID(participant); err == nil {
+					payload.SenderID = phoneNumberJID(ctx, jid).String()
+				}
+			}
+			if original != nil {
+				if sender, err := types.ParseJID(original.Sender); err == nil && !sender.IsEmpty() {
+					payload.SenderID = phoneNumberJID(ctx, sender).String()
+				}
+				payload.OriginalText = original.Content
+				payload.OriginalMediaType = original.MediaType
+			}
+			return &lifecycleEvent{name: domainWebhook.EventMessageRevoked, payload: payload}
+		}
+		return nil
+	}
+
+	if reaction := evt.Message.GetReactionMessage(); reaction != nil {
+		payload := domainWebhook.MessageReactionPayload{
+			MessageID: reaction.GetKey().GetID(),
+			ChatID:    chatID,
+			SenderID:  senderID,
+			IsFromMe:  evt.Info.IsFromMe,
+			Reaction:  reaction.GetText(),
+			Action:    domainWebhook.ReactionAdded,
+			ReactedAt: at,
+		}
+		if payload.Reaction == "" {
+			payload.Action = domainWebhook.ReactionRemoved
+		}
+		return &lifecycleEvent{name: domainWebhook.EventMessageReaction, payload: payload}
+	}
+
+	return nil
+}
+
+// forwardLifecycleEvent sends a typed event about the message of evt to the configured webhooks
+func forwardLifecycleEvent(ctx context.Context, evt *events.Message, event lifecycleEvent) {
+	body := map[string]any{
+		"event":     event.name,
+		"timestamp": time.Now().Format(time.RFC3339),
+		"payload":   event.payload,
+	}
+
+	ctx = withWebhookEvent(ctx, webhookEvent{
+		Name:      event.name,
+		ChatJID:   evt.Info.Chat,
+		SenderJID: evt.Info.Sender,
+		IsFromMe:  evt.Info.IsFromMe,
+	})
+	if err := forwardPayloadToConfiguredWebhooks(ctx, body, event.name+" event"); err != nil {
+		logrus.Errorf("[WEBHOOK] Failed to forward %s event: %v", event.name, err)
+	}
+}
+
+// forwardMediaDownloaded announces media that was saved by the auto download
+func forwardMediaDownloaded(ctx context.Context, evt *events.Message, mediaType string, media utils.ExtractedMedia) {
+	go forwardLifecycleEvent(ctx, evt, lifecycleEvent{
+		name: domainWebhook.EventMessageMediaDownloaded,
+		payload: domainWebhook.MediaDownloadedPayload{
+			MessageID: evt.Info.ID,
+			ChatID:    evt.Info.Chat.ToNonAD().String(),
+			SenderID:  phoneNumberJID(ctx, evt.Info.Sender).String(),
+			IsFromMe:  evt.Info.IsFromMe,
+			MediaType: mediaType,
+			Path:      media.MediaPath,
+			MimeType:  media.MimeType,
+			Caption:   media.Caption,
+		},
+	})
+}
+
+func storedMessage(chatStorageRepo domainChatStorage.IChatStorageRepository, messageID string) *domainChatStorage.Message {
+	if chatStorageRepo == nil || messageID == "" {
+		return nil
+	}
+	message, err := chatStorageRepo.GetMessageByID(messageID)
+	if err != nil {
+		logrus.Warnf("[WEBHOOK] Failed to load message %s: %v", messageID, err)
+		return nil
+	}
+	return message
+}
@@ -0,0 +1,227 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+type fakeMessageRepo struct {
+	domainChatStorage.IChatStorageRepository
+	messages map[string]*domainChatStorage.Message
+}
+
+func (f *fakeMessageRepo) GetMessageByID(id string) (*domainChatStorage.Message, error) {
+	return f.messages[id], nil
+}
+
+// jsonSchema is the subset of JSON Schema used by docs/webhook-schemas
+type jsonSchema struct {
+	Type                 string                 `json:"type"`
+	Const                any                    `json:"const"`
+	Enum                 []any                  `json:"enum"`
+	Pattern              string                 `json:"pattern"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+}
+
+func (s *jsonSchema) validate(t *testing.T, path string, value any) {
+	t.Helper()
+	if s.Const != nil && value != s.Const {
+		t.Errorf("%s = %v, want %v", path, value, s.Const)
+	}
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, value) {
+		t.Errorf("%s = %v, want one of %v", path, value, s.Enum)
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			t.Errorf("%s is %T, want an object", path, value)
+			return
+		}
+		for _, key := range s.Required {
+			if _, ok := object[key]; !ok {
+				t.Errorf("%s.%s is required", path, key)
+			}
+		}
+		for key, field := range object {
+			property, ok := s.Properties[key]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					t.Errorf("%s.%s is not in the schema", path, key)
+				}
+				continue
+			}
+			property.validate(t, path+"."+key, field)
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			t.Errorf("%s is %T, want an array", path, value)
+			return
+		}
+		for _, item := range items {
+			s.Items.validate(t, path+"[]", item)
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			t.Errorf("%s is %T, want a string", path, value)
+			return
+		}
+		if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(text) {
+			t.Errorf("%s = %q does not match %s", path, text, s.Pattern)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			t.Errorf("%s is %T, want a boolean", path, value)
+		}
+	}
+}
+
+func TestLifecycleEventsMatchSchemas(t *testing.T) {
+	chat := types.NewJID("120363000000000000", types.GroupServer)
+	sender := types.NewJID("628111", types.DefaultUserServer)
+	author := types.NewJID("628222", types.DefaultUserServer)
+	info := types.MessageInfo{
+		MessageSource: types.MessageSource{Chat: chat, Sender: sender, IsGroup: true},
+		ID:            "EVT1",
+		Timestamp:     time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC),
+	}
+	repo := &fakeMessageRepo{messages: map[string]*domainChatStorage.Message{
+		"ORIGINAL": {ID: "ORIGINAL", ChatJID: chat.String(), Sender: author.String(), Content: "hello", MediaType: "image"},
+	}}
+	key := &waCommon.MessageKey{ID: proto.String("ORIGINAL"), RemoteJID: proto.String(chat.String())}
+
+	tests := []struct {
+		name    string
+		message *waE2E.Message
+		event   string
+		check   func(t *testing.T, payload any)
+	}{
+		{
+			name: "Edit reports the stored text",
+			message: &waE2E.Message{ProtocolMessage: &waE2E.ProtocolMessage{
+				Type:          waE2E.ProtocolMessage_MESSAGE_EDIT.Enum(),
+				Key:           key,
+				EditedMessage: &waE2E.Message{Conversation: proto.String("hello there")},
+			}},
+			event: domainWebhook.EventMessageEdited,
+			check: func(t *testing.T, payload any) {
+				edited := payload.(domainWebhook.MessageEditedPayload)
+				if edited.OldText != "hello" || edited.NewText != "hello there" {
+					t.Fatalf("edit = %q -> %q, want hello -> hello there", edited.OldText, edited.NewText)
+				}
+			},
+		},
+		{
+			name: "Revoke by an admin reports the author",
+			message: &waE2E.Message{ProtocolMessage: &waE2E.ProtocolMessage{
+				Type: waE2E.ProtocolMessage_REVOKE.Enum(),
+				Key:  key,
+			}},
+			event: domainWebhook.EventMessageRevoked,
+			check: func(t *testing.T, payload any) {
+				revoked := payload.(domainWebhook.MessageRevokedPayload)
+				if revoked.SenderID != author.String() || revoked.RevokedBy != sender.String() {
+					t.Fatalf("revoked = %+v, want author %s revoked by %s", revoked, author, sender)
+				}
+			},
+		},
+		{
+			name:    "Removed reaction",
+			message: &waE2E.Message{ReactionMessage: &waE2E.ReactionMessage{Key: key, Text: proto.String("")}},
+			event:   domainWebhook.EventMessageReaction,
+			check: func(t *testing.T, payload any) {
+				if action := payload.(domainWebhook.MessageReactionPayload).Action; action != domainWebhook.ReactionRemoved {
+					t.Fatalf("action = %s, want removed", action)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := buildLifecycleEvent(context.Background(), &events.Message{Info: info, Message: tt.message}, repo)
+			if event == nil || event.name != tt.event {
+				t.Fatalf("event = %+v, want %s", event, tt.event)
+			}
+			tt.check(t, event.payload)
+			validateLifecycleEvent(t, *event)
+		})
+	}
+
+	t.Run("Other events", func(t *testing.T) {
+		validateLifecycleEvent(t, lifecycleEvent{name: domainWebhook.EventPollVote, payload: domainWebhook.PollVotePayload{
+			PollID:               "POLL1",
+			ChatID:               chat.String(),
+			VoterID:              sender.String(),
+			SelectedOptions:      []string{"hello"},
+			SelectedOptionHashes: []string{"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+			VotedAt:              info.Timestamp.Format(time.RFC3339),
+		}})
+		validateLifecycleEvent(t, lifecycleEvent{name: domainWebhook.EventMessageMediaDownloaded, payload: domainWebhook.MediaDownloadedPayload{
+			MessageID: "EVT1",
+			ChatID:    chat.String(),
+			SenderID:  sender.String(),
+			MediaType: "image",
+			Path:      "statics/media/1736000000-image.jpg",
+			MimeType:  "image/jpeg",
+		}})
+	})
+}
+
+func TestBuildLifecycleEventIgnoresPlainMessages(t *testing.T) {
+	evt := &events.Message{
+		Info:    types.MessageInfo{MessageSource: types.MessageSource{Chat: types.NewJID("628111", types.DefaultUserServer)}},
+		Message: &waE2E.Message{Conversation: proto.String("hi")},
+	}
+	if event := buildLifecycleEvent(context.Background(), evt, nil); event != nil {
+		t.Fatalf("buildLifecycleEvent() = %+v, want nil", event)
+	}
+}
+
+// validateLifecycleEvent checks the webhook body of event against its schema in docs/webhook-schemas
+func validateLifecycleEvent(t *testing.T, event lifecycleEvent) {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("..", "..", "..", "docs", "webhook-schemas", event.name+".schema.json"))
+	if err != nil {
+		t.Fatalf("schema of %s: %v", event.name, err)
+	}
+	var schema jsonSchema
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("schema of %s: %v", event.name, err)
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"event":     event.name,
+		"device_id": DefaultDeviceID,
+		"timestamp": time.Now().Format(time.RFC3339),
+		"payload":   event.payload,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	schema.validate(t, "$", decoded)
+}
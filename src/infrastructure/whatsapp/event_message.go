@@ -10,6 +10,7 @@ import (
 	"go.mau.fi/whatsmeow/types"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
@@ -23,6 +24,12 @@ func forwardMessageToWebhook(ctx context.Context, evt *events.Message) error {
 		return err
 	}
 
+	ctx = withWebhookEvent(ctx, webhookEvent{
+		Name:      domainWebhook.EventMessage,
+		ChatJID:   evt.Info.Chat,
+		SenderJID: evt.Info.Sender,
+		IsFromMe:  evt.Info.IsFromMe,
+	})
 	return forwardPayloadToConfiguredWebhooks(ctx, payload, "message event")
 }
 
@@ -51,7 +58,7 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 			if err != nil {
 				logrus.Errorf("Error when parse jid: %v", err)
 			} else {
-				pn, err := cli.Store.LIDs.GetPNForLID(ctx, lid)
+				pn, err := ClientFromContext(ctx).Store.LIDs.GetPNForLID(ctx, lid)
 				if err != nil {
 					logrus.Errorf("Error when get pn for lid %s: %v", lid.String(), err)
 				}
@@ -76,7 +83,7 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 			if err != nil {
 				logrus.Errorf("Error when parse jid: %v", err)
 			} else {
-				pn, err := cli.Store.LIDs.GetPNForLID(ctx, lid)
+				pn, err := ClientFromContext(ctx).Store.LIDs.GetPNForLID(ctx, lid)
 				if err != nil {
 					logrus.Errorf("Error when get pn for lid %s: %v", lid.String(), err)
 				}
@@ -133,14 +140,19 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 		}
 	}
 
+	// Without a webhook or a connected subscriber the event is only kept for replay, so it links the media
+	// instead of downloading it
+	downloadMedia := config.WhatsappAutoDownloadMedia && hasLiveEventTargets(ctx)
+
 	if audioMedia := evt.Message.GetAudioMessage(); audioMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
-			path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, audioMedia)
+		if downloadMedia {
+			path, err := utils.ExtractMedia(ctx, ClientFromContext(ctx), config.PathMedia, audioMedia)
 			if err != nil {
 				logrus.Errorf("Failed to download audio from %s: %v", evt.Info.SourceString(), err)
 				return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download audio: %v", err))
 			}
 			body["audio"] = path
+			forwardMediaDownloaded(ctx, evt, "audio", path)
 		} else {
 			body["audio"] = map[string]any{
 				"url": audioMedia.GetURL(),
@@ -153,13 +165,14 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if documentMedia := evt.Message.GetDocumentMessage(); documentMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
-			path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, documentMedia)
+		if downloadMedia {
+			path, err := utils.ExtractMedia(ctx, ClientFromContext(ctx), config.PathMedia, documentMedia)
 			if err != nil {
 				logrus.Errorf("Failed to download document from %s: %v", evt.Info.SourceString(), err)
 				return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download document: %v", err))
 			}
 			body["document"] = path
+			forwardMediaDownloaded(ctx, evt, "document", path)
 		} else {
 			body["document"] = map[string]any{
 				"url":      documentMedia.GetURL(),
@@ -169,13 +182,14 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if imageMedia := evt.Message.GetImageMessage(); imageMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
-			path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, imageMedia)
+		if downloadMedia {
+			path, err := utils.ExtractMedia(ctx, ClientFromContext(ctx), config.PathMedia, imageMedia)
 			if err != nil {
 				logrus.Errorf("Failed to download image from %s: %v", evt.Info.SourceString(), err)
 				return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download image: %v", err))
 			}
 			body["image"] = path
+			forwardMediaDownloaded(ctx, evt, "image", path)
 		} else {
 			body["image"] = map[string]any{
 				"url":     imageMedia.GetURL(),
@@ -201,13 +215,14 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if stickerMedia := evt.Message.GetStickerMessage(); stickerMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
-			path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, stickerMedia)
+		if downloadMedia {
+			path, err := utils.ExtractMedia(ctx, ClientFromContext(ctx), config.PathMedia, stickerMedia)
 			if err != nil {
 				logrus.Errorf("Failed to download sticker from %s: %v", evt.Info.SourceString(), err)
 				return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download sticker: %v", err))
 			}
 			body["sticker"] = path
+			forwardMediaDownloaded(ctx, evt, "sticker", path)
 		} else {
 			body["sticker"] = map[string]any{
 				"url": stickerMedia.GetURL(),
@@ -216,13 +231,14 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if videoMedia := evt.Message.GetVideoMessage(); videoMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
-			path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, videoMedia)
+		if downloadMedia {
+			path, err := utils.ExtractMedia(ctx, ClientFromContext(ctx), config.PathMedia, videoMedia)
 			if err != nil {
 				logrus.Errorf("Failed to download video from %s: %v", evt.Info.SourceString(), err)
 				return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download video: %v", err))
 			}
 			body["video"] = path
+			forwardMediaDownloaded(ctx, evt, "video", path)
 		} else {
 			body["video"] = map[string]any{
 				"url":     videoMedia.GetURL(),
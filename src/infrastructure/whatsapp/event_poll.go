@@ -0,0 +1,183 @@
+package whatsapp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"go.mau.fi/whatsmeow/util/gcmutil"
+	"go.mau.fi/whatsmeow/util/hkdfutil"
+	"google.golang.org/protobuf/proto"
+)
+
+// NewPoll returns the poll of a poll creation message, secret is the message secret its votes are encrypted with
+func NewPoll(messageID string, chat types.JID, creator types.JID, creation *waE2E.PollCreationMessage, secret []byte, createdAt time.Time) *domainChatStorage.Poll {
+	poll := &domainChatStorage.Poll{
+		MessageID:       messageID,
+		ChatJID:         chat.ToNonAD().String(),
+		CreatorJID:      creator.ToNonAD().String(),
+		Question:        creation.GetName(),
+		SelectableCount: int(creation.GetSelectableOptionsCount()),
+		Secret:          secret,
+		CreatedAt:       createdAt,
+	}
+	for _, option := range creation.GetOptions() {
+		poll.Options = append(poll.Options, option.GetOptionName())
+	}
+	return poll
+}
+
+// handlePoll stores the polls that are received and the votes cast on them, and sends votes as poll.vote events
+func handlePoll(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if creation := pollCreation(evt.Message); creation != nil {
+		poll := NewPoll(evt.Info.ID, evt.Info.Chat, evt.Info.Sender, creation, evt.Message.GetMessageContextInfo().GetMessageSecret(), evt.Info.Timestamp)
+		if err := chatStorageRepo.StorePoll(poll); err != nil {
+			logrus.Errorf("[POLL] Failed to store poll %s: %v", evt.Info.ID, err)
+		}
+		return
+	}
+
+	pollUpdate := evt.Message.GetPollUpdateMessage()
+	if pollUpdate == nil {
+		return
+	}
+
+	pollID := pollUpdate.GetPollCreationMessageKey().GetID()
+	poll, err := chatStorageRepo.GetPoll(pollID)
+	if err != nil {
+		logrus.Errorf("[POLL] Failed to load poll %s: %v", pollID, err)
+		return
+	}
+
+	vote, err := decryptPollVote(ctx, evt, poll)
+	if err != nil {
+		logrus.Warnf("[POLL] Failed to decrypt vote %s on poll %s: %v", evt.Info.ID, pollID, err)
+		return
+	}
+
+	voter := phoneNumberJID(ctx, evt.Info.Sender)
+	record := &domainChatStorage.PollVote{
+		PollID:          pollID,
+		VoterJID:        voter.String(),
+		SelectedOptions: pollOptionNames(poll, vote.GetSelectedOptions()),
+		VotedAt:         evt.Info.Timestamp,
+	}
+	if err := chatStorageRepo.StorePollVote(record); err != nil {
+		logrus.Errorf("[POLL] Failed to store vote of %s on poll %s: %v", record.VoterJID, pollID, err)
+	}
+
+	if !hasEventTargets(ctx) {
+		return
+	}
+
+	payload := domainWebhook.PollVotePayload{
+		PollID:               pollID,
+		ChatID:               evt.Info.Chat.ToNonAD().String(),
+		VoterID:              voter.String(),
+		IsFromMe:             evt.Info.IsFromMe,
+		SelectedOptions:      record.SelectedOptions,
+		SelectedOptionHashes: make([]string, 0, len(vote.GetSelectedOptions())),
+		VotedAt:              evt.Info.Timestamp.Format(time.RFC3339),
+	}
+	for _, hash := range vote.GetSelectedOptions() {
+		payload.SelectedOptionHashes = append(payload.SelectedOptionHashes, hex.EncodeToString(hash))
+	}
+	go forwardLifecycleEvent(ctx, evt, lifecycleEvent{name: domainWebhook.EventPollVote, payload: payload})
+}
+
+// pollCreation returns the poll of a message, whichever version of the poll message it was sent as
+func pollCreation(message *waE2E.Message) *waE2E.PollCreationMessage {
+	for _, creation := range []*waE2E.PollCreationMessage{
+		message.GetPollCreationMessage(),
+		message.GetPollCreationMessageV2(),
+		message.GetPollCreationMessageV3(),
+		message.GetPollCreationMessageV5(),
+	} {
+		if creation != nil {
+			return creation
+		}
+	}
+	return nil
+}
+
+// decryptPollVote decrypts a vote with the message secret whatsmeow keeps, falling back to the secret
+// stored with the poll when whatsmeow does not know it
+func decryptPollVote(ctx context.Context, evt *events.Message, poll *domainChatStorage.Poll) (*waE2E.PollVoteMessage, error) {
+	var clientErr error
+	if client := ClientFromContext(ctx); client != nil {
+		vote, err := client.DecryptPollVote(ctx, evt)
+		if err == nil {
+			return vote, nil
+		}
+		clientErr = err
+	}
+
+	if poll == nil || len(poll.Secret) == 0 {
+		if clientErr != nil {
+			return nil, clientErr
+		}
+		return nil, whatsmeow.ErrOriginalMessageSecretNotFound
+	}
+
+	creator, err := types.ParseJID(poll.CreatorJID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid creator of poll %s: %w", poll.MessageID, err)
+	}
+	return decryptPollVoteWithSecret(evt.Message.GetPollUpdateMessage(), poll.MessageID, creator, evt.Info.Sender, poll.Secret)
+}
+
+// decryptPollVoteWithSecret derives the vote key from the poll secret the same way WhatsApp does
+func decryptPollVoteWithSecret(pollUpdate *waE2E.PollUpdateMessage, pollID string, creator types.JID, voter types.JID, secret []byte) (*waE2E.PollVoteMessage, error) {
+	creatorStr := creator.ToNonAD().String()
+	voterStr := voter.ToNonAD().String()
+
+	useCaseSecret := make([]byte, 0, len(pollID)+len(creatorStr)+len(voterStr)+len(whatsmeow.EncSecretPollVote))
+	useCaseSecret = append(useCaseSecret, pollID...)
+	useCaseSecret = append(useCaseSecret, creatorStr...)
+	useCaseSecret = append(useCaseSecret, voterStr...)
+	useCaseSecret = append(useCaseSecret, whatsmeow.EncSecretPollVote...)
+
+	key := hkdfutil.SHA256(secret, nil, useCaseSecret, 32)
+	additionalData := fmt.Appendf(nil, "%s\x00%s", pollID, voterStr)
+	plaintext, err := gcmutil.Decrypt(key, pollUpdate.GetVote().GetEncIV(), pollUpdate.GetVote().GetEncPayload(), additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt poll vote: %w", err)
+	}
+
+	var vote waE2E.PollVoteMessage
+	if err := proto.Unmarshal(plaintext, &vote); err != nil {
+		return nil, fmt.Errorf("failed to decode poll vote: %w", err)
+	}
+	return &vote, nil
+}
+
+// pollOptionNames maps the hashes of a vote to the option names of the poll, unknown hashes are kept as hex
+func pollOptionNames(poll *domainChatStorage.Poll, hashes [][]byte) []string {
+	names := make(map[string]string)
+	if poll != nil {
+		for _, option := range poll.Options {
+			hash := sha256.Sum256([]byte(option))
+			names[hex.EncodeToString(hash[:])] = option
+		}
+	}
+
+	selected := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		encoded := hex.EncodeToString(hash)
+		if name, ok := names[encoded]; ok {
+			selected = append(selected, name)
+		} else {
+			selected = append(selected, encoded)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,128 @@
+package whatsapp
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"go.mau.fi/whatsmeow/util/gcmutil"
+	"go.mau.fi/whatsmeow/util/hkdfutil"
+	"google.golang.org/protobuf/proto"
+)
+
+type fakePollRepo struct {
+	domainChatStorage.IChatStorageRepository
+	polls map[string]*domainChatStorage.Poll
+	votes map[string]*domainChatStorage.PollVote
+}
+
+func (f *fakePollRepo) StorePoll(poll *domainChatStorage.Poll) error {
+	f.polls[poll.MessageID] = poll
+	return nil
+}
+
+func (f *fakePollRepo) GetPoll(messageID string) (*domainChatStorage.Poll, error) {
+	return f.polls[messageID], nil
+}
+
+func (f *fakePollRepo) StorePollVote(vote *domainChatStorage.PollVote) error {
+	f.votes[vote.VoterJID] = vote
+	return nil
+}
+
+// encryptPollVote encrypts a vote the way the WhatsApp clients of voters do
+func encryptPollVote(t *testing.T, secret []byte, pollID string, creator, voter types.JID, options ...string) *waE2E.PollEncValue {
+	t.Helper()
+	plaintext, err := proto.Marshal(&waE2E.PollVoteMessage{SelectedOptions: hashOptions(options...)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := []byte(pollID + creator.String() + voter.String() + "Poll Vote")
+	key := hkdfutil.SHA256(secret, nil, info, 32)
+	iv := []byte("0123456789ab")
+	payload, err := gcmutil.Encrypt(key, iv, plaintext, fmt.Appendf(nil, "%s\x00%s", pollID, voter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &waE2E.PollEncValue{EncPayload: payload, EncIV: iv}
+}
+
+func hashOptions(options ...string) [][]byte {
+	hashes := make([][]byte, 0, len(options))
+	for _, option := range options {
+		hash := sha256.Sum256([]byte(option))
+		hashes = append(hashes, hash[:])
+	}
+	return hashes
+}
+
+func TestHandlePoll(t *testing.T) {
+	chat := types.NewJID("120363000000000000", types.GroupServer)
+	creator := types.NewJID("628111", types.DefaultUserServer)
+	voter := types.NewJID("628222", types.DefaultUserServer)
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	createdAt := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	repo := &fakePollRepo{polls: map[string]*domainChatStorage.Poll{}, votes: map[string]*domainChatStorage.PollVote{}}
+
+	handlePoll(context.Background(), &events.Message{
+		Info: types.MessageInfo{MessageSource: types.MessageSource{Chat: chat, Sender: creator, IsGroup: true}, ID: "POLL1", Timestamp: createdAt},
+		Message: &waE2E.Message{
+			PollCreationMessageV3: &waE2E.PollCreationMessage{
+				Name:                   proto.String("Lunch?"),
+				Options:                []*waE2E.PollCreationMessage_Option{{OptionName: proto.String("Pizza")}, {OptionName: proto.String("Sushi")}},
+				SelectableOptionsCount: proto.Uint32(1),
+			},
+			MessageContextInfo: &waE2E.MessageContextInfo{MessageSecret: secret},
+		},
+	}, repo)
+
+	poll := repo.polls["POLL1"]
+	if poll == nil {
+		t.Fatal("poll was not stored")
+	}
+	if poll.Question != "Lunch?" || len(poll.Options) != 2 || poll.CreatorJID != creator.String() || string(poll.Secret) != string(secret) {
+		t.Fatalf("poll = %+v", poll)
+	}
+
+	vote := func(options ...string) *events.Message {
+		return &events.Message{
+			Info: types.MessageInfo{MessageSource: types.MessageSource{Chat: chat, Sender: voter, IsGroup: true}, ID: "VOTE1", Timestamp: createdAt.Add(time.Minute)},
+			Message: &waE2E.Message{PollUpdateMessage: &waE2E.PollUpdateMessage{
+				PollCreationMessageKey: &waCommon.MessageKey{ID: proto.String("POLL1"), RemoteJID: proto.String(chat.String()), Participant: proto.String(creator.String())},
+				Vote:                   encryptPollVote(t, secret, "POLL1", creator, voter, options...),
+			}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		options []string
+		want    []string
+	}{
+		{name: "Vote for a poll option", options: []string{"Sushi"}, want: []string{"Sushi"}},
+		{name: "Unknown option is kept as its hash", options: []string{"Ramen"}, want: []string{fmt.Sprintf("%x", sha256.Sum256([]byte("Ramen")))}},
+		{name: "Vote taken back", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlePoll(context.Background(), vote(tt.options...), repo)
+
+			stored := repo.votes[voter.String()]
+			if stored == nil {
+				t.Fatal("vote was not stored")
+			}
+			if fmt.Sprint(stored.SelectedOptions) != fmt.Sprint(tt.want) || len(stored.SelectedOptions) != len(tt.want) {
+				t.Fatalf("selected options = %v, want %v", stored.SelectedOptions, tt.want)
+			}
+		})
+	}
+}
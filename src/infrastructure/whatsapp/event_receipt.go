@@ -4,6 +4,9 @@ import (
 	"context"
 	"time"
 
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
@@ -69,5 +72,81 @@ func createReceiptPayload(evt *events.Receipt) map[string]any {
 // forwardReceiptToWebhook forwards message acknowledgement events to the configured webhook URLs
 func forwardReceiptToWebhook(ctx context.Context, evt *events.Receipt) error {
 	payload := createReceiptPayload(evt)
+	ctx = withWebhookEvent(ctx, webhookEvent{
+		Name:      domainWebhook.EventMessageAck,
+		ChatJID:   evt.Chat,
+		SenderJID: evt.Sender,
+		IsFromMe:  evt.IsFromMe,
+	})
 	return forwardPayloadToConfiguredWebhooks(ctx, payload, "message ack event")
 }
+
+// receiptMessageStatus returns the message state a receipt from a recipient reports, or an empty string
+// for receipts that do not advance an outgoing message
+func receiptMessageStatus(evt *events.Receipt) string {
+	// Receipts sent by our own devices are about messages we received
+	if evt.IsFromMe {
+		return ""
+	}
+
+	switch evt.Type {
+	case types.ReceiptTypeDelivered:
+		return domainChatStorage.MessageStatusDelivered
+	case types.ReceiptTypeRead:
+		return domainChatStorage.MessageStatusRead
+	case types.ReceiptTypePlayed:
+		return domainChatStorage.MessageStatusPlayed
+	}
+	return ""
+}
+
+// recordMessageReceipt stores the state the messages of a receipt reached for the recipient who sent it
+func recordMessageReceipt(evt *events.Receipt, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	status := receiptMessageStatus(evt)
+	if status == "" || chatStorageRepo == nil {
+		return
+	}
+
+	participant := evt.Sender.ToNonAD().String()
+	receipts := make([]*domainChatStorage.MessageReceipt, 0, len(evt.MessageIDs))
+	for _, id := range evt.MessageIDs {
+		receipts = append(receipts, &domainChatStorage.MessageReceipt{
+			MessageID:      string(id),
+			ChatJID:        evt.Chat.ToNonAD().String(),
+			ParticipantJID: participant,
+			Status:         status,
+			Timestamp:      evt.Timestamp,
+		})
+	}
+
+	if err := chatStorageRepo.StoreMessageReceipts(receipts); err != nil {
+		logrus.Errorf("Failed to store %s receipt of %v: %v", status, evt.MessageIDs, err)
+	}
+}
+
+// recordCampaignReceipt moves campaign recipients to delivered or read when their message receipt arrives
+func recordCampaignReceipt(evt *events.Receipt) {
+	runner := GetCampaignRunner()
+	if runner == nil || len(evt.MessageIDs) == 0 {
+		return
+	}
+
+	var status string
+	switch evt.Type {
+	case types.ReceiptTypeDelivered:
+		status = domainChatStorage.CampaignRecipientDelivered
+	case types.ReceiptTypeRead:
+		status = domainChatStorage.CampaignRecipientRead
+	default:
+		return
+	}
+
+	messageIDs := make([]string, 0, len(evt.MessageIDs))
+	for _, id := range evt.MessageIDs {
+		messageIDs = append(messageIDs, string(id))
+	}
+
+	if _, err := runner.repo.UpdateCampaignRecipientReceipt(messageIDs, status, evt.Timestamp); err != nil {
+		logrus.Errorf("[CAMPAIGN] Failed to record %s receipt: %v", status, err)
+	}
+}
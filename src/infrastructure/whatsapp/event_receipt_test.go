@@ -0,0 +1,83 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+type fakeReceiptRepo struct {
+	domainChatStorage.IChatStorageRepository
+	receipts []*domainChatStorage.MessageReceipt
+}
+
+func (f *fakeReceiptRepo) StoreMessageReceipts(receipts []*domainChatStorage.MessageReceipt) error {
+	f.receipts = append(f.receipts, receipts...)
+	return nil
+}
+
+func TestRecordMessageReceipt(t *testing.T) {
+	group := types.NewJID("120363025246125486", types.GroupServer)
+	participant := types.JID{User: "628111", Server: types.DefaultUserServer, Device: 3}
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		receipt    *events.Receipt
+		wantStatus string
+	}{
+		{
+			name:       "Delivered to a group participant",
+			receipt:    &events.Receipt{MessageSource: types.MessageSource{Chat: group, Sender: participant, IsGroup: true}, Type: types.ReceiptTypeDelivered},
+			wantStatus: domainChatStorage.MessageStatusDelivered,
+		},
+		{
+			name:       "Read",
+			receipt:    &events.Receipt{MessageSource: types.MessageSource{Chat: group, Sender: participant}, Type: types.ReceiptTypeRead},
+			wantStatus: domainChatStorage.MessageStatusRead,
+		},
+		{
+			name:       "Played",
+			receipt:    &events.Receipt{MessageSource: types.MessageSource{Chat: group, Sender: participant}, Type: types.ReceiptTypePlayed},
+			wantStatus: domainChatStorage.MessageStatusPlayed,
+		},
+		{
+			name:    "Read on our other device",
+			receipt: &events.Receipt{MessageSource: types.MessageSource{Chat: group, Sender: participant, IsFromMe: true}, Type: types.ReceiptTypeReadSelf},
+		},
+		{
+			name:    "Retry",
+			receipt: &events.Receipt{MessageSource: types.MessageSource{Chat: group, Sender: participant}, Type: types.ReceiptTypeRetry},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReceiptRepo{}
+			tt.receipt.MessageIDs = []types.MessageID{"MSG-1", "MSG-2"}
+			tt.receipt.Timestamp = at
+
+			recordMessageReceipt(tt.receipt, repo)
+
+			if tt.wantStatus == "" {
+				if len(repo.receipts) != 0 {
+					t.Fatalf("recordMessageReceipt() stored %d receipts, want none", len(repo.receipts))
+				}
+				return
+			}
+			if len(repo.receipts) != 2 {
+				t.Fatalf("recordMessageReceipt() stored %d receipts, want 2", len(repo.receipts))
+			}
+			receipt := repo.receipts[1]
+			if receipt.MessageID != "MSG-2" || receipt.Status != tt.wantStatus || !receipt.Timestamp.Equal(at) {
+				t.Fatalf("recordMessageReceipt() stored %+v, want MSG-2 %s at %s", receipt, tt.wantStatus, at)
+			}
+			if receipt.ParticipantJID != "628111@s.whatsapp.net" || receipt.ChatJID != group.String() {
+				t.Fatalf("recordMessageReceipt() stored participant %s in %s", receipt.ParticipantJID, receipt.ChatJID)
+			}
+		})
+	}
+}
@@ -15,18 +15,17 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/appstate"
-	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/store"
 	"go.mau.fi/whatsmeow/store/sqlstore"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 	waLog "go.mau.fi/whatsmeow/util/log"
-	"google.golang.org/protobuf/proto"
 )
 
 // Type definitions
@@ -76,33 +75,91 @@ func syncKeysDevice(ctx context.Context, db, keysDB *sqlstore.Container) {
 		return
 	}
 
-	dev, err := db.GetFirstDevice(ctx)
-	if err != nil {
+	client := ClientFromContext(ctx)
+	if client == nil || client.Store == nil || client.Store.ID == nil {
+		return
+	}
+
+	dev, err := db.GetDevice(ctx, *client.Store.ID)
+	if err != nil || dev == nil {
+		log.Errorf("Failed to get paired device: %v", err)
+		return
+	}
+
+	// Stale entries are only pruned when a single device is managed; otherwise
+	// they belong to the other registered devices.
+	pruneOthers := deviceManager == nil || deviceManager.Count() <= 1
+
+	found := false
+	if devs, err := keysDB.GetAllDevices(ctx); err != nil {
 		log.Errorf("Failed to get all devices: %v", err)
 	} else {
-		found := false
-		if devs, err := keysDB.GetAllDevices(ctx); err != nil {
-			log.Errorf("Failed to get all devices: %v", err)
-		} else {
-			for _, d := range devs {
-				if d.ID == dev.ID {
-					found = true
-					break
-				} else {
-					keysDB.DeleteDevice(ctx, d)
-				}
+		for _, d := range devs {
+			if *d.ID == *dev.ID {
+				found = true
+			} else if pruneOthers {
+				keysDB.DeleteDevice(ctx, d)
 			}
+		}
+
+		if !found {
+			keysDB.PutDevice(ctx, dev)
+		}
+	}
+}
+
+// defaultStoreDevice returns the whatsmeow device of the default device. Sessions claimed by
+// other registered devices are skipped, so a store shared by several devices resolves correctly.
+func defaultStoreDevice(ctx context.Context, storeContainer *sqlstore.Container, chatStorageRepo domainChatStorage.IChatStorageRepository) (*store.Device, error) {
+	if chatStorageRepo == nil {
+		return storeContainer.GetFirstDevice(ctx)
+	}
 
-			if !found {
-				keysDB.PutDevice(ctx, dev)
+	records, err := chatStorageRepo.GetDeviceRecords()
+	if err != nil || len(records) == 0 {
+		return storeContainer.GetFirstDevice(ctx)
+	}
+
+	claimed := make(map[string]bool)
+	for _, record := range records {
+		if record.ID == DefaultDeviceID {
+			if record.JID == "" {
+				return storeContainer.NewDevice(), nil
+			}
+			jid, err := types.ParseJID(record.JID)
+			if err != nil {
+				return nil, err
+			}
+			device, err := storeContainer.GetDevice(ctx, jid)
+			if err != nil {
+				return nil, err
+			}
+			if device == nil {
+				// The registered session is gone, start with an unpaired device
+				return storeContainer.NewDevice(), nil
 			}
+			return device, nil
+		}
+		if record.JID != "" {
+			claimed[record.JID] = true
+		}
+	}
+
+	devices, err := storeContainer.GetAllDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, device := range devices {
+		if device.ID != nil && !claimed[device.ID.String()] {
+			return device, nil
 		}
 	}
+	return storeContainer.NewDevice(), nil
 }
 
 // InitWaCLI initializes the WhatsApp client
 func InitWaCLI(ctx context.Context, storeContainer, keysStoreContainer *sqlstore.Container, chatStorageRepo domainChatStorage.IChatStorageRepository) *whatsmeow.Client {
-	device, err := storeContainer.GetFirstDevice(ctx)
+	device, err := defaultStoreDevice(ctx, storeContainer, chatStorageRepo)
 	if err != nil {
 		log.Errorf("Failed to get device: %v", err)
 		panic(err)
@@ -144,6 +201,8 @@ func InitWaCLI(ctx context.Context, storeContainer, keysStoreContainer *sqlstore
 		handler(ctx, rawEvt, chatStorageRepo)
 	})
 
+	metrics.RegisterConnectionStatus(connectionStatuses)
+
 	return cli
 }
 
@@ -166,22 +225,49 @@ func GetDB() *sqlstore.Container {
 	return db
 }
 
-// GetConnectionStatus returns the current connection status of the global client
-func GetConnectionStatus() (isConnected bool, isLoggedIn bool, deviceID string) {
-	if cli == nil {
+// GetConnectionStatus returns the current connection status of the device bound to ctx
+func GetConnectionStatus(ctx context.Context) (isConnected bool, isLoggedIn bool, deviceID string) {
+	client := ClientFromContext(ctx)
+	if client == nil {
 		return false, false, ""
 	}
 
-	isConnected = cli.IsConnected()
-	isLoggedIn = cli.IsLoggedIn()
+	isConnected = client.IsConnected()
+	isLoggedIn = client.IsLoggedIn()
 
-	if cli.Store != nil && cli.Store.ID != nil {
-		deviceID = cli.Store.ID.String()
+	if client.Store != nil && client.Store.ID != nil {
+		deviceID = client.Store.ID.String()
 	}
 
 	return isConnected, isLoggedIn, deviceID
 }
 
+// UpdateDeviceClient updates the client of the device bound to ctx
+func UpdateDeviceClient(ctx context.Context, newCli *whatsmeow.Client) {
+	device := DeviceFromContext(ctx)
+	if device == nil || device.ID() == DefaultDeviceID {
+		UpdateGlobalClient(newCli, db)
+		return
+	}
+	device.setClient(newCli)
+}
+
+// ResetManagedDevice resets only the session of the device bound to ctx when other devices share
+// the whatsmeow store, so a logout does not wipe the sessions of the remaining devices.
+// It reports false when the regular full cleanup should be performed instead.
+func ResetManagedDevice(ctx context.Context) (bool, error) {
+	device := DeviceFromContext(ctx)
+	if device == nil || deviceManager == nil {
+		return false, nil
+	}
+	if device.ID() == DefaultDeviceID && deviceManager.Count() == 1 {
+		return false, nil
+	}
+
+	logrus.Infof("[DEVICE] Resetting session of device %s", device.ID())
+	return true, deviceManager.ResetDevice(ctx, device)
+}
+
 // CleanupDatabase removes the database file (SQLite) or deletes all devices (PostgreSQL) to prevent foreign key constraint issues
 func CleanupDatabase() error {
 	// Check if using PostgreSQL
@@ -418,6 +504,15 @@ func PerformCleanupAndUpdateGlobals(ctx context.Context, logPrefix string, chatS
 // handleRemoteLogout performs cleanup when user logs out from their phone
 func handleRemoteLogout(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository) {
 	logrus.Info("[REMOTE_LOGOUT] User logged out from phone - starting cleanup...")
+
+	// Only reset the affected device when several devices share the store
+	if handled, err := ResetManagedDevice(ctx); handled {
+		if err != nil {
+			logrus.Errorf("[REMOTE_LOGOUT] Device cleanup failed: %v", err)
+		}
+		return
+	}
+
 	logrus.Info("[REMOTE_LOGOUT] This will clear all WhatsApp session data and chat storage")
 
 	// Log database state before cleanup
@@ -442,6 +537,8 @@ func handleRemoteLogout(ctx context.Context, chatStorageRepo domainChatStorage.I
 
 // handler is the main event handler for WhatsApp events
 func handler(ctx context.Context, rawEvt any, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	observeEvent(rawEvt)
+
 	switch evt := rawEvt.(type) {
 	case *events.DeleteForMe:
 		handleDeleteForMe(ctx, evt, chatStorageRepo)
@@ -458,15 +555,29 @@ func handler(ctx context.Context, rawEvt any, chatStorageRepo domainChatStorage.
 	case *events.Message:
 		handleMessage(ctx, evt, chatStorageRepo)
 	case *events.Receipt:
-		handleReceipt(ctx, evt)
+		handleReceipt(ctx, evt, chatStorageRepo)
 	case *events.Presence:
 		handlePresence(ctx, evt)
+	case *events.ChatPresence:
+		handleChatPresence(ctx, evt)
 	case *events.HistorySync:
 		handleHistorySync(ctx, evt, chatStorageRepo)
 	case *events.AppState:
 		handleAppState(ctx, evt)
 	case *events.GroupInfo:
-		handleGroupInfo(ctx, evt)
+		handleGroupInfo(ctx, evt, chatStorageRepo)
+	case *events.Picture:
+		handleGroupPicture(ctx, evt, chatStorageRepo)
+	case *events.CallOffer:
+		handleCallOffer(ctx, evt.BasicCallMeta, callOfferIsVideo(evt.Data), chatStorageRepo)
+	case *events.CallOfferNotice:
+		handleCallOffer(ctx, evt.BasicCallMeta, evt.Media == "video", chatStorageRepo)
+	case *events.CallAccept:
+		handleCallAccept(ctx, evt.BasicCallMeta, chatStorageRepo)
+	case *events.CallReject:
+		handleCallEnd(ctx, evt.BasicCallMeta, domainChatStorage.CallStatusRejected, "rejected", chatStorageRepo)
+	case *events.CallTerminate:
+		handleCallEnd(ctx, evt.BasicCallMeta, "", evt.Reason, chatStorageRepo)
 	}
 }
 
@@ -495,7 +606,7 @@ func handleDeleteForMe(ctx context.Context, evt *events.DeleteForMe, chatStorage
 	}
 
 	// Send webhook notification for delete event
-	if len(config.WhatsappWebhook) > 0 {
+	if hasEventTargets(ctx) {
 		go func() {
 			if err := forwardDeleteToWebhook(ctx, evt, message); err != nil {
 				log.Errorf("Failed to forward delete event to webhook: %v", err)
@@ -504,9 +615,10 @@ func handleDeleteForMe(ctx context.Context, evt *events.DeleteForMe, chatStorage
 	}
 }
 
-func handleAppStateSyncComplete(_ context.Context, evt *events.AppStateSyncComplete) {
-	if len(cli.Store.PushName) > 0 && evt.Name == appstate.WAPatchCriticalBlock {
-		if err := cli.SendPresence(context.Background(), types.PresenceAvailable); err != nil {
+func handleAppStateSyncComplete(ctx context.Context, evt *events.AppStateSyncComplete) {
+	client := ClientFromContext(ctx)
+	if len(client.Store.PushName) > 0 && evt.Name == appstate.WAPatchCriticalBlock {
+		if err := client.SendPresence(context.Background(), types.PresenceAvailable); err != nil {
 			log.Warnf("Failed to send available presence: %v", err)
 		} else {
 			log.Infof("Marked self as available")
@@ -515,11 +627,26 @@ func handleAppStateSyncComplete(_ context.Context, evt *events.AppStateSyncCompl
 }
 
 func handlePairSuccess(ctx context.Context, evt *events.PairSuccess) {
+	device := DeviceFromContext(ctx)
+
 	websocket.Broadcast <- websocket.BroadcastMessage{
 		Code:    "LOGIN_SUCCESS",
 		Message: fmt.Sprintf("Successfully pair with %s", evt.ID.String()),
+		Result:  deviceResult(device),
 	}
 	syncKeysDevice(ctx, db, keysDB)
+
+	if device != nil && deviceManager != nil {
+		deviceManager.OnPaired(device)
+	}
+}
+
+// deviceResult builds the websocket result identifying the device an event belongs to
+func deviceResult(device *DeviceInstance) map[string]any {
+	if device == nil {
+		return nil
+	}
+	return map[string]any{"device_id": device.ID()}
 }
 
 func handleLoggedOut(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository) {
@@ -532,25 +659,34 @@ func handleLoggedOut(ctx context.Context, chatStorageRepo domainChatStorage.ICha
 	websocket.Broadcast <- websocket.BroadcastMessage{
 		Code:    "LOGOUT_COMPLETE",
 		Message: "Remote logout cleanup completed - ready for new login",
-		Result:  nil,
+		Result:  deviceResult(DeviceFromContext(ctx)),
 	}
 }
 
-func handleConnectionEvents(_ context.Context) {
-	if len(cli.Store.PushName) == 0 {
+func handleConnectionEvents(ctx context.Context) {
+	client := ClientFromContext(ctx)
+	if len(client.Store.PushName) == 0 {
 		return
 	}
 
 	// Send presence available when connecting and when the pushname is changed.
 	// This makes sure that outgoing messages always have the right pushname.
-	if err := cli.SendPresence(context.Background(), types.PresenceAvailable); err != nil {
+	if err := client.SendPresence(context.Background(), types.PresenceAvailable); err != nil {
 		log.Warnf("Failed to send available presence: %v", err)
 	} else {
 		log.Infof("Marked self as available")
+		// Presence subscriptions only work while available and are dropped on disconnect
+		resubscribePresences(ctx)
 	}
 }
 
-func handleStreamReplaced(_ context.Context) {
+func handleStreamReplaced(ctx context.Context) {
+	// Additional devices only lose their own session; the process keeps serving the others
+	if device := DeviceFromContext(ctx); device != nil && device.ID() != DefaultDeviceID {
+		logrus.Warnf("[DEVICE] Stream of device %s was replaced by another client", device.ID())
+		device.GetClient().Disconnect()
+		return
+	}
 	os.Exit(0)
 }
 
@@ -564,19 +700,28 @@ func handleMessage(ctx context.Context, evt *events.Message, chatStorageRepo dom
 		evt.Message,
 	)
 
+	// Send the typed events about earlier messages while their stored version is unchanged
+	handleMessageLifecycle(ctx, evt, chatStorageRepo)
+
+	// Store polls and count their votes
+	handlePoll(ctx, evt, chatStorageRepo)
+
 	if err := chatStorageRepo.CreateMessage(ctx, evt); err != nil {
 		// Log storage errors to avoid silent failures that could lead to data loss
 		log.Errorf("Failed to store incoming message %s: %v", evt.Info.ID, err)
 	}
 
+	// Tell the listeners, such as the MCP resource subscriptions, that the chat changed
+	notifyMessageListeners(ctx, evt)
+
 	// Handle image message if present
 	handleImageMessage(ctx, evt)
 
 	// Auto-mark message as read if configured
 	handleAutoMarkRead(ctx, evt)
 
-	// Handle auto-reply if configured
-	handleAutoReply(ctx, evt, chatStorageRepo)
+	// Run the auto-reply rules
+	handleAutoReply(ctx, evt)
 
 	// Forward to webhook if configured
 	handleWebhookForward(ctx, evt)
@@ -604,7 +749,7 @@ func handleImageMessage(ctx context.Context, evt *events.Message) {
 		return
 	}
 	if img := evt.Message.GetImageMessage(); img != nil {
-		if path, err := utils.ExtractMedia(ctx, cli, config.PathStorages, img); err != nil {
+		if path, err := utils.ExtractMedia(ctx, ClientFromContext(ctx), config.PathStorages, img); err != nil {
 			log.Errorf("Failed to download image: %v", err)
 		} else {
 			log.Infof("Image downloaded to %s", path)
@@ -612,7 +757,7 @@ func handleImageMessage(ctx context.Context, evt *events.Message) {
 	}
 }
 
-func handleAutoMarkRead(_ context.Context, evt *events.Message) {
+func handleAutoMarkRead(ctx context.Context, evt *events.Message) {
 	// Only mark read if auto-mark read is enabled and message is incoming
 	if !config.WhatsappAutoMarkRead || evt.Info.IsFromMe {
 		return
@@ -624,119 +769,13 @@ func handleAutoMarkRead(_ context.Context, evt *events.Message) {
 	chat := evt.Info.Chat
 	sender := evt.Info.Sender
 
-	if err := cli.MarkRead(context.Background(), messageIDs, timestamp, chat, sender); err != nil {
+	if err := ClientFromContext(ctx).MarkRead(context.Background(), messageIDs, timestamp, chat, sender); err != nil {
 		log.Warnf("Failed to mark message %s as read: %v", evt.Info.ID, err)
 	} else {
 		log.Debugf("Marked message %s as read", evt.Info.ID)
 	}
 }
 
-func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
-	if config.WhatsappAutoReplyMessage == "" {
-		return
-	}
-
-	// Skip groups, broadcasts, and self messages
-	if utils.IsGroupJID(evt.Info.Chat.String()) || evt.Info.IsIncomingBroadcast() || evt.Info.IsFromMe {
-		return
-	}
-
-	// Only reply to direct 1:1 chats (e.g., *@s.whatsapp.net)
-	if evt.Info.Chat.Server != types.DefaultUserServer {
-		return
-	}
-
-	// Extra safety: skip any broadcast/status contexts
-	source := evt.Info.SourceString()
-	if strings.Contains(source, "broadcast") ||
-		strings.HasSuffix(evt.Info.Chat.String(), "@broadcast") ||
-		strings.HasPrefix(evt.Info.Chat.String(), "status@") {
-		return
-	}
-
-	// Require actual typed text (not captions or synthetic labels)
-	hasText := false
-
-	// Unwrap FutureProof wrappers to access the inner message content first
-	innerMsg := evt.Message
-	for i := 0; i < 3; i++ { // safeguard against excessively nested wrappers
-		if vm := innerMsg.GetViewOnceMessage(); vm != nil && vm.GetMessage() != nil {
-			innerMsg = vm.GetMessage()
-			continue
-		}
-		if em := innerMsg.GetEphemeralMessage(); em != nil && em.GetMessage() != nil {
-			innerMsg = em.GetMessage()
-			continue
-		}
-		if vm2 := innerMsg.GetViewOnceMessageV2(); vm2 != nil && vm2.GetMessage() != nil {
-			innerMsg = vm2.GetMessage()
-			continue
-		}
-		if vm2e := innerMsg.GetViewOnceMessageV2Extension(); vm2e != nil && vm2e.GetMessage() != nil {
-			innerMsg = vm2e.GetMessage()
-			continue
-		}
-		break
-	}
-
-	// Check for genuine typed text on the unwrapped content
-	if conv := innerMsg.GetConversation(); conv != "" {
-		hasText = true
-	} else if ext := innerMsg.GetExtendedTextMessage(); ext != nil && ext.GetText() != "" {
-		hasText = true
-	} else if protoMsg := innerMsg.GetProtocolMessage(); protoMsg != nil {
-		if edited := protoMsg.GetEditedMessage(); edited != nil {
-			if ext := edited.GetExtendedTextMessage(); ext != nil && ext.GetText() != "" {
-				hasText = true
-			} else if conv := edited.GetConversation(); conv != "" {
-				hasText = true
-			}
-		}
-	}
-	if !hasText {
-		return
-	}
-
-	// Format recipient JID
-	recipientJID := utils.FormatJID(evt.Info.Sender.String())
-
-	// Send the auto-reply message
-	response, err := cli.SendMessage(
-		ctx,
-		recipientJID,
-		&waE2E.Message{Conversation: proto.String(config.WhatsappAutoReplyMessage)},
-	)
-
-	if err != nil {
-		log.Errorf("Failed to send auto-reply message: %v", err)
-		return
-	}
-
-	// Store the auto-reply message in chat storage if send was successful
-	if chatStorageRepo != nil {
-		// Get our own JID as sender
-		senderJID := ""
-		if cli.Store.ID != nil {
-			senderJID = cli.Store.ID.String()
-		}
-
-		// Store the sent auto-reply message
-		if err := chatStorageRepo.StoreSentMessageWithContext(
-			ctx,
-			response.ID,                     // Message ID from WhatsApp response
-			senderJID,                       // Our JID as sender
-			recipientJID.String(),           // Recipient JID
-			config.WhatsappAutoReplyMessage, // Auto-reply content
-			response.Timestamp,              // Timestamp from response
-		); err != nil {
-			// Log storage error but don't fail the auto-reply
-			log.Errorf("Failed to store auto-reply message in chat storage: %v", err)
-		} else {
-			log.Debugf("Auto-reply message %s stored successfully in chat storage", response.ID)
-		}
-	}
-}
-
 func handleWebhookForward(ctx context.Context, evt *events.Message) {
 	// Skip webhook for specific protocol messages that shouldn't trigger webhooks
 	if protocolMessage := evt.Message.GetProtocolMessage(); protocolMessage != nil {
@@ -748,7 +787,7 @@ func handleWebhookForward(ctx context.Context, evt *events.Message) {
 		}
 	}
 
-	if len(config.WhatsappWebhook) > 0 &&
+	if hasEventTargets(ctx) &&
 		!strings.Contains(evt.Info.SourceString(), "broadcast") {
 		go func(evt *events.Message) {
 			if err := forwardMessageToWebhook(ctx, evt); err != nil {
@@ -758,7 +797,7 @@ func handleWebhookForward(ctx context.Context, evt *events.Message) {
 	}
 }
 
-func handleReceipt(ctx context.Context, evt *events.Receipt) {
+func handleReceipt(ctx context.Context, evt *events.Receipt, chatStorageRepo domainChatStorage.IChatStorageRepository) {
 	sendReceipt := false
 	switch evt.Type {
 	case types.ReceiptTypeRead, types.ReceiptTypeReadSelf:
@@ -769,9 +808,15 @@ func handleReceipt(ctx context.Context, evt *events.Receipt) {
 		log.Infof("%s was delivered to %s at %s: %+v", evt.MessageIDs[0], evt.SourceString(), evt.Timestamp, evt)
 	}
 
+	// Outgoing messages keep the state each recipient reached
+	recordMessageReceipt(evt, chatStorageRepo)
+
+	// Campaign recipients follow the delivery state of the messages sent to them
+	recordCampaignReceipt(evt)
+
 	// Forward receipt (ack) event to webhook if configured
 	// Note: Receipt events are not rate limited as they are critical for message delivery status
-	if len(config.WhatsappWebhook) > 0 && sendReceipt {
+	if hasEventTargets(ctx) && sendReceipt {
 		go func(e *events.Receipt) {
 			if err := forwardReceiptToWebhook(ctx, e); err != nil {
 				logrus.Errorf("Failed to forward ack event to webhook: %v", err)
@@ -780,24 +825,12 @@ func handleReceipt(ctx context.Context, evt *events.Receipt) {
 	}
 }
 
-func handlePresence(_ context.Context, evt *events.Presence) {
-	if evt.Unavailable {
-		if evt.LastSeen.IsZero() {
-			log.Infof("%s is now offline", evt.From)
-		} else {
-			log.Infof("%s is now offline (last seen: %s)", evt.From, evt.LastSeen)
-		}
-	} else {
-		log.Infof("%s is now online", evt.From)
-	}
-}
-
 func handleHistorySync(ctx context.Context, evt *events.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) {
 	id := atomic.AddInt32(&historySyncID, 1)
 	fileName := fmt.Sprintf("%s/history-%d-%s-%d-%s.json",
 		config.PathStorages,
 		startupTime,
-		cli.Store.ID.String(),
+		ClientFromContext(ctx).Store.ID.String(),
 		id,
 		evt.Data.SyncType.String(),
 	)
@@ -854,7 +887,8 @@ func processHistorySync(ctx context.Context, data *waHistorySync.HistorySync, ch
 }
 
 // processConversationMessages processes and stores conversation messages from history sync
-func processConversationMessages(_ context.Context, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
+func processConversationMessages(ctx context.Context, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
+	client := ClientFromContext(ctx)
 	conversations := data.GetConversations()
 	log.Infof("Processing %d conversations from history sync", len(conversations))
 
@@ -918,8 +952,8 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 			isFromMe := msgKey.GetFromMe()
 			if isFromMe {
 				// For self-messages, use the full JID format to match regular message processing
-				if cli.Store.ID != nil {
-					sender = cli.Store.ID.String() // Use full JID instead of just User part
+				if client.Store.ID != nil {
+					sender = client.Store.ID.String() // Use full JID instead of just User part
 				} else {
 					// Skip messages where we can't determine the sender to avoid NOT NULL violations
 					log.Warnf("Skipping self-message %s: client ID unavailable", messageID)
@@ -1036,7 +1070,10 @@ func processPushNames(_ context.Context, data *waHistorySync.HistorySync, chatSt
 	return nil
 }
 
-func handleGroupInfo(ctx context.Context, evt *events.GroupInfo) {
+func handleGroupInfo(ctx context.Context, evt *events.GroupInfo, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	// Every membership and settings change is kept in the group history
+	recordGroupEvents(ctx, evt, chatStorageRepo)
+
 	// Only process events that have actual changes
 	hasChanges := len(evt.Join) > 0 || len(evt.Leave) > 0 || len(evt.Promote) > 0 || len(evt.Demote) > 0 ||
 		evt.Name != nil || evt.Topic != nil || evt.Locked != nil || evt.Announce != nil
@@ -1060,7 +1097,7 @@ func handleGroupInfo(ctx context.Context, evt *events.GroupInfo) {
 	}
 
 	// Forward group info event to webhook if configured
-	if len(config.WhatsappWebhook) > 0 {
+	if hasEventTargets(ctx) {
 		go func(e *events.GroupInfo) {
 			if err := forwardGroupInfoToWebhook(ctx, e); err != nil {
 				logrus.Errorf("Failed to forward group info event to webhook: %v", err)
@@ -0,0 +1,33 @@
+package whatsapp
+
+import (
+	"context"
+	"sync"
+
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// MessageListener is told about every incoming message once it is stored
+type MessageListener func(ctx context.Context, evt *events.Message)
+
+var (
+	messageListenersMu sync.RWMutex
+	messageListeners   []MessageListener
+)
+
+// AddMessageListener registers listener for the incoming messages of every device
+func AddMessageListener(listener MessageListener) {
+	messageListenersMu.Lock()
+	defer messageListenersMu.Unlock()
+	messageListeners = append(messageListeners, listener)
+}
+
+func notifyMessageListeners(ctx context.Context, evt *events.Message) {
+	messageListenersMu.RLock()
+	listeners := messageListeners
+	messageListenersMu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(ctx, evt)
+	}
+}
@@ -0,0 +1,82 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// observeEvent counts a whatsmeow event before it is handled
+func observeEvent(rawEvt any) {
+	metrics.Events.WithLabelValues(eventName(rawEvt)).Inc()
+
+	switch evt := rawEvt.(type) {
+	case *events.Message:
+		messageType := autoReplyMessageType(unwrapAutoReplyMessage(evt.Message))
+		if messageType == "" {
+			messageType = "other"
+		}
+		metrics.MessagesReceived.WithLabelValues(messageType).Inc()
+	case *events.HistorySync:
+		conversations := evt.Data.GetConversations()
+		metrics.HistorySyncConversations.Add(float64(len(conversations)))
+		for _, conversation := range conversations {
+			metrics.HistorySyncMessages.Add(float64(len(conversation.GetMessages())))
+		}
+	default:
+		if state := connectionState(rawEvt); state != "" {
+			metrics.ConnectionEvents.WithLabelValues(state).Inc()
+		}
+	}
+}
+
+// eventName returns the whatsmeow type name of an event, e.g. "Message" or "Receipt"
+func eventName(rawEvt any) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", rawEvt), "*events.")
+}
+
+// connectionState returns the connection state an event reports, or an empty string for other events
+func connectionState(rawEvt any) string {
+	switch rawEvt.(type) {
+	case *events.Connected:
+		return "connected"
+	case *events.Disconnected:
+		return "disconnected"
+	case *events.PairSuccess:
+		return "paired"
+	case *events.LoggedOut:
+		return "logged_out"
+	case *events.StreamReplaced:
+		return "stream_replaced"
+	case *events.ConnectFailure:
+		return "connect_failure"
+	case *events.TemporaryBan:
+		return "temporary_ban"
+	case *events.ClientOutdated:
+		return "client_outdated"
+	case *events.KeepAliveTimeout:
+		return "keepalive_timeout"
+	case *events.KeepAliveRestored:
+		return "keepalive_restored"
+	}
+	return ""
+}
+
+// connectionStatuses reports the state of every device from GetConnectionStatus when metrics are scraped
+func connectionStatuses() []metrics.ConnectionStatus {
+	if deviceManager == nil {
+		isConnected, isLoggedIn, _ := GetConnectionStatus(context.Background())
+		return []metrics.ConnectionStatus{{DeviceID: DefaultDeviceID, Connected: isConnected, LoggedIn: isLoggedIn}}
+	}
+
+	devices := deviceManager.List()
+	statuses := make([]metrics.ConnectionStatus, 0, len(devices))
+	for _, device := range devices {
+		isConnected, isLoggedIn, _ := GetConnectionStatus(ContextWithDevice(context.Background(), device))
+		statuses = append(statuses, metrics.ConnectionStatus{DeviceID: device.ID(), Connected: isConnected, LoggedIn: isLoggedIn})
+	}
+	return statuses
+}
@@ -0,0 +1,275 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// Chat states reported by typing indicators
+const (
+	ChatStateTyping    = "typing"
+	ChatStateRecording = "recording"
+	ChatStatePaused    = "paused"
+)
+
+// PresenceState is the last known presence of a contact
+type PresenceState struct {
+	JID         string
+	Subscribed  bool
+	Online      bool
+	LastSeen    *time.Time
+	ChatState   string
+	ChatJID     string
+	ChatStateAt *time.Time
+	UpdatedAt   time.Time
+}
+
+// presenceTracker keeps the presence subscriptions and the last known presence of contacts per device.
+// Both only live in memory; subscriptions are sent again after every reconnect.
+type presenceTracker struct {
+	mu     sync.RWMutex
+	states map[string]map[string]*PresenceState
+}
+
+var presences = newPresenceTracker()
+
+func newPresenceTracker() *presenceTracker {
+	return &presenceTracker{states: make(map[string]map[string]*PresenceState)}
+}
+
+// SubscribePresence asks WhatsApp for the presence updates of jid on the device of ctx
+func SubscribePresence(ctx context.Context, jid types.JID) error {
+	client := ClientFromContext(ctx)
+	if client == nil {
+		return fmt.Errorf("whatsapp client is not initialized")
+	}
+	if err := client.SubscribePresence(ctx, jid); err != nil {
+		return err
+	}
+
+	presences.update(presenceDeviceID(ctx), jid.ToNonAD().String(), func(state *PresenceState) {
+		state.Subscribed = true
+	})
+	return nil
+}
+
+// UnsubscribePresence stops forwarding the presence updates of jid. WhatsApp has no way to cancel a
+// subscription, updates it still sends until the next reconnect are only cached.
+func UnsubscribePresence(ctx context.Context, jid types.JID) {
+	presences.unsubscribe(presenceDeviceID(ctx), jid.ToNonAD().String())
+}
+
+// GetPresences returns the last known presence of the given contacts, or of every known contact when none are given
+func GetPresences(ctx context.Context, jids []types.JID) []PresenceState {
+	keys := make([]string, 0, len(jids))
+	for _, jid := range jids {
+		keys = append(keys, jid.ToNonAD().String())
+	}
+	return presences.get(presenceDeviceID(ctx), keys)
+}
+
+// resubscribePresences sends the presence subscriptions of the device again, WhatsApp drops them on disconnect
+func resubscribePresences(ctx context.Context) {
+	client := ClientFromContext(ctx)
+	if client == nil {
+		return
+	}
+
+	for _, state := range presences.get(presenceDeviceID(ctx), nil) {
+		if !state.Subscribed {
+			continue
+		}
+		jid, err := types.ParseJID(state.JID)
+		if err != nil {
+			continue
+		}
+		if err := client.SubscribePresence(ctx, jid); err != nil {
+			logrus.Warnf("[PRESENCE] Failed to resubscribe to %s: %v", state.JID, err)
+		}
+	}
+}
+
+func handlePresence(ctx context.Context, evt *events.Presence) {
+	if evt.Unavailable {
+		if evt.LastSeen.IsZero() {
+			log.Infof("%s is now offline", evt.From)
+		} else {
+			log.Infof("%s is now offline (last seen: %s)", evt.From, evt.LastSeen)
+		}
+	} else {
+		log.Infof("%s is now online", evt.From)
+	}
+
+	jid := phoneNumberJID(ctx, evt.From)
+	now := time.Now()
+	state := presences.update(presenceDeviceID(ctx), jid.String(), func(state *PresenceState) {
+		state.Online = !evt.Unavailable
+		if !evt.LastSeen.IsZero() {
+			lastSeen := evt.LastSeen
+			state.LastSeen = &lastSeen
+		} else if !evt.Unavailable {
+			state.LastSeen = &now
+		}
+	})
+
+	// Updates that still arrive after unsubscribing are only cached
+	if !state.Subscribed {
+		return
+	}
+
+	payload := map[string]any{
+		"jid":    state.JID,
+		"online": state.Online,
+	}
+	if state.LastSeen != nil {
+		payload["last_seen"] = state.LastSeen.Format(time.RFC3339)
+	}
+	emitPresenceEvent(ctx, domainWebhook.EventPresenceUpdate, payload, webhookEvent{Name: domainWebhook.EventPresenceUpdate, ChatJID: jid, SenderJID: jid}, now)
+}
+
+func handleChatPresence(ctx context.Context, evt *events.ChatPresence) {
+	if evt.IsFromMe {
+		return
+	}
+
+	chatState := ChatStatePaused
+	if evt.State == types.ChatPresenceComposing {
+		chatState = ChatStateTyping
+		if evt.Media == types.ChatPresenceMediaAudio {
+			chatState = ChatStateRecording
+		}
+	}
+
+	sender := phoneNumberJID(ctx, evt.Sender)
+	chat := evt.Chat.ToNonAD()
+	if !evt.IsGroup {
+		chat = sender
+	}
+
+	now := time.Now()
+	presences.update(presenceDeviceID(ctx), sender.String(), func(state *PresenceState) {
+		// Typing implies being online
+		state.Online = true
+		state.LastSeen = &now
+		state.ChatState = chatState
+		state.ChatJID = chat.String()
+		state.ChatStateAt = &now
+	})
+
+	payload := map[string]any{
+		"chat_id":   chat.String(),
+		"sender_id": sender.String(),
+		"state":     chatState,
+		"is_group":  evt.IsGroup,
+	}
+	emitPresenceEvent(ctx, domainWebhook.EventChatTyping, payload, webhookEvent{Name: domainWebhook.EventChatTyping, ChatJID: chat, SenderJID: sender}, now)
+}
+
+// emitPresenceEvent sends a presence event to the event stream and the configured webhooks
+func emitPresenceEvent(ctx context.Context, event string, payload map[string]any, filter webhookEvent, at time.Time) {
+	if !hasEventTargets(ctx) {
+		return
+	}
+
+	body := map[string]any{
+		"event":     event,
+		"timestamp": at.Format(time.RFC3339),
+		"payload":   payload,
+	}
+	ctx = withWebhookEvent(ctx, filter)
+	go func() {
+		if err := forwardPayloadToConfiguredWebhooks(ctx, body, event+" event"); err != nil {
+			logrus.Errorf("[PRESENCE] Failed to forward %s event to webhook: %v", event, err)
+		}
+	}()
+}
+
+// phoneNumberJID maps a LID to the phone number JID it belongs to, so contacts are reported the same way
+// whichever addressing WhatsApp used
+func phoneNumberJID(ctx context.Context, jid types.JID) types.JID {
+	jid = jid.ToNonAD()
+	if jid.Server != types.HiddenUserServer {
+		return jid
+	}
+
+	client := ClientFromContext(ctx)
+	if client == nil || client.Store == nil || client.Store.LIDs == nil {
+		return jid
+	}
+	if pn, err := client.Store.LIDs.GetPNForLID(ctx, jid); err == nil && !pn.IsEmpty() {
+		return pn.ToNonAD()
+	}
+	return jid
+}
+
+func presenceDeviceID(ctx context.Context) string {
+	if device := DeviceFromContext(ctx); device != nil {
+		return device.ID()
+	}
+	return DefaultDeviceID
+}
+
+// update applies change to the state of jid and returns a copy of the result
+func (t *presenceTracker) update(deviceID string, jid string, change func(state *PresenceState)) PresenceState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	states, ok := t.states[deviceID]
+	if !ok {
+		states = make(map[string]*PresenceState)
+		t.states[deviceID] = states
+	}
+	state, ok := states[jid]
+	if !ok {
+		state = &PresenceState{JID: jid}
+		states[jid] = state
+	}
+
+	change(state)
+	state.UpdatedAt = time.Now()
+	return *state
+}
+
+func (t *presenceTracker) unsubscribe(deviceID string, jid string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if state, ok := t.states[deviceID][jid]; ok {
+		state.Subscribed = false
+	}
+}
+
+// get returns copies of the states of the given JIDs, unknown ones included as empty states, or of every
+// known JID when jids is empty
+func (t *presenceTracker) get(deviceID string, jids []string) []PresenceState {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	states := t.states[deviceID]
+	if len(jids) == 0 {
+		result := make([]PresenceState, 0, len(states))
+		for _, state := range states {
+			result = append(result, *state)
+		}
+		sort.Slice(result, func(i, j int) bool { return result[i].JID < result[j].JID })
+		return result
+	}
+
+	result := make([]PresenceState, 0, len(jids))
+	for _, jid := range jids {
+		if state, ok := states[jid]; ok {
+			result = append(result, *state)
+		} else {
+			result = append(result, PresenceState{JID: jid})
+		}
+	}
+	return result
+}
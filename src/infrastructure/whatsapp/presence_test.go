@@ -0,0 +1,84 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestPresenceTracker(t *testing.T) {
+	tracker := newPresenceTracker()
+	tracker.update("device-a", "628111@s.whatsapp.net", func(state *PresenceState) { state.Subscribed = true })
+	tracker.update("device-a", "628222@s.whatsapp.net", func(state *PresenceState) { state.Online = true })
+
+	if got := tracker.get("device-b", nil); len(got) != 0 {
+		t.Fatalf("states of another device = %+v, want none", got)
+	}
+
+	all := tracker.get("device-a", nil)
+	if len(all) != 2 || all[0].JID != "628111@s.whatsapp.net" || all[1].JID != "628222@s.whatsapp.net" {
+		t.Fatalf("get() = %+v, want both JIDs sorted", all)
+	}
+
+	tracker.unsubscribe("device-a", "628111@s.whatsapp.net")
+	got := tracker.get("device-a", []string{"628111@s.whatsapp.net", "628333@s.whatsapp.net"})
+	if len(got) != 2 {
+		t.Fatalf("get() returned %d states, want 2", len(got))
+	}
+	if got[0].Subscribed {
+		t.Fatal("state is still subscribed after unsubscribe")
+	}
+	if got[1].JID != "628333@s.whatsapp.net" || !got[1].UpdatedAt.IsZero() {
+		t.Fatalf("unknown JID = %+v, want an empty state", got[1])
+	}
+}
+
+func TestHandleChatPresence(t *testing.T) {
+	sender := types.NewJID("628444", types.DefaultUserServer)
+	group := types.NewJID("120363000000000000", types.GroupServer)
+
+	tests := []struct {
+		name      string
+		evt       *events.ChatPresence
+		wantState string
+		wantChat  string
+	}{
+		{
+			name:      "Typing in a private chat",
+			evt:       &events.ChatPresence{MessageSource: types.MessageSource{Chat: sender, Sender: sender}, State: types.ChatPresenceComposing},
+			wantState: ChatStateTyping,
+			wantChat:  sender.String(),
+		},
+		{
+			name:      "Recording in a group",
+			evt:       &events.ChatPresence{MessageSource: types.MessageSource{Chat: group, Sender: sender, IsGroup: true}, State: types.ChatPresenceComposing, Media: types.ChatPresenceMediaAudio},
+			wantState: ChatStateRecording,
+			wantChat:  group.String(),
+		},
+		{
+			name:      "Stopped typing",
+			evt:       &events.ChatPresence{MessageSource: types.MessageSource{Chat: sender, Sender: sender}, State: types.ChatPresencePaused},
+			wantState: ChatStatePaused,
+			wantChat:  sender.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handleChatPresence(context.Background(), tt.evt)
+
+			state := GetPresences(context.Background(), []types.JID{sender})[0]
+			if state.ChatState != tt.wantState {
+				t.Fatalf("chat state = %q, want %q", state.ChatState, tt.wantState)
+			}
+			if state.ChatJID != tt.wantChat {
+				t.Fatalf("chat = %q, want %q", state.ChatJID, tt.wantChat)
+			}
+			if !state.Online || state.ChatStateAt == nil {
+				t.Fatalf("state = %+v, want online with a chat state time", state)
+			}
+		})
+	}
+}
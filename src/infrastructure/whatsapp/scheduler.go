@@ -0,0 +1,258 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	schedulerPollInterval   = time.Second
+	schedulerBatchSize      = 10
+	schedulerMaxAttempts    = 3
+	schedulerBaseBackoff    = 30 * time.Second
+	schedulerMaxBackoff     = 10 * time.Minute
+	schedulerReconnectDelay = 15 * time.Second
+)
+
+// ScheduledMessageSender sends a due scheduled message and returns the WhatsApp message ID
+type ScheduledMessageSender func(ctx context.Context, message *domainChatStorage.ScheduledMessage) (messageID string, err error)
+
+// MessageScheduler sends messages persisted in the chat storage database once they are due.
+// Scheduled messages survive restarts, wait while their device is disconnected, and the
+// outcome of every dispatch is reported to webhooks as a scheduled.sent or scheduled.failed event.
+type MessageScheduler struct {
+	repo    domainChatStorage.IChatStorageRepository
+	send    ScheduledMessageSender
+	ready   func(ctx context.Context) bool
+	notify  func(ctx context.Context, message *domainChatStorage.ScheduledMessage, event string) error
+	wake    chan struct{}
+	nowFunc func() time.Time
+}
+
+var messageScheduler *MessageScheduler
+
+// InitMessageScheduler creates the message scheduler and starts dispatching due messages
+func InitMessageScheduler(ctx context.Context, repo domainChatStorage.IChatStorageRepository, send ScheduledMessageSender) *MessageScheduler {
+	scheduler := newMessageScheduler(repo, send)
+
+	// Messages that were being sent when the process stopped are picked up again
+	if released, err := repo.ReleaseInFlightScheduledMessages(); err != nil {
+		logrus.Errorf("[SCHEDULER] Failed to release in-flight scheduled messages: %v", err)
+	} else if released > 0 {
+		logrus.Infof("[SCHEDULER] Resuming %d interrupted scheduled messages", released)
+	}
+
+	go scheduler.run(ctx)
+
+	messageScheduler = scheduler
+	return scheduler
+}
+
+func newMessageScheduler(repo domainChatStorage.IChatStorageRepository, send ScheduledMessageSender) *MessageScheduler {
+	return &MessageScheduler{
+		repo:    repo,
+		send:    send,
+		ready:   deviceReady,
+		notify:  forwardScheduledMessageToWebhook,
+		wake:    make(chan struct{}, 1),
+		nowFunc: time.Now,
+	}
+}
+
+// GetMessageScheduler returns the running message scheduler, or nil when it has not been started
+func GetMessageScheduler() *MessageScheduler {
+	return messageScheduler
+}
+
+// Wake makes the scheduler look for due messages immediately
+func (s *MessageScheduler) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *MessageScheduler) run(ctx context.Context) {
+	ticker := time.NewTicker(schedulerPollInterval)
+	defer ticker.Stop()
+
+	for {
+		messages, err := s.repo.ClaimDueScheduledMessages(s.nowFunc(), schedulerBatchSize)
+		if err != nil {
+			logrus.Errorf("[SCHEDULER] Failed to claim scheduled messages: %v", err)
+		}
+
+		// Messages are sent one by one so a burst of due messages keeps its order
+		for _, message := range messages {
+			if ctx.Err() != nil {
+				return
+			}
+			s.process(ctx, message)
+		}
+
+		// A full batch means more work is probably waiting
+		if len(messages) == schedulerBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+func (s *MessageScheduler) process(ctx context.Context, message *domainChatStorage.ScheduledMessage) {
+	deviceCtx := ctx
+	if deviceManager != nil {
+		device, ok := deviceManager.Get(scheduledDeviceID(message))
+		if !ok {
+			s.fail(ctx, message, message.Attempts, "device "+scheduledDeviceID(message)+" no longer exists")
+			return
+		}
+		deviceCtx = ContextWithDevice(ctx, device)
+	}
+
+	// A disconnected device does not use up attempts, the message waits until it is back
+	if !s.ready(deviceCtx) {
+		logrus.Debugf("[SCHEDULER] Device of scheduled message %s is not connected, retrying in %s", message.ID, schedulerReconnectDelay)
+		if err := s.repo.RetryScheduledMessage(message.ID, message.Attempts, s.nowFunc().Add(schedulerReconnectDelay), "device is not connected"); err != nil {
+			logrus.Errorf("[SCHEDULER] Failed to postpone scheduled message %s: %v", message.ID, err)
+		}
+		return
+	}
+
+	attempts := message.Attempts + 1
+	messageID, err := s.send(deviceCtx, message)
+	if err == nil {
+		if err := s.repo.MarkScheduledMessageSent(message.ID, attempts, messageID); err != nil {
+			logrus.Errorf("[SCHEDULER] Failed to mark scheduled message %s as sent: %v", message.ID, err)
+		}
+		logrus.Infof("[SCHEDULER] Sent scheduled %s message %s to %s as %s", message.Type, message.ID, message.Phone, messageID)
+
+		message.Status = domainChatStorage.ScheduledMessageSent
+		message.Attempts = attempts
+		message.MessageID = messageID
+		s.finish(deviceCtx, message, domainWebhook.EventScheduledSent)
+		return
+	}
+
+	// A timed out send may still have been delivered, so it is not sent a second time
+	if attempts >= schedulerMaxAttempts || isPermanentSendError(err) || isSendTimeout(err) {
+		s.fail(deviceCtx, message, attempts, err.Error())
+		return
+	}
+
+	backoff := webhookBackoff(attempts, schedulerBaseBackoff, schedulerMaxBackoff)
+	logrus.Warnf("[SCHEDULER] Attempt %d of scheduled message %s failed, retrying in %s: %v", attempts, message.ID, backoff, err)
+	if err := s.repo.RetryScheduledMessage(message.ID, attempts, s.nowFunc().Add(backoff), err.Error()); err != nil {
+		logrus.Errorf("[SCHEDULER] Failed to schedule retry of message %s: %v", message.ID, err)
+	}
+}
+
+func (s *MessageScheduler) fail(ctx context.Context, message *domainChatStorage.ScheduledMessage, attempts int, reason string) {
+	logrus.Errorf("[SCHEDULER] Scheduled message %s to %s failed after %d attempts: %s", message.ID, message.Phone, attempts, reason)
+	if err := s.repo.MarkScheduledMessageFailed(message.ID, attempts, reason); err != nil {
+		logrus.Errorf("[SCHEDULER] Failed to mark scheduled message %s as failed: %v", message.ID, err)
+	}
+
+	message.Status = domainChatStorage.ScheduledMessageFailed
+	message.Attempts = attempts
+	message.LastError = reason
+	s.finish(ctx, message, domainWebhook.EventScheduledFailed)
+}
+
+// finish reports the outcome to webhooks and drops the stored media of a message that will not be sent again
+func (s *MessageScheduler) finish(ctx context.Context, message *domainChatStorage.ScheduledMessage, event string) {
+	RemoveScheduledMedia(message)
+
+	if err := s.notify(ctx, message, event); err != nil {
+		logrus.Warnf("[SCHEDULER] Failed to forward %s event of message %s: %v", event, message.ID, err)
+	}
+}
+
+// RemoveScheduledMedia deletes the directory holding the media of a scheduled message
+func RemoveScheduledMedia(message *domainChatStorage.ScheduledMessage) {
+	if message.MediaPath == "" || message.ID == "" {
+		return
+	}
+	if err := os.RemoveAll(filepath.Join(config.PathScheduled, message.ID)); err != nil {
+		logrus.Warnf("[SCHEDULER] Failed to remove media of scheduled message %s: %v", message.ID, err)
+	}
+}
+
+func scheduledDeviceID(message *domainChatStorage.ScheduledMessage) string {
+	if message.DeviceID == "" {
+		return DefaultDeviceID
+	}
+	return message.DeviceID
+}
+
+func deviceReady(ctx context.Context) bool {
+	client := ClientFromContext(ctx)
+	return client != nil && client.IsConnected() && client.IsLoggedIn()
+}
+
+// isPermanentSendError reports whether retrying the send cannot succeed, e.g. for invalid requests
+func isPermanentSendError(err error) bool {
+	var validationErr pkgError.ValidationError
+	var notFoundErr pkgError.NotFoundError
+	var invalidJIDErr pkgError.InvalidJID
+	return errors.As(err, &validationErr) || errors.As(err, &notFoundErr) || errors.As(err, &invalidJIDErr)
+}
+
+// createScheduledMessagePayload creates a webhook payload describing the outcome of a scheduled message
+func createScheduledMessagePayload(message *domainChatStorage.ScheduledMessage, event string) map[string]any {
+	payload := map[string]any{
+		"schedule_id": message.ID,
+		"type":        message.Type,
+		"phone":       message.Phone,
+		"send_at":     message.SendAt.UTC().Format(time.RFC3339),
+		"status":      message.Status,
+		"attempts":    message.Attempts,
+	}
+	if message.MessageID != "" {
+		payload["message_id"] = message.MessageID
+	}
+	if message.LastError != "" && event == domainWebhook.EventScheduledFailed {
+		payload["error"] = message.LastError
+	}
+
+	return map[string]any{
+		"event":     event,
+		"payload":   payload,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// forwardScheduledMessageToWebhook forwards the outcome of a scheduled message to the configured webhook URLs
+func forwardScheduledMessageToWebhook(ctx context.Context, message *domainChatStorage.ScheduledMessage, event string) error {
+	ctx = withWebhookEvent(ctx, webhookEvent{
+		Name:     event,
+		ChatJID:  scheduledRecipientJID(message.Phone),
+		IsFromMe: true,
+	})
+	return forwardPayloadToConfiguredWebhooks(ctx, createScheduledMessagePayload(message, event), event+" event")
+}
+
+func scheduledRecipientJID(phone string) types.JID {
+	if strings.Contains(phone, "@") {
+		if jid, err := types.ParseJID(phone); err == nil {
+			return jid
+		}
+	}
+	return types.NewJID(strings.TrimPrefix(phone, "+"), types.DefaultUserServer)
+}
@@ -0,0 +1,132 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"go.mau.fi/whatsmeow"
+)
+
+type fakeSchedulerRepo struct {
+	domainChatStorage.IChatStorageRepository
+	sent     []string
+	retried  []string
+	failed   []string
+	attempts int
+	nextAt   time.Time
+}
+
+func (f *fakeSchedulerRepo) MarkScheduledMessageSent(id string, attempts int, _ string) error {
+	f.sent = append(f.sent, id)
+	f.attempts = attempts
+	return nil
+}
+
+func (f *fakeSchedulerRepo) RetryScheduledMessage(id string, attempts int, nextAttemptAt time.Time, _ string) error {
+	f.retried = append(f.retried, id)
+	f.attempts = attempts
+	f.nextAt = nextAttemptAt
+	return nil
+}
+
+func (f *fakeSchedulerRepo) MarkScheduledMessageFailed(id string, attempts int, _ string) error {
+	f.failed = append(f.failed, id)
+	f.attempts = attempts
+	return nil
+}
+
+func TestMessageSchedulerProcess(t *testing.T) {
+	failing := func(context.Context, *domainChatStorage.ScheduledMessage) (string, error) {
+		return "", errors.New("upload failed")
+	}
+	timedOut := func(context.Context, *domainChatStorage.ScheduledMessage) (string, error) {
+		return "", fmt.Errorf("failed to send message: %w", whatsmeow.ErrMessageTimedOut)
+	}
+	invalid := func(context.Context, *domainChatStorage.ScheduledMessage) (string, error) {
+		return "", pkgError.ErrUserNotRegistered
+	}
+	succeeding := func(context.Context, *domainChatStorage.ScheduledMessage) (string, error) {
+		return "3EB0ABC", nil
+	}
+
+	tests := []struct {
+		name         string
+		send         ScheduledMessageSender
+		connected    bool
+		attempts     int
+		wantSent     int
+		wantRetried  int
+		wantFailed   int
+		wantAttempts int
+		wantEvent    string
+	}{
+		{name: "success marks sent", send: succeeding, connected: true, wantSent: 1, wantAttempts: 1, wantEvent: domainWebhook.EventScheduledSent},
+		{name: "failure schedules retry", send: failing, connected: true, wantRetried: 1, wantAttempts: 1},
+		{name: "last failure marks failed", send: failing, connected: true, attempts: 2, wantFailed: 1, wantAttempts: 3, wantEvent: domainWebhook.EventScheduledFailed},
+		{name: "timed out send fails without a resend", send: timedOut, connected: true, wantFailed: 1, wantAttempts: 1, wantEvent: domainWebhook.EventScheduledFailed},
+		{name: "invalid recipient fails immediately", send: invalid, connected: true, wantFailed: 1, wantAttempts: 1, wantEvent: domainWebhook.EventScheduledFailed},
+		{name: "disconnected device waits without using an attempt", send: succeeding, attempts: 1, wantRetried: 1, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeSchedulerRepo{}
+			var events []string
+			scheduler := newMessageScheduler(repo, tt.send)
+			scheduler.ready = func(context.Context) bool { return tt.connected }
+			scheduler.notify = func(_ context.Context, _ *domainChatStorage.ScheduledMessage, event string) error {
+				events = append(events, event)
+				return nil
+			}
+
+			before := time.Now()
+			scheduler.process(context.Background(), &domainChatStorage.ScheduledMessage{ID: "sched-1", Phone: "628123", Attempts: tt.attempts})
+
+			if len(repo.sent) != tt.wantSent || len(repo.retried) != tt.wantRetried || len(repo.failed) != tt.wantFailed {
+				t.Fatalf("unexpected outcome sent=%v retried=%v failed=%v", repo.sent, repo.retried, repo.failed)
+			}
+			if repo.attempts != tt.wantAttempts {
+				t.Fatalf("expected %d attempts to be recorded, got %d", tt.wantAttempts, repo.attempts)
+			}
+			if tt.wantRetried > 0 && !repo.nextAt.After(before) {
+				t.Fatalf("expected retry to be scheduled in the future, got %s", repo.nextAt)
+			}
+			if tt.wantEvent == "" && len(events) != 0 {
+				t.Fatalf("expected no webhook event, got %v", events)
+			}
+			if tt.wantEvent != "" && (len(events) != 1 || events[0] != tt.wantEvent) {
+				t.Fatalf("expected %s webhook event, got %v", tt.wantEvent, events)
+			}
+		})
+	}
+}
+
+func TestRemoveScheduledMedia(t *testing.T) {
+	storages := t.TempDir()
+	previous := config.PathScheduled
+	config.PathScheduled = filepath.Join(storages, "scheduled")
+	defer func() { config.PathScheduled = previous }()
+
+	dir := filepath.Join(config.PathScheduled, "msg-1")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// A media path outside the message directory must not widen what is removed
+	RemoveScheduledMedia(&domainChatStorage.ScheduledMessage{ID: "msg-1", MediaPath: config.PathScheduled})
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("the directory of the message was not removed: %v", err)
+	}
+	if _, err := os.Stat(config.PathScheduled); err != nil {
+		t.Fatalf("the scheduled media directory was removed: %v", err)
+	}
+}
@@ -11,54 +11,82 @@ import (
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 )
 
+// submitWebhook hands a webhook event over for delivery. When the durable queue is running the event is
+// persisted and delivered by the queue workers; otherwise it is delivered in-process with a few retries.
 func submitWebhook(ctx context.Context, payload map[string]any, url string) error {
-	client := &http.Client{Timeout: 10 * time.Second}
-
-	postBody, err := json.Marshal(payload)
-	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("Failed to marshal body: %v", err))
+	target, ok := webhookTargetFromContext(ctx)
+	if !ok || target.URL != url {
+		target = webhookTarget{URL: url, Secret: config.WhatsappWebhookSecret}
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
-	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("error when create http object %v", err))
+	if webhookQueue != nil {
+		return webhookQueue.Enqueue(ctx, payload, target)
 	}
 
-	secretKey := []byte(config.WhatsappWebhookSecret)
-	signature, err := utils.GetMessageDigestOrSignature(postBody, secretKey)
+	postBody, err := json.Marshal(payload)
 	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("error when create signature %v", err))
+		return pkgError.WebhookError(fmt.Sprintf("Failed to marshal body: %v", err))
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Hub-Signature-256", fmt.Sprintf("sha256=%s", signature))
-
 	var attempt int
 	var maxAttempts = 5
 	var sleepDuration = 1 * time.Second
 
 	for attempt = 0; attempt < maxAttempts; attempt++ {
-		// Create new request body for each attempt
-		req.Body = io.NopCloser(bytes.NewBuffer(postBody))
-		resp, err := client.Do(req)
+		err = deliverWebhook(ctx, postBody, url, target.Secret)
 		if err == nil {
-			defer resp.Body.Close()
-			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-				logrus.Infof("Successfully submitted webhook on attempt %d", attempt+1)
-				return nil
-			}
-			err = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+			logrus.Infof("Successfully submitted webhook on attempt %d", attempt+1)
+			return nil
 		}
 		logrus.Warnf("Attempt %d to submit webhook failed: %v", attempt+1, err)
 		if attempt < maxAttempts-1 {
+			metrics.WebhookRetries.Inc()
 			time.Sleep(sleepDuration)
 			sleepDuration *= 2
 		}
 	}
 
+	metrics.WebhookDeadLetters.Inc()
 	return pkgError.WebhookError(fmt.Sprintf("error when submit webhook after %d attempts: %v", attempt, err))
 }
+
+// deliverWebhook performs a single signed POST of an already encoded payload
+func deliverWebhook(ctx context.Context, postBody []byte, url string, secret string) (err error) {
+	start := time.Now()
+	defer func() {
+		metrics.ObserveWebhookDelivery(time.Since(start), err)
+	}()
+
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(postBody))
+	if err != nil {
+		return pkgError.WebhookError(fmt.Sprintf("error when create http object %v", err))
+	}
+
+	secretKey := []byte(secret)
+	signature, err := utils.GetMessageDigestOrSignature(postBody, secretKey)
+	if err != nil {
+		return pkgError.WebhookError(fmt.Sprintf("error when create signature %v", err))
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Hub-Signature-256", fmt.Sprintf("sha256=%s", signature))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
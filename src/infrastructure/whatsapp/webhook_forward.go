@@ -6,17 +6,118 @@ import (
 	"strings"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
 	"github.com/sirupsen/logrus"
 )
 
 var submitWebhookFn = submitWebhook
 
-// forwardPayloadToConfiguredWebhooks attempts to deliver the provided payload to every configured webhook URL.
+// webhookTarget is a webhook endpoint an event is delivered to. Targets coming from the legacy
+// --webhook option and device webhooks have no subscription and receive every event.
+type webhookTarget struct {
+	ID           string
+	URL          string
+	Secret       string
+	subscription *domainChatStorage.WebhookSubscription
+}
+
+type webhookTargetKey struct{}
+
+func withWebhookTarget(ctx context.Context, target webhookTarget) context.Context {
+	return context.WithValue(ctx, webhookTargetKey{}, target)
+}
+
+func webhookTargetFromContext(ctx context.Context) (webhookTarget, bool) {
+	target, ok := ctx.Value(webhookTargetKey{}).(webhookTarget)
+	return target, ok
+}
+
+// webhookTargets returns the globally configured webhook URLs followed by the ones registered
+// for the device bound to ctx and the webhook subscriptions, without duplicate legacy URLs.
+func webhookTargets(ctx context.Context) []webhookTarget {
+	targets := make([]webhookTarget, 0, len(config.WhatsappWebhook))
+	seen := make(map[string]bool)
+	add := func(urls []string) {
+		for _, url := range urls {
+			if url == "" || seen[url] {
+				continue
+			}
+			seen[url] = true
+			targets = append(targets, webhookTarget{URL: url, Secret: config.WhatsappWebhookSecret})
+		}
+	}
+
+	add(config.WhatsappWebhook)
+	if device := DeviceFromContext(ctx); device != nil {
+		add(device.Webhooks())
+	}
+
+	for _, subscription := range webhookSubscriptions.list() {
+		if !subscription.Enabled {
+			continue
+		}
+		targets = append(targets, webhookTarget{
+			ID:           subscription.ID,
+			URL:          subscription.URL,
+			Secret:       webhookSecret(subscription),
+			subscription: subscription,
+		})
+	}
+	return targets
+}
+
+// hasEventTargets reports whether any webhook or the websocket event stream could receive events for
+// the device bound to ctx
+func hasEventTargets(ctx context.Context) bool {
+	return websocket.EventsEnabled() || len(webhookTargets(ctx)) > 0
+}
+
+// hasLiveEventTargets reports whether a webhook or a connected event stream subscriber could receive events
+// for the device bound to ctx. Events only kept for replay skip costly work such as downloading media.
+func hasLiveEventTargets(ctx context.Context) bool {
+	return websocket.HasEventSubscribers() || len(webhookTargets(ctx)) > 0
+}
+
+// forwardPayloadToConfiguredWebhooks publishes the payload to the websocket event stream and attempts to
+// deliver it to every configured webhook whose filters accept the event described by ctx (see withWebhookEvent).
 // It only returns an error when all webhook deliveries fail. Partial failures are logged and suppressed so
 // successful targets still receive the event.
 func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]any, eventName string) error {
-	total := len(config.WhatsappWebhook)
+	event, ok := webhookEventFromContext(ctx)
+	if !ok {
+		event = webhookEvent{Name: domainWebhook.EventMessage}
+		if name, isString := payload["event"].(string); isString {
+			event.Name = name
+		}
+		ctx = withWebhookEvent(ctx, event)
+	}
+
+	var deviceID string
+	if device := DeviceFromContext(ctx); device != nil {
+		deviceID = device.ID()
+		if _, exists := payload["device_id"]; !exists {
+			payload["device_id"] = deviceID
+		}
+	}
+
+	var chatID string
+	if !event.ChatJID.IsEmpty() {
+		chatID = event.ChatJID.ToNonAD().String()
+	}
+	websocket.PublishEvent(event.Name, deviceID, chatID, payload)
+
+	var targets []webhookTarget
+	for _, target := range webhookTargets(ctx) {
+		if target.subscription != nil && !webhookSubscriptionMatches(target.subscription, deviceID, event) {
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	total := len(targets)
 	logrus.Infof("Forwarding %s to %d configured webhook(s)", eventName, total)
 
 	if total == 0 {
@@ -28,10 +129,15 @@ func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]
 		failed    []string
 		successes int
 	)
-	for _, url := range config.WhatsappWebhook {
-		if err := submitWebhookFn(ctx, payload, url); err != nil {
-			failed = append(failed, fmt.Sprintf("%s: %v", url, err))
-			logrus.Warnf("Failed forwarding %s to %s: %v", eventName, url, err)
+	for _, target := range targets {
+		targetPayload := payload
+		if target.subscription != nil && !target.subscription.IncludeMedia {
+			targetPayload = withoutMedia(payload)
+		}
+
+		if err := submitWebhookFn(withWebhookTarget(ctx, target), targetPayload, target.URL); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", target.URL, err))
+			logrus.Warnf("Failed forwarding %s to %s: %v", eventName, target.URL, err)
 			continue
 		}
 		successes++
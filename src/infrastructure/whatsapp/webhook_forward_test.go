@@ -75,3 +75,32 @@ func TestForwardPayloadToConfiguredWebhooks_AllFail(t *testing.T) {
 		t.Fatalf("expected error when all webhooks fail")
 	}
 }
+
+func TestForwardPayloadToConfiguredWebhooks_DeviceWebhooks(t *testing.T) {
+	device := &DeviceInstance{id: "sales", webhooks: []string{"https://device", "https://global"}}
+	ctx := ContextWithDevice(context.Background(), device)
+	payload := map[string]any{"foo": "bar"}
+
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = []string{"https://global"}
+	defer func() { config.WhatsappWebhook = originalWebhooks }()
+
+	originalSubmit := submitWebhookFn
+	var attempts []string
+	submitWebhookFn = func(_ context.Context, p map[string]any, url string) error {
+		if p["device_id"] != "sales" {
+			t.Fatalf("expected device_id sales in payload, got %v", p["device_id"])
+		}
+		attempts = append(attempts, url)
+		return nil
+	}
+	defer func() { submitWebhookFn = originalSubmit }()
+
+	if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "test"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if strings.Join(attempts, ",") != "https://global,https://device" {
+		t.Fatalf("expected global then device webhook without duplicates, got %v", attempts)
+	}
+}
@@ -0,0 +1,214 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	webhookBaseBackoff  = 2 * time.Second
+	webhookMaxBackoff   = 30 * time.Minute
+	webhookPollInterval = 2 * time.Second
+)
+
+// WebhookQueue delivers webhook events persisted in the chat storage database.
+// Events survive restarts, failed attempts are retried with exponential backoff and jitter,
+// and events that exhaust their attempts are kept in a dead-letter state for inspection and replay.
+type WebhookQueue struct {
+	repo        domainChatStorage.IChatStorageRepository
+	workers     int
+	maxAttempts int
+	jobs        chan *domainChatStorage.WebhookDelivery
+	wake        chan struct{}
+	deliver     func(ctx context.Context, postBody []byte, url string, secret string) error
+}
+
+var webhookQueue *WebhookQueue
+
+// InitWebhookQueue creates the durable webhook queue and starts its workers
+func InitWebhookQueue(ctx context.Context, repo domainChatStorage.IChatStorageRepository) *WebhookQueue {
+	workers := config.WhatsappWebhookWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	maxAttempts := config.WhatsappWebhookMaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	queue := &WebhookQueue{
+		repo:        repo,
+		workers:     workers,
+		maxAttempts: maxAttempts,
+		jobs:        make(chan *domainChatStorage.WebhookDelivery, workers),
+		wake:        make(chan struct{}, 1),
+		deliver:     deliverWebhook,
+	}
+
+	// Deliveries that were in flight when the process stopped are picked up again
+	if released, err := repo.ReleaseInFlightWebhookDeliveries(); err != nil {
+		logrus.Errorf("[WEBHOOK_QUEUE] Failed to release in-flight deliveries: %v", err)
+	} else if released > 0 {
+		logrus.Infof("[WEBHOOK_QUEUE] Resuming %d interrupted deliveries", released)
+	}
+
+	for i := 0; i < workers; i++ {
+		go queue.worker(ctx)
+	}
+	go queue.dispatch(ctx)
+
+	webhookQueue = queue
+	return queue
+}
+
+// GetWebhookQueue returns the running webhook queue, or nil when deliveries are not queued
+func GetWebhookQueue() *WebhookQueue {
+	return webhookQueue
+}
+
+// Enqueue persists a webhook event for delivery to the target
+func (q *WebhookQueue) Enqueue(ctx context.Context, payload map[string]any, target webhookTarget) error {
+	postBody, err := json.Marshal(payload)
+	if err != nil {
+		return pkgError.WebhookError(fmt.Sprintf("Failed to marshal body: %v", err))
+	}
+
+	delivery := &domainChatStorage.WebhookDelivery{
+		WebhookID: target.ID,
+		URL:       target.URL,
+		Payload:   string(postBody),
+	}
+	if event, ok := payload["event"].(string); ok {
+		delivery.Event = event
+	} else if event, ok := webhookEventFromContext(ctx); ok {
+		delivery.Event = event.Name
+	}
+	if deviceID, ok := payload["device_id"].(string); ok {
+		delivery.DeviceID = deviceID
+	}
+
+	if err := q.repo.EnqueueWebhookDelivery(delivery); err != nil {
+		return pkgError.WebhookError(fmt.Sprintf("failed to queue webhook: %v", err))
+	}
+
+	q.Wake()
+	return nil
+}
+
+// Wake makes the dispatcher look for due deliveries immediately
+func (q *WebhookQueue) Wake() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *WebhookQueue) dispatch(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		deliveries, err := q.repo.ClaimDueWebhookDeliveries(time.Now(), q.workers)
+		if err != nil {
+			logrus.Errorf("[WEBHOOK_QUEUE] Failed to claim deliveries: %v", err)
+		}
+
+		for _, delivery := range deliveries {
+			select {
+			case q.jobs <- delivery:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		// A full batch means more work is probably waiting
+		if len(deliveries) == q.workers {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+func (q *WebhookQueue) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case delivery := <-q.jobs:
+			q.process(ctx, delivery)
+		}
+	}
+}
+
+func (q *WebhookQueue) process(ctx context.Context, delivery *domainChatStorage.WebhookDelivery) {
+	attempts := delivery.Attempts + 1
+
+	secret := config.WhatsappWebhookSecret
+	if delivery.WebhookID != "" {
+		subscription := webhookSubscriptions.get(delivery.WebhookID)
+		if subscription == nil {
+			logrus.Warnf("[WEBHOOK_QUEUE] Delivery %d dead-lettered, webhook %s no longer exists", delivery.ID, delivery.WebhookID)
+			metrics.WebhookDeadLetters.Inc()
+			if err := q.repo.MarkWebhookDead(delivery.ID, delivery.Attempts, "webhook no longer exists"); err != nil {
+				logrus.Errorf("[WEBHOOK_QUEUE] Failed to dead-letter delivery %d: %v", delivery.ID, err)
+			}
+			return
+		}
+		secret = webhookSecret(subscription)
+	}
+
+	err := q.deliver(ctx, []byte(delivery.Payload), delivery.URL, secret)
+	if err == nil {
+		if err := q.repo.MarkWebhookDelivered(delivery.ID, attempts); err != nil {
+			logrus.Errorf("[WEBHOOK_QUEUE] Failed to mark delivery %d as delivered: %v", delivery.ID, err)
+		}
+		logrus.Infof("[WEBHOOK_QUEUE] Delivered %s event %d to %s on attempt %d", delivery.Event, delivery.ID, delivery.URL, attempts)
+		return
+	}
+
+	if attempts >= q.maxAttempts {
+		logrus.Errorf("[WEBHOOK_QUEUE] Delivery %d to %s dead-lettered after %d attempts: %v", delivery.ID, delivery.URL, attempts, err)
+		metrics.WebhookDeadLetters.Inc()
+		if err := q.repo.MarkWebhookDead(delivery.ID, attempts, err.Error()); err != nil {
+			logrus.Errorf("[WEBHOOK_QUEUE] Failed to dead-letter delivery %d: %v", delivery.ID, err)
+		}
+		return
+	}
+
+	backoff := webhookBackoff(attempts, webhookBaseBackoff, webhookMaxBackoff)
+	logrus.Warnf("[WEBHOOK_QUEUE] Attempt %d of delivery %d to %s failed, retrying in %s: %v", attempts, delivery.ID, delivery.URL, backoff, err)
+	metrics.WebhookRetries.Inc()
+	if err := q.repo.ScheduleWebhookRetry(delivery.ID, attempts, time.Now().Add(backoff), err.Error()); err != nil {
+		logrus.Errorf("[WEBHOOK_QUEUE] Failed to schedule retry of delivery %d: %v", delivery.ID, err)
+	}
+}
+
+// webhookBackoff returns the delay before the next attempt: exponential in the number of attempts made,
+// capped at max, with the upper half randomized so retries of many events do not arrive together
+func webhookBackoff(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
@@ -0,0 +1,83 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+type fakeQueueRepo struct {
+	domainChatStorage.IChatStorageRepository
+	delivered []int64
+	retried   []int64
+	dead      []int64
+	nextAt    time.Time
+}
+
+func (f *fakeQueueRepo) MarkWebhookDelivered(id int64, _ int) error {
+	f.delivered = append(f.delivered, id)
+	return nil
+}
+
+func (f *fakeQueueRepo) ScheduleWebhookRetry(id int64, _ int, nextAttemptAt time.Time, _ string) error {
+	f.retried = append(f.retried, id)
+	f.nextAt = nextAttemptAt
+	return nil
+}
+
+func (f *fakeQueueRepo) MarkWebhookDead(id int64, _ int, _ string) error {
+	f.dead = append(f.dead, id)
+	return nil
+}
+
+func TestWebhookBackoff_GrowsAndIsCapped(t *testing.T) {
+	base := time.Second
+	max := 10 * time.Second
+
+	for attempts, want := range map[int]time.Duration{1: base, 2: 2 * base, 3: 4 * base, 10: max} {
+		for i := 0; i < 20; i++ {
+			got := webhookBackoff(attempts, base, max)
+			if got < want/2 || got > want {
+				t.Fatalf("attempt %d: expected backoff within [%s, %s], got %s", attempts, want/2, want, got)
+			}
+		}
+	}
+}
+
+func TestWebhookQueueProcess(t *testing.T) {
+	failing := func(context.Context, []byte, string, string) error { return errors.New("boom") }
+	succeeding := func(context.Context, []byte, string, string) error { return nil }
+
+	tests := []struct {
+		name          string
+		deliver       func(context.Context, []byte, string, string) error
+		attempts      int
+		wantDelivered int
+		wantRetried   int
+		wantDead      int
+	}{
+		{name: "success marks delivered", deliver: succeeding, wantDelivered: 1},
+		{name: "failure schedules retry", deliver: failing, attempts: 1, wantRetried: 1},
+		{name: "last failure dead-letters", deliver: failing, attempts: 2, wantDead: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeQueueRepo{}
+			queue := &WebhookQueue{repo: repo, maxAttempts: 3, deliver: tt.deliver}
+
+			before := time.Now()
+			queue.process(context.Background(), &domainChatStorage.WebhookDelivery{ID: 7, Attempts: tt.attempts})
+
+			if len(repo.delivered) != tt.wantDelivered || len(repo.retried) != tt.wantRetried || len(repo.dead) != tt.wantDead {
+				t.Fatalf("unexpected outcome delivered=%v retried=%v dead=%v", repo.delivered, repo.retried, repo.dead)
+			}
+			if tt.wantRetried > 0 && !repo.nextAt.After(before) {
+				t.Fatalf("expected retry to be scheduled in the future, got %s", repo.nextAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package whatsapp
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// webhookEvent describes the event being forwarded so subscriptions can filter on it
+type webhookEvent struct {
+	Name      string
+	ChatJID   types.JID
+	SenderJID types.JID
+	IsFromMe  bool
+}
+
+type webhookEventKey struct{}
+
+// withWebhookEvent attaches the description of the event being forwarded to ctx
+func withWebhookEvent(ctx context.Context, event webhookEvent) context.Context {
+	return context.WithValue(ctx, webhookEventKey{}, event)
+}
+
+func webhookEventFromContext(ctx context.Context) (webhookEvent, bool) {
+	event, ok := ctx.Value(webhookEventKey{}).(webhookEvent)
+	return event, ok
+}
+
+// webhookSubscriptionStore caches the configured webhook subscriptions in memory
+type webhookSubscriptionStore struct {
+	mu            sync.RWMutex
+	repo          domainChatStorage.IChatStorageRepository
+	subscriptions []*domainChatStorage.WebhookSubscription
+}
+
+var webhookSubscriptions = &webhookSubscriptionStore{}
+
+// InitWebhookSubscriptions loads the persisted webhook subscriptions
+func InitWebhookSubscriptions(repo domainChatStorage.IChatStorageRepository) {
+	webhookSubscriptions.mu.Lock()
+	webhookSubscriptions.repo = repo
+	webhookSubscriptions.mu.Unlock()
+
+	if err := ReloadWebhookSubscriptions(); err != nil {
+		logrus.Errorf("[WEBHOOK] Failed to load webhook subscriptions: %v", err)
+	}
+}
+
+// ReloadWebhookSubscriptions refreshes the cached subscriptions after they were changed
+func ReloadWebhookSubscriptions() error {
+	webhookSubscriptions.mu.Lock()
+	defer webhookSubscriptions.mu.Unlock()
+
+	if webhookSubscriptions.repo == nil {
+		return nil
+	}
+
+	subscriptions, err := webhookSubscriptions.repo.GetWebhookSubscriptions()
+	if err != nil {
+		return err
+	}
+	webhookSubscriptions.subscriptions = subscriptions
+	return nil
+}
+
+func (s *webhookSubscriptionStore) list() []*domainChatStorage.WebhookSubscription {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.subscriptions
+}
+
+func (s *webhookSubscriptionStore) get(id string) *domainChatStorage.WebhookSubscription {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, subscription := range s.subscriptions {
+		if subscription.ID == id {
+			return subscription
+		}
+	}
+	return nil
+}
+
+// webhookSecret returns the signing secret of a subscription, falling back to the global secret
+func webhookSecret(subscription *domainChatStorage.WebhookSubscription) string {
+	if subscription.Secret != "" {
+		return subscription.Secret
+	}
+	return config.WhatsappWebhookSecret
+}
+
+// webhookSubscriptionMatches reports whether a subscription wants the given event
+func webhookSubscriptionMatches(subscription *domainChatStorage.WebhookSubscription, deviceID string, event webhookEvent) bool {
+	if !subscription.Enabled {
+		return false
+	}
+	if subscription.DeviceID != "" && subscription.DeviceID != deviceID {
+		return false
+	}
+	if len(subscription.Events) > 0 && !slices.Contains(subscription.Events, event.Name) {
+		return false
+	}
+	if subscription.SkipFromMe && event.IsFromMe {
+		return false
+	}
+
+	isGroup := event.ChatJID.Server == types.GroupServer
+	switch subscription.ChatType {
+	case domainChatStorage.WebhookChatTypeGroup:
+		if !isGroup {
+			return false
+		}
+	case domainChatStorage.WebhookChatTypeDM:
+		if isGroup || event.ChatJID.IsEmpty() {
+			return false
+		}
+	}
+
+	candidates := jidCandidates(event.ChatJID, event.SenderJID)
+	if len(subscription.DenyJIDs) > 0 && containsAny(subscription.DenyJIDs, candidates) {
+		return false
+	}
+	if len(subscription.AllowJIDs) > 0 && !containsAny(subscription.AllowJIDs, candidates) {
+		return false
+	}
+
+	return true
+}
+
+// jidCandidates returns the forms a filter entry may use to reference the chat or sender:
+// the full JID or only its user part (phone number, group id)
+func jidCandidates(jids ...types.JID) []string {
+	var candidates []string
+	for _, jid := range jids {
+		if jid.IsEmpty() {
+			continue
+		}
+		candidates = append(candidates, jid.ToNonAD().String(), jid.User)
+	}
+	return candidates
+}
+
+func containsAny(list []string, candidates []string) bool {
+	for _, candidate := range candidates {
+		if slices.Contains(list, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaPayloadKeys are the payload fields removed for subscriptions that exclude media
+var mediaPayloadKeys = []string{"image", "video", "audio", "document", "sticker"}
+
+func withoutMedia(payload map[string]any) map[string]any {
+	stripped := make(map[string]any, len(payload))
+	for key, value := range payload {
+		if slices.Contains(mediaPayloadKeys, key) {
+			continue
+		}
+		stripped[key] = value
+	}
+	return stripped
+}
@@ -0,0 +1,158 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestWebhookSubscriptionMatches(t *testing.T) {
+	dm := types.NewJID("628123456789", types.DefaultUserServer)
+	group := types.NewJID("120363025246125486", types.GroupServer)
+	other := types.NewJID("628999999999", types.DefaultUserServer)
+
+	base := func(modify func(s *domainChatStorage.WebhookSubscription)) *domainChatStorage.WebhookSubscription {
+		subscription := &domainChatStorage.WebhookSubscription{Enabled: true, ChatType: domainChatStorage.WebhookChatTypeAll, IncludeMedia: true}
+		if modify != nil {
+			modify(subscription)
+		}
+		return subscription
+	}
+
+	tests := []struct {
+		name         string
+		subscription *domainChatStorage.WebhookSubscription
+		deviceID     string
+		event        webhookEvent
+		want         bool
+	}{
+		{
+			name:         "no filters accepts everything",
+			subscription: base(nil),
+			event:        webhookEvent{Name: domainWebhook.EventMessage, ChatJID: dm, SenderJID: dm},
+			want:         true,
+		},
+		{
+			name:         "disabled subscription",
+			subscription: base(func(s *domainChatStorage.WebhookSubscription) { s.Enabled = false }),
+			event:        webhookEvent{Name: domainWebhook.EventMessage, ChatJID: dm},
+			want:         false,
+		},
+		{
+			name:         "event not in allowlist",
+			subscription: base(func(s *domainChatStorage.WebhookSubscription) { s.Events = []string{domainWebhook.EventMessageAck} }),
+			event:        webhookEvent{Name: domainWebhook.EventMessage, ChatJID: dm},
+			want:         false,
+		},
+		{
+			name:         "skip from me",
+			subscription: base(func(s *domainChatStorage.WebhookSubscription) { s.SkipFromMe = true }),
+			event:        webhookEvent{Name: domainWebhook.EventMessage, ChatJID: dm, IsFromMe: true},
+			want:         false,
+		},
+		{
+			name:         "groups only rejects direct messages",
+			subscription: base(func(s *domainChatStorage.WebhookSubscription) { s.ChatType = domainChatStorage.WebhookChatTypeGroup }),
+			event:        webhookEvent{Name: domainWebhook.EventMessage, ChatJID: dm},
+			want:         false,
+		},
+		{
+			name:         "direct messages only rejects groups",
+			subscription: base(func(s *domainChatStorage.WebhookSubscription) { s.ChatType = domainChatStorage.WebhookChatTypeDM }),
+			event:        webhookEvent{Name: domainWebhook.EventMessage, ChatJID: group, SenderJID: dm},
+			want:         false,
+		},
+		{
+			name:         "allowlist matches sender phone number",
+			subscription: base(func(s *domainChatStorage.WebhookSubscription) { s.AllowJIDs = []string{"628123456789"} }),
+			event:        webhookEvent{Name: domainWebhook.EventMessage, ChatJID: group, SenderJID: dm},
+			want:         true,
+		},
+		{
+			name:         "allowlist rejects other chats",
+			subscription: base(func(s *domainChatStorage.WebhookSubscription) { s.AllowJIDs = []string{group.String()} }),
+			event:        webhookEvent{Name: domainWebhook.EventMessage, ChatJID: other, SenderJID: other},
+			want:         false,
+		},
+		{
+			name: "denylist wins over allowlist",
+			subscription: base(func(s *domainChatStorage.WebhookSubscription) {
+				s.AllowJIDs = []string{group.String()}
+				s.DenyJIDs = []string{dm.String()}
+			}),
+			event: webhookEvent{Name: domainWebhook.EventMessage, ChatJID: group, SenderJID: dm},
+			want:  false,
+		},
+		{
+			name:         "device restriction",
+			subscription: base(func(s *domainChatStorage.WebhookSubscription) { s.DeviceID = "sales" }),
+			deviceID:     DefaultDeviceID,
+			event:        webhookEvent{Name: domainWebhook.EventMessage, ChatJID: dm},
+			want:         false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := webhookSubscriptionMatches(tt.subscription, tt.deviceID, tt.event); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestForwardPayloadToConfiguredWebhooks_Subscriptions(t *testing.T) {
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = nil
+	defer func() { config.WhatsappWebhook = originalWebhooks }()
+
+	originalSubscriptions := webhookSubscriptions.subscriptions
+	webhookSubscriptions.subscriptions = []*domainChatStorage.WebhookSubscription{
+		{ID: "acks", URL: "https://acks", Enabled: true, Events: []string{domainWebhook.EventMessageAck}, IncludeMedia: true},
+		{ID: "text", URL: "https://text", Secret: "own-secret", Enabled: true, IncludeMedia: false},
+	}
+	defer func() { webhookSubscriptions.subscriptions = originalSubscriptions }()
+
+	originalSubmit := submitWebhookFn
+	received := map[string]map[string]any{}
+	secrets := map[string]string{}
+	submitWebhookFn = func(ctx context.Context, payload map[string]any, url string) error {
+		received[url] = payload
+		if target, ok := webhookTargetFromContext(ctx); ok {
+			secrets[url] = target.Secret
+		}
+		return nil
+	}
+	defer func() { submitWebhookFn = originalSubmit }()
+
+	ctx := withWebhookEvent(context.Background(), webhookEvent{
+		Name:    domainWebhook.EventMessage,
+		ChatJID: types.NewJID("628123456789", types.DefaultUserServer),
+	})
+	payload := map[string]any{"image": "statics/media/a.jpg", "message": "hi"}
+
+	if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "message event"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, ok := received["https://acks"]; ok {
+		t.Fatal("subscription limited to message.ack should not receive message events")
+	}
+	text, ok := received["https://text"]
+	if !ok {
+		t.Fatal("expected catch-all subscription to receive the message")
+	}
+	if _, hasImage := text["image"]; hasImage {
+		t.Fatal("expected media to be stripped for subscription without include_media")
+	}
+	if _, hasImage := payload["image"]; !hasImage {
+		t.Fatal("original payload must not be modified")
+	}
+	if secrets["https://text"] != "own-secret" {
+		t.Fatalf("expected subscription secret to be used, got %q", secrets["https://text"])
+	}
+}
@@ -0,0 +1,43 @@
+package error
+
+import "net/http"
+
+type DeviceNotFoundError string
+
+// Error for complying the error interface
+func (e DeviceNotFoundError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e DeviceNotFoundError) ErrCode() string {
+	return "DEVICE_NOT_FOUND"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e DeviceNotFoundError) StatusCode() int {
+	return http.StatusNotFound
+}
+
+type DeviceConflictError string
+
+// Error for complying the error interface
+func (e DeviceConflictError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e DeviceConflictError) ErrCode() string {
+	return "DEVICE_CONFLICT"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e DeviceConflictError) StatusCode() int {
+	return http.StatusConflict
+}
+
+var (
+	ErrDeviceNotFound      = DeviceNotFoundError("device not found")
+	ErrDeviceAlreadyExists = DeviceConflictError("device already exists")
+	ErrDefaultDeviceRemove = DeviceConflictError("the default device cannot be removed")
+)
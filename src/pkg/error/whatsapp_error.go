@@ -74,4 +74,21 @@ const (
 	ErrInvalidJID        = InvalidJID("your JID is invalid")
 	ErrUserNotRegistered = InvalidJID("user is not registered")
 	ErrWaCLI             = WaCliError("your WhatsApp CLI is invalid or empty")
+
+	ErrWebhookNotFound         = NotFoundError("webhook not found")
+	ErrWebhookDeliveryNotFound = NotFoundError("webhook delivery not found")
+
+	ErrScheduledMessageNotFound = NotFoundError("scheduled message not found")
+
+	ErrCampaignNotFound = NotFoundError("campaign not found")
+
+	ErrAutoReplyRuleNotFound = NotFoundError("auto-reply rule not found")
+
+	ErrMessageNotFound = NotFoundError("message not found")
+
+	ErrPollNotFound = NotFoundError("poll not found")
+
+	ErrAPIKeyNotFound = NotFoundError("API key not found")
+	ErrAPIKeyInvalid  = AuthError("invalid or expired API key")
+	ErrUnauthorized   = AuthError("authentication required, use basic auth or an API key")
 )
@@ -0,0 +1,231 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "whatsapp"
+
+// Send request results
+const (
+	ResultSent      = "sent"
+	ResultScheduled = "scheduled"
+	ResultError     = "error"
+)
+
+// Webhook delivery attempt results
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	SendRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "send_requests_total",
+		Help:      "Send requests by message type and result (sent, scheduled or error).",
+	}, []string{"type", "result"})
+
+	SendDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "send_duration_seconds",
+		Help:      "Duration of send requests by message type, including media upload.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"type"})
+
+	SendErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "send_errors_total",
+		Help:      "Failed send requests by message type and error code.",
+	}, []string{"type", "code"})
+
+	MessagesReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_received_total",
+		Help:      "Incoming messages by message type.",
+	}, []string{"type"})
+
+	Events = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_total",
+		Help:      "whatsmeow events handled by event type.",
+	}, []string{"event"})
+
+	ConnectionEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "connection_events_total",
+		Help:      "Connection state changes by state.",
+	}, []string{"state"})
+
+	HistorySyncConversations = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "history_sync_conversations_total",
+		Help:      "Conversations received through history sync.",
+	})
+
+	HistorySyncMessages = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "history_sync_messages_total",
+		Help:      "Messages received through history sync.",
+	})
+
+	WebhookDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Webhook delivery attempts by result.",
+	}, []string{"result"})
+
+	WebhookDeliveryDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "webhook_delivery_duration_seconds",
+		Help:      "Duration of webhook delivery attempts.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10},
+	})
+
+	WebhookRetries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_retries_total",
+		Help:      "Webhook deliveries retried after a failed attempt.",
+	})
+
+	WebhookDeadLetters = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_dead_letters_total",
+		Help:      "Webhook deliveries given up after their last attempt.",
+	})
+
+	StorageQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "chatstorage_query_duration_seconds",
+		Help:      "Duration of chat storage operations by operation.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"operation"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		SendRequests,
+		SendDuration,
+		SendErrors,
+		MessagesReceived,
+		Events,
+		ConnectionEvents,
+		HistorySyncConversations,
+		HistorySyncMessages,
+		WebhookDeliveries,
+		WebhookDeliveryDuration,
+		WebhookRetries,
+		WebhookDeadLetters,
+		StorageQueryDuration,
+		connections,
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveSend records a send request of the given message type
+func ObserveSend(messageType string, scheduled bool, duration time.Duration, err error) {
+	SendDuration.WithLabelValues(messageType).Observe(duration.Seconds())
+
+	switch {
+	case err != nil:
+		SendRequests.WithLabelValues(messageType, ResultError).Inc()
+		SendErrors.WithLabelValues(messageType, ErrorCode(err)).Inc()
+	case scheduled:
+		SendRequests.WithLabelValues(messageType, ResultScheduled).Inc()
+	default:
+		SendRequests.WithLabelValues(messageType, ResultSent).Inc()
+	}
+}
+
+// ObserveWebhookDelivery records a single webhook delivery attempt
+func ObserveWebhookDelivery(duration time.Duration, err error) {
+	WebhookDeliveryDuration.Observe(duration.Seconds())
+	if err != nil {
+		WebhookDeliveries.WithLabelValues(ResultFailure).Inc()
+		return
+	}
+	WebhookDeliveries.WithLabelValues(ResultSuccess).Inc()
+}
+
+// ObserveStorageQuery records the duration of a chat storage operation started at start
+func ObserveStorageQuery(operation string, start time.Time) {
+	StorageQueryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// ConnectionStatus is the state of one device when the metrics are scraped
+type ConnectionStatus struct {
+	DeviceID  string
+	Connected bool
+	LoggedIn  bool
+}
+
+// RegisterConnectionStatus reports the devices returned by fn as the connected and logged_in gauges
+func RegisterConnectionStatus(fn func() []ConnectionStatus) {
+	connections.mu.Lock()
+	defer connections.mu.Unlock()
+	connections.status = fn
+}
+
+var connections = &connectionCollector{
+	connected: prometheus.NewDesc(namespace+"_connected", "Whether the device is connected to the WhatsApp servers.", []string{"device_id"}, nil),
+	loggedIn:  prometheus.NewDesc(namespace+"_logged_in", "Whether the device is logged in.", []string{"device_id"}, nil),
+}
+
+// connectionCollector reads the connection state at scrape time instead of tracking every change
+type connectionCollector struct {
+	mu        sync.RWMutex
+	status    func() []ConnectionStatus
+	connected *prometheus.Desc
+	loggedIn  *prometheus.Desc
+}
+
+func (c *connectionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.connected
+	ch <- c.loggedIn
+}
+
+func (c *connectionCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	status := c.status
+	c.mu.RUnlock()
+	if status == nil {
+		return
+	}
+
+	for _, device := range status() {
+		ch <- prometheus.MustNewConstMetric(c.connected, prometheus.GaugeValue, gaugeValue(device.Connected), device.DeviceID)
+		ch <- prometheus.MustNewConstMetric(c.loggedIn, prometheus.GaugeValue, gaugeValue(device.LoggedIn), device.DeviceID)
+	}
+}
+
+func gaugeValue(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// ErrorCode returns the pkgError code of err, the same code REST responses carry
+func ErrorCode(err error) string {
+	var genericErr pkgError.GenericError
+	if errors.As(err, &genericErr) {
+		return genericErr.ErrCode()
+	}
+	return "INTERNAL_SERVER_ERROR"
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "validation error", err: pkgError.ValidationError("phone: cannot be blank"), want: "VALIDATION_ERROR"},
+		{name: "wrapped error", err: errors.Join(errors.New("context"), pkgError.ErrNotConnected), want: pkgError.ErrNotConnected.ErrCode()},
+		{name: "plain error", err: errors.New("boom"), want: "INTERNAL_SERVER_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ErrorCode(tt.err))
+		})
+	}
+}
+
+func TestObserveSend(t *testing.T) {
+	ObserveSend("metrics_test", false, time.Second, nil)
+	ObserveSend("metrics_test", true, time.Millisecond, nil)
+	ObserveSend("metrics_test", false, time.Millisecond, pkgError.ValidationError("phone: cannot be blank"))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(SendRequests.WithLabelValues("metrics_test", ResultSent)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(SendRequests.WithLabelValues("metrics_test", ResultScheduled)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(SendRequests.WithLabelValues("metrics_test", ResultError)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(SendErrors.WithLabelValues("metrics_test", "VALIDATION_ERROR")))
+}
+
+func TestHandlerReportsConnectionStatus(t *testing.T) {
+	RegisterConnectionStatus(func() []ConnectionStatus {
+		return []ConnectionStatus{
+			{DeviceID: "default", Connected: true, LoggedIn: true},
+			{DeviceID: "sales", Connected: false, LoggedIn: true},
+		}
+	})
+	defer RegisterConnectionStatus(nil)
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := recorder.Body.String()
+	assert.Equal(t, 200, recorder.Code)
+	assert.True(t, strings.Contains(body, `whatsapp_connected{device_id="default"} 1`), body)
+	assert.True(t, strings.Contains(body, `whatsapp_connected{device_id="sales"} 0`), body)
+	assert.True(t, strings.Contains(body, `whatsapp_logged_in{device_id="sales"} 1`), body)
+}
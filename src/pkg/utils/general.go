@@ -232,6 +232,34 @@ func ContainsMention(message string) []string {
 	return phoneNumbers
 }
 
+// templatePlaceholder matches {{name}} placeholders, spaces inside the braces are allowed
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// TemplatePlaceholders returns the distinct placeholder names used in a message template
+func TemplatePlaceholders(template string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range templatePlaceholder.FindAllStringSubmatch(template, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// RenderTemplate replaces the {{name}} placeholders of a message template with their variables,
+// placeholders without a variable are left untouched
+func RenderTemplate(template string, variables map[string]string) string {
+	return templatePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+		if value, ok := variables[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
 func DownloadImageFromURL(url string) ([]byte, string, error) {
 	client := &http.Client{
 		Timeout: 30 * time.Second,
@@ -604,6 +604,16 @@ func (suite *UtilsTestSuite) TestDownloadVideoFromURL() {
 	assert.Contains(suite.T(), err.Error(), "too many redirects")
 }
 
+func (suite *UtilsTestSuite) TestRenderTemplate() {
+	template := "Hi {{name}}, your order {{ order }} is ready. {{name}}, see you at {{place}}!"
+
+	assert.Equal(suite.T(), []string{"name", "order", "place"}, utils.TemplatePlaceholders(template))
+	assert.Empty(suite.T(), utils.TemplatePlaceholders("no placeholders {name}"))
+
+	rendered := utils.RenderTemplate(template, map[string]string{"name": "Ann", "order": "#42"})
+	assert.Equal(suite.T(), "Hi Ann, your order #42 is ready. Ann, see you at {{place}}!", rendered)
+}
+
 func TestUtilsTestSuite(t *testing.T) {
 	suite.Run(t, new(UtilsTestSuite))
 }
@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type APIKeyHandler struct {
+	apiKeyService domainAPIKey.IAPIKeyUsecase
+}
+
+func InitMcpAPIKey(apiKeyService domainAPIKey.IAPIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+func (h *APIKeyHandler) AddAPIKeyTools(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(h.toolListKeys(), h.handleListKeys)
+	mcpServer.AddTool(h.toolCreateKey(), h.handleCreateKey)
+	mcpServer.AddTool(h.toolDeleteKey(), h.handleDeleteKey)
+	mcpServer.AddTool(h.toolListAudit(), h.handleListAudit)
+}
+
+func (h *APIKeyHandler) toolListKeys() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_api_key_list",
+		mcp.WithDescription("List the API keys with their scopes and allowed recipients. The keys themselves are never returned."),
+		mcp.WithTitleAnnotation("List API Keys"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *APIKeyHandler) handleListKeys(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	keys, err := h.apiKeyService.ListKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(map[string]any{"keys": keys}, fmt.Sprintf("Found %d API keys", len(keys))), nil
+}
+
+func (h *APIKeyHandler) toolCreateKey() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_api_key_create",
+		mcp.WithDescription("Create an API key. The key is only returned once, store it right away."),
+		mcp.WithTitleAnnotation("Create API Key"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("name",
+			mcp.Description("Name describing who uses the key."),
+			mcp.Required(),
+		),
+		mcp.WithArray("scopes",
+			mcp.Description("Scopes granted to the key."),
+			mcp.Required(),
+			mcp.WithStringItems(mcp.Enum(domainAPIKey.Scopes...)),
+		),
+		mcp.WithArray("allowed_recipients",
+			mcp.Description("Only allow sending to recipients matching one of these patterns, e.g. 62812* or *@g.us."),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("expires_at",
+			mcp.Description("Expiry time in RFC3339 format, e.g. 2025-12-31T23:59:59Z. The key never expires when omitted."),
+		),
+	)
+}
+
+func (h *APIKeyHandler) handleCreateKey(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := request.RequireString("name")
+	if err != nil {
+		return nil, err
+	}
+
+	req := domainAPIKey.CreateKeyRequest{Name: name}
+	args := request.GetArguments()
+	if req.Scopes, err = toStringSlice("scopes", args["scopes"]); err != nil {
+		return nil, err
+	}
+	if req.AllowedRecipients, err = toStringSlice("allowed_recipients", args["allowed_recipients"]); err != nil {
+		return nil, err
+	}
+	if expiresAt := strings.TrimSpace(request.GetString("expires_at", "")); expiresAt != "" {
+		parsed, err := time.Parse(time.RFC3339, expiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("expires_at must be in RFC3339 format: %w", err)
+		}
+		req.ExpiresAt = &parsed
+	}
+
+	resp, err := h.apiKeyService.CreateKey(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Created API key %s: %s", resp.ID, resp.APIKey)), nil
+}
+
+func (h *APIKeyHandler) toolDeleteKey() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_api_key_delete",
+		mcp.WithDescription("Revoke an API key. Requests using it are rejected right away."),
+		mcp.WithTitleAnnotation("Revoke API Key"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("key_id",
+			mcp.Description("The API key ID."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *APIKeyHandler) handleDeleteKey(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	keyID, err := request.RequireString("key_id")
+	if err != nil {
+		return nil, err
+	}
+
+	keyID = strings.TrimSpace(keyID)
+	if err := h.apiKeyService.DeleteKey(ctx, keyID); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Revoked API key %s", keyID)), nil
+}
+
+func (h *APIKeyHandler) toolListAudit() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_audit_log",
+		mcp.WithDescription("List the audited actions taken through the REST API, MCP and CLI, newest first."),
+		mcp.WithTitleAnnotation("Audit Log"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("key_id",
+			mcp.Description("Only list the actions taken with this API key."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of entries to return (default 50)."),
+			mcp.DefaultNumber(50),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of entries to skip (default 0)."),
+			mcp.DefaultNumber(0),
+		),
+	)
+}
+
+func (h *APIKeyHandler) handleListAudit(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resp, err := h.apiKeyService.ListAudit(ctx, domainAPIKey.ListAuditRequest{
+		KeyID:  strings.TrimSpace(request.GetString("key_id", "")),
+		Limit:  request.GetInt("limit", 50),
+		Offset: request.GetInt("offset", 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Found %d audit entries", len(resp.Data))), nil
+}
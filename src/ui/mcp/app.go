@@ -27,6 +27,7 @@ func (h *AppHandler) AddAppTools(mcpServer *server.MCPServer) {
 	mcpServer.AddTool(h.toolLoginWithCode(), h.handleLoginWithCode)
 	mcpServer.AddTool(h.toolLogout(), h.handleLogout)
 	mcpServer.AddTool(h.toolReconnect(), h.handleReconnect)
+	mcpServer.AddTool(h.toolAppDevices(), h.handleAppDevices)
 }
 
 func (h *AppHandler) toolConnectionStatus() mcp.Tool {
@@ -40,8 +41,8 @@ func (h *AppHandler) toolConnectionStatus() mcp.Tool {
 	)
 }
 
-func (h *AppHandler) handleConnectionStatus(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	isConnected, isLoggedIn, deviceID := whatsapp.GetConnectionStatus()
+func (h *AppHandler) handleConnectionStatus(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	isConnected, isLoggedIn, deviceID := whatsapp.GetConnectionStatus(ctx)
 
 	structured := map[string]any{
 		"is_connected": isConnected,
@@ -163,3 +164,24 @@ func (h *AppHandler) handleReconnect(ctx context.Context, _ mcp.CallToolRequest)
 
 	return mcp.NewToolResultText("Reconnect initiated"), nil
 }
+
+func (h *AppHandler) toolAppDevices() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_app_devices",
+		mcp.WithDescription("List the WhatsApp sessions linked to the current device, with their push name and JID."),
+		mcp.WithTitleAnnotation("Linked Sessions"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *AppHandler) handleAppDevices(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	devices, err := h.appService.FetchDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	structured := map[string]any{"devices": devices}
+	return mcp.NewToolResultStructured(structured, fmt.Sprintf("Found %d sessions", len(devices))), nil
+}
@@ -0,0 +1,228 @@
+package mcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolScopes maps tool names to the scope they require, the first matching prefix wins.
+// Tools that are not listed require the admin scope.
+var toolScopes = []struct {
+	prefix string
+	scope  string
+}{
+	{"whatsapp_send_", domainAPIKey.ScopeSend},
+	{"whatsapp_scheduled_", domainAPIKey.ScopeSend},
+	{"whatsapp_message_", domainAPIKey.ScopeSend},
+	{"whatsapp_chat_", domainAPIKey.ScopeSend},
+	{"whatsapp_campaign_", domainAPIKey.ScopeSend},
+	{"whatsapp_list_devices", domainAPIKey.ScopeAppLogin},
+	{"whatsapp_list_", domainAPIKey.ScopeReadChats},
+	{"whatsapp_get_chat_messages", domainAPIKey.ScopeReadChats},
+	{"whatsapp_search_messages", domainAPIKey.ScopeReadChats},
+	{"whatsapp_download_message_media", domainAPIKey.ScopeReadChats},
+	{"whatsapp_get_message_status", domainAPIKey.ScopeReadChats},
+	{"whatsapp_get_poll_results", domainAPIKey.ScopeReadChats},
+	{"whatsapp_user_set_", domainAPIKey.ScopeAppLogin},
+	{"whatsapp_user_", domainAPIKey.ScopeReadChats},
+	{"whatsapp_group_info", domainAPIKey.ScopeReadChats},
+	{"whatsapp_group_participants", domainAPIKey.ScopeReadChats},
+	{"whatsapp_group_invite_link", domainAPIKey.ScopeReadChats},
+	{"whatsapp_group_join_requests", domainAPIKey.ScopeReadChats},
+	{"whatsapp_group_history", domainAPIKey.ScopeReadChats},
+	{"whatsapp_group_", domainAPIKey.ScopeGroupAdmin},
+	{"whatsapp_community_info", domainAPIKey.ScopeReadChats},
+	{"whatsapp_community_groups", domainAPIKey.ScopeReadChats},
+	{"whatsapp_community_participants", domainAPIKey.ScopeReadChats},
+	{"whatsapp_community_", domainAPIKey.ScopeGroupAdmin},
+	{"whatsapp_newsletter_info", domainAPIKey.ScopeReadChats},
+	{"whatsapp_newsletter_messages", domainAPIKey.ScopeReadChats},
+	{"whatsapp_newsletter_mark_viewed", domainAPIKey.ScopeReadChats},
+	{"whatsapp_newsletter_", domainAPIKey.ScopeGroupAdmin},
+	{"whatsapp_login_", domainAPIKey.ScopeAppLogin},
+	{"whatsapp_logout", domainAPIKey.ScopeAppLogin},
+	{"whatsapp_reconnect", domainAPIKey.ScopeAppLogin},
+	{"whatsapp_connection_status", domainAPIKey.ScopeAppLogin},
+	{"whatsapp_app_", domainAPIKey.ScopeAppLogin},
+	{"whatsapp_device_", domainAPIKey.ScopeAppLogin},
+}
+
+// ToolScope returns the scope needed to call the named tool
+func ToolScope(name string) string {
+	for _, tool := range toolScopes {
+		if strings.HasPrefix(name, tool.prefix) {
+			return tool.scope
+		}
+	}
+	return domainAPIKey.ScopeAdmin
+}
+
+// AuthHandler authenticates MCP HTTP requests before they reach next. Callers use an API key, sent in the
+// X-Api-Key header or as a bearer token, or the basic auth credentials of APP_BASIC_AUTH. With SSE the
+// credentials have to be sent on the /sse stream and on every /message post alike.
+// A non-empty scope is required of every request; otherwise scopes are checked per tool.
+func AuthHandler(service domainAPIKey.IAPIKeyUsecase, scope string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := authenticateRequest(r, service)
+		if err != nil {
+			writeAuthError(w, err)
+			return
+		}
+
+		if principal != nil {
+			if scope != "" && !principal.HasScope(scope) {
+				writeAuthError(w, pkgError.ForbiddenError(fmt.Sprintf("API key %s is missing the %s scope", principal.Name, scope)))
+				return
+			}
+			r = r.WithContext(domainAPIKey.ContextWithPrincipal(r.Context(), principal))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AuthServerOptions hide the tools the caller has no scope for and audit the tools that change something.
+// Reading resources needs the read:chats scope.
+func AuthServerOptions(service domainAPIKey.IAPIKeyUsecase) []server.ServerOption {
+	return []server.ServerOption{
+		server.WithToolFilter(filterToolsByScope),
+		server.WithToolHandlerMiddleware(authorizeToolMiddleware(service)),
+		server.WithResourceHandlerMiddleware(authorizeResourceMiddleware),
+	}
+}
+
+func authenticateRequest(r *http.Request, service domainAPIKey.IAPIKeyUsecase) (*domainAPIKey.Principal, error) {
+	token := strings.TrimSpace(r.Header.Get("X-Api-Key"))
+	if auth := r.Header.Get("Authorization"); token == "" && len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+		token = strings.TrimSpace(auth[7:])
+	}
+	if token != "" {
+		return service.Authenticate(r.Context(), token)
+	}
+
+	if username, password, ok := r.BasicAuth(); ok {
+		for _, credential := range config.AppBasicAuthCredential {
+			user, secret, _ := strings.Cut(credential, ":")
+			if subtle.ConstantTimeCompare([]byte(user), []byte(username)) == 1 &&
+				subtle.ConstantTimeCompare([]byte(secret), []byte(password)) == 1 {
+				return &domainAPIKey.Principal{Name: username, Scopes: []string{domainAPIKey.ScopeAdmin}}, nil
+			}
+		}
+		return nil, pkgError.ErrUnauthorized
+	}
+
+	required, err := service.AuthRequired(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		return nil, pkgError.ErrUnauthorized
+	}
+	return nil, nil
+}
+
+func writeAuthError(w http.ResponseWriter, err error) {
+	response := utils.ResponseData{Status: http.StatusInternalServerError, Code: "INTERNAL_SERVER_ERROR", Message: err.Error()}
+	if genericErr, ok := err.(pkgError.GenericError); ok {
+		response.Status = genericErr.StatusCode()
+		response.Code = genericErr.ErrCode()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.Status)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func filterToolsByScope(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	principal, ok := domainAPIKey.PrincipalFromContext(ctx)
+	if !ok {
+		return tools
+	}
+
+	result := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if principal.HasScope(ToolScope(tool.Name)) {
+			result = append(result, tool)
+		}
+	}
+	return result
+}
+
+func authorizeResourceMiddleware(next server.ResourceHandlerFunc) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if principal, ok := domainAPIKey.PrincipalFromContext(ctx); ok && !principal.HasScope(domainAPIKey.ScopeReadChats) {
+			return nil, pkgError.ForbiddenError(fmt.Sprintf("API key %s is missing the %s scope", principal.Name, domainAPIKey.ScopeReadChats))
+		}
+		return next(ctx, request)
+	}
+}
+
+func authorizeToolMiddleware(service domainAPIKey.IAPIKeyUsecase) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (result *mcp.CallToolResult, err error) {
+			scope := ToolScope(request.Params.Name)
+			principal, ok := domainAPIKey.PrincipalFromContext(ctx)
+			if ok && !principal.HasScope(scope) {
+				return nil, pkgError.ForbiddenError(fmt.Sprintf("API key %s is missing the %s scope", principal.Name, scope))
+			}
+
+			// Reading chats is not audited, like GET requests on the REST API
+			if scope == domainAPIKey.ScopeReadChats {
+				return next(ctx, request)
+			}
+
+			entry := domainAPIKey.AuditEntry{
+				Actor:  "anonymous",
+				Source: domainAPIKey.SourceMCP,
+				Action: request.Params.Name,
+				Target: request.GetString("phone", request.GetString("group_id", "")),
+			}
+			if ok {
+				entry.KeyID = principal.KeyID
+				entry.Actor = principal.Name
+			}
+			if device := whatsapp.DeviceFromContext(ctx); device != nil {
+				entry.DeviceID = device.ID()
+			}
+
+			defer func() {
+				entry.Status = http.StatusOK
+				recovered := recover()
+				if recovered != nil {
+					err = fmt.Errorf("%v", recovered)
+				}
+				switch {
+				case err != nil:
+					entry.Status = http.StatusInternalServerError
+					entry.Error = err.Error()
+					if genericErr, isGeneric := recovered.(pkgError.GenericError); isGeneric {
+						entry.Status = genericErr.StatusCode()
+					} else if genericErr, isGeneric := err.(pkgError.GenericError); isGeneric {
+						entry.Status = genericErr.StatusCode()
+					}
+				case result != nil && result.IsError:
+					entry.Status = http.StatusBadRequest
+				}
+
+				service.RecordAudit(ctx, entry)
+				if recovered != nil {
+					panic(recovered)
+				}
+			}()
+
+			return next(ctx, request)
+		}
+	}
+}
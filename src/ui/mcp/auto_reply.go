@@ -0,0 +1,267 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type AutoReplyHandler struct {
+	autoReplyService domainAutoReply.IAutoReplyUsecase
+}
+
+func InitMcpAutoReply(autoReplyService domainAutoReply.IAutoReplyUsecase) *AutoReplyHandler {
+	return &AutoReplyHandler{autoReplyService: autoReplyService}
+}
+
+func (h *AutoReplyHandler) AddAutoReplyTools(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(h.toolListRules(), h.handleListRules)
+	mcpServer.AddTool(h.toolGetRule(), h.handleGetRule)
+	mcpServer.AddTool(h.toolCreateRule(), h.handleCreateRule)
+	mcpServer.AddTool(h.toolUpdateRule(), h.handleUpdateRule)
+	mcpServer.AddTool(h.toolDeleteRule(), h.handleDeleteRule)
+	mcpServer.AddTool(h.toolReplaceRules(), h.handleReplaceRules)
+}
+
+// ruleToolOptions are the settings of a rule, as taken by the create and update tools
+func ruleToolOptions(options ...mcp.ToolOption) []mcp.ToolOption {
+	return append(options,
+		mcp.WithString("name",
+			mcp.Description("Rule name."),
+			mcp.Required(),
+		),
+		mcp.WithNumber("position",
+			mcp.Description("Rules are evaluated by ascending position (default: after the last rule)."),
+		),
+		mcp.WithBoolean("enabled",
+			mcp.Description("Whether the rule is evaluated (default: true)."),
+		),
+		mcp.WithString(deviceIDArgument,
+			mcp.Description("Only apply the rule to the messages of this device."),
+		),
+		mcp.WithObject("conditions",
+			mcp.Description("Conditions that must all hold, e.g. {\"match_type\": \"keyword\", \"patterns\": [\"price\"], \"chat_type\": \"dm\", \"message_types\": [\"text\"], \"business_hours\": {\"timezone\": \"Asia/Jakarta\", \"start\": \"09:00\", \"end\": \"17:00\", \"outside\": true}}. Empty conditions match every message."),
+		),
+		mcp.WithArray("actions",
+			mcp.Description("Actions run when the rule matches, e.g. {\"type\": \"reply\", \"text\": \"Thanks, we will get back to you\", \"quote\": true}. Types: reply, react, mark_read, label, webhook."),
+			mcp.Required(),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"type": map[string]any{
+						"type": "string",
+						"enum": []string{domainAutoReply.ActionReply, domainAutoReply.ActionReact, domainAutoReply.ActionMarkRead, domainAutoReply.ActionLabel, domainAutoReply.ActionWebhook},
+					},
+					"text":       map[string]any{"type": "string"},
+					"media_url":  map[string]any{"type": "string"},
+					"media_type": map[string]any{"type": "string", "enum": []string{domainAutoReply.MediaImage, domainAutoReply.MediaVideo, domainAutoReply.MediaAudio}},
+					"quote":      map[string]any{"type": "boolean"},
+					"emoji":      map[string]any{"type": "string"},
+					"label_id":   map[string]any{"type": "string"},
+					"url":        map[string]any{"type": "string"},
+				},
+				"required": []string{"type"},
+			}),
+		),
+		mcp.WithNumber("cooldown_seconds",
+			mcp.Description("Minimum time before the rule replies to the same chat again (default: 0)."),
+		),
+		mcp.WithBoolean("continue_matching",
+			mcp.Description("Keep evaluating the following rules after this one matched (default: false)."),
+		),
+	)
+}
+
+func ruleRequest(request mcp.CallToolRequest) (domainAutoReply.RuleRequest, error) {
+	name, err := request.RequireString("name")
+	if err != nil {
+		return domainAutoReply.RuleRequest{}, err
+	}
+
+	req := domainAutoReply.RuleRequest{
+		Name:             name,
+		Position:         request.GetInt("position", 0),
+		DeviceID:         strings.TrimSpace(request.GetString(deviceIDArgument, "")),
+		CooldownSeconds:  request.GetInt("cooldown_seconds", 0),
+		ContinueMatching: request.GetBool("continue_matching", false),
+	}
+	if _, ok := request.GetArguments()["enabled"]; ok {
+		enabled := request.GetBool("enabled", true)
+		req.Enabled = &enabled
+	}
+	if err := decodeArgument(request, "conditions", &req.Conditions); err != nil {
+		return req, err
+	}
+	if err := decodeArgument(request, "actions", &req.Actions); err != nil {
+		return req, err
+	}
+	return req, nil
+}
+
+func ruleIDOption() mcp.ToolOption {
+	return mcp.WithString("rule_id",
+		mcp.Description("The auto-reply rule ID."),
+		mcp.Required(),
+	)
+}
+
+func (h *AutoReplyHandler) toolListRules() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_auto_reply_list",
+		mcp.WithDescription("List the auto-reply rules in evaluation order."),
+		mcp.WithTitleAnnotation("List Auto-Reply Rules"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *AutoReplyHandler) handleListRules(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rules, err := h.autoReplyService.ListRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(map[string]any{"rules": rules}, fmt.Sprintf("Found %d auto-reply rules", len(rules))), nil
+}
+
+func (h *AutoReplyHandler) toolGetRule() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_auto_reply_get",
+		mcp.WithDescription("Get an auto-reply rule with its conditions and actions."),
+		mcp.WithTitleAnnotation("Get Auto-Reply Rule"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		ruleIDOption(),
+	)
+}
+
+func (h *AutoReplyHandler) handleGetRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ruleID, err := request.RequireString("rule_id")
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := h.autoReplyService.GetRule(ctx, strings.TrimSpace(ruleID))
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(rule, fmt.Sprintf("Auto-reply rule %s (%s)", rule.ID, rule.Name)), nil
+}
+
+func (h *AutoReplyHandler) toolCreateRule() mcp.Tool {
+	return mcp.NewTool("whatsapp_auto_reply_create", ruleToolOptions(
+		mcp.WithDescription("Add an auto-reply rule that runs its actions on the incoming messages matching its conditions."),
+		mcp.WithTitleAnnotation("Create Auto-Reply Rule"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+	)...)
+}
+
+func (h *AutoReplyHandler) handleCreateRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	req, err := ruleRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := h.autoReplyService.CreateRule(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(rule, fmt.Sprintf("Created auto-reply rule %s at position %d", rule.ID, rule.Position)), nil
+}
+
+func (h *AutoReplyHandler) toolUpdateRule() mcp.Tool {
+	return mcp.NewTool("whatsapp_auto_reply_update", ruleToolOptions(
+		mcp.WithDescription("Replace the settings of an auto-reply rule."),
+		mcp.WithTitleAnnotation("Update Auto-Reply Rule"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		ruleIDOption(),
+	)...)
+}
+
+func (h *AutoReplyHandler) handleUpdateRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ruleID, err := request.RequireString("rule_id")
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := ruleRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	req.ID = strings.TrimSpace(ruleID)
+
+	rule, err := h.autoReplyService.UpdateRule(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(rule, fmt.Sprintf("Updated auto-reply rule %s", rule.ID)), nil
+}
+
+func (h *AutoReplyHandler) toolDeleteRule() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_auto_reply_delete",
+		mcp.WithDescription("Delete an auto-reply rule."),
+		mcp.WithTitleAnnotation("Delete Auto-Reply Rule"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		ruleIDOption(),
+	)
+}
+
+func (h *AutoReplyHandler) handleDeleteRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ruleID, err := request.RequireString("rule_id")
+	if err != nil {
+		return nil, err
+	}
+
+	ruleID = strings.TrimSpace(ruleID)
+	if err := h.autoReplyService.DeleteRule(ctx, ruleID); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Deleted auto-reply rule %s", ruleID)), nil
+}
+
+func (h *AutoReplyHandler) toolReplaceRules() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_auto_reply_replace",
+		mcp.WithDescription("Replace the whole auto-reply rule set, e.g. to import an exported configuration. Rules not in the list are deleted."),
+		mcp.WithTitleAnnotation("Replace Auto-Reply Rules"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithArray("rules",
+			mcp.Description("The new rules, each shaped like the result of whatsapp_auto_reply_get."),
+			mcp.Required(),
+			mcp.Items(map[string]any{"type": "object"}),
+		),
+	)
+}
+
+func (h *AutoReplyHandler) handleReplaceRules(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var req domainAutoReply.ReplaceRulesRequest
+	if err := decodeArgument(request, "rules", &req.Rules); err != nil {
+		return nil, err
+	}
+
+	rules, err := h.autoReplyService.ReplaceRules(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(map[string]any{"rules": rules}, fmt.Sprintf("Replaced the auto-reply rules with %d rules", len(rules))), nil
+}
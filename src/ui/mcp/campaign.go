@@ -0,0 +1,328 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainCampaign "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/campaign"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type CampaignHandler struct {
+	campaignService domainCampaign.ICampaignUsecase
+}
+
+func InitMcpCampaign(campaignService domainCampaign.ICampaignUsecase) *CampaignHandler {
+	return &CampaignHandler{campaignService: campaignService}
+}
+
+func (h *CampaignHandler) AddCampaignTools(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(h.toolCreateCampaign(), h.handleCreateCampaign)
+	mcpServer.AddTool(h.toolListCampaigns(), h.handleListCampaigns)
+	mcpServer.AddTool(h.toolGetCampaign(), h.handleGetCampaign)
+	mcpServer.AddTool(h.toolListRecipients(), h.handleListRecipients)
+	mcpServer.AddTool(h.toolPauseCampaign(), h.handlePauseCampaign)
+	mcpServer.AddTool(h.toolResumeCampaign(), h.handleResumeCampaign)
+	mcpServer.AddTool(h.toolCancelCampaign(), h.handleCancelCampaign)
+}
+
+func (h *CampaignHandler) toolCreateCampaign() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_campaign_create",
+		mcp.WithDescription("Send a message to a list of recipients at a throttled rate. {{name}} placeholders in the message are filled from the variables of each recipient."),
+		mcp.WithTitleAnnotation("Create Campaign"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("name",
+			mcp.Description("Campaign name."),
+			mcp.Required(),
+		),
+		mcp.WithString("message",
+			mcp.Description("Message text, sent as the caption when an image is attached."),
+			mcp.Required(),
+		),
+		mcp.WithArray("recipients",
+			mcp.Description("Recipients as objects with a phone and optional variables, e.g. {\"phone\": \"628123456789\", \"variables\": {\"name\": \"Budi\"}}."),
+			mcp.Required(),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"phone":     map[string]any{"type": "string"},
+					"variables": map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+				},
+				"required": []string{"phone"},
+			}),
+		),
+		mcp.WithString("image_url",
+			mcp.Description("URL of an image to send with the message."),
+		),
+		mcp.WithNumber("rate_per_minute",
+			mcp.Description("Maximum messages sent per minute."),
+		),
+		mcp.WithNumber("min_delay_seconds",
+			mcp.Description("Minimum random delay between two messages."),
+		),
+		mcp.WithNumber("max_delay_seconds",
+			mcp.Description("Maximum random delay between two messages."),
+		),
+		mcp.WithBoolean("validate_numbers",
+			mcp.Description("Skip recipients that are not on WhatsApp (default: true)."),
+			mcp.DefaultBool(true),
+		),
+	)
+}
+
+func (h *CampaignHandler) handleCreateCampaign(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := request.RequireString("name")
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := request.RequireString("message")
+	if err != nil {
+		return nil, err
+	}
+
+	req := domainCampaign.CreateCampaignRequest{
+		Name:            name,
+		Message:         message,
+		RatePerMinute:   request.GetInt("rate_per_minute", 0),
+		MinDelaySeconds: request.GetInt("min_delay_seconds", 0),
+		MaxDelaySeconds: request.GetInt("max_delay_seconds", 0),
+		ValidateNumbers: request.GetBool("validate_numbers", true),
+	}
+	if err := decodeArgument(request, "recipients", &req.Recipients); err != nil {
+		return nil, err
+	}
+	if imageURL := strings.TrimSpace(request.GetString("image_url", "")); imageURL != "" {
+		if req.Image, err = downloadFile(ctx, imageURL, config.WhatsappSettingMaxImageSize); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := h.campaignService.CreateCampaign(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Campaign %s started for %d recipients", resp.ID, resp.Stats.Total)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *CampaignHandler) toolListCampaigns() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_campaign_list",
+		mcp.WithDescription("List campaigns with their progress, newest first."),
+		mcp.WithTitleAnnotation("List Campaigns"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("status",
+			mcp.Description("Only list campaigns with this status."),
+			mcp.Enum("running", "paused", "completed", "cancelled"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of campaigns to return (default 25)."),
+			mcp.DefaultNumber(25),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of campaigns to skip (default 0)."),
+			mcp.DefaultNumber(0),
+		),
+	)
+}
+
+func (h *CampaignHandler) handleListCampaigns(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resp, err := h.campaignService.ListCampaigns(ctx, domainCampaign.ListCampaignsRequest{
+		Status: request.GetString("status", ""),
+		Limit:  request.GetInt("limit", 25),
+		Offset: request.GetInt("offset", 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Found %d campaigns", len(resp.Data))), nil
+}
+
+func (h *CampaignHandler) toolGetCampaign() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_campaign_get",
+		mcp.WithDescription("Get a campaign with the number of recipients per status."),
+		mcp.WithTitleAnnotation("Get Campaign"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		campaignIDOption(),
+	)
+}
+
+func (h *CampaignHandler) handleGetCampaign(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	campaignID, err := request.RequireString("campaign_id")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.campaignService.GetCampaign(ctx, strings.TrimSpace(campaignID))
+	if err != nil {
+		return nil, err
+	}
+
+	return campaignResult(resp), nil
+}
+
+func (h *CampaignHandler) toolListRecipients() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_campaign_recipients",
+		mcp.WithDescription("List the recipients of a campaign with their delivery status."),
+		mcp.WithTitleAnnotation("List Campaign Recipients"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		campaignIDOption(),
+		mcp.WithString("status",
+			mcp.Description("Only list recipients with this status."),
+			mcp.Enum("queued", "sending", "sent", "delivered", "read", "failed", "needs_review"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of recipients to return (default 100)."),
+			mcp.DefaultNumber(100),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of recipients to skip (default 0)."),
+			mcp.DefaultNumber(0),
+		),
+	)
+}
+
+func (h *CampaignHandler) handleListRecipients(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	campaignID, err := request.RequireString("campaign_id")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.campaignService.ListRecipients(ctx, domainCampaign.ListRecipientsRequest{
+		CampaignID: strings.TrimSpace(campaignID),
+		Status:     request.GetString("status", ""),
+		Limit:      request.GetInt("limit", 100),
+		Offset:     request.GetInt("offset", 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Found %d recipients", len(resp.Data))), nil
+}
+
+func (h *CampaignHandler) toolPauseCampaign() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_campaign_pause",
+		mcp.WithDescription("Pause a running campaign, queued recipients wait until it is resumed."),
+		mcp.WithTitleAnnotation("Pause Campaign"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		campaignIDOption(),
+	)
+}
+
+func (h *CampaignHandler) handlePauseCampaign(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	campaignID, err := request.RequireString("campaign_id")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.campaignService.PauseCampaign(ctx, strings.TrimSpace(campaignID))
+	if err != nil {
+		return nil, err
+	}
+
+	return campaignResult(resp), nil
+}
+
+func (h *CampaignHandler) toolResumeCampaign() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_campaign_resume",
+		mcp.WithDescription("Resume a paused campaign."),
+		mcp.WithTitleAnnotation("Resume Campaign"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		campaignIDOption(),
+	)
+}
+
+func (h *CampaignHandler) handleResumeCampaign(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	campaignID, err := request.RequireString("campaign_id")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.campaignService.ResumeCampaign(ctx, strings.TrimSpace(campaignID))
+	if err != nil {
+		return nil, err
+	}
+
+	return campaignResult(resp), nil
+}
+
+func (h *CampaignHandler) toolCancelCampaign() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_campaign_cancel",
+		mcp.WithDescription("Cancel a campaign, the recipients still queued are not messaged."),
+		mcp.WithTitleAnnotation("Cancel Campaign"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		campaignIDOption(),
+	)
+}
+
+func (h *CampaignHandler) handleCancelCampaign(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	campaignID, err := request.RequireString("campaign_id")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.campaignService.CancelCampaign(ctx, strings.TrimSpace(campaignID))
+	if err != nil {
+		return nil, err
+	}
+
+	return campaignResult(resp), nil
+}
+
+func campaignIDOption() mcp.ToolOption {
+	return mcp.WithString("campaign_id",
+		mcp.Description("The campaign ID returned when the campaign was created."),
+		mcp.Required(),
+	)
+}
+
+func campaignResult(campaign domainCampaign.Campaign) *mcp.CallToolResult {
+	fallback := fmt.Sprintf("Campaign %s is %s: %d of %d sent, %d failed",
+		campaign.ID, campaign.Status, campaign.Stats.Sent+campaign.Stats.Delivered+campaign.Stats.Read, campaign.Stats.Total, campaign.Stats.Failed)
+	return mcp.NewToolResultStructured(campaign, fallback)
+}
+
+// decodeArgument decodes the object or array argument name into target, an omitted argument leaves it unchanged
+func decodeArgument(request mcp.CallToolRequest, name string, target any) error {
+	raw, ok := request.GetArguments()[name]
+	if !ok || raw == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("%s has an invalid format: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domainCommunity "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/community"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type CommunityHandler struct {
+	communityService domainCommunity.ICommunityUsecase
+}
+
+func InitMcpCommunity(communityService domainCommunity.ICommunityUsecase) *CommunityHandler {
+	return &CommunityHandler{communityService: communityService}
+}
+
+func (h *CommunityHandler) AddCommunityTools(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(h.toolCreateCommunity(), h.handleCreateCommunity)
+	mcpServer.AddTool(h.toolCommunityInfo(), h.handleCommunityInfo)
+	mcpServer.AddTool(h.toolCommunityGroups(), h.handleCommunityGroups)
+	mcpServer.AddTool(h.toolLinkGroup(), h.handleLinkGroup)
+	mcpServer.AddTool(h.toolUnlinkGroup(), h.handleUnlinkGroup)
+	mcpServer.AddTool(h.toolCommunityParticipants(), h.handleCommunityParticipants)
+	mcpServer.AddTool(h.toolSendAnnouncement(), h.handleSendAnnouncement)
+}
+
+func (h *CommunityHandler) toolCreateCommunity() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_community_create",
+		mcp.WithDescription("Create a new WhatsApp community. WhatsApp creates its announcement group along with it."),
+		mcp.WithTitleAnnotation("Create Community"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("name",
+			mcp.Description("Community name."),
+			mcp.Required(),
+		),
+		mcp.WithString("description",
+			mcp.Description("Optional community description."),
+		),
+		mcp.WithArray("participants",
+			mcp.Description("Phone numbers to add during creation (without @s.whatsapp.net suffix)."),
+			mcp.WithStringItems(),
+		),
+	)
+}
+
+func (h *CommunityHandler) handleCreateCommunity(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := request.RequireString("name")
+	if err != nil {
+		return nil, err
+	}
+
+	var participants []string
+	if args := request.GetArguments(); args != nil {
+		if raw, ok := args["participants"]; ok {
+			participants, err = toStringSlice("participants", raw)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	resp, err := h.communityService.CreateCommunity(ctx, domainCommunity.CreateCommunityRequest{
+		Name:         strings.TrimSpace(name),
+		Description:  request.GetString("description", ""),
+		Participants: participants,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Created community %s (%s)", resp.Name, resp.CommunityID)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *CommunityHandler) toolCommunityInfo() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_community_info",
+		mcp.WithDescription("Retrieve a community with its announcement group and linked groups."),
+		mcp.WithTitleAnnotation("Community Info"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("community_id",
+			mcp.Description("Community JID or numeric ID."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *CommunityHandler) handleCommunityInfo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	communityID, err := requireCommunityID(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.communityService.CommunityInfo(ctx, domainCommunity.CommunityRequest{CommunityID: communityID})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Community %s has %d linked groups", resp.Name, len(resp.SubGroups))
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *CommunityHandler) toolCommunityGroups() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_community_groups",
+		mcp.WithDescription("List the groups linked to a community, including its announcement group."),
+		mcp.WithTitleAnnotation("List Community Groups"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("community_id",
+			mcp.Description("Community JID or numeric ID."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *CommunityHandler) handleCommunityGroups(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	communityID, err := requireCommunityID(request)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := h.communityService.ListSubGroups(ctx, domainCommunity.CommunityRequest{CommunityID: communityID})
+	if err != nil {
+		return nil, err
+	}
+
+	structured := map[string]any{
+		"community_id": communityID,
+		"groups":       groups,
+	}
+	fallback := fmt.Sprintf("Community %s has %d linked groups", communityID, len(groups))
+	return mcp.NewToolResultStructured(structured, fallback), nil
+}
+
+func (h *CommunityHandler) toolLinkGroup() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_community_link_group",
+		mcp.WithDescription("Link an existing group to a community."),
+		mcp.WithTitleAnnotation("Link Group to Community"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("community_id",
+			mcp.Description("Community JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithString("group_id",
+			mcp.Description("Group JID or numeric ID to link."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *CommunityHandler) handleLinkGroup(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	linkRequest, err := linkGroupRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.communityService.LinkGroup(ctx, linkRequest); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Linked group %s to community %s", linkRequest.GroupID, linkRequest.CommunityID)), nil
+}
+
+func (h *CommunityHandler) toolUnlinkGroup() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_community_unlink_group",
+		mcp.WithDescription("Unlink a group from a community. The group itself is kept."),
+		mcp.WithTitleAnnotation("Unlink Group from Community"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("community_id",
+			mcp.Description("Community JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithString("group_id",
+			mcp.Description("Group JID or numeric ID to unlink."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *CommunityHandler) handleUnlinkGroup(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	linkRequest, err := linkGroupRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.communityService.UnlinkGroup(ctx, linkRequest); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Unlinked group %s from community %s", linkRequest.GroupID, linkRequest.CommunityID)), nil
+}
+
+func (h *CommunityHandler) toolCommunityParticipants() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_community_participants",
+		mcp.WithDescription("List the participants of every group linked to a community."),
+		mcp.WithTitleAnnotation("List Community Participants"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("community_id",
+			mcp.Description("Community JID or numeric ID."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *CommunityHandler) handleCommunityParticipants(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	communityID, err := requireCommunityID(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.communityService.ListParticipants(ctx, domainCommunity.CommunityRequest{CommunityID: communityID})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Community %s has %d participants", resp.CommunityID, len(resp.Participants))
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *CommunityHandler) toolSendAnnouncement() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_send_community_announcement",
+		mcp.WithDescription("Send a text message to the announcement group of a community, which reaches every member."),
+		mcp.WithTitleAnnotation("Send Community Announcement"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("community_id",
+			mcp.Description("Community JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithString("message",
+			mcp.Description("Text of the announcement."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *CommunityHandler) handleSendAnnouncement(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	communityID, err := requireCommunityID(request)
+	if err != nil {
+		return nil, err
+	}
+	message, err := request.RequireString("message")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.communityService.SendAnnouncement(ctx, domainCommunity.AnnouncementRequest{
+		CommunityID: communityID,
+		Message:     message,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Announcement %s sent to %s", resp.MessageID, resp.GroupID)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func requireCommunityID(request mcp.CallToolRequest) (string, error) {
+	communityID, err := request.RequireString("community_id")
+	if err != nil {
+		return "", err
+	}
+
+	trimmed := strings.TrimSpace(communityID)
+	utils.SanitizePhone(&trimmed)
+	return trimmed, nil
+}
+
+func linkGroupRequest(request mcp.CallToolRequest) (domainCommunity.LinkGroupRequest, error) {
+	communityID, err := requireCommunityID(request)
+	if err != nil {
+		return domainCommunity.LinkGroupRequest{}, err
+	}
+	groupID, err := request.RequireString("group_id")
+	if err != nil {
+		return domainCommunity.LinkGroupRequest{}, err
+	}
+
+	trimmed := strings.TrimSpace(groupID)
+	utils.SanitizePhone(&trimmed)
+	return domainCommunity.LinkGroupRequest{CommunityID: communityID, GroupID: trimmed}, nil
+}
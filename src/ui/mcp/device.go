@@ -0,0 +1,251 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	domainDevice "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const deviceIDArgument = "device_id"
+
+type DeviceHandler struct {
+	deviceService domainDevice.IDeviceUsecase
+}
+
+func InitMcpDevice(deviceService domainDevice.IDeviceUsecase) *DeviceHandler {
+	return &DeviceHandler{deviceService: deviceService}
+}
+
+func (h *DeviceHandler) AddDeviceTools(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(h.toolListDevices(), h.handleListDevices)
+	mcpServer.AddTool(h.toolDeviceInfo(), h.handleDeviceInfo)
+	mcpServer.AddTool(h.toolAddDevice(), h.handleAddDevice)
+	mcpServer.AddTool(h.toolSetDeviceWebhooks(), h.handleSetDeviceWebhooks)
+	mcpServer.AddTool(h.toolRemoveDevice(), h.handleRemoveDevice)
+}
+
+// DeviceServerOptions lets every tool target a specific device through an optional device_id argument
+func DeviceServerOptions() []server.ServerOption {
+	return []server.ServerOption{
+		server.WithToolFilter(addDeviceArgument),
+		server.WithToolHandlerMiddleware(selectDeviceMiddleware),
+	}
+}
+
+// DeviceHTTPContext binds SSE sessions and streamable HTTP requests to the device named in the X-Device-Id header
+func DeviceHTTPContext(ctx context.Context, r *http.Request) context.Context {
+	deviceID := strings.TrimSpace(r.Header.Get("X-Device-Id"))
+	if deviceID == "" {
+		return ctx
+	}
+
+	if manager := whatsapp.GetDeviceManager(); manager != nil {
+		if device, ok := manager.Get(deviceID); ok {
+			return whatsapp.ContextWithDevice(ctx, device)
+		}
+	}
+	return ctx
+}
+
+func addDeviceArgument(_ context.Context, tools []mcp.Tool) []mcp.Tool {
+	result := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if _, exists := tool.InputSchema.Properties[deviceIDArgument]; !exists {
+			properties := make(map[string]any, len(tool.InputSchema.Properties)+1)
+			for key, value := range tool.InputSchema.Properties {
+				properties[key] = value
+			}
+			properties[deviceIDArgument] = map[string]any{
+				"type":        "string",
+				"description": "Managed device to run the tool on. Defaults to the default device.",
+			}
+			tool.InputSchema.Properties = properties
+		}
+		result = append(result, tool)
+	}
+	return result
+}
+
+func selectDeviceMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		deviceID := strings.TrimSpace(request.GetString(deviceIDArgument, ""))
+		if deviceID == "" {
+			return next(ctx, request)
+		}
+
+		manager := whatsapp.GetDeviceManager()
+		if manager == nil {
+			return nil, pkgError.ErrDeviceNotFound
+		}
+		device, ok := manager.Get(deviceID)
+		if !ok {
+			return nil, pkgError.ErrDeviceNotFound
+		}
+
+		return next(whatsapp.ContextWithDevice(ctx, device), request)
+	}
+}
+
+func (h *DeviceHandler) toolListDevices() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_list_devices",
+		mcp.WithDescription("List the WhatsApp devices managed by this server with their connection state."),
+		mcp.WithTitleAnnotation("List Devices"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *DeviceHandler) handleListDevices(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	devices, err := h.deviceService.ListDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Found %d device(s)", len(devices))
+	return mcp.NewToolResultStructured(map[string]any{"devices": devices}, fallback), nil
+}
+
+func (h *DeviceHandler) toolDeviceInfo() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_device_info",
+		mcp.WithDescription("Get a managed device with its JID, connection state and webhooks."),
+		mcp.WithTitleAnnotation("Device Info"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString(deviceIDArgument,
+			mcp.Description("ID of the managed device."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *DeviceHandler) handleDeviceInfo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	deviceID, err := request.RequireString(deviceIDArgument)
+	if err != nil {
+		return nil, err
+	}
+
+	device, err := h.deviceService.GetDevice(ctx, strings.TrimSpace(deviceID))
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Device %s: connected=%t logged_in=%t", device.DeviceID, device.IsConnected, device.IsLoggedIn)
+	return mcp.NewToolResultStructured(device, fallback), nil
+}
+
+func (h *DeviceHandler) toolAddDevice() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_device_add",
+		mcp.WithDescription("Add a device to the server. Log it in afterwards with whatsapp_login_qr or whatsapp_login_with_code and its device_id."),
+		mcp.WithTitleAnnotation("Add Device"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("new_device_id",
+			mcp.Description("ID for the new device, generated when omitted."),
+		),
+		mcp.WithArray("webhooks",
+			mcp.Description("Webhook URLs receiving the events of this device only."),
+			mcp.WithStringItems(),
+		),
+	)
+}
+
+func (h *DeviceHandler) handleAddDevice(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	webhooks, err := toStringSlice("webhooks", request.GetArguments()["webhooks"])
+	if err != nil {
+		return nil, err
+	}
+
+	device, err := h.deviceService.AddDevice(ctx, domainDevice.AddDeviceRequest{
+		DeviceID: strings.TrimSpace(request.GetString("new_device_id", "")),
+		Webhooks: webhooks,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(device, fmt.Sprintf("Added device %s, log it in next", device.DeviceID)), nil
+}
+
+func (h *DeviceHandler) toolSetDeviceWebhooks() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_device_set_webhooks",
+		mcp.WithDescription("Replace the webhook URLs of a managed device. An empty list removes them."),
+		mcp.WithTitleAnnotation("Set Device Webhooks"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString(deviceIDArgument,
+			mcp.Description("ID of the managed device."),
+			mcp.Required(),
+		),
+		mcp.WithArray("webhooks",
+			mcp.Description("Webhook URLs receiving the events of this device only."),
+			mcp.Required(),
+			mcp.WithStringItems(),
+		),
+	)
+}
+
+func (h *DeviceHandler) handleSetDeviceWebhooks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	deviceID, err := request.RequireString(deviceIDArgument)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := toStringSlice("webhooks", request.GetArguments()["webhooks"])
+	if err != nil {
+		return nil, err
+	}
+
+	device, err := h.deviceService.UpdateWebhooks(ctx, domainDevice.UpdateWebhooksRequest{
+		DeviceID: strings.TrimSpace(deviceID),
+		Webhooks: webhooks,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(device, fmt.Sprintf("Device %s has %d webhooks", device.DeviceID, len(device.Webhooks))), nil
+}
+
+func (h *DeviceHandler) toolRemoveDevice() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_device_remove",
+		mcp.WithDescription("Log out a managed device and remove it from the server."),
+		mcp.WithTitleAnnotation("Remove Device"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString(deviceIDArgument,
+			mcp.Description("ID of the managed device."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *DeviceHandler) handleRemoveDevice(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	deviceID, err := request.RequireString(deviceIDArgument)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceID = strings.TrimSpace(deviceID)
+	if err := h.deviceService.RemoveDevice(ctx, deviceID); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Removed device %s", deviceID)), nil
+}
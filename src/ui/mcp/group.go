@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -34,6 +35,10 @@ func (h *GroupHandler) AddGroupTools(mcpServer *server.MCPServer) {
 	mcpServer.AddTool(h.toolSetGroupAnnounce(), h.handleSetGroupAnnounce)
 	mcpServer.AddTool(h.toolListGroupJoinRequests(), h.handleListGroupJoinRequests)
 	mcpServer.AddTool(h.toolManageGroupJoinRequests(), h.handleManageGroupJoinRequests)
+	mcpServer.AddTool(h.toolGroupInfoFromLink(), h.handleGroupInfoFromLink)
+	mcpServer.AddTool(h.toolReconcileParticipants(), h.handleReconcileParticipants)
+	mcpServer.AddTool(h.toolSetGroupPhoto(), h.handleSetGroupPhoto)
+	mcpServer.AddTool(h.toolGroupHistory(), h.handleGroupHistory)
 }
 
 func (h *GroupHandler) toolCreateGroup() mcp.Tool {
@@ -64,7 +69,7 @@ func (h *GroupHandler) handleCreateGroup(ctx context.Context, request mcp.CallTo
 	var participants []string
 	if args := request.GetArguments(); args != nil {
 		if raw, ok := args["participants"]; ok {
-			participants, err = toStringSlice(raw)
+			participants, err = toStringSlice("participants", raw)
 			if err != nil {
 				return nil, err
 			}
@@ -230,7 +235,7 @@ func (h *GroupHandler) handleManageParticipants(ctx context.Context, request mcp
 		return nil, fmt.Errorf("participants are required")
 	}
 
-	participants, err = toStringSlice(rawParticipants)
+	participants, err = toStringSlice("participants", rawParticipants)
 	if err != nil {
 		return nil, err
 	}
@@ -611,7 +616,7 @@ func (h *GroupHandler) handleManageGroupJoinRequests(ctx context.Context, reques
 		return nil, fmt.Errorf("participants are required")
 	}
 
-	participants, err := toStringSlice(participantsRaw)
+	participants, err := toStringSlice("participants", participantsRaw)
 	if err != nil {
 		return nil, err
 	}
@@ -659,6 +664,239 @@ func (h *GroupHandler) handleManageGroupJoinRequests(ctx context.Context, reques
 	return mcp.NewToolResultStructured(result, fallback), nil
 }
 
+func (h *GroupHandler) toolGroupInfoFromLink() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_group_info_from_link",
+		mcp.WithDescription("Preview a group from its invite link without joining it."),
+		mcp.WithTitleAnnotation("Group Info From Link"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("link",
+			mcp.Description("Invite link (https://chat.whatsapp.com/...) or its code."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *GroupHandler) handleGroupInfoFromLink(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	link, err := request.RequireString("link")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.groupService.GetGroupInfoFromLink(ctx, domainGroup.GetGroupInfoFromLinkRequest{Link: strings.TrimSpace(link)})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Group %s (%s) has %d participants", resp.Name, resp.GroupID, resp.ParticipantCount)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *GroupHandler) toolReconcileParticipants() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_group_reconcile_participants",
+		mcp.WithDescription("Bring the members and admins of a group in line with the given lists: missing participants are added, others removed, admins promoted or demoted. Use dry_run to only see the plan."),
+		mcp.WithTitleAnnotation("Reconcile Group Participants"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("group_id",
+			mcp.Description("Group JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithArray("participants",
+			mcp.Description("Phone numbers of everyone who should be in the group."),
+			mcp.Required(),
+			mcp.WithStringItems(),
+		),
+		mcp.WithArray("admins",
+			mcp.Description("Phone numbers of the participants who should be admins."),
+			mcp.WithStringItems(),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Only return the plan without changing the group (default: false)."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("batch_size",
+			mcp.Description("Number of participants changed per request."),
+		),
+		mcp.WithNumber("batch_delay_seconds",
+			mcp.Description("Seconds to wait between batches."),
+		),
+		mcp.WithString("invite_message",
+			mcp.Description("Message sent with the invite link to participants whose privacy settings block being added."),
+		),
+	)
+}
+
+func (h *GroupHandler) handleReconcileParticipants(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	groupID, err := request.RequireString("group_id")
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := toStringSlice("participants", request.GetArguments()["participants"])
+	if err != nil {
+		return nil, err
+	}
+	admins, err := toStringSlice("admins", request.GetArguments()["admins"])
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := strings.TrimSpace(groupID)
+	utils.SanitizePhone(&trimmed)
+
+	req := domainGroup.ReconcileParticipantsRequest{
+		GroupID:       trimmed,
+		Participants:  participants,
+		Admins:        admins,
+		DryRun:        request.GetBool("dry_run", false),
+		BatchSize:     request.GetInt("batch_size", 0),
+		InviteMessage: request.GetString("invite_message", ""),
+	}
+	if _, ok := request.GetArguments()["batch_delay_seconds"]; ok {
+		delay := request.GetInt("batch_delay_seconds", 0)
+		req.BatchDelaySeconds = &delay
+	}
+
+	resp, err := h.groupService.ReconcileParticipants(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Add %d, remove %d, promote %d, demote %d, %d unchanged",
+		len(resp.Plan.Add), len(resp.Plan.Remove), len(resp.Plan.Promote), len(resp.Plan.Demote), resp.Plan.Unchanged)
+	if resp.DryRun {
+		fallback = "Planned: " + fallback
+	}
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *GroupHandler) toolSetGroupPhoto() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_group_set_photo",
+		mcp.WithDescription("Change the group photo to an image downloaded from a URL, or remove it when photo_url is omitted."),
+		mcp.WithTitleAnnotation("Set Group Photo"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("group_id",
+			mcp.Description("Group JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithString("photo_url",
+			mcp.Description("URL of a JPEG or PNG image."),
+		),
+	)
+}
+
+func (h *GroupHandler) handleSetGroupPhoto(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	groupID, err := request.RequireString("group_id")
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := strings.TrimSpace(groupID)
+	utils.SanitizePhone(&trimmed)
+
+	req := domainGroup.SetGroupPhotoRequest{GroupID: trimmed}
+	if photoURL := strings.TrimSpace(request.GetString("photo_url", "")); photoURL != "" {
+		photo, err := downloadFile(ctx, photoURL, config.WhatsappSettingMaxImageSize)
+		if err != nil {
+			return nil, err
+		}
+		if err := utils.ValidateGroupPhotoFormat(photo); err != nil {
+			return nil, err
+		}
+		req.Photo = photo
+	}
+
+	pictureID, err := h.groupService.SetGroupPhoto(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	message := "Group photo updated"
+	if req.Photo == nil {
+		message = "Group photo removed"
+	}
+	return mcp.NewToolResultStructured(domainGroup.SetGroupPhotoResponse{PictureID: pictureID, Message: message}, message), nil
+}
+
+func (h *GroupHandler) toolGroupHistory() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_group_history",
+		mcp.WithDescription("List the recorded changes of a group (joins, leaves, promotions, setting changes), newest first."),
+		mcp.WithTitleAnnotation("Group History"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("group_id",
+			mcp.Description("Group JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithString("type",
+			mcp.Description("Comma separated event types to include, e.g. join,leave,promote."),
+		),
+		mcp.WithString("actor",
+			mcp.Description("Only changes made by this phone number or JID."),
+		),
+		mcp.WithString("participant",
+			mcp.Description("Only changes affecting this phone number or JID."),
+		),
+		mcp.WithString("start_time",
+			mcp.Description("Only changes at or after this time (RFC3339)."),
+		),
+		mcp.WithString("end_time",
+			mcp.Description("Only changes at or before this time (RFC3339)."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of changes to return (default 50, max 500)."),
+			mcp.DefaultNumber(50),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of changes to skip from the start (default 0)."),
+			mcp.DefaultNumber(0),
+		),
+	)
+}
+
+func (h *GroupHandler) handleGroupHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	groupID, err := request.RequireString("group_id")
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := strings.TrimSpace(groupID)
+	utils.SanitizePhone(&trimmed)
+
+	req := domainGroup.GroupHistoryRequest{
+		GroupID:     trimmed,
+		Type:        request.GetString("type", ""),
+		Actor:       strings.TrimSpace(request.GetString("actor", "")),
+		Participant: strings.TrimSpace(request.GetString("participant", "")),
+		Limit:       request.GetInt("limit", 50),
+		Offset:      request.GetInt("offset", 0),
+	}
+	if startTime := request.GetString("start_time", ""); startTime != "" {
+		req.StartTime = &startTime
+	}
+	if endTime := request.GetString("end_time", ""); endTime != "" {
+		req.EndTime = &endTime
+	}
+
+	resp, err := h.groupService.GroupHistory(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Retrieved %d of %d changes of group %s", len(resp.Data), resp.Total, resp.GroupID)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
 func parseParticipantChange(action string) (whatsmeow.ParticipantChange, error) {
 	switch strings.ToLower(strings.TrimSpace(action)) {
 	case "add":
@@ -685,7 +923,8 @@ func parseParticipantRequestChange(action string) (whatsmeow.ParticipantRequestC
 	}
 }
 
-func toStringSlice(raw any) ([]string, error) {
+// toStringSlice converts the raw value of the string array argument name, an omitted argument gives nil
+func toStringSlice(name string, raw any) ([]string, error) {
 	if raw == nil {
 		return nil, nil
 	}
@@ -702,12 +941,12 @@ func toStringSlice(raw any) ([]string, error) {
 		for i, item := range v {
 			str, ok := item.(string)
 			if !ok {
-				return nil, fmt.Errorf("participants[%d] is not a string", i)
+				return nil, fmt.Errorf("%s[%d] is not a string", name, i)
 			}
 			result[i] = strings.TrimSpace(str)
 		}
 		return result, nil
 	default:
-		return nil, fmt.Errorf("participants must be an array of strings")
+		return nil, fmt.Errorf("%s must be an array of strings", name)
 	}
 }
@@ -0,0 +1,296 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type MessageHandler struct {
+	messageService domainMessage.IMessageUsecase
+	chatService    domainChat.IChatUsecase
+}
+
+func InitMcpMessage(messageService domainMessage.IMessageUsecase, chatService domainChat.IChatUsecase) *MessageHandler {
+	return &MessageHandler{
+		messageService: messageService,
+		chatService:    chatService,
+	}
+}
+
+func (h *MessageHandler) AddMessageTools(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(h.toolReactMessage(), h.handleReactMessage)
+	mcpServer.AddTool(h.toolRevokeMessage(), h.handleRevokeMessage)
+	mcpServer.AddTool(h.toolDeleteMessage(), h.handleDeleteMessage)
+	mcpServer.AddTool(h.toolEditMessage(), h.handleEditMessage)
+	mcpServer.AddTool(h.toolMarkAsRead(), h.handleMarkAsRead)
+	mcpServer.AddTool(h.toolStarMessage(), h.handleStarMessage)
+	mcpServer.AddTool(h.toolUnstarMessage(), h.handleUnstarMessage)
+	mcpServer.AddTool(h.toolPinChat(), h.handlePinChat)
+}
+
+// messageToolOptions are the arguments selecting the message a message tool acts on
+func messageToolOptions(options ...mcp.ToolOption) []mcp.ToolOption {
+	return append([]mcp.ToolOption{
+		mcp.WithString("message_id",
+			mcp.Description("The WhatsApp message ID."),
+			mcp.Required(),
+		),
+		mcp.WithString("phone",
+			mcp.Description("Phone number or group ID of the chat the message is in."),
+			mcp.Required(),
+		),
+	}, options...)
+}
+
+// requireMessage returns the message ID and the sanitized chat of a message tool call
+func requireMessage(request mcp.CallToolRequest) (messageID string, phone string, err error) {
+	messageID, err = request.RequireString("message_id")
+	if err != nil {
+		return "", "", err
+	}
+
+	phone, err = request.RequireString("phone")
+	if err != nil {
+		return "", "", err
+	}
+	phone = strings.TrimSpace(phone)
+	utils.SanitizePhone(&phone)
+
+	return strings.TrimSpace(messageID), phone, nil
+}
+
+func (h *MessageHandler) toolReactMessage() mcp.Tool {
+	return mcp.NewTool("whatsapp_message_react", messageToolOptions(
+		mcp.WithDescription("React to a message with an emoji. An empty emoji removes the reaction."),
+		mcp.WithTitleAnnotation("React to Message"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("emoji",
+			mcp.Description("The emoji to react with, e.g. 👍."),
+		),
+	)...)
+}
+
+func (h *MessageHandler) handleReactMessage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	messageID, phone, err := requireMessage(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.messageService.ReactMessage(ctx, domainMessage.ReactionRequest{
+		MessageID: messageID,
+		Phone:     phone,
+		Emoji:     request.GetString("emoji", ""),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, resp.Status), nil
+}
+
+func (h *MessageHandler) toolRevokeMessage() mcp.Tool {
+	return mcp.NewTool("whatsapp_message_revoke", messageToolOptions(
+		mcp.WithDescription("Delete a sent message for everyone in the chat."),
+		mcp.WithTitleAnnotation("Revoke Message"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+	)...)
+}
+
+func (h *MessageHandler) handleRevokeMessage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	messageID, phone, err := requireMessage(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.messageService.RevokeMessage(ctx, domainMessage.RevokeRequest{MessageID: messageID, Phone: phone})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, resp.Status), nil
+}
+
+func (h *MessageHandler) toolDeleteMessage() mcp.Tool {
+	return mcp.NewTool("whatsapp_message_delete", messageToolOptions(
+		mcp.WithDescription("Delete a message for this account only, other participants keep it."),
+		mcp.WithTitleAnnotation("Delete Message"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+	)...)
+}
+
+func (h *MessageHandler) handleDeleteMessage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	messageID, phone, err := requireMessage(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.messageService.DeleteMessage(ctx, domainMessage.DeleteRequest{MessageID: messageID, Phone: phone}); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Message %s deleted", messageID)), nil
+}
+
+func (h *MessageHandler) toolEditMessage() mcp.Tool {
+	return mcp.NewTool("whatsapp_message_edit", messageToolOptions(
+		mcp.WithDescription("Replace the text of a message sent by this account."),
+		mcp.WithTitleAnnotation("Edit Message"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("message",
+			mcp.Description("The new text of the message."),
+			mcp.Required(),
+		),
+	)...)
+}
+
+func (h *MessageHandler) handleEditMessage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	messageID, phone, err := requireMessage(request)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := request.RequireString("message")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.messageService.UpdateMessage(ctx, domainMessage.UpdateMessageRequest{
+		MessageID: messageID,
+		Message:   message,
+		Phone:     phone,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, resp.Status), nil
+}
+
+func (h *MessageHandler) toolMarkAsRead() mcp.Tool {
+	return mcp.NewTool("whatsapp_message_mark_read", messageToolOptions(
+		mcp.WithDescription("Mark a received message as read, sending the read receipt."),
+		mcp.WithTitleAnnotation("Mark Message as Read"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)...)
+}
+
+func (h *MessageHandler) handleMarkAsRead(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	messageID, phone, err := requireMessage(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.messageService.MarkAsRead(ctx, domainMessage.MarkAsReadRequest{MessageID: messageID, Phone: phone})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, resp.Status), nil
+}
+
+func (h *MessageHandler) toolStarMessage() mcp.Tool {
+	return mcp.NewTool("whatsapp_message_star", messageToolOptions(
+		mcp.WithDescription("Star a message."),
+		mcp.WithTitleAnnotation("Star Message"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)...)
+}
+
+func (h *MessageHandler) handleStarMessage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.starMessage(ctx, request, true)
+}
+
+func (h *MessageHandler) toolUnstarMessage() mcp.Tool {
+	return mcp.NewTool("whatsapp_message_unstar", messageToolOptions(
+		mcp.WithDescription("Remove the star from a message."),
+		mcp.WithTitleAnnotation("Unstar Message"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)...)
+}
+
+func (h *MessageHandler) handleUnstarMessage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.starMessage(ctx, request, false)
+}
+
+func (h *MessageHandler) starMessage(ctx context.Context, request mcp.CallToolRequest, starred bool) (*mcp.CallToolResult, error) {
+	messageID, phone, err := requireMessage(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.messageService.StarMessage(ctx, domainMessage.StarRequest{
+		MessageID: messageID,
+		Phone:     phone,
+		IsStarred: starred,
+	}); err != nil {
+		return nil, err
+	}
+
+	if starred {
+		return mcp.NewToolResultText(fmt.Sprintf("Message %s starred", messageID)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Message %s unstarred", messageID)), nil
+}
+
+func (h *MessageHandler) toolPinChat() mcp.Tool {
+	return mcp.NewTool("whatsapp_chat_pin",
+		mcp.WithDescription("Pin a chat to the top of the chat list, or unpin it."),
+		mcp.WithTitleAnnotation("Pin Chat"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("chat_jid",
+			mcp.Description("The chat JID, e.g. 628123456789@s.whatsapp.net or 120363...@g.us."),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("pinned",
+			mcp.Description("True to pin the chat, false to unpin it (default: true)."),
+			mcp.DefaultBool(true),
+		),
+	)
+}
+
+func (h *MessageHandler) handlePinChat(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	chatJID, err := request.RequireString("chat_jid")
+	if err != nil {
+		return nil, err
+	}
+
+	pinned := true
+	if value, ok := request.GetArguments()["pinned"]; ok {
+		if pinned, err = toBool(value); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := h.chatService.PinChat(ctx, domainChat.PinChatRequest{
+		ChatJID: strings.TrimSpace(chatJID),
+		Pinned:  pinned,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, resp.Message), nil
+}
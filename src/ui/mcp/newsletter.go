@@ -0,0 +1,332 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type NewsletterHandler struct {
+	newsletterService domainNewsletter.INewsletterUsecase
+}
+
+func InitMcpNewsletter(newsletterService domainNewsletter.INewsletterUsecase) *NewsletterHandler {
+	return &NewsletterHandler{newsletterService: newsletterService}
+}
+
+func (h *NewsletterHandler) AddNewsletterTools(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(h.toolCreateNewsletter(), h.handleCreateNewsletter)
+	mcpServer.AddTool(h.toolUpdateNewsletter(), h.handleUpdateNewsletter)
+	mcpServer.AddTool(h.toolFollowNewsletter(), h.handleFollowNewsletter)
+	mcpServer.AddTool(h.toolUnfollowNewsletter(), h.handleUnfollowNewsletter)
+	mcpServer.AddTool(h.toolNewsletterInfo(), h.handleNewsletterInfo)
+	mcpServer.AddTool(h.toolNewsletterMessages(), h.handleNewsletterMessages)
+	mcpServer.AddTool(h.toolMarkViewed(), h.handleMarkViewed)
+	mcpServer.AddTool(h.toolPublish(), h.handlePublish)
+}
+
+func (h *NewsletterHandler) toolCreateNewsletter() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_newsletter_create",
+		mcp.WithDescription("Create a new WhatsApp channel (newsletter) owned by this account."),
+		mcp.WithTitleAnnotation("Create Channel"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("name",
+			mcp.Description("Channel name."),
+			mcp.Required(),
+		),
+		mcp.WithString("description",
+			mcp.Description("Optional channel description."),
+		),
+	)
+}
+
+func (h *NewsletterHandler) handleCreateNewsletter(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := request.RequireString("name")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.newsletterService.Create(ctx, domainNewsletter.CreateRequest{
+		Name:        name,
+		Description: request.GetString("description", ""),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Created channel %s (%s)", resp.ThreadMeta.Name.Text, resp.ID)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *NewsletterHandler) toolUpdateNewsletter() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_newsletter_update",
+		mcp.WithDescription("Change the name and/or description of a channel this account administers. Omitted fields keep their value."),
+		mcp.WithTitleAnnotation("Update Channel"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("newsletter_id",
+			mcp.Description("Channel JID (e.g. 120363...@newsletter) or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("New channel name."),
+		),
+		mcp.WithString("description",
+			mcp.Description("New channel description."),
+		),
+	)
+}
+
+func (h *NewsletterHandler) handleUpdateNewsletter(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	newsletterID, err := requireNewsletterID(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.newsletterService.Update(ctx, domainNewsletter.UpdateRequest{
+		NewsletterID: newsletterID,
+		Name:         request.GetString("name", ""),
+		Description:  request.GetString("description", ""),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Updated channel %s (%s)", resp.ThreadMeta.Name.Text, resp.ID)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *NewsletterHandler) toolFollowNewsletter() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_newsletter_follow",
+		mcp.WithDescription("Follow a WhatsApp channel using its invite link."),
+		mcp.WithTitleAnnotation("Follow Channel"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("invite",
+			mcp.Description("Channel invite link (https://whatsapp.com/channel/...) or its code."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *NewsletterHandler) handleFollowNewsletter(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	invite, err := request.RequireString("invite")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.newsletterService.Follow(ctx, domainNewsletter.FollowRequest{Invite: invite})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Following channel %s (%s)", resp.ThreadMeta.Name.Text, resp.ID)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *NewsletterHandler) toolUnfollowNewsletter() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_newsletter_unfollow",
+		mcp.WithDescription("Stop following a WhatsApp channel."),
+		mcp.WithTitleAnnotation("Unfollow Channel"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("newsletter_id",
+			mcp.Description("Channel JID (e.g. 120363...@newsletter) or numeric ID."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *NewsletterHandler) handleUnfollowNewsletter(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	newsletterID, err := requireNewsletterID(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.newsletterService.Unfollow(ctx, domainNewsletter.UnfollowRequest{NewsletterID: newsletterID}); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Unfollowed channel %s", newsletterID)), nil
+}
+
+func (h *NewsletterHandler) toolNewsletterInfo() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_newsletter_info",
+		mcp.WithDescription("Retrieve the metadata of a channel: name, description, picture, subscriber count, verification and this account's role."),
+		mcp.WithTitleAnnotation("Channel Info"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("newsletter_id",
+			mcp.Description("Channel JID (e.g. 120363...@newsletter) or numeric ID."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *NewsletterHandler) handleNewsletterInfo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	newsletterID, err := requireNewsletterID(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.newsletterService.Info(ctx, domainNewsletter.InfoRequest{NewsletterID: newsletterID})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Channel %s has %d subscribers", resp.ThreadMeta.Name.Text, resp.ThreadMeta.SubscriberCount)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *NewsletterHandler) toolNewsletterMessages() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_newsletter_messages",
+		mcp.WithDescription("Fetch the most recent posts of a channel with their view and reaction counts."),
+		mcp.WithTitleAnnotation("Get Channel Messages"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("newsletter_id",
+			mcp.Description("Channel JID (e.g. 120363...@newsletter) or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of posts to return (default 50, max 100)."),
+		),
+		mcp.WithNumber("before",
+			mcp.Description("Only return posts older than this server ID, for paging."),
+		),
+	)
+}
+
+func (h *NewsletterHandler) handleNewsletterMessages(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	newsletterID, err := requireNewsletterID(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.newsletterService.Messages(ctx, domainNewsletter.MessagesRequest{
+		NewsletterID: newsletterID,
+		Limit:        request.GetInt("limit", 50),
+		Before:       request.GetInt("before", 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Found %d posts in channel %s", len(resp.Data), resp.NewsletterID)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *NewsletterHandler) toolMarkViewed() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_newsletter_mark_viewed",
+		mcp.WithDescription("Mark channel posts as viewed so they count towards the view counts."),
+		mcp.WithTitleAnnotation("Mark Channel Posts Viewed"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("newsletter_id",
+			mcp.Description("Channel JID (e.g. 120363...@newsletter) or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithArray("server_ids",
+			mcp.Description("Server IDs of the posts, as returned by whatsapp_newsletter_messages."),
+			mcp.Required(),
+			mcp.WithNumberItems(),
+		),
+	)
+}
+
+func (h *NewsletterHandler) handleMarkViewed(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	newsletterID, err := requireNewsletterID(request)
+	if err != nil {
+		return nil, err
+	}
+	serverIDs, err := request.RequireIntSlice("server_ids")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.newsletterService.MarkViewed(ctx, domainNewsletter.MarkViewedRequest{
+		NewsletterID: newsletterID,
+		ServerIDs:    serverIDs,
+	}); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Marked %d posts of channel %s as viewed", len(serverIDs), newsletterID)), nil
+}
+
+func (h *NewsletterHandler) toolPublish() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_send_newsletter_post",
+		mcp.WithDescription("Publish a post to a channel this account administers. Attach image_url or video_url to post media with message as its caption."),
+		mcp.WithTitleAnnotation("Publish Channel Post"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("newsletter_id",
+			mcp.Description("Channel JID (e.g. 120363...@newsletter) or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithString("message",
+			mcp.Description("Text of the post, or the caption when media is attached."),
+		),
+		mcp.WithString("image_url",
+			mcp.Description("URL of an image to post."),
+		),
+		mcp.WithString("video_url",
+			mcp.Description("URL of a video to post."),
+		),
+	)
+}
+
+func (h *NewsletterHandler) handlePublish(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	newsletterID, err := requireNewsletterID(request)
+	if err != nil {
+		return nil, err
+	}
+
+	publishRequest := domainNewsletter.PublishRequest{
+		NewsletterID: newsletterID,
+		Message:      request.GetString("message", ""),
+	}
+	if imageURL := strings.TrimSpace(request.GetString("image_url", "")); imageURL != "" {
+		publishRequest.ImageURL = &imageURL
+	}
+	if videoURL := strings.TrimSpace(request.GetString("video_url", "")); videoURL != "" {
+		publishRequest.VideoURL = &videoURL
+	}
+
+	resp, err := h.newsletterService.Publish(ctx, publishRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Post %s published to %s", resp.MessageID, resp.NewsletterID)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func requireNewsletterID(request mcp.CallToolRequest) (string, error) {
+	newsletterID, err := request.RequireString("newsletter_id")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(newsletterID), nil
+}
@@ -0,0 +1,220 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// restTools maps every REST route to the MCP tool offering the same operation
+var restTools = map[string]string{
+	"GET /app/login":                   "whatsapp_login_qr",
+	"GET /app/login-with-code":         "whatsapp_login_with_code",
+	"GET /app/logout":                  "whatsapp_logout",
+	"GET /app/reconnect":               "whatsapp_reconnect",
+	"GET /app/devices":                 "whatsapp_app_devices",
+	"GET /app/status":                  "whatsapp_connection_status",
+	"GET /devices":                     "whatsapp_list_devices",
+	"POST /devices":                    "whatsapp_device_add",
+	"GET /devices/:device_id":          "whatsapp_device_info",
+	"PUT /devices/:device_id/webhooks": "whatsapp_device_set_webhooks",
+	"DELETE /devices/:device_id":       "whatsapp_device_remove",
+
+	"POST /send/message":                  "whatsapp_send_text",
+	"POST /send/image":                    "whatsapp_send_image",
+	"POST /send/file":                     "whatsapp_send_file",
+	"POST /send/video":                    "whatsapp_send_video",
+	"POST /send/sticker":                  "whatsapp_send_sticker",
+	"POST /send/contact":                  "whatsapp_send_contact",
+	"POST /send/link":                     "whatsapp_send_link",
+	"POST /send/location":                 "whatsapp_send_location",
+	"POST /send/audio":                    "whatsapp_send_audio",
+	"POST /send/poll":                     "whatsapp_send_poll",
+	"POST /send/presence":                 "whatsapp_send_presence",
+	"POST /send/chat-presence":            "whatsapp_send_chat_presence",
+	"GET /send/scheduled":                 "whatsapp_scheduled_list",
+	"GET /send/scheduled/:schedule_id":    "whatsapp_scheduled_get",
+	"PATCH /send/scheduled/:schedule_id":  "whatsapp_scheduled_reschedule",
+	"DELETE /send/scheduled/:schedule_id": "whatsapp_scheduled_cancel",
+
+	"POST /message/:message_id/reaction": "whatsapp_message_react",
+	"POST /message/:message_id/revoke":   "whatsapp_message_revoke",
+	"POST /message/:message_id/delete":   "whatsapp_message_delete",
+	"POST /message/:message_id/update":   "whatsapp_message_edit",
+	"POST /message/:message_id/read":     "whatsapp_message_mark_read",
+	"POST /message/:message_id/star":     "whatsapp_message_star",
+	"POST /message/:message_id/unstar":   "whatsapp_message_unstar",
+	"GET /message/:message_id/download":  "whatsapp_download_message_media",
+	"GET /message/:message_id/status":    "whatsapp_get_message_status",
+	"GET /poll/:message_id/results":      "whatsapp_get_poll_results",
+
+	"GET /chats":                   "whatsapp_list_chats",
+	"GET /chat/:chat_jid/messages": "whatsapp_get_chat_messages",
+	"POST /chat/:chat_jid/pin":     "whatsapp_chat_pin",
+	"GET /search/messages":         "whatsapp_search_messages",
+	"GET /calls":                   "whatsapp_list_calls",
+
+	"GET /user/info":                  "whatsapp_user_info",
+	"GET /user/avatar":                "whatsapp_user_avatar",
+	"POST /user/avatar":               "whatsapp_user_set_avatar",
+	"POST /user/pushname":             "whatsapp_user_set_push_name",
+	"GET /user/my/privacy":            "whatsapp_user_privacy",
+	"GET /user/my/groups":             "whatsapp_list_groups",
+	"GET /user/my/newsletters":        "whatsapp_list_newsletters",
+	"GET /user/my/contacts":           "whatsapp_list_contacts",
+	"GET /user/check":                 "whatsapp_user_check",
+	"GET /user/business-profile":      "whatsapp_user_business_profile",
+	"GET /user/presence":              "whatsapp_user_presence",
+	"POST /user/presence/subscribe":   "whatsapp_user_presence_subscribe",
+	"POST /user/presence/unsubscribe": "whatsapp_user_presence_unsubscribe",
+
+	"POST /group":                              "whatsapp_group_create",
+	"POST /group/join-with-link":               "whatsapp_group_join_via_link",
+	"GET /group/info-from-link":                "whatsapp_group_info_from_link",
+	"GET /group/info":                          "whatsapp_group_info",
+	"POST /group/leave":                        "whatsapp_group_leave",
+	"GET /group/participants":                  "whatsapp_group_participants",
+	"GET /group/participants/export":           "whatsapp_group_participants",
+	"POST /group/participants":                 "whatsapp_group_manage_participants",
+	"POST /group/participants/remove":          "whatsapp_group_manage_participants",
+	"POST /group/participants/promote":         "whatsapp_group_manage_participants",
+	"POST /group/participants/demote":          "whatsapp_group_manage_participants",
+	"POST /group/participants/reconcile":       "whatsapp_group_reconcile_participants",
+	"GET /group/participant-requests":          "whatsapp_group_join_requests",
+	"POST /group/participant-requests/approve": "whatsapp_group_manage_join_requests",
+	"POST /group/participant-requests/reject":  "whatsapp_group_manage_join_requests",
+	"POST /group/photo":                        "whatsapp_group_set_photo",
+	"POST /group/name":                         "whatsapp_group_set_name",
+	"POST /group/locked":                       "whatsapp_group_set_locked",
+	"POST /group/announce":                     "whatsapp_group_set_announce",
+	"POST /group/topic":                        "whatsapp_group_set_topic",
+	"GET /group/invite-link":                   "whatsapp_group_invite_link",
+	"GET /group/history":                       "whatsapp_group_history",
+	"GET /group/history/export":                "whatsapp_group_history",
+
+	"POST /community":               "whatsapp_community_create",
+	"GET /community/info":           "whatsapp_community_info",
+	"GET /community/groups":         "whatsapp_community_groups",
+	"POST /community/groups/link":   "whatsapp_community_link_group",
+	"POST /community/groups/unlink": "whatsapp_community_unlink_group",
+	"GET /community/participants":   "whatsapp_community_participants",
+	"POST /community/announcement":  "whatsapp_send_community_announcement",
+
+	"POST /newsletter":          "whatsapp_newsletter_create",
+	"POST /newsletter/update":   "whatsapp_newsletter_update",
+	"POST /newsletter/follow":   "whatsapp_newsletter_follow",
+	"POST /newsletter/unfollow": "whatsapp_newsletter_unfollow",
+	"GET /newsletter/info":      "whatsapp_newsletter_info",
+	"GET /newsletter/messages":  "whatsapp_newsletter_messages",
+	"POST /newsletter/publish":  "whatsapp_send_newsletter_post",
+	"POST /newsletter/viewed":   "whatsapp_newsletter_mark_viewed",
+
+	"POST /campaigns":                        "whatsapp_campaign_create",
+	"GET /campaigns":                         "whatsapp_campaign_list",
+	"GET /campaigns/:campaign_id":            "whatsapp_campaign_get",
+	"GET /campaigns/:campaign_id/recipients": "whatsapp_campaign_recipients",
+	"POST /campaigns/:campaign_id/pause":     "whatsapp_campaign_pause",
+	"POST /campaigns/:campaign_id/resume":    "whatsapp_campaign_resume",
+	"POST /campaigns/:campaign_id/cancel":    "whatsapp_campaign_cancel",
+
+	"GET /webhooks":                                "whatsapp_webhook_list",
+	"POST /webhooks":                               "whatsapp_webhook_create",
+	"GET /webhooks/:webhook_id":                    "whatsapp_webhook_get",
+	"PUT /webhooks/:webhook_id":                    "whatsapp_webhook_update",
+	"DELETE /webhooks/:webhook_id":                 "whatsapp_webhook_delete",
+	"GET /webhook/deliveries":                      "whatsapp_webhook_deliveries",
+	"DELETE /webhook/deliveries":                   "whatsapp_webhook_purge_deliveries",
+	"GET /webhook/deliveries/:delivery_id":         "whatsapp_webhook_delivery",
+	"POST /webhook/deliveries/:delivery_id/replay": "whatsapp_webhook_replay_delivery",
+
+	"GET /auto-reply/rules":             "whatsapp_auto_reply_list",
+	"POST /auto-reply/rules":            "whatsapp_auto_reply_create",
+	"PUT /auto-reply/rules":             "whatsapp_auto_reply_replace",
+	"GET /auto-reply/rules/:rule_id":    "whatsapp_auto_reply_get",
+	"PUT /auto-reply/rules/:rule_id":    "whatsapp_auto_reply_update",
+	"DELETE /auto-reply/rules/:rule_id": "whatsapp_auto_reply_delete",
+
+	"GET /api-keys":            "whatsapp_api_key_list",
+	"POST /api-keys":           "whatsapp_api_key_create",
+	"DELETE /api-keys/:key_id": "whatsapp_api_key_delete",
+	"GET /audit":               "whatsapp_audit_log",
+}
+
+// restOnlyRoutes are the REST routes without an MCP tool, with the reason why
+var restOnlyRoutes = map[string]string{
+	"GET /events": "server-sent event stream, MCP clients get notifications instead",
+}
+
+func TestRestRoutesHaveMcpTools(t *testing.T) {
+	app := fiber.New()
+	rest.InitRestApp(app, nil)
+	rest.InitRestChat(app, nil)
+	rest.InitRestSend(app, nil)
+	rest.InitRestUser(app, nil)
+	rest.InitRestMessage(app, nil)
+	rest.InitRestGroup(app, nil)
+	rest.InitRestNewsletter(app, nil)
+	rest.InitRestCommunity(app, nil)
+	rest.InitRestDevice(app, nil)
+	rest.InitRestWebhook(app, nil)
+	rest.InitRestCampaign(app, nil)
+	rest.InitRestAutoReply(app, nil)
+	rest.InitRestAPIKey(app, nil)
+	rest.InitRestCall(app, nil)
+	rest.InitRestPoll(app, nil)
+	rest.InitRestEvents(app)
+
+	mcpServer := server.NewMCPServer("test", "0.0.0", server.WithToolCapabilities(true))
+	InitMcpSend(nil).AddSendTools(mcpServer)
+	InitMcpQuery(nil, nil, nil, nil, nil).AddQueryTools(mcpServer)
+	InitMcpApp(nil).AddAppTools(mcpServer)
+	InitMcpGroup(nil).AddGroupTools(mcpServer)
+	InitMcpCommunity(nil).AddCommunityTools(mcpServer)
+	InitMcpNewsletter(nil).AddNewsletterTools(mcpServer)
+	InitMcpDevice(nil).AddDeviceTools(mcpServer)
+	InitMcpMessage(nil, nil).AddMessageTools(mcpServer)
+	InitMcpUser(nil).AddUserTools(mcpServer)
+	InitMcpCampaign(nil).AddCampaignTools(mcpServer)
+	InitMcpWebhook(nil).AddWebhookTools(mcpServer)
+	InitMcpAutoReply(nil).AddAutoReplyTools(mcpServer)
+	InitMcpAPIKey(nil).AddAPIKeyTools(mcpServer)
+
+	seen := make(map[string]bool)
+	for _, route := range app.GetRoutes(true) {
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+		key := route.Method + " " + route.Path
+		seen[key] = true
+
+		if _, ok := restOnlyRoutes[key]; ok {
+			continue
+		}
+		tool, ok := restTools[key]
+		if !ok {
+			t.Errorf("REST route %s has no MCP tool, add one and map it in restTools", key)
+			continue
+		}
+		if mcpServer.GetTool(tool) == nil {
+			t.Errorf("REST route %s maps to MCP tool %s, which is not registered", key, tool)
+			continue
+		}
+		if toolScope, routeScope := ToolScope(tool), middleware.RouteScope(route.Method, route.Path); toolScope != routeScope {
+			t.Errorf("MCP tool %s requires the %s scope, but REST route %s requires %s", tool, toolScope, key, routeScope)
+		}
+	}
+
+	for key := range restTools {
+		if !seen[key] {
+			t.Errorf("restTools maps %s, which is not a REST route", key)
+		}
+	}
+	for key := range restOnlyRoutes {
+		if !seen[key] {
+			t.Errorf("restOnlyRoutes lists %s, which is not a REST route", key)
+		}
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"strconv"
 	"strings"
 
+	domainCall "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/call"
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+	domainPoll "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/poll"
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -18,21 +20,31 @@ type QueryHandler struct {
 	chatService    domainChat.IChatUsecase
 	userService    domainUser.IUserUsecase
 	messageService domainMessage.IMessageUsecase
+	pollService    domainPoll.IPollUsecase
+	callService    domainCall.ICallUsecase
 }
 
-func InitMcpQuery(chatService domainChat.IChatUsecase, userService domainUser.IUserUsecase, messageService domainMessage.IMessageUsecase) *QueryHandler {
+func InitMcpQuery(chatService domainChat.IChatUsecase, userService domainUser.IUserUsecase, messageService domainMessage.IMessageUsecase, pollService domainPoll.IPollUsecase, callService domainCall.ICallUsecase) *QueryHandler {
 	return &QueryHandler{
 		chatService:    chatService,
 		userService:    userService,
 		messageService: messageService,
+		pollService:    pollService,
+		callService:    callService,
 	}
 }
 
 func (h *QueryHandler) AddQueryTools(mcpServer *server.MCPServer) {
 	mcpServer.AddTool(h.toolListContacts(), h.handleListContacts)
+	mcpServer.AddTool(h.toolListGroups(), h.handleListGroups)
+	mcpServer.AddTool(h.toolListNewsletters(), h.handleListNewsletters)
 	mcpServer.AddTool(h.toolListChats(), h.handleListChats)
 	mcpServer.AddTool(h.toolGetChatMessages(), h.handleGetChatMessages)
+	mcpServer.AddTool(h.toolSearchMessages(), h.handleSearchMessages)
 	mcpServer.AddTool(h.toolDownloadMedia(), h.handleDownloadMedia)
+	mcpServer.AddTool(h.toolGetMessageStatus(), h.handleGetMessageStatus)
+	mcpServer.AddTool(h.toolGetPollResults(), h.handleGetPollResults)
+	mcpServer.AddTool(h.toolListCalls(), h.handleListCalls)
 }
 
 func (h *QueryHandler) toolListContacts() mcp.Tool {
@@ -56,6 +68,48 @@ func (h *QueryHandler) handleListContacts(ctx context.Context, request mcp.CallT
 	return mcp.NewToolResultStructured(resp, fallback), nil
 }
 
+func (h *QueryHandler) toolListGroups() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_list_groups",
+		mcp.WithDescription("Retrieve the groups the connected WhatsApp account is a member of."),
+		mcp.WithTitleAnnotation("List Groups"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *QueryHandler) handleListGroups(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resp, err := h.userService.MyListGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Found %d groups", len(resp.Data))
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *QueryHandler) toolListNewsletters() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_list_newsletters",
+		mcp.WithDescription("Retrieve the channels (newsletters) the connected WhatsApp account follows or administers."),
+		mcp.WithTitleAnnotation("List Channels"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *QueryHandler) handleListNewsletters(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resp, err := h.userService.MyListNewsletter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Found %d channels", len(resp.Data))
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
 func (h *QueryHandler) toolListChats() mcp.Tool {
 	return mcp.NewTool(
 		"whatsapp_list_chats",
@@ -119,7 +173,7 @@ func (h *QueryHandler) handleListChats(ctx context.Context, request mcp.CallTool
 func (h *QueryHandler) toolGetChatMessages() mcp.Tool {
 	return mcp.NewTool(
 		"whatsapp_get_chat_messages",
-		mcp.WithDescription("Fetch messages from a specific chat, with optional pagination, search, and time filters."),
+		mcp.WithDescription("Fetch messages from a specific chat, with optional pagination, search, and time filters. Messages include their edit history, current reactions and whether they were deleted for everyone."),
 		mcp.WithTitleAnnotation("Get Chat Messages"),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithDestructiveHintAnnotation(false),
@@ -221,6 +275,111 @@ func (h *QueryHandler) handleGetChatMessages(ctx context.Context, request mcp.Ca
 	return mcp.NewToolResultStructured(resp, fallback), nil
 }
 
+func (h *QueryHandler) toolSearchMessages() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_search_messages",
+		mcp.WithDescription("Full-text search across all stored chats, ordered by relevance, with highlighted snippets."),
+		mcp.WithTitleAnnotation("Search Messages"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("query",
+			mcp.Description(`Search query. Supports "exact phrases", prefix* matches and AND / OR / NOT.`),
+			mcp.Required(),
+		),
+		mcp.WithString("chat_jid",
+			mcp.Description("Restrict the search to one chat JID."),
+		),
+		mcp.WithString("sender",
+			mcp.Description("Restrict the search to a sender phone number or JID."),
+		),
+		mcp.WithString("start_time",
+			mcp.Description("Only messages sent after this RFC3339 timestamp."),
+		),
+		mcp.WithString("end_time",
+			mcp.Description("Only messages sent before this RFC3339 timestamp."),
+		),
+		mcp.WithString("media_type",
+			mcp.Description("Only messages with this media type."),
+			mcp.Enum("image", "video", "audio", "document", "sticker"),
+		),
+		mcp.WithBoolean("media_only",
+			mcp.Description("If true, return only messages containing media."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("is_from_me",
+			mcp.Description("If provided, filter messages sent by you (true) or others (false)."),
+		),
+		mcp.WithString("sort",
+			mcp.Description("Result ordering (default relevance)."),
+			mcp.Enum("relevance", "newest"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of messages to return (default 25, max 100)."),
+			mcp.DefaultNumber(25),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of messages to skip from the start (default 0)."),
+			mcp.DefaultNumber(0),
+		),
+	)
+}
+
+func (h *QueryHandler) handleSearchMessages(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := request.RequireString("query")
+	if err != nil {
+		return nil, err
+	}
+
+	req := domainChat.SearchMessagesRequest{
+		Query:     query,
+		ChatJID:   strings.TrimSpace(request.GetString("chat_jid", "")),
+		Sender:    strings.TrimSpace(request.GetString("sender", "")),
+		MediaType: request.GetString("media_type", ""),
+		Sort:      request.GetString("sort", ""),
+		Limit:     request.GetInt("limit", 25),
+		Offset:    request.GetInt("offset", 0),
+	}
+
+	if startTime := strings.TrimSpace(request.GetString("start_time", "")); startTime != "" {
+		req.StartTime = &startTime
+	}
+	if endTime := strings.TrimSpace(request.GetString("end_time", "")); endTime != "" {
+		req.EndTime = &endTime
+	}
+
+	args := request.GetArguments()
+	if args != nil {
+		if value, ok := args["media_only"]; ok {
+			parsed, err := toBool(value)
+			if err != nil {
+				return nil, err
+			}
+			req.MediaOnly = parsed
+		}
+		if value, ok := args["is_from_me"]; ok {
+			parsed, err := toBool(value)
+			if err != nil {
+				return nil, err
+			}
+			req.IsFromMe = &parsed
+		}
+	}
+
+	resp, err := h.chatService.SearchMessages(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf(
+		"Found %d messages matching %q (showing %d)",
+		resp.Pagination.Total,
+		query,
+		len(resp.Data),
+	)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
 func (h *QueryHandler) toolDownloadMedia() mcp.Tool {
 	return mcp.NewTool(
 		"whatsapp_download_message_media",
@@ -267,6 +426,107 @@ func (h *QueryHandler) handleDownloadMedia(ctx context.Context, request mcp.Call
 	return mcp.NewToolResultStructured(resp, fallback), nil
 }
 
+func (h *QueryHandler) toolGetMessageStatus() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_get_message_status",
+		mcp.WithDescription("Get the delivery status of a sent message (sent, server_ack, delivered, read or played), with the status per participant for groups."),
+		mcp.WithTitleAnnotation("Get Message Status"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("message_id",
+			mcp.Description("The WhatsApp message ID returned when the message was sent."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *QueryHandler) handleGetMessageStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	messageID, err := request.RequireString("message_id")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.messageService.GetMessageStatus(ctx, domainMessage.MessageStatusRequest{MessageID: messageID})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Message %s is %s (%d participants)", resp.MessageID, resp.Status, len(resp.Participants))
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *QueryHandler) toolGetPollResults() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_get_poll_results",
+		mcp.WithDescription("Get the results of a poll: the votes per option and the options each voter picked."),
+		mcp.WithTitleAnnotation("Get Poll Results"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("message_id",
+			mcp.Description("The WhatsApp message ID of the poll."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *QueryHandler) handleGetPollResults(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	messageID, err := request.RequireString("message_id")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.pollService.GetPollResults(ctx, domainPoll.PollResultsRequest{MessageID: messageID})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Poll %q has %d voters", resp.Question, resp.TotalVoters)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *QueryHandler) toolListCalls() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_list_calls",
+		mcp.WithDescription("Retrieve the call log, newest first, with the outcome of every call."),
+		mcp.WithTitleAnnotation("List Calls"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("phone",
+			mcp.Description("Only list calls with this phone number or group ID."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of calls to return (default 50)."),
+			mcp.DefaultNumber(50),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of calls to skip from the start (default 0)."),
+			mcp.DefaultNumber(0),
+		),
+	)
+}
+
+func (h *QueryHandler) handleListCalls(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	phone := strings.TrimSpace(request.GetString("phone", ""))
+	if phone != "" {
+		utils.SanitizePhone(&phone)
+	}
+
+	resp, err := h.callService.ListCalls(ctx, domainCall.ListCallsRequest{
+		Phone:  phone,
+		Limit:  request.GetInt("limit", 50),
+		Offset: request.GetInt("offset", 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Retrieved %d calls (offset %d, limit %d)", len(resp.Data), resp.Offset, resp.Limit)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
 func toBool(value any) (bool, error) {
 	switch v := value.(type) {
 	case bool:
@@ -0,0 +1,341 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	resourceScheme = "whatsapp"
+	chatsURI       = "whatsapp://chats"
+	contactsURI    = "whatsapp://contacts"
+	groupsURI      = "whatsapp://groups"
+
+	// resourcePageMax is the largest page of contacts or groups, which are paged after loading them all
+	resourcePageMax = 100
+)
+
+// chatMessagesURI is the resource holding the message history of a chat
+func chatMessagesURI(chatJID string) string {
+	return fmt.Sprintf("%s://chat/%s/messages", resourceScheme, chatJID)
+}
+
+type ResourceHandler struct {
+	chatService    domainChat.IChatUsecase
+	userService    domainUser.IUserUsecase
+	groupService   domainGroup.IGroupUsecase
+	messageService domainMessage.IMessageUsecase
+}
+
+func InitMcpResource(chatService domainChat.IChatUsecase, userService domainUser.IUserUsecase, groupService domainGroup.IGroupUsecase, messageService domainMessage.IMessageUsecase) *ResourceHandler {
+	return &ResourceHandler{
+		chatService:    chatService,
+		userService:    userService,
+		groupService:   groupService,
+		messageService: messageService,
+	}
+}
+
+// AddResources exposes chats, messages, contacts, groups and media as resources. Listings take limit and
+// offset query params and link the next page in next_uri. Subscribers of a chat's messages, and of the
+// chat list, are notified when a message arrives.
+func (h *ResourceHandler) AddResources(mcpServer *server.MCPServer) {
+	mcpServer.AddResource(mcp.NewResource(chatsURI, "Chats",
+		mcp.WithResourceDescription("Recent chats."),
+		mcp.WithMIMEType("application/json"),
+	), h.readChats)
+	mcpServer.AddResource(mcp.NewResource(contactsURI, "Contacts",
+		mcp.WithResourceDescription("The contacts of the account."),
+		mcp.WithMIMEType("application/json"),
+	), h.readContacts)
+	mcpServer.AddResource(mcp.NewResource(groupsURI, "Groups",
+		mcp.WithResourceDescription("The groups the account is a member of."),
+		mcp.WithMIMEType("application/json"),
+	), h.readGroups)
+
+	mcpServer.AddResourceTemplate(mcp.NewResourceTemplate("whatsapp://chats{?limit,offset,search}", "Chats",
+		mcp.WithTemplateDescription("A page of recent chats, optionally filtered by name (default limit 25, max 100)."),
+		mcp.WithTemplateMIMEType("application/json"),
+	), h.readChats)
+	mcpServer.AddResourceTemplate(mcp.NewResourceTemplate("whatsapp://chat/{+jid}/messages{?limit,offset,search,media_only}", "Chat Messages",
+		mcp.WithTemplateDescription("A page of the message history of a chat (default limit 50, max 100). Subscribe to be notified of new messages."),
+		mcp.WithTemplateMIMEType("application/json"),
+	), h.readChatMessages)
+	mcpServer.AddResourceTemplate(mcp.NewResourceTemplate("whatsapp://contacts{?limit,offset}", "Contacts",
+		mcp.WithTemplateDescription("A page of the contacts of the account (default limit 100, max 100)."),
+		mcp.WithTemplateMIMEType("application/json"),
+	), h.readContacts)
+	mcpServer.AddResourceTemplate(mcp.NewResourceTemplate("whatsapp://groups{?limit,offset}", "Groups",
+		mcp.WithTemplateDescription("A page of the groups the account is a member of (default limit 100, max 100)."),
+		mcp.WithTemplateMIMEType("application/json"),
+	), h.readGroups)
+	mcpServer.AddResourceTemplate(mcp.NewResourceTemplate("whatsapp://group/{+jid}", "Group",
+		mcp.WithTemplateDescription("A group with its participants and community."),
+		mcp.WithTemplateMIMEType("application/json"),
+	), h.readGroup)
+	mcpServer.AddResourceTemplate(mcp.NewResourceTemplate("whatsapp://media/{+jid}/{message_id}", "Message Media",
+		mcp.WithTemplateDescription("The image, video, audio, document or sticker of a message in a chat."),
+	), h.readMedia)
+
+	subscriptions.bind(mcpServer)
+}
+
+func (h *ResourceHandler) readChats(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	_, query, err := parseResourceURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	req := domainChat.ListChatsRequest{Search: query.Get("search")}
+	if req.Limit, err = queryInt(query, "limit", 25); err != nil {
+		return nil, err
+	}
+	if req.Offset, err = queryInt(query, "offset", 0); err != nil {
+		return nil, err
+	}
+
+	resp, err := h.chatService.ListChats(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonResource(request.Params.URI, resp, nextPageURI(chatsURI, query, resp.Pagination))
+}
+
+func (h *ResourceHandler) readChatMessages(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	segments, query, err := parseResourceURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) != 3 || segments[2] != "messages" {
+		return nil, fmt.Errorf("%s is not a chat messages resource", request.Params.URI)
+	}
+
+	req := domainChat.GetChatMessagesRequest{ChatJID: segments[1], Search: query.Get("search")}
+	if req.Limit, err = queryInt(query, "limit", 50); err != nil {
+		return nil, err
+	}
+	if req.Offset, err = queryInt(query, "offset", 0); err != nil {
+		return nil, err
+	}
+	if value := query.Get("media_only"); value != "" {
+		if req.MediaOnly, err = strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("media_only must be true or false")
+		}
+	}
+
+	resp, err := h.chatService.GetChatMessages(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonResource(request.Params.URI, resp, nextPageURI(chatMessagesURI(req.ChatJID), query, resp.Pagination))
+}
+
+func (h *ResourceHandler) readContacts(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	_, query, err := parseResourceURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.userService.MyListContacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts, pagination, err := pageOf(resp.Data, query)
+	if err != nil {
+		return nil, err
+	}
+	page := map[string]any{"data": contacts, "pagination": pagination}
+	return jsonResource(request.Params.URI, page, nextPageURI(contactsURI, query, pagination))
+}
+
+func (h *ResourceHandler) readGroups(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	_, query, err := parseResourceURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.userService.MyListGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, pagination, err := pageOf(resp.Data, query)
+	if err != nil {
+		return nil, err
+	}
+	page := map[string]any{"data": groups, "pagination": pagination}
+	return jsonResource(request.Params.URI, page, nextPageURI(groupsURI, query, pagination))
+}
+
+func (h *ResourceHandler) readGroup(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	segments, _, err := parseResourceURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) != 2 {
+		return nil, fmt.Errorf("%s is not a group resource", request.Params.URI)
+	}
+
+	resp, err := h.groupService.GroupInfo(ctx, domainGroup.GroupInfoRequest{GroupID: segments[1]})
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonResource(request.Params.URI, resp.Data, "")
+}
+
+func (h *ResourceHandler) readMedia(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	segments, _, err := parseResourceURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) != 3 {
+		return nil, fmt.Errorf("%s is not a media resource", request.Params.URI)
+	}
+
+	resp, err := h.messageService.DownloadMedia(ctx, domainMessage.DownloadMediaRequest{
+		MessageID: segments[2],
+		Phone:     segments[1],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(resp.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read media of message %s: %w", resp.MessageID, err)
+	}
+
+	mimeType := mime.TypeByExtension(filepath.Ext(resp.FilePath))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+
+	return []mcp.ResourceContents{mcp.BlobResourceContents{
+		URI:      request.Params.URI,
+		MIMEType: mimeType,
+		Blob:     base64.StdEncoding.EncodeToString(data),
+	}}, nil
+}
+
+// parseResourceURI splits a whatsapp:// URI into its host and path segments, e.g. chat, the JID and messages,
+// and its query params
+func parseResourceURI(uri string) (segments []string, query url.Values, err error) {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != resourceScheme || parsed.Host == "" {
+		return nil, nil, fmt.Errorf("%q is not a %s:// resource URI", uri, resourceScheme)
+	}
+
+	segments = []string{parsed.Host}
+	for _, segment := range strings.Split(parsed.Path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments, parsed.Query(), nil
+}
+
+// resourceKey identifies the resource of uri regardless of its encoding and query params, which select a page
+func resourceKey(uri string) string {
+	segments, _, err := parseResourceURI(uri)
+	if err != nil {
+		return uri
+	}
+	return fmt.Sprintf("%s://%s", resourceScheme, strings.Join(segments, "/"))
+}
+
+func queryInt(query url.Values, name string, defaultValue int) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number", name)
+	}
+	return parsed, nil
+}
+
+// pageOf returns the page of items selected by the limit and offset query params
+func pageOf[T any](items []T, query url.Values) ([]T, domainChat.PaginationResponse, error) {
+	pagination := domainChat.PaginationResponse{Total: len(items)}
+
+	var err error
+	if pagination.Limit, err = queryInt(query, "limit", resourcePageMax); err != nil {
+		return nil, pagination, err
+	}
+	if pagination.Offset, err = queryInt(query, "offset", 0); err != nil {
+		return nil, pagination, err
+	}
+	if pagination.Limit < 1 || pagination.Limit > resourcePageMax {
+		return nil, pagination, fmt.Errorf("limit must be between 1 and %d", resourcePageMax)
+	}
+	if pagination.Offset < 0 {
+		return nil, pagination, fmt.Errorf("offset must not be negative")
+	}
+
+	start := min(pagination.Offset, len(items))
+	end := min(start+pagination.Limit, len(items))
+	return items[start:end], pagination, nil
+}
+
+// nextPageURI links the page after the one described by pagination, keeping the other query params.
+// It is empty on the last page.
+func nextPageURI(base string, query url.Values, pagination domainChat.PaginationResponse) string {
+	if pagination.Limit <= 0 || pagination.Offset+pagination.Limit >= pagination.Total {
+		return ""
+	}
+
+	next := url.Values{}
+	for name, values := range query {
+		next[name] = values
+	}
+	next.Set("limit", strconv.Itoa(pagination.Limit))
+	next.Set("offset", strconv.Itoa(pagination.Offset+pagination.Limit))
+	return base + "?" + next.Encode()
+}
+
+// jsonResource encodes value as the JSON contents of uri, adding next_uri when there is a next page
+func jsonResource(uri string, value any, nextURI string) ([]mcp.ResourceContents, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if nextURI != "" {
+		var fields map[string]any
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+		fields["next_uri"] = nextURI
+		if data, err = json.Marshal(fields); err != nil {
+			return nil, err
+		}
+	}
+
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      uri,
+		MIMEType: "application/json",
+		Text:     string(data),
+	}}, nil
+}
@@ -0,0 +1,216 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+const (
+	methodResourcesSubscribe   = "resources/subscribe"
+	methodResourcesUnsubscribe = "resources/unsubscribe"
+
+	// stdioSessionID is the session the MCP library gives the stdio client
+	stdioSessionID = "stdio"
+)
+
+// resourceSubscriptions tracks the resources each session subscribed to. The MCP library advertises
+// subscriptions but does not route resources/subscribe and resources/unsubscribe, so the transports
+// pass every request through intercept before the server sees it.
+type resourceSubscriptions struct {
+	mu       sync.RWMutex
+	server   *server.MCPServer
+	sessions map[string]map[string]string // session ID -> subscribed URI -> resource key
+	listen   sync.Once
+}
+
+var subscriptions = newResourceSubscriptions()
+
+func newResourceSubscriptions() *resourceSubscriptions {
+	return &resourceSubscriptions{sessions: make(map[string]map[string]string)}
+}
+
+// ResourceServerOptions drop the subscriptions of the sessions that end
+func ResourceServerOptions() []server.ServerOption {
+	hooks := &server.Hooks{}
+	hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+		subscriptions.removeSession(session.SessionID())
+	})
+	return []server.ServerOption{server.WithHooks(hooks)}
+}
+
+// SubscriptionHandler lets SSE and streamable HTTP clients subscribe to resources. It runs after AuthHandler,
+// subscribing needs the same read:chats scope as reading the resources.
+func SubscriptionHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// SSE clients post to the message endpoint with the session in the query, streamable HTTP clients send a header
+		sessionID := r.URL.Query().Get("sessionId")
+		if sessionID == "" {
+			sessionID = r.Header.Get(server.HeaderKeySessionID)
+		}
+		if r.Method != http.MethodPost || sessionID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		_ = r.Body.Close()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		body, err = subscriptions.intercept(r.Context(), sessionID, body)
+		if err != nil {
+			writeAuthError(w, err)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SubscriptionReader lets the stdio client subscribe to resources by passing the messages read from stdin
+// through intercept
+func SubscriptionReader(stdin io.Reader) io.Reader {
+	reader, writer := io.Pipe()
+	go func() {
+		buffered := bufio.NewReader(stdin)
+		for {
+			line, readErr := buffered.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) > 0 {
+				message, _ := subscriptions.intercept(context.Background(), stdioSessionID, bytes.TrimSpace(line))
+				if _, err := writer.Write(append(message, '\n')); err != nil {
+					return
+				}
+			}
+			if readErr != nil {
+				_ = writer.CloseWithError(readErr)
+				return
+			}
+		}
+	}()
+	return reader
+}
+
+// bind sends the notifications of mcpServer and starts listening for incoming messages
+func (s *resourceSubscriptions) bind(mcpServer *server.MCPServer) {
+	s.mu.Lock()
+	s.server = mcpServer
+	s.mu.Unlock()
+
+	s.listen.Do(func() {
+		whatsapp.AddMessageListener(s.messageReceived)
+	})
+}
+
+// intercept records a resources/subscribe or resources/unsubscribe request of session and returns it rewritten
+// to a ping, whose empty result is the answer the client expects. Other messages are returned unchanged.
+func (s *resourceSubscriptions) intercept(ctx context.Context, sessionID string, message []byte) ([]byte, error) {
+	var request struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params struct {
+			URI string `json:"uri"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(message, &request); err != nil || len(request.ID) == 0 {
+		return message, nil
+	}
+	if request.Method != methodResourcesSubscribe && request.Method != methodResourcesUnsubscribe {
+		return message, nil
+	}
+	// Unknown URIs are left to the server, which answers that the method does not exist
+	if _, _, err := parseResourceURI(request.Params.URI); err != nil {
+		return message, nil
+	}
+
+	if principal, ok := domainAPIKey.PrincipalFromContext(ctx); ok && !principal.HasScope(domainAPIKey.ScopeReadChats) {
+		return nil, pkgError.ForbiddenError(fmt.Sprintf("API key %s is missing the %s scope", principal.Name, domainAPIKey.ScopeReadChats))
+	}
+
+	if request.Method == methodResourcesSubscribe {
+		s.subscribe(sessionID, request.Params.URI)
+	} else {
+		s.unsubscribe(sessionID, request.Params.URI)
+	}
+
+	return json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      request.ID,
+		"method":  string(mcp.MethodPing),
+	})
+}
+
+func (s *resourceSubscriptions) subscribe(sessionID string, uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sessions[sessionID] == nil {
+		s.sessions[sessionID] = make(map[string]string)
+	}
+	s.sessions[sessionID][uri] = resourceKey(uri)
+}
+
+func (s *resourceSubscriptions) unsubscribe(sessionID string, uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions[sessionID], uri)
+	if len(s.sessions[sessionID]) == 0 {
+		delete(s.sessions, sessionID)
+	}
+}
+
+func (s *resourceSubscriptions) removeSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionID)
+}
+
+// messageReceived notifies the subscribers of the chat's messages and of the chat list, which is ordered by
+// the last message
+func (s *resourceSubscriptions) messageReceived(_ context.Context, evt *events.Message) {
+	s.notify(resourceKey(chatMessagesURI(evt.Info.Chat.String())), resourceKey(chatsURI))
+}
+
+// notify sends resources/updated to every session subscribed to one of keys, with the URI it subscribed to
+func (s *resourceSubscriptions) notify(keys ...string) {
+	s.mu.RLock()
+	mcpServer := s.server
+	var updates [][2]string
+	for sessionID, uris := range s.sessions {
+		for uri, key := range uris {
+			for _, updated := range keys {
+				if key == updated {
+					updates = append(updates, [2]string{sessionID, uri})
+				}
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	if mcpServer == nil {
+		return
+	}
+	for _, update := range updates {
+		err := mcpServer.SendNotificationToSpecificClient(update[0], mcp.MethodNotificationResourceUpdated, map[string]any{"uri": update[1]})
+		if err != nil {
+			logrus.Debugf("[MCP] Failed to notify session %s of an update to %s: %v", update[0], update[1], err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+type testSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testSession) SessionID() string                                   { return s.id }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifications }
+func (s *testSession) Initialize()                                         {}
+func (s *testSession) Initialized() bool                                   { return true }
+
+func TestParseResourceURI(t *testing.T) {
+	segments, query, err := parseResourceURI("whatsapp://chat/628111%40s.whatsapp.net/messages?limit=10&offset=20")
+	if err != nil {
+		t.Fatalf("parseResourceURI() error = %v", err)
+	}
+	if len(segments) != 3 || segments[0] != "chat" || segments[1] != "628111@s.whatsapp.net" || segments[2] != "messages" {
+		t.Fatalf("segments = %v", segments)
+	}
+	if query.Get("limit") != "10" || query.Get("offset") != "20" {
+		t.Fatalf("query = %v", query)
+	}
+
+	for _, uri := range []string{"https://chats", "whatsapp:chats", "chats"} {
+		if _, _, err := parseResourceURI(uri); err == nil {
+			t.Errorf("parseResourceURI(%q) accepted a URI of another scheme", uri)
+		}
+	}
+
+	if got, want := resourceKey("whatsapp://chat/628111%40s.whatsapp.net/messages?limit=10"), chatMessagesURI("628111@s.whatsapp.net"); got != want {
+		t.Fatalf("resourceKey() = %q, want %q", got, want)
+	}
+}
+
+func TestPageOf(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	page, pagination, err := pageOf(items, url.Values{"limit": {"2"}, "offset": {"2"}})
+	if err != nil {
+		t.Fatalf("pageOf() error = %v", err)
+	}
+	if len(page) != 2 || page[0] != 3 || pagination.Total != 5 {
+		t.Fatalf("pageOf() = %v, %+v", page, pagination)
+	}
+	if got, want := nextPageURI(contactsURI, url.Values{"limit": {"2"}, "offset": {"2"}}, pagination), "whatsapp://contacts?limit=2&offset=4"; got != want {
+		t.Fatalf("nextPageURI() = %q, want %q", got, want)
+	}
+
+	page, pagination, err = pageOf(items, url.Values{"offset": {"4"}})
+	if err != nil || len(page) != 1 {
+		t.Fatalf("pageOf() = %v, %v", page, err)
+	}
+	if next := nextPageURI(contactsURI, nil, pagination); next != "" {
+		t.Fatalf("nextPageURI() on the last page = %q", next)
+	}
+
+	if _, _, err := pageOf(items, url.Values{"limit": {"0"}}); err == nil {
+		t.Fatal("pageOf() accepted a zero limit")
+	}
+	if _, _, err := pageOf(items, url.Values{"offset": {"x"}}); err == nil {
+		t.Fatal("pageOf() accepted an offset that is not a number")
+	}
+	if next := nextPageURI(chatsURI, url.Values{"search": {"budi"}}, domainChat.PaginationResponse{Limit: 25, Total: 30}); next != "whatsapp://chats?limit=25&offset=25&search=budi" {
+		t.Fatalf("nextPageURI() = %q", next)
+	}
+}
+
+func TestResourceSubscriptions(t *testing.T) {
+	subs := newResourceSubscriptions()
+	mcpServer := server.NewMCPServer("test", "0.0.0", server.WithResourceCapabilities(true, true))
+	session := &testSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 10)}
+	if err := mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+	subs.mu.Lock()
+	subs.server = mcpServer
+	subs.mu.Unlock()
+
+	subscribe := []byte(`{"jsonrpc":"2.0","id":7,"method":"resources/subscribe","params":{"uri":"whatsapp://chat/628111%40s.whatsapp.net/messages?limit=10"}}`)
+	rewritten, err := subs.intercept(context.Background(), session.id, subscribe)
+	if err != nil {
+		t.Fatalf("intercept() error = %v", err)
+	}
+	var ping struct {
+		ID     int    `json:"id"`
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(rewritten, &ping); err != nil || ping.ID != 7 || ping.Method != string(mcp.MethodPing) {
+		t.Fatalf("intercept() = %s, want a ping with the request ID", rewritten)
+	}
+
+	other := []byte(`{"jsonrpc":"2.0","id":8,"method":"resources/read","params":{"uri":"whatsapp://chats"}}`)
+	if unchanged, _ := subs.intercept(context.Background(), session.id, other); string(unchanged) != string(other) {
+		t.Fatalf("intercept() changed a resources/read request to %s", unchanged)
+	}
+
+	message := func(chat string) *events.Message {
+		evt := &events.Message{}
+		evt.Info.Chat = types.NewJID(chat, types.DefaultUserServer)
+		return evt
+	}
+
+	subs.messageReceived(context.Background(), message("628222"))
+	subs.messageReceived(context.Background(), message("628111"))
+	select {
+	case notification := <-session.notifications:
+		if notification.Method != mcp.MethodNotificationResourceUpdated {
+			t.Fatalf("notification method = %s", notification.Method)
+		}
+		if uri := notification.Params.AdditionalFields["uri"]; uri != "whatsapp://chat/628111%40s.whatsapp.net/messages?limit=10" {
+			t.Fatalf("notification uri = %v, want the subscribed URI", uri)
+		}
+	default:
+		t.Fatal("no notification for a message in the subscribed chat")
+	}
+	if len(session.notifications) != 0 {
+		t.Fatalf("got %d notifications for other chats", len(session.notifications))
+	}
+
+	unsubscribe := []byte(`{"jsonrpc":"2.0","id":9,"method":"resources/unsubscribe","params":{"uri":"whatsapp://chat/628111%40s.whatsapp.net/messages?limit=10"}}`)
+	if _, err := subs.intercept(context.Background(), session.id, unsubscribe); err != nil {
+		t.Fatal(err)
+	}
+	subs.messageReceived(context.Background(), message("628111"))
+	if len(session.notifications) != 0 {
+		t.Fatal("notified after unsubscribing")
+	}
+
+	ctx := domainAPIKey.ContextWithPrincipal(context.Background(), &domainAPIKey.Principal{Name: "sender", Scopes: []string{domainAPIKey.ScopeSend}})
+	if _, err := subs.intercept(ctx, session.id, subscribe); err == nil {
+		t.Fatal("intercept() let a key without the read:chats scope subscribe")
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -27,6 +31,16 @@ func (s *SendHandler) AddSendTools(mcpServer *server.MCPServer) {
 	mcpServer.AddTool(s.toolSendLocation(), s.handleSendLocation)
 	mcpServer.AddTool(s.toolSendImage(), s.handleSendImage)
 	mcpServer.AddTool(s.toolSendSticker(), s.handleSendSticker)
+	mcpServer.AddTool(s.toolSendFile(), s.handleSendFile)
+	mcpServer.AddTool(s.toolSendVideo(), s.handleSendVideo)
+	mcpServer.AddTool(s.toolSendAudio(), s.handleSendAudio)
+	mcpServer.AddTool(s.toolSendPoll(), s.handleSendPoll)
+	mcpServer.AddTool(s.toolSendPresence(), s.handleSendPresence)
+	mcpServer.AddTool(s.toolSendChatPresence(), s.handleSendChatPresence)
+	mcpServer.AddTool(s.toolListScheduled(), s.handleListScheduled)
+	mcpServer.AddTool(s.toolGetScheduled(), s.handleGetScheduled)
+	mcpServer.AddTool(s.toolReschedule(), s.handleReschedule)
+	mcpServer.AddTool(s.toolCancelScheduled(), s.handleCancelScheduled)
 }
 
 func (s *SendHandler) toolSendText() mcp.Tool {
@@ -397,3 +411,430 @@ func (s *SendHandler) handleSendSticker(ctx context.Context, request mcp.CallToo
 
 	return mcp.NewToolResultText(fmt.Sprintf("Sticker sent successfully with ID %s", res.MessageID)), nil
 }
+
+// sendToolOptions are the arguments of the send tools next to their own: the recipient, forwarding and scheduling
+func sendToolOptions(subject string, options ...mcp.ToolOption) []mcp.ToolOption {
+	return append([]mcp.ToolOption{
+		mcp.WithString("phone",
+			mcp.Required(),
+			mcp.Description(fmt.Sprintf("Phone number or group ID to send %s to", subject)),
+		),
+		mcp.WithBoolean("is_forwarded",
+			mcp.Description("Whether this message is being forwarded (default: false)"),
+		),
+		mcp.WithString("send_at",
+			mcp.Description("Schedule the message for this time (RFC3339) instead of sending it right away"),
+		),
+		mcp.WithNumber("delay_seconds",
+			mcp.Description("Schedule the message this many seconds from now instead of sending it right away"),
+		),
+	}, options...)
+}
+
+func sendBaseRequest(request mcp.CallToolRequest) (domainSend.BaseRequest, error) {
+	phone, err := request.RequireString("phone")
+	if err != nil {
+		return domainSend.BaseRequest{}, err
+	}
+	phone = strings.TrimSpace(phone)
+	utils.SanitizePhone(&phone)
+
+	base := domainSend.BaseRequest{
+		Phone:        phone,
+		IsForwarded:  request.GetBool("is_forwarded", false),
+		DelaySeconds: request.GetInt("delay_seconds", 0),
+	}
+	if sendAt := request.GetString("send_at", ""); sendAt != "" {
+		base.SendAt = &sendAt
+	}
+	return base, nil
+}
+
+// sendResult reports a sent or scheduled message
+func sendResult(subject string, res domainSend.GenericResponse) *mcp.CallToolResult {
+	fallback := fmt.Sprintf("%s sent successfully with ID %s", subject, res.MessageID)
+	if res.ScheduleID != "" && res.SendAt != nil {
+		fallback = fmt.Sprintf("%s scheduled for %s with schedule ID %s", subject, res.SendAt.Format(time.RFC3339), res.ScheduleID)
+	}
+	return mcp.NewToolResultStructured(res, fallback)
+}
+
+func (s *SendHandler) toolSendFile() mcp.Tool {
+	return mcp.NewTool("whatsapp_send_file", sendToolOptions("file",
+		mcp.WithDescription("Send a document downloaded from a URL to a WhatsApp contact or group."),
+		mcp.WithTitleAnnotation("Send File"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("file_url",
+			mcp.Required(),
+			mcp.Description("URL of the file to send, its name is taken from the URL path"),
+		),
+		mcp.WithString("caption",
+			mcp.Description("Caption for the file"),
+		),
+	)...)
+}
+
+func (s *SendHandler) handleSendFile(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	base, err := sendBaseRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	fileURL, err := request.RequireString("file_url")
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := downloadFile(ctx, fileURL, config.WhatsappSettingMaxFileSize)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.sendService.SendFile(ctx, domainSend.FileRequest{
+		BaseRequest: base,
+		File:        file,
+		Caption:     request.GetString("caption", ""),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sendResult("File", res), nil
+}
+
+func (s *SendHandler) toolSendVideo() mcp.Tool {
+	return mcp.NewTool("whatsapp_send_video", sendToolOptions("video",
+		mcp.WithDescription("Send a video from a URL to a WhatsApp contact or group."),
+		mcp.WithTitleAnnotation("Send Video"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("video_url",
+			mcp.Required(),
+			mcp.Description("URL of the video to send"),
+		),
+		mcp.WithString("caption",
+			mcp.Description("Caption for the video"),
+		),
+		mcp.WithBoolean("view_once",
+			mcp.Description("Whether this video should be viewed only once (default: false)"),
+		),
+		mcp.WithBoolean("compress",
+			mcp.Description("Whether to compress the video (default: false)"),
+		),
+	)...)
+}
+
+func (s *SendHandler) handleSendVideo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	base, err := sendBaseRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	videoURL, err := request.RequireString("video_url")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.sendService.SendVideo(ctx, domainSend.VideoRequest{
+		BaseRequest: base,
+		Caption:     request.GetString("caption", ""),
+		ViewOnce:    request.GetBool("view_once", false),
+		Compress:    request.GetBool("compress", false),
+		VideoURL:    &videoURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sendResult("Video", res), nil
+}
+
+func (s *SendHandler) toolSendAudio() mcp.Tool {
+	return mcp.NewTool("whatsapp_send_audio", sendToolOptions("audio",
+		mcp.WithDescription("Send an audio file from a URL to a WhatsApp contact or group."),
+		mcp.WithTitleAnnotation("Send Audio"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("audio_url",
+			mcp.Required(),
+			mcp.Description("URL of the audio file to send"),
+		),
+	)...)
+}
+
+func (s *SendHandler) handleSendAudio(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	base, err := sendBaseRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	audioURL, err := request.RequireString("audio_url")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.sendService.SendAudio(ctx, domainSend.AudioRequest{
+		BaseRequest: base,
+		AudioURL:    &audioURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sendResult("Audio", res), nil
+}
+
+func (s *SendHandler) toolSendPoll() mcp.Tool {
+	return mcp.NewTool("whatsapp_send_poll", sendToolOptions("poll",
+		mcp.WithDescription("Send a poll to a WhatsApp contact or group."),
+		mcp.WithTitleAnnotation("Send Poll"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("question",
+			mcp.Required(),
+			mcp.Description("The poll question"),
+		),
+		mcp.WithArray("options",
+			mcp.Required(),
+			mcp.Description("The answers to choose from, each unique"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithNumber("max_answer",
+			mcp.Description("How many options a voter may pick (default: 1)"),
+			mcp.DefaultNumber(1),
+		),
+	)...)
+}
+
+func (s *SendHandler) handleSendPoll(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	base, err := sendBaseRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := request.RequireString("question")
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := toStringSlice("options", request.GetArguments()["options"])
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.sendService.SendPoll(ctx, domainSend.PollRequest{
+		BaseRequest: base,
+		Question:    question,
+		Options:     options,
+		MaxAnswer:   request.GetInt("max_answer", 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sendResult("Poll", res), nil
+}
+
+func (s *SendHandler) toolSendPresence() mcp.Tool {
+	return mcp.NewTool("whatsapp_send_presence",
+		mcp.WithDescription("Set whether this account shows as available (online) or unavailable to contacts."),
+		mcp.WithTitleAnnotation("Send Presence"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("type",
+			mcp.Required(),
+			mcp.Description("Presence to show"),
+			mcp.Enum("available", "unavailable"),
+		),
+	)
+}
+
+func (s *SendHandler) handleSendPresence(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	presenceType, err := request.RequireString("type")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.sendService.SendPresence(ctx, domainSend.PresenceRequest{Type: presenceType})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(res, res.Status), nil
+}
+
+func (s *SendHandler) toolSendChatPresence() mcp.Tool {
+	return mcp.NewTool("whatsapp_send_chat_presence", sendToolOptions("the typing indicator",
+		mcp.WithDescription("Start or stop showing the typing indicator in a chat."),
+		mcp.WithTitleAnnotation("Send Chat Presence"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("action",
+			mcp.Required(),
+			mcp.Description("Whether to start or stop typing"),
+			mcp.Enum("start", "stop"),
+		),
+	)...)
+}
+
+func (s *SendHandler) handleSendChatPresence(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	base, err := sendBaseRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	action, err := request.RequireString("action")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.sendService.SendChatPresence(ctx, domainSend.ChatPresenceRequest{
+		BaseRequest: base,
+		Phone:       base.Phone,
+		Action:      action,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(res, res.Status), nil
+}
+
+func (s *SendHandler) toolListScheduled() mcp.Tool {
+	return mcp.NewTool("whatsapp_scheduled_list",
+		mcp.WithDescription("List the messages scheduled with send_at or delay_seconds, newest first."),
+		mcp.WithTitleAnnotation("List Scheduled Messages"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("status",
+			mcp.Description("Only list messages with this status"),
+			mcp.Enum("pending", "sending", "sent", "failed", "cancelled"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of messages to return (default 25)"),
+			mcp.DefaultNumber(25),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of messages to skip (default 0)"),
+			mcp.DefaultNumber(0),
+		),
+	)
+}
+
+func (s *SendHandler) handleListScheduled(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	res, err := s.sendService.ListScheduled(ctx, domainSend.ListScheduledRequest{
+		Status: request.GetString("status", ""),
+		Limit:  request.GetInt("limit", 25),
+		Offset: request.GetInt("offset", 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(res, fmt.Sprintf("Found %d scheduled messages", len(res.Data))), nil
+}
+
+func (s *SendHandler) toolGetScheduled() mcp.Tool {
+	return mcp.NewTool("whatsapp_scheduled_get",
+		mcp.WithDescription("Get a scheduled message with its status, attempts and the ID of the sent message."),
+		mcp.WithTitleAnnotation("Get Scheduled Message"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("schedule_id",
+			mcp.Required(),
+			mcp.Description("The schedule ID returned when the message was scheduled"),
+		),
+	)
+}
+
+func (s *SendHandler) handleGetScheduled(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	scheduleID, err := request.RequireString("schedule_id")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.sendService.GetScheduled(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(res, fmt.Sprintf("Scheduled %s to %s is %s", res.Type, res.Phone, res.Status)), nil
+}
+
+func (s *SendHandler) toolReschedule() mcp.Tool {
+	return mcp.NewTool("whatsapp_scheduled_reschedule",
+		mcp.WithDescription("Move a pending scheduled message to another time."),
+		mcp.WithTitleAnnotation("Reschedule Message"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("schedule_id",
+			mcp.Required(),
+			mcp.Description("The schedule ID of the pending message"),
+		),
+		mcp.WithString("send_at",
+			mcp.Description("New time to send the message (RFC3339)"),
+		),
+		mcp.WithNumber("delay_seconds",
+			mcp.Description("Send the message this many seconds from now instead"),
+		),
+	)
+}
+
+func (s *SendHandler) handleReschedule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	scheduleID, err := request.RequireString("schedule_id")
+	if err != nil {
+		return nil, err
+	}
+
+	req := domainSend.RescheduleRequest{
+		ScheduleID:   scheduleID,
+		DelaySeconds: request.GetInt("delay_seconds", 0),
+	}
+	if sendAt := request.GetString("send_at", ""); sendAt != "" {
+		req.SendAt = &sendAt
+	}
+
+	res, err := s.sendService.Reschedule(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(res, fmt.Sprintf("Message rescheduled for %s", res.SendAt.Format(time.RFC3339))), nil
+}
+
+func (s *SendHandler) toolCancelScheduled() mcp.Tool {
+	return mcp.NewTool("whatsapp_scheduled_cancel",
+		mcp.WithDescription("Cancel a pending scheduled message so it is never sent."),
+		mcp.WithTitleAnnotation("Cancel Scheduled Message"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("schedule_id",
+			mcp.Required(),
+			mcp.Description("The schedule ID of the pending message"),
+		),
+	)
+}
+
+func (s *SendHandler) handleCancelScheduled(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	scheduleID, err := request.RequireString("schedule_id")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sendService.CancelScheduled(ctx, scheduleID); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Scheduled message %s cancelled", scheduleID)), nil
+}
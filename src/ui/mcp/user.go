@@ -0,0 +1,350 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type UserHandler struct {
+	userService domainUser.IUserUsecase
+}
+
+func InitMcpUser(userService domainUser.IUserUsecase) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+func (h *UserHandler) AddUserTools(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(h.toolUserInfo(), h.handleUserInfo)
+	mcpServer.AddTool(h.toolUserAvatar(), h.handleUserAvatar)
+	mcpServer.AddTool(h.toolUserCheck(), h.handleUserCheck)
+	mcpServer.AddTool(h.toolBusinessProfile(), h.handleBusinessProfile)
+	mcpServer.AddTool(h.toolMyPrivacy(), h.handleMyPrivacy)
+	mcpServer.AddTool(h.toolPresence(), h.handlePresence)
+	mcpServer.AddTool(h.toolSubscribePresence(), h.handleSubscribePresence)
+	mcpServer.AddTool(h.toolUnsubscribePresence(), h.handleUnsubscribePresence)
+	mcpServer.AddTool(h.toolSetAvatar(), h.handleSetAvatar)
+	mcpServer.AddTool(h.toolSetPushName(), h.handleSetPushName)
+}
+
+// requirePhone returns the sanitized phone argument
+func requirePhone(request mcp.CallToolRequest) (string, error) {
+	phone, err := request.RequireString("phone")
+	if err != nil {
+		return "", err
+	}
+	phone = strings.TrimSpace(phone)
+	utils.SanitizePhone(&phone)
+	return phone, nil
+}
+
+func (h *UserHandler) toolUserInfo() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_user_info",
+		mcp.WithDescription("Get the WhatsApp profile of a phone number: verified name, status, picture ID and devices."),
+		mcp.WithTitleAnnotation("Get User Info"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("phone",
+			mcp.Description("Phone number with country code, e.g. 628123456789."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *UserHandler) handleUserInfo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	phone, err := requirePhone(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.userService.Info(ctx, domainUser.InfoRequest{Phone: phone})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No WhatsApp profile found for %s", phone)), nil
+	}
+
+	info := resp.Data[0]
+	fallback := fmt.Sprintf("%s has status %q and %d devices", phone, info.Status, len(info.Devices))
+	return mcp.NewToolResultStructured(info, fallback), nil
+}
+
+func (h *UserHandler) toolUserAvatar() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_user_avatar",
+		mcp.WithDescription("Get the URL of the profile picture of a contact, group or community."),
+		mcp.WithTitleAnnotation("Get Avatar"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("phone",
+			mcp.Description("Phone number, group ID or community ID."),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("is_preview",
+			mcp.Description("Return the low resolution preview instead of the full picture (default: false)."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("is_community",
+			mcp.Description("Whether phone is a community ID (default: false)."),
+			mcp.DefaultBool(false),
+		),
+	)
+}
+
+func (h *UserHandler) handleUserAvatar(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	phone, err := requirePhone(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.userService.Avatar(ctx, domainUser.AvatarRequest{
+		Phone:       phone,
+		IsPreview:   request.GetBool("is_preview", false),
+		IsCommunity: request.GetBool("is_community", false),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Avatar of %s: %s", phone, resp.URL)), nil
+}
+
+func (h *UserHandler) toolUserCheck() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_user_check",
+		mcp.WithDescription("Check whether a phone number is registered on WhatsApp."),
+		mcp.WithTitleAnnotation("Check User"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("phone",
+			mcp.Description("Phone number with country code, e.g. 628123456789."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *UserHandler) handleUserCheck(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	phone, err := request.RequireString("phone")
+	if err != nil {
+		return nil, err
+	}
+	phone = strings.TrimSpace(phone)
+
+	resp, err := h.userService.IsOnWhatsApp(ctx, domainUser.CheckRequest{Phone: phone})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("%s is not on WhatsApp", phone)
+	if resp.IsOnWhatsApp {
+		fallback = fmt.Sprintf("%s is on WhatsApp", phone)
+	}
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *UserHandler) toolBusinessProfile() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_user_business_profile",
+		mcp.WithDescription("Get the business profile of a WhatsApp Business account: email, address, categories and opening hours."),
+		mcp.WithTitleAnnotation("Get Business Profile"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("phone",
+			mcp.Description("Phone number of the business account."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *UserHandler) handleBusinessProfile(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	phone, err := requirePhone(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.userService.BusinessProfile(ctx, domainUser.BusinessProfileRequest{Phone: phone})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Business profile of %s with %d categories", resp.JID, len(resp.Categories))), nil
+}
+
+func (h *UserHandler) toolMyPrivacy() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_user_privacy",
+		mcp.WithDescription("Get the privacy settings of this account: who can add it to groups and see its last seen, status, profile picture and read receipts."),
+		mcp.WithTitleAnnotation("Get Privacy Settings"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *UserHandler) handleMyPrivacy(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resp, err := h.userService.MyPrivacySetting(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Group add: %s, last seen: %s, status: %s, profile: %s, read receipts: %s",
+		resp.GroupAdd, resp.LastSeen, resp.Status, resp.Profile, resp.ReadReceipts)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *UserHandler) toolPresence() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_user_presence",
+		mcp.WithDescription("Get the last known presence (online, last seen, typing) of a contact, or of every known contact when phone is omitted."),
+		mcp.WithTitleAnnotation("Get Presence"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("phone",
+			mcp.Description("Phone number of the contact (optional)."),
+		),
+	)
+}
+
+func (h *UserHandler) handlePresence(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	phone := strings.TrimSpace(request.GetString("phone", ""))
+	if phone != "" {
+		utils.SanitizePhone(&phone)
+	}
+
+	resp, err := h.userService.Presence(ctx, domainUser.PresenceRequest{Phone: phone})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Found the presence of %d contacts", len(resp.Data))), nil
+}
+
+func (h *UserHandler) toolSubscribePresence() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_user_presence_subscribe",
+		mcp.WithDescription("Subscribe to the presence of a contact, so its online status and last seen are tracked."),
+		mcp.WithTitleAnnotation("Subscribe to Presence"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("phone",
+			mcp.Description("Phone number of the contact."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *UserHandler) handleSubscribePresence(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	phone, err := requirePhone(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.userService.SubscribePresence(ctx, domainUser.PresenceRequest{Phone: phone}); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Subscribed to the presence of %s", phone)), nil
+}
+
+func (h *UserHandler) toolUnsubscribePresence() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_user_presence_unsubscribe",
+		mcp.WithDescription("Stop tracking the presence of a contact."),
+		mcp.WithTitleAnnotation("Unsubscribe from Presence"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("phone",
+			mcp.Description("Phone number of the contact."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *UserHandler) handleUnsubscribePresence(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	phone, err := requirePhone(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.userService.UnsubscribePresence(ctx, domainUser.PresenceRequest{Phone: phone}); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Unsubscribed from the presence of %s", phone)), nil
+}
+
+func (h *UserHandler) toolSetAvatar() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_user_set_avatar",
+		mcp.WithDescription("Change the profile picture of this account to an image downloaded from a URL."),
+		mcp.WithTitleAnnotation("Set Avatar"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("avatar_url",
+			mcp.Description("URL of a JPEG or PNG image."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *UserHandler) handleSetAvatar(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	avatarURL, err := request.RequireString("avatar_url")
+	if err != nil {
+		return nil, err
+	}
+
+	avatar, err := downloadFile(ctx, avatarURL, config.WhatsappSettingMaxImageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.userService.ChangeAvatar(ctx, domainUser.ChangeAvatarRequest{Avatar: avatar}); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText("Avatar changed"), nil
+}
+
+func (h *UserHandler) toolSetPushName() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_user_set_push_name",
+		mcp.WithDescription("Change the display name contacts see for this account."),
+		mcp.WithTitleAnnotation("Set Push Name"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("push_name",
+			mcp.Description("The new display name."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *UserHandler) handleSetPushName(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	pushName, err := request.RequireString("push_name")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.userService.ChangePushName(ctx, domainUser.ChangePushNameRequest{PushName: pushName}); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Push name changed to %s", pushName)), nil
+}
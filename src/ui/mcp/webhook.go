@@ -0,0 +1,376 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type WebhookHandler struct {
+	webhookService domainWebhook.IWebhookUsecase
+}
+
+func InitMcpWebhook(webhookService domainWebhook.IWebhookUsecase) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+func (h *WebhookHandler) AddWebhookTools(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(h.toolListWebhooks(), h.handleListWebhooks)
+	mcpServer.AddTool(h.toolGetWebhook(), h.handleGetWebhook)
+	mcpServer.AddTool(h.toolCreateWebhook(), h.handleCreateWebhook)
+	mcpServer.AddTool(h.toolUpdateWebhook(), h.handleUpdateWebhook)
+	mcpServer.AddTool(h.toolDeleteWebhook(), h.handleDeleteWebhook)
+	mcpServer.AddTool(h.toolListDeliveries(), h.handleListDeliveries)
+	mcpServer.AddTool(h.toolGetDelivery(), h.handleGetDelivery)
+	mcpServer.AddTool(h.toolReplayDelivery(), h.handleReplayDelivery)
+	mcpServer.AddTool(h.toolPurgeDeliveries(), h.handlePurgeDeliveries)
+}
+
+// webhookToolOptions are the settings of a webhook, as taken by the create and update tools
+func webhookToolOptions(options ...mcp.ToolOption) []mcp.ToolOption {
+	return append(options,
+		mcp.WithString("url",
+			mcp.Description("URL the events are posted to."),
+			mcp.Required(),
+		),
+		mcp.WithString("secret",
+			mcp.Description("Secret used to sign the payloads in the X-Hub-Signature-256 header."),
+		),
+		mcp.WithString(deviceIDArgument,
+			mcp.Description("Only deliver the events of this device."),
+		),
+		mcp.WithArray("events",
+			mcp.Description("Event types to deliver, all when omitted."),
+			mcp.WithStringItems(mcp.Enum(domainWebhook.EventTypes...)),
+		),
+		mcp.WithArray("allow_jids",
+			mcp.Description("Only deliver events of these chats."),
+			mcp.WithStringItems(),
+		),
+		mcp.WithArray("deny_jids",
+			mcp.Description("Never deliver events of these chats."),
+			mcp.WithStringItems(),
+		),
+		mcp.WithBoolean("skip_from_me",
+			mcp.Description("Skip the messages sent by this account (default: false)."),
+		),
+		mcp.WithString("chat_type",
+			mcp.Description("Only deliver events of groups or direct messages (default: all)."),
+			mcp.Enum("all", "group", "dm"),
+		),
+		mcp.WithBoolean("include_media",
+			mcp.Description("Download media and include it in the payload (default: true)."),
+		),
+		mcp.WithBoolean("enabled",
+			mcp.Description("Whether events are delivered (default: true)."),
+		),
+	)
+}
+
+func webhookRequest(request mcp.CallToolRequest) (domainWebhook.WebhookRequest, error) {
+	webhookURL, err := request.RequireString("url")
+	if err != nil {
+		return domainWebhook.WebhookRequest{}, err
+	}
+
+	req := domainWebhook.WebhookRequest{
+		URL:        strings.TrimSpace(webhookURL),
+		Secret:     request.GetString("secret", ""),
+		DeviceID:   strings.TrimSpace(request.GetString(deviceIDArgument, "")),
+		SkipFromMe: request.GetBool("skip_from_me", false),
+		ChatType:   request.GetString("chat_type", ""),
+	}
+	args := request.GetArguments()
+	if req.Events, err = toStringSlice("events", args["events"]); err != nil {
+		return req, err
+	}
+	if req.AllowJIDs, err = toStringSlice("allow_jids", args["allow_jids"]); err != nil {
+		return req, err
+	}
+	if req.DenyJIDs, err = toStringSlice("deny_jids", args["deny_jids"]); err != nil {
+		return req, err
+	}
+	if _, ok := args["include_media"]; ok {
+		includeMedia := request.GetBool("include_media", true)
+		req.IncludeMedia = &includeMedia
+	}
+	if _, ok := args["enabled"]; ok {
+		enabled := request.GetBool("enabled", true)
+		req.Enabled = &enabled
+	}
+	return req, nil
+}
+
+func webhookIDOption() mcp.ToolOption {
+	return mcp.WithString("webhook_id",
+		mcp.Description("The webhook ID."),
+		mcp.Required(),
+	)
+}
+
+func deliveryIDOption() mcp.ToolOption {
+	return mcp.WithNumber("delivery_id",
+		mcp.Description("The delivery ID."),
+		mcp.Required(),
+	)
+}
+
+func requireDeliveryID(request mcp.CallToolRequest) (int64, error) {
+	deliveryID, err := request.RequireInt("delivery_id")
+	if err != nil {
+		return 0, err
+	}
+	if deliveryID <= 0 {
+		return 0, fmt.Errorf("delivery_id must be a positive number")
+	}
+	return int64(deliveryID), nil
+}
+
+func (h *WebhookHandler) toolListWebhooks() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_webhook_list",
+		mcp.WithDescription("List the webhooks events are delivered to, with their filters."),
+		mcp.WithTitleAnnotation("List Webhooks"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *WebhookHandler) handleListWebhooks(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	webhooks, err := h.webhookService.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(map[string]any{"webhooks": webhooks}, fmt.Sprintf("Found %d webhooks", len(webhooks))), nil
+}
+
+func (h *WebhookHandler) toolGetWebhook() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_webhook_get",
+		mcp.WithDescription("Get a webhook with its filters."),
+		mcp.WithTitleAnnotation("Get Webhook"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		webhookIDOption(),
+	)
+}
+
+func (h *WebhookHandler) handleGetWebhook(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	webhookID, err := request.RequireString("webhook_id")
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := h.webhookService.GetWebhook(ctx, strings.TrimSpace(webhookID))
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(webhook, fmt.Sprintf("Webhook %s posts to %s", webhook.ID, webhook.URL)), nil
+}
+
+func (h *WebhookHandler) toolCreateWebhook() mcp.Tool {
+	return mcp.NewTool("whatsapp_webhook_create", webhookToolOptions(
+		mcp.WithDescription("Add a webhook that receives the events matching its filters."),
+		mcp.WithTitleAnnotation("Create Webhook"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+	)...)
+}
+
+func (h *WebhookHandler) handleCreateWebhook(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	req, err := webhookRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(webhook, fmt.Sprintf("Created webhook %s posting to %s", webhook.ID, webhook.URL)), nil
+}
+
+func (h *WebhookHandler) toolUpdateWebhook() mcp.Tool {
+	return mcp.NewTool("whatsapp_webhook_update", webhookToolOptions(
+		mcp.WithDescription("Replace the settings of a webhook. Omitted filters are cleared."),
+		mcp.WithTitleAnnotation("Update Webhook"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		webhookIDOption(),
+	)...)
+}
+
+func (h *WebhookHandler) handleUpdateWebhook(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	webhookID, err := request.RequireString("webhook_id")
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := webhookRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	req.ID = strings.TrimSpace(webhookID)
+
+	webhook, err := h.webhookService.UpdateWebhook(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(webhook, fmt.Sprintf("Updated webhook %s", webhook.ID)), nil
+}
+
+func (h *WebhookHandler) toolDeleteWebhook() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_webhook_delete",
+		mcp.WithDescription("Delete a webhook."),
+		mcp.WithTitleAnnotation("Delete Webhook"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		webhookIDOption(),
+	)
+}
+
+func (h *WebhookHandler) handleDeleteWebhook(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	webhookID, err := request.RequireString("webhook_id")
+	if err != nil {
+		return nil, err
+	}
+
+	webhookID = strings.TrimSpace(webhookID)
+	if err := h.webhookService.DeleteWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Deleted webhook %s", webhookID)), nil
+}
+
+func (h *WebhookHandler) toolListDeliveries() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_webhook_deliveries",
+		mcp.WithDescription("List the queued, delivered and dead webhook deliveries, newest first."),
+		mcp.WithTitleAnnotation("List Webhook Deliveries"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("status",
+			mcp.Description("Only list deliveries with this status."),
+			mcp.Enum("pending", "delivering", "delivered", "dead"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of deliveries to return (default 25, max 100)."),
+			mcp.DefaultNumber(25),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of deliveries to skip (default 0)."),
+			mcp.DefaultNumber(0),
+		),
+	)
+}
+
+func (h *WebhookHandler) handleListDeliveries(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resp, err := h.webhookService.ListDeliveries(ctx, domainWebhook.ListDeliveriesRequest{
+		Status: request.GetString("status", ""),
+		Limit:  request.GetInt("limit", 25),
+		Offset: request.GetInt("offset", 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Found %d deliveries", len(resp.Data))), nil
+}
+
+func (h *WebhookHandler) toolGetDelivery() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_webhook_delivery",
+		mcp.WithDescription("Get a webhook delivery with its payload, attempts and last error."),
+		mcp.WithTitleAnnotation("Get Webhook Delivery"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		deliveryIDOption(),
+	)
+}
+
+func (h *WebhookHandler) handleGetDelivery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	deliveryID, err := requireDeliveryID(request)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := h.webhookService.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Delivery %d of %s to %s is %s after %d attempts", delivery.ID, delivery.Event, delivery.URL, delivery.Status, delivery.Attempts)
+	return mcp.NewToolResultStructured(delivery, fallback), nil
+}
+
+func (h *WebhookHandler) toolReplayDelivery() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_webhook_replay_delivery",
+		mcp.WithDescription("Queue a webhook delivery to be sent again, e.g. a dead one after the receiver was fixed."),
+		mcp.WithTitleAnnotation("Replay Webhook Delivery"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		deliveryIDOption(),
+	)
+}
+
+func (h *WebhookHandler) handleReplayDelivery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	deliveryID, err := requireDeliveryID(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.webhookService.ReplayDelivery(ctx, deliveryID); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Webhook delivery %d queued for replay", deliveryID)), nil
+}
+
+func (h *WebhookHandler) toolPurgeDeliveries() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_webhook_purge_deliveries",
+		mcp.WithDescription("Delete every delivered or dead webhook delivery."),
+		mcp.WithTitleAnnotation("Purge Webhook Deliveries"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("status",
+			mcp.Description("Status of the deliveries to delete."),
+			mcp.Required(),
+			mcp.Enum("delivered", "dead"),
+		),
+	)
+}
+
+func (h *WebhookHandler) handlePurgeDeliveries(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	status, err := request.RequireString("status")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.webhookService.PurgeDeliveries(ctx, domainWebhook.PurgeDeliveriesRequest{Status: status})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Purged %d %s webhook deliveries", resp.Deleted, resp.Status)), nil
+}
@@ -0,0 +1,75 @@
+package rest
+
+import (
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type APIKey struct {
+	Service domainAPIKey.IAPIKeyUsecase
+}
+
+func InitRestAPIKey(app fiber.Router, service domainAPIKey.IAPIKeyUsecase) APIKey {
+	rest := APIKey{Service: service}
+	app.Get("/api-keys", rest.ListKeys)
+	app.Post("/api-keys", rest.CreateKey)
+	app.Delete("/api-keys/:key_id", rest.DeleteKey)
+	app.Get("/audit", rest.ListAudit)
+	return rest
+}
+
+func (controller *APIKey) ListKeys(c *fiber.Ctx) error {
+	response, err := controller.Service.ListKeys(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get API keys",
+		Results: response,
+	})
+}
+
+func (controller *APIKey) CreateKey(c *fiber.Ctx) error {
+	var request domainAPIKey.CreateKeyRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.CreateKey(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success create API key, store it now as it cannot be shown again",
+		Results: response,
+	})
+}
+
+func (controller *APIKey) DeleteKey(c *fiber.Ctx) error {
+	err := controller.Service.DeleteKey(c.UserContext(), c.Params("key_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success revoke API key",
+	})
+}
+
+func (controller *APIKey) ListAudit(c *fiber.Ctx) error {
+	var request domainAPIKey.ListAuditRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.ListAudit(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get audit log",
+		Results: response,
+	})
+}
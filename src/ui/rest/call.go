@@ -0,0 +1,35 @@
+package rest
+
+import (
+	domainCall "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/call"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Call struct {
+	Service domainCall.ICallUsecase
+}
+
+func InitRestCall(app fiber.Router, service domainCall.ICallUsecase) Call {
+	rest := Call{Service: service}
+	app.Get("/calls", rest.ListCalls)
+	return rest
+}
+
+func (controller *Call) ListCalls(c *fiber.Ctx) error {
+	var request domainCall.ListCallsRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.ListCalls(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get call log",
+		Results: response,
+	})
+}
@@ -0,0 +1,143 @@
+package rest
+
+import (
+	"encoding/json"
+
+	domainCampaign "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/campaign"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Campaign struct {
+	Service domainCampaign.ICampaignUsecase
+}
+
+func InitRestCampaign(app fiber.Router, service domainCampaign.ICampaignUsecase) Campaign {
+	rest := Campaign{Service: service}
+	app.Post("/campaigns", rest.CreateCampaign)
+	app.Get("/campaigns", rest.ListCampaigns)
+	app.Get("/campaigns/:campaign_id", rest.GetCampaign)
+	app.Get("/campaigns/:campaign_id/recipients", rest.ListRecipients)
+	app.Post("/campaigns/:campaign_id/pause", rest.PauseCampaign)
+	app.Post("/campaigns/:campaign_id/resume", rest.ResumeCampaign)
+	app.Post("/campaigns/:campaign_id/cancel", rest.CancelCampaign)
+	return rest
+}
+
+func (controller *Campaign) CreateCampaign(c *fiber.Ctx) error {
+	var request domainCampaign.CreateCampaignRequest
+	request.ValidateNumbers = true
+
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	// Multipart requests can still pass the recipient list as a JSON encoded field
+	if recipients := c.FormValue("recipients"); recipients != "" && len(request.Recipients) == 0 {
+		if err := json.Unmarshal([]byte(recipients), &request.Recipients); err != nil {
+			utils.PanicIfNeeded(pkgError.ValidationError("recipients: must be a JSON array of recipients"))
+		}
+	}
+
+	if file, err := c.FormFile("image"); err == nil {
+		request.Image = file
+	}
+	if file, err := c.FormFile("recipients_file"); err == nil {
+		request.RecipientsFile = file
+	}
+
+	response, err := controller.Service.CreateCampaign(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success create campaign",
+		Results: response,
+	})
+}
+
+func (controller *Campaign) ListCampaigns(c *fiber.Ctx) error {
+	var request domainCampaign.ListCampaignsRequest
+
+	request.Status = c.Query("status", "")
+	request.Limit = c.QueryInt("limit", 25)
+	request.Offset = c.QueryInt("offset", 0)
+
+	response, err := controller.Service.ListCampaigns(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get campaigns",
+		Results: response,
+	})
+}
+
+func (controller *Campaign) GetCampaign(c *fiber.Ctx) error {
+	response, err := controller.Service.GetCampaign(c.UserContext(), c.Params("campaign_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get campaign",
+		Results: response,
+	})
+}
+
+func (controller *Campaign) ListRecipients(c *fiber.Ctx) error {
+	var request domainCampaign.ListRecipientsRequest
+
+	request.CampaignID = c.Params("campaign_id")
+	request.Status = c.Query("status", "")
+	request.Limit = c.QueryInt("limit", 100)
+	request.Offset = c.QueryInt("offset", 0)
+
+	response, err := controller.Service.ListRecipients(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get campaign recipients",
+		Results: response,
+	})
+}
+
+func (controller *Campaign) PauseCampaign(c *fiber.Ctx) error {
+	response, err := controller.Service.PauseCampaign(c.UserContext(), c.Params("campaign_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success pause campaign",
+		Results: response,
+	})
+}
+
+func (controller *Campaign) ResumeCampaign(c *fiber.Ctx) error {
+	response, err := controller.Service.ResumeCampaign(c.UserContext(), c.Params("campaign_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success resume campaign",
+		Results: response,
+	})
+}
+
+func (controller *Campaign) CancelCampaign(c *fiber.Ctx) error {
+	response, err := controller.Service.CancelCampaign(c.UserContext(), c.Params("campaign_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success cancel campaign",
+		Results: response,
+	})
+}
@@ -17,6 +17,7 @@ func InitRestChat(app fiber.Router, service domainChat.IChatUsecase) Chat {
 	app.Get("/chats", rest.ListChats)
 	app.Get("/chat/:chat_jid/messages", rest.GetChatMessages)
 	app.Post("/chat/:chat_jid/pin", rest.PinChat)
+	app.Get("/search/messages", rest.SearchMessages)
 
 	return rest
 }
@@ -78,6 +79,44 @@ func (controller *Chat) GetChatMessages(c *fiber.Ctx) error {
 	})
 }
 
+func (controller *Chat) SearchMessages(c *fiber.Ctx) error {
+	var request domainChat.SearchMessagesRequest
+
+	// Parse query parameters
+	request.Query = c.Query("q")
+	request.ChatJID = c.Query("chat_jid")
+	request.Sender = c.Query("sender")
+	request.MediaType = c.Query("media_type")
+	request.MediaOnly = c.QueryBool("media_only", false)
+	request.Sort = c.Query("sort")
+	request.Limit = c.QueryInt("limit", 25)
+	request.Offset = c.QueryInt("offset", 0)
+
+	// Parse time filters
+	if startTime := c.Query("start_time"); startTime != "" {
+		request.StartTime = &startTime
+	}
+	if endTime := c.Query("end_time"); endTime != "" {
+		request.EndTime = &endTime
+	}
+
+	// Parse is_from_me filter
+	if isFromMeStr := c.Query("is_from_me"); isFromMeStr != "" {
+		isFromMe := c.QueryBool("is_from_me")
+		request.IsFromMe = &isFromMe
+	}
+
+	response, err := controller.Service.SearchMessages(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success search messages",
+		Results: response,
+	})
+}
+
 func (controller *Chat) PinChat(c *fiber.Ctx) error {
 	var request domainChat.PinChatRequest
 
@@ -0,0 +1,141 @@
+package rest
+
+import (
+	domainCommunity "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/community"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Community struct {
+	Service domainCommunity.ICommunityUsecase
+}
+
+func InitRestCommunity(app fiber.Router, service domainCommunity.ICommunityUsecase) Community {
+	rest := Community{Service: service}
+	app.Post("/community", rest.CreateCommunity)
+	app.Get("/community/info", rest.CommunityInfo)
+	app.Get("/community/groups", rest.ListSubGroups)
+	app.Post("/community/groups/link", rest.LinkGroup)
+	app.Post("/community/groups/unlink", rest.UnlinkGroup)
+	app.Get("/community/participants", rest.ListParticipants)
+	app.Post("/community/announcement", rest.SendAnnouncement)
+	return rest
+}
+
+func (controller *Community) CreateCommunity(c *fiber.Ctx) error {
+	var request domainCommunity.CreateCommunityRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	result, err := controller.Service.CreateCommunity(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success create community " + result.Name,
+		Results: result,
+	})
+}
+
+func (controller *Community) CommunityInfo(c *fiber.Ctx) error {
+	var request domainCommunity.CommunityRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+	utils.SanitizePhone(&request.CommunityID)
+
+	result, err := controller.Service.CommunityInfo(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get community info",
+		Results: result,
+	})
+}
+
+func (controller *Community) ListSubGroups(c *fiber.Ctx) error {
+	var request domainCommunity.CommunityRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+	utils.SanitizePhone(&request.CommunityID)
+
+	result, err := controller.Service.ListSubGroups(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get community groups",
+		Results: result,
+	})
+}
+
+func (controller *Community) LinkGroup(c *fiber.Ctx) error {
+	var request domainCommunity.LinkGroupRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+	utils.SanitizePhone(&request.CommunityID)
+	utils.SanitizePhone(&request.GroupID)
+
+	err = controller.Service.LinkGroup(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success link group to community",
+	})
+}
+
+func (controller *Community) UnlinkGroup(c *fiber.Ctx) error {
+	var request domainCommunity.LinkGroupRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+	utils.SanitizePhone(&request.CommunityID)
+	utils.SanitizePhone(&request.GroupID)
+
+	err = controller.Service.UnlinkGroup(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success unlink group from community",
+	})
+}
+
+func (controller *Community) ListParticipants(c *fiber.Ctx) error {
+	var request domainCommunity.CommunityRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+	utils.SanitizePhone(&request.CommunityID)
+
+	result, err := controller.Service.ListParticipants(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get community participants",
+		Results: result,
+	})
+}
+
+func (controller *Community) SendAnnouncement(c *fiber.Ctx) error {
+	var request domainCommunity.AnnouncementRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+	utils.SanitizePhone(&request.CommunityID)
+
+	result, err := controller.Service.SendAnnouncement(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success send community announcement",
+		Results: result,
+	})
+}
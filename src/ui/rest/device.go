@@ -0,0 +1,90 @@
+package rest
+
+import (
+	domainDevice "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Device struct {
+	Service domainDevice.IDeviceUsecase
+}
+
+func InitRestDevice(app fiber.Router, service domainDevice.IDeviceUsecase) Device {
+	rest := Device{Service: service}
+	app.Get("/devices", rest.ListDevices)
+	app.Post("/devices", rest.AddDevice)
+	app.Get("/devices/:device_id", rest.GetDevice)
+	app.Put("/devices/:device_id/webhooks", rest.UpdateWebhooks)
+	app.Delete("/devices/:device_id", rest.RemoveDevice)
+	return rest
+}
+
+func (controller *Device) ListDevices(c *fiber.Ctx) error {
+	response, err := controller.Service.ListDevices(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get list devices",
+		Results: response,
+	})
+}
+
+func (controller *Device) AddDevice(c *fiber.Ctx) error {
+	var request domainDevice.AddDeviceRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.AddDevice(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success add device, login using /device/" + response.DeviceID + "/app/login",
+		Results: response,
+	})
+}
+
+func (controller *Device) GetDevice(c *fiber.Ctx) error {
+	response, err := controller.Service.GetDevice(c.UserContext(), c.Params("device_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get device",
+		Results: response,
+	})
+}
+
+func (controller *Device) UpdateWebhooks(c *fiber.Ctx) error {
+	var request domainDevice.UpdateWebhooksRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.DeviceID = c.Params("device_id")
+
+	response, err := controller.Service.UpdateWebhooks(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success update device webhooks",
+		Results: response,
+	})
+}
+
+func (controller *Device) RemoveDevice(c *fiber.Ctx) error {
+	err := controller.Service.RemoveDevice(c.UserContext(), c.Params("device_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success remove device",
+	})
+}
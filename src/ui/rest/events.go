@@ -0,0 +1,115 @@
+package rest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// eventStreamBuffer is the number of events queued per stream before it counts as too slow
+	eventStreamBuffer = 256
+	eventHeartbeat    = 15 * time.Second
+	eventRetry        = 3 * time.Second
+)
+
+type Events struct{}
+
+func InitRestEvents(app fiber.Router) Events {
+	rest := Events{}
+	app.Get("/events", rest.StreamEvents)
+	return rest
+}
+
+// StreamEvents streams the typed events as server-sent events. The types, chats and devices query params
+// filter the events, Last-Event-ID (or the last_event_id query param) replays the logged events after it.
+func (controller *Events) StreamEvents(c *fiber.Ctx) error {
+	filter := websocket.EventFilter{
+		Topics:  queryList(c, "types"),
+		Chats:   queryList(c, "chats"),
+		Devices: queryList(c, "devices"),
+	}
+
+	var cursor *uint64
+	lastEventID := c.Get("Last-Event-ID", c.Query("last_event_id"))
+	if lastEventID != "" {
+		id, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Last-Event-ID must be an event id")
+		}
+		cursor = &id
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		subscription, replay, gap := websocket.SubscribeEvents(filter, cursor, eventStreamBuffer)
+		defer subscription.Close()
+
+		fmt.Fprintf(w, "retry: %d\n\n", eventRetry.Milliseconds())
+		if gap {
+			fmt.Fprintf(w, "event: gap\ndata: {\"message\":\"some events after %d are no longer logged\"}\n\n", *cursor)
+		}
+		for _, event := range replay {
+			writeStreamEvent(w, event)
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(eventHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case event, ok := <-subscription.Events:
+				if !ok {
+					if subscription.Dropped() {
+						logrus.Warnln("[EVENTS] Event stream client fell behind, closing stream")
+					}
+					return
+				}
+				writeStreamEvent(w, event)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+			if err := w.Flush(); err != nil {
+				// The client went away
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// writeStreamEvent writes event as a server-sent event whose data is the webhook body of the event
+func writeStreamEvent(w *bufio.Writer, event websocket.Event) {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		logrus.Errorf("[EVENTS] Failed to encode event %d: %v", event.ID, err)
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Event, data)
+}
+
+// queryList returns the values of a query param given as comma separated list, repeated, or both
+func queryList(c *fiber.Ctx, key string) []string {
+	var values []string
+	for _, raw := range c.Context().QueryArgs().PeekMulti(key) {
+		for _, value := range strings.Split(string(raw), ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"encoding/csv"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -31,6 +33,7 @@ func InitRestGroup(app fiber.Router, service domainGroup.IGroupUsecase) Group {
 	app.Post("/group/participants/remove", rest.DeleteParticipants)
 	app.Post("/group/participants/promote", rest.PromoteParticipants)
 	app.Post("/group/participants/demote", rest.DemoteParticipants)
+	app.Post("/group/participants/reconcile", rest.ReconcileParticipants)
 	app.Get("/group/participant-requests", rest.ListParticipantRequests)
 	app.Post("/group/participant-requests/approve", rest.ApproveParticipantRequests)
 	app.Post("/group/participant-requests/reject", rest.RejectParticipantRequests)
@@ -40,6 +43,8 @@ func InitRestGroup(app fiber.Router, service domainGroup.IGroupUsecase) Group {
 	app.Post("/group/announce", rest.SetGroupAnnounce)
 	app.Post("/group/topic", rest.SetGroupTopic)
 	app.Get("/group/invite-link", rest.GetGroupInviteLink)
+	app.Get("/group/history", rest.GroupHistory)
+	app.Get("/group/history/export", rest.ExportGroupHistory)
 	return rest
 }
 
@@ -207,6 +212,28 @@ func (controller *Group) DemoteParticipants(c *fiber.Ctx) error {
 	return controller.manageParticipants(c, whatsmeow.ParticipantChangeDemote, "Success demote participants")
 }
 
+// ReconcileParticipants brings the participants and admins of a group in line with the roster in the request
+func (controller *Group) ReconcileParticipants(c *fiber.Ctx) error {
+	var request domainGroup.ReconcileParticipantsRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+	utils.SanitizePhone(&request.GroupID)
+
+	result, err := controller.Service.ReconcileParticipants(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	message := "Success reconcile participants"
+	if result.DryRun {
+		message = "Success plan participants reconcile"
+	}
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: message,
+		Results: result,
+	})
+}
+
 func (controller *Group) ListParticipantRequests(c *fiber.Ctx) error {
 	var request domainGroup.GetGroupRequestParticipantsRequest
 	err := c.QueryParser(&request)
@@ -443,3 +470,81 @@ func (controller *Group) GetGroupInviteLink(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *Group) GroupHistory(c *fiber.Ctx) error {
+	var request domainGroup.GroupHistoryRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	result, err := controller.Service.GroupHistory(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success getting group history",
+		Results: result,
+	})
+}
+
+// ExportGroupHistory writes every change matching the filters as CSV, limit and offset are ignored
+func (controller *Group) ExportGroupHistory(c *fiber.Ctx) error {
+	var request domainGroup.GroupHistoryRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	if request.GroupID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "INVALID_GROUP_ID",
+			Message: "Group ID cannot be empty",
+		})
+	}
+
+	utils.SanitizePhone(&request.GroupID)
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	utils.PanicIfNeeded(writer.Write([]string{"id", "group_jid", "type", "actor_jid", "target_jid", "value", "timestamp"}))
+
+	request.Limit = 500
+	request.Offset = 0
+	groupID := request.GroupID
+	for {
+		result, err := controller.Service.GroupHistory(c.UserContext(), request)
+		utils.PanicIfNeeded(err)
+		groupID = result.GroupID
+
+		for _, event := range result.Data {
+			record := []string{
+				strconv.FormatInt(event.ID, 10),
+				event.GroupJID,
+				event.Type,
+				event.ActorJID,
+				event.TargetJID,
+				event.Value,
+				event.Timestamp.Format(time.RFC3339),
+			}
+
+			utils.PanicIfNeeded(writer.Write(record))
+		}
+
+		if len(result.Data) < request.Limit {
+			break
+		}
+		request.Offset += request.Limit
+	}
+
+	writer.Flush()
+	utils.PanicIfNeeded(writer.Error())
+
+	fileName := fmt.Sprintf("group-%s-history.csv", strings.ReplaceAll(groupID, "@", "_"))
+
+	c.Type("text/csv; charset=utf-8")
+	c.Attachment(fileName)
+
+	return c.Send(buffer.Bytes())
+}
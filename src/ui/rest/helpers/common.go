@@ -6,6 +6,7 @@ import (
 	"time"
 
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"go.mau.fi/whatsmeow"
 )
 
@@ -19,8 +20,22 @@ func SetAutoReconnectChecking(cli *whatsmeow.Client) {
 	go func() {
 		for {
 			time.Sleep(5 * time.Minute)
-			if !cli.IsConnected() {
-				_ = cli.Connect()
+
+			manager := whatsapp.GetDeviceManager()
+			if manager == nil {
+				if !cli.IsConnected() {
+					_ = cli.Connect()
+				}
+				continue
+			}
+
+			for _, device := range manager.List() {
+				client := device.GetClient()
+				// Devices that were never paired have nothing to reconnect to
+				if client == nil || client.Store.ID == nil || client.IsConnected() {
+					continue
+				}
+				_ = client.Connect()
 			}
 		}
 	}()
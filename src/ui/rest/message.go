@@ -22,6 +22,7 @@ func InitRestMessage(app fiber.Router, service domainMessage.IMessageUsecase) Me
 	app.Post("/message/:message_id/star", rest.StarMessage)
 	app.Post("/message/:message_id/unstar", rest.UnstarMessage)
 	app.Get("/message/:message_id/download", rest.DownloadMedia)
+	app.Get("/message/:message_id/status", rest.GetMessageStatus)
 	return rest
 }
 
@@ -176,3 +177,19 @@ func (controller *Message) DownloadMedia(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *Message) GetMessageStatus(c *fiber.Ctx) error {
+	var request domainMessage.MessageStatusRequest
+
+	request.MessageID = c.Params("message_id")
+
+	response, err := controller.Service.GetMessageStatus(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get message status",
+		Results: response,
+	})
+}
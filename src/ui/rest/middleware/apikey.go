@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+const APIKeyHeader = "X-Api-Key"
+
+// routeScopes maps routes to the scope they require, the first matching entry wins.
+// An empty method matches every method; routes that are not listed require the admin scope.
+var routeScopes = []struct {
+	method string
+	prefix string
+	scope  string
+}{
+	{"", "/api-keys", domainAPIKey.ScopeAdmin},
+	{"", "/audit", domainAPIKey.ScopeAdmin},
+	{"", "/webhooks", domainAPIKey.ScopeAdmin},
+	{"", "/webhook", domainAPIKey.ScopeAdmin},
+	{"", "/auto-reply", domainAPIKey.ScopeAdmin},
+	{"", "/metrics", domainAPIKey.ScopeAdmin},
+	{"", "/app", domainAPIKey.ScopeAppLogin},
+	{"", "/devices", domainAPIKey.ScopeAppLogin},
+	{"", "/ws", domainAPIKey.ScopeAppLogin},
+	{fiber.MethodGet, "/events", domainAPIKey.ScopeReadChats},
+	{fiber.MethodPost, "/user/presence", domainAPIKey.ScopeReadChats},
+	{fiber.MethodPost, "/user/avatar", domainAPIKey.ScopeAppLogin},
+	{fiber.MethodPost, "/user/pushname", domainAPIKey.ScopeAppLogin},
+	{fiber.MethodGet, "/user", domainAPIKey.ScopeReadChats},
+	{fiber.MethodGet, "/message", domainAPIKey.ScopeReadChats},
+	{"", "/message", domainAPIKey.ScopeSend},
+	{"", "/send", domainAPIKey.ScopeSend},
+	{"", "/campaigns", domainAPIKey.ScopeSend},
+	{fiber.MethodPost, "/chat", domainAPIKey.ScopeSend},
+	{fiber.MethodGet, "/chat", domainAPIKey.ScopeReadChats},
+	{fiber.MethodGet, "/chats", domainAPIKey.ScopeReadChats},
+	{fiber.MethodGet, "/search", domainAPIKey.ScopeReadChats},
+	{fiber.MethodGet, "/calls", domainAPIKey.ScopeReadChats},
+	{fiber.MethodGet, "/poll", domainAPIKey.ScopeReadChats},
+	{fiber.MethodGet, "/group", domainAPIKey.ScopeReadChats},
+	{"", "/group", domainAPIKey.ScopeGroupAdmin},
+	{fiber.MethodPost, "/newsletter/publish", domainAPIKey.ScopeSend},
+	{fiber.MethodPost, "/newsletter/viewed", domainAPIKey.ScopeReadChats},
+	{fiber.MethodGet, "/newsletter", domainAPIKey.ScopeReadChats},
+	{"", "/newsletter", domainAPIKey.ScopeGroupAdmin},
+	{fiber.MethodPost, "/community/announcement", domainAPIKey.ScopeSend},
+	{fiber.MethodGet, "/community", domainAPIKey.ScopeReadChats},
+	{"", "/community", domainAPIKey.ScopeGroupAdmin},
+}
+
+// RouteScope returns the scope a request to path, relative to the base path, requires
+func RouteScope(method string, path string) string {
+	for _, route := range routeScopes {
+		if route.method != "" && route.method != method {
+			continue
+		}
+		if path == route.prefix || strings.HasPrefix(path, route.prefix+"/") {
+			return route.scope
+		}
+	}
+	return domainAPIKey.ScopeAdmin
+}
+
+// APIKeyFromRequest returns the API key sent in the X-Api-Key header or as a bearer token
+func APIKeyFromRequest(c *fiber.Ctx) string {
+	if key := strings.TrimSpace(c.Get(APIKeyHeader)); key != "" {
+		return key
+	}
+	if auth := c.Get(fiber.HeaderAuthorization); len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
+
+// APIKeyAuth authenticates requests carrying an API key and checks the scope of the route against it.
+// Requests that passed basic auth act with every scope. Without any credentials a request is only
+// let through while neither basic auth nor API keys are configured. Requests that change something
+// are recorded in the audit log.
+// It runs after DeviceSelector, which has already rewritten per-device paths.
+func APIKeyAuth(basePath string, service domainAPIKey.IAPIKeyUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		path := strings.TrimPrefix(c.Path(), basePath)
+		if path == "" || path == "/" {
+			return c.Next()
+		}
+
+		var principal *domainAPIKey.Principal
+		if token := APIKeyFromRequest(c); token != "" {
+			authenticated, err := service.Authenticate(c.UserContext(), token)
+			utils.PanicIfNeeded(err)
+			principal = authenticated
+		} else if username, ok := c.Locals("username").(string); ok && username != "" {
+			principal = &domainAPIKey.Principal{Name: username, Scopes: []string{domainAPIKey.ScopeAdmin}}
+		} else {
+			required, err := service.AuthRequired(c.UserContext())
+			utils.PanicIfNeeded(err)
+			if required {
+				panic(pkgError.ErrUnauthorized)
+			}
+		}
+
+		authorize := func() error {
+			if principal != nil {
+				if scope := RouteScope(c.Method(), path); !principal.HasScope(scope) {
+					panic(pkgError.ForbiddenError(fmt.Sprintf("API key %s is missing the %s scope", principal.Name, scope)))
+				}
+				c.SetUserContext(domainAPIKey.ContextWithPrincipal(c.UserContext(), principal))
+			}
+			return c.Next()
+		}
+
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead || c.Method() == fiber.MethodOptions {
+			return authorize()
+		}
+		return auditRequest(c, service, principal, path, authorize)
+	}
+}
+
+// auditRequest runs next and records who did what, also when the request is denied or a handler panics
+func auditRequest(c *fiber.Ctx, service domainAPIKey.IAPIKeyUsecase, principal *domainAPIKey.Principal, path string, next func() error) (err error) {
+	entry := domainAPIKey.AuditEntry{
+		Actor:  "anonymous",
+		Source: domainAPIKey.SourceREST,
+		Action: c.Method() + " " + path,
+		Target: auditTarget(c),
+	}
+	if principal != nil {
+		entry.KeyID = principal.KeyID
+		entry.Actor = principal.Name
+	}
+	if device := whatsapp.DeviceFromContext(c.UserContext()); device != nil {
+		entry.DeviceID = device.ID()
+	}
+
+	defer func() {
+		recovered := recover()
+		entry.Status = c.Response().StatusCode()
+		if recovered != nil {
+			entry.Status = fiber.StatusInternalServerError
+			entry.Error = fmt.Sprintf("%v", recovered)
+			if genericErr, ok := recovered.(pkgError.GenericError); ok {
+				entry.Status = genericErr.StatusCode()
+			}
+		} else if err != nil {
+			entry.Error = err.Error()
+		}
+
+		service.RecordAudit(c.UserContext(), entry)
+		if recovered != nil {
+			panic(recovered)
+		}
+	}()
+
+	return next()
+}
+
+// auditTarget returns the recipient or group a request acts on, taken from its form or JSON body
+func auditTarget(c *fiber.Ctx) string {
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
+		var body map[string]any
+		if json.Unmarshal(c.Body(), &body) != nil {
+			return ""
+		}
+		for _, field := range []string{"phone", "group_id", "newsletter_id", "community_id"} {
+			if value, ok := body[field].(string); ok && value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	for _, field := range []string{"phone", "group_id", "newsletter_id", "community_id"} {
+		if value := c.FormValue(field); value != "" {
+			// Form values alias the request buffer
+			return strings.Clone(value)
+		}
+	}
+	return ""
+}
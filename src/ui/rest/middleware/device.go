@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/gofiber/fiber/v2"
+)
+
+const DeviceIDHeader = "X-Device-Id"
+
+// DeviceSelector binds the request to a managed device. The device is taken from the
+// X-Device-Id header or from a <basePath>/device/<id>/... path prefix, which is stripped
+// before routing so every existing endpoint is reachable per device.
+// Requests without a selection keep using the default device.
+func DeviceSelector(basePath string) fiber.Handler {
+	prefix := basePath + "/device/"
+
+	return func(c *fiber.Ctx) error {
+		deviceID := strings.Clone(strings.TrimSpace(c.Get(DeviceIDHeader)))
+
+		if path := c.Path(); strings.HasPrefix(path, prefix) {
+			rest := strings.TrimPrefix(path, prefix)
+			id, remainder, _ := strings.Cut(rest, "/")
+			if id != "" {
+				// Path values alias the request buffer, copy them before rewriting it
+				deviceID = strings.Clone(id)
+				c.Path(basePath + "/" + strings.Clone(remainder))
+			}
+		}
+
+		if deviceID == "" {
+			return c.Next()
+		}
+
+		manager := whatsapp.GetDeviceManager()
+		if manager == nil {
+			panic(pkgError.ErrDeviceNotFound)
+		}
+		device, ok := manager.Get(deviceID)
+		if !ok {
+			panic(pkgError.ErrDeviceNotFound)
+		}
+
+		c.SetUserContext(whatsapp.ContextWithDevice(c.UserContext(), device))
+		return c.Next()
+	}
+}
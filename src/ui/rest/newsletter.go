@@ -1,6 +1,8 @@
 package rest
 
 import (
+	"fmt"
+
 	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v2"
@@ -12,10 +14,73 @@ type Newsletter struct {
 
 func InitRestNewsletter(app fiber.Router, service domainNewsletter.INewsletterUsecase) Newsletter {
 	rest := Newsletter{Service: service}
+	app.Post("/newsletter", rest.Create)
+	app.Post("/newsletter/update", rest.Update)
+	app.Post("/newsletter/follow", rest.Follow)
 	app.Post("/newsletter/unfollow", rest.Unfollow)
+	app.Get("/newsletter/info", rest.Info)
+	app.Get("/newsletter/messages", rest.Messages)
+	app.Post("/newsletter/publish", rest.Publish)
+	app.Post("/newsletter/viewed", rest.MarkViewed)
 	return rest
 }
 
+func (controller *Newsletter) Create(c *fiber.Ctx) error {
+	var request domainNewsletter.CreateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	if file, err := c.FormFile("picture"); err == nil {
+		request.Picture = file
+	}
+
+	response, err := controller.Service.Create(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success create newsletter %s", response.ID),
+		Results: response,
+	})
+}
+
+func (controller *Newsletter) Update(c *fiber.Ctx) error {
+	var request domainNewsletter.UpdateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	if file, err := c.FormFile("picture"); err == nil {
+		request.Picture = file
+	}
+
+	response, err := controller.Service.Update(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success update newsletter",
+		Results: response,
+	})
+}
+
+func (controller *Newsletter) Follow(c *fiber.Ctx) error {
+	var request domainNewsletter.FollowRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.Follow(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success follow newsletter %s", response.ID),
+		Results: response,
+	})
+}
+
 func (controller *Newsletter) Unfollow(c *fiber.Ctx) error {
 	var request domainNewsletter.UnfollowRequest
 	err := c.BodyParser(&request)
@@ -30,3 +95,73 @@ func (controller *Newsletter) Unfollow(c *fiber.Ctx) error {
 		Message: "Success unfollow newsletter",
 	})
 }
+
+func (controller *Newsletter) Info(c *fiber.Ctx) error {
+	var request domainNewsletter.InfoRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.Info(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get newsletter info",
+		Results: response,
+	})
+}
+
+func (controller *Newsletter) Messages(c *fiber.Ctx) error {
+	var request domainNewsletter.MessagesRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.Messages(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get newsletter messages",
+		Results: response,
+	})
+}
+
+func (controller *Newsletter) Publish(c *fiber.Ctx) error {
+	var request domainNewsletter.PublishRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	if file, err := c.FormFile("image"); err == nil {
+		request.Image = file
+	}
+	if file, err := c.FormFile("video"); err == nil {
+		request.Video = file
+	}
+
+	response, err := controller.Service.Publish(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
+func (controller *Newsletter) MarkViewed(c *fiber.Ctx) error {
+	var request domainNewsletter.MarkViewedRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	err = controller.Service.MarkViewed(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success mark %d newsletter messages as viewed", len(request.ServerIDs)),
+	})
+}
@@ -0,0 +1,32 @@
+package rest
+
+import (
+	domainPoll "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/poll"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Poll struct {
+	Service domainPoll.IPollUsecase
+}
+
+func InitRestPoll(app fiber.Router, service domainPoll.IPollUsecase) Poll {
+	rest := Poll{Service: service}
+	app.Get("/poll/:message_id/results", rest.GetPollResults)
+	return rest
+}
+
+func (controller *Poll) GetPollResults(c *fiber.Ctx) error {
+	var request domainPoll.PollResultsRequest
+	request.MessageID = c.Params("message_id")
+
+	response, err := controller.Service.GetPollResults(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get poll results",
+		Results: response,
+	})
+}
@@ -24,6 +24,12 @@ func InitRestSend(app fiber.Router, service domainSend.ISendUsecase) Send {
 	app.Post("/send/poll", rest.SendPoll)
 	app.Post("/send/presence", rest.SendPresence)
 	app.Post("/send/chat-presence", rest.SendChatPresence)
+
+	// Scheduled message endpoints
+	app.Get("/send/scheduled", rest.ListScheduled)
+	app.Get("/send/scheduled/:schedule_id", rest.GetScheduled)
+	app.Patch("/send/scheduled/:schedule_id", rest.Reschedule)
+	app.Delete("/send/scheduled/:schedule_id", rest.CancelScheduled)
 	return rest
 }
 
@@ -266,3 +272,62 @@ func (controller *Send) SendChatPresence(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *Send) ListScheduled(c *fiber.Ctx) error {
+	var request domainSend.ListScheduledRequest
+
+	request.Status = c.Query("status", "")
+	request.Limit = c.QueryInt("limit", 25)
+	request.Offset = c.QueryInt("offset", 0)
+
+	response, err := controller.Service.ListScheduled(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get scheduled messages",
+		Results: response,
+	})
+}
+
+func (controller *Send) GetScheduled(c *fiber.Ctx) error {
+	response, err := controller.Service.GetScheduled(c.UserContext(), c.Params("schedule_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get scheduled message",
+		Results: response,
+	})
+}
+
+func (controller *Send) Reschedule(c *fiber.Ctx) error {
+	var request domainSend.RescheduleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.ScheduleID = c.Params("schedule_id")
+
+	response, err := controller.Service.Reschedule(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success reschedule message",
+		Results: response,
+	})
+}
+
+func (controller *Send) CancelScheduled(c *fiber.Ctx) error {
+	err := controller.Service.CancelScheduled(c.UserContext(), c.Params("schedule_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success cancel scheduled message",
+	})
+}
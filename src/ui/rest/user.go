@@ -22,6 +22,9 @@ func InitRestUser(app fiber.Router, service domainUser.IUserUsecase) User {
 	app.Get("/user/my/contacts", rest.UserMyListContacts)
 	app.Get("/user/check", rest.UserCheck)
 	app.Get("/user/business-profile", rest.UserBusinessProfile)
+	app.Get("/user/presence", rest.UserPresence)
+	app.Post("/user/presence/subscribe", rest.UserSubscribePresence)
+	app.Post("/user/presence/unsubscribe", rest.UserUnsubscribePresence)
 
 	return rest
 }
@@ -176,3 +179,55 @@ func (controller *User) UserBusinessProfile(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *User) UserPresence(c *fiber.Ctx) error {
+	var request domainUser.PresenceRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.Presence(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get presence",
+		Results: response,
+	})
+}
+
+func (controller *User) UserSubscribePresence(c *fiber.Ctx) error {
+	var request domainUser.PresenceRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+
+	err = controller.Service.SubscribePresence(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success subscribe to presence",
+	})
+}
+
+func (controller *User) UserUnsubscribePresence(c *fiber.Ctx) error {
+	var request domainUser.PresenceRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+
+	err = controller.Service.UnsubscribePresence(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success unsubscribe from presence",
+	})
+}
@@ -0,0 +1,168 @@
+package rest
+
+import (
+	"fmt"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Webhook struct {
+	Service domainWebhook.IWebhookUsecase
+}
+
+func InitRestWebhook(app fiber.Router, service domainWebhook.IWebhookUsecase) Webhook {
+	rest := Webhook{Service: service}
+
+	// Webhook endpoints
+	app.Get("/webhooks", rest.ListWebhooks)
+	app.Post("/webhooks", rest.CreateWebhook)
+	app.Get("/webhooks/:webhook_id", rest.GetWebhook)
+	app.Put("/webhooks/:webhook_id", rest.UpdateWebhook)
+	app.Delete("/webhooks/:webhook_id", rest.DeleteWebhook)
+
+	// Delivery queue endpoints
+	app.Get("/webhook/deliveries", rest.ListDeliveries)
+	app.Delete("/webhook/deliveries", rest.PurgeDeliveries)
+	app.Get("/webhook/deliveries/:delivery_id", rest.GetDelivery)
+	app.Post("/webhook/deliveries/:delivery_id/replay", rest.ReplayDelivery)
+
+	return rest
+}
+
+func (controller *Webhook) ListWebhooks(c *fiber.Ctx) error {
+	response, err := controller.Service.ListWebhooks(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhooks",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) CreateWebhook(c *fiber.Ctx) error {
+	var request domainWebhook.WebhookRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.CreateWebhook(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success create webhook",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) GetWebhook(c *fiber.Ctx) error {
+	response, err := controller.Service.GetWebhook(c.UserContext(), c.Params("webhook_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhook",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) UpdateWebhook(c *fiber.Ctx) error {
+	var request domainWebhook.WebhookRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.ID = c.Params("webhook_id")
+
+	response, err := controller.Service.UpdateWebhook(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success update webhook",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) DeleteWebhook(c *fiber.Ctx) error {
+	err := controller.Service.DeleteWebhook(c.UserContext(), c.Params("webhook_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success delete webhook",
+	})
+}
+
+func (controller *Webhook) ListDeliveries(c *fiber.Ctx) error {
+	var request domainWebhook.ListDeliveriesRequest
+
+	request.Status = c.Query("status", "")
+	request.Limit = c.QueryInt("limit", 25)
+	request.Offset = c.QueryInt("offset", 0)
+
+	response, err := controller.Service.ListDeliveries(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhook deliveries",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) GetDelivery(c *fiber.Ctx) error {
+	response, err := controller.Service.GetDelivery(c.UserContext(), deliveryIDParam(c))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhook delivery",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) ReplayDelivery(c *fiber.Ctx) error {
+	deliveryID := deliveryIDParam(c)
+
+	err := controller.Service.ReplayDelivery(c.UserContext(), deliveryID)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Webhook delivery %d queued for replay", deliveryID),
+	})
+}
+
+func (controller *Webhook) PurgeDeliveries(c *fiber.Ctx) error {
+	var request domainWebhook.PurgeDeliveriesRequest
+	request.Status = c.Query("status", "")
+
+	response, err := controller.Service.PurgeDeliveries(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Purged %d %s webhook deliveries", response.Deleted, response.Status),
+		Results: response,
+	})
+}
+
+func deliveryIDParam(c *fiber.Ctx) int64 {
+	deliveryID, err := c.ParamsInt("delivery_id")
+	if err != nil || deliveryID <= 0 {
+		panic(pkgError.ValidationError("delivery_id: must be a positive number."))
+	}
+	return int64(deliveryID)
+}
@@ -0,0 +1,366 @@
+package websocket
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+const (
+	// eventTrimInterval is the number of events logged between two trims of the on-disk event log
+	eventTrimInterval = 100
+	// eventWriteBuffer is the number of events waiting for the writer of the on-disk event log
+	eventWriteBuffer = 1024
+)
+
+// Event is a typed event as it is sent to webhooks, Data holds the webhook body. IDs increase by one
+// per event; they continue across restarts when an EventStore is set and restart with the process
+// otherwise. Clients pass the last ID they saw as cursor to replay what they missed while disconnected.
+type Event struct {
+	ID       uint64         `json:"id"`
+	Event    string         `json:"event"`
+	DeviceID string         `json:"device_id,omitempty"`
+	ChatID   string         `json:"chat_id,omitempty"`
+	Data     map[string]any `json:"data"`
+}
+
+// EventFilter selects events, empty lists match everything. Topics are event names where "group.*"
+// matches every event starting with "group." and "*" matches all events. Chats are chat JIDs or
+// their user part.
+type EventFilter struct {
+	Topics  []string `json:"topics,omitempty"`
+	Chats   []string `json:"chats,omitempty"`
+	Devices []string `json:"devices,omitempty"`
+}
+
+// Matches reports whether event passes the filter
+func (f EventFilter) Matches(event Event) bool {
+	if len(f.Topics) > 0 && !matchesTopic(f.Topics, event.Event) {
+		return false
+	}
+	if len(f.Devices) > 0 && !containsString(f.Devices, event.DeviceID) {
+		return false
+	}
+	if len(f.Chats) > 0 {
+		user, _, _ := strings.Cut(event.ChatID, "@")
+		if event.ChatID == "" || !(containsString(f.Chats, event.ChatID) || containsString(f.Chats, user)) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesTopic(topics []string, name string) bool {
+	for _, topic := range topics {
+		if topic == "*" || topic == name {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(topic, "*"); ok && strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// EventSubscription receives the events matching its filter on Events. Events is closed when the
+// subscription is closed, or when the subscriber fell more than its buffer behind, which Dropped reports.
+type EventSubscription struct {
+	Events  chan Event
+	filter  EventFilter
+	dropped bool
+}
+
+// Dropped reports whether the subscription was closed because its subscriber did not keep up
+func (s *EventSubscription) Dropped() bool {
+	eventBus.mu.Lock()
+	defer eventBus.mu.Unlock()
+	return s.dropped
+}
+
+// Close stops the subscription, it is safe to call more than once
+func (s *EventSubscription) Close() {
+	eventBus.mu.Lock()
+	defer eventBus.mu.Unlock()
+	eventBus.unsubscribeLocked(s)
+}
+
+// EventStore keeps the event log on disk, so replay survives restarts. It is implemented by the chat storage.
+type EventStore interface {
+	StoreStreamEvent(event *domainChatStorage.StreamEvent) error
+	GetStreamEventsAfter(afterID int64, limit int) ([]*domainChatStorage.StreamEvent, error)
+	GetLastStreamEventID() (int64, error)
+	TrimStreamEvents(upToID int64) error
+}
+
+// eventLog keeps the most recent events for replay, in memory or in its store, and fans new events
+// out to the subscriptions. A background writer logs the events to the store, so publishing never waits
+// on the database; events are pending until they are written.
+type eventLog struct {
+	mu            sync.Mutex
+	written       *sync.Cond
+	enabled       bool
+	size          int
+	lastID        uint64
+	events        []Event
+	store         EventStore
+	writes        chan Event
+	pending       []Event
+	subscriptions map[*EventSubscription]struct{}
+}
+
+var eventBus = newEventLog()
+
+func newEventLog() *eventLog {
+	l := &eventLog{subscriptions: make(map[*EventSubscription]struct{})}
+	l.written = sync.NewCond(&l.mu)
+	return l
+}
+
+// startEvents enables the event stream, keeping the last size events for replay
+func startEvents(size int) {
+	eventBus.mu.Lock()
+	defer eventBus.mu.Unlock()
+	eventBus.enabled = true
+	eventBus.size = max(size, 0)
+}
+
+// SetEventStore logs the events to store instead of memory, continuing from the last logged ID
+func SetEventStore(store EventStore) {
+	lastID, err := store.GetLastStreamEventID()
+	if err != nil {
+		logrus.Errorf("[EVENTS] Failed to read the event log, keeping events in memory: %v", err)
+		return
+	}
+
+	eventBus.mu.Lock()
+	defer eventBus.mu.Unlock()
+	eventBus.store = store
+	eventBus.lastID = max(eventBus.lastID, uint64(lastID))
+	eventBus.events = nil
+	eventBus.writes = make(chan Event, eventWriteBuffer)
+	go eventBus.writeEvents(store, eventBus.writes)
+}
+
+// EventsEnabled reports whether published events are kept or delivered to anyone
+func EventsEnabled() bool {
+	eventBus.mu.Lock()
+	defer eventBus.mu.Unlock()
+	return eventBus.enabled && (eventBus.size > 0 || len(eventBus.subscriptions) > 0)
+}
+
+// HasEventSubscribers reports whether a connected client currently receives published events
+func HasEventSubscribers() bool {
+	eventBus.mu.Lock()
+	defer eventBus.mu.Unlock()
+	return eventBus.enabled && len(eventBus.subscriptions) > 0
+}
+
+// PublishEvent records an event and delivers it to the matching subscriptions without blocking,
+// subscriptions whose buffer is full are dropped.
+func PublishEvent(name, deviceID, chatID string, data map[string]any) {
+	eventBus.mu.Lock()
+	defer eventBus.mu.Unlock()
+	if !eventBus.enabled {
+		return
+	}
+
+	eventBus.lastID++
+	event := Event{ID: eventBus.lastID, Event: name, DeviceID: deviceID, ChatID: chatID, Data: data}
+	if eventBus.size > 0 {
+		eventBus.record(event)
+	}
+
+	for subscription := range eventBus.subscriptions {
+		if !subscription.filter.Matches(event) {
+			continue
+		}
+		select {
+		case subscription.Events <- event:
+		default:
+			subscription.dropped = true
+			eventBus.unsubscribeLocked(subscription)
+		}
+	}
+}
+
+// SubscribeEvents delivers the events matching filter from now on, with a buffer of buffer events.
+// With a cursor the buffered events after it are returned as replay first; gap reports that some
+// events after the cursor are no longer buffered, e.g. because the buffer wrapped or the server restarted.
+func SubscribeEvents(filter EventFilter, cursor *uint64, buffer int) (subscription *EventSubscription, replay []Event, gap bool) {
+	eventBus.mu.Lock()
+	subscription = &EventSubscription{Events: make(chan Event, buffer), filter: filter}
+	eventBus.subscriptions[subscription] = struct{}{}
+	// The replay ends at the last event published so far, the later ones reach the subscription
+	var snapshot logSnapshot
+	if cursor != nil {
+		snapshot = eventBus.snapshot()
+	}
+	eventBus.mu.Unlock()
+
+	if cursor != nil {
+		replay, gap = snapshot.since(*cursor, filter)
+	}
+	return subscription, replay, gap
+}
+
+// LastEventID returns the ID of the most recent event
+func LastEventID() uint64 {
+	eventBus.mu.Lock()
+	defer eventBus.mu.Unlock()
+	return eventBus.lastID
+}
+
+// record keeps event for replay, dropping the events beyond the size of the log. Events for the store
+// are handed to its writer.
+func (l *eventLog) record(event Event) {
+	if l.store == nil {
+		l.events = append(l.events, event)
+		if len(l.events) > l.size {
+			l.events = l.events[len(l.events)-l.size:]
+		}
+		return
+	}
+
+	select {
+	case l.writes <- event:
+		l.pending = append(l.pending, event)
+	default:
+		logrus.Errorf("[EVENTS] The event log writer fell behind, event %d is not logged", event.ID)
+	}
+}
+
+// writeEvents logs the events handed to it to store, outside the lock of the log
+func (l *eventLog) writeEvents(store EventStore, writes <-chan Event) {
+	for event := range writes {
+		l.mu.Lock()
+		size := l.size
+		l.mu.Unlock()
+
+		writeEvent(store, event, size)
+
+		l.mu.Lock()
+		for len(l.pending) > 0 && l.pending[0].ID <= event.ID {
+			l.pending = l.pending[1:]
+		}
+		l.written.Broadcast()
+		l.mu.Unlock()
+	}
+}
+
+// writeEvent logs event to store, trimming the log to size every eventTrimInterval events
+func writeEvent(store EventStore, event Event, size int) {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		logrus.Errorf("[EVENTS] Failed to encode event %d: %v", event.ID, err)
+		return
+	}
+	if err := store.StoreStreamEvent(&domainChatStorage.StreamEvent{
+		ID:       int64(event.ID),
+		Event:    event.Event,
+		DeviceID: event.DeviceID,
+		ChatJID:  event.ChatID,
+		Data:     string(data),
+	}); err != nil {
+		logrus.Errorf("[EVENTS] Failed to log event %d: %v", event.ID, err)
+		return
+	}
+	if event.ID%eventTrimInterval == 0 && event.ID > uint64(size) {
+		if err := store.TrimStreamEvents(int64(event.ID) - int64(size)); err != nil {
+			logrus.Warnf("[EVENTS] Failed to trim the event log: %v", err)
+		}
+	}
+}
+
+// logSnapshot is the state of the event log when a subscription started, so its replay is read
+// without holding the lock of the log
+type logSnapshot struct {
+	lastID uint64
+	size   int
+	store  EventStore
+	events []Event // the events kept in memory, or the ones not written to the store yet
+}
+
+func (l *eventLog) snapshot() logSnapshot {
+	events := l.events
+	if l.store != nil {
+		events = l.pending
+	}
+	return logSnapshot{
+		lastID: l.lastID,
+		size:   l.size,
+		store:  l.store,
+		events: append([]Event(nil), events...),
+	}
+}
+
+// after returns the logged events with an ID above cursor up to the last ID of the snapshot, oldest first
+func (s logSnapshot) after(cursor uint64) []Event {
+	var events []Event
+	if s.store != nil {
+		logged, err := s.store.GetStreamEventsAfter(int64(cursor), s.size+eventTrimInterval)
+		if err != nil {
+			logrus.Errorf("[EVENTS] Failed to read the event log after %d: %v", cursor, err)
+			return nil
+		}
+		for _, entry := range logged {
+			if uint64(entry.ID) > s.lastID {
+				break
+			}
+			event := Event{ID: uint64(entry.ID), Event: entry.Event, DeviceID: entry.DeviceID, ChatID: entry.ChatJID}
+			if err := json.Unmarshal([]byte(entry.Data), &event.Data); err != nil {
+				logrus.Warnf("[EVENTS] Skipping unreadable event %d: %v", entry.ID, err)
+				continue
+			}
+			events = append(events, event)
+		}
+	}
+
+	// Pending events may have been written since the snapshot, those are already read from the store
+	for _, event := range s.events {
+		if event.ID > cursor && (len(events) == 0 || event.ID > events[len(events)-1].ID) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func (s logSnapshot) since(cursor uint64, filter EventFilter) (replay []Event, gap bool) {
+	if cursor > s.lastID {
+		// The IDs restarted, everything logged is new to the client
+		cursor = 0
+		gap = true
+	}
+
+	events := s.after(cursor)
+	if cursor < s.lastID && (len(events) == 0 || events[0].ID > cursor+1) {
+		gap = true
+	}
+
+	for _, event := range events {
+		if filter.Matches(event) {
+			replay = append(replay, event)
+		}
+	}
+	return replay, gap
+}
+
+func (l *eventLog) unsubscribeLocked(subscription *EventSubscription) {
+	if _, ok := l.subscriptions[subscription]; !ok {
+		return
+	}
+	delete(l.subscriptions, subscription)
+	close(subscription.Events)
+}
@@ -0,0 +1,245 @@
+package websocket
+
+import (
+	"sync"
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+func resetEventBus(t *testing.T, size int) {
+	t.Helper()
+	eventBus = newEventLog()
+	startEvents(size)
+}
+
+// flushEvents waits until the writer logged the pending events to the store
+func flushEvents() {
+	eventBus.mu.Lock()
+	defer eventBus.mu.Unlock()
+	for len(eventBus.pending) > 0 {
+		eventBus.written.Wait()
+	}
+}
+
+func TestEventFilterMatches(t *testing.T) {
+	event := Event{Event: "message.reaction", DeviceID: "device-1", ChatID: "628111@s.whatsapp.net"}
+
+	tests := []struct {
+		name   string
+		filter EventFilter
+		want   bool
+	}{
+		{name: "empty filter", filter: EventFilter{}, want: true},
+		{name: "exact topic", filter: EventFilter{Topics: []string{"message.reaction"}}, want: true},
+		{name: "wildcard topic", filter: EventFilter{Topics: []string{"message.*"}}, want: true},
+		{name: "all topics", filter: EventFilter{Topics: []string{"*"}}, want: true},
+		{name: "other topic", filter: EventFilter{Topics: []string{"message", "group.*"}}, want: false},
+		{name: "chat JID", filter: EventFilter{Chats: []string{"628111@s.whatsapp.net"}}, want: true},
+		{name: "chat user", filter: EventFilter{Chats: []string{"628111"}}, want: true},
+		{name: "other chat", filter: EventFilter{Chats: []string{"628222"}}, want: false},
+		{name: "other device", filter: EventFilter{Devices: []string{"device-2"}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(event); got != tt.want {
+				t.Fatalf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if (EventFilter{Chats: []string{"628111"}}).Matches(Event{Event: "message"}) {
+		t.Fatal("a chat filter matched an event without chat")
+	}
+}
+
+func TestSubscribeEventsReplay(t *testing.T) {
+	resetEventBus(t, 3)
+	if !EventsEnabled() || HasEventSubscribers() {
+		t.Fatal("events are not kept for replay, or count as received without subscribers")
+	}
+	for _, name := range []string{"message", "message.reaction", "group.participants", "message", "message.revoked"} {
+		PublishEvent(name, "device-1", "628111@s.whatsapp.net", map[string]any{"event": name})
+	}
+	// Events 3, 4 and 5 are still buffered
+
+	cursor := uint64(3)
+	subscription, replay, gap := SubscribeEvents(EventFilter{Topics: []string{"message*"}}, &cursor, 10)
+	defer subscription.Close()
+	if !HasEventSubscribers() {
+		t.Fatal("HasEventSubscribers() = false with a subscription")
+	}
+	if gap || len(replay) != 2 || replay[0].ID != 4 || replay[1].ID != 5 {
+		t.Fatalf("replay after 3 = %+v (gap %v), want events 4 and 5 without gap", replay, gap)
+	}
+
+	cursor = 1
+	_, replay, gap = SubscribeEvents(EventFilter{}, &cursor, 10)
+	if !gap || len(replay) != 3 || replay[0].ID != 3 {
+		t.Fatalf("replay after 1 = %+v (gap %v), want events 3 to 5 with a gap", replay, gap)
+	}
+
+	cursor = 99
+	_, replay, gap = SubscribeEvents(EventFilter{}, &cursor, 10)
+	if !gap || len(replay) != 3 {
+		t.Fatalf("replay after a restart = %+v (gap %v), want every buffered event with a gap", replay, gap)
+	}
+
+	cursor = 5
+	_, replay, gap = SubscribeEvents(EventFilter{}, &cursor, 10)
+	if gap || len(replay) != 0 {
+		t.Fatalf("replay of an up to date cursor = %+v (gap %v), want nothing", replay, gap)
+	}
+
+	PublishEvent("message.edited", "device-1", "628111@s.whatsapp.net", nil)
+	if event := <-subscription.Events; event.ID != 6 || event.Event != "message.edited" {
+		t.Fatalf("received %+v, want event 6", event)
+	}
+}
+
+func TestPublishEventDropsSlowSubscriptions(t *testing.T) {
+	resetEventBus(t, 0)
+
+	slow, _, _ := SubscribeEvents(EventFilter{}, nil, 1)
+	other, _, _ := SubscribeEvents(EventFilter{Topics: []string{"group.*"}}, nil, 1)
+	if !EventsEnabled() {
+		t.Fatal("events are disabled while there are subscriptions")
+	}
+
+	PublishEvent("message", "", "", nil)
+	PublishEvent("message", "", "", nil)
+
+	if event, ok := <-slow.Events; !ok || event.ID != 1 {
+		t.Fatalf("received %+v, want the first event", event)
+	}
+	if _, ok := <-slow.Events; ok || !slow.Dropped() {
+		t.Fatal("the subscription that fell behind was not dropped")
+	}
+	if other.Dropped() {
+		t.Fatal("a subscription that filtered the events out was dropped")
+	}
+
+	other.Close()
+	other.Close()
+	if EventsEnabled() {
+		t.Fatal("events are enabled without buffer or subscriptions")
+	}
+}
+
+// memoryEventStore is an EventStore keeping the log in a slice
+type memoryEventStore struct {
+	mu     sync.Mutex
+	events []*domainChatStorage.StreamEvent
+}
+
+func (s *memoryEventStore) StoreStreamEvent(event *domainChatStorage.StreamEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *memoryEventStore) GetStreamEventsAfter(afterID int64, limit int) ([]*domainChatStorage.StreamEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events []*domainChatStorage.StreamEvent
+	for _, event := range s.events {
+		if event.ID > afterID && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (s *memoryEventStore) GetLastStreamEventID() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.events) == 0 {
+		return 0, nil
+	}
+	return s.events[len(s.events)-1].ID, nil
+}
+
+func (s *memoryEventStore) TrimStreamEvents(upToID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var kept []*domainChatStorage.StreamEvent
+	for _, event := range s.events {
+		if event.ID > upToID {
+			kept = append(kept, event)
+		}
+	}
+	s.events = kept
+	return nil
+}
+
+func TestEventStoreReplayAcrossRestarts(t *testing.T) {
+	store := &memoryEventStore{}
+	resetEventBus(t, 150)
+	SetEventStore(store)
+	for i := 0; i < 200; i++ {
+		PublishEvent("message", "device-1", "628111@s.whatsapp.net", map[string]any{"event": "message", "index": i})
+	}
+	flushEvents()
+	// The log was trimmed at event 200 to the last 150 events
+	if len(store.events) != 150 || store.events[0].ID != 51 {
+		t.Fatalf("logged %d events from %d, want 150 events from 51", len(store.events), store.events[0].ID)
+	}
+
+	// A restart continues the IDs of the log
+	resetEventBus(t, 150)
+	SetEventStore(store)
+	PublishEvent("message.reaction", "device-1", "628111@s.whatsapp.net", map[string]any{"event": "message.reaction"})
+
+	// Event 201 may still be pending, the replay covers it either way
+	cursor := uint64(198)
+	subscription, replay, gap := SubscribeEvents(EventFilter{}, &cursor, 10)
+	defer subscription.Close()
+	if gap || len(replay) != 3 || replay[2].ID != 201 || replay[2].Event != "message.reaction" {
+		t.Fatalf("replay after 198 = %+v (gap %v), want events 199 to 201 without gap", replay, gap)
+	}
+	if index, _ := replay[0].Data["index"].(float64); index != 198 {
+		t.Fatalf("replayed data = %v, want the logged webhook body", replay[0].Data)
+	}
+
+	cursor = 10
+	_, replay, gap = SubscribeEvents(EventFilter{Topics: []string{"message.reaction"}}, &cursor, 10)
+	if !gap || len(replay) != 1 {
+		t.Fatalf("replay after a trimmed cursor = %+v (gap %v), want the matching events with a gap", replay, gap)
+	}
+}
+
+// blockingEventStore holds every write until release is closed
+type blockingEventStore struct {
+	memoryEventStore
+	release chan struct{}
+}
+
+func (s *blockingEventStore) StoreStreamEvent(event *domainChatStorage.StreamEvent) error {
+	<-s.release
+	return s.memoryEventStore.StoreStreamEvent(event)
+}
+
+func TestPublishEventDoesNotWaitForTheEventStore(t *testing.T) {
+	store := &blockingEventStore{release: make(chan struct{})}
+	resetEventBus(t, 10)
+	SetEventStore(store)
+
+	for _, name := range []string{"message", "message.reaction", "message.revoked"} {
+		PublishEvent(name, "device-1", "628111@s.whatsapp.net", map[string]any{"event": name})
+	}
+
+	cursor := uint64(1)
+	subscription, replay, gap := SubscribeEvents(EventFilter{}, &cursor, 10)
+	defer subscription.Close()
+	if gap || len(replay) != 2 || replay[0].ID != 2 || replay[1].ID != 3 {
+		t.Fatalf("replay of events not written yet = %+v (gap %v), want events 2 and 3", replay, gap)
+	}
+
+	close(store.release)
+	flushEvents()
+	_, replay, _ = SubscribeEvents(EventFilter{}, &cursor, 10)
+	if len(replay) != 2 || replay[1].ID != 3 {
+		t.Fatalf("replay after the events were written = %+v, want events 2 and 3 once", replay)
+	}
+}
@@ -3,15 +3,24 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
 )
 
-type client struct{}
+const (
+	// clientBuffer is the number of messages queued per connection before it counts as too slow
+	clientBuffer = 256
+	writeTimeout = 10 * time.Second
+)
 
 type BroadcastMessage struct {
 	Code    string `json:"code"`
@@ -19,56 +28,208 @@ type BroadcastMessage struct {
 	Result  any    `json:"result"`
 }
 
+// clientMessage is a command sent by a websocket client. SUBSCRIBE replaces the event filter of the
+// connection and replays the buffered events after Cursor when it is set, UNSUBSCRIBE stops events.
+type clientMessage struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	EventFilter
+	Cursor *uint64 `json:"cursor"`
+}
+
+// client is a websocket connection with its own write queue, so a slow connection is dropped instead
+// of holding up the others
+type client struct {
+	conn          *websocket.Conn
+	send          chan []byte
+	done          chan struct{}
+	writerDone    chan struct{}
+	closeOnce     sync.Once
+	canReadEvents bool
+
+	mu           sync.Mutex
+	subscription *EventSubscription
+}
+
 var (
-	Clients    = make(map[*websocket.Conn]client)
-	Register   = make(chan *websocket.Conn)
+	clients    = make(map[*client]struct{})
+	register   = make(chan *client)
+	unregister = make(chan *client)
 	Broadcast  = make(chan BroadcastMessage)
-	Unregister = make(chan *websocket.Conn)
 )
 
-func handleRegister(conn *websocket.Conn) {
-	Clients[conn] = client{}
-	logrus.Println("connection registered")
+func newClient(conn *websocket.Conn, canReadEvents bool) *client {
+	return &client{
+		conn:          conn,
+		send:          make(chan []byte, clientBuffer),
+		done:          make(chan struct{}),
+		writerDone:    make(chan struct{}),
+		canReadEvents: canReadEvents,
+	}
 }
 
-func handleUnregister(conn *websocket.Conn) {
-	delete(Clients, conn)
-	logrus.Println("connection unregistered")
+// writePump writes the queued messages to the connection, it is the only writer of the connection
+func (c *client) writePump() {
+	defer close(c.writerDone)
+	for {
+		select {
+		case message := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				logrus.Println("write error:", err)
+				c.close()
+				return
+			}
+		case <-c.done:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := c.conn.WriteMessage(websocket.CloseMessage, []byte{}); err != nil {
+				logrus.Debugln("write close message error:", err)
+			}
+			return
+		}
+	}
 }
 
-func broadcastMessage(message BroadcastMessage) {
+// enqueue queues message without blocking, a connection whose queue is full is closed
+func (c *client) enqueue(message []byte) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+
+	select {
+	case c.send <- message:
+		return true
+	default:
+		logrus.Warnln("websocket client is too slow, closing connection")
+		c.close()
+		return false
+	}
+}
+
+// enqueueWait queues message, waiting for room unless the connection is closed
+func (c *client) enqueueWait(message []byte) bool {
+	select {
+	case c.send <- message:
+		return true
+	case <-c.done:
+		return false
+	}
+}
+
+func (c *client) sendMessage(message BroadcastMessage) bool {
 	marshalMessage, err := json.Marshal(message)
 	if err != nil {
 		logrus.Println("marshal error:", err)
-		return
+		return false
+	}
+	return c.enqueueWait(marshalMessage)
+}
+
+// close stops the writer after it sent a close message and gives the reader until the write
+// timeout to see the connection end
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.unsubscribe()
+		if c.conn != nil {
+			_ = c.conn.SetReadDeadline(time.Now().Add(writeTimeout))
+		}
+	})
+}
+
+// subscribe replaces the event subscription of the connection, replaying the events after cursor first
+func (c *client) subscribe(filter EventFilter, cursor *uint64) {
+	c.unsubscribe()
+
+	subscription, replay, gap := SubscribeEvents(filter, cursor, clientBuffer)
+	c.mu.Lock()
+	c.subscription = subscription
+	c.mu.Unlock()
+
+	c.sendMessage(BroadcastMessage{
+		Code:    "SUBSCRIBED",
+		Message: fmt.Sprintf("Subscribed to events, replaying %d", len(replay)),
+		Result: map[string]any{
+			"filter":   filter,
+			"replayed": len(replay),
+			"gap":      gap,
+			"cursor":   LastEventID(),
+		},
+	})
+	for _, event := range replay {
+		if !c.sendMessage(eventMessage(event)) {
+			return
+		}
 	}
 
-	for conn := range Clients {
-		if err := conn.WriteMessage(websocket.TextMessage, marshalMessage); err != nil {
-			logrus.Println("write error:", err)
-			closeConnection(conn)
+	go func() {
+		for event := range subscription.Events {
+			if !c.sendMessage(eventMessage(event)) {
+				return
+			}
+		}
+		if subscription.Dropped() {
+			logrus.Warnln("websocket client fell behind the event stream, closing connection")
+			c.close()
 		}
+	}()
+}
+
+func (c *client) unsubscribe() {
+	c.mu.Lock()
+	subscription := c.subscription
+	c.subscription = nil
+	c.mu.Unlock()
+
+	if subscription != nil {
+		subscription.Close()
+	}
+}
+
+func eventMessage(event Event) BroadcastMessage {
+	return BroadcastMessage{
+		Code:    "EVENT",
+		Message: event.Event,
+		Result:  event,
 	}
 }
 
-func closeConnection(conn *websocket.Conn) {
-	if err := conn.WriteMessage(websocket.CloseMessage, []byte{}); err != nil {
-		logrus.Println("write close message error:", err)
+func handleRegister(c *client) {
+	clients[c] = struct{}{}
+	logrus.Println("connection registered")
+}
+
+func handleUnregister(c *client) {
+	delete(clients, c)
+	logrus.Println("connection unregistered")
+}
+
+func broadcastMessage(message BroadcastMessage) {
+	marshalMessage, err := json.Marshal(message)
+	if err != nil {
+		logrus.Println("marshal error:", err)
+		return
 	}
-	if err := conn.Close(); err != nil {
-		logrus.Println("close connection error:", err)
+
+	for c := range clients {
+		if !c.enqueue(marshalMessage) {
+			delete(clients, c)
+		}
 	}
-	delete(Clients, conn)
 }
 
 func RunHub() {
+	startEvents(config.AppEventBuffer)
+
 	for {
 		select {
-		case conn := <-Register:
-			handleRegister(conn)
+		case c := <-register:
+			handleRegister(c)
 
-		case conn := <-Unregister:
-			handleUnregister(conn)
+		case c := <-unregister:
+			handleUnregister(c)
 
 		case message := <-Broadcast:
 			logrus.Println("message received:", message)
@@ -80,18 +241,27 @@ func RunHub() {
 func RegisterRoutes(app fiber.Router, service domainApp.IAppUsecase) {
 	app.Use("/ws", func(c *fiber.Ctx) error {
 		if websocket.IsWebSocketUpgrade(c) {
+			// Events carry message contents, so API keys need the read:chats scope to subscribe
+			principal, ok := domainAPIKey.PrincipalFromContext(c.UserContext())
+			c.Locals("can_read_events", !ok || principal.HasScope(domainAPIKey.ScopeReadChats))
 			return c.Next()
 		}
 		return c.SendStatus(fiber.StatusUpgradeRequired)
 	})
 
 	app.Get("/ws", websocket.New(func(conn *websocket.Conn) {
+		canReadEvents, _ := conn.Locals("can_read_events").(bool)
+		c := newClient(conn, canReadEvents)
+		go c.writePump()
+
 		defer func() {
-			Unregister <- conn
+			unregister <- c
+			c.close()
+			<-c.writerDone
 			_ = conn.Close()
 		}()
 
-		Register <- conn
+		register <- c
 
 		for {
 			messageType, message, err := conn.ReadMessage()
@@ -103,19 +273,32 @@ func RegisterRoutes(app fiber.Router, service domainApp.IAppUsecase) {
 			}
 
 			if messageType == websocket.TextMessage {
-				var messageData BroadcastMessage
+				var messageData clientMessage
 				if err := json.Unmarshal(message, &messageData); err != nil {
 					logrus.Println("unmarshal error:", err)
 					return
 				}
 
-				if messageData.Code == "FETCH_DEVICES" {
+				switch messageData.Code {
+				case "FETCH_DEVICES":
 					devices, _ := service.FetchDevices(context.Background())
 					Broadcast <- BroadcastMessage{
 						Code:    "LIST_DEVICES",
 						Message: "Device found",
 						Result:  devices,
 					}
+				case "SUBSCRIBE":
+					if !c.canReadEvents {
+						c.sendMessage(BroadcastMessage{
+							Code:    "ERROR",
+							Message: fmt.Sprintf("subscribing to events needs the %s scope", domainAPIKey.ScopeReadChats),
+						})
+						continue
+					}
+					c.subscribe(messageData.EventFilter, messageData.Cursor)
+				case "UNSUBSCRIBE":
+					c.unsubscribe()
+					c.sendMessage(BroadcastMessage{Code: "UNSUBSCRIBED", Message: "Unsubscribed from events"})
 				}
 			} else {
 				logrus.Println("unsupported message type:", messageType)
@@ -0,0 +1,30 @@
+package websocket
+
+import (
+	"testing"
+)
+
+func TestBroadcastMessageDropsSlowClients(t *testing.T) {
+	slow := newClient(nil, true)
+	fast := newClient(nil, true)
+	clients = map[*client]struct{}{slow: {}, fast: {}}
+	defer func() { clients = make(map[*client]struct{}) }()
+
+	for i := 0; i < clientBuffer; i++ {
+		slow.send <- []byte("queued")
+	}
+
+	broadcastMessage(BroadcastMessage{Code: "LOGIN_SUCCESS", Message: "paired"})
+
+	if _, ok := clients[slow]; ok {
+		t.Fatal("the client with a full queue is still registered")
+	}
+	select {
+	case <-slow.done:
+	default:
+		t.Fatal("the client with a full queue was not closed")
+	}
+	if _, ok := clients[fast]; !ok || len(fast.send) != 1 {
+		t.Fatalf("the other client has %d queued messages, want 1", len(fast.send))
+	}
+}
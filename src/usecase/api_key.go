@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	fiberUtils "github.com/gofiber/fiber/v2/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	apiKeyPrefix = "gowa_"
+	// apiKeyTouchInterval limits how often last_used_at is written for a busy key
+	apiKeyTouchInterval = time.Minute
+)
+
+type serviceAPIKey struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+func NewAPIKeyService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainAPIKey.IAPIKeyUsecase {
+	return &serviceAPIKey{
+		chatStorageRepo: chatStorageRepo,
+	}
+}
+
+func (service serviceAPIKey) ListKeys(_ context.Context) (response []domainAPIKey.Key, err error) {
+	keys, err := service.chatStorageRepo.GetAPIKeys()
+	if err != nil {
+		return response, err
+	}
+
+	response = make([]domainAPIKey.Key, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, toAPIKey(key))
+	}
+	return response, nil
+}
+
+func (service serviceAPIKey) CreateKey(ctx context.Context, request domainAPIKey.CreateKeyRequest) (response domainAPIKey.CreateKeyResponse, err error) {
+	if err = validations.ValidateCreateAPIKey(ctx, request); err != nil {
+		return response, err
+	}
+
+	secret := make([]byte, 24)
+	if _, err = rand.Read(secret); err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to generate API key %v", err))
+	}
+	token := apiKeyPrefix + hex.EncodeToString(secret)
+
+	key := &domainChatStorage.APIKey{
+		ID:                fiberUtils.UUIDv4(),
+		Name:              request.Name,
+		KeyHash:           hashAPIKey(token),
+		KeyPrefix:         token[:len(apiKeyPrefix)+8],
+		Scopes:            request.Scopes,
+		AllowedRecipients: request.AllowedRecipients,
+		ExpiresAt:         request.ExpiresAt,
+		CreatedAt:         time.Now(),
+	}
+	if err = service.chatStorageRepo.StoreAPIKey(key); err != nil {
+		return response, err
+	}
+
+	return domainAPIKey.CreateKeyResponse{Key: toAPIKey(key), APIKey: token}, nil
+}
+
+func (service serviceAPIKey) DeleteKey(_ context.Context, keyID string) (err error) {
+	key, err := service.chatStorageRepo.GetAPIKey(keyID)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return pkgError.ErrAPIKeyNotFound
+	}
+	return service.chatStorageRepo.DeleteAPIKey(keyID)
+}
+
+func (service serviceAPIKey) Authenticate(_ context.Context, token string) (principal *domainAPIKey.Principal, err error) {
+	key, err := service.chatStorageRepo.GetAPIKeyByHash(hashAPIKey(token))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if key == nil || (key.ExpiresAt != nil && !key.ExpiresAt.After(now)) {
+		return nil, pkgError.ErrAPIKeyInvalid
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err = service.chatStorageRepo.TouchAPIKey(key.ID, now); err != nil {
+			logrus.Warnf("Failed to update last use of API key %s: %v", key.ID, err)
+		}
+	}
+
+	return &domainAPIKey.Principal{
+		KeyID:             key.ID,
+		Name:              key.Name,
+		Scopes:            key.Scopes,
+		AllowedRecipients: key.AllowedRecipients,
+	}, nil
+}
+
+func (service serviceAPIKey) AuthRequired(_ context.Context) (required bool, err error) {
+	if len(config.AppBasicAuthCredential) > 0 {
+		return true, nil
+	}
+
+	count, err := service.chatStorageRepo.CountAPIKeys()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (service serviceAPIKey) RecordAudit(_ context.Context, entry domainAPIKey.AuditEntry) {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	// A failing audit write must not fail the request it describes
+	err := service.chatStorageRepo.StoreAuditLog(&domainChatStorage.AuditLog{
+		KeyID:     entry.KeyID,
+		Actor:     entry.Actor,
+		Source:    entry.Source,
+		DeviceID:  entry.DeviceID,
+		Action:    entry.Action,
+		Target:    entry.Target,
+		Status:    entry.Status,
+		Error:     entry.Error,
+		CreatedAt: entry.CreatedAt,
+	})
+	if err != nil {
+		logrus.Errorf("Failed to record audit log for %s %s: %v", entry.Actor, entry.Action, err)
+	}
+}
+
+func (service serviceAPIKey) ListAudit(ctx context.Context, request domainAPIKey.ListAuditRequest) (response domainAPIKey.ListAuditResponse, err error) {
+	if err = validations.ValidateListAudit(ctx, &request); err != nil {
+		return response, err
+	}
+
+	entries, err := service.chatStorageRepo.GetAuditLogs(&domainChatStorage.AuditLogFilter{
+		KeyID:  request.KeyID,
+		Limit:  request.Limit,
+		Offset: request.Offset,
+	})
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainAPIKey.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		response.Data = append(response.Data, domainAPIKey.AuditEntry{
+			ID:        entry.ID,
+			KeyID:     entry.KeyID,
+			Actor:     entry.Actor,
+			Source:    entry.Source,
+			DeviceID:  entry.DeviceID,
+			Action:    entry.Action,
+			Target:    entry.Target,
+			Status:    entry.Status,
+			Error:     entry.Error,
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+	response.Limit = request.Limit
+	response.Offset = request.Offset
+	return response, nil
+}
+
+// authorizeRecipient rejects sending to recipient when the caller's API key is limited to other recipients
+func authorizeRecipient(ctx context.Context, recipient string) error {
+	principal, ok := domainAPIKey.PrincipalFromContext(ctx)
+	if !ok || principal.AllowsRecipient(recipient) {
+		return nil
+	}
+	return pkgError.ForbiddenError(fmt.Sprintf("API key %s is not allowed to send to %s", principal.Name, recipient))
+}
+
+func hashAPIKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func toAPIKey(key *domainChatStorage.APIKey) domainAPIKey.Key {
+	return domainAPIKey.Key{
+		ID:                key.ID,
+		Name:              key.Name,
+		Prefix:            key.KeyPrefix,
+		Scopes:            key.Scopes,
+		AllowedRecipients: key.AllowedRecipients,
+		ExpiresAt:         key.ExpiresAt,
+		LastUsedAt:        key.LastUsedAt,
+		CreatedAt:         key.CreatedAt,
+	}
+}
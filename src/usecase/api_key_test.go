@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+)
+
+func TestAuthorizeRecipient(t *testing.T) {
+	restricted := domainAPIKey.ContextWithPrincipal(context.Background(), &domainAPIKey.Principal{
+		Name:              "crm",
+		Scopes:            []string{domainAPIKey.ScopeSend},
+		AllowedRecipients: []string{"62812*", "*@g.us"},
+	})
+	unrestricted := domainAPIKey.ContextWithPrincipal(context.Background(), &domainAPIKey.Principal{
+		Name:   "ops",
+		Scopes: []string{domainAPIKey.ScopeSend},
+	})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		recipient string
+		wantErr   bool
+	}{
+		{name: "No principal", ctx: context.Background(), recipient: "6289876543210"},
+		{name: "Unrestricted key", ctx: unrestricted, recipient: "6289876543210"},
+		{name: "Matching phone", ctx: restricted, recipient: "+6281234567890"},
+		{name: "Matching user JID", ctx: restricted, recipient: "6281234567890@s.whatsapp.net"},
+		{name: "Matching group JID", ctx: restricted, recipient: "120363025246125486@g.us"},
+		{name: "Other phone", ctx: restricted, recipient: "6289876543210", wantErr: true},
+		{name: "Other user JID", ctx: restricted, recipient: "6289876543210@s.whatsapp.net", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizeRecipient(tt.ctx, tt.recipient)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("authorizeRecipient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
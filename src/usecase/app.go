@@ -31,8 +31,8 @@ func NewAppService(chatStorageRepo domainChatStorage.IChatStorageRepository) dom
 	}
 }
 
-func (service *serviceApp) Login(_ context.Context) (response domainApp.LoginResponse, err error) {
-	client := whatsapp.GetClient()
+func (service *serviceApp) Login(ctx context.Context) (response domainApp.LoginResponse, err error) {
+	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
 	}
@@ -119,7 +119,7 @@ func (service *serviceApp) Login(_ context.Context) (response domainApp.LoginRes
 		client.IsConnected(), client.IsLoggedIn())
 
 	// Ensure global client is synchronized with service client
-	whatsapp.UpdateGlobalClient(client, whatsapp.GetDB())
+	whatsapp.UpdateDeviceClient(ctx, client)
 
 	return response, nil
 }
@@ -130,7 +130,7 @@ func (service *serviceApp) LoginWithCode(ctx context.Context, phoneNumber string
 		return loginCode, err
 	}
 
-	client := whatsapp.GetClient()
+	client := whatsapp.ClientFromContext(ctx)
 	// detect is already logged in
 	if client.Store.ID != nil || client.IsLoggedIn() {
 		logrus.Warn("User is already logged in")
@@ -144,7 +144,7 @@ func (service *serviceApp) LoginWithCode(ctx context.Context, phoneNumber string
 	}
 
 	// refresh client reference after reconnect
-	client = whatsapp.GetClient()
+	client = whatsapp.ClientFromContext(ctx)
 	if client.IsLoggedIn() || client.Store.ID != nil {
 		logrus.Warn("User is already logged in after reconnect")
 		return loginCode, pkgError.ErrAlreadyLoggedIn
@@ -162,7 +162,7 @@ func (service *serviceApp) LoginWithCode(ctx context.Context, phoneNumber string
 		client.IsConnected(), client.IsLoggedIn())
 
 	// Ensure global client is synchronized with service client
-	whatsapp.UpdateGlobalClient(client, whatsapp.GetDB())
+	whatsapp.UpdateDeviceClient(ctx, client)
 
 	logrus.Infof("Successfully paired phone with code: %s", loginCode)
 	return loginCode, nil
@@ -183,7 +183,7 @@ func (service *serviceApp) Logout(ctx context.Context) (err error) {
 
 	// [DEBUG] Call WhatsApp client logout first to disconnect from server
 	logrus.Info("[DEBUG] Calling WhatsApp client logout...")
-	err = whatsapp.GetClient().Logout(ctx)
+	err = whatsapp.ClientFromContext(ctx).Logout(ctx)
 	if err != nil {
 		logrus.Errorf("[DEBUG] WhatsApp logout failed: %v", err)
 		// Continue with cleanup even if logout fails
@@ -199,6 +199,16 @@ func (service *serviceApp) Logout(ctx context.Context) (err error) {
 		logrus.Infof("[DEBUG] Devices after logout: %d found", len(devices))
 	}
 
+	// Keep the sessions of other devices intact when several devices are managed
+	if handled, resetErr := whatsapp.ResetManagedDevice(ctx); handled {
+		if resetErr != nil {
+			logrus.Errorf("[DEBUG] Device reset failed: %v", resetErr)
+			return resetErr
+		}
+		logrus.Info("[DEBUG] Logout process completed successfully")
+		return nil
+	}
+
 	// Perform complete cleanup with global client synchronization
 	newDB, newCli, err := whatsapp.PerformCleanupAndUpdateGlobals(ctx, "MANUAL_LOGOUT", service.chatStorageRepo)
 	if err != nil {
@@ -213,10 +223,10 @@ func (service *serviceApp) Logout(ctx context.Context) (err error) {
 	return nil
 }
 
-func (service *serviceApp) Reconnect(_ context.Context) (err error) {
+func (service *serviceApp) Reconnect(ctx context.Context) (err error) {
 	logrus.Info("[DEBUG] Starting reconnect process...")
 
-	client := whatsapp.GetClient()
+	client := whatsapp.ClientFromContext(ctx)
 	client.Disconnect()
 	err = client.Connect()
 
@@ -230,14 +240,14 @@ func (service *serviceApp) Reconnect(_ context.Context) (err error) {
 		client.IsConnected(), client.IsLoggedIn())
 
 	// Ensure global client is synchronized with service client
-	whatsapp.UpdateGlobalClient(client, whatsapp.GetDB())
+	whatsapp.UpdateDeviceClient(ctx, client)
 
 	logrus.Info("[DEBUG] Reconnect process completed successfully")
 	return err
 }
 
 func (service *serviceApp) FirstDevice(ctx context.Context) (response domainApp.DevicesResponse, err error) {
-	if whatsapp.GetClient() == nil {
+	if whatsapp.ClientFromContext(ctx) == nil {
 		return response, pkgError.ErrWaCLI
 	}
 
@@ -257,7 +267,7 @@ func (service *serviceApp) FirstDevice(ctx context.Context) (response domainApp.
 }
 
 func (service *serviceApp) FetchDevices(ctx context.Context) (response []domainApp.DevicesResponse, err error) {
-	if whatsapp.GetClient() == nil {
+	if whatsapp.ClientFromContext(ctx) == nil {
 		return response, pkgError.ErrWaCLI
 	}
 
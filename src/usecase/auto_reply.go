@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	fiberUtils "github.com/gofiber/fiber/v2/utils"
+)
+
+type serviceAutoReply struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+func NewAutoReplyService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainAutoReply.IAutoReplyUsecase {
+	return &serviceAutoReply{
+		chatStorageRepo: chatStorageRepo,
+	}
+}
+
+func (service serviceAutoReply) ListRules(_ context.Context) (response []domainAutoReply.Rule, err error) {
+	rules, err := service.chatStorageRepo.GetAutoReplyRules()
+	if err != nil {
+		return response, err
+	}
+	return toAutoReplyRules(rules)
+}
+
+func (service serviceAutoReply) GetRule(_ context.Context, ruleID string) (response domainAutoReply.Rule, err error) {
+	rule, err := service.getRule(ruleID)
+	if err != nil {
+		return response, err
+	}
+	return toAutoReplyRule(rule)
+}
+
+func (service serviceAutoReply) CreateRule(ctx context.Context, request domainAutoReply.RuleRequest) (response domainAutoReply.Rule, err error) {
+	if err = validations.ValidateAutoReplyRule(ctx, request); err != nil {
+		return response, err
+	}
+
+	rule := &domainChatStorage.AutoReplyRule{ID: fiberUtils.UUIDv4()}
+	if err = applyAutoReplyRequest(rule, request); err != nil {
+		return response, err
+	}
+
+	return service.saveRule(rule)
+}
+
+func (service serviceAutoReply) UpdateRule(ctx context.Context, request domainAutoReply.RuleRequest) (response domainAutoReply.Rule, err error) {
+	if err = validations.ValidateAutoReplyRule(ctx, request); err != nil {
+		return response, err
+	}
+
+	rule, err := service.getRule(request.ID)
+	if err != nil {
+		return response, err
+	}
+	if err = applyAutoReplyRequest(rule, request); err != nil {
+		return response, err
+	}
+
+	return service.saveRule(rule)
+}
+
+func (service serviceAutoReply) DeleteRule(_ context.Context, ruleID string) (err error) {
+	if _, err = service.getRule(ruleID); err != nil {
+		return err
+	}
+
+	if err = service.chatStorageRepo.DeleteAutoReplyRule(ruleID); err != nil {
+		return err
+	}
+	return whatsapp.ReloadAutoReplyRules()
+}
+
+func (service serviceAutoReply) ReplaceRules(ctx context.Context, request domainAutoReply.ReplaceRulesRequest) (response []domainAutoReply.Rule, err error) {
+	if err = validations.ValidateReplaceAutoReplyRules(ctx, request); err != nil {
+		return response, err
+	}
+
+	rules := make([]*domainChatStorage.AutoReplyRule, 0, len(request.Rules))
+	for _, ruleRequest := range request.Rules {
+		// Exported rules keep their IDs when they are imported again
+		rule := &domainChatStorage.AutoReplyRule{ID: ruleRequest.ID}
+		if rule.ID == "" {
+			rule.ID = fiberUtils.UUIDv4()
+		}
+		if err = applyAutoReplyRequest(rule, ruleRequest); err != nil {
+			return response, err
+		}
+		rules = append(rules, rule)
+	}
+
+	if err = service.chatStorageRepo.ReplaceAutoReplyRules(rules); err != nil {
+		return response, err
+	}
+	if err = whatsapp.ReloadAutoReplyRules(); err != nil {
+		return response, err
+	}
+
+	return toAutoReplyRules(rules)
+}
+
+func (service serviceAutoReply) getRule(ruleID string) (*domainChatStorage.AutoReplyRule, error) {
+	rule, err := service.chatStorageRepo.GetAutoReplyRule(ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, pkgError.ErrAutoReplyRuleNotFound
+	}
+	return rule, nil
+}
+
+func (service serviceAutoReply) saveRule(rule *domainChatStorage.AutoReplyRule) (response domainAutoReply.Rule, err error) {
+	if err = service.chatStorageRepo.StoreAutoReplyRule(rule); err != nil {
+		return response, err
+	}
+	if err = whatsapp.ReloadAutoReplyRules(); err != nil {
+		return response, err
+	}
+
+	return toAutoReplyRule(rule)
+}
+
+func applyAutoReplyRequest(rule *domainChatStorage.AutoReplyRule, request domainAutoReply.RuleRequest) error {
+	conditions, err := json.Marshal(request.Conditions)
+	if err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("failed to encode rule conditions %v", err))
+	}
+	actions, err := json.Marshal(request.Actions)
+	if err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("failed to encode rule actions %v", err))
+	}
+
+	rule.Name = request.Name
+	rule.Position = request.Position
+	rule.Enabled = request.Enabled == nil || *request.Enabled
+	rule.DeviceID = request.DeviceID
+	rule.Conditions = string(conditions)
+	rule.Actions = string(actions)
+	rule.CooldownSeconds = request.CooldownSeconds
+	rule.ContinueMatching = request.ContinueMatching
+	return nil
+}
+
+func toAutoReplyRules(rules []*domainChatStorage.AutoReplyRule) ([]domainAutoReply.Rule, error) {
+	response := make([]domainAutoReply.Rule, 0, len(rules))
+	for _, rule := range rules {
+		converted, err := toAutoReplyRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		response = append(response, converted)
+	}
+	return response, nil
+}
+
+func toAutoReplyRule(rule *domainChatStorage.AutoReplyRule) (response domainAutoReply.Rule, err error) {
+	response = domainAutoReply.Rule{
+		ID:               rule.ID,
+		Name:             rule.Name,
+		Position:         rule.Position,
+		Enabled:          rule.Enabled,
+		DeviceID:         rule.DeviceID,
+		CooldownSeconds:  rule.CooldownSeconds,
+		ContinueMatching: rule.ContinueMatching,
+		CreatedAt:        rule.CreatedAt,
+		UpdatedAt:        rule.UpdatedAt,
+	}
+	if err = json.Unmarshal([]byte(rule.Conditions), &response.Conditions); err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("invalid conditions of rule %s: %v", rule.ID, err))
+	}
+	if err = json.Unmarshal([]byte(rule.Actions), &response.Actions); err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("invalid actions of rule %s: %v", rule.ID, err))
+	}
+	if response.Actions == nil {
+		response.Actions = []domainAutoReply.Action{}
+	}
+	return response, nil
+}
+
+// NewAutoReplySender returns the function the auto-reply engine uses to send replies
+func NewAutoReplySender(service domainSend.ISendUsecase) whatsapp.AutoReplySender {
+	return func(ctx context.Context, chat string, quotedMessageID string, action domainAutoReply.Action) (messageID string, err error) {
+		// Sending panics when the device disconnected meanwhile, which must not take the event handler down
+		defer recoverSendPanic(&err)
+
+		base := domainSend.BaseRequest{Phone: chat}
+		var response domainSend.GenericResponse
+
+		switch {
+		case action.MediaURL != "" && action.MediaType == domainAutoReply.MediaImage:
+			response, err = service.SendImage(ctx, domainSend.ImageRequest{BaseRequest: base, Caption: action.Text, ImageURL: &action.MediaURL, Compress: true})
+		case action.MediaURL != "" && action.MediaType == domainAutoReply.MediaVideo:
+			response, err = service.SendVideo(ctx, domainSend.VideoRequest{BaseRequest: base, Caption: action.Text, VideoURL: &action.MediaURL})
+		case action.MediaURL != "" && action.MediaType == domainAutoReply.MediaAudio:
+			response, err = service.SendAudio(ctx, domainSend.AudioRequest{BaseRequest: base, AudioURL: &action.MediaURL})
+		default:
+			request := domainSend.MessageRequest{BaseRequest: base, Message: action.Text}
+			if quotedMessageID != "" {
+				request.ReplyMessageID = &quotedMessageID
+			}
+			response, err = service.SendText(ctx, request)
+		}
+		return response.MessageID, err
+	}
+}
+
+// recoverSendPanic turns a panic raised while sending (e.g. by utils.MustLogin) into an error
+func recoverSendPanic(err *error) {
+	if recovered := recover(); recovered != nil {
+		if recoveredErr, ok := recovered.(error); ok {
+			*err = recoveredErr
+			return
+		}
+		*err = fmt.Errorf("%v", recovered)
+	}
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	domainCall "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/call"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+)
+
+type serviceCall struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+func NewCallService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainCall.ICallUsecase {
+	return &serviceCall{
+		chatStorageRepo: chatStorageRepo,
+	}
+}
+
+func (service serviceCall) ListCalls(ctx context.Context, request domainCall.ListCallsRequest) (response domainCall.ListCallsResponse, err error) {
+	if err = validations.ValidateListCalls(ctx, &request); err != nil {
+		return response, err
+	}
+
+	calls, err := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo).GetCalls(&domainChatStorage.CallFilter{
+		ChatJID: request.Phone,
+		Limit:   request.Limit,
+		Offset:  request.Offset,
+	})
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainCall.Call, 0, len(calls))
+	for _, call := range calls {
+		response.Data = append(response.Data, toCall(call))
+	}
+	response.Limit = request.Limit
+	response.Offset = request.Offset
+
+	return response, nil
+}
+
+// NewCallRejectSender sends the follow-up message of auto-rejected calls through the send usecase
+func NewCallRejectSender(service domainSend.ISendUsecase) whatsapp.CallRejectSender {
+	return func(ctx context.Context, phone string, message string) (err error) {
+		// Sending panics when the device disconnected meanwhile, which must not take the event handler down
+		defer recoverSendPanic(&err)
+
+		_, err = service.SendText(ctx, domainSend.MessageRequest{
+			BaseRequest: domainSend.BaseRequest{Phone: phone},
+			Message:     message,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to send call follow-up message: %w", err)
+		}
+		return nil
+	}
+}
+
+func toCall(call *domainChatStorage.Call) domainCall.Call {
+	response := domainCall.Call{
+		ID:           call.ID,
+		ChatJID:      call.ChatJID,
+		CallerJID:    call.CallerJID,
+		IsFromMe:     call.IsFromMe,
+		IsVideo:      call.IsVideo,
+		IsGroup:      call.IsGroup,
+		Status:       call.Status,
+		AutoRejected: call.AutoRejected,
+		EndReason:    call.EndReason,
+		StartedAt:    call.StartedAt,
+		AcceptedAt:   call.AcceptedAt,
+		EndedAt:      call.EndedAt,
+	}
+	if call.AcceptedAt != nil && call.EndedAt != nil {
+		response.DurationSeconds = int(call.EndedAt.Sub(*call.AcceptedAt).Seconds())
+	}
+	return response
+}
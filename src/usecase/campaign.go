@@ -0,0 +1,441 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainCampaign "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/campaign"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	fiberUtils "github.com/gofiber/fiber/v2/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+)
+
+// maxRecipientsFileSize limits uploaded recipient lists
+const maxRecipientsFileSize = 5 << 20
+
+type serviceCampaign struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+func NewCampaignService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainCampaign.ICampaignUsecase {
+	return &serviceCampaign{
+		chatStorageRepo: chatStorageRepo,
+	}
+}
+
+func (service serviceCampaign) CreateCampaign(ctx context.Context, request domainCampaign.CreateCampaignRequest) (response domainCampaign.Campaign, err error) {
+	if request.RecipientsFile != nil {
+		recipients, err := parseRecipientsFile(request.RecipientsFile)
+		if err != nil {
+			return response, err
+		}
+		request.Recipients = append(request.Recipients, recipients...)
+	}
+
+	if err = validations.ValidateCreateCampaign(ctx, &request); err != nil {
+		return response, err
+	}
+
+	campaign := &domainChatStorage.Campaign{
+		ID:              fiberUtils.UUIDv4(),
+		Name:            request.Name,
+		Message:         request.Message,
+		RatePerMinute:   request.RatePerMinute,
+		MinDelaySeconds: request.MinDelaySeconds,
+		MaxDelaySeconds: request.MaxDelaySeconds,
+		ValidateNumbers: request.ValidateNumbers,
+		Status:          domainChatStorage.CampaignRunning,
+	}
+	if device := whatsapp.DeviceFromContext(ctx); device != nil {
+		campaign.DeviceID = device.ID()
+	}
+
+	recipients, err := campaignRecipients(request.Recipients)
+	if err != nil {
+		return response, err
+	}
+	for _, recipient := range recipients {
+		if err = authorizeRecipient(ctx, recipient.Phone); err != nil {
+			return response, err
+		}
+	}
+
+	if request.Image != nil {
+		if _, err = uploadFilename(request.Image.Filename); err != nil {
+			return response, err
+		}
+		dir := filepath.Join(config.PathCampaigns, campaign.ID)
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			return response, pkgError.InternalServerError(fmt.Sprintf("failed to store campaign image %v", err))
+		}
+		campaign.MediaPath = filepath.Join(dir, storedMediaName)
+		campaign.MediaType = request.Image.Header.Get("Content-Type")
+		if err = fasthttp.SaveMultipartFile(request.Image, campaign.MediaPath); err != nil {
+			removeCampaignMedia(campaign)
+			return response, pkgError.InternalServerError(fmt.Sprintf("failed to store campaign image %v", err))
+		}
+	}
+
+	if err = service.chatStorageRepo.StoreCampaign(campaign, recipients); err != nil {
+		removeCampaignMedia(campaign)
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to store campaign %v", err))
+	}
+
+	if runner := whatsapp.GetCampaignRunner(); runner != nil {
+		runner.Start(campaign.ID)
+	}
+
+	return service.GetCampaign(ctx, campaign.ID)
+}
+
+func (service serviceCampaign) ListCampaigns(ctx context.Context, request domainCampaign.ListCampaignsRequest) (response domainCampaign.ListCampaignsResponse, err error) {
+	if err = validations.ValidateListCampaigns(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := &domainChatStorage.CampaignFilter{
+		Status: request.Status,
+		Limit:  request.Limit,
+		Offset: request.Offset,
+	}
+	if device := whatsapp.DeviceFromContext(ctx); device != nil {
+		filter.DeviceID = device.ID()
+	}
+
+	campaigns, err := service.chatStorageRepo.GetCampaigns(filter)
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainCampaign.Campaign, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		counts, err := service.chatStorageRepo.CountCampaignRecipients(campaign.ID)
+		if err != nil {
+			return response, err
+		}
+		response.Data = append(response.Data, toCampaign(campaign, counts))
+	}
+	response.Limit = request.Limit
+	response.Offset = request.Offset
+	return response, nil
+}
+
+func (service serviceCampaign) GetCampaign(_ context.Context, campaignID string) (response domainCampaign.Campaign, err error) {
+	campaign, err := service.getCampaign(campaignID)
+	if err != nil {
+		return response, err
+	}
+
+	counts, err := service.chatStorageRepo.CountCampaignRecipients(campaign.ID)
+	if err != nil {
+		return response, err
+	}
+	return toCampaign(campaign, counts), nil
+}
+
+func (service serviceCampaign) ListRecipients(ctx context.Context, request domainCampaign.ListRecipientsRequest) (response domainCampaign.ListRecipientsResponse, err error) {
+	if err = validations.ValidateListCampaignRecipients(ctx, &request); err != nil {
+		return response, err
+	}
+
+	if _, err = service.getCampaign(request.CampaignID); err != nil {
+		return response, err
+	}
+
+	recipients, err := service.chatStorageRepo.GetCampaignRecipients(&domainChatStorage.CampaignRecipientFilter{
+		CampaignID: request.CampaignID,
+		Status:     request.Status,
+		Limit:      request.Limit,
+		Offset:     request.Offset,
+	})
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainCampaign.RecipientStatus, 0, len(recipients))
+	for _, recipient := range recipients {
+		var variables map[string]string
+		_ = json.Unmarshal([]byte(recipient.Variables), &variables)
+
+		response.Data = append(response.Data, domainCampaign.RecipientStatus{
+			ID:          recipient.ID,
+			Phone:       recipient.Phone,
+			Variables:   variables,
+			Status:      recipient.Status,
+			MessageID:   recipient.MessageID,
+			Error:       recipient.Error,
+			SentAt:      recipient.SentAt,
+			DeliveredAt: recipient.DeliveredAt,
+			ReadAt:      recipient.ReadAt,
+			UpdatedAt:   recipient.UpdatedAt,
+		})
+	}
+	response.Limit = request.Limit
+	response.Offset = request.Offset
+	return response, nil
+}
+
+func (service serviceCampaign) PauseCampaign(ctx context.Context, campaignID string) (response domainCampaign.Campaign, err error) {
+	campaign, err := service.getCampaign(campaignID)
+	if err != nil {
+		return response, err
+	}
+
+	if err = service.chatStorageRepo.UpdateCampaignStatus(campaign.ID, domainChatStorage.CampaignPaused, domainChatStorage.CampaignRunning); err != nil {
+		return response, campaignTransitionError(campaign, "paused", err)
+	}
+
+	if runner := whatsapp.GetCampaignRunner(); runner != nil {
+		runner.Stop(campaign.ID)
+	}
+
+	return service.GetCampaign(ctx, campaign.ID)
+}
+
+func (service serviceCampaign) ResumeCampaign(ctx context.Context, campaignID string) (response domainCampaign.Campaign, err error) {
+	campaign, err := service.getCampaign(campaignID)
+	if err != nil {
+		return response, err
+	}
+
+	if err = service.chatStorageRepo.UpdateCampaignStatus(campaign.ID, domainChatStorage.CampaignRunning, domainChatStorage.CampaignPaused); err != nil {
+		return response, campaignTransitionError(campaign, "resumed", err)
+	}
+
+	if runner := whatsapp.GetCampaignRunner(); runner != nil {
+		runner.Start(campaign.ID)
+	}
+
+	return service.GetCampaign(ctx, campaign.ID)
+}
+
+func (service serviceCampaign) CancelCampaign(ctx context.Context, campaignID string) (response domainCampaign.Campaign, err error) {
+	campaign, err := service.getCampaign(campaignID)
+	if err != nil {
+		return response, err
+	}
+
+	if err = service.chatStorageRepo.UpdateCampaignStatus(
+		campaign.ID, domainChatStorage.CampaignCancelled, domainChatStorage.CampaignRunning, domainChatStorage.CampaignPaused,
+	); err != nil {
+		return response, campaignTransitionError(campaign, "cancelled", err)
+	}
+
+	if runner := whatsapp.GetCampaignRunner(); runner != nil {
+		runner.Stop(campaign.ID)
+	}
+	removeCampaignMedia(campaign)
+
+	return service.GetCampaign(ctx, campaign.ID)
+}
+
+func (service serviceCampaign) getCampaign(campaignID string) (*domainChatStorage.Campaign, error) {
+	campaign, err := service.chatStorageRepo.GetCampaign(campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign == nil {
+		return nil, pkgError.ErrCampaignNotFound
+	}
+	return campaign, nil
+}
+
+// campaignTransitionError explains why a campaign could not change its state
+func campaignTransitionError(campaign *domainChatStorage.Campaign, action string, err error) error {
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	return pkgError.ValidationError(fmt.Sprintf("campaign is %s and cannot be %s", campaign.Status, action))
+}
+
+func toCampaign(campaign *domainChatStorage.Campaign, counts map[string]int) domainCampaign.Campaign {
+	stats := domainCampaign.Stats{
+		Queued:      counts[domainChatStorage.CampaignRecipientQueued],
+		Sending:     counts[domainChatStorage.CampaignRecipientSending],
+		Sent:        counts[domainChatStorage.CampaignRecipientSent],
+		Delivered:   counts[domainChatStorage.CampaignRecipientDelivered],
+		Read:        counts[domainChatStorage.CampaignRecipientRead],
+		Failed:      counts[domainChatStorage.CampaignRecipientFailed],
+		NeedsReview: counts[domainChatStorage.CampaignRecipientNeedsReview],
+	}
+	for _, count := range counts {
+		stats.Total += count
+	}
+
+	return domainCampaign.Campaign{
+		ID:              campaign.ID,
+		DeviceID:        campaign.DeviceID,
+		Name:            campaign.Name,
+		Message:         campaign.Message,
+		HasImage:        campaign.MediaPath != "",
+		RatePerMinute:   campaign.RatePerMinute,
+		MinDelaySeconds: campaign.MinDelaySeconds,
+		MaxDelaySeconds: campaign.MaxDelaySeconds,
+		ValidateNumbers: campaign.ValidateNumbers,
+		Status:          campaign.Status,
+		Stats:           stats,
+		CreatedAt:       campaign.CreatedAt,
+		UpdatedAt:       campaign.UpdatedAt,
+		CompletedAt:     campaign.CompletedAt,
+	}
+}
+
+// campaignRecipients normalizes the recipient phones and drops duplicates, so nobody gets the message twice
+func campaignRecipients(recipients []domainCampaign.Recipient) ([]*domainChatStorage.CampaignRecipient, error) {
+	result := make([]*domainChatStorage.CampaignRecipient, 0, len(recipients))
+	seen := make(map[string]bool, len(recipients))
+
+	for _, recipient := range recipients {
+		phone := strings.TrimPrefix(strings.TrimSpace(recipient.Phone), "+")
+		utils.SanitizePhone(&phone)
+		if seen[phone] {
+			continue
+		}
+		seen[phone] = true
+
+		variables := "{}"
+		if len(recipient.Variables) > 0 {
+			encoded, err := json.Marshal(recipient.Variables)
+			if err != nil {
+				return nil, pkgError.InternalServerError(fmt.Sprintf("failed to encode recipient variables %v", err))
+			}
+			variables = string(encoded)
+		}
+
+		result = append(result, &domainChatStorage.CampaignRecipient{Phone: phone, Variables: variables})
+	}
+
+	return result, nil
+}
+
+func removeCampaignMedia(campaign *domainChatStorage.Campaign) {
+	if campaign.MediaPath == "" || campaign.ID == "" {
+		return
+	}
+	if err := os.RemoveAll(filepath.Join(config.PathCampaigns, campaign.ID)); err != nil {
+		logrus.Warnf("[CAMPAIGN] Failed to remove image of campaign %s: %v", campaign.ID, err)
+	}
+}
+
+// parseRecipientsFile reads an uploaded recipient list. JSON files hold an array of recipients,
+// CSV files need a phone column and every other column becomes a template variable.
+func parseRecipientsFile(file *multipart.FileHeader) ([]domainCampaign.Recipient, error) {
+	if file.Size > maxRecipientsFileSize {
+		return nil, pkgError.ValidationError(fmt.Sprintf("recipients_file cannot be larger than %d MB", maxRecipientsFileSize>>20))
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("failed to open recipients file %v", err))
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxRecipientsFileSize))
+	if err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("failed to read recipients file %v", err))
+	}
+
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if strings.EqualFold(filepath.Ext(file.Filename), ".json") || bytes.HasPrefix(trimmed, []byte("[")) {
+		var recipients []domainCampaign.Recipient
+		if err := json.Unmarshal(trimmed, &recipients); err != nil {
+			return nil, pkgError.ValidationError(fmt.Sprintf("recipients_file is not a valid JSON recipient list: %v", err))
+		}
+		return recipients, nil
+	}
+
+	return parseRecipientsCSV(trimmed)
+}
+
+func parseRecipientsCSV(data []byte) ([]domainCampaign.Recipient, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, pkgError.ValidationError(fmt.Sprintf("recipients_file is not a valid CSV file: %v", err))
+	}
+	if len(records) == 0 {
+		return nil, pkgError.ValidationError("recipients_file is empty")
+	}
+
+	header := records[0]
+	phoneColumn := -1
+	for i, name := range header {
+		header[i] = strings.TrimSpace(name)
+		if strings.EqualFold(header[i], "phone") {
+			phoneColumn = i
+		}
+	}
+	if phoneColumn < 0 {
+		return nil, pkgError.ValidationError("recipients_file must have a phone column")
+	}
+
+	recipients := make([]domainCampaign.Recipient, 0, len(records)-1)
+	for _, record := range records[1:] {
+		if phoneColumn >= len(record) || strings.TrimSpace(record[phoneColumn]) == "" {
+			continue
+		}
+
+		recipient := domainCampaign.Recipient{Phone: strings.TrimSpace(record[phoneColumn])}
+		for i, value := range record {
+			if i == phoneColumn || i >= len(header) || header[i] == "" {
+				continue
+			}
+			if recipient.Variables == nil {
+				recipient.Variables = make(map[string]string)
+			}
+			recipient.Variables[header[i]] = strings.TrimSpace(value)
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, nil
+}
+
+// NewCampaignMessageSender returns the function the campaign runner uses to send a message to one recipient
+func NewCampaignMessageSender(service domainSend.ISendUsecase) whatsapp.CampaignMessageSender {
+	return func(ctx context.Context, campaign *domainChatStorage.Campaign, phone string, message string) (messageID string, err error) {
+		defer recoverSendPanic(&err)
+
+		if campaign.MediaPath == "" {
+			response, err := service.SendText(ctx, domainSend.MessageRequest{
+				BaseRequest: domainSend.BaseRequest{Phone: phone},
+				Message:     message,
+			})
+			return response.MessageID, err
+		}
+
+		image, form, err := scheduledMediaFile(campaign.MediaPath, "", campaign.MediaType)
+		if err != nil {
+			return "", err
+		}
+		defer form.RemoveAll()
+
+		response, err := service.SendImage(ctx, domainSend.ImageRequest{
+			BaseRequest: domainSend.BaseRequest{Phone: phone},
+			Caption:     message,
+			Image:       image,
+			Compress:    true,
+		})
+		return response.MessageID, err
+	}
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainCampaign "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/campaign"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+func TestParseRecipientsCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []domainCampaign.Recipient
+		wantErr bool
+	}{
+		{
+			name: "Variables from columns",
+			data: "Phone,name,city\n6281234567890,Ann,Jakarta\n\n6289876543210, Bob ,\n",
+			want: []domainCampaign.Recipient{
+				{Phone: "6281234567890", Variables: map[string]string{"name": "Ann", "city": "Jakarta"}},
+				{Phone: "6289876543210", Variables: map[string]string{"name": "Bob", "city": ""}},
+			},
+		},
+		{
+			name: "Phone only",
+			data: "phone\n6281234567890\n",
+			want: []domainCampaign.Recipient{{Phone: "6281234567890"}},
+		},
+		{
+			name:    "Missing phone column",
+			data:    "name\nAnn\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRecipientsCSV([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRecipientsCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("parseRecipientsCSV() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveCampaignMedia(t *testing.T) {
+	previous := config.PathCampaigns
+	config.PathCampaigns = filepath.Join(t.TempDir(), "campaigns")
+	defer func() { config.PathCampaigns = previous }()
+
+	dir := filepath.Join(config.PathCampaigns, "campaign-1")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// A media path outside the campaign directory must not widen what is removed
+	removeCampaignMedia(&domainChatStorage.Campaign{ID: "campaign-1", MediaPath: config.PathCampaigns})
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("the directory of the campaign was not removed: %v", err)
+	}
+	if _, err := os.Stat(config.PathCampaigns); err != nil {
+		t.Fatalf("the campaign media directory was removed: %v", err)
+	}
+}
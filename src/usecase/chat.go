@@ -2,12 +2,14 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
@@ -38,14 +40,14 @@ func (service serviceChat) ListChats(ctx context.Context, request domainChat.Lis
 	}
 
 	// Get chats from storage
-	chats, err := service.chatStorageRepo.GetChats(filter)
+	chats, err := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo).GetChats(filter)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get chats from storage")
 		return response, err
 	}
 
 	// Get total count for pagination
-	totalCount, err := service.chatStorageRepo.GetTotalChatCount()
+	totalCount, err := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo).GetTotalChatCount()
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get total chat count")
 		// Continue with partial data
@@ -91,7 +93,7 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	}
 
 	// Get chat info first
-	chat, err := service.chatStorageRepo.GetChat(request.ChatJID)
+	chat, err := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo).GetChat(request.ChatJID)
 	if err != nil {
 		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get chat info")
 		return response, err
@@ -130,14 +132,14 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	var messages []*domainChatStorage.Message
 	if request.Search != "" {
 		// Use search functionality if search query is provided
-		messages, err = service.chatStorageRepo.SearchMessages(request.ChatJID, request.Search, request.Limit)
+		messages, err = whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo).SearchMessages(request.ChatJID, request.Search, request.Limit)
 		if err != nil {
 			logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to search messages")
 			return response, err
 		}
 	} else {
 		// Use regular filter
-		messages, err = service.chatStorageRepo.GetMessages(filter)
+		messages, err = whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo).GetMessages(filter)
 		if err != nil {
 			logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get messages")
 			return response, err
@@ -145,13 +147,16 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	}
 
 	// Get total message count for pagination
-	totalCount, err := service.chatStorageRepo.GetChatMessageCount(request.ChatJID)
+	totalCount, err := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo).GetChatMessageCount(request.ChatJID)
 	if err != nil {
 		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get message count")
 		// Continue with partial data
 		totalCount = 0
 	}
 
+	statuses := sentMessageStatuses(whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo), messages)
+	edits, reactions := messageEditsAndReactions(whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo), messages)
+
 	// Convert entities to domain objects
 	messageInfos := make([]domainChat.MessageInfo, 0, len(messages))
 	for _, message := range messages {
@@ -166,6 +171,12 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 			Filename:   message.Filename,
 			URL:        message.URL,
 			FileLength: message.FileLength,
+			Status:     statuses[message.ID],
+			EditedAt:   formatOptionalTime(message.EditedAt),
+			Edits:      edits[message.ID],
+			Reactions:  reactions[message.ID],
+			IsRevoked:  message.IsRevoked,
+			RevokedAt:  formatOptionalTime(message.RevokedAt),
 			CreatedAt:  message.CreatedAt.Format(time.RFC3339),
 			UpdatedAt:  message.UpdatedAt.Format(time.RFC3339),
 		}
@@ -203,13 +214,109 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	return response, nil
 }
 
+func (service serviceChat) SearchMessages(ctx context.Context, request domainChat.SearchMessagesRequest) (response domainChat.SearchMessagesResponse, err error) {
+	if err = validations.ValidateSearchMessages(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := &domainChatStorage.MessageSearchFilter{
+		Query:     request.Query,
+		ChatJID:   request.ChatJID,
+		Sender:    request.Sender,
+		MediaType: request.MediaType,
+		MediaOnly: request.MediaOnly,
+		IsFromMe:  request.IsFromMe,
+		Sort:      request.Sort,
+		Limit:     request.Limit,
+		Offset:    request.Offset,
+	}
+
+	// Time filters were checked by the validation
+	if request.StartTime != nil && *request.StartTime != "" {
+		startTime, _ := time.Parse(time.RFC3339, *request.StartTime)
+		filter.StartTime = &startTime
+	}
+	if request.EndTime != nil && *request.EndTime != "" {
+		endTime, _ := time.Parse(time.RFC3339, *request.EndTime)
+		filter.EndTime = &endTime
+	}
+
+	repo := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo)
+	results, err := repo.SearchAllMessages(filter)
+	if err != nil {
+		if errors.Is(err, domainChatStorage.ErrInvalidSearchQuery) {
+			return response, pkgError.ValidationError(err.Error())
+		}
+		logrus.WithError(err).WithField("query", request.Query).Error("Failed to search messages")
+		return response, err
+	}
+
+	totalCount, err := repo.CountSearchMessages(filter)
+	if err != nil {
+		logrus.WithError(err).WithField("query", request.Query).Error("Failed to count search results")
+		// Continue with partial data
+		totalCount = 0
+	}
+
+	searchMessages := make([]*domainChatStorage.Message, 0, len(results))
+	for _, result := range results {
+		searchMessages = append(searchMessages, result.Message)
+	}
+	statuses := sentMessageStatuses(repo, searchMessages)
+	edits, reactions := messageEditsAndReactions(repo, searchMessages)
+
+	response.Data = make([]domainChat.SearchMessageInfo, 0, len(results))
+	for _, result := range results {
+		message := result.Message
+		response.Data = append(response.Data, domainChat.SearchMessageInfo{
+			MessageInfo: domainChat.MessageInfo{
+				ID:         message.ID,
+				ChatJID:    message.ChatJID,
+				SenderJID:  message.Sender,
+				Content:    message.Content,
+				Timestamp:  message.Timestamp.Format(time.RFC3339),
+				IsFromMe:   message.IsFromMe,
+				MediaType:  message.MediaType,
+				Filename:   message.Filename,
+				URL:        message.URL,
+				FileLength: message.FileLength,
+				Status:     statuses[message.ID],
+				EditedAt:   formatOptionalTime(message.EditedAt),
+				Edits:      edits[message.ID],
+				Reactions:  reactions[message.ID],
+				IsRevoked:  message.IsRevoked,
+				RevokedAt:  formatOptionalTime(message.RevokedAt),
+				CreatedAt:  message.CreatedAt.Format(time.RFC3339),
+				UpdatedAt:  message.UpdatedAt.Format(time.RFC3339),
+			},
+			ChatName: result.ChatName,
+			Snippet:  result.Snippet,
+			Rank:     result.Rank,
+		})
+	}
+
+	response.Pagination = domainChat.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  int(totalCount),
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"query":   request.Query,
+		"results": len(response.Data),
+		"total":   totalCount,
+	}).Info("Searched messages successfully")
+
+	return response, nil
+}
+
 func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinChatRequest) (response domainChat.PinChatResponse, err error) {
 	if err = validations.ValidatePinChat(ctx, &request); err != nil {
 		return response, err
 	}
 
 	// Validate JID and ensure connection
-	targetJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.ChatJID)
+	targetJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.ChatJID)
 	if err != nil {
 		return response, err
 	}
@@ -218,7 +325,7 @@ func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinCh
 	patchInfo := appstate.BuildPin(targetJID, request.Pinned)
 
 	// Send app state update
-	if err = whatsapp.GetClient().SendAppState(ctx, patchInfo); err != nil {
+	if err = whatsapp.ClientFromContext(ctx).SendAppState(ctx, patchInfo); err != nil {
 		logrus.WithError(err).WithFields(logrus.Fields{
 			"chat_jid": request.ChatJID,
 			"pinned":   request.Pinned,
@@ -244,3 +351,68 @@ func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinCh
 
 	return response, nil
 }
+
+// sentMessageStatuses returns the delivery status of the messages we sent, messages without receipts are reported as sent
+func sentMessageStatuses(repo domainChatStorage.IChatStorageRepository, messages []*domainChatStorage.Message) map[string]string {
+	var messageIDs []string
+	for _, message := range messages {
+		if message.IsFromMe {
+			messageIDs = append(messageIDs, message.ID)
+		}
+	}
+
+	statuses, err := messageStatuses(repo, messageIDs)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to get message statuses")
+		statuses = make(map[string]string)
+	}
+	for _, messageID := range messageIDs {
+		if statuses[messageID] == "" {
+			statuses[messageID] = domainChatStorage.MessageStatusSent
+		}
+	}
+	return statuses
+}
+
+// messageEditsAndReactions returns the edit history and the current reactions of messages by message ID
+func messageEditsAndReactions(repo domainChatStorage.IChatStorageRepository, messages []*domainChatStorage.Message) (map[string][]domainChat.MessageEditInfo, map[string][]domainChat.MessageReactionInfo) {
+	messageIDs := make([]string, 0, len(messages))
+	for _, message := range messages {
+		messageIDs = append(messageIDs, message.ID)
+	}
+
+	edits := make(map[string][]domainChat.MessageEditInfo)
+	messageEdits, err := repo.GetMessageEdits(messageIDs)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to get message edits")
+	}
+	for _, edit := range messageEdits {
+		edits[edit.MessageID] = append(edits[edit.MessageID], domainChat.MessageEditInfo{
+			OldContent: edit.OldContent,
+			NewContent: edit.NewContent,
+			EditedAt:   edit.EditedAt.Format(time.RFC3339),
+		})
+	}
+
+	reactions := make(map[string][]domainChat.MessageReactionInfo)
+	messageReactions, err := repo.GetMessageReactions(messageIDs)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to get message reactions")
+	}
+	for _, reaction := range messageReactions {
+		reactions[reaction.MessageID] = append(reactions[reaction.MessageID], domainChat.MessageReactionInfo{
+			SenderJID: reaction.SenderJID,
+			Reaction:  reaction.Reaction,
+			ReactedAt: reaction.ReactedAt.Format(time.RFC3339),
+		})
+	}
+
+	return edits, reactions
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domainCommunity "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/community"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+type serviceCommunity struct {
+	sendService domainSend.ISendUsecase
+}
+
+func NewCommunityService(sendService domainSend.ISendUsecase) domainCommunity.ICommunityUsecase {
+	return &serviceCommunity{
+		sendService: sendService,
+	}
+}
+
+func (service serviceCommunity) CreateCommunity(ctx context.Context, request domainCommunity.CreateCommunityRequest) (response domainCommunity.CreateCommunityResponse, err error) {
+	if err = validations.ValidateCreateCommunity(ctx, request); err != nil {
+		return response, err
+	}
+	client := whatsapp.ClientFromContext(ctx)
+	utils.MustLogin(client)
+
+	participants := make([]types.JID, 0, len(request.Participants))
+	for _, participant := range request.Participants {
+		jid, err := utils.ValidateJidWithLogin(client, participant)
+		if err != nil {
+			return response, err
+		}
+		participants = append(participants, jid)
+	}
+
+	communityInfo, err := client.CreateGroup(ctx, whatsmeow.ReqCreateGroup{
+		Name:         request.Name,
+		Participants: participants,
+		GroupParent:  types.GroupParent{IsParent: true},
+	})
+	if err != nil {
+		return response, err
+	}
+
+	if description := strings.TrimSpace(request.Description); description != "" {
+		if err = client.SetGroupTopic(ctx, communityInfo.JID, "", "", description); err != nil {
+			return response, fmt.Errorf("community %s was created but its description could not be set: %w", communityInfo.JID, err)
+		}
+	}
+
+	response.CommunityID = communityInfo.JID.String()
+	response.Name = communityInfo.Name
+	return response, nil
+}
+
+func (service serviceCommunity) CommunityInfo(ctx context.Context, request domainCommunity.CommunityRequest) (response domainCommunity.CommunityInfo, err error) {
+	communityJID, err := service.communityJID(ctx, request.CommunityID)
+	if err != nil {
+		return response, err
+	}
+	client := whatsapp.ClientFromContext(ctx)
+
+	communityInfo, err := client.GetGroupInfo(ctx, communityJID)
+	if err != nil {
+		return response, err
+	}
+	if !communityInfo.IsParent {
+		return response, pkgError.ValidationError(fmt.Sprintf("%s is not a community", communityJID))
+	}
+
+	subGroups, err := client.GetSubGroups(ctx, communityJID)
+	if err != nil {
+		return response, err
+	}
+
+	response.CommunityID = communityJID.String()
+	response.Name = communityInfo.Name
+	response.Description = communityInfo.Topic
+	response.SubGroups = toSubGroups(subGroups)
+	for _, subGroup := range response.SubGroups {
+		if subGroup.IsAnnouncement {
+			response.AnnouncementGroupID = subGroup.GroupID
+		}
+	}
+	return response, nil
+}
+
+func (service serviceCommunity) ListSubGroups(ctx context.Context, request domainCommunity.CommunityRequest) (response []domainCommunity.SubGroup, err error) {
+	communityJID, err := service.communityJID(ctx, request.CommunityID)
+	if err != nil {
+		return response, err
+	}
+
+	subGroups, err := whatsapp.ClientFromContext(ctx).GetSubGroups(ctx, communityJID)
+	if err != nil {
+		return response, err
+	}
+	return toSubGroups(subGroups), nil
+}
+
+func (service serviceCommunity) LinkGroup(ctx context.Context, request domainCommunity.LinkGroupRequest) (err error) {
+	communityJID, groupJID, err := service.linkJIDs(ctx, request)
+	if err != nil {
+		return err
+	}
+	return whatsapp.ClientFromContext(ctx).LinkGroup(ctx, communityJID, groupJID)
+}
+
+func (service serviceCommunity) UnlinkGroup(ctx context.Context, request domainCommunity.LinkGroupRequest) (err error) {
+	communityJID, groupJID, err := service.linkJIDs(ctx, request)
+	if err != nil {
+		return err
+	}
+	return whatsapp.ClientFromContext(ctx).UnlinkGroup(ctx, communityJID, groupJID)
+}
+
+func (service serviceCommunity) ListParticipants(ctx context.Context, request domainCommunity.CommunityRequest) (response domainCommunity.CommunityParticipantsResponse, err error) {
+	communityJID, err := service.communityJID(ctx, request.CommunityID)
+	if err != nil {
+		return response, err
+	}
+
+	participants, err := whatsapp.ClientFromContext(ctx).GetLinkedGroupsParticipants(ctx, communityJID)
+	if err != nil {
+		return response, err
+	}
+
+	response.CommunityID = communityJID.String()
+	response.Participants = make([]string, 0, len(participants))
+	for _, participant := range participants {
+		response.Participants = append(response.Participants, participant.String())
+	}
+	return response, nil
+}
+
+func (service serviceCommunity) SendAnnouncement(ctx context.Context, request domainCommunity.AnnouncementRequest) (response domainCommunity.AnnouncementResponse, err error) {
+	if err = validations.ValidateCommunityAnnouncement(ctx, request); err != nil {
+		return response, err
+	}
+
+	info, err := service.CommunityInfo(ctx, domainCommunity.CommunityRequest{CommunityID: request.CommunityID})
+	if err != nil {
+		return response, err
+	}
+	if info.AnnouncementGroupID == "" {
+		return response, pkgError.ValidationError(fmt.Sprintf("community %s has no announcement group", info.CommunityID))
+	}
+
+	sent, err := service.sendService.SendText(ctx, domainSend.MessageRequest{
+		BaseRequest: domainSend.BaseRequest{Phone: info.AnnouncementGroupID},
+		Message:     request.Message,
+	})
+	if err != nil {
+		return response, err
+	}
+
+	response.GroupID = info.AnnouncementGroupID
+	response.MessageID = sent.MessageID
+	response.Status = sent.Status
+	return response, nil
+}
+
+func (service serviceCommunity) communityJID(ctx context.Context, communityID string) (types.JID, error) {
+	if err := validations.ValidateCommunityRequest(ctx, domainCommunity.CommunityRequest{CommunityID: communityID}); err != nil {
+		return types.JID{}, err
+	}
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
+
+	return utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), communityID)
+}
+
+func (service serviceCommunity) linkJIDs(ctx context.Context, request domainCommunity.LinkGroupRequest) (communityJID types.JID, groupJID types.JID, err error) {
+	if err = validations.ValidateLinkGroup(ctx, request); err != nil {
+		return communityJID, groupJID, err
+	}
+	client := whatsapp.ClientFromContext(ctx)
+	utils.MustLogin(client)
+
+	if communityJID, err = utils.ValidateJidWithLogin(client, request.CommunityID); err != nil {
+		return communityJID, groupJID, err
+	}
+	groupJID, err = utils.ValidateJidWithLogin(client, request.GroupID)
+	return communityJID, groupJID, err
+}
+
+func toSubGroups(subGroups []*types.GroupLinkTarget) []domainCommunity.SubGroup {
+	result := make([]domainCommunity.SubGroup, 0, len(subGroups))
+	for _, subGroup := range subGroups {
+		result = append(result, domainCommunity.SubGroup{
+			GroupID:        subGroup.JID.String(),
+			Name:           subGroup.Name,
+			IsAnnouncement: subGroup.IsDefaultSubGroup,
+		})
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	domainCommunity "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/community"
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestToSubGroups(t *testing.T) {
+	announcement := types.NewJID("120363000000000002", types.GroupServer)
+	linked := types.NewJID("120363000000000003", types.GroupServer)
+
+	got := toSubGroups([]*types.GroupLinkTarget{
+		{JID: announcement, GroupName: types.GroupName{Name: "Neighbours"}, GroupIsDefaultSub: types.GroupIsDefaultSub{IsDefaultSubGroup: true}},
+		{JID: linked, GroupName: types.GroupName{Name: "Parking"}},
+	})
+	want := []domainCommunity.SubGroup{
+		{GroupID: announcement.String(), Name: "Neighbours", IsAnnouncement: true},
+		{GroupID: linked.String(), Name: "Parking"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("toSubGroups() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGroupCommunity(t *testing.T) {
+	service := serviceGroup{}
+	community := types.NewJID("120363000000000001", types.GroupServer)
+
+	if got := service.groupCommunity(context.Background(), &types.GroupInfo{JID: types.NewJID("120363000000000009", types.GroupServer)}); got != nil {
+		t.Fatalf("groupCommunity() of a plain group = %+v, want nil", got)
+	}
+
+	got := service.groupCommunity(context.Background(), &types.GroupInfo{
+		JID:               types.NewJID("120363000000000002", types.GroupServer),
+		GroupLinkedParent: types.GroupLinkedParent{LinkedParentJID: community},
+		GroupIsDefaultSub: types.GroupIsDefaultSub{IsDefaultSubGroup: true},
+	})
+	want := &domainGroup.GroupCommunity{CommunityID: community.String(), IsAnnouncementGroup: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("groupCommunity() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+
+	domainDevice "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+)
+
+type serviceDevice struct{}
+
+func NewDeviceService() domainDevice.IDeviceUsecase {
+	return &serviceDevice{}
+}
+
+func (service serviceDevice) ListDevices(_ context.Context) (response []domainDevice.DeviceInfo, err error) {
+	manager := whatsapp.GetDeviceManager()
+	if manager == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	for _, device := range manager.List() {
+		response = append(response, toDeviceInfo(device))
+	}
+	return response, nil
+}
+
+func (service serviceDevice) GetDevice(_ context.Context, deviceID string) (response domainDevice.DeviceInfo, err error) {
+	manager := whatsapp.GetDeviceManager()
+	if manager == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	device, ok := manager.Get(deviceID)
+	if !ok {
+		return response, pkgError.ErrDeviceNotFound
+	}
+	return toDeviceInfo(device), nil
+}
+
+func (service serviceDevice) AddDevice(ctx context.Context, request domainDevice.AddDeviceRequest) (response domainDevice.DeviceInfo, err error) {
+	if err = validations.ValidateAddDevice(ctx, request); err != nil {
+		return response, err
+	}
+
+	manager := whatsapp.GetDeviceManager()
+	if manager == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	device, err := manager.AddDevice(ctx, request.DeviceID, request.Webhooks)
+	if err != nil {
+		return response, err
+	}
+	return toDeviceInfo(device), nil
+}
+
+func (service serviceDevice) UpdateWebhooks(ctx context.Context, request domainDevice.UpdateWebhooksRequest) (response domainDevice.DeviceInfo, err error) {
+	if err = validations.ValidateUpdateDeviceWebhooks(ctx, request); err != nil {
+		return response, err
+	}
+
+	manager := whatsapp.GetDeviceManager()
+	if manager == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	device, err := manager.UpdateWebhooks(request.DeviceID, request.Webhooks)
+	if err != nil {
+		return response, err
+	}
+	return toDeviceInfo(device), nil
+}
+
+func (service serviceDevice) RemoveDevice(ctx context.Context, deviceID string) (err error) {
+	manager := whatsapp.GetDeviceManager()
+	if manager == nil {
+		return pkgError.ErrWaCLI
+	}
+
+	return manager.RemoveDevice(ctx, deviceID)
+}
+
+func toDeviceInfo(device *whatsapp.DeviceInstance) domainDevice.DeviceInfo {
+	isConnected, isLoggedIn, jid := device.Status()
+	webhooks := device.Webhooks()
+	if webhooks == nil {
+		webhooks = []string{}
+	}
+
+	return domainDevice.DeviceInfo{
+		DeviceID:    device.ID(),
+		JID:         jid,
+		IsConnected: isConnected,
+		IsLoggedIn:  isLoggedIn,
+		Webhooks:    webhooks,
+	}
+}
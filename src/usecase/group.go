@@ -3,11 +3,15 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
@@ -16,19 +20,25 @@ import (
 	"go.mau.fi/whatsmeow/types"
 )
 
-type serviceGroup struct{}
+type serviceGroup struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+	sendService     domainSend.ISendUsecase
+}
 
-func NewGroupService() domainGroup.IGroupUsecase {
-	return &serviceGroup{}
+func NewGroupService(chatStorageRepo domainChatStorage.IChatStorageRepository, sendService domainSend.ISendUsecase) domainGroup.IGroupUsecase {
+	return &serviceGroup{
+		chatStorageRepo: chatStorageRepo,
+		sendService:     sendService,
+	}
 }
 
 func (service serviceGroup) JoinGroupWithLink(ctx context.Context, request domainGroup.JoinGroupWithLinkRequest) (groupID string, err error) {
 	if err = validations.ValidateJoinGroupWithLink(ctx, request); err != nil {
 		return groupID, err
 	}
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	jid, err := whatsapp.GetClient().JoinGroupWithLink(ctx, request.Link)
+	jid, err := whatsapp.ClientFromContext(ctx).JoinGroupWithLink(ctx, request.Link)
 	if err != nil {
 		return
 	}
@@ -40,21 +50,21 @@ func (service serviceGroup) LeaveGroup(ctx context.Context, request domainGroup.
 		return err
 	}
 
-	JID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	JID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return err
 	}
 
-	return whatsapp.GetClient().LeaveGroup(ctx, JID)
+	return whatsapp.ClientFromContext(ctx).LeaveGroup(ctx, JID)
 }
 
 func (service serviceGroup) CreateGroup(ctx context.Context, request domainGroup.CreateGroupRequest) (groupID string, err error) {
 	if err = validations.ValidateCreateGroup(ctx, request); err != nil {
 		return groupID, err
 	}
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	participantsJID, err := service.participantToJID(request.Participants)
+	participantsJID, err := service.participantToJID(ctx, request.Participants)
 	if err != nil {
 		return
 	}
@@ -66,7 +76,7 @@ func (service serviceGroup) CreateGroup(ctx context.Context, request domainGroup
 		GroupLinkedParent: types.GroupLinkedParent{},
 	}
 
-	groupInfo, err := whatsapp.GetClient().CreateGroup(ctx, groupConfig)
+	groupInfo, err := whatsapp.ClientFromContext(ctx).CreateGroup(ctx, groupConfig)
 	if err != nil {
 		return
 	}
@@ -78,9 +88,9 @@ func (service serviceGroup) GetGroupInfoFromLink(ctx context.Context, request do
 	if err = validations.ValidateGetGroupInfoFromLink(ctx, request); err != nil {
 		return response, err
 	}
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	groupInfo, err := whatsapp.GetClient().GetGroupInfoFromLink(ctx, request.Link)
+	groupInfo, err := whatsapp.ClientFromContext(ctx).GetGroupInfoFromLink(ctx, request.Link)
 	if err != nil {
 		return response, err
 	}
@@ -104,19 +114,19 @@ func (service serviceGroup) ManageParticipant(ctx context.Context, request domai
 	if err = validations.ValidateParticipant(ctx, request); err != nil {
 		return result, err
 	}
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return result, err
 	}
 
-	participantsJID, err := service.participantToJID(request.Participants)
+	participantsJID, err := service.participantToJID(ctx, request.Participants)
 	if err != nil {
 		return result, err
 	}
 
-	participants, err := whatsapp.GetClient().UpdateGroupParticipants(ctx, groupJID, participantsJID, request.Action)
+	participants, err := whatsapp.ClientFromContext(ctx).UpdateGroupParticipants(ctx, groupJID, participantsJID, request.Action)
 	if err != nil {
 		return result, err
 	}
@@ -145,12 +155,12 @@ func (service serviceGroup) GetGroupParticipants(ctx context.Context, request do
 		return response, err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return response, err
 	}
 
-	groupInfo, err := whatsapp.GetClient().GetGroupInfo(ctx, groupJID)
+	groupInfo, err := whatsapp.ClientFromContext(ctx).GetGroupInfo(ctx, groupJID)
 	if err != nil {
 		return response, err
 	}
@@ -181,12 +191,12 @@ func (service serviceGroup) GetGroupRequestParticipants(ctx context.Context, req
 		return result, err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return result, err
 	}
 
-	participants, err := whatsapp.GetClient().GetGroupRequestParticipants(ctx, groupJID)
+	participants, err := whatsapp.ClientFromContext(ctx).GetGroupRequestParticipants(ctx, groupJID)
 	if err != nil {
 		return result, err
 	}
@@ -206,17 +216,17 @@ func (service serviceGroup) ManageGroupRequestParticipants(ctx context.Context,
 		return result, err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return result, err
 	}
 
-	participantsJID, err := service.participantToJID(request.Participants)
+	participantsJID, err := service.participantToJID(ctx, request.Participants)
 	if err != nil {
 		return result, err
 	}
 
-	participants, err := whatsapp.GetClient().UpdateGroupRequestParticipants(ctx, groupJID, participantsJID, request.Action)
+	participants, err := whatsapp.ClientFromContext(ctx).UpdateGroupRequestParticipants(ctx, groupJID, participantsJID, request.Action)
 	if err != nil {
 		return result, err
 	}
@@ -240,12 +250,12 @@ func (service serviceGroup) ManageGroupRequestParticipants(ctx context.Context,
 	return result, nil
 }
 
-func (service serviceGroup) participantToJID(participants []string) ([]types.JID, error) {
+func (service serviceGroup) participantToJID(ctx context.Context, participants []string) ([]types.JID, error) {
 	var participantsJID []types.JID
 	for _, participant := range participants {
 		formattedParticipant := participant + config.WhatsappTypeUser
 
-		if !utils.IsOnWhatsapp(whatsapp.GetClient(), formattedParticipant) {
+		if !utils.IsOnWhatsapp(whatsapp.ClientFromContext(ctx), formattedParticipant) {
 			return nil, pkgError.ErrUserNotRegistered
 		}
 
@@ -261,7 +271,7 @@ func (service serviceGroup) SetGroupPhoto(ctx context.Context, request domainGro
 		return pictureID, err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return pictureID, err
 	}
@@ -284,7 +294,7 @@ func (service serviceGroup) SetGroupPhoto(ctx context.Context, request domainGro
 		photoBytes = processedImageBuffer.Bytes()
 	}
 
-	pictureID, err = whatsapp.GetClient().SetGroupPhoto(ctx, groupJID, photoBytes)
+	pictureID, err = whatsapp.ClientFromContext(ctx).SetGroupPhoto(ctx, groupJID, photoBytes)
 	if err != nil {
 		logrus.Printf("Failed to set group photo: %v", err)
 		return pictureID, err
@@ -298,12 +308,12 @@ func (service serviceGroup) SetGroupName(ctx context.Context, request domainGrou
 		return err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return err
 	}
 
-	return whatsapp.GetClient().SetGroupName(ctx, groupJID, request.Name)
+	return whatsapp.ClientFromContext(ctx).SetGroupName(ctx, groupJID, request.Name)
 }
 
 func (service serviceGroup) SetGroupLocked(ctx context.Context, request domainGroup.SetGroupLockedRequest) (err error) {
@@ -311,12 +321,12 @@ func (service serviceGroup) SetGroupLocked(ctx context.Context, request domainGr
 		return err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return err
 	}
 
-	return whatsapp.GetClient().SetGroupLocked(ctx, groupJID, request.Locked)
+	return whatsapp.ClientFromContext(ctx).SetGroupLocked(ctx, groupJID, request.Locked)
 }
 
 func (service serviceGroup) SetGroupAnnounce(ctx context.Context, request domainGroup.SetGroupAnnounceRequest) (err error) {
@@ -324,12 +334,12 @@ func (service serviceGroup) SetGroupAnnounce(ctx context.Context, request domain
 		return err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return err
 	}
 
-	return whatsapp.GetClient().SetGroupAnnounce(ctx, groupJID, request.Announce)
+	return whatsapp.ClientFromContext(ctx).SetGroupAnnounce(ctx, groupJID, request.Announce)
 }
 
 func (service serviceGroup) SetGroupTopic(ctx context.Context, request domainGroup.SetGroupTopicRequest) (err error) {
@@ -337,13 +347,13 @@ func (service serviceGroup) SetGroupTopic(ctx context.Context, request domainGro
 		return err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return err
 	}
 
 	// SetGroupTopic with auto-generated IDs (previousID and newID will be handled automatically)
-	return whatsapp.GetClient().SetGroupTopic(ctx, groupJID, "", "", request.Topic)
+	return whatsapp.ClientFromContext(ctx).SetGroupTopic(ctx, groupJID, "", "", request.Topic)
 }
 
 // GroupInfo retrieves detailed information about a WhatsApp group
@@ -354,40 +364,75 @@ func (service serviceGroup) GroupInfo(ctx context.Context, request domainGroup.G
 	}
 
 	// Ensure we are logged in
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
 	// Validate and parse the provided group JID / ID
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return response, err
 	}
 
 	// Fetch group information from WhatsApp
-	groupInfo, err := whatsapp.GetClient().GetGroupInfo(ctx, groupJID)
+	groupInfo, err := whatsapp.ClientFromContext(ctx).GetGroupInfo(ctx, groupJID)
 	if err != nil {
 		return response, err
 	}
 
 	// Map the response
 	if groupInfo != nil {
-		response.Data = *groupInfo
+		response.Data = domainGroup.GroupInfoData{
+			GroupInfo: *groupInfo,
+			Community: service.groupCommunity(ctx, groupInfo),
+		}
 	}
 
 	return response, nil
 }
 
+// groupCommunity returns the community metadata of a group, or nil when it is a plain group
+func (service serviceGroup) groupCommunity(ctx context.Context, groupInfo *types.GroupInfo) *domainGroup.GroupCommunity {
+	if !groupInfo.IsParent && groupInfo.LinkedParentJID.IsEmpty() {
+		return nil
+	}
+
+	community := &domainGroup.GroupCommunity{
+		IsCommunity:         groupInfo.IsParent,
+		IsAnnouncementGroup: groupInfo.IsDefaultSubGroup,
+	}
+	if !groupInfo.LinkedParentJID.IsEmpty() {
+		community.CommunityID = groupInfo.LinkedParentJID.String()
+	}
+
+	if groupInfo.IsParent {
+		subGroups, err := whatsapp.ClientFromContext(ctx).GetSubGroups(ctx, groupInfo.JID)
+		if err != nil {
+			// The group info is still useful without the linked groups
+			logrus.Warnf("Failed to get linked groups of community %s: %v", groupInfo.JID, err)
+			return community
+		}
+		for _, subGroup := range subGroups {
+			community.SubGroups = append(community.SubGroups, domainGroup.GroupCommunityLink{
+				GroupID:        subGroup.JID.String(),
+				Name:           subGroup.Name,
+				IsAnnouncement: subGroup.IsDefaultSubGroup,
+			})
+		}
+	}
+	return community
+}
+
 func (service serviceGroup) GetGroupInviteLink(ctx context.Context, request domainGroup.GetGroupInviteLinkRequest) (response domainGroup.GetGroupInviteLinkResponse, err error) {
 	if err = validations.ValidateGetGroupInviteLink(ctx, request); err != nil {
 		return response, err
 	}
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return response, err
 	}
 
-	inviteLink, err := whatsapp.GetClient().GetGroupInviteLink(ctx, groupJID, request.Reset)
+	inviteLink, err := whatsapp.ClientFromContext(ctx).GetGroupInviteLink(ctx, groupJID, request.Reset)
 	if err != nil {
 		return response, err
 	}
@@ -399,3 +444,64 @@ func (service serviceGroup) GetGroupInviteLink(ctx context.Context, request doma
 
 	return response, nil
 }
+
+func (service serviceGroup) GroupHistory(ctx context.Context, request domainGroup.GroupHistoryRequest) (response domainGroup.GroupHistoryResponse, err error) {
+	if err = validations.ValidateGroupHistory(ctx, &request); err != nil {
+		return response, err
+	}
+
+	groupJID := request.GroupID
+	if !strings.ContainsRune(groupJID, '@') {
+		groupJID = types.NewJID(groupJID, types.GroupServer).String()
+	}
+
+	filter := &domainChatStorage.GroupEventFilter{
+		GroupJID: groupJID,
+		Types:    domainGroup.SplitGroupEventTypes(request.Type),
+		Limit:    request.Limit,
+		Offset:   request.Offset,
+	}
+	if request.Actor != "" {
+		filter.ActorJID = utils.FormatJID(request.Actor).String()
+	}
+	if request.Participant != "" {
+		filter.TargetJID = utils.FormatJID(request.Participant).String()
+	}
+	if request.StartTime != nil && *request.StartTime != "" {
+		startTime, _ := time.Parse(time.RFC3339, *request.StartTime)
+		filter.StartTime = &startTime
+	}
+	if request.EndTime != nil && *request.EndTime != "" {
+		endTime, _ := time.Parse(time.RFC3339, *request.EndTime)
+		filter.EndTime = &endTime
+	}
+
+	repo := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo)
+	groupEvents, err := repo.GetGroupEvents(filter)
+	if err != nil {
+		return response, err
+	}
+	total, err := repo.CountGroupEvents(filter)
+	if err != nil {
+		return response, err
+	}
+
+	response.GroupID = groupJID
+	response.Data = make([]domainGroup.GroupEvent, 0, len(groupEvents))
+	for _, groupEvent := range groupEvents {
+		response.Data = append(response.Data, domainGroup.GroupEvent{
+			ID:        groupEvent.ID,
+			GroupJID:  groupEvent.GroupJID,
+			Type:      groupEvent.Type,
+			ActorJID:  groupEvent.ActorJID,
+			TargetJID: groupEvent.TargetJID,
+			Value:     groupEvent.Value,
+			Timestamp: groupEvent.Timestamp,
+		})
+	}
+	response.Total = total
+	response.Limit = request.Limit
+	response.Offset = request.Offset
+
+	return response, nil
+}
@@ -0,0 +1,384 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// Error codes WhatsApp reports per participant when updating the participants of a group
+const (
+	participantErrorPrivacy       = 403
+	participantErrorAlreadyMember = 409
+)
+
+// reconcileTarget is a participant an action applies to. JID is how the group addresses the participant,
+// Participant is how it is reported: the phone number JID when it is known.
+type reconcileTarget struct {
+	JID         types.JID
+	Participant string
+}
+
+type reconcilePlan struct {
+	Add       []reconcileTarget
+	Remove    []reconcileTarget
+	Promote   []reconcileTarget
+	Demote    []reconcileTarget
+	Unchanged int
+	Skipped   []domainGroup.ReconcileResult
+}
+
+func (service serviceGroup) ReconcileParticipants(ctx context.Context, request domainGroup.ReconcileParticipantsRequest) (response domainGroup.ReconcileParticipantsResponse, err error) {
+	if err = validations.ValidateReconcileParticipants(ctx, &request); err != nil {
+		return response, err
+	}
+	client := whatsapp.ClientFromContext(ctx)
+	utils.MustLogin(client)
+
+	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	if err != nil {
+		return response, err
+	}
+
+	members, err := parseRoster(request.Participants)
+	if err != nil {
+		return response, err
+	}
+	admins, err := parseRoster(request.Admins)
+	if err != nil {
+		return response, err
+	}
+
+	groupInfo, err := client.GetGroupInfo(ctx, groupJID)
+	if err != nil {
+		return response, err
+	}
+
+	var self types.JID
+	if client.Store.ID != nil {
+		self = client.Store.ID.ToNonAD()
+	}
+	plan := planReconcile(groupInfo.Participants, members, admins, self)
+
+	response.GroupID = groupJID.String()
+	response.DryRun = request.DryRun
+	response.Plan = domainGroup.ReconcilePlan{
+		Add:       reconcileParticipants(plan.Add),
+		Remove:    reconcileParticipants(plan.Remove),
+		Promote:   reconcileParticipants(plan.Promote),
+		Demote:    reconcileParticipants(plan.Demote),
+		Unchanged: plan.Unchanged,
+	}
+	response.Results = append([]domainGroup.ReconcileResult{}, plan.Skipped...)
+
+	if request.DryRun {
+		for _, step := range []struct {
+			action  string
+			targets []reconcileTarget
+		}{
+			{domainGroup.ReconcileAdd, plan.Add},
+			{domainGroup.ReconcilePromote, plan.Promote},
+			{domainGroup.ReconcileDemote, plan.Demote},
+			{domainGroup.ReconcileRemove, plan.Remove},
+		} {
+			for _, target := range step.targets {
+				response.Results = append(response.Results, domainGroup.ReconcileResult{
+					Participant: target.Participant,
+					Action:      step.action,
+					Status:      domainGroup.ReconcileStatusPlanned,
+				})
+			}
+		}
+		return response, nil
+	}
+
+	batches := &reconcileBatches{size: request.BatchSize, delay: time.Duration(*request.BatchDelaySeconds) * time.Second}
+	inviter := &reconcileInviter{service: service, groupJID: groupJID, groupName: groupInfo.GroupName.Name, message: request.InviteMessage}
+
+	// Admins that are added in this run can only be promoted once they are in the group
+	added := make(map[string]bool)
+	results, err := batches.apply(ctx, groupJID, plan.Add, whatsmeow.ParticipantChangeAdd, func(target reconcileTarget, participant types.GroupParticipant) domainGroup.ReconcileResult {
+		result := participantResult(target, domainGroup.ReconcileAdd, participant)
+		switch participant.Error {
+		case 0:
+			added[target.Participant] = true
+		case participantErrorAlreadyMember:
+			added[target.Participant] = true
+			result.Status = domainGroup.ReconcileStatusSuccess
+			result.Message = "Already a participant"
+		case participantErrorPrivacy:
+			result = inviter.invite(ctx, target)
+		}
+		return result
+	})
+	response.Results = append(response.Results, results...)
+	if err != nil {
+		return response, err
+	}
+
+	pendingAdd := make(map[string]bool, len(plan.Add))
+	for _, target := range plan.Add {
+		pendingAdd[target.Participant] = true
+	}
+	var promote []reconcileTarget
+	for _, target := range plan.Promote {
+		if pendingAdd[target.Participant] && !added[target.Participant] {
+			response.Results = append(response.Results, domainGroup.ReconcileResult{
+				Participant: target.Participant,
+				Action:      domainGroup.ReconcilePromote,
+				Status:      domainGroup.ReconcileStatusSkipped,
+				Message:     "Not added to the group",
+			})
+			continue
+		}
+		promote = append(promote, target)
+	}
+
+	for _, step := range []struct {
+		action  string
+		change  whatsmeow.ParticipantChange
+		targets []reconcileTarget
+	}{
+		{domainGroup.ReconcilePromote, whatsmeow.ParticipantChangePromote, promote},
+		{domainGroup.ReconcileDemote, whatsmeow.ParticipantChangeDemote, plan.Demote},
+		{domainGroup.ReconcileRemove, whatsmeow.ParticipantChangeRemove, plan.Remove},
+	} {
+		action := step.action
+		results, err := batches.apply(ctx, groupJID, step.targets, step.change, func(target reconcileTarget, participant types.GroupParticipant) domainGroup.ReconcileResult {
+			return participantResult(target, action, participant)
+		})
+		response.Results = append(response.Results, results...)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	return response, nil
+}
+
+// planReconcile computes the changes that turn the current participants into the desired roster. Participants
+// are matched by phone number; members WhatsApp only reports by LID are left alone, as are this account and
+// the group creator, who cannot be removed or demoted.
+func planReconcile(current []types.GroupParticipant, members []types.JID, admins []types.JID, self types.JID) reconcilePlan {
+	var plan reconcilePlan
+
+	desired := make(map[string]types.JID)
+	wantAdmin := make(map[string]bool)
+	var order []string
+	for _, jid := range append(append([]types.JID{}, members...), admins...) {
+		if _, ok := desired[jid.User]; !ok {
+			desired[jid.User] = jid
+			order = append(order, jid.User)
+		}
+	}
+	for _, jid := range admins {
+		wantAdmin[jid.User] = true
+	}
+
+	present := make(map[string]bool)
+	for _, participant := range current {
+		phone := participantPhone(participant)
+		if phone.IsEmpty() {
+			continue
+		}
+		present[phone.User] = true
+		target := reconcileTarget{JID: participant.JID, Participant: phone.String()}
+
+		if phone.User == self.User {
+			continue
+		}
+
+		_, isDesired := desired[phone.User]
+		switch {
+		case !isDesired && participant.IsSuperAdmin:
+			plan.Skipped = append(plan.Skipped, domainGroup.ReconcileResult{
+				Participant: target.Participant,
+				Action:      domainGroup.ReconcileRemove,
+				Status:      domainGroup.ReconcileStatusSkipped,
+				Message:     "The group creator cannot be removed",
+			})
+		case !isDesired:
+			plan.Remove = append(plan.Remove, target)
+		case wantAdmin[phone.User] && !participant.IsAdmin && !participant.IsSuperAdmin:
+			plan.Promote = append(plan.Promote, target)
+		case !wantAdmin[phone.User] && participant.IsSuperAdmin:
+			plan.Skipped = append(plan.Skipped, domainGroup.ReconcileResult{
+				Participant: target.Participant,
+				Action:      domainGroup.ReconcileDemote,
+				Status:      domainGroup.ReconcileStatusSkipped,
+				Message:     "The group creator cannot be demoted",
+			})
+		case !wantAdmin[phone.User] && participant.IsAdmin:
+			plan.Demote = append(plan.Demote, target)
+		default:
+			plan.Unchanged++
+		}
+	}
+
+	for _, user := range order {
+		if present[user] || user == self.User {
+			continue
+		}
+		jid := desired[user]
+		target := reconcileTarget{JID: jid, Participant: jid.String()}
+		plan.Add = append(plan.Add, target)
+		if wantAdmin[user] {
+			plan.Promote = append(plan.Promote, target)
+		}
+	}
+
+	return plan
+}
+
+// participantPhone returns the phone number JID of a participant, or an empty JID when only its LID is known
+func participantPhone(participant types.GroupParticipant) types.JID {
+	if !participant.PhoneNumber.IsEmpty() {
+		return participant.PhoneNumber.ToNonAD()
+	}
+	if participant.JID.Server == types.DefaultUserServer {
+		return participant.JID.ToNonAD()
+	}
+	return types.JID{}
+}
+
+func parseRoster(participants []string) ([]types.JID, error) {
+	jids := make([]types.JID, 0, len(participants))
+	for _, participant := range participants {
+		jid, err := utils.ParseJID(participant)
+		if err != nil {
+			return nil, err
+		}
+		jids = append(jids, jid.ToNonAD())
+	}
+	return jids, nil
+}
+
+func reconcileParticipants(targets []reconcileTarget) []string {
+	participants := make([]string, 0, len(targets))
+	for _, target := range targets {
+		participants = append(participants, target.Participant)
+	}
+	return participants
+}
+
+func participantResult(target reconcileTarget, action string, participant types.GroupParticipant) domainGroup.ReconcileResult {
+	result := domainGroup.ReconcileResult{
+		Participant: target.Participant,
+		Action:      action,
+		Status:      domainGroup.ReconcileStatusSuccess,
+	}
+	if participant.Error != 0 {
+		result.Status = domainGroup.ReconcileStatusError
+		result.Message = fmt.Sprintf("WhatsApp rejected the change with code %d", participant.Error)
+	}
+	return result
+}
+
+// reconcileBatches sends participant changes in batches, pausing between batches so large rosters do not
+// trip WhatsApp's rate limits
+type reconcileBatches struct {
+	size  int
+	delay time.Duration
+	sent  bool
+}
+
+// apply sends change for targets and maps the outcome of every target with result. It stops at the first
+// batch WhatsApp rejects as a whole or when ctx is done.
+func (b *reconcileBatches) apply(ctx context.Context, groupJID types.JID, targets []reconcileTarget, change whatsmeow.ParticipantChange, result func(target reconcileTarget, participant types.GroupParticipant) domainGroup.ReconcileResult) ([]domainGroup.ReconcileResult, error) {
+	var results []domainGroup.ReconcileResult
+	for start := 0; start < len(targets); start += b.size {
+		if b.sent && b.delay > 0 {
+			select {
+			case <-ctx.Done():
+				return results, ctx.Err()
+			case <-time.After(b.delay):
+			}
+		}
+		b.sent = true
+
+		batch := targets[start:min(start+b.size, len(targets))]
+		jids := make([]types.JID, 0, len(batch))
+		byUser := make(map[string]reconcileTarget, len(batch))
+		for _, target := range batch {
+			jids = append(jids, target.JID)
+			byUser[target.JID.User] = target
+		}
+
+		participants, err := whatsapp.ClientFromContext(ctx).UpdateGroupParticipants(ctx, groupJID, jids, change)
+		if err != nil {
+			return results, fmt.Errorf("failed to %s participants of %s: %w", change, groupJID, err)
+		}
+
+		for _, participant := range participants {
+			target, ok := byUser[participant.JID.User]
+			if !ok {
+				target, ok = byUser[participant.PhoneNumber.User]
+			}
+			if !ok {
+				target = reconcileTarget{JID: participant.JID, Participant: participant.JID.String()}
+			}
+			results = append(results, result(target, participant))
+		}
+	}
+	return results, nil
+}
+
+// reconcileInviter sends the invite link of the group to participants whose privacy settings block being added
+type reconcileInviter struct {
+	service   serviceGroup
+	groupJID  types.JID
+	groupName string
+	message   string
+	link      string
+}
+
+func (i *reconcileInviter) invite(ctx context.Context, target reconcileTarget) domainGroup.ReconcileResult {
+	result := domainGroup.ReconcileResult{
+		Participant: target.Participant,
+		Action:      domainGroup.ReconcileInvite,
+		Status:      domainGroup.ReconcileStatusInvited,
+		Message:     "Privacy settings block adding, invite link sent",
+	}
+
+	if err := i.send(ctx, target); err != nil {
+		logrus.Warnf("[GROUP] Failed to send the invite link of %s to %s: %v", i.groupJID, target.Participant, err)
+		result.Status = domainGroup.ReconcileStatusError
+		result.Message = fmt.Sprintf("Privacy settings block adding and the invite link could not be sent: %v", err)
+	}
+	return result
+}
+
+func (i *reconcileInviter) send(ctx context.Context, target reconcileTarget) (err error) {
+	if i.service.sendService == nil {
+		return fmt.Errorf("sending messages is not available")
+	}
+	// Sending panics when the device disconnected meanwhile, which must not lose the results so far
+	defer recoverSendPanic(&err)
+
+	if i.link == "" {
+		link, err := whatsapp.ClientFromContext(ctx).GetGroupInviteLink(ctx, i.groupJID, false)
+		if err != nil {
+			return fmt.Errorf("failed to get invite link: %w", err)
+		}
+		i.link = link
+	}
+
+	message := i.message
+	if message == "" {
+		message = fmt.Sprintf("You are invited to join the WhatsApp group %s", i.groupName)
+	}
+	_, err = i.service.sendService.SendText(ctx, domainSend.MessageRequest{
+		BaseRequest: domainSend.BaseRequest{Phone: target.Participant},
+		Message:     message + "\n" + i.link,
+	})
+	return err
+}
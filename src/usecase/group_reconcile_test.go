@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestPlanReconcile(t *testing.T) {
+	phone := func(user string) types.JID { return types.NewJID(user, types.DefaultUserServer) }
+	lid := func(user string) types.JID { return types.NewJID(user, types.HiddenUserServer) }
+
+	current := []types.GroupParticipant{
+		{JID: phone("111"), PhoneNumber: phone("111"), IsSuperAdmin: true},             // this account
+		{JID: lid("9222"), PhoneNumber: phone("222"), LID: lid("9222"), IsAdmin: true}, // stays admin
+		{JID: phone("333"), IsAdmin: true},                                             // demoted
+		{JID: phone("444")},                                                            // promoted
+		{JID: phone("555")},                                                            // removed
+		{JID: phone("666"), IsSuperAdmin: true},                                        // creator, cannot be removed
+		{JID: lid("9777"), LID: lid("9777")},                                           // unknown phone number
+		{JID: phone("888")},                                                            // unchanged
+	}
+	members := []types.JID{phone("222"), phone("333"), phone("888"), phone("999"), phone("111")}
+	admins := []types.JID{phone("222"), phone("444"), phone("100")}
+
+	got := planReconcile(current, members, admins, phone("111"))
+	want := reconcilePlan{
+		Add: []reconcileTarget{
+			{JID: phone("999"), Participant: "999@s.whatsapp.net"},
+			{JID: phone("100"), Participant: "100@s.whatsapp.net"},
+		},
+		Remove: []reconcileTarget{{JID: phone("555"), Participant: "555@s.whatsapp.net"}},
+		Promote: []reconcileTarget{
+			{JID: phone("444"), Participant: "444@s.whatsapp.net"},
+			{JID: phone("100"), Participant: "100@s.whatsapp.net"},
+		},
+		Demote:    []reconcileTarget{{JID: phone("333"), Participant: "333@s.whatsapp.net"}},
+		Unchanged: 2,
+		Skipped: []domainGroup.ReconcileResult{{
+			Participant: "666@s.whatsapp.net",
+			Action:      domainGroup.ReconcileRemove,
+			Status:      domainGroup.ReconcileStatusSkipped,
+			Message:     "The group creator cannot be removed",
+		}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("planReconcile() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPlanReconcileKeepsCurrentRoster(t *testing.T) {
+	phone := func(user string) types.JID { return types.NewJID(user, types.DefaultUserServer) }
+	current := []types.GroupParticipant{
+		{JID: phone("222"), IsAdmin: true},
+		{JID: phone("333")},
+	}
+
+	got := planReconcile(current, []types.JID{phone("333")}, []types.JID{phone("222")}, phone("111"))
+	if len(got.Add)+len(got.Remove)+len(got.Promote)+len(got.Demote)+len(got.Skipped) != 0 || got.Unchanged != 2 {
+		t.Fatalf("planReconcile() = %+v, want no changes", got)
+	}
+}
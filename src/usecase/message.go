@@ -11,6 +11,7 @@ import (
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
@@ -37,13 +38,13 @@ func (service serviceMessage) MarkAsRead(ctx context.Context, request domainMess
 	if err = validations.ValidateMarkAsRead(ctx, request); err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.Phone)
 	if err != nil {
 		return response, err
 	}
 
 	ids := []types.MessageID{request.MessageID}
-	if err = whatsapp.GetClient().MarkRead(ctx, ids, time.Now(), dataWaRecipient, *whatsapp.GetClient().Store.ID); err != nil {
+	if err = whatsapp.ClientFromContext(ctx).MarkRead(ctx, ids, time.Now(), dataWaRecipient, *whatsapp.ClientFromContext(ctx).Store.ID); err != nil {
 		return response, err
 	}
 
@@ -51,7 +52,7 @@ func (service serviceMessage) MarkAsRead(ctx context.Context, request domainMess
 		"phone":      request.Phone,
 		"message_id": request.MessageID,
 		"chat":       dataWaRecipient.String(),
-		"sender":     whatsapp.GetClient().Store.ID.String(),
+		"sender":     whatsapp.ClientFromContext(ctx).Store.ID.String(),
 	})
 
 	response.MessageID = request.MessageID
@@ -63,7 +64,7 @@ func (service serviceMessage) ReactMessage(ctx context.Context, request domainMe
 	if err = validations.ValidateReactMessage(ctx, request); err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -79,11 +80,28 @@ func (service serviceMessage) ReactMessage(ctx context.Context, request domainMe
 			SenderTimestampMS: proto.Int64(time.Now().UnixMilli()),
 		},
 	}
-	ts, err := whatsapp.GetClient().SendMessage(ctx, dataWaRecipient, msg)
+	if err = authorizeRecipient(ctx, dataWaRecipient.String()); err != nil {
+		return response, err
+	}
+
+	ts, err := whatsapp.ClientFromContext(ctx).SendMessage(ctx, dataWaRecipient, msg)
 	if err != nil {
 		return response, err
 	}
 
+	if ownID := whatsapp.ClientFromContext(ctx).Store.ID; ownID != nil {
+		reaction := &domainChatStorage.MessageReaction{
+			MessageID: request.MessageID,
+			ChatJID:   dataWaRecipient.String(),
+			SenderJID: ownID.ToNonAD().String(),
+			Reaction:  request.Emoji,
+			ReactedAt: ts.Timestamp,
+		}
+		if err := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo).StoreMessageReaction(reaction); err != nil {
+			logrus.Warnf("Failed to store reaction to message %s: %v", request.MessageID, err)
+		}
+	}
+
 	response.MessageID = ts.ID
 	response.Status = fmt.Sprintf("Reaction sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp)
 	return response, nil
@@ -93,16 +111,24 @@ func (service serviceMessage) RevokeMessage(ctx context.Context, request domainM
 	if err = validations.ValidateRevokeMessage(ctx, request); err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.Phone)
 	if err != nil {
 		return response, err
 	}
 
-	ts, err := whatsapp.GetClient().SendMessage(context.Background(), dataWaRecipient, whatsapp.GetClient().BuildRevoke(dataWaRecipient, types.EmptyJID, request.MessageID))
+	if err = authorizeRecipient(ctx, dataWaRecipient.String()); err != nil {
+		return response, err
+	}
+
+	ts, err := whatsapp.ClientFromContext(ctx).SendMessage(context.Background(), dataWaRecipient, whatsapp.ClientFromContext(ctx).BuildRevoke(dataWaRecipient, types.EmptyJID, request.MessageID))
 	if err != nil {
 		return response, err
 	}
 
+	if err := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo).RevokeMessage(request.MessageID, dataWaRecipient.String(), ts.Timestamp); err != nil {
+		logrus.Warnf("Failed to store revocation of message %s: %v", request.MessageID, err)
+	}
+
 	response.MessageID = ts.ID
 	response.Status = fmt.Sprintf("Revoke success %s (server timestamp: %s)", request.Phone, ts.Timestamp)
 	return response, nil
@@ -112,7 +138,7 @@ func (service serviceMessage) DeleteMessage(ctx context.Context, request domainM
 	if err = validations.ValidateDeleteMessage(ctx, request); err != nil {
 		return err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.Phone)
 	if err != nil {
 		return err
 	}
@@ -126,7 +152,7 @@ func (service serviceMessage) DeleteMessage(ctx context.Context, request domainM
 		Timestamp: time.Now(),
 		Type:      appstate.WAPatchRegularHigh,
 		Mutations: []appstate.MutationInfo{{
-			Index: []string{appstate.IndexDeleteMessageForMe, dataWaRecipient.String(), request.MessageID, isFromMe, whatsapp.GetClient().Store.ID.String()},
+			Index: []string{appstate.IndexDeleteMessageForMe, dataWaRecipient.String(), request.MessageID, isFromMe, whatsapp.ClientFromContext(ctx).Store.ID.String()},
 			Value: &waSyncAction.SyncActionValue{
 				DeleteMessageForMeAction: &waSyncAction.DeleteMessageForMeAction{
 					DeleteMedia:      proto.Bool(true),
@@ -136,7 +162,7 @@ func (service serviceMessage) DeleteMessage(ctx context.Context, request domainM
 		}},
 	}
 
-	if err = whatsapp.GetClient().SendAppState(ctx, patchInfo); err != nil {
+	if err = whatsapp.ClientFromContext(ctx).SendAppState(ctx, patchInfo); err != nil {
 		return err
 	}
 	return nil
@@ -147,17 +173,32 @@ func (service serviceMessage) UpdateMessage(ctx context.Context, request domainM
 		return response, err
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.Phone)
 	if err != nil {
 		return response, err
 	}
 
+	if err = authorizeRecipient(ctx, dataWaRecipient.String()); err != nil {
+		return response, err
+	}
+
 	msg := &waE2E.Message{Conversation: proto.String(request.Message)}
-	ts, err := whatsapp.GetClient().SendMessage(context.Background(), dataWaRecipient, whatsapp.GetClient().BuildEdit(dataWaRecipient, request.MessageID, msg))
+	ts, err := whatsapp.ClientFromContext(ctx).SendMessage(context.Background(), dataWaRecipient, whatsapp.ClientFromContext(ctx).BuildEdit(dataWaRecipient, request.MessageID, msg))
 	if err != nil {
 		return response, err
 	}
 
+	edit := &domainChatStorage.MessageEdit{
+		ID:         ts.ID,
+		MessageID:  request.MessageID,
+		ChatJID:    dataWaRecipient.String(),
+		NewContent: request.Message,
+		EditedAt:   ts.Timestamp,
+	}
+	if err := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo).StoreMessageEdit(edit); err != nil {
+		logrus.Warnf("Failed to store edit of message %s: %v", request.MessageID, err)
+	}
+
 	response.MessageID = ts.ID
 	response.Status = fmt.Sprintf("Update message success %s (server timestamp: %s)", request.Phone, ts.Timestamp)
 	return response, nil
@@ -169,7 +210,7 @@ func (service serviceMessage) StarMessage(ctx context.Context, request domainMes
 		return err
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.Phone)
 	if err != nil {
 		return err
 	}
@@ -179,9 +220,9 @@ func (service serviceMessage) StarMessage(ctx context.Context, request domainMes
 		isFromMe = false
 	}
 
-	patchInfo := appstate.BuildStar(dataWaRecipient.ToNonAD(), *whatsapp.GetClient().Store.ID, request.MessageID, isFromMe, request.IsStarred)
+	patchInfo := appstate.BuildStar(dataWaRecipient.ToNonAD(), *whatsapp.ClientFromContext(ctx).Store.ID, request.MessageID, isFromMe, request.IsStarred)
 
-	if err = whatsapp.GetClient().SendAppState(ctx, patchInfo); err != nil {
+	if err = whatsapp.ClientFromContext(ctx).SendAppState(ctx, patchInfo); err != nil {
 		return err
 	}
 	return nil
@@ -193,13 +234,13 @@ func (service serviceMessage) DownloadMedia(ctx context.Context, request domainM
 		return response, err
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.Phone)
 	if err != nil {
 		return response, err
 	}
 
 	// Query the message from chat storage
-	message, err := service.chatStorageRepo.GetMessageByID(request.MessageID)
+	message, err := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo).GetMessageByID(request.MessageID)
 	if err != nil {
 		return response, fmt.Errorf("message not found: %v", err)
 	}
@@ -277,7 +318,7 @@ func (service serviceMessage) DownloadMedia(ctx context.Context, request domainM
 	}
 
 	// Download the media using existing utils.ExtractMedia function
-	extractedMedia, err := utils.ExtractMedia(ctx, whatsapp.GetClient(), dateDir, downloadableMsg.(whatsmeow.DownloadableMessage))
+	extractedMedia, err := utils.ExtractMedia(ctx, whatsapp.ClientFromContext(ctx), dateDir, downloadableMsg.(whatsmeow.DownloadableMessage))
 	if err != nil {
 		return response, fmt.Errorf("failed to download media: %v", err)
 	}
@@ -309,3 +350,105 @@ func (service serviceMessage) DownloadMedia(ctx context.Context, request domainM
 
 	return response, nil
 }
+
+func (service serviceMessage) GetMessageStatus(ctx context.Context, request domainMessage.MessageStatusRequest) (response domainMessage.MessageStatusResponse, err error) {
+	if err = validations.ValidateMessageStatus(ctx, request); err != nil {
+		return response, err
+	}
+
+	chatStorageRepo := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo)
+	message, err := chatStorageRepo.GetMessageByID(request.MessageID)
+	if err != nil {
+		return response, err
+	}
+
+	receipts, err := chatStorageRepo.GetMessageReceipts([]string{request.MessageID})
+	if err != nil {
+		return response, err
+	}
+
+	if message == nil && len(receipts) == 0 {
+		return response, pkgError.ErrMessageNotFound
+	}
+
+	response = summarizeMessageReceipts(request.MessageID, receipts)
+	if message != nil {
+		response.ChatJID = message.ChatJID
+		response.IsFromMe = message.IsFromMe
+		if message.IsFromMe && response.Status == "" {
+			response.Status = domainChatStorage.MessageStatusSent
+		}
+	} else {
+		// Receipts are only recorded for messages we sent
+		response.IsFromMe = true
+	}
+
+	return response, nil
+}
+
+// summarizeMessageReceipts folds the receipts of one message into its overall status, the furthest state
+// any recipient reached, and the state per recipient
+func summarizeMessageReceipts(messageID string, receipts []*domainChatStorage.MessageReceipt) domainMessage.MessageStatusResponse {
+	response := domainMessage.MessageStatusResponse{
+		MessageID:    messageID,
+		Participants: []domainMessage.ParticipantStatus{},
+	}
+
+	participants := make(map[string]int)
+	for _, receipt := range receipts {
+		if response.ChatJID == "" {
+			response.ChatJID = receipt.ChatJID
+		}
+		if domainChatStorage.MessageStatusRank(receipt.Status) > domainChatStorage.MessageStatusRank(response.Status) {
+			response.Status = receipt.Status
+		}
+
+		timestamp := receipt.Timestamp
+		if receipt.ParticipantJID == "" {
+			response.ServerAckAt = &timestamp
+			continue
+		}
+
+		index, ok := participants[receipt.ParticipantJID]
+		if !ok {
+			index = len(response.Participants)
+			participants[receipt.ParticipantJID] = index
+			response.Participants = append(response.Participants, domainMessage.ParticipantStatus{JID: receipt.ParticipantJID})
+		}
+
+		participant := &response.Participants[index]
+		if domainChatStorage.MessageStatusRank(receipt.Status) > domainChatStorage.MessageStatusRank(participant.Status) {
+			participant.Status = receipt.Status
+		}
+		switch receipt.Status {
+		case domainChatStorage.MessageStatusDelivered:
+			participant.DeliveredAt = &timestamp
+		case domainChatStorage.MessageStatusRead:
+			participant.ReadAt = &timestamp
+		case domainChatStorage.MessageStatusPlayed:
+			participant.PlayedAt = &timestamp
+		}
+	}
+
+	return response
+}
+
+// messageStatuses returns the overall delivery status of each of the given messages that has receipts
+func messageStatuses(chatStorageRepo domainChatStorage.IChatStorageRepository, messageIDs []string) (map[string]string, error) {
+	statuses := make(map[string]string)
+	if len(messageIDs) == 0 {
+		return statuses, nil
+	}
+
+	receipts, err := chatStorageRepo.GetMessageReceipts(messageIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, receipt := range receipts {
+		if domainChatStorage.MessageStatusRank(receipt.Status) > domainChatStorage.MessageStatusRank(statuses[receipt.MessageID]) {
+			statuses[receipt.MessageID] = receipt.Status
+		}
+	}
+	return statuses, nil
+}
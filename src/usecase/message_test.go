@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+)
+
+func TestSummarizeMessageReceipts(t *testing.T) {
+	sentAt := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	deliveredAt := sentAt.Add(time.Second)
+	readAt := sentAt.Add(time.Minute)
+
+	receipts := []*domainChatStorage.MessageReceipt{
+		{MessageID: "ID1", ChatJID: "123@g.us", Status: domainChatStorage.MessageStatusServerAck, Timestamp: sentAt},
+		{MessageID: "ID1", ChatJID: "123@g.us", ParticipantJID: "111@s.whatsapp.net", Status: domainChatStorage.MessageStatusDelivered, Timestamp: deliveredAt},
+		{MessageID: "ID1", ChatJID: "123@g.us", ParticipantJID: "222@s.whatsapp.net", Status: domainChatStorage.MessageStatusDelivered, Timestamp: deliveredAt},
+		{MessageID: "ID1", ChatJID: "123@g.us", ParticipantJID: "111@s.whatsapp.net", Status: domainChatStorage.MessageStatusRead, Timestamp: readAt},
+	}
+
+	got := summarizeMessageReceipts("ID1", receipts)
+	want := domainMessage.MessageStatusResponse{
+		MessageID:   "ID1",
+		ChatJID:     "123@g.us",
+		Status:      domainChatStorage.MessageStatusRead,
+		ServerAckAt: &sentAt,
+		Participants: []domainMessage.ParticipantStatus{
+			{JID: "111@s.whatsapp.net", Status: domainChatStorage.MessageStatusRead, DeliveredAt: &deliveredAt, ReadAt: &readAt},
+			{JID: "222@s.whatsapp.net", Status: domainChatStorage.MessageStatusDelivered, DeliveredAt: &deliveredAt},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("summarizeMessageReceipts() = %+v, want %+v", got, want)
+	}
+
+	empty := summarizeMessageReceipts("ID2", nil)
+	if empty.Status != "" || len(empty.Participants) != 0 || empty.ServerAckAt != nil {
+		t.Fatalf("summarizeMessageReceipts() without receipts = %+v", empty)
+	}
+}
@@ -1,18 +1,124 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
 
 	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/disintegration/imaging"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
 )
 
-type serviceNewsletter struct{}
+// mutationUpdateNewsletter is the GraphQL query ID whatsmeow uses internally to update channel metadata,
+// it has no public method for it yet.
+const mutationUpdateNewsletter = "7150902998257522"
 
-func NewNewsletterService() domainNewsletter.INewsletterUsecase {
-	return &serviceNewsletter{}
+const newsletterInvitePath = "whatsapp.com/channel/"
+
+type serviceNewsletter struct {
+	sendService domainSend.ISendUsecase
+	upload      func(ctx context.Context, media []byte, mediaType whatsmeow.MediaType) (whatsmeow.UploadResponse, error)
+	send        func(ctx context.Context, to types.JID, message *waE2E.Message, extra ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error)
+}
+
+func NewNewsletterService(sendService domainSend.ISendUsecase) domainNewsletter.INewsletterUsecase {
+	return &serviceNewsletter{
+		sendService: sendService,
+		upload: func(ctx context.Context, media []byte, mediaType whatsmeow.MediaType) (whatsmeow.UploadResponse, error) {
+			return whatsapp.ClientFromContext(ctx).UploadNewsletter(ctx, media, mediaType)
+		},
+		send: func(ctx context.Context, to types.JID, message *waE2E.Message, extra ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
+			return whatsapp.ClientFromContext(ctx).SendMessage(ctx, to, message, extra...)
+		},
+	}
+}
+
+func (service serviceNewsletter) Create(ctx context.Context, request domainNewsletter.CreateRequest) (response *types.NewsletterMetadata, err error) {
+	if err = validations.ValidateCreateNewsletter(ctx, request); err != nil {
+		return response, err
+	}
+	client := whatsapp.ClientFromContext(ctx)
+	utils.MustLogin(client)
+
+	picture, err := newsletterPicture(request.Picture)
+	if err != nil {
+		return response, err
+	}
+
+	return client.CreateNewsletter(ctx, whatsmeow.CreateNewsletterParams{
+		Name:        strings.TrimSpace(request.Name),
+		Description: strings.TrimSpace(request.Description),
+		Picture:     picture,
+	})
+}
+
+func (service serviceNewsletter) Update(ctx context.Context, request domainNewsletter.UpdateRequest) (response *types.NewsletterMetadata, err error) {
+	if err = validations.ValidateUpdateNewsletter(ctx, request); err != nil {
+		return response, err
+	}
+	newsletterJID, err := service.newsletterJID(ctx, request.NewsletterID)
+	if err != nil {
+		return response, err
+	}
+
+	picture, err := newsletterPicture(request.Picture)
+	if err != nil {
+		return response, err
+	}
+
+	updates := map[string]any{}
+	if name := strings.TrimSpace(request.Name); name != "" {
+		updates["name"] = name
+	}
+	if description := strings.TrimSpace(request.Description); description != "" {
+		updates["description"] = description
+	}
+	if picture != nil {
+		updates["picture"] = picture
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if _, err = client.DangerousInternals().SendMexIQ(ctx, mutationUpdateNewsletter, map[string]any{
+		"newsletter_id": newsletterJID.String(),
+		"updates":       updates,
+	}); err != nil {
+		return response, err
+	}
+
+	return client.GetNewsletterInfo(ctx, newsletterJID)
+}
+
+func (service serviceNewsletter) Follow(ctx context.Context, request domainNewsletter.FollowRequest) (response *types.NewsletterMetadata, err error) {
+	if err = validations.ValidateFollowNewsletter(ctx, request); err != nil {
+		return response, err
+	}
+	client := whatsapp.ClientFromContext(ctx)
+	utils.MustLogin(client)
+
+	response, err = client.GetNewsletterInfoWithInvite(ctx, newsletterInviteCode(request.Invite))
+	if err != nil {
+		return response, err
+	}
+
+	if err = client.FollowNewsletter(ctx, response.ID); err != nil {
+		return response, err
+	}
+	return response, nil
 }
 
 func (service serviceNewsletter) Unfollow(ctx context.Context, request domainNewsletter.UnfollowRequest) (err error) {
@@ -20,10 +126,251 @@ func (service serviceNewsletter) Unfollow(ctx context.Context, request domainNew
 		return err
 	}
 
-	JID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.NewsletterID)
+	JID, err := service.newsletterJID(ctx, request.NewsletterID)
+	if err != nil {
+		return err
+	}
+
+	return whatsapp.ClientFromContext(ctx).UnfollowNewsletter(ctx, JID)
+}
+
+func (service serviceNewsletter) Info(ctx context.Context, request domainNewsletter.InfoRequest) (response *types.NewsletterMetadata, err error) {
+	if err = validations.ValidateNewsletterInfo(ctx, request); err != nil {
+		return response, err
+	}
+
+	newsletterJID, err := service.newsletterJID(ctx, request.NewsletterID)
+	if err != nil {
+		return response, err
+	}
+
+	return whatsapp.ClientFromContext(ctx).GetNewsletterInfo(ctx, newsletterJID)
+}
+
+func (service serviceNewsletter) Messages(ctx context.Context, request domainNewsletter.MessagesRequest) (response domainNewsletter.MessagesResponse, err error) {
+	if err = validations.ValidateNewsletterMessages(ctx, &request); err != nil {
+		return response, err
+	}
+
+	newsletterJID, err := service.newsletterJID(ctx, request.NewsletterID)
+	if err != nil {
+		return response, err
+	}
+
+	messages, err := whatsapp.ClientFromContext(ctx).GetNewsletterMessages(ctx, newsletterJID, &whatsmeow.GetNewsletterMessagesParams{
+		Count:  request.Limit,
+		Before: types.MessageServerID(request.Before),
+	})
+	if err != nil {
+		return response, err
+	}
+
+	response.NewsletterID = newsletterJID.String()
+	response.Data = toNewsletterMessages(messages)
+	return response, nil
+}
+
+func (service serviceNewsletter) Publish(ctx context.Context, request domainNewsletter.PublishRequest) (response domainNewsletter.PublishResponse, err error) {
+	if err = validations.ValidatePublishNewsletter(ctx, request); err != nil {
+		return response, err
+	}
+
+	newsletterJID, err := service.newsletterJID(ctx, request.NewsletterID)
+	if err != nil {
+		return response, err
+	}
+
+	// Channel media is not end-to-end encrypted, so it is uploaded and sent differently from chat media
+	var media []byte
+	var sent domainSend.GenericResponse
+	switch {
+	case request.Image != nil || (request.ImageURL != nil && *request.ImageURL != ""):
+		if media, err = newsletterMedia(request.Image, request.ImageURL, utils.DownloadImageFromURL); err != nil {
+			return response, err
+		}
+		sent, err = service.publishMedia(ctx, newsletterJID, whatsmeow.MediaImage, media, request.Message)
+	case request.Video != nil || (request.VideoURL != nil && *request.VideoURL != ""):
+		if media, err = newsletterMedia(request.Video, request.VideoURL, utils.DownloadVideoFromURL); err != nil {
+			return response, err
+		}
+		sent, err = service.publishMedia(ctx, newsletterJID, whatsmeow.MediaVideo, media, request.Message)
+	default:
+		sent, err = service.sendService.SendText(ctx, domainSend.MessageRequest{
+			BaseRequest: domainSend.BaseRequest{Phone: newsletterJID.String()},
+			Message:     request.Message,
+		})
+	}
+	if err != nil {
+		return response, err
+	}
+
+	response.NewsletterID = newsletterJID.String()
+	response.MessageID = sent.MessageID
+	response.Status = sent.Status
+	return response, nil
+}
+
+// publishMedia uploads media to the channel media servers and posts it with caption. The upload handle has to
+// be sent along with the message, channel media carries no encryption keys.
+func (service serviceNewsletter) publishMedia(ctx context.Context, newsletterJID types.JID, mediaType whatsmeow.MediaType, media []byte, caption string) (response domainSend.GenericResponse, err error) {
+	if err = authorizeRecipient(ctx, newsletterJID.String()); err != nil {
+		return response, err
+	}
+
+	uploaded, err := service.upload(ctx, media, mediaType)
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to upload channel media %v", err))
+	}
+
+	message := &waE2E.Message{}
+	switch mediaType {
+	case whatsmeow.MediaImage:
+		message.ImageMessage = &waE2E.ImageMessage{
+			JPEGThumbnail: newsletterThumbnail(media),
+			Caption:       proto.String(caption),
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			Mimetype:      proto.String(http.DetectContentType(media)),
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uploaded.FileLength),
+		}
+	case whatsmeow.MediaVideo:
+		message.VideoMessage = &waE2E.VideoMessage{
+			Caption:    proto.String(caption),
+			URL:        proto.String(uploaded.URL),
+			DirectPath: proto.String(uploaded.DirectPath),
+			Mimetype:   proto.String(http.DetectContentType(media)),
+			FileSHA256: uploaded.FileSHA256,
+			FileLength: proto.Uint64(uploaded.FileLength),
+		}
+	default:
+		return response, pkgError.ValidationError(fmt.Sprintf("unsupported channel media type %s", mediaType))
+	}
+
+	sent, err := service.send(ctx, newsletterJID, message, whatsmeow.SendRequestExtra{MediaHandle: uploaded.Handle})
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = sent.ID
+	response.Status = fmt.Sprintf("Message sent to %s (server timestamp: %s)", newsletterJID.String(), sent.Timestamp.String())
+	return response, nil
+}
+
+func (service serviceNewsletter) MarkViewed(ctx context.Context, request domainNewsletter.MarkViewedRequest) (err error) {
+	if err = validations.ValidateMarkNewsletterViewed(ctx, request); err != nil {
+		return err
+	}
+
+	newsletterJID, err := service.newsletterJID(ctx, request.NewsletterID)
 	if err != nil {
 		return err
 	}
 
-	return whatsapp.GetClient().UnfollowNewsletter(ctx, JID)
+	serverIDs := make([]types.MessageServerID, 0, len(request.ServerIDs))
+	for _, serverID := range request.ServerIDs {
+		serverIDs = append(serverIDs, types.MessageServerID(serverID))
+	}
+	return whatsapp.ClientFromContext(ctx).NewsletterMarkViewed(ctx, newsletterJID, serverIDs)
+}
+
+// newsletterJID parses a channel JID, a bare numeric ID gets the newsletter server
+func (service serviceNewsletter) newsletterJID(ctx context.Context, newsletterID string) (types.JID, error) {
+	newsletterID = strings.TrimSpace(newsletterID)
+	if newsletterID != "" && !strings.Contains(newsletterID, "@") {
+		newsletterID = fmt.Sprintf("%s@%s", newsletterID, types.NewsletterServer)
+	}
+
+	return utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), newsletterID)
+}
+
+// newsletterInviteCode returns the code of a channel invite link, codes are returned unchanged
+func newsletterInviteCode(invite string) string {
+	code := strings.TrimSpace(invite)
+	if idx := strings.Index(code, newsletterInvitePath); idx != -1 {
+		code = code[idx+len(newsletterInvitePath):]
+	}
+	if idx := strings.IndexAny(code, "/?#"); idx != -1 {
+		code = code[:idx]
+	}
+	return code
+}
+
+// newsletterMedia reads an uploaded file, or downloads mediaURL when no file was uploaded
+func newsletterMedia(file *multipart.FileHeader, mediaURL *string, download func(string) ([]byte, string, error)) ([]byte, error) {
+	if file == nil {
+		media, _, err := download(*mediaURL)
+		if err != nil {
+			return nil, pkgError.InternalServerError(fmt.Sprintf("failed to download media from URL %v", err))
+		}
+		return media, nil
+	}
+
+	opened, err := file.Open()
+	if err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("failed to open %s %v", file.Filename, err))
+	}
+	defer opened.Close()
+
+	media, err := io.ReadAll(opened)
+	if err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("failed to read %s %v", file.Filename, err))
+	}
+	return media, nil
+}
+
+// newsletterThumbnail returns a small JPEG preview of an image, or nil when the image cannot be decoded
+func newsletterThumbnail(image []byte) []byte {
+	decoded, err := imaging.Decode(bytes.NewReader(image))
+	if err != nil {
+		logrus.Warnf("[NEWSLETTER] Failed to decode image for its thumbnail: %v", err)
+		return nil
+	}
+
+	var thumbnail bytes.Buffer
+	if err = imaging.Encode(&thumbnail, imaging.Resize(decoded, 100, 0, imaging.Lanczos), imaging.JPEG); err != nil {
+		logrus.Warnf("[NEWSLETTER] Failed to encode thumbnail: %v", err)
+		return nil
+	}
+	return thumbnail.Bytes()
+}
+
+// newsletterPicture resizes an uploaded picture the way group photos are, a nil picture returns nil
+func newsletterPicture(picture *multipart.FileHeader) ([]byte, error) {
+	if picture == nil {
+		return nil, nil
+	}
+
+	processed, err := utils.ProcessGroupPhoto(picture)
+	if err != nil {
+		logrus.Errorf("[NEWSLETTER] Failed to process picture %s: %v", picture.Filename, err)
+		return nil, err
+	}
+	return processed.Bytes(), nil
+}
+
+func toNewsletterMessages(messages []*types.NewsletterMessage) []domainNewsletter.Message {
+	result := make([]domainNewsletter.Message, 0, len(messages))
+	for _, message := range messages {
+		reactionCounts := message.ReactionCounts
+		if reactionCounts == nil {
+			reactionCounts = map[string]int{}
+		}
+
+		var timestamp string
+		if !message.Timestamp.IsZero() {
+			timestamp = message.Timestamp.Format(time.RFC3339)
+		}
+
+		result = append(result, domainNewsletter.Message{
+			ServerID:       int(message.MessageServerID),
+			MessageID:      message.MessageID,
+			Type:           message.Type,
+			Text:           utils.ExtractMessageTextFromProto(message.Message),
+			Timestamp:      timestamp,
+			ViewsCount:     message.ViewsCount,
+			ReactionCounts: reactionCounts,
+		})
+	}
+	return result
 }
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"reflect"
+	"testing"
+	"time"
+
+	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewsletterInviteCode(t *testing.T) {
+	tests := map[string]string{
+		"https://whatsapp.com/channel/0029VaABCdef":         "0029VaABCdef",
+		"whatsapp.com/channel/0029VaABCdef/":                "0029VaABCdef",
+		"https://www.whatsapp.com/channel/0029VaABCdef?x=1": "0029VaABCdef",
+		" 0029VaABCdef ": "0029VaABCdef",
+	}
+	for invite, want := range tests {
+		if got := newsletterInviteCode(invite); got != want {
+			t.Errorf("newsletterInviteCode(%q) = %q, want %q", invite, got, want)
+		}
+	}
+}
+
+func TestToNewsletterMessages(t *testing.T) {
+	at := time.Unix(1736000000, 0)
+
+	got := toNewsletterMessages([]*types.NewsletterMessage{
+		{
+			MessageServerID: 101,
+			MessageID:       "3EB0A1",
+			Type:            "text",
+			Timestamp:       at,
+			ViewsCount:      42,
+			ReactionCounts:  map[string]int{"👍": 3},
+			Message:         &waE2E.Message{Conversation: proto.String("Version 2 is out")},
+		},
+		{MessageServerID: 102, Type: "media"},
+	})
+	want := []domainNewsletter.Message{
+		{ServerID: 101, MessageID: "3EB0A1", Type: "text", Text: "Version 2 is out", Timestamp: at.Format(time.RFC3339), ViewsCount: 42, ReactionCounts: map[string]int{"👍": 3}},
+		{ServerID: 102, Type: "media", ReactionCounts: map[string]int{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("toNewsletterMessages() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPublishNewsletterMedia(t *testing.T) {
+	var picture bytes.Buffer
+	if err := png.Encode(&picture, image.NewRGBA(image.Rect(0, 0, 200, 100))); err != nil {
+		t.Fatal(err)
+	}
+	channel := types.NewJID("120363000000000000", types.NewsletterServer)
+
+	var uploadedType whatsmeow.MediaType
+	var sentTo types.JID
+	var sentMessage *waE2E.Message
+	var sentExtra []whatsmeow.SendRequestExtra
+	service := serviceNewsletter{
+		upload: func(_ context.Context, _ []byte, mediaType whatsmeow.MediaType) (whatsmeow.UploadResponse, error) {
+			uploadedType = mediaType
+			return whatsmeow.UploadResponse{URL: "https://mmg.whatsapp.net/n/1", DirectPath: "/n/1", Handle: "handle-1", FileSHA256: []byte{1}, FileLength: 42}, nil
+		},
+		send: func(_ context.Context, to types.JID, message *waE2E.Message, extra ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
+			sentTo, sentMessage, sentExtra = to, message, extra
+			return whatsmeow.SendResponse{ID: "3EB0POST"}, nil
+		},
+	}
+
+	response, err := service.publishMedia(context.Background(), channel, whatsmeow.MediaImage, picture.Bytes(), "Launch day")
+	if err != nil {
+		t.Fatalf("publishMedia() error = %v", err)
+	}
+	if response.MessageID != "3EB0POST" || sentTo != channel || uploadedType != whatsmeow.MediaImage {
+		t.Fatalf("published %q to %s as %s", response.MessageID, sentTo, uploadedType)
+	}
+	if len(sentExtra) != 1 || sentExtra[0].MediaHandle != "handle-1" {
+		t.Fatalf("the upload handle was not sent with the message, extra = %+v", sentExtra)
+	}
+
+	posted := sentMessage.GetImageMessage()
+	if posted.GetDirectPath() != "/n/1" || posted.GetCaption() != "Launch day" || posted.GetFileLength() != 42 {
+		t.Fatalf("unexpected image message %v", posted)
+	}
+	if posted.MediaKey != nil || posted.FileEncSHA256 != nil {
+		t.Fatalf("channel media must not carry encryption keys, got %v", posted)
+	}
+	if len(posted.GetJPEGThumbnail()) == 0 {
+		t.Fatal("the image was posted without a thumbnail")
+	}
+}
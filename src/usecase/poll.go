@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainPoll "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/poll"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+)
+
+type servicePoll struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+func NewPollService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainPoll.IPollUsecase {
+	return &servicePoll{
+		chatStorageRepo: chatStorageRepo,
+	}
+}
+
+func (service servicePoll) GetPollResults(ctx context.Context, request domainPoll.PollResultsRequest) (response domainPoll.PollResultsResponse, err error) {
+	if err = validations.ValidatePollResults(ctx, request); err != nil {
+		return response, err
+	}
+
+	repo := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo)
+	poll, err := repo.GetPoll(request.MessageID)
+	if err != nil {
+		return response, err
+	}
+	if poll == nil {
+		return response, pkgError.ErrPollNotFound
+	}
+
+	votes, err := repo.GetPollVotes(poll.MessageID)
+	if err != nil {
+		return response, err
+	}
+
+	return tallyPoll(poll, votes), nil
+}
+
+// tallyPoll counts the current vote of every voter. Votes for options that are not part of the poll are
+// listed after the options of the poll.
+func tallyPoll(poll *domainChatStorage.Poll, votes []*domainChatStorage.PollVote) domainPoll.PollResultsResponse {
+	response := domainPoll.PollResultsResponse{
+		MessageID:       poll.MessageID,
+		ChatJID:         poll.ChatJID,
+		CreatorJID:      poll.CreatorJID,
+		Question:        poll.Question,
+		SelectableCount: poll.SelectableCount,
+		CreatedAt:       poll.CreatedAt,
+		Options:         make([]domainPoll.OptionResult, 0, len(poll.Options)),
+		Voters:          make([]domainPoll.Voter, 0, len(votes)),
+	}
+
+	index := make(map[string]int, len(poll.Options))
+	for _, option := range poll.Options {
+		index[option] = len(response.Options)
+		response.Options = append(response.Options, domainPoll.OptionResult{Name: option, Voters: []string{}})
+	}
+
+	for _, vote := range votes {
+		if len(vote.SelectedOptions) == 0 {
+			response.Voters = append(response.Voters, domainPoll.Voter{JID: vote.VoterJID, Options: []string{}, VotedAt: vote.VotedAt})
+			continue
+		}
+		response.Voters = append(response.Voters, domainPoll.Voter{JID: vote.VoterJID, Options: vote.SelectedOptions, VotedAt: vote.VotedAt})
+
+		response.TotalVoters++
+		for _, option := range vote.SelectedOptions {
+			i, ok := index[option]
+			if !ok {
+				i = len(response.Options)
+				index[option] = i
+				response.Options = append(response.Options, domainPoll.OptionResult{Name: option, Voters: []string{}})
+			}
+			response.Options[i].Votes++
+			response.Options[i].Voters = append(response.Options[i].Voters, vote.VoterJID)
+		}
+	}
+
+	return response
+}
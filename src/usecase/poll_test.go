@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainPoll "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/poll"
+)
+
+func TestTallyPoll(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	votedAt := createdAt.Add(time.Minute)
+	poll := &domainChatStorage.Poll{
+		MessageID: "POLL1", ChatJID: "123@g.us", CreatorJID: "111@s.whatsapp.net", Question: "Lunch?",
+		Options: []string{"Pizza", "Sushi", "Salad"}, SelectableCount: 0, CreatedAt: createdAt,
+	}
+	votes := []*domainChatStorage.PollVote{
+		{PollID: "POLL1", VoterJID: "222@s.whatsapp.net", SelectedOptions: []string{"Pizza", "Sushi"}, VotedAt: votedAt},
+		{PollID: "POLL1", VoterJID: "333@s.whatsapp.net", SelectedOptions: []string{"Sushi"}, VotedAt: votedAt},
+		{PollID: "POLL1", VoterJID: "444@s.whatsapp.net", VotedAt: votedAt},
+		{PollID: "POLL1", VoterJID: "555@s.whatsapp.net", SelectedOptions: []string{"ab12"}, VotedAt: votedAt},
+	}
+
+	got := tallyPoll(poll, votes)
+	want := domainPoll.PollResultsResponse{
+		MessageID:   "POLL1",
+		ChatJID:     "123@g.us",
+		CreatorJID:  "111@s.whatsapp.net",
+		Question:    "Lunch?",
+		CreatedAt:   createdAt,
+		TotalVoters: 3,
+		Options: []domainPoll.OptionResult{
+			{Name: "Pizza", Votes: 1, Voters: []string{"222@s.whatsapp.net"}},
+			{Name: "Sushi", Votes: 2, Voters: []string{"222@s.whatsapp.net", "333@s.whatsapp.net"}},
+			{Name: "Salad", Votes: 0, Voters: []string{}},
+			{Name: "ab12", Votes: 1, Voters: []string{"555@s.whatsapp.net"}},
+		},
+		Voters: []domainPoll.Voter{
+			{JID: "222@s.whatsapp.net", Options: []string{"Pizza", "Sushi"}, VotedAt: votedAt},
+			{JID: "333@s.whatsapp.net", Options: []string{"Sushi"}, VotedAt: votedAt},
+			{JID: "444@s.whatsapp.net", Options: []string{}, VotedAt: votedAt},
+			{JID: "555@s.whatsapp.net", Options: []string{"ab12"}, VotedAt: votedAt},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("tallyPoll() = %+v, want %+v", got, want)
+	}
+}
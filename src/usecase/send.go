@@ -19,6 +19,7 @@ import (
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
@@ -46,15 +47,19 @@ func NewSendService(appService app.IAppUsecase, chatStorageRepo domainChatStorag
 
 // wrapSendMessage wraps the message sending process with message ID saving
 func (service serviceSend) wrapSendMessage(ctx context.Context, recipient types.JID, msg *waE2E.Message, content string) (whatsmeow.SendResponse, error) {
-	ts, err := whatsapp.GetClient().SendMessage(ctx, recipient, msg)
+	if err := authorizeRecipient(ctx, recipient.String()); err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+
+	ts, err := whatsapp.ClientFromContext(ctx).SendMessage(ctx, recipient, msg)
 	if err != nil {
 		return whatsmeow.SendResponse{}, err
 	}
 
 	// Store the sent message using chatstorage
 	senderJID := ""
-	if whatsapp.GetClient().Store.ID != nil {
-		senderJID = whatsapp.GetClient().Store.ID.String()
+	if whatsapp.ClientFromContext(ctx).Store.ID != nil {
+		senderJID = whatsapp.ClientFromContext(ctx).Store.ID.String()
 	}
 
 	// Store message asynchronously with timeout
@@ -63,24 +68,61 @@ func (service serviceSend) wrapSendMessage(ctx context.Context, recipient types.
 		storeCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
 
-		if err := service.chatStorageRepo.StoreSentMessageWithContext(storeCtx, ts.ID, senderJID, recipient.String(), content, ts.Timestamp); err != nil {
+		chatStorageRepo := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo)
+		if err := chatStorageRepo.StoreSentMessageWithContext(storeCtx, ts.ID, senderJID, recipient.String(), content, ts.Timestamp); err != nil {
 			if errors.Is(err, context.DeadlineExceeded) {
 				logrus.Warn("Timeout storing sent message")
 			} else {
 				logrus.Warnf("Failed to store sent message: %v", err)
 			}
 		}
+
+		// The server timestamp of the response is the server acknowledgement of the message
+		if err := chatStorageRepo.StoreMessageReceipts([]*domainChatStorage.MessageReceipt{{
+			MessageID: ts.ID,
+			ChatJID:   recipient.String(),
+			Status:    domainChatStorage.MessageStatusServerAck,
+			Timestamp: ts.Timestamp,
+		}}); err != nil {
+			logrus.Warnf("Failed to store server acknowledgement of sent message: %v", err)
+		}
 	}()
 
 	return ts, nil
 }
 
+// observeSend records a send request in the metrics. It is deferred directly so it can also record the
+// panics raised by utils.MustLogin, which are passed on to the recovery middleware afterwards.
+func observeSend(messageType string, scheduled bool, start time.Time, err *error) {
+	recovered := recover()
+
+	sendErr := *err
+	if recovered != nil {
+		sendErr = fmt.Errorf("%v", recovered)
+		if recoveredErr, ok := recovered.(error); ok {
+			sendErr = recoveredErr
+		}
+	}
+	metrics.ObserveSend(messageType, scheduled, time.Since(start), sendErr)
+
+	if recovered != nil {
+		panic(recovered)
+	}
+}
+
 func (service serviceSend) SendText(ctx context.Context, request domainSend.MessageRequest) (response domainSend.GenericResponse, err error) {
+	defer observeSend(domainSend.ScheduledTypeMessage, request.IsScheduled(), time.Now(), &err)
+
 	err = validations.ValidateSendMessage(ctx, request)
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.BaseRequest.Phone)
+
+	if request.IsScheduled() {
+		return service.schedule(ctx, domainSend.ScheduledTypeMessage, request.BaseRequest, request, nil)
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.BaseRequest.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -103,7 +145,7 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
 		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	} else {
-		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(request.BaseRequest.Phone))
+		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(ctx, request.BaseRequest.Phone))
 	}
 
 	parsedMentions := service.getMentionFromText(ctx, request.Message)
@@ -113,7 +155,7 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 
 	// Reply message
 	if request.ReplyMessageID != nil && *request.ReplyMessageID != "" {
-		message, err := service.chatStorageRepo.GetMessageByID(*request.ReplyMessageID)
+		message, err := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo).GetMessageByID(*request.ReplyMessageID)
 		if err != nil {
 			logrus.Warnf("Error retrieving reply message ID %s: %v, continuing without reply context", *request.ReplyMessageID, err)
 		} else if message != nil { // Only set reply context if we found the message
@@ -142,7 +184,7 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 			if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
 				ctxInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 			} else {
-				ctxInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(participantJID))
+				ctxInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(ctx, participantJID))
 			}
 
 			// Preserve mentions
@@ -170,11 +212,20 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 }
 
 func (service serviceSend) SendImage(ctx context.Context, request domainSend.ImageRequest) (response domainSend.GenericResponse, err error) {
+	defer observeSend(domainSend.ScheduledTypeImage, request.IsScheduled(), time.Now(), &err)
+
 	err = validations.ValidateSendImage(ctx, request)
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+
+	if request.IsScheduled() {
+		media := request.Image
+		request.Image = nil
+		return service.schedule(ctx, domainSend.ScheduledTypeImage, request.BaseRequest, request, media)
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -332,11 +383,20 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 }
 
 func (service serviceSend) SendFile(ctx context.Context, request domainSend.FileRequest) (response domainSend.GenericResponse, err error) {
+	defer observeSend(domainSend.ScheduledTypeFile, request.IsScheduled(), time.Now(), &err)
+
 	err = validations.ValidateSendFile(ctx, request)
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.BaseRequest.Phone)
+
+	if request.IsScheduled() {
+		media := request.File
+		request.File = nil
+		return service.schedule(ctx, domainSend.ScheduledTypeFile, request.BaseRequest, request, media)
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.BaseRequest.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -408,11 +468,20 @@ func resolveDocumentMIME(filename string, fileBytes []byte) string {
 }
 
 func (service serviceSend) SendVideo(ctx context.Context, request domainSend.VideoRequest) (response domainSend.GenericResponse, err error) {
+	defer observeSend(domainSend.ScheduledTypeVideo, request.IsScheduled(), time.Now(), &err)
+
 	err = validations.ValidateSendVideo(ctx, request)
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.BaseRequest.Phone)
+
+	if request.IsScheduled() {
+		media := request.Video
+		request.Video = nil
+		return service.schedule(ctx, domainSend.ScheduledTypeVideo, request.BaseRequest, request, media)
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.BaseRequest.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -583,11 +652,18 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 }
 
 func (service serviceSend) SendContact(ctx context.Context, request domainSend.ContactRequest) (response domainSend.GenericResponse, err error) {
+	defer observeSend(domainSend.ScheduledTypeContact, request.IsScheduled(), time.Now(), &err)
+
 	err = validations.ValidateSendContact(ctx, request)
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.BaseRequest.Phone)
+
+	if request.IsScheduled() {
+		return service.schedule(ctx, domainSend.ScheduledTypeContact, request.BaseRequest, request, nil)
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.BaseRequest.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -626,11 +702,18 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 }
 
 func (service serviceSend) SendLink(ctx context.Context, request domainSend.LinkRequest) (response domainSend.GenericResponse, err error) {
+	defer observeSend(domainSend.ScheduledTypeLink, request.IsScheduled(), time.Now(), &err)
+
 	err = validations.ValidateSendLink(ctx, request)
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.BaseRequest.Phone)
+
+	if request.IsScheduled() {
+		return service.schedule(ctx, domainSend.ScheduledTypeLink, request.BaseRequest, request, nil)
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.BaseRequest.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -701,11 +784,18 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 }
 
 func (service serviceSend) SendLocation(ctx context.Context, request domainSend.LocationRequest) (response domainSend.GenericResponse, err error) {
+	defer observeSend(domainSend.ScheduledTypeLocation, request.IsScheduled(), time.Now(), &err)
+
 	err = validations.ValidateSendLocation(ctx, request)
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.BaseRequest.Phone)
+
+	if request.IsScheduled() {
+		return service.schedule(ctx, domainSend.ScheduledTypeLocation, request.BaseRequest, request, nil)
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.BaseRequest.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -746,13 +836,21 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 }
 
 func (service serviceSend) SendAudio(ctx context.Context, request domainSend.AudioRequest) (response domainSend.GenericResponse, err error) {
+	defer observeSend(domainSend.ScheduledTypeAudio, request.IsScheduled(), time.Now(), &err)
+
 	// Validate request
 	err = validations.ValidateSendAudio(ctx, request)
 	if err != nil {
 		return response, err
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.BaseRequest.Phone)
+	if request.IsScheduled() {
+		media := request.Audio
+		request.Audio = nil
+		return service.schedule(ctx, domainSend.ScheduledTypeAudio, request.BaseRequest, request, media)
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.BaseRequest.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -820,18 +918,25 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 }
 
 func (service serviceSend) SendPoll(ctx context.Context, request domainSend.PollRequest) (response domainSend.GenericResponse, err error) {
+	defer observeSend(domainSend.ScheduledTypePoll, request.IsScheduled(), time.Now(), &err)
+
 	err = validations.ValidateSendPoll(ctx, request)
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.BaseRequest.Phone)
+
+	if request.IsScheduled() {
+		return service.schedule(ctx, domainSend.ScheduledTypePoll, request.BaseRequest, request, nil)
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.BaseRequest.Phone)
 	if err != nil {
 		return response, err
 	}
 
 	content := "📊 " + request.Question
 
-	msg := whatsapp.GetClient().BuildPollCreation(request.Question, request.Options, request.MaxAnswer)
+	msg := whatsapp.ClientFromContext(ctx).BuildPollCreation(request.Question, request.Options, request.MaxAnswer)
 
 	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
 		if msg.PollCreationMessage.ContextInfo == nil {
@@ -845,18 +950,28 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 		return response, err
 	}
 
+	// Keep the options and secret of the poll so the votes on it can be decrypted and tallied
+	if ownID := whatsapp.ClientFromContext(ctx).Store.ID; ownID != nil {
+		poll := whatsapp.NewPoll(ts.ID, dataWaRecipient, *ownID, msg.GetPollCreationMessage(), msg.GetMessageContextInfo().GetMessageSecret(), ts.Timestamp)
+		if err := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo).StorePoll(poll); err != nil {
+			logrus.Warnf("Failed to store sent poll %s: %v", ts.ID, err)
+		}
+	}
+
 	response.MessageID = ts.ID
 	response.Status = fmt.Sprintf("Send poll success %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
 func (service serviceSend) SendPresence(ctx context.Context, request domainSend.PresenceRequest) (response domainSend.GenericResponse, err error) {
+	defer observeSend("presence", false, time.Now(), &err)
+
 	err = validations.ValidateSendPresence(ctx, request)
 	if err != nil {
 		return response, err
 	}
 
-	err = whatsapp.GetClient().SendPresence(ctx, types.Presence(request.Type))
+	err = whatsapp.ClientFromContext(ctx).SendPresence(ctx, types.Presence(request.Type))
 	if err != nil {
 		return response, err
 	}
@@ -867,12 +982,18 @@ func (service serviceSend) SendPresence(ctx context.Context, request domainSend.
 }
 
 func (service serviceSend) SendChatPresence(ctx context.Context, request domainSend.ChatPresenceRequest) (response domainSend.GenericResponse, err error) {
+	defer observeSend(domainSend.ScheduledTypeChatPresence, request.IsScheduled(), time.Now(), &err)
+
 	err = validations.ValidateSendChatPresence(ctx, request)
 	if err != nil {
 		return response, err
 	}
 
-	userJid, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	if request.IsScheduled() {
+		return service.schedule(ctx, domainSend.ScheduledTypeChatPresence, request.BaseRequest, request, nil)
+	}
+
+	userJid, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -894,7 +1015,7 @@ func (service serviceSend) SendChatPresence(ctx context.Context, request domainS
 		return response, fmt.Errorf("invalid action: %s. Must be 'start' or 'stop'", request.Action)
 	}
 
-	err = whatsapp.GetClient().SendChatPresence(ctx, userJid, presenceType, types.ChatPresenceMedia(""))
+	err = whatsapp.ClientFromContext(ctx).SendChatPresence(ctx, userJid, presenceType, types.ChatPresenceMedia(""))
 	if err != nil {
 		return response, err
 	}
@@ -904,11 +1025,11 @@ func (service serviceSend) SendChatPresence(ctx context.Context, request domainS
 	return response, nil
 }
 
-func (service serviceSend) getMentionFromText(_ context.Context, messages string) (result []string) {
+func (service serviceSend) getMentionFromText(ctx context.Context, messages string) (result []string) {
 	mentions := utils.ContainsMention(messages)
 	for _, mention := range mentions {
 		// Get JID from phone number
-		if dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), mention); err == nil {
+		if dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), mention); err == nil {
 			result = append(result, dataWaRecipient.String())
 		}
 	}
@@ -916,13 +1037,21 @@ func (service serviceSend) getMentionFromText(_ context.Context, messages string
 }
 
 func (service serviceSend) SendSticker(ctx context.Context, request domainSend.StickerRequest) (response domainSend.GenericResponse, err error) {
+	defer observeSend(domainSend.ScheduledTypeSticker, request.IsScheduled(), time.Now(), &err)
+
 	// Validate request
 	err = validations.ValidateSendSticker(ctx, request)
 	if err != nil {
 		return response, err
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	if request.IsScheduled() {
+		media := request.Sticker
+		request.Sticker = nil
+		return service.schedule(ctx, domainSend.ScheduledTypeSticker, request.BaseRequest, request, media)
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -1100,20 +1229,20 @@ func (service serviceSend) SendSticker(ctx context.Context, request domainSend.S
 
 func (service serviceSend) uploadMedia(ctx context.Context, mediaType whatsmeow.MediaType, media []byte, recipient types.JID) (uploaded whatsmeow.UploadResponse, err error) {
 	if recipient.Server == types.NewsletterServer {
-		uploaded, err = whatsapp.GetClient().UploadNewsletter(ctx, media, mediaType)
+		uploaded, err = whatsapp.ClientFromContext(ctx).UploadNewsletter(ctx, media, mediaType)
 	} else {
-		uploaded, err = whatsapp.GetClient().Upload(ctx, media, mediaType)
+		uploaded, err = whatsapp.ClientFromContext(ctx).Upload(ctx, media, mediaType)
 	}
 	return uploaded, err
 }
 
-func (service serviceSend) getDefaultEphemeralExpiration(jid string) (expiration uint32) {
+func (service serviceSend) getDefaultEphemeralExpiration(ctx context.Context, jid string) (expiration uint32) {
 	expiration = 0
 	if jid == "" {
 		return expiration
 	}
 
-	chat, err := service.chatStorageRepo.GetChat(jid)
+	chat, err := whatsapp.ChatStorageFromContext(ctx, service.chatStorageRepo).GetChat(jid)
 	if err != nil {
 		return expiration
 	}
@@ -0,0 +1,368 @@
+package usecase
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	fiberUtils "github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// scheduledMediaContentType is the payload key holding the content type of an uploaded scheduled file
+	scheduledMediaContentType = "media_content_type"
+	// scheduledMediaFilename is the payload key holding the name the scheduled file was uploaded with
+	scheduledMediaFilename = "media_filename"
+	// storedMediaName is the name uploads are stored under, client file names never reach the file system
+	storedMediaName = "media"
+)
+
+// schedule persists a validated send request so the message scheduler sends it at the requested time.
+// Uploaded media is copied to storage because the multipart file does not outlive the HTTP request.
+func (service serviceSend) schedule(ctx context.Context, messageType string, base domainSend.BaseRequest, request any, media *multipart.FileHeader) (response domainSend.GenericResponse, err error) {
+	if err = authorizeRecipient(ctx, base.Phone); err != nil {
+		return response, err
+	}
+
+	sendAt := scheduledSendTime(base.SendAt, base.DelaySeconds)
+
+	encoded, err := json.Marshal(request)
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to encode scheduled message %v", err))
+	}
+
+	// The stored request is replayed as an immediate send once it is due
+	payload := map[string]json.RawMessage{}
+	if err = json.Unmarshal(encoded, &payload); err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to encode scheduled message %v", err))
+	}
+	delete(payload, "send_at")
+	delete(payload, "delay_seconds")
+
+	message := &domainChatStorage.ScheduledMessage{
+		ID:     fiberUtils.UUIDv4(),
+		Type:   messageType,
+		Phone:  base.Phone,
+		SendAt: sendAt,
+		Status: domainChatStorage.ScheduledMessagePending,
+	}
+	if device := whatsapp.DeviceFromContext(ctx); device != nil {
+		message.DeviceID = device.ID()
+	}
+
+	if media != nil {
+		var filename string
+		if filename, err = uploadFilename(media.Filename); err != nil {
+			return response, err
+		}
+
+		dir := filepath.Join(config.PathScheduled, message.ID)
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			return response, pkgError.InternalServerError(fmt.Sprintf("failed to store scheduled media %v", err))
+		}
+		message.MediaPath = filepath.Join(dir, storedMediaName)
+		if err = fasthttp.SaveMultipartFile(media, message.MediaPath); err != nil {
+			whatsapp.RemoveScheduledMedia(message)
+			return response, pkgError.InternalServerError(fmt.Sprintf("failed to store scheduled media %v", err))
+		}
+
+		contentType, _ := json.Marshal(media.Header.Get("Content-Type"))
+		payload[scheduledMediaContentType] = contentType
+		payload[scheduledMediaFilename], _ = json.Marshal(filename)
+	}
+
+	encoded, err = json.Marshal(payload)
+	if err != nil {
+		whatsapp.RemoveScheduledMedia(message)
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to encode scheduled message %v", err))
+	}
+	message.Payload = string(encoded)
+
+	if err = service.chatStorageRepo.StoreScheduledMessage(message); err != nil {
+		whatsapp.RemoveScheduledMedia(message)
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to store scheduled message %v", err))
+	}
+
+	if scheduler := whatsapp.GetMessageScheduler(); scheduler != nil {
+		scheduler.Wake()
+	}
+
+	response.ScheduleID = message.ID
+	response.SendAt = &sendAt
+	response.Status = fmt.Sprintf("Message scheduled for %s", sendAt.Format(time.RFC3339))
+	return response, nil
+}
+
+func (service serviceSend) ListScheduled(ctx context.Context, request domainSend.ListScheduledRequest) (response domainSend.ListScheduledResponse, err error) {
+	if err = validations.ValidateListScheduled(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := &domainChatStorage.ScheduledMessageFilter{
+		Status: request.Status,
+		Limit:  request.Limit,
+		Offset: request.Offset,
+	}
+	if device := whatsapp.DeviceFromContext(ctx); device != nil {
+		filter.DeviceID = device.ID()
+	}
+
+	messages, err := service.chatStorageRepo.GetScheduledMessages(filter)
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainSend.ScheduledMessage, 0, len(messages))
+	for _, message := range messages {
+		response.Data = append(response.Data, toScheduledMessage(message))
+	}
+	response.Limit = request.Limit
+	response.Offset = request.Offset
+	return response, nil
+}
+
+func (service serviceSend) GetScheduled(ctx context.Context, scheduleID string) (response domainSend.ScheduledMessage, err error) {
+	message, err := service.getScheduledMessage(ctx, scheduleID)
+	if err != nil {
+		return response, err
+	}
+	return toScheduledMessage(message), nil
+}
+
+func (service serviceSend) Reschedule(ctx context.Context, request domainSend.RescheduleRequest) (response domainSend.ScheduledMessage, err error) {
+	if err = validations.ValidateReschedule(ctx, request); err != nil {
+		return response, err
+	}
+
+	message, err := service.getScheduledMessage(ctx, request.ScheduleID)
+	if err != nil {
+		return response, err
+	}
+
+	sendAt := scheduledSendTime(request.SendAt, request.DelaySeconds)
+	if err = service.chatStorageRepo.RescheduleScheduledMessage(message.ID, sendAt); err != nil {
+		return response, scheduledChangeError(message, err)
+	}
+
+	if scheduler := whatsapp.GetMessageScheduler(); scheduler != nil {
+		scheduler.Wake()
+	}
+
+	return service.GetScheduled(ctx, message.ID)
+}
+
+func (service serviceSend) CancelScheduled(ctx context.Context, scheduleID string) error {
+	message, err := service.getScheduledMessage(ctx, scheduleID)
+	if err != nil {
+		return err
+	}
+
+	if err = service.chatStorageRepo.CancelScheduledMessage(message.ID); err != nil {
+		return scheduledChangeError(message, err)
+	}
+
+	whatsapp.RemoveScheduledMedia(message)
+	return nil
+}
+
+// getScheduledMessage loads a scheduled message of the device bound to ctx, the messages of other devices
+// are reported as not found
+func (service serviceSend) getScheduledMessage(ctx context.Context, scheduleID string) (*domainChatStorage.ScheduledMessage, error) {
+	message, err := service.chatStorageRepo.GetScheduledMessage(scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, pkgError.ErrScheduledMessageNotFound
+	}
+
+	if device := whatsapp.DeviceFromContext(ctx); device != nil {
+		deviceID := message.DeviceID
+		if deviceID == "" {
+			deviceID = whatsapp.DefaultDeviceID
+		}
+		if deviceID != device.ID() {
+			return nil, pkgError.ErrScheduledMessageNotFound
+		}
+	}
+	return message, nil
+}
+
+// scheduledChangeError explains why a scheduled message could not be changed
+func scheduledChangeError(message *domainChatStorage.ScheduledMessage, err error) error {
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	status := message.Status
+	if status == domainChatStorage.ScheduledMessagePending {
+		// The message left the pending state between reading and updating it
+		status = domainChatStorage.ScheduledMessageSending
+	}
+	return pkgError.ValidationError(fmt.Sprintf("scheduled message is %s and can no longer be changed", status))
+}
+
+// scheduledSendTime resolves the validated send_at / delay_seconds options to a point in time
+func scheduledSendTime(sendAt *string, delaySeconds int) time.Time {
+	if sendAt != nil && *sendAt != "" {
+		if at, err := time.Parse(time.RFC3339, *sendAt); err == nil {
+			return at.UTC()
+		}
+	}
+	return time.Now().UTC().Add(time.Duration(delaySeconds) * time.Second)
+}
+
+func toScheduledMessage(message *domainChatStorage.ScheduledMessage) domainSend.ScheduledMessage {
+	return domainSend.ScheduledMessage{
+		ID:        message.ID,
+		DeviceID:  message.DeviceID,
+		Type:      message.Type,
+		Phone:     message.Phone,
+		SendAt:    message.SendAt,
+		Status:    message.Status,
+		Attempts:  message.Attempts,
+		LastError: message.LastError,
+		MessageID: message.MessageID,
+		SentAt:    message.SentAt,
+		CreatedAt: message.CreatedAt,
+		UpdatedAt: message.UpdatedAt,
+	}
+}
+
+// NewScheduledMessageSender returns the function the message scheduler uses to send due messages
+func NewScheduledMessageSender(service domainSend.ISendUsecase) whatsapp.ScheduledMessageSender {
+	return func(ctx context.Context, message *domainChatStorage.ScheduledMessage) (messageID string, err error) {
+		defer recoverSendPanic(&err)
+
+		switch message.Type {
+		case domainSend.ScheduledTypeMessage:
+			return sendScheduled(ctx, message, nil, service.SendText)
+		case domainSend.ScheduledTypeImage:
+			return sendScheduled(ctx, message, func(r *domainSend.ImageRequest, media *multipart.FileHeader) { r.Image = media }, service.SendImage)
+		case domainSend.ScheduledTypeFile:
+			return sendScheduled(ctx, message, func(r *domainSend.FileRequest, media *multipart.FileHeader) { r.File = media }, service.SendFile)
+		case domainSend.ScheduledTypeVideo:
+			return sendScheduled(ctx, message, func(r *domainSend.VideoRequest, media *multipart.FileHeader) { r.Video = media }, service.SendVideo)
+		case domainSend.ScheduledTypeAudio:
+			return sendScheduled(ctx, message, func(r *domainSend.AudioRequest, media *multipart.FileHeader) { r.Audio = media }, service.SendAudio)
+		case domainSend.ScheduledTypeSticker:
+			return sendScheduled(ctx, message, func(r *domainSend.StickerRequest, media *multipart.FileHeader) { r.Sticker = media }, service.SendSticker)
+		case domainSend.ScheduledTypeContact:
+			return sendScheduled(ctx, message, nil, service.SendContact)
+		case domainSend.ScheduledTypeLink:
+			return sendScheduled(ctx, message, nil, service.SendLink)
+		case domainSend.ScheduledTypeLocation:
+			return sendScheduled(ctx, message, nil, service.SendLocation)
+		case domainSend.ScheduledTypePoll:
+			return sendScheduled(ctx, message, nil, service.SendPoll)
+		case domainSend.ScheduledTypeChatPresence:
+			return sendScheduled(ctx, message, nil, service.SendChatPresence)
+		default:
+			return "", pkgError.ValidationError(fmt.Sprintf("unsupported scheduled message type %s", message.Type))
+		}
+	}
+}
+
+// sendScheduled decodes the stored request, reattaches its media and sends it
+func sendScheduled[T any](
+	ctx context.Context,
+	message *domainChatStorage.ScheduledMessage,
+	attach func(request *T, media *multipart.FileHeader),
+	send func(ctx context.Context, request T) (domainSend.GenericResponse, error),
+) (string, error) {
+	var request T
+	if err := json.Unmarshal([]byte(message.Payload), &request); err != nil {
+		return "", pkgError.ValidationError(fmt.Sprintf("invalid scheduled message payload: %v", err))
+	}
+
+	if attach != nil && message.MediaPath != "" {
+		var payload map[string]json.RawMessage
+		_ = json.Unmarshal([]byte(message.Payload), &payload)
+		var contentType, filename string
+		_ = json.Unmarshal(payload[scheduledMediaContentType], &contentType)
+		_ = json.Unmarshal(payload[scheduledMediaFilename], &filename)
+
+		media, form, err := scheduledMediaFile(message.MediaPath, filename, contentType)
+		if err != nil {
+			return "", err
+		}
+		defer form.RemoveAll()
+		attach(&request, media)
+	}
+
+	response, err := send(ctx, request)
+	if err != nil {
+		return "", err
+	}
+	return response.MessageID, nil
+}
+
+// uploadFilename returns the base name of an uploaded file, rejecting names that do not name a file
+func uploadFilename(filename string) (string, error) {
+	name := filepath.Base(filename)
+	if filename == "" || name == "." || name == ".." || name == string(filepath.Separator) {
+		return "", pkgError.ValidationError(fmt.Sprintf("invalid file name %q", filename))
+	}
+	return name, nil
+}
+
+// scheduledMediaFile turns a stored file back into the multipart file the send use cases expect, named
+// filename or after the stored file when it is empty
+func scheduledMediaFile(path, filename, contentType string) (*multipart.FileHeader, *multipart.Form, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, pkgError.ValidationError("media of the scheduled message no longer exists")
+		}
+		return nil, nil, err
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+		"name":     "media",
+		"filename": cmp.Or(filename, filepath.Base(path)),
+	}))
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err = part.Write(data); err != nil {
+		return nil, nil, err
+	}
+	if err = writer.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(32 << 20)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(form.File["media"]) == 0 {
+		_ = form.RemoveAll()
+		return nil, nil, pkgError.InternalServerError("failed to restore media of the scheduled message")
+	}
+	return form.File["media"][0], form, nil
+}
@@ -39,3 +39,14 @@ func TestResolveDocumentMIME(t *testing.T) {
 		})
 	}
 }
+
+func TestUploadFilename(t *testing.T) {
+	if name, err := uploadFilename("../photos/holiday.jpg"); err != nil || name != "holiday.jpg" {
+		t.Fatalf("uploadFilename() = %q, %v, want the base name", name, err)
+	}
+	for _, filename := range []string{"", ".", "..", "/", "photos/.."} {
+		if _, err := uploadFilename(filename); err == nil {
+			t.Errorf("uploadFilename(%q) accepted a name that is not a file", filename)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	fiberUtils "github.com/gofiber/fiber/v2/utils"
+)
+
+type serviceWebhook struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+func NewWebhookService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainWebhook.IWebhookUsecase {
+	return &serviceWebhook{
+		chatStorageRepo: chatStorageRepo,
+	}
+}
+
+func (service serviceWebhook) ListWebhooks(_ context.Context) (response []domainWebhook.Webhook, err error) {
+	subscriptions, err := service.chatStorageRepo.GetWebhookSubscriptions()
+	if err != nil {
+		return response, err
+	}
+
+	response = make([]domainWebhook.Webhook, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, toWebhook(subscription))
+	}
+	return response, nil
+}
+
+func (service serviceWebhook) GetWebhook(_ context.Context, webhookID string) (response domainWebhook.Webhook, err error) {
+	subscription, err := service.chatStorageRepo.GetWebhookSubscription(webhookID)
+	if err != nil {
+		return response, err
+	}
+	if subscription == nil {
+		return response, pkgError.ErrWebhookNotFound
+	}
+
+	return toWebhook(subscription), nil
+}
+
+func (service serviceWebhook) CreateWebhook(ctx context.Context, request domainWebhook.WebhookRequest) (response domainWebhook.Webhook, err error) {
+	if err = validations.ValidateWebhook(ctx, request); err != nil {
+		return response, err
+	}
+
+	subscription := &domainChatStorage.WebhookSubscription{ID: fiberUtils.UUIDv4()}
+	applyWebhookRequest(subscription, request)
+
+	return service.saveWebhook(subscription)
+}
+
+func (service serviceWebhook) UpdateWebhook(ctx context.Context, request domainWebhook.WebhookRequest) (response domainWebhook.Webhook, err error) {
+	if err = validations.ValidateWebhook(ctx, request); err != nil {
+		return response, err
+	}
+
+	subscription, err := service.chatStorageRepo.GetWebhookSubscription(request.ID)
+	if err != nil {
+		return response, err
+	}
+	if subscription == nil {
+		return response, pkgError.ErrWebhookNotFound
+	}
+
+	// An empty secret keeps the current one so it does not have to be resent on every update
+	secret := subscription.Secret
+	applyWebhookRequest(subscription, request)
+	if request.Secret == "" {
+		subscription.Secret = secret
+	}
+
+	return service.saveWebhook(subscription)
+}
+
+func (service serviceWebhook) DeleteWebhook(_ context.Context, webhookID string) (err error) {
+	subscription, err := service.chatStorageRepo.GetWebhookSubscription(webhookID)
+	if err != nil {
+		return err
+	}
+	if subscription == nil {
+		return pkgError.ErrWebhookNotFound
+	}
+
+	if err = service.chatStorageRepo.DeleteWebhookSubscription(webhookID); err != nil {
+		return err
+	}
+	return whatsapp.ReloadWebhookSubscriptions()
+}
+
+func (service serviceWebhook) saveWebhook(subscription *domainChatStorage.WebhookSubscription) (response domainWebhook.Webhook, err error) {
+	if err = service.chatStorageRepo.StoreWebhookSubscription(subscription); err != nil {
+		return response, err
+	}
+	if err = whatsapp.ReloadWebhookSubscriptions(); err != nil {
+		return response, err
+	}
+
+	return toWebhook(subscription), nil
+}
+
+func applyWebhookRequest(subscription *domainChatStorage.WebhookSubscription, request domainWebhook.WebhookRequest) {
+	subscription.URL = request.URL
+	subscription.Secret = request.Secret
+	subscription.DeviceID = request.DeviceID
+	subscription.Events = request.Events
+	subscription.AllowJIDs = request.AllowJIDs
+	subscription.DenyJIDs = request.DenyJIDs
+	subscription.SkipFromMe = request.SkipFromMe
+	subscription.ChatType = request.ChatType
+	if subscription.ChatType == "" {
+		subscription.ChatType = domainChatStorage.WebhookChatTypeAll
+	}
+	subscription.IncludeMedia = request.IncludeMedia == nil || *request.IncludeMedia
+	subscription.Enabled = request.Enabled == nil || *request.Enabled
+}
+
+func toWebhook(subscription *domainChatStorage.WebhookSubscription) domainWebhook.Webhook {
+	return domainWebhook.Webhook{
+		ID:           subscription.ID,
+		URL:          subscription.URL,
+		HasSecret:    subscription.Secret != "",
+		DeviceID:     subscription.DeviceID,
+		Events:       nonNilStrings(subscription.Events),
+		AllowJIDs:    nonNilStrings(subscription.AllowJIDs),
+		DenyJIDs:     nonNilStrings(subscription.DenyJIDs),
+		SkipFromMe:   subscription.SkipFromMe,
+		ChatType:     subscription.ChatType,
+		IncludeMedia: subscription.IncludeMedia,
+		Enabled:      subscription.Enabled,
+		CreatedAt:    subscription.CreatedAt,
+		UpdatedAt:    subscription.UpdatedAt,
+	}
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func (service serviceWebhook) ListDeliveries(ctx context.Context, request domainWebhook.ListDeliveriesRequest) (response domainWebhook.ListDeliveriesResponse, err error) {
+	if err = validations.ValidateListWebhookDeliveries(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deliveries, err := service.chatStorageRepo.GetWebhookDeliveries(&domainChatStorage.WebhookDeliveryFilter{
+		Status: request.Status,
+		Limit:  request.Limit,
+		Offset: request.Offset,
+	})
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainWebhook.Delivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		// Payloads are only returned when inspecting a single delivery
+		response.Data = append(response.Data, toWebhookDelivery(delivery, false))
+	}
+	response.Limit = request.Limit
+	response.Offset = request.Offset
+
+	return response, nil
+}
+
+func (service serviceWebhook) GetDelivery(_ context.Context, deliveryID int64) (response domainWebhook.Delivery, err error) {
+	delivery, err := service.chatStorageRepo.GetWebhookDelivery(deliveryID)
+	if err != nil {
+		return response, err
+	}
+	if delivery == nil {
+		return response, pkgError.ErrWebhookDeliveryNotFound
+	}
+
+	return toWebhookDelivery(delivery, true), nil
+}
+
+func (service serviceWebhook) ReplayDelivery(_ context.Context, deliveryID int64) (err error) {
+	if err = service.chatStorageRepo.ReplayWebhookDelivery(deliveryID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return pkgError.ErrWebhookDeliveryNotFound
+		}
+		return err
+	}
+
+	if queue := whatsapp.GetWebhookQueue(); queue != nil {
+		queue.Wake()
+	}
+	return nil
+}
+
+func (service serviceWebhook) PurgeDeliveries(ctx context.Context, request domainWebhook.PurgeDeliveriesRequest) (response domainWebhook.PurgeDeliveriesResponse, err error) {
+	if err = validations.ValidatePurgeWebhookDeliveries(ctx, request); err != nil {
+		return response, err
+	}
+
+	deleted, err := service.chatStorageRepo.PurgeWebhookDeliveries(request.Status)
+	if err != nil {
+		return response, err
+	}
+
+	response.Status = request.Status
+	response.Deleted = deleted
+	return response, nil
+}
+
+func toWebhookDelivery(delivery *domainChatStorage.WebhookDelivery, withPayload bool) domainWebhook.Delivery {
+	result := domainWebhook.Delivery{
+		ID:            delivery.ID,
+		WebhookID:     delivery.WebhookID,
+		DeviceID:      delivery.DeviceID,
+		Event:         delivery.Event,
+		URL:           delivery.URL,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		LastError:     delivery.LastError,
+		NextAttemptAt: delivery.NextAttemptAt,
+		DeliveredAt:   delivery.DeliveredAt,
+		CreatedAt:     delivery.CreatedAt,
+		UpdatedAt:     delivery.UpdatedAt,
+	}
+	if withPayload && json.Valid([]byte(delivery.Payload)) {
+		result.Payload = json.RawMessage(delivery.Payload)
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package validations
+
+import (
+	"context"
+	"errors"
+	"path"
+	"time"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateCreateAPIKey(ctx context.Context, request domainAPIKey.CreateKeyRequest) error {
+	scopes := make([]any, 0, len(domainAPIKey.Scopes))
+	for _, scope := range domainAPIKey.Scopes {
+		scopes = append(scopes, scope)
+	}
+
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&request.Scopes, validation.Required, validation.Each(validation.Required, validation.In(scopes...))),
+		validation.Field(&request.AllowedRecipients, validation.Each(validation.Required, validation.By(validateRecipientPattern))),
+		validation.Field(&request.ExpiresAt, validation.By(func(value any) error {
+			expiresAt, _ := value.(*time.Time)
+			if expiresAt != nil && !expiresAt.After(time.Now()) {
+				return errors.New("must be in the future")
+			}
+			return nil
+		})),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateListAudit(ctx context.Context, request *domainAPIKey.ListAuditRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(500)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func validateRecipientPattern(value any) error {
+	pattern, _ := value.(string)
+	if _, err := path.Match(pattern, ""); err != nil {
+		return errors.New("must be a valid pattern")
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package validations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateAPIKey(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		request domainAPIKey.CreateKeyRequest
+		err     any
+	}{
+		{
+			name:    "should success with name and scopes",
+			request: domainAPIKey.CreateKeyRequest{Name: "crm", Scopes: []string{"send", "read:chats"}},
+			err:     nil,
+		},
+		{
+			name: "should success with recipients and expiry",
+			request: domainAPIKey.CreateKeyRequest{
+				Name:              "crm",
+				Scopes:            []string{"send"},
+				AllowedRecipients: []string{"62812*", "*@g.us"},
+				ExpiresAt:         &future,
+			},
+			err: nil,
+		},
+		{
+			name:    "should error without name",
+			request: domainAPIKey.CreateKeyRequest{Scopes: []string{"send"}},
+			err:     pkgError.ValidationError("name: cannot be blank."),
+		},
+		{
+			name:    "should error without scopes",
+			request: domainAPIKey.CreateKeyRequest{Name: "crm"},
+			err:     pkgError.ValidationError("scopes: cannot be blank."),
+		},
+		{
+			name:    "should error with unknown scope",
+			request: domainAPIKey.CreateKeyRequest{Name: "crm", Scopes: []string{"write:chats"}},
+			err:     pkgError.ValidationError("scopes: (0: must be a valid value.)."),
+		},
+		{
+			name:    "should error with invalid recipient pattern",
+			request: domainAPIKey.CreateKeyRequest{Name: "crm", Scopes: []string{"send"}, AllowedRecipients: []string{"62812["}},
+			err:     pkgError.ValidationError("allowed_recipients: (0: must be a valid pattern.)."),
+		},
+		{
+			name:    "should error with expiry in the past",
+			request: domainAPIKey.CreateKeyRequest{Name: "crm", Scopes: []string{"send"}, ExpiresAt: &past},
+			err:     pkgError.ValidationError("expires_at: must be in the future."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateAPIKey(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateListAudit(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   domainAPIKey.ListAuditRequest
+		err       any
+		wantLimit int
+	}{
+		{
+			name:      "should apply default limit",
+			request:   domainAPIKey.ListAuditRequest{},
+			err:       nil,
+			wantLimit: 50,
+		},
+		{
+			name:      "should error with limit above maximum",
+			request:   domainAPIKey.ListAuditRequest{Limit: 1000},
+			err:       pkgError.ValidationError("limit: must be no greater than 500."),
+			wantLimit: 1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListAudit(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantLimit, tt.request.Limit)
+		})
+	}
+}
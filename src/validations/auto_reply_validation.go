@@ -0,0 +1,162 @@
+package validations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+const (
+	autoReplyMaxActions         = 10
+	autoReplyMaxCooldownSeconds = 30 * 24 * 60 * 60
+)
+
+var (
+	autoReplyClockTime = regexp.MustCompile(`^([01]\d|2[0-3]):[0-5]\d$`)
+	autoReplyWeekdays  = []any{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+)
+
+func ValidateAutoReplyRule(ctx context.Context, request domainAutoReply.RuleRequest) error {
+	if err := validateAutoReplyRule(ctx, request); err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+	return nil
+}
+
+func ValidateReplaceAutoReplyRules(ctx context.Context, request domainAutoReply.ReplaceRulesRequest) error {
+	seen := make(map[string]bool, len(request.Rules))
+	for i, rule := range request.Rules {
+		if err := validateAutoReplyRule(ctx, rule); err != nil {
+			return pkgError.ValidationError(prefixValidationError(fmt.Sprintf("rules[%d].", i), err).Error())
+		}
+		if rule.ID == "" {
+			continue
+		}
+		if seen[rule.ID] {
+			return pkgError.ValidationError(fmt.Sprintf("rules[%d].id: %q is used by another rule", i, rule.ID))
+		}
+		seen[rule.ID] = true
+	}
+	return nil
+}
+
+func validateAutoReplyRule(ctx context.Context, request domainAutoReply.RuleRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Name, validation.Length(0, 100)),
+		validation.Field(&request.Position, validation.Min(0)),
+		validation.Field(&request.Actions, validation.Required, validation.Length(1, autoReplyMaxActions)),
+		validation.Field(&request.CooldownSeconds, validation.Min(0), validation.Max(autoReplyMaxCooldownSeconds)),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := validateAutoReplyConditions(ctx, request.Conditions); err != nil {
+		return prefixValidationError("conditions.", err)
+	}
+
+	for i, action := range request.Actions {
+		if err := validateAutoReplyAction(ctx, action); err != nil {
+			return prefixValidationError(fmt.Sprintf("actions[%d].", i), err)
+		}
+	}
+
+	return nil
+}
+
+func validateAutoReplyConditions(ctx context.Context, conditions domainAutoReply.Conditions) error {
+	messageTypes := make([]any, 0, len(domainAutoReply.MessageTypes))
+	for _, messageType := range domainAutoReply.MessageTypes {
+		messageTypes = append(messageTypes, messageType)
+	}
+
+	err := validation.ValidateStructWithContext(ctx, &conditions,
+		validation.Field(&conditions.MatchType,
+			validation.When(len(conditions.Patterns) > 0, validation.Required),
+			validation.In(domainAutoReply.MatchKeyword, domainAutoReply.MatchRegex, domainAutoReply.MatchExact),
+		),
+		validation.Field(&conditions.Patterns,
+			validation.When(conditions.MatchType != "", validation.Required),
+			validation.Each(validation.Required),
+		),
+		validation.Field(&conditions.Senders, validation.Each(validation.Required)),
+		validation.Field(&conditions.ChatType, validation.In(
+			domainAutoReply.ChatTypeAll,
+			domainAutoReply.ChatTypeDM,
+			domainAutoReply.ChatTypeGroup,
+		)),
+		validation.Field(&conditions.MessageTypes, validation.Each(validation.In(messageTypes...))),
+	)
+	if err != nil {
+		return err
+	}
+
+	if conditions.MatchType == domainAutoReply.MatchRegex {
+		for i, pattern := range conditions.Patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return validation.Errors{fmt.Sprintf("patterns[%d]", i): errors.New("must be a valid regular expression")}
+			}
+		}
+	}
+
+	if hours := conditions.BusinessHours; hours != nil {
+		err := validation.ValidateStructWithContext(ctx, hours,
+			validation.Field(&hours.Start, validation.Required, validation.Match(autoReplyClockTime).Error("must be a time in HH:MM format")),
+			validation.Field(&hours.End, validation.Required, validation.Match(autoReplyClockTime).Error("must be a time in HH:MM format")),
+			validation.Field(&hours.Days, validation.Each(validation.In(autoReplyWeekdays...))),
+		)
+		if err != nil {
+			return prefixValidationError("business_hours.", err)
+		}
+		if _, err := time.LoadLocation(hours.Timezone); err != nil {
+			return validation.Errors{"business_hours.timezone": errors.New("must be a valid IANA time zone")}
+		}
+	}
+
+	return nil
+}
+
+func validateAutoReplyAction(ctx context.Context, action domainAutoReply.Action) error {
+	return validation.ValidateStructWithContext(ctx, &action,
+		validation.Field(&action.Type, validation.Required, validation.In(
+			domainAutoReply.ActionReply,
+			domainAutoReply.ActionReact,
+			domainAutoReply.ActionMarkRead,
+			domainAutoReply.ActionLabel,
+			domainAutoReply.ActionWebhook,
+		)),
+		validation.Field(&action.Text,
+			validation.When(action.Type == domainAutoReply.ActionReply && action.MediaURL == "", validation.Required),
+			validation.Length(0, 4096),
+		),
+		validation.Field(&action.MediaURL, is.URL),
+		validation.Field(&action.MediaType,
+			validation.When(action.MediaURL != "", validation.Required),
+			validation.In(domainAutoReply.MediaImage, domainAutoReply.MediaVideo, domainAutoReply.MediaAudio),
+		),
+		validation.Field(&action.Emoji, validation.When(action.Type == domainAutoReply.ActionReact, validation.Required)),
+		validation.Field(&action.LabelID, validation.When(action.Type == domainAutoReply.ActionLabel, validation.Required)),
+		validation.Field(&action.URL, is.URL),
+	)
+}
+
+// prefixValidationError qualifies the field names of a nested validation error
+func prefixValidationError(prefix string, err error) error {
+	var fieldErrors validation.Errors
+	if !errors.As(err, &fieldErrors) {
+		return err
+	}
+
+	prefixed := make(validation.Errors, len(fieldErrors))
+	for field, fieldErr := range fieldErrors {
+		prefixed[prefix+field] = fieldErr
+	}
+	return prefixed
+}
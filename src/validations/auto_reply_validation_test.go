@@ -0,0 +1,141 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAutoReplyRule(t *testing.T) {
+	reply := []domainAutoReply.Action{{Type: "reply", Text: "Thanks, we will get back to you"}}
+
+	tests := []struct {
+		name    string
+		request domainAutoReply.RuleRequest
+		err     any
+	}{
+		{
+			name: "should success with keyword rule",
+			request: domainAutoReply.RuleRequest{
+				Conditions: domainAutoReply.Conditions{MatchType: "keyword", Patterns: []string{"price"}, ChatType: "dm"},
+				Actions:    reply,
+			},
+			err: nil,
+		},
+		{
+			name: "should success outside business hours",
+			request: domainAutoReply.RuleRequest{
+				Conditions: domainAutoReply.Conditions{BusinessHours: &domainAutoReply.BusinessHours{
+					Timezone: "Asia/Jakarta", Days: []string{"mon", "fri"}, Start: "09:00", End: "17:00", Outside: true,
+				}},
+				Actions:         reply,
+				CooldownSeconds: 3600,
+			},
+			err: nil,
+		},
+		{
+			name:    "should error without actions",
+			request: domainAutoReply.RuleRequest{},
+			err:     pkgError.ValidationError("actions: cannot be blank."),
+		},
+		{
+			name: "should error with patterns but no match type",
+			request: domainAutoReply.RuleRequest{
+				Conditions: domainAutoReply.Conditions{Patterns: []string{"price"}},
+				Actions:    reply,
+			},
+			err: pkgError.ValidationError("conditions.match_type: cannot be blank."),
+		},
+		{
+			name: "should error with invalid regex",
+			request: domainAutoReply.RuleRequest{
+				Conditions: domainAutoReply.Conditions{MatchType: "regex", Patterns: []string{"order #\\d+", "(unclosed"}},
+				Actions:    reply,
+			},
+			err: pkgError.ValidationError("conditions.patterns[1]: must be a valid regular expression."),
+		},
+		{
+			name: "should error with unknown message type",
+			request: domainAutoReply.RuleRequest{
+				Conditions: domainAutoReply.Conditions{MessageTypes: []string{"gif"}},
+				Actions:    reply,
+			},
+			err: pkgError.ValidationError("conditions.message_types: (0: must be a valid value.)."),
+		},
+		{
+			name: "should error with invalid business hours",
+			request: domainAutoReply.RuleRequest{
+				Conditions: domainAutoReply.Conditions{BusinessHours: &domainAutoReply.BusinessHours{Start: "9am", End: "17:00"}},
+				Actions:    reply,
+			},
+			err: pkgError.ValidationError("conditions.business_hours.start: must be a time in HH:MM format."),
+		},
+		{
+			name: "should error with unknown time zone",
+			request: domainAutoReply.RuleRequest{
+				Conditions: domainAutoReply.Conditions{BusinessHours: &domainAutoReply.BusinessHours{Timezone: "Mars/Olympus", Start: "09:00", End: "17:00"}},
+				Actions:    reply,
+			},
+			err: pkgError.ValidationError("conditions.business_hours.timezone: must be a valid IANA time zone."),
+		},
+		{
+			name:    "should error with react without emoji",
+			request: domainAutoReply.RuleRequest{Actions: []domainAutoReply.Action{{Type: "mark_read"}, {Type: "react"}}},
+			err:     pkgError.ValidationError("actions[1].emoji: cannot be blank."),
+		},
+		{
+			name:    "should error with media reply without media type",
+			request: domainAutoReply.RuleRequest{Actions: []domainAutoReply.Action{{Type: "reply", MediaURL: "https://example.com/menu.jpg"}}},
+			err:     pkgError.ValidationError("actions[0].media_type: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAutoReplyRule(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateReplaceAutoReplyRules(t *testing.T) {
+	reply := []domainAutoReply.Action{{Type: "reply", Text: "Hi"}}
+
+	tests := []struct {
+		name    string
+		request domainAutoReply.ReplaceRulesRequest
+		err     any
+	}{
+		{
+			name:    "should success with empty rule set",
+			request: domainAutoReply.ReplaceRulesRequest{},
+			err:     nil,
+		},
+		{
+			name: "should error with invalid rule",
+			request: domainAutoReply.ReplaceRulesRequest{Rules: []domainAutoReply.RuleRequest{
+				{Actions: reply},
+				{Actions: []domainAutoReply.Action{{Type: "forward"}}},
+			}},
+			err: pkgError.ValidationError("rules[1].actions[0].type: must be a valid value."),
+		},
+		{
+			name: "should error with duplicate ids",
+			request: domainAutoReply.ReplaceRulesRequest{Rules: []domainAutoReply.RuleRequest{
+				{ID: "greeting", Actions: reply},
+				{ID: "greeting", Actions: reply},
+			}},
+			err: pkgError.ValidationError(`rules[1].id: "greeting" is used by another rule`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateReplaceAutoReplyRules(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
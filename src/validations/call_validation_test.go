@@ -0,0 +1,40 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainCall "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/call"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateListCalls(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   domainCall.ListCallsRequest
+		err       any
+		wantLimit int
+	}{
+		{
+			name:      "should apply default limit",
+			request:   domainCall.ListCallsRequest{Phone: "6281234567890"},
+			err:       nil,
+			wantLimit: 50,
+		},
+		{
+			name:      "should error with negative offset",
+			request:   domainCall.ListCallsRequest{Limit: 10, Offset: -1},
+			err:       pkgError.ValidationError("offset: must be no less than 0."),
+			wantLimit: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListCalls(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantLimit, tt.request.Limit)
+		})
+	}
+}
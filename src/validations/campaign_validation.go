@@ -0,0 +1,123 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+
+	domainCampaign "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/campaign"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	// campaignMaxRecipients bounds a single campaign, larger lists can be split over several campaigns
+	campaignMaxRecipients = 10000
+	// campaignMaxRatePerMinute keeps campaigns well below the pace that gets numbers banned
+	campaignMaxRatePerMinute = 60
+	campaignMaxDelaySeconds  = 3600
+)
+
+func ValidateCreateCampaign(ctx context.Context, request *domainCampaign.CreateCampaignRequest) error {
+	// Set default rate if not provided
+	if request.RatePerMinute == 0 {
+		request.RatePerMinute = 20
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Name, validation.Length(0, 100)),
+		validation.Field(&request.Message, validation.When(request.Image == nil, validation.Required), validation.Length(0, 4096)),
+		validation.Field(&request.Recipients, validation.Required, validation.Length(1, campaignMaxRecipients)),
+		validation.Field(&request.RatePerMinute, validation.Min(1), validation.Max(campaignMaxRatePerMinute)),
+		validation.Field(&request.MinDelaySeconds, validation.Min(0), validation.Max(campaignMaxDelaySeconds)),
+		validation.Field(&request.MaxDelaySeconds, validation.Min(request.MinDelaySeconds), validation.Max(campaignMaxDelaySeconds)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if request.Image != nil {
+		availableMimes := map[string]bool{
+			"image/jpeg": true,
+			"image/jpg":  true,
+			"image/png":  true,
+		}
+
+		if !availableMimes[request.Image.Header.Get("Content-Type")] {
+			return pkgError.ValidationError("your image is not allowed. please use jpg/jpeg/png")
+		}
+	}
+
+	placeholders := utils.TemplatePlaceholders(request.Message)
+	for i, recipient := range request.Recipients {
+		if recipient.Phone == "" {
+			return pkgError.ValidationError(fmt.Sprintf("recipients[%d]: phone cannot be blank", i))
+		}
+		if err := validatePhoneNumber(recipient.Phone); err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("recipients[%d]: %s", i, err.Error()))
+		}
+
+		// Every placeholder must be filled, {{phone}} defaults to the recipient's number
+		for _, name := range placeholders {
+			if _, ok := recipient.Variables[name]; !ok && name != "phone" {
+				return pkgError.ValidationError(fmt.Sprintf("recipients[%d]: missing variable %q used in the message", i, name))
+			}
+		}
+	}
+
+	return nil
+}
+
+func ValidateListCampaigns(ctx context.Context, request *domainCampaign.ListCampaignsRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 25
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Status, validation.In(
+			domainChatStorage.CampaignRunning,
+			domainChatStorage.CampaignPaused,
+			domainChatStorage.CampaignCompleted,
+			domainChatStorage.CampaignCancelled,
+		)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateListCampaignRecipients(ctx context.Context, request *domainCampaign.ListRecipientsRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 100
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.CampaignID, validation.Required),
+		validation.Field(&request.Status, validation.In(
+			domainChatStorage.CampaignRecipientQueued,
+			domainChatStorage.CampaignRecipientSending,
+			domainChatStorage.CampaignRecipientSent,
+			domainChatStorage.CampaignRecipientDelivered,
+			domainChatStorage.CampaignRecipientRead,
+			domainChatStorage.CampaignRecipientFailed,
+			domainChatStorage.CampaignRecipientNeedsReview,
+		)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(1000)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
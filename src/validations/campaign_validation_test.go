@@ -0,0 +1,117 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainCampaign "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/campaign"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateCampaign(t *testing.T) {
+	recipients := []domainCampaign.Recipient{
+		{Phone: "6281234567890", Variables: map[string]string{"name": "Ann"}},
+		{Phone: "6289876543210", Variables: map[string]string{"name": "Bob"}},
+	}
+
+	tests := []struct {
+		name     string
+		request  domainCampaign.CreateCampaignRequest
+		err      any
+		wantRate int
+	}{
+		{
+			name:     "should success and apply default rate",
+			request:  domainCampaign.CreateCampaignRequest{Message: "Hi {{name}}, this is {{ phone }}", Recipients: recipients},
+			err:      nil,
+			wantRate: 20,
+		},
+		{
+			name:     "should success with random delays",
+			request:  domainCampaign.CreateCampaignRequest{Message: "Hi", Recipients: recipients, RatePerMinute: 10, MinDelaySeconds: 5, MaxDelaySeconds: 15},
+			err:      nil,
+			wantRate: 10,
+		},
+		{
+			name:     "should error without message",
+			request:  domainCampaign.CreateCampaignRequest{Recipients: recipients},
+			err:      pkgError.ValidationError("message: cannot be blank."),
+			wantRate: 20,
+		},
+		{
+			name:     "should error without recipients",
+			request:  domainCampaign.CreateCampaignRequest{Message: "Hi"},
+			err:      pkgError.ValidationError("recipients: cannot be blank."),
+			wantRate: 20,
+		},
+		{
+			name:     "should error with rate above maximum",
+			request:  domainCampaign.CreateCampaignRequest{Message: "Hi", Recipients: recipients, RatePerMinute: 120},
+			err:      pkgError.ValidationError("rate_per_minute: must be no greater than 60."),
+			wantRate: 120,
+		},
+		{
+			name:     "should error with max delay below min delay",
+			request:  domainCampaign.CreateCampaignRequest{Message: "Hi", Recipients: recipients, MinDelaySeconds: 10, MaxDelaySeconds: 5},
+			err:      pkgError.ValidationError("max_delay_seconds: must be no less than 10."),
+			wantRate: 20,
+		},
+		{
+			name:     "should error with local phone format",
+			request:  domainCampaign.CreateCampaignRequest{Message: "Hi", Recipients: []domainCampaign.Recipient{{Phone: "081234567890"}}},
+			err:      pkgError.ValidationError("recipients[0]: phone number must be in international format (should not start with 0). For Indonesian numbers, use 62xxx format instead of 08xxx"),
+			wantRate: 20,
+		},
+		{
+			name:     "should error with missing variable",
+			request:  domainCampaign.CreateCampaignRequest{Message: "Hi {{name}}", Recipients: []domainCampaign.Recipient{recipients[0], {Phone: "6280000000000"}}},
+			err:      pkgError.ValidationError(`recipients[1]: missing variable "name" used in the message`),
+			wantRate: 20,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateCampaign(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantRate, tt.request.RatePerMinute)
+		})
+	}
+}
+
+func TestValidateListCampaignRecipients(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   domainCampaign.ListRecipientsRequest
+		err       any
+		wantLimit int
+	}{
+		{
+			name:      "should apply default limit",
+			request:   domainCampaign.ListRecipientsRequest{CampaignID: "camp-1"},
+			err:       nil,
+			wantLimit: 100,
+		},
+		{
+			name:      "should success with read status",
+			request:   domainCampaign.ListRecipientsRequest{CampaignID: "camp-1", Status: "read", Limit: 10},
+			err:       nil,
+			wantLimit: 10,
+		},
+		{
+			name:      "should error with unknown status",
+			request:   domainCampaign.ListRecipientsRequest{CampaignID: "camp-1", Status: "pending", Limit: 10},
+			err:       pkgError.ValidationError("status: must be a valid value."),
+			wantLimit: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListCampaignRecipients(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantLimit, tt.request.Limit)
+		})
+	}
+}
@@ -2,8 +2,10 @@ package validations
 
 import (
 	"context"
+	"time"
 
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
@@ -45,6 +47,32 @@ func ValidateGetChatMessages(ctx context.Context, request *domainChat.GetChatMes
 	return nil
 }
 
+func ValidateSearchMessages(ctx context.Context, request *domainChat.SearchMessagesRequest) error {
+	// Set defaults if not provided
+	if request.Limit == 0 {
+		request.Limit = 25
+	}
+	if request.Sort == "" {
+		request.Sort = domainChatStorage.SearchSortRelevance
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Query, validation.Required, validation.Length(1, 256)),
+		validation.Field(&request.StartTime, validation.NilOrNotEmpty, validation.Date(time.RFC3339)),
+		validation.Field(&request.EndTime, validation.NilOrNotEmpty, validation.Date(time.RFC3339)),
+		validation.Field(&request.MediaType, validation.In("image", "video", "audio", "document", "sticker")),
+		validation.Field(&request.Sort, validation.In(domainChatStorage.SearchSortRelevance, domainChatStorage.SearchSortNewest)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
 func ValidatePinChat(ctx context.Context, request *domainChat.PinChatRequest) error {
 	err := validation.ValidateStructWithContext(ctx, request,
 		validation.Field(&request.ChatJID, validation.Required),
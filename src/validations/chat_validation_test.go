@@ -183,3 +183,82 @@ func TestValidatePinChat(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSearchMessages(t *testing.T) {
+	validTime := "2024-01-01T00:00:00Z"
+	invalidTime := "yesterday"
+
+	type args struct {
+		request domainChat.SearchMessagesRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with query only",
+			args: args{request: domainChat.SearchMessagesRequest{
+				Query: "invoice",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with all filters",
+			args: args{request: domainChat.SearchMessagesRequest{
+				Query:     `"monthly report" OR invoice*`,
+				ChatJID:   "120363025246125486@g.us",
+				Sender:    "6289685028129",
+				StartTime: &validTime,
+				MediaType: "document",
+				Sort:      "newest",
+				Limit:     100,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty query",
+			args: args{request: domainChat.SearchMessagesRequest{}},
+			err:  pkgError.ValidationError("q: cannot be blank."),
+		},
+		{
+			name: "should error with invalid start_time",
+			args: args{request: domainChat.SearchMessagesRequest{
+				Query:     "invoice",
+				StartTime: &invalidTime,
+			}},
+			err: pkgError.ValidationError("start_time: must be a valid date."),
+		},
+		{
+			name: "should error with unknown media type",
+			args: args{request: domainChat.SearchMessagesRequest{
+				Query:     "invoice",
+				MediaType: "gif",
+			}},
+			err: pkgError.ValidationError("media_type: must be a valid value."),
+		},
+		{
+			name: "should error with unknown sort",
+			args: args{request: domainChat.SearchMessagesRequest{
+				Query: "invoice",
+				Sort:  "oldest",
+			}},
+			err: pkgError.ValidationError("sort: must be a valid value."),
+		},
+		{
+			name: "should error with limit too high",
+			args: args{request: domainChat.SearchMessagesRequest{
+				Query: "invoice",
+				Limit: 101,
+			}},
+			err: pkgError.ValidationError("limit: must be no greater than 100."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSearchMessages(context.Background(), &tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
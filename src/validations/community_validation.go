@@ -0,0 +1,65 @@
+package validations
+
+import (
+	"context"
+
+	domainCommunity "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/community"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateCreateCommunity(ctx context.Context, request domainCommunity.CreateCommunityRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&request.Description, validation.Length(0, 2048)),
+		validation.Field(&request.Participants, validation.Each(validation.Required)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateCommunityRequest(ctx context.Context, request domainCommunity.CommunityRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.CommunityID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateLinkGroup(ctx context.Context, request domainCommunity.LinkGroupRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.CommunityID, validation.Required),
+		validation.Field(&request.GroupID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if request.CommunityID == request.GroupID {
+		return pkgError.ValidationError("group_id: a community cannot be linked to itself.")
+	}
+
+	return nil
+}
+
+func ValidateCommunityAnnouncement(ctx context.Context, request domainCommunity.AnnouncementRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.CommunityID, validation.Required),
+		validation.Field(&request.Message, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
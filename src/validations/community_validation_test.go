@@ -0,0 +1,109 @@
+package validations
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	domainCommunity "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/community"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateCommunity(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainCommunity.CreateCommunityRequest
+		err     any
+	}{
+		{
+			name:    "should success with name only",
+			request: domainCommunity.CreateCommunityRequest{Name: "Neighbours"},
+			err:     nil,
+		},
+		{
+			name:    "should success with participants",
+			request: domainCommunity.CreateCommunityRequest{Name: "Neighbours", Description: "Street updates", Participants: []string{"6281234567890"}},
+			err:     nil,
+		},
+		{
+			name:    "should error with empty name",
+			request: domainCommunity.CreateCommunityRequest{},
+			err:     pkgError.ValidationError("name: cannot be blank."),
+		},
+		{
+			name:    "should error with name too long",
+			request: domainCommunity.CreateCommunityRequest{Name: strings.Repeat("a", 101)},
+			err:     pkgError.ValidationError("name: the length must be between 1 and 100."),
+		},
+		{
+			name:    "should error with empty participant",
+			request: domainCommunity.CreateCommunityRequest{Name: "Neighbours", Participants: []string{""}},
+			err:     pkgError.ValidationError("participants: (0: cannot be blank.)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateCommunity(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateLinkGroup(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainCommunity.LinkGroupRequest
+		err     any
+	}{
+		{
+			name:    "should success with community and group",
+			request: domainCommunity.LinkGroupRequest{CommunityID: "120363000000000001@g.us", GroupID: "120363000000000002@g.us"},
+			err:     nil,
+		},
+		{
+			name:    "should error with empty group id",
+			request: domainCommunity.LinkGroupRequest{CommunityID: "120363000000000001@g.us"},
+			err:     pkgError.ValidationError("group_id: cannot be blank."),
+		},
+		{
+			name:    "should error when linking a community to itself",
+			request: domainCommunity.LinkGroupRequest{CommunityID: "120363000000000001@g.us", GroupID: "120363000000000001@g.us"},
+			err:     pkgError.ValidationError("group_id: a community cannot be linked to itself."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLinkGroup(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateCommunityAnnouncement(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainCommunity.AnnouncementRequest
+		err     any
+	}{
+		{
+			name:    "should success with message",
+			request: domainCommunity.AnnouncementRequest{CommunityID: "120363000000000001@g.us", Message: "Meeting at 7"},
+			err:     nil,
+		},
+		{
+			name:    "should error with empty message",
+			request: domainCommunity.AnnouncementRequest{CommunityID: "120363000000000001@g.us"},
+			err:     pkgError.ValidationError("message: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCommunityAnnouncement(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package validations
+
+import (
+	"context"
+	"regexp"
+
+	domainDevice "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+// deviceIDPattern keeps device ids safe to use in URL paths and storage file names
+var deviceIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+func ValidateAddDevice(ctx context.Context, request domainDevice.AddDeviceRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.DeviceID, validation.Required, validation.Length(1, 64), validation.Match(deviceIDPattern)),
+		validation.Field(&request.Webhooks, validation.Each(validation.Required, is.URL)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateUpdateDeviceWebhooks(ctx context.Context, request domainDevice.UpdateWebhooksRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.DeviceID, validation.Required),
+		validation.Field(&request.Webhooks, validation.Each(validation.Required, is.URL)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
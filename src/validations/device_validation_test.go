@@ -0,0 +1,99 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainDevice "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAddDevice(t *testing.T) {
+	type args struct {
+		request domainDevice.AddDeviceRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with device id only",
+			args: args{request: domainDevice.AddDeviceRequest{
+				DeviceID: "sales-01",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with webhooks",
+			args: args{request: domainDevice.AddDeviceRequest{
+				DeviceID: "support",
+				Webhooks: []string{"https://example.com/webhook"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty device id",
+			args: args{request: domainDevice.AddDeviceRequest{
+				DeviceID: "",
+			}},
+			err: pkgError.ValidationError("device_id: cannot be blank."),
+		},
+		{
+			name: "should error with unsafe device id",
+			args: args{request: domainDevice.AddDeviceRequest{
+				DeviceID: "../etc",
+			}},
+			err: pkgError.ValidationError("device_id: must be in a valid format."),
+		},
+		{
+			name: "should error with invalid webhook url",
+			args: args{request: domainDevice.AddDeviceRequest{
+				DeviceID: "sales",
+				Webhooks: []string{"not a url"},
+			}},
+			err: pkgError.ValidationError("webhooks: (0: must be a valid URL.)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAddDevice(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateUpdateDeviceWebhooks(t *testing.T) {
+	type args struct {
+		request domainDevice.UpdateWebhooksRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success clearing webhooks",
+			args: args{request: domainDevice.UpdateWebhooksRequest{
+				DeviceID: "sales",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty device id",
+			args: args{request: domainDevice.UpdateWebhooksRequest{
+				Webhooks: []string{"https://example.com/webhook"},
+			}},
+			err: pkgError.ValidationError("device_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpdateDeviceWebhooks(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -2,7 +2,9 @@ package validations
 
 import (
 	"context"
+	"time"
 
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -228,3 +230,59 @@ func ValidateGetGroupInviteLink(ctx context.Context, request domainGroup.GetGrou
 
 	return nil
 }
+
+func ValidateGroupHistory(ctx context.Context, request *domainGroup.GroupHistoryRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	eventTypes := make([]any, 0, len(domainChatStorage.GroupEventTypes))
+	for _, eventType := range domainChatStorage.GroupEventTypes {
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.GroupID, validation.Required),
+		validation.Field(&request.Type, validation.By(func(value any) error {
+			return validation.Validate(domainGroup.SplitGroupEventTypes(value.(string)), validation.Each(validation.In(eventTypes...)))
+		})),
+		validation.Field(&request.StartTime, validation.NilOrNotEmpty, validation.Date(time.RFC3339)),
+		validation.Field(&request.EndTime, validation.NilOrNotEmpty, validation.Date(time.RFC3339)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(500)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateReconcileParticipants(ctx context.Context, request *domainGroup.ReconcileParticipantsRequest) error {
+	// Set defaults if not provided
+	if request.BatchSize == 0 {
+		request.BatchSize = 20
+	}
+	if request.BatchDelaySeconds == nil {
+		delay := 3
+		request.BatchDelaySeconds = &delay
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.GroupID, validation.Required),
+		// An empty roster would remove everyone, at least one member or admin is required
+		validation.Field(&request.Participants, validation.When(len(request.Admins) == 0, validation.Required), validation.Each(validation.Required)),
+		validation.Field(&request.Admins, validation.Each(validation.Required)),
+		validation.Field(&request.BatchSize, validation.Min(1), validation.Max(50)),
+		validation.Field(&request.BatchDelaySeconds, validation.Min(0), validation.Max(300)),
+		validation.Field(&request.InviteMessage, validation.Length(0, 1024)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
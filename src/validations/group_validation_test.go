@@ -706,3 +706,110 @@ func TestValidateGroupInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateGroupHistory(t *testing.T) {
+	validTime := "2025-01-01T00:00:00Z"
+	invalidTime := "yesterday"
+
+	tests := []struct {
+		name      string
+		request   domainGroup.GroupHistoryRequest
+		err       any
+		wantLimit int
+	}{
+		{
+			name:      "should apply default limit",
+			request:   domainGroup.GroupHistoryRequest{GroupID: "123456789@g.us"},
+			err:       nil,
+			wantLimit: 50,
+		},
+		{
+			name:      "should success with types and time range",
+			request:   domainGroup.GroupHistoryRequest{GroupID: "123456789@g.us", Type: "join, name", StartTime: &validTime, EndTime: &validTime, Limit: 10},
+			err:       nil,
+			wantLimit: 10,
+		},
+		{
+			name:      "should error with empty group id",
+			request:   domainGroup.GroupHistoryRequest{Limit: 10},
+			err:       pkgError.ValidationError("group_id: cannot be blank."),
+			wantLimit: 10,
+		},
+		{
+			name:      "should error with unknown type",
+			request:   domainGroup.GroupHistoryRequest{GroupID: "123456789@g.us", Type: "join,kick", Limit: 10},
+			err:       pkgError.ValidationError("type: (1: must be a valid value.)."),
+			wantLimit: 10,
+		},
+		{
+			name:      "should error with invalid start_time",
+			request:   domainGroup.GroupHistoryRequest{GroupID: "123456789@g.us", StartTime: &invalidTime, Limit: 10},
+			err:       pkgError.ValidationError("start_time: must be a valid date."),
+			wantLimit: 10,
+		},
+		{
+			name:      "should error with limit above maximum",
+			request:   domainGroup.GroupHistoryRequest{GroupID: "123456789@g.us", Limit: 501},
+			err:       pkgError.ValidationError("limit: must be no greater than 500."),
+			wantLimit: 501,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGroupHistory(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantLimit, tt.request.Limit)
+		})
+	}
+}
+
+func TestValidateReconcileParticipants(t *testing.T) {
+	delay := 500
+
+	tests := []struct {
+		name          string
+		request       domainGroup.ReconcileParticipantsRequest
+		err           any
+		wantBatchSize int
+	}{
+		{
+			name:          "should apply defaults",
+			request:       domainGroup.ReconcileParticipantsRequest{GroupID: "123456789@g.us", Participants: []string{"6281234567890"}},
+			err:           nil,
+			wantBatchSize: 20,
+		},
+		{
+			name:          "should success with admins only",
+			request:       domainGroup.ReconcileParticipantsRequest{GroupID: "123456789@g.us", Admins: []string{"6281234567890"}, BatchSize: 5},
+			err:           nil,
+			wantBatchSize: 5,
+		},
+		{
+			name:          "should error with empty roster",
+			request:       domainGroup.ReconcileParticipantsRequest{GroupID: "123456789@g.us", BatchSize: 5},
+			err:           pkgError.ValidationError("participants: cannot be blank."),
+			wantBatchSize: 5,
+		},
+		{
+			name:          "should error with empty group id",
+			request:       domainGroup.ReconcileParticipantsRequest{Participants: []string{"6281234567890"}, BatchSize: 5},
+			err:           pkgError.ValidationError("group_id: cannot be blank."),
+			wantBatchSize: 5,
+		},
+		{
+			name:          "should error with batch delay above maximum",
+			request:       domainGroup.ReconcileParticipantsRequest{GroupID: "123456789@g.us", Participants: []string{"6281234567890"}, BatchDelaySeconds: &delay},
+			err:           pkgError.ValidationError("batch_delay_seconds: must be no greater than 300."),
+			wantBatchSize: 20,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateReconcileParticipants(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantBatchSize, tt.request.BatchSize)
+		})
+	}
+}
@@ -291,3 +291,36 @@ func TestValidateStarMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateMessageStatus(t *testing.T) {
+	type args struct {
+		request domainMessage.MessageStatusRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with message id",
+			args: args{request: domainMessage.MessageStatusRequest{MessageID: "3EB0C127D7BACC83D6A1"}},
+			err:  nil,
+		},
+		{
+			name: "should error with empty message id",
+			args: args{request: domainMessage.MessageStatusRequest{MessageID: ""}},
+			err:  pkgError.ValidationError("message_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMessageStatus(context.Background(), tt.args.request)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.err, err)
+			}
+		})
+	}
+}
@@ -2,11 +2,69 @@ package validations
 
 import (
 	"context"
+
 	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
+func ValidateCreateNewsletter(ctx context.Context, request domainNewsletter.CreateRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&request.Description, validation.Length(0, 2048)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if request.Picture != nil {
+		contentType := request.Picture.Header.Get("Content-Type")
+		if contentType != "" && !isImageContentType(contentType) {
+			return pkgError.ValidationError("uploaded picture must be an image")
+		}
+	}
+
+	return nil
+}
+
+func ValidateUpdateNewsletter(ctx context.Context, request domainNewsletter.UpdateRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.NewsletterID, validation.Required),
+		validation.Field(&request.Name, validation.Length(0, 100)),
+		validation.Field(&request.Description, validation.Length(0, 2048)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if request.Name == "" && request.Description == "" && request.Picture == nil {
+		return pkgError.ValidationError("at least one of name, description or picture must be provided")
+	}
+
+	if request.Picture != nil {
+		contentType := request.Picture.Header.Get("Content-Type")
+		if contentType != "" && !isImageContentType(contentType) {
+			return pkgError.ValidationError("uploaded picture must be an image")
+		}
+	}
+
+	return nil
+}
+
+func ValidateFollowNewsletter(ctx context.Context, request domainNewsletter.FollowRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Invite, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
 func ValidateUnfollowNewsletter(ctx context.Context, request domainNewsletter.UnfollowRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.NewsletterID, validation.Required),
@@ -18,3 +76,68 @@ func ValidateUnfollowNewsletter(ctx context.Context, request domainNewsletter.Un
 
 	return nil
 }
+
+func ValidateNewsletterInfo(ctx context.Context, request domainNewsletter.InfoRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.NewsletterID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateNewsletterMessages(ctx context.Context, request *domainNewsletter.MessagesRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.NewsletterID, validation.Required),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Before, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidatePublishNewsletter(ctx context.Context, request domainNewsletter.PublishRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.NewsletterID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	hasImage := request.Image != nil || (request.ImageURL != nil && *request.ImageURL != "")
+	hasVideo := request.Video != nil || (request.VideoURL != nil && *request.VideoURL != "")
+	if hasImage && hasVideo {
+		return pkgError.ValidationError("a post can have either an image or a video, not both")
+	}
+	if !hasImage && !hasVideo && request.Message == "" {
+		return pkgError.ValidationError("message: cannot be blank.")
+	}
+
+	return nil
+}
+
+func ValidateMarkNewsletterViewed(ctx context.Context, request domainNewsletter.MarkViewedRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.NewsletterID, validation.Required),
+		validation.Field(&request.ServerIDs, validation.Required, validation.Each(validation.Required, validation.Min(1))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
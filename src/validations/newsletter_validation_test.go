@@ -2,6 +2,8 @@ package validations
 
 import (
 	"context"
+	"mime/multipart"
+	"net/textproto"
 	"testing"
 
 	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
@@ -48,3 +50,190 @@ func TestValidateUnfollowNewsletter(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCreateNewsletter(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainNewsletter.CreateRequest
+		err     any
+	}{
+		{
+			name:    "should success with name and description",
+			request: domainNewsletter.CreateRequest{Name: "Product news", Description: "Release announcements"},
+			err:     nil,
+		},
+		{
+			name:    "should error with empty name",
+			request: domainNewsletter.CreateRequest{Description: "Release announcements"},
+			err:     pkgError.ValidationError("name: cannot be blank."),
+		},
+		{
+			name: "should error with a picture that is not an image",
+			request: domainNewsletter.CreateRequest{Name: "Product news", Picture: &multipart.FileHeader{
+				Filename: "notes.txt",
+				Header:   textproto.MIMEHeader{"Content-Type": []string{"text/plain"}},
+			}},
+			err: pkgError.ValidationError("uploaded picture must be an image"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateNewsletter(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateUpdateNewsletter(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainNewsletter.UpdateRequest
+		err     any
+	}{
+		{
+			name:    "should success with a new name",
+			request: domainNewsletter.UpdateRequest{NewsletterID: "120363123456789@newsletter", Name: "Product updates"},
+			err:     nil,
+		},
+		{
+			name:    "should error with empty newsletter id",
+			request: domainNewsletter.UpdateRequest{Name: "Product updates"},
+			err:     pkgError.ValidationError("newsletter_id: cannot be blank."),
+		},
+		{
+			name:    "should error without any change",
+			request: domainNewsletter.UpdateRequest{NewsletterID: "120363123456789@newsletter"},
+			err:     pkgError.ValidationError("at least one of name, description or picture must be provided"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpdateNewsletter(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateFollowNewsletter(t *testing.T) {
+	assert.Nil(t, ValidateFollowNewsletter(context.Background(), domainNewsletter.FollowRequest{Invite: "https://whatsapp.com/channel/0029VaABC"}))
+	assert.Equal(t, pkgError.ValidationError("invite: cannot be blank."), ValidateFollowNewsletter(context.Background(), domainNewsletter.FollowRequest{}))
+}
+
+func TestValidateNewsletterMessages(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   domainNewsletter.MessagesRequest
+		err       any
+		wantLimit int
+	}{
+		{
+			name:      "should set default limit",
+			request:   domainNewsletter.MessagesRequest{NewsletterID: "120363123456789@newsletter"},
+			err:       nil,
+			wantLimit: 50,
+		},
+		{
+			name:      "should error with limit above maximum",
+			request:   domainNewsletter.MessagesRequest{NewsletterID: "120363123456789@newsletter", Limit: 101},
+			err:       pkgError.ValidationError("limit: must be no greater than 100."),
+			wantLimit: 101,
+		},
+		{
+			name:      "should error with negative before",
+			request:   domainNewsletter.MessagesRequest{NewsletterID: "120363123456789@newsletter", Before: -1},
+			err:       pkgError.ValidationError("before: must be no less than 0."),
+			wantLimit: 50,
+		},
+		{
+			name:      "should error with empty newsletter id",
+			request:   domainNewsletter.MessagesRequest{},
+			err:       pkgError.ValidationError("newsletter_id: cannot be blank."),
+			wantLimit: 50,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNewsletterMessages(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantLimit, tt.request.Limit)
+		})
+	}
+}
+
+func TestValidatePublishNewsletter(t *testing.T) {
+	imageURL := "https://example.com/launch.png"
+	videoURL := "https://example.com/launch.mp4"
+
+	tests := []struct {
+		name    string
+		request domainNewsletter.PublishRequest
+		err     any
+	}{
+		{
+			name:    "should success with text",
+			request: domainNewsletter.PublishRequest{NewsletterID: "120363123456789@newsletter", Message: "Version 2 is out"},
+			err:     nil,
+		},
+		{
+			name:    "should success with an image without caption",
+			request: domainNewsletter.PublishRequest{NewsletterID: "120363123456789@newsletter", ImageURL: &imageURL},
+			err:     nil,
+		},
+		{
+			name:    "should error without message or media",
+			request: domainNewsletter.PublishRequest{NewsletterID: "120363123456789@newsletter"},
+			err:     pkgError.ValidationError("message: cannot be blank."),
+		},
+		{
+			name:    "should error with both image and video",
+			request: domainNewsletter.PublishRequest{NewsletterID: "120363123456789@newsletter", ImageURL: &imageURL, VideoURL: &videoURL},
+			err:     pkgError.ValidationError("a post can have either an image or a video, not both"),
+		},
+		{
+			name:    "should error with empty newsletter id",
+			request: domainNewsletter.PublishRequest{Message: "Version 2 is out"},
+			err:     pkgError.ValidationError("newsletter_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePublishNewsletter(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateMarkNewsletterViewed(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainNewsletter.MarkViewedRequest
+		err     any
+	}{
+		{
+			name:    "should success with server ids",
+			request: domainNewsletter.MarkViewedRequest{NewsletterID: "120363123456789@newsletter", ServerIDs: []int{101, 102}},
+			err:     nil,
+		},
+		{
+			name:    "should error without server ids",
+			request: domainNewsletter.MarkViewedRequest{NewsletterID: "120363123456789@newsletter"},
+			err:     pkgError.ValidationError("server_ids: cannot be blank."),
+		},
+		{
+			name:    "should error with invalid server id",
+			request: domainNewsletter.MarkViewedRequest{NewsletterID: "120363123456789@newsletter", ServerIDs: []int{101, -1}},
+			err:     pkgError.ValidationError("server_ids: (1: must be no less than 1.)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMarkNewsletterViewed(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package validations
+
+import (
+	"context"
+
+	domainPoll "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/poll"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidatePollResults(ctx context.Context, request domainPoll.PollResultsRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.MessageID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainPoll "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/poll"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePollResults(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainPoll.PollResultsRequest
+		err     any
+	}{
+		{
+			name:    "should success with message id",
+			request: domainPoll.PollResultsRequest{MessageID: "3EB0A1B2C3D4E5F60718"},
+			err:     nil,
+		},
+		{
+			name:    "should error with empty message id",
+			request: domainPoll.PollResultsRequest{},
+			err:     pkgError.ValidationError("message_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePollResults(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// maxScheduleAhead is how far in the future a message can be scheduled
+const maxScheduleAhead = 365 * 24 * time.Hour
+
+// validateSchedule validates the optional send_at / delay_seconds options shared by the send requests
+func validateSchedule(request domainSend.BaseRequest) error {
+	return ValidateScheduleTime(request.SendAt, request.DelaySeconds)
+}
+
+// ValidateScheduleTime checks that at most one of sendAt (RFC3339) and delaySeconds is set
+// and that the resulting send time lies within the allowed scheduling window
+func ValidateScheduleTime(sendAt *string, delaySeconds int) error {
+	hasSendAt := sendAt != nil && *sendAt != ""
+
+	if hasSendAt && delaySeconds != 0 {
+		return pkgError.ValidationError("send_at and delay_seconds cannot be used together")
+	}
+
+	if delaySeconds < 0 || time.Duration(delaySeconds)*time.Second > maxScheduleAhead {
+		return pkgError.ValidationError(fmt.Sprintf("delay_seconds must be between 0 and %d", int(maxScheduleAhead.Seconds())))
+	}
+
+	if hasSendAt {
+		at, err := time.Parse(time.RFC3339, *sendAt)
+		if err != nil {
+			return pkgError.ValidationError("send_at must be an RFC3339 timestamp, e.g. 2025-01-31T09:00:00+07:00")
+		}
+		if !at.After(time.Now()) {
+			return pkgError.ValidationError("send_at must be in the future")
+		}
+		if at.After(time.Now().Add(maxScheduleAhead)) {
+			return pkgError.ValidationError("send_at cannot be more than 365 days ahead")
+		}
+	}
+
+	return nil
+}
+
+func ValidateListScheduled(ctx context.Context, request *domainSend.ListScheduledRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 25
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Status, validation.In(
+			domainChatStorage.ScheduledMessagePending,
+			domainChatStorage.ScheduledMessageSending,
+			domainChatStorage.ScheduledMessageSent,
+			domainChatStorage.ScheduledMessageFailed,
+			domainChatStorage.ScheduledMessageCancelled,
+		)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateReschedule(ctx context.Context, request domainSend.RescheduleRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.ScheduleID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if (request.SendAt == nil || *request.SendAt == "") && request.DelaySeconds == 0 {
+		return pkgError.ValidationError("either send_at or delay_seconds must be provided")
+	}
+
+	return ValidateScheduleTime(request.SendAt, request.DelaySeconds)
+}
@@ -0,0 +1,147 @@
+package validations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSendMessage_WithSchedule(t *testing.T) {
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	tooFar := time.Now().Add(400 * 24 * time.Hour).Format(time.RFC3339)
+	invalid := "tomorrow"
+
+	tests := []struct {
+		name string
+		base domainSend.BaseRequest
+		err  any
+	}{
+		{
+			name: "should success with send_at in the future",
+			base: domainSend.BaseRequest{Phone: "6281234567890", SendAt: &future},
+			err:  nil,
+		},
+		{
+			name: "should success with delay_seconds",
+			base: domainSend.BaseRequest{Phone: "6281234567890", DelaySeconds: 600},
+			err:  nil,
+		},
+		{
+			name: "should error with both send_at and delay_seconds",
+			base: domainSend.BaseRequest{Phone: "6281234567890", SendAt: &future, DelaySeconds: 600},
+			err:  pkgError.ValidationError("send_at and delay_seconds cannot be used together"),
+		},
+		{
+			name: "should error with send_at in the past",
+			base: domainSend.BaseRequest{Phone: "6281234567890", SendAt: &past},
+			err:  pkgError.ValidationError("send_at must be in the future"),
+		},
+		{
+			name: "should error with send_at too far ahead",
+			base: domainSend.BaseRequest{Phone: "6281234567890", SendAt: &tooFar},
+			err:  pkgError.ValidationError("send_at cannot be more than 365 days ahead"),
+		},
+		{
+			name: "should error with invalid send_at",
+			base: domainSend.BaseRequest{Phone: "6281234567890", SendAt: &invalid},
+			err:  pkgError.ValidationError("send_at must be an RFC3339 timestamp, e.g. 2025-01-31T09:00:00+07:00"),
+		},
+		{
+			name: "should error with negative delay_seconds",
+			base: domainSend.BaseRequest{Phone: "6281234567890", DelaySeconds: -1},
+			err:  pkgError.ValidationError("delay_seconds must be between 0 and 31536000"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendMessage(context.Background(), domainSend.MessageRequest{BaseRequest: tt.base, Message: "Hello"})
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateListScheduled(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   domainSend.ListScheduledRequest
+		wantLimit int
+		err       any
+	}{
+		{
+			name:      "should default limit",
+			request:   domainSend.ListScheduledRequest{},
+			wantLimit: 25,
+			err:       nil,
+		},
+		{
+			name:      "should success with status filter",
+			request:   domainSend.ListScheduledRequest{Status: "pending", Limit: 10},
+			wantLimit: 10,
+			err:       nil,
+		},
+		{
+			name:      "should error with unknown status",
+			request:   domainSend.ListScheduledRequest{Status: "queued"},
+			wantLimit: 25,
+			err:       pkgError.ValidationError("status: must be a valid value."),
+		},
+		{
+			name:      "should error with limit above max",
+			request:   domainSend.ListScheduledRequest{Limit: 500},
+			wantLimit: 500,
+			err:       pkgError.ValidationError("limit: must be no greater than 100."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListScheduled(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantLimit, tt.request.Limit)
+		})
+	}
+}
+
+func TestValidateReschedule(t *testing.T) {
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name    string
+		request domainSend.RescheduleRequest
+		err     any
+	}{
+		{
+			name:    "should success with send_at",
+			request: domainSend.RescheduleRequest{ScheduleID: "abc", SendAt: &future},
+			err:     nil,
+		},
+		{
+			name:    "should success with delay_seconds",
+			request: domainSend.RescheduleRequest{ScheduleID: "abc", DelaySeconds: 30},
+			err:     nil,
+		},
+		{
+			name:    "should error without schedule id",
+			request: domainSend.RescheduleRequest{DelaySeconds: 30},
+			err:     pkgError.ValidationError("schedule_id: cannot be blank."),
+		},
+		{
+			name:    "should error without a new time",
+			request: domainSend.RescheduleRequest{ScheduleID: "abc"},
+			err:     pkgError.ValidationError("either send_at or delay_seconds must be provided"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateReschedule(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -68,6 +68,10 @@ func ValidateSendMessage(ctx context.Context, request domainSend.MessageRequest)
 	if err := validateDuration(request.Duration); err != nil {
 		return err
 	}
+
+	if err := validateSchedule(request.BaseRequest); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -117,6 +121,10 @@ func ValidateSendImage(ctx context.Context, request domainSend.ImageRequest) err
 		return err
 	}
 
+	if err := validateSchedule(request.BaseRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -172,6 +180,10 @@ func ValidateSendSticker(ctx context.Context, request domainSend.StickerRequest)
 		return err
 	}
 
+	if err := validateSchedule(request.BaseRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -199,6 +211,10 @@ func ValidateSendFile(ctx context.Context, request domainSend.FileRequest) error
 		return err
 	}
 
+	if err := validateSchedule(request.BaseRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -256,6 +272,10 @@ func ValidateSendVideo(ctx context.Context, request domainSend.VideoRequest) err
 		return err
 	}
 
+	if err := validateSchedule(request.BaseRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -284,6 +304,10 @@ func ValidateSendContact(ctx context.Context, request domainSend.ContactRequest)
 		return err
 	}
 
+	if err := validateSchedule(request.BaseRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -307,6 +331,10 @@ func ValidateSendLink(ctx context.Context, request domainSend.LinkRequest) error
 		return err
 	}
 
+	if err := validateSchedule(request.BaseRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -330,6 +358,10 @@ func ValidateSendLocation(ctx context.Context, request domainSend.LocationReques
 		return err
 	}
 
+	if err := validateSchedule(request.BaseRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -405,6 +437,10 @@ func ValidateSendAudio(ctx context.Context, request domainSend.AudioRequest) err
 		return err
 	}
 
+	if err := validateSchedule(request.BaseRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -438,6 +474,10 @@ func ValidateSendPoll(ctx context.Context, request domainSend.PollRequest) error
 		return err
 	}
 
+	if err := validateSchedule(request.BaseRequest); err != nil {
+		return err
+	}
+
 	// validate options should be unique each other
 	uniqueOptions := make(map[string]bool)
 	for _, option := range request.Options {
@@ -477,5 +517,9 @@ func ValidateSendChatPresence(ctx context.Context, request domainSend.ChatPresen
 		return err
 	}
 
+	if err := validateSchedule(request.BaseRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -110,3 +110,36 @@ func TestValidateBusinessProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePresenceSubscription(t *testing.T) {
+	type args struct {
+		request domainUser.PresenceRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with valid phone",
+			args: args{request: domainUser.PresenceRequest{
+				Phone: "6289685028129@s.whatsapp.net",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty phone",
+			args: args{request: domainUser.PresenceRequest{
+				Phone: "",
+			}},
+			err: pkgError.ValidationError("phone: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePresenceSubscription(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
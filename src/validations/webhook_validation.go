@@ -0,0 +1,75 @@
+package validations
+
+import (
+	"context"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+func ValidateWebhook(ctx context.Context, request domainWebhook.WebhookRequest) error {
+	eventTypes := make([]any, 0, len(domainWebhook.EventTypes))
+	for _, eventType := range domainWebhook.EventTypes {
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.URL, validation.Required, is.URL),
+		validation.Field(&request.Events, validation.Each(validation.Required, validation.In(eventTypes...))),
+		validation.Field(&request.AllowJIDs, validation.Each(validation.Required)),
+		validation.Field(&request.DenyJIDs, validation.Each(validation.Required)),
+		validation.Field(&request.ChatType, validation.In(
+			domainChatStorage.WebhookChatTypeAll,
+			domainChatStorage.WebhookChatTypeGroup,
+			domainChatStorage.WebhookChatTypeDM,
+		)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateListWebhookDeliveries(ctx context.Context, request *domainWebhook.ListDeliveriesRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 25
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Status, validation.In(
+			domainChatStorage.WebhookDeliveryPending,
+			domainChatStorage.WebhookDeliveryDelivering,
+			domainChatStorage.WebhookDeliveryDelivered,
+			domainChatStorage.WebhookDeliveryDead,
+		)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidatePurgeWebhookDeliveries(ctx context.Context, request domainWebhook.PurgeDeliveriesRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Status, validation.Required, validation.In(
+			domainChatStorage.WebhookDeliveryDelivered,
+			domainChatStorage.WebhookDeliveryDead,
+		)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
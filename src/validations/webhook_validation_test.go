@@ -0,0 +1,134 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateListWebhookDeliveries(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   domainWebhook.ListDeliveriesRequest
+		err       any
+		wantLimit int
+	}{
+		{
+			name:      "should apply default limit",
+			request:   domainWebhook.ListDeliveriesRequest{},
+			err:       nil,
+			wantLimit: 25,
+		},
+		{
+			name:      "should success with dead status",
+			request:   domainWebhook.ListDeliveriesRequest{Status: "dead", Limit: 10},
+			err:       nil,
+			wantLimit: 10,
+		},
+		{
+			name:      "should error with unknown status",
+			request:   domainWebhook.ListDeliveriesRequest{Status: "failed", Limit: 10},
+			err:       pkgError.ValidationError("status: must be a valid value."),
+			wantLimit: 10,
+		},
+		{
+			name:      "should error with limit above maximum",
+			request:   domainWebhook.ListDeliveriesRequest{Limit: 500},
+			err:       pkgError.ValidationError("limit: must be no greater than 100."),
+			wantLimit: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListWebhookDeliveries(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantLimit, tt.request.Limit)
+		})
+	}
+}
+
+func TestValidatePurgeWebhookDeliveries(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainWebhook.PurgeDeliveriesRequest
+		err     any
+	}{
+		{
+			name:    "should success purging dead deliveries",
+			request: domainWebhook.PurgeDeliveriesRequest{Status: "dead"},
+			err:     nil,
+		},
+		{
+			name:    "should error without status",
+			request: domainWebhook.PurgeDeliveriesRequest{},
+			err:     pkgError.ValidationError("status: cannot be blank."),
+		},
+		{
+			name:    "should error purging pending deliveries",
+			request: domainWebhook.PurgeDeliveriesRequest{Status: "pending"},
+			err:     pkgError.ValidationError("status: must be a valid value."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePurgeWebhookDeliveries(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainWebhook.WebhookRequest
+		err     any
+	}{
+		{
+			name:    "should success with url only",
+			request: domainWebhook.WebhookRequest{URL: "https://example.com/hook"},
+			err:     nil,
+		},
+		{
+			name: "should success with filters",
+			request: domainWebhook.WebhookRequest{
+				URL:       "https://example.com/hook",
+				Events:    []string{domainWebhook.EventMessage, domainWebhook.EventMessageAck},
+				AllowJIDs: []string{"628123456789"},
+				ChatType:  "group",
+			},
+			err: nil,
+		},
+		{
+			name:    "should error without url",
+			request: domainWebhook.WebhookRequest{},
+			err:     pkgError.ValidationError("url: cannot be blank."),
+		},
+		{
+			name:    "should error with invalid url",
+			request: domainWebhook.WebhookRequest{URL: "not a url"},
+			err:     pkgError.ValidationError("url: must be a valid URL."),
+		},
+		{
+			name:    "should error with unknown event",
+			request: domainWebhook.WebhookRequest{URL: "https://example.com/hook", Events: []string{"presence"}},
+			err:     pkgError.ValidationError("events: (0: must be a valid value.)."),
+		},
+		{
+			name:    "should error with unknown chat type",
+			request: domainWebhook.WebhookRequest{URL: "https://example.com/hook", ChatType: "channel"},
+			err:     pkgError.ValidationError("chat_type: must be a valid value."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWebhook(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
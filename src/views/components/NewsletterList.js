@@ -2,15 +2,31 @@ export default {
     name: 'ListNewsletter',
     data() {
         return {
-            newsletters: []
+            newsletters: [],
+            view: 'list',
+            loading: false,
+            selected: null,
+            info: null,
+            messages: [],
+            form_name: '',
+            form_description: '',
+            form_invite: '',
+            form_message: '',
+            form_media_type: 'text',
+            form_media_url: '',
         }
     },
     methods: {
         async openModal() {
             try {
+                this.view = 'list'
                 this.dtClear()
                 await this.submitApi();
-                $('#modalNewsletterList').modal('show');
+                $('#modalNewsletterList').modal({
+                    onApprove: function () {
+                        return false;
+                    }
+                }).modal('show');
                 this.dtRebuild()
                 showSuccessInfo("Newsletters fetched")
             } catch (err) {
@@ -26,15 +42,48 @@ export default {
                 "reloadData": true,
             }).draw();
         },
+        async refreshList() {
+            this.dtClear()
+            await this.submitApi();
+            this.dtRebuild()
+        },
+        handleReset() {
+            this.info = null;
+            this.messages = [];
+            this.form_name = '';
+            this.form_description = '';
+            this.form_invite = '';
+            this.form_message = '';
+            this.form_media_type = 'text';
+            this.form_media_url = '';
+            $("#newsletter_picture").val('');
+            $("#newsletter_media").val('');
+        },
+        showList() {
+            this.handleReset();
+            this.selected = null;
+            this.view = 'list';
+        },
+        showForm(view, newsletter = null) {
+            this.handleReset();
+            this.selected = newsletter;
+            if (view === 'edit' && newsletter) {
+                this.form_name = newsletter.thread_metadata?.name?.text || '';
+                this.form_description = newsletter.thread_metadata?.description?.text || '';
+            }
+            this.view = view;
+        },
+        isAdmin(newsletter) {
+            const role = newsletter?.viewer_metadata?.role;
+            return role === 'owner' || role === 'admin';
+        },
         async handleUnfollowNewsletter(newsletter_id) {
             try {
                 const ok = confirm("Are you sure to leave this newsletter?");
                 if (!ok) return;
 
                 await this.unfollowNewsletterApi(newsletter_id);
-                this.dtClear()
-                await this.submitApi();
-                this.dtRebuild()
+                await this.refreshList();
                 showSuccessInfo("Success unfollow newsletter")
             } catch (err) {
                 showErrorInfo(err)
@@ -54,6 +103,110 @@ export default {
 
             }
         },
+        async handleSave() {
+            if (this.loading) return;
+            this.loading = true;
+            try {
+                let payload = new FormData();
+                payload.append("name", this.form_name)
+                payload.append("description", this.form_description)
+                if (this.view === 'edit') {
+                    payload.append("newsletter_id", this.selected.id)
+                }
+                const fileInput = $("#newsletter_picture");
+                if (fileInput.length > 0 && fileInput[0].files.length > 0) {
+                    payload.append('picture', fileInput[0].files[0]);
+                }
+
+                const url = this.view === 'edit' ? `/newsletter/update` : `/newsletter`;
+                let response = await window.http.post(url, payload)
+                this.showList();
+                await this.refreshList();
+                showSuccessInfo(response.data.message)
+            } catch (error) {
+                showErrorInfo(error.response ? error.response.data.message : error.message)
+            } finally {
+                this.loading = false;
+            }
+        },
+        async handleFollow() {
+            if (this.loading || !this.form_invite.trim()) return;
+            this.loading = true;
+            try {
+                let response = await window.http.post(`/newsletter/follow`, {invite: this.form_invite.trim()})
+                this.showList();
+                await this.refreshList();
+                showSuccessInfo(response.data.message)
+            } catch (error) {
+                showErrorInfo(error.response ? error.response.data.message : error.message)
+            } finally {
+                this.loading = false;
+            }
+        },
+        async handleInfo(newsletter) {
+            this.showForm('info', newsletter);
+            this.loading = true;
+            try {
+                let response = await window.http.get(`/newsletter/info`, {params: {newsletter_id: newsletter.id}})
+                this.info = response.data.results;
+            } catch (error) {
+                showErrorInfo(error.response ? error.response.data.message : error.message)
+            } finally {
+                this.loading = false;
+            }
+        },
+        async handleMessages(newsletter) {
+            this.showForm('messages', newsletter);
+            this.loading = true;
+            try {
+                let response = await window.http.get(`/newsletter/messages`, {params: {newsletter_id: newsletter.id}})
+                this.messages = response.data.results.data || [];
+            } catch (error) {
+                showErrorInfo(error.response ? error.response.data.message : error.message)
+            } finally {
+                this.loading = false;
+            }
+        },
+        async handleMarkViewed() {
+            if (this.loading || this.messages.length === 0) return;
+            this.loading = true;
+            try {
+                let response = await window.http.post(`/newsletter/viewed`, {
+                    newsletter_id: this.selected.id,
+                    server_ids: this.messages.map(m => m.server_id),
+                })
+                showSuccessInfo(response.data.message)
+            } catch (error) {
+                showErrorInfo(error.response ? error.response.data.message : error.message)
+            } finally {
+                this.loading = false;
+            }
+        },
+        async handlePublish() {
+            if (this.loading) return;
+            this.loading = true;
+            try {
+                let payload = new FormData();
+                payload.append("newsletter_id", this.selected.id)
+                payload.append("message", this.form_message)
+                if (this.form_media_type !== 'text') {
+                    const fileInput = $("#newsletter_media");
+                    if (fileInput.length > 0 && fileInput[0].files.length > 0) {
+                        payload.append(this.form_media_type, fileInput[0].files[0]);
+                    } else if (this.form_media_url) {
+                        payload.append(`${this.form_media_type}_url`, this.form_media_url)
+                    }
+                }
+
+                let response = await window.http.post(`/newsletter/publish`, payload)
+                this.showList();
+                showSuccessInfo(response.data.message)
+            } catch (error) {
+                showErrorInfo(error.response ? error.response.data.message : error.message)
+            } finally {
+                this.loading = false;
+            }
+        },
         async submitApi() {
             try {
                 let response = await window.http.get(`/user/my/newsletters`)
@@ -65,6 +218,14 @@ export default {
                 throw new Error(error.message);
             }
         },
+        formatReactions(reactions) {
+            if (!reactions) return '';
+            return Object.entries(reactions).map(([emoji, count]) => `${emoji} ${count}`).join('  ');
+        },
+        formatTimestamp(value) {
+            if (!value) return '';
+            return moment(value).format('LLL');
+        },
         formatDate: function (value) {
             if (!value) return ''
             if (isNaN(value)) return 'Invalid date';
@@ -77,41 +238,171 @@ export default {
             <a class="ui green right ribbon label">Newsletter</a>
             <div class="header">List Newsletters</div>
             <div class="description">
-                Display all your newsletters
+                Display, create, follow and publish to your newsletters
             </div>
         </div>
     </div>
-    
+
     <!--  Modal AccountNewsletter  -->
     <div class="ui small modal" id="modalNewsletterList">
         <i class="close icon"></i>
         <div class="header">
-            My Newsletter List
+            <span v-if="view === 'list'">My Newsletter List</span>
+            <span v-else-if="view === 'create'">Create Newsletter</span>
+            <span v-else-if="view === 'follow'">Follow Newsletter</span>
+            <span v-else-if="view === 'edit'">Update {{ selected?.thread_metadata?.name?.text }}</span>
+            <span v-else-if="view === 'info'">{{ selected?.thread_metadata?.name?.text }} Info</span>
+            <span v-else-if="view === 'messages'">{{ selected?.thread_metadata?.name?.text }} Posts</span>
+            <span v-else-if="view === 'publish'">Publish to {{ selected?.thread_metadata?.name?.text }}</span>
         </div>
-        <div class="content">
-            <table class="ui celled table" id="account_newsletters_table">
-                <thead>
-                <tr>
-                    <th>Newsletter ID</th>
-                    <th>Name</th>
-                    <th>Role</th>
-                    <th>Created At</th>
-                    <th>Action</th>
-                </tr>
-                </thead>
-                <tbody v-if="newsletters != null">
-                <tr v-for="n in newsletters">
-                    <td>{{ n.id.split('@')[0] }}</td>
-                    <td>{{ n.thread_metadata?.name?.text || 'N/A' }}</td>
-                    <td>{{ n.viewer_metadata?.role || 'N/A' }}</td>
-                    <td>{{ formatDate(n.thread_metadata?.creation_time) }}</td>
-                    <td>
-                        <button class="ui red tiny button" @click="handleUnfollowNewsletter(n.id)">Unfollow</button>
-                    </td>
-                </tr>
-                </tbody>
-            </table>
+        <div class="content" style="max-height: 70vh; overflow-y: auto;">
+            <div v-show="view === 'list'">
+                <div style="margin-bottom: 15px">
+                    <button class="ui green tiny button" @click="showForm('create')">
+                        <i class="plus icon"></i> Create
+                    </button>
+                    <button class="ui blue tiny button" @click="showForm('follow')">
+                        <i class="linkify icon"></i> Follow by invite link
+                    </button>
+                </div>
+                <table class="ui celled table" id="account_newsletters_table">
+                    <thead>
+                    <tr>
+                        <th>Newsletter ID</th>
+                        <th>Name</th>
+                        <th>Role</th>
+                        <th>Created At</th>
+                        <th>Action</th>
+                    </tr>
+                    </thead>
+                    <tbody v-if="newsletters != null">
+                    <tr v-for="n in newsletters">
+                        <td>{{ n.id.split('@')[0] }}</td>
+                        <td>{{ n.thread_metadata?.name?.text || 'N/A' }}</td>
+                        <td>{{ n.viewer_metadata?.role || 'N/A' }}</td>
+                        <td>{{ formatDate(n.thread_metadata?.creation_time) }}</td>
+                        <td>
+                            <div class="ui mini buttons" style="flex-wrap: wrap">
+                                <button class="ui button" @click="handleInfo(n)">Info</button>
+                                <button class="ui button" @click="handleMessages(n)">Posts</button>
+                                <button class="ui teal button" v-if="isAdmin(n)" @click="showForm('publish', n)">Publish</button>
+                                <button class="ui button" v-if="isAdmin(n)" @click="showForm('edit', n)">Edit</button>
+                                <button class="ui red button" @click="handleUnfollowNewsletter(n.id)">Unfollow</button>
+                            </div>
+                        </td>
+                    </tr>
+                    </tbody>
+                </table>
+            </div>
+
+            <form class="ui form" v-if="view === 'create' || view === 'edit'" @submit.prevent="handleSave">
+                <div class="field" :class="{required: view === 'create'}">
+                    <label>Name</label>
+                    <input v-model="form_name" type="text" placeholder="Product announcements" aria-label="name"/>
+                </div>
+                <div class="field">
+                    <label>Description</label>
+                    <textarea v-model="form_description" rows="3" placeholder="What this channel is about"
+                              aria-label="description"></textarea>
+                </div>
+                <div class="field">
+                    <label>Picture</label>
+                    <input type="file" id="newsletter_picture" accept="image/png,image/jpg,image/jpeg"/>
+                </div>
+            </form>
+
+            <form class="ui form" v-if="view === 'follow'" @submit.prevent="handleFollow">
+                <div class="field required">
+                    <label>Invite Link</label>
+                    <input v-model="form_invite" type="text" placeholder="https://whatsapp.com/channel/..."
+                           aria-label="invite link"/>
+                </div>
+            </form>
+
+            <div v-if="view === 'info'">
+                <div class="ui active centered inline loader" v-if="loading"></div>
+                <table class="ui very basic definition table" v-if="info">
+                    <tbody>
+                    <tr><td>ID</td><td>{{ info.id }}</td></tr>
+                    <tr><td>Name</td><td>{{ info.thread_metadata?.name?.text }}</td></tr>
+                    <tr><td>Description</td><td style="white-space: pre-wrap">{{ info.thread_metadata?.description?.text }}</td></tr>
+                    <tr><td>Subscribers</td><td>{{ info.thread_metadata?.subscribers_count }}</td></tr>
+                    <tr><td>Verification</td><td>{{ info.thread_metadata?.verification }}</td></tr>
+                    <tr><td>Invite Link</td><td>https://whatsapp.com/channel/{{ info.thread_metadata?.invite }}</td></tr>
+                    <tr><td>Role</td><td>{{ info.viewer_metadata?.role || 'N/A' }}</td></tr>
+                    <tr><td>Created At</td><td>{{ formatDate(info.thread_metadata?.creation_time) }}</td></tr>
+                    </tbody>
+                </table>
+            </div>
+
+            <div v-if="view === 'messages'">
+                <div class="ui active centered inline loader" v-if="loading && messages.length === 0"></div>
+                <div class="ui message" v-if="!loading && messages.length === 0">No posts found</div>
+                <table class="ui celled table" v-if="messages.length > 0">
+                    <thead>
+                    <tr>
+                        <th>Server ID</th>
+                        <th>Type</th>
+                        <th>Text</th>
+                        <th>Views</th>
+                        <th>Reactions</th>
+                        <th>Sent At</th>
+                    </tr>
+                    </thead>
+                    <tbody>
+                    <tr v-for="m in messages">
+                        <td>{{ m.server_id }}</td>
+                        <td>{{ m.type }}</td>
+                        <td style="white-space: pre-wrap">{{ m.text }}</td>
+                        <td>{{ m.views_count }}</td>
+                        <td>{{ formatReactions(m.reaction_counts) }}</td>
+                        <td>{{ formatTimestamp(m.timestamp) }}</td>
+                    </tr>
+                    </tbody>
+                </table>
+            </div>
+
+            <form class="ui form" v-if="view === 'publish'" @submit.prevent="handlePublish">
+                <div class="field">
+                    <label>Post Type</label>
+                    <select class="ui dropdown" v-model="form_media_type" aria-label="post type">
+                        <option value="text">Text</option>
+                        <option value="image">Image</option>
+                        <option value="video">Video</option>
+                    </select>
+                </div>
+                <div class="field">
+                    <label>{{ form_media_type === 'text' ? 'Message' : 'Caption' }}</label>
+                    <textarea v-model="form_message" rows="4" placeholder="What's new?" aria-label="message"></textarea>
+                </div>
+                <template v-if="form_media_type !== 'text'">
+                    <div class="field">
+                        <label>{{ form_media_type === 'image' ? 'Image' : 'Video' }} URL</label>
+                        <input v-model="form_media_url" type="text" placeholder="https://example.com/..." aria-label="media url"/>
+                    </div>
+                    <div class="field">
+                        <label>or upload from your device</label>
+                        <input type="file" id="newsletter_media"
+                               :accept="form_media_type === 'image' ? 'image/png,image/jpg,image/jpeg' : 'video/mp4,video/x-matroska,video/avi'"/>
+                    </div>
+                </template>
+            </form>
+        </div>
+        <div class="actions" v-if="view !== 'list'">
+            <button class="ui button" @click="showList">Back</button>
+            <button class="ui positive button" :class="{loading: loading}" v-if="view === 'create' || view === 'edit'" @click="handleSave">
+                Save
+            </button>
+            <button class="ui positive button" :class="{loading: loading}" v-if="view === 'follow'" @click="handleFollow">
+                Follow
+            </button>
+            <button class="ui positive button" :class="{loading: loading}" v-if="view === 'publish'" @click="handlePublish">
+                Publish
+            </button>
+            <button class="ui positive button" :class="{loading: loading}" v-if="view === 'messages' && messages.length > 0" @click="handleMarkViewed">
+                Mark as viewed
+            </button>
         </div>
     </div>
     `
-}
\ No newline at end of file
+}